@@ -3,6 +3,7 @@ package currency
 import (
 	"fmt"
 	"math"
+	"strings"
 	"sync"
 	"time"
 )
@@ -37,9 +38,10 @@ var SupportedCurrencies = map[string]Currency{
 // Converter handles currency conversion with cached exchange rates.
 // Rates are stored relative to USD (i.e., 1 USD = rate units of currency).
 type Converter struct {
-	rates    map[string]float64
-	mu       sync.RWMutex
-	cacheExp time.Time
+	rates     map[string]float64
+	mu        sync.RWMutex
+	cacheExp  time.Time
+	updatedAt time.Time
 }
 
 // NewConverter creates a new Converter pre-loaded with default exchange
@@ -64,7 +66,8 @@ func NewConverter() *Converter {
 			"SEK": 10.45,
 			"AED": 3.67,
 		},
-		cacheExp: time.Now().Add(24 * time.Hour),
+		cacheExp:  time.Now().Add(24 * time.Hour),
+		updatedAt: time.Now(),
 	}
 	return c
 }
@@ -82,7 +85,12 @@ func (c *Converter) Convert(amountCents int64, from, to string) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
+	return convertAtRate(amountCents, from, to, rate)
+}
 
+// convertAtRate converts an amount in the smallest unit of from into the
+// smallest unit of to at the given rate.
+func convertAtRate(amountCents int64, from, to string, rate float64) (int64, error) {
 	fromCurrency, fromOk := SupportedCurrencies[from]
 	toCurrency, toOk := SupportedCurrencies[to]
 	if !fromOk || !toOk {
@@ -143,6 +151,76 @@ func (c *Converter) UpdateRates(rates map[string]float64) {
 	// Ensure USD is always 1.
 	c.rates["USD"] = 1.0
 	c.cacheExp = time.Now().Add(1 * time.Hour)
+	c.updatedAt = time.Now()
+}
+
+// Snapshot returns a copy of the current exchange rates. Amounts converted
+// with the snapshot keep using these rates however the converter's rates
+// change afterwards.
+func (c *Converter) Snapshot() RateSnapshot {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rates := make(map[string]float64, len(c.rates))
+	for code, rate := range c.rates {
+		rates[code] = rate
+	}
+	return RateSnapshot{
+		Base:  "USD",
+		Rates: rates,
+		AsOf:  c.updatedAt,
+	}
+}
+
+// RateSnapshot is a fixed set of exchange rates, such as the rates locked
+// when an order is placed. Rates are relative to Base (i.e., 1 Base = rate
+// units of currency).
+type RateSnapshot struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+	AsOf  time.Time          `json:"as_of"`
+}
+
+// Rate returns how many units of to equal one unit of from at the
+// snapshot's rates.
+func (s RateSnapshot) Rate(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	fromRate, fromOk := s.Rates[from]
+	toRate, toOk := s.Rates[to]
+	if !fromOk || fromRate <= 0 {
+		return 0, fmt.Errorf("no locked rate for source currency: %s", from)
+	}
+	if !toOk || toRate <= 0 {
+		return 0, fmt.Errorf("no locked rate for target currency: %s", to)
+	}
+	return toRate / fromRate, nil
+}
+
+// Convert converts an amount in the smallest unit of from into the smallest
+// unit of to at the snapshot's rates.
+func (s RateSnapshot) Convert(amountCents int64, from, to string) (int64, error) {
+	if from == to {
+		return amountCents, nil
+	}
+	rate, err := s.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return convertAtRate(amountCents, from, to, rate)
+}
+
+// Normalize returns the upper-case ISO 4217 form of a currency code, so that
+// "usd" and " USD" both become "USD".
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsSupported reports whether a currency code, in any case, is supported.
+func IsSupported(code string) bool {
+	_, ok := SupportedCurrencies[Normalize(code)]
+	return ok
 }
 
 // FormatPrice formats an amount (in the smallest currency unit) as a
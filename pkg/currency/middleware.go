@@ -4,6 +4,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// DefaultCurrency is the fallback currency when none is specified.
+const DefaultCurrency = "USD"
+
 const (
 	// contextKeyCurrency is the gin context key used to store the resolved currency.
 	contextKeyCurrency = "x_currency"
@@ -11,25 +14,30 @@ const (
 	// headerCurrency is the HTTP header used to specify the desired currency.
 	headerCurrency = "X-Currency"
 
-	// defaultCurrency is the fallback currency when none is specified.
-	defaultCurrency = "USD"
+	// queryCurrency is the query parameter that overrides the header, so
+	// that links can select a currency.
+	queryCurrency = "currency"
 )
 
-// GinMiddleware returns a gin.HandlerFunc that extracts the X-Currency header
-// from incoming requests and stores the validated currency code in the gin
-// context. If the header is missing or contains an unsupported currency code,
-// it defaults to USD.
+// GinMiddleware returns a gin.HandlerFunc that selects the buyer's
+// presentment currency from the currency query parameter or, failing that,
+// the X-Currency header, and stores the validated currency code in the gin
+// context. Codes are case-insensitive. If neither is set or the code is
+// unsupported, it defaults to USD.
 func GinMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		currencyCode := c.GetHeader(headerCurrency)
+		currencyCode := Normalize(c.Query(queryCurrency))
+		if currencyCode == "" {
+			currencyCode = Normalize(c.GetHeader(headerCurrency))
+		}
 
 		if currencyCode == "" {
-			currencyCode = defaultCurrency
+			currencyCode = DefaultCurrency
 		}
 
 		// Validate against supported currencies.
 		if _, ok := SupportedCurrencies[currencyCode]; !ok {
-			currencyCode = defaultCurrency
+			currencyCode = DefaultCurrency
 		}
 
 		c.Set(contextKeyCurrency, currencyCode)
@@ -45,5 +53,5 @@ func GetCurrency(c *gin.Context) string {
 			return s
 		}
 	}
-	return defaultCurrency
+	return DefaultCurrency
 }
@@ -0,0 +1,160 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// RateSource supplies exchange rates. Rates are relative to USD (i.e.,
+// 1 USD = rate units of currency).
+type RateSource interface {
+	FetchRates(ctx context.Context) (map[string]float64, error)
+}
+
+// rateDocument is the JSON document read by the file and HTTP rate
+// sources:
+//
+//	{"base": "EUR", "rates": {"USD": 1.087, "GBP": 0.858}}
+//
+// Rates quoted against a base other than USD are rebased to USD.
+type rateDocument struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// usdRates returns the document's rates relative to USD.
+func (d rateDocument) usdRates() (map[string]float64, error) {
+	base := Normalize(d.Base)
+	if base == "" {
+		base = "USD"
+	}
+
+	rates := make(map[string]float64, len(d.Rates)+1)
+	for code, rate := range d.Rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("invalid rate for %s: %v", code, rate)
+		}
+		rates[Normalize(code)] = rate
+	}
+	rates[base] = 1.0
+
+	usd, ok := rates["USD"]
+	if !ok {
+		return nil, fmt.Errorf("rates quoted in %s have no USD rate", base)
+	}
+	for code, rate := range rates {
+		rates[code] = rate / usd
+	}
+	return rates, nil
+}
+
+func decodeRates(r io.Reader) (map[string]float64, error) {
+	var doc rateDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode rates: %w", err)
+	}
+	if len(doc.Rates) == 0 {
+		return nil, fmt.Errorf("no rates found")
+	}
+	return doc.usdRates()
+}
+
+// FileRateSource reads exchange rates from a JSON file.
+type FileRateSource struct {
+	Path string
+}
+
+// FetchRates reads the rates from the file.
+func (s *FileRateSource) FetchRates(ctx context.Context) (map[string]float64, error) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rates file: %w", err)
+	}
+	defer f.Close()
+	return decodeRates(f)
+}
+
+// HTTPRateSource fetches exchange rates as JSON from a URL, such as a local
+// stub of a rates provider.
+type HTTPRateSource struct {
+	URL    string
+	Client *http.Client
+}
+
+// FetchRates fetches the rates from the URL.
+func (s *HTTPRateSource) FetchRates(ctx context.Context) (map[string]float64, error) {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rates request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rates source returned status %d", resp.StatusCode)
+	}
+	return decodeRates(resp.Body)
+}
+
+// NewRateSource returns the rate source for a location: an http(s) URL is
+// fetched, anything else is read as a file path. An empty location has no
+// source and returns nil.
+func NewRateSource(location string) RateSource {
+	switch {
+	case location == "":
+		return nil
+	case strings.HasPrefix(location, "http://"), strings.HasPrefix(location, "https://"):
+		return &HTTPRateSource{URL: location}
+	default:
+		return &FileRateSource{Path: location}
+	}
+}
+
+// LoadRates fetches rates from the source and updates the converter with
+// them.
+func (c *Converter) LoadRates(ctx context.Context, src RateSource) error {
+	rates, err := src.FetchRates(ctx)
+	if err != nil {
+		return err
+	}
+	c.UpdateRates(rates)
+	return nil
+}
+
+// StartRefresher reloads rates from the source every interval until ctx is
+// cancelled. Failed reloads keep the previous rates and are reported to
+// onError, if set. A non-positive interval disables refreshing.
+func (c *Converter) StartRefresher(ctx context.Context, src RateSource, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.LoadRates(ctx, src); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
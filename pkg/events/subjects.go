@@ -16,11 +16,12 @@ const (
 	SubjectUserProfileUpdated = "user.profile.updated"
 
 	// Product events
-	SubjectProductCreated     = "product.created"
-	SubjectProductUpdated     = "product.updated"
-	SubjectProductDeleted     = "product.deleted"
-	SubjectProductStockUpdate = "product.stock.updated"
-	SubjectProductPriceUpdate = "product.price.updated"
+	SubjectProductCreated      = "product.created"
+	SubjectProductUpdated      = "product.updated"
+	SubjectProductDeleted      = "product.deleted"
+	SubjectProductStockUpdate  = "product.stock.updated"
+	SubjectProductPriceUpdate  = "product.price.updated"
+	SubjectProductRatingUpdate = "product.rating.updated"
 
 	// Cart events
 	SubjectCartItemAdded   = "cart.item.added"
@@ -37,6 +38,12 @@ const (
 	SubjectOrderRefunded   = "order.refunded"
 	SubjectOrderCompleted  = "order.completed"
 
+	// Subscription events
+	SubjectSubscriptionRenewalUpcoming = "subscription.renewal.upcoming"
+	SubjectSubscriptionPaymentFailed   = "subscription.payment.failed"
+	SubjectSubscriptionPaymentRetry    = "subscription.payment.retry"
+	SubjectSubscriptionCancelled       = "subscription.cancelled"
+
 	// Payment events
 	SubjectPaymentInitiated = "payment.initiated"
 	SubjectPaymentCompleted = "payment.completed"
@@ -54,6 +61,11 @@ const (
 	SubjectReviewDeleted  = "review.deleted"
 	SubjectReviewApproved = "review.approved"
 
+	// Product Q&A events
+	SubjectQuestionCreated  = "question.created"
+	SubjectQuestionAnswered = "question.answered"
+	SubjectQuestionRemoved  = "question.removed"
+
 	// Search events
 	SubjectSearchIndexProduct = "search.index.product"
 	SubjectSearchRemoveProduct = "search.remove.product"
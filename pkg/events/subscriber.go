@@ -3,6 +3,7 @@ package events
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/nats-io/nats.go"
 )
@@ -50,6 +51,51 @@ func (s *Subscriber) Subscribe(subject, durable string, handler func(data []byte
 	return nil
 }
 
+// retryDelay is how long a message whose handler failed waits before it is
+// redelivered, and maxDeliveries how many times it is delivered at most.
+const (
+	retryDelay    = 5 * time.Second
+	maxDeliveries = 10
+)
+
+// SubscribeWithRetry is like Subscribe, but a message is only acknowledged
+// once the handler succeeds. Messages the handler fails are redelivered
+// after a delay, up to a limited number of deliveries.
+func (s *Subscriber) SubscribeWithRetry(subject, durable string, handler func(data []byte) error) error {
+	if err := s.ensureStream(subject); err != nil {
+		return fmt.Errorf("failed to ensure stream for %s: %w", subject, err)
+	}
+
+	cb := func(msg *nats.Msg) {
+		if err := handler(msg.Data); err != nil {
+			fmt.Printf("failed to handle message on %s, retrying: %v\n", subject, err)
+			if nakErr := msg.NakWithDelay(retryDelay); nakErr != nil {
+				fmt.Printf("failed to nak message on %s: %v\n", subject, nakErr)
+			}
+			return
+		}
+		if ackErr := msg.Ack(); ackErr != nil {
+			fmt.Printf("failed to ack message on %s: %v\n", subject, ackErr)
+		}
+	}
+	opts := []nats.SubOpt{nats.Durable(durable), nats.ManualAck(), nats.MaxDeliver(maxDeliveries)}
+
+	_, err := s.js.Subscribe(subject, cb, opts...)
+	if err != nil {
+		// If the consumer is already bound (stale from a previous run), delete it and retry.
+		if strings.Contains(err.Error(), "already bound") {
+			streamName := strings.ToUpper(strings.Split(subject, ".")[0])
+			_ = s.js.DeleteConsumer(streamName, durable)
+			_, err = s.js.Subscribe(subject, cb, opts...)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+		}
+	}
+
+	return nil
+}
+
 // ensureStream creates the JetStream stream for the given subject if it doesn't already exist.
 func (s *Subscriber) ensureStream(subject string) error {
 	streamName := strings.ToUpper(strings.Split(subject, ".")[0])
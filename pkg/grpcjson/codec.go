@@ -0,0 +1,45 @@
+// Package grpcjson provides a JSON codec for the gRPC services whose
+// service descriptors are written by hand around plain Go structs rather
+// than generated protobuf messages.
+//
+// Importing the package registers the codec. Servers then answer calls made
+// with the "json" content subtype, which clients select with CallOption.
+package grpcjson
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+)
+
+// Name is the content subtype of the codec, sent as
+// "application/grpc+json".
+const Name = "json"
+
+func init() {
+	encoding.RegisterCodec(Codec{})
+}
+
+// Codec marshals gRPC messages as JSON.
+type Codec struct{}
+
+// Marshal encodes v as JSON.
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes JSON data into v.
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Name returns the codec's content subtype.
+func (Codec) Name() string {
+	return Name
+}
+
+// CallOption makes a call use the JSON codec.
+func CallOption() grpc.CallOption {
+	return grpc.CallContentSubtype(Name)
+}
@@ -21,6 +21,9 @@ var supportedLanguages = []language.Tag{
 	language.Italian,            // it
 }
 
+// DefaultLanguage is the language every fallback chain ends in.
+const DefaultLanguage = "en"
+
 // Bundle holds translations for multiple languages and provides thread-safe
 // lookup with BCP47 language tag matching via golang.org/x/text/language.
 type Bundle struct {
@@ -62,19 +65,9 @@ func (b *Bundle) T(lang, key string, args ...interface{}) string {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
-	// Match the requested language to the closest supported language.
-	matched := b.matchLanguage(lang)
-
-	// Try the matched language.
-	if msgs, ok := b.translations[matched]; ok {
-		if msg, ok := msgs[key]; ok {
-			return b.format(msg, args...)
-		}
-	}
-
-	// Fall back to English.
-	if matched != "en" {
-		if msgs, ok := b.translations["en"]; ok {
+	// Try the closest supported language, then English.
+	for _, l := range b.FallbackChain(lang) {
+		if msgs, ok := b.translations[l]; ok {
 			if msg, ok := msgs[key]; ok {
 				return b.format(msg, args...)
 			}
@@ -93,16 +86,49 @@ func (b *Bundle) SetupDefaults() {
 	}
 }
 
+// MatchLanguage returns the supported language closest to lang, which may
+// be a single tag or a full Accept-Language header value.
+func (b *Bundle) MatchLanguage(lang string) string {
+	return b.matchLanguage(lang)
+}
+
+// FallbackChain returns the languages to try, in order, for content wanted
+// in lang: the closest supported language, then English. Translated content
+// should be resolved along this chain so it falls back the same way T does.
+func (b *Bundle) FallbackChain(lang string) []string {
+	matched := b.matchLanguage(lang)
+	if matched == DefaultLanguage {
+		return []string{DefaultLanguage}
+	}
+	return []string{matched, DefaultLanguage}
+}
+
+// IsSupported reports whether lang is one of the supported languages rather
+// than something that would only fall back to English.
+func IsSupported(lang string) bool {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return false
+	}
+	base, _ := tag.Base()
+	for _, supported := range supportedLanguages {
+		if b, _ := supported.Base(); b == base {
+			return true
+		}
+	}
+	return false
+}
+
 // matchLanguage uses BCP47 matching to find the best supported language
 // for the given language string. Returns the language code as a string.
 func (b *Bundle) matchLanguage(lang string) string {
-	tag, err := language.Parse(lang)
-	if err != nil {
-		return "en"
+	tags, _, err := language.ParseAcceptLanguage(lang)
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage
 	}
-	_, idx, _ := b.matcher.Match(tag)
+	_, idx, _ := b.matcher.Match(tags...)
 	if idx < 0 || idx >= len(supportedLanguages) {
-		return "en"
+		return DefaultLanguage
 	}
 	base, _ := supportedLanguages[idx].Base()
 	return base.String()
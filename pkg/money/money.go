@@ -1,77 +1,261 @@
 package money
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
+	"sort"
+
+	"github.com/southern-martin/ecommerce/pkg/currency"
 )
 
-// Money represents a currency-safe monetary value stored as cents.
+// ErrCurrencyMismatch is returned when combining amounts in different
+// currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// defaultDecimals is the number of decimal places assumed for currencies
+// that pkg/currency does not know.
+const defaultDecimals = 2
+
+// tieEpsilon is how close to a half a fraction must be to be rounded as a
+// tie, absorbing float error such as 100.49999999999999.
+const tieEpsilon = 1e-9
+
+// Money represents a currency-safe monetary value stored in the minor unit
+// of its currency (cents for USD, whole yen for JPY).
 type Money struct {
 	AmountCents int64  `json:"amount_cents"`
 	Currency    string `json:"currency"`
 }
 
-// NewMoney creates a new Money value from cents and currency code.
-func NewMoney(amountCents int64, currency string) Money {
+// RoundingMode selects how fractional minor units are rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds ties away from zero (2.5 -> 3, -2.5 -> -3).
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds ties to the nearest even number (2.5 -> 2,
+	// 3.5 -> 4), also known as banker's rounding. It does not bias sums of
+	// many rounded amounts upwards.
+	RoundHalfEven
+	// RoundDown truncates towards zero.
+	RoundDown
+)
+
+// Round rounds an amount of minor units to a whole number of them.
+func (r RoundingMode) Round(amount float64) int64 {
+	if r == RoundDown {
+		return int64(math.Trunc(amount))
+	}
+
+	whole, frac := math.Modf(amount)
+	if math.Abs(math.Abs(frac)-0.5) < tieEpsilon {
+		amount = whole + math.Copysign(0.5, amount)
+	}
+	if r == RoundHalfEven {
+		return int64(math.RoundToEven(amount))
+	}
+	return int64(math.Round(amount))
+}
+
+// Decimals returns the number of decimal places of a currency: 2 for USD,
+// 0 for JPY and KRW. Unknown currencies are assumed to have 2.
+func Decimals(code string) int {
+	if cur, ok := currency.SupportedCurrencies[currency.Normalize(code)]; ok {
+		return cur.Decimals
+	}
+	return defaultDecimals
+}
+
+// NewMoney creates a new Money value from minor units and currency code.
+func NewMoney(amountCents int64, code string) Money {
 	return Money{
 		AmountCents: amountCents,
-		Currency:    currency,
+		Currency:    currency.Normalize(code),
 	}
 }
 
-// NewMoneyFromFloat creates a new Money value from a float amount (e.g., 19.99) and currency.
-func NewMoneyFromFloat(amount float64, currency string) Money {
-	cents := int64(math.Round(amount * 100))
-	return Money{
-		AmountCents: cents,
-		Currency:    currency,
+// NewMoneyFromFloat creates a new Money value from an amount in major units
+// (e.g., 19.99 USD or 1999 JPY) and currency. The amount is rounded half up
+// to the currency's minor unit.
+func NewMoneyFromFloat(amount float64, code string) Money {
+	return NewMoney(RoundHalfUp.Round(amount*minorPerMajor(code)), code)
+}
+
+// Sum adds up amounts in the given currency. It returns ErrCurrencyMismatch
+// if any amount is in another currency.
+func Sum(code string, amounts ...Money) (Money, error) {
+	total := NewMoney(0, code)
+	for _, amount := range amounts {
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return Money{}, err
+		}
 	}
+	return total, nil
 }
 
-// Add returns a new Money that is the sum of m and other.
-// Panics if currencies do not match.
-func (m Money) Add(other Money) Money {
-	m.assertSameCurrency(other)
+// Add returns the sum of m and other. It returns ErrCurrencyMismatch if the
+// currencies differ.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
 	return Money{
 		AmountCents: m.AmountCents + other.AmountCents,
 		Currency:    m.Currency,
-	}
+	}, nil
 }
 
-// Subtract returns a new Money that is the difference of m and other.
-// Panics if currencies do not match.
-func (m Money) Subtract(other Money) Money {
-	m.assertSameCurrency(other)
+// Subtract returns the difference of m and other. It returns
+// ErrCurrencyMismatch if the currencies differ.
+func (m Money) Subtract(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
 	return Money{
 		AmountCents: m.AmountCents - other.AmountCents,
 		Currency:    m.Currency,
-	}
+	}, nil
 }
 
 // Multiply returns a new Money with the amount multiplied by the given factor.
-// The result is rounded to the nearest cent.
+// The result is rounded half up to the nearest minor unit.
 func (m Money) Multiply(factor float64) Money {
-	cents := int64(math.Round(float64(m.AmountCents) * factor))
+	return m.MultiplyRounded(factor, RoundHalfUp)
+}
+
+// MultiplyRounded returns a new Money with the amount multiplied by the
+// given factor, rounded to the nearest minor unit with mode.
+func (m Money) MultiplyRounded(factor float64, mode RoundingMode) Money {
 	return Money{
-		AmountCents: cents,
+		AmountCents: mode.Round(float64(m.AmountCents) * factor),
 		Currency:    m.Currency,
 	}
 }
 
-// Format returns a human-readable string representation of the money value.
-// For example: "USD 19.99" or "EUR 100.00".
+// MultiplyRatio returns a new Money with the amount multiplied by
+// numerator/denominator, computed exactly in integers and rounded to the
+// nearest minor unit with mode. Use it for rates held as integers, such as
+// basis points: MultiplyRatio(10000-bps, 10000, mode) takes bps off.
+func (m Money) MultiplyRatio(numerator, denominator int64, mode RoundingMode) (Money, error) {
+	if denominator == 0 {
+		return Money{}, errors.New("ratio denominator is zero")
+	}
+
+	product := new(big.Int).Mul(big.NewInt(m.AmountCents), big.NewInt(numerator))
+	den := big.NewInt(denominator)
+	quo, rem := new(big.Int).QuoRem(product, den, new(big.Int))
+	if rem.Sign() != 0 && mode != RoundDown {
+		// Compare the remainder with half the denominator to round the
+		// truncated quotient away from zero when needed.
+		sign := int64(product.Sign() * den.Sign())
+		cmp := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).CmpAbs(den)
+		if cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || quo.Bit(0) == 1)) {
+			quo.Add(quo, big.NewInt(sign))
+		}
+	}
+	if !quo.IsInt64() {
+		return Money{}, fmt.Errorf("%d * %d / %d overflows", m.AmountCents, numerator, denominator)
+	}
+	return Money{AmountCents: quo.Int64(), Currency: m.Currency}, nil
+}
+
+// Allocate splits the amount into parts proportional to ratios without
+// losing or creating minor units: the parts always add up to the amount.
+// Minor units left over after the proportional split go to the parts with
+// the largest remainders, earlier parts first on ties. A negative amount is
+// split the same way as its absolute value.
+//
+// For example, 100 cents allocated 1:1:1 is 34, 33 and 33.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("no ratios to allocate by")
+	}
+	var total int64
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, fmt.Errorf("negative allocation ratio: %d", ratio)
+		}
+		total += ratio
+	}
+	if total == 0 {
+		return nil, errors.New("allocation ratios sum to zero")
+	}
+
+	sign := int64(1)
+	amount := m.AmountCents
+	if amount < 0 {
+		sign, amount = -1, -amount
+	}
+
+	// Shares are computed in big integers so that amount*ratio cannot
+	// overflow.
+	shares := make([]int64, len(ratios))
+	remainders := make([]int64, len(ratios))
+	bigAmount, bigTotal := big.NewInt(amount), big.NewInt(total)
+	allocated := int64(0)
+	for i, ratio := range ratios {
+		product := new(big.Int).Mul(bigAmount, big.NewInt(ratio))
+		share, rem := product.QuoRem(product, bigTotal, new(big.Int))
+		shares[i] = share.Int64()
+		remainders[i] = rem.Int64()
+		allocated += shares[i]
+	}
+
+	order := make([]int, len(ratios))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	for i := int64(0); i < amount-allocated; i++ {
+		shares[order[i]]++
+	}
+
+	parts := make([]Money, len(ratios))
+	for i, share := range shares {
+		parts[i] = Money{AmountCents: sign * share, Currency: m.Currency}
+	}
+	return parts, nil
+}
+
+// Split splits the amount into n parts as equal as possible, the earlier
+// parts taking any minor units left over.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("cannot split into %d parts", n)
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// Format returns a human-readable string representation of the money value
+// with the currency's number of decimals.
+// For example: "USD 19.99", "EUR 100.00" or "JPY 1500".
 func (m Money) Format() string {
-	whole := m.AmountCents / 100
-	frac := m.AmountCents % 100
-	if frac < 0 {
-		frac = -frac
+	sign := ""
+	amount := m.AmountCents
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	decimals := Decimals(m.Currency)
+	if decimals == 0 {
+		return fmt.Sprintf("%s %s%d", m.Currency, sign, amount)
 	}
-	return fmt.Sprintf("%s %d.%02d", m.Currency, whole, frac)
+	unit := int64(math.Pow10(decimals))
+	return fmt.Sprintf("%s %s%d.%0*d", m.Currency, sign, amount/unit, decimals, amount%unit)
 }
 
-// ToFloat returns the amount as a float64.
+// ToFloat returns the amount in major units as a float64.
 func (m Money) ToFloat() float64 {
-	return float64(m.AmountCents) / 100.0
+	return float64(m.AmountCents) / minorPerMajor(m.Currency)
 }
 
 // IsZero returns true if the amount is zero.
@@ -91,12 +275,20 @@ func (m Money) IsNegative() bool {
 
 // Equals returns true if both Money values have the same amount and currency.
 func (m Money) Equals(other Money) bool {
-	return m.AmountCents == other.AmountCents && m.Currency == other.Currency
+	return m.AmountCents == other.AmountCents && m.sameCurrency(other) == nil
 }
 
-// assertSameCurrency panics if the currencies of the two Money values differ.
-func (m Money) assertSameCurrency(other Money) {
-	if m.Currency != other.Currency {
-		panic(fmt.Sprintf("currency mismatch: %s vs %s", m.Currency, other.Currency))
+// sameCurrency returns ErrCurrencyMismatch if the currencies of the two
+// Money values differ.
+func (m Money) sameCurrency(other Money) error {
+	if currency.Normalize(m.Currency) != currency.Normalize(other.Currency) {
+		return fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, m.Currency, other.Currency)
 	}
+	return nil
+}
+
+// minorPerMajor returns the number of minor units in one major unit of a
+// currency.
+func minorPerMajor(code string) float64 {
+	return math.Pow10(Decimals(code))
 }
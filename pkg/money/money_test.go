@@ -0,0 +1,137 @@
+package money
+
+import "testing"
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		ratios []int64
+		want   []int64
+	}{
+		{"even thirds", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"proportional", 1000, []int64{1, 2, 7}, []int64{100, 200, 700}},
+		{"largest remainder first", 10, []int64{1, 3}, []int64{3, 7}},
+		{"negative amount", -100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{"negative proportional", -10, []int64{1, 3}, []int64{-3, -7}},
+		{"zero weight gets nothing", 100, []int64{0, 1, 1}, []int64{0, 50, 50}},
+		{"zero weight with leftover", 101, []int64{1, 0, 1}, []int64{51, 0, 50}},
+		{"zero amount", 0, []int64{1, 2}, []int64{0, 0}},
+		{"single part", 99, []int64{5}, []int64{99}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := NewMoney(tt.amount, "USD").Allocate(tt.ratios...)
+			if err != nil {
+				t.Fatalf("Allocate: %v", err)
+			}
+			if len(parts) != len(tt.want) {
+				t.Fatalf("got %d parts, want %d", len(parts), len(tt.want))
+			}
+			var sum int64
+			for i, part := range parts {
+				if part.AmountCents != tt.want[i] {
+					t.Errorf("part %d = %d, want %d", i, part.AmountCents, tt.want[i])
+				}
+				if part.Currency != "USD" {
+					t.Errorf("part %d currency = %q, want USD", i, part.Currency)
+				}
+				sum += part.AmountCents
+			}
+			if sum != tt.amount {
+				t.Errorf("parts add up to %d, want %d", sum, tt.amount)
+			}
+		})
+	}
+}
+
+func TestAllocateInvalidRatios(t *testing.T) {
+	tests := []struct {
+		name   string
+		ratios []int64
+	}{
+		{"no ratios", nil},
+		{"all zero", []int64{0, 0}},
+		{"negative", []int64{1, -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMoney(100, "USD").Allocate(tt.ratios...); err == nil {
+				t.Error("Allocate succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestRoundingModeRound(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   RoundingMode
+		amount float64
+		want   int64
+	}{
+		{"half even tie down", RoundHalfEven, 2.5, 2},
+		{"half even tie up", RoundHalfEven, 3.5, 4},
+		{"half even negative tie", RoundHalfEven, -2.5, -2},
+		{"half even negative tie up", RoundHalfEven, -3.5, -4},
+		{"half even not a tie", RoundHalfEven, 2.51, 3},
+		{"half even float error tie", RoundHalfEven, 100.49999999999999, 100},
+		{"half up tie", RoundHalfUp, 2.5, 3},
+		{"half up negative tie", RoundHalfUp, -2.5, -3},
+		{"half up float error tie", RoundHalfUp, 100.49999999999999, 101},
+		{"down", RoundDown, 2.9, 2},
+		{"down negative", RoundDown, -2.9, -2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.mode.Round(tt.amount); got != tt.want {
+				t.Errorf("Round(%v) = %d, want %d", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiplyRatio(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      int64
+		numerator   int64
+		denominator int64
+		mode        RoundingMode
+		want        int64
+	}{
+		{"exact", 1000, 9000, 10000, RoundHalfUp, 900},
+		{"basis points", 1999, 10000 - 1250, 10000, RoundHalfUp, 1749},
+		{"half up tie", 5, 1, 2, RoundHalfUp, 3},
+		{"half up negative tie", -5, 1, 2, RoundHalfUp, -3},
+		{"half even tie down", 5, 1, 2, RoundHalfEven, 2},
+		{"half even tie up", 7, 1, 2, RoundHalfEven, 4},
+		{"half even negative tie", -5, 1, 2, RoundHalfEven, -2},
+		{"below half", 10, 1, 3, RoundHalfUp, 3},
+		{"above half", 20, 1, 3, RoundHalfEven, 7},
+		{"down", 20, 1, 3, RoundDown, 6},
+		{"down negative", -20, 1, 3, RoundDown, -6},
+		{"negative denominator", 5, 1, -2, RoundHalfUp, -3},
+		{"large amount", 1 << 60, 3, 4, RoundHalfUp, 3 << 58},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMoney(tt.amount, "USD").MultiplyRatio(tt.numerator, tt.denominator, tt.mode)
+			if err != nil {
+				t.Fatalf("MultiplyRatio: %v", err)
+			}
+			if got.AmountCents != tt.want || got.Currency != "USD" {
+				t.Errorf("MultiplyRatio(%d, %d) of %d = %+v, want %d USD", tt.numerator, tt.denominator, tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiplyRatioErrors(t *testing.T) {
+	if _, err := NewMoney(100, "USD").MultiplyRatio(1, 0, RoundHalfUp); err == nil {
+		t.Error("zero denominator succeeded, want an error")
+	}
+	if _, err := NewMoney(1<<62, "USD").MultiplyRatio(4, 1, RoundHalfUp); err == nil {
+		t.Error("overflow succeeded, want an error")
+	}
+}
@@ -13,12 +13,14 @@ import (
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	cartgrpc "github.com/southern-martin/ecommerce/services/cart/internal/adapter/grpc"
 	carthttp "github.com/southern-martin/ecommerce/services/cart/internal/adapter/http"
 	cartredis "github.com/southern-martin/ecommerce/services/cart/internal/adapter/redis"
 	"github.com/southern-martin/ecommerce/services/cart/internal/infrastructure/config"
 	"github.com/southern-martin/ecommerce/services/cart/internal/infrastructure/database"
 	cartnats "github.com/southern-martin/ecommerce/services/cart/internal/infrastructure/nats"
+	"github.com/southern-martin/ecommerce/services/cart/internal/infrastructure/promotion"
 	"github.com/southern-martin/ecommerce/services/cart/internal/usecase"
 )
 
@@ -57,10 +59,30 @@ func main() {
 	}
 	defer natsConn.Close()
 
+	// Exchange rates promotion amounts are converted at
+	ratesCtx, stopRates := context.WithCancel(context.Background())
+	defer stopRates()
+	converter := currency.NewConverter()
+	if src := currency.NewRateSource(cfg.RatesSource); src != nil {
+		if err := converter.LoadRates(ratesCtx, src); err != nil {
+			logger.Warn().Err(err).Msg("failed to load exchange rates, using default rates")
+		}
+		converter.StartRefresher(ratesCtx, src, time.Duration(cfg.RatesRefreshMinutes)*time.Minute, func(err error) {
+			logger.Error().Err(err).Msg("failed to refresh exchange rates")
+		})
+	}
+
+	// Carts are priced with the promotion service's promotions
+	promotions, err := promotion.NewClient(cfg.PromotionGRPCAddr)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create promotion client")
+	}
+	defer promotions.Close()
+
 	// Initialize layers
 	cartRepo := cartredis.NewRedisCartRepository(rdb)
 	eventPublisher := cartnats.NewEventPublisher(natsConn, logger)
-	cartUC := usecase.NewCartUseCase(cartRepo, eventPublisher, logger)
+	cartUC := usecase.NewCartUseCase(cartRepo, eventPublisher, promotions, converter, cfg.SettlementCurrency, logger)
 
 	// HTTP server
 	handler := carthttp.NewCartHandler(cartUC, logger)
@@ -3,12 +3,16 @@ package grpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/rs/zerolog"
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	"github.com/southern-martin/ecommerce/services/cart/internal/domain"
 	"github.com/southern-martin/ecommerce/services/cart/internal/usecase"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // --- Request / Response types for gRPC ---
@@ -23,6 +27,21 @@ type ClearCartRequest struct {
 	UserID string `json:"user_id"`
 }
 
+// AddItemRequest is the request for AddItem RPC.
+type AddItemRequest struct {
+	UserID      string `json:"user_id"`
+	Currency    string `json:"currency"`
+	ProductID   string `json:"product_id"`
+	VariantID   string `json:"variant_id"`
+	ProductName string `json:"product_name"`
+	VariantName string `json:"variant_name"`
+	SKU         string `json:"sku"`
+	PriceCents  int64  `json:"price_cents"`
+	Quantity    int32  `json:"quantity"`
+	ImageURL    string `json:"image_url"`
+	SellerID    string `json:"seller_id"`
+}
+
 // CartItemResponse is a cart item in the gRPC response.
 type CartItemResponse struct {
 	ProductID   string `json:"product_id"`
@@ -39,7 +58,9 @@ type CartItemResponse struct {
 // GetCartResponse is the response for GetCart RPC.
 type GetCartResponse struct {
 	UserID        string             `json:"user_id"`
+	Currency      string             `json:"currency"`
 	Items         []CartItemResponse `json:"items"`
+	Version       int64              `json:"version"`
 	TotalItems    int32              `json:"total_items"`
 	SubtotalCents int64              `json:"subtotal_cents"`
 }
@@ -54,6 +75,7 @@ type ClearCartResponse struct {
 // CartService defines the gRPC service interface for inter-service cart operations.
 type CartService interface {
 	GetCart(ctx context.Context, req *GetCartRequest) (*GetCartResponse, error)
+	AddItem(ctx context.Context, req *AddItemRequest) (*GetCartResponse, error)
 	ClearCart(ctx context.Context, req *ClearCartRequest) (*ClearCartResponse, error)
 }
 
@@ -83,6 +105,26 @@ func (s *cartServiceServer) GetCart(ctx context.Context, req *GetCartRequest) (*
 	return toGetCartResponse(cart), nil
 }
 
+func (s *cartServiceServer) AddItem(ctx context.Context, req *AddItemRequest) (*GetCartResponse, error) {
+	cart, err := s.cartUC.AddItem(ctx, req.UserID, currency.Normalize(req.Currency), domain.CartItem{
+		ProductID:   req.ProductID,
+		VariantID:   req.VariantID,
+		ProductName: req.ProductName,
+		VariantName: req.VariantName,
+		SKU:         req.SKU,
+		PriceCents:  req.PriceCents,
+		Quantity:    int(req.Quantity),
+		ImageURL:    req.ImageURL,
+		SellerID:    req.SellerID,
+	})
+	if err != nil {
+		s.logger.Error().Err(err).Str("user_id", req.UserID).Msg("grpc: failed to add item")
+		return nil, toStatusError(err, "failed to add item")
+	}
+
+	return toGetCartResponse(cart), nil
+}
+
 func (s *cartServiceServer) ClearCart(ctx context.Context, req *ClearCartRequest) (*ClearCartResponse, error) {
 	if err := s.cartUC.ClearCart(ctx, req.UserID); err != nil {
 		s.logger.Error().Err(err).Str("user_id", req.UserID).Msg("grpc: failed to clear cart")
@@ -110,12 +152,32 @@ func toGetCartResponse(cart *domain.Cart) *GetCartResponse {
 
 	return &GetCartResponse{
 		UserID:        cart.UserID,
+		Currency:      cart.Currency,
 		Items:         items,
+		Version:       cart.Version,
 		TotalItems:    int32(cart.TotalItems()),
 		SubtotalCents: cart.SubtotalCents(),
 	}
 }
 
+// toStatusError maps use case errors to gRPC status errors.
+func toStatusError(err error, msg string) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidUserID),
+		errors.Is(err, usecase.ErrInvalidProduct),
+		errors.Is(err, usecase.ErrInvalidQuantity):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrItemNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrCartConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, usecase.ErrCurrencyMismatch):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, fmt.Sprintf("%s: %v", msg, err))
+	}
+}
+
 // --- Manual gRPC ServiceDesc ---
 
 // jsonCodec is a simple JSON-based gRPC codec for manual service registration.
@@ -142,6 +204,10 @@ var CartServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCart",
 			Handler:    getCartHandler,
 		},
+		{
+			MethodName: "AddItem",
+			Handler:    addItemHandler,
+		},
 		{
 			MethodName: "ClearCart",
 			Handler:    clearCartHandler,
@@ -169,6 +235,24 @@ func getCartHandler(srv interface{}, ctx context.Context, dec func(interface{})
 	return interceptor(ctx, req, info, handler)
 }
 
+func addItemHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := new(AddItemRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartService).AddItem(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cart.CartService/AddItem",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartService).AddItem(ctx, req.(*AddItemRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
 func clearCartHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	req := new(ClearCartRequest)
 	if err := dec(req); err != nil {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	"github.com/southern-martin/ecommerce/services/cart/internal/domain"
 	"github.com/southern-martin/ecommerce/services/cart/internal/usecase"
 )
@@ -24,24 +25,56 @@ func NewCartHandler(cartUC *usecase.CartUseCase, logger zerolog.Logger) *CartHan
 	}
 }
 
-// cartResponse is the standard response for cart endpoints.
+// cartResponse is the standard response for cart endpoints. Discounts are
+// those of the bundles and automatic promotions running on the cart.
 type cartResponse struct {
-	UserID       string            `json:"user_id"`
-	Items        []domain.CartItem `json:"items"`
-	TotalItems   int               `json:"total_items"`
-	SubtotalCents int64            `json:"subtotal_cents"`
+	UserID        string                 `json:"user_id"`
+	Currency      string                 `json:"currency"`
+	Items         []cartItemResponse     `json:"items"`
+	Version       int64                  `json:"version"`
+	TotalItems    int                    `json:"total_items"`
+	SubtotalCents int64                  `json:"subtotal_cents"`
+	DiscountCents int64                  `json:"discount_cents"`
+	TotalCents    int64                  `json:"total_cents"`
+	Gifts         []domain.PromotionGift `json:"gifts"`
 }
 
-func toCartResponse(cart *domain.Cart) cartResponse {
-	items := cart.Items
-	if items == nil {
-		items = []domain.CartItem{}
+type cartItemResponse struct {
+	domain.CartItem
+	DiscountCents int64 `json:"discount_cents"`
+}
+
+// toCartResponse prices a cart with the promotions running. Carts are still
+// shown, at their list prices, when they cannot be priced.
+func (h *CartHandler) toCartResponse(c *gin.Context, cart *domain.Cart) cartResponse {
+	totals, err := h.cartUC.PriceCart(c.Request.Context(), cart)
+	if err != nil {
+		h.logger.Warn().Err(err).Str("user_id", cart.UserID).Msg("failed to price cart with promotions")
+		totals = &domain.CartTotals{
+			SubtotalCents: cart.SubtotalCents(),
+			TotalCents:    cart.SubtotalCents(),
+			ItemDiscounts: make([]int64, len(cart.Items)),
+		}
+	}
+
+	items := make([]cartItemResponse, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = cartItemResponse{CartItem: item, DiscountCents: totals.ItemDiscounts[i]}
+	}
+	gifts := totals.Gifts
+	if gifts == nil {
+		gifts = []domain.PromotionGift{}
 	}
 	return cartResponse{
 		UserID:        cart.UserID,
+		Currency:      cart.Currency,
 		Items:         items,
+		Version:       cart.Version,
 		TotalItems:    cart.TotalItems(),
-		SubtotalCents: cart.SubtotalCents(),
+		SubtotalCents: totals.SubtotalCents,
+		DiscountCents: totals.DiscountCents,
+		TotalCents:    totals.TotalCents,
+		Gifts:         gifts,
 	}
 }
 
@@ -65,7 +98,7 @@ func (h *CartHandler) GetCart(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toCartResponse(cart))
+	c.JSON(http.StatusOK, h.toCartResponse(c, cart))
 }
 
 // addItemRequest is the request body for adding an item to the cart.
@@ -79,6 +112,7 @@ type addItemRequest struct {
 	Quantity    int    `json:"quantity" binding:"required,min=1"`
 	ImageURL    string `json:"image_url"`
 	SellerID    string `json:"seller_id"`
+	CategoryID  string `json:"category_id"`
 }
 
 // AddItem handles POST /api/v1/cart/items
@@ -105,15 +139,16 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 		Quantity:    req.Quantity,
 		ImageURL:    req.ImageURL,
 		SellerID:    req.SellerID,
+		CategoryID:  req.CategoryID,
 	}
 
-	cart, err := h.cartUC.AddItem(c.Request.Context(), userID, item)
+	cart, err := h.cartUC.AddItem(c.Request.Context(), userID, currency.GetCurrency(c), item)
 	if err != nil {
 		h.handleUseCaseError(c, err, "failed to add item")
 		return
 	}
 
-	c.JSON(http.StatusOK, toCartResponse(cart))
+	c.JSON(http.StatusOK, h.toCartResponse(c, cart))
 }
 
 // updateQuantityRequest is the request body for updating an item's quantity.
@@ -143,7 +178,7 @@ func (h *CartHandler) UpdateQuantity(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toCartResponse(cart))
+	c.JSON(http.StatusOK, h.toCartResponse(c, cart))
 }
 
 // removeItemRequest is the request body for removing an item from the cart.
@@ -172,7 +207,7 @@ func (h *CartHandler) RemoveItem(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toCartResponse(cart))
+	c.JSON(http.StatusOK, h.toCartResponse(c, cart))
 }
 
 // ClearCart handles DELETE /api/v1/cart
@@ -211,13 +246,13 @@ func (h *CartHandler) MergeCart(c *gin.Context) {
 		return
 	}
 
-	cart, err := h.cartUC.MergeCart(c.Request.Context(), userID, req.Items)
+	cart, err := h.cartUC.MergeCart(c.Request.Context(), userID, currency.GetCurrency(c), req.Items)
 	if err != nil {
 		h.handleUseCaseError(c, err, "failed to merge cart")
 		return
 	}
 
-	c.JSON(http.StatusOK, toCartResponse(cart))
+	c.JSON(http.StatusOK, h.toCartResponse(c, cart))
 }
 
 // Health handles GET /health
@@ -234,6 +269,9 @@ func (h *CartHandler) handleUseCaseError(c *gin.Context, err error, msg string)
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 	case errors.Is(err, usecase.ErrItemNotFound):
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCartConflict),
+		errors.Is(err, usecase.ErrCurrencyMismatch):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 	default:
 		h.logger.Error().Err(err).Msg(msg)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
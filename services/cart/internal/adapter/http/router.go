@@ -2,14 +2,18 @@ package http
 
 import (
 	"github.com/gin-gonic/gin"
+
+	"github.com/southern-martin/ecommerce/pkg/currency"
 )
 
-// NewRouter creates a new Gin router with all cart routes registered.
+// NewRouter creates a new Gin router with all cart routes registered. Items
+// are priced in the buyer's currency selected by the currency middleware.
 func NewRouter(handler *CartHandler) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
 	router.Use(gin.Recovery())
+	router.Use(currency.GinMiddleware())
 
 	// Health check
 	router.GET("/health", handler.Health)
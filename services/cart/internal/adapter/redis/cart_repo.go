@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,8 +12,10 @@ import (
 )
 
 const (
-	cartKeyPrefix = "cart:"
-	cartTTL       = 30 * 24 * time.Hour // 30 days
+	cartKeyPrefix        = "cart:"
+	cartVersionKeyPrefix = "cart_version:"
+	cartTTL              = 30 * 24 * time.Hour // 30 days
+	maxDeleteAttempts    = 3
 )
 
 // redisCartRepo implements domain.CartRepository using Redis as the primary store.
@@ -29,16 +32,47 @@ func cartKey(userID string) string {
 	return fmt.Sprintf("%s%s", cartKeyPrefix, userID)
 }
 
+// cartVersionKey is where the version of a user's cart is kept. It outlives
+// a deleted cart so that versions keep increasing across deletes and a
+// client holding a version from before a delete cannot overwrite a newer
+// cart. It expires cartTTL after the last write, like the cart.
+func cartVersionKey(userID string) string {
+	return fmt.Sprintf("%s%s", cartVersionKeyPrefix, userID)
+}
+
 // GetCart retrieves the cart for a given user from Redis.
 // Returns an empty cart if no cart exists.
 func (r *redisCartRepo) GetCart(ctx context.Context, userID string) (*domain.Cart, error) {
-	data, err := r.client.Get(ctx, cartKey(userID)).Bytes()
-	if err == redis.Nil {
-		return &domain.Cart{
+	cart, err := loadCart(ctx, r.client, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		cart = &domain.Cart{
 			UserID:    userID,
 			Items:     []domain.CartItem{},
 			UpdatedAt: time.Now().UTC(),
-		}, nil
+		}
+	}
+
+	version, err := storedVersion(ctx, r.client, userID, cart)
+	if err != nil {
+		return nil, err
+	}
+	cart.Version = version
+
+	if cart.Items == nil {
+		cart.Items = []domain.CartItem{}
+	}
+
+	return cart, nil
+}
+
+// loadCart reads the cart stored for userID, or nil if there is none.
+func loadCart(ctx context.Context, c redis.Cmdable, userID string) (*domain.Cart, error) {
+	data, err := c.Get(ctx, cartKey(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("redis get cart: %w", err)
@@ -48,33 +82,103 @@ func (r *redisCartRepo) GetCart(ctx context.Context, userID string) (*domain.Car
 	if err := json.Unmarshal(data, &cart); err != nil {
 		return nil, fmt.Errorf("unmarshal cart: %w", err)
 	}
-
-	if cart.Items == nil {
-		cart.Items = []domain.CartItem{}
-	}
-
 	return &cart, nil
 }
 
-// SaveCart persists the cart to Redis with a 30-day TTL.
+// SaveCart persists the cart to Redis with a 30-day TTL using optimistic
+// concurrency: the cart and version keys are WATCHed, the stored version is
+// compared with cart.Version, and the write is applied in a MULTI/EXEC
+// transaction. On success cart.Version is incremented.
 func (r *redisCartRepo) SaveCart(ctx context.Context, cart *domain.Cart) error {
-	data, err := json.Marshal(cart)
+	key := cartKey(cart.UserID)
+	versionKey := cartVersionKey(cart.UserID)
+	next := *cart
+	next.Version = cart.Version + 1
+
+	data, err := json.Marshal(&next)
 	if err != nil {
 		return fmt.Errorf("marshal cart: %w", err)
 	}
 
-	if err := r.client.Set(ctx, cartKey(cart.UserID), data, cartTTL).Err(); err != nil {
+	err = r.client.Watch(ctx, func(tx *redis.Tx) error {
+		stored, err := loadCart(ctx, tx, cart.UserID)
+		if err != nil {
+			return err
+		}
+		current, err := storedVersion(ctx, tx, cart.UserID, stored)
+		if err != nil {
+			return err
+		}
+		if current != cart.Version {
+			return domain.ErrCartVersionConflict
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, cartTTL)
+			pipe.Set(ctx, versionKey, next.Version, cartTTL)
+			return nil
+		})
+		return err
+	}, key, versionKey)
+	if errors.Is(err, redis.TxFailedErr) || errors.Is(err, domain.ErrCartVersionConflict) {
+		return domain.ErrCartVersionConflict
+	}
+	if err != nil {
 		return fmt.Errorf("redis set cart: %w", err)
 	}
 
+	cart.Version = next.Version
 	return nil
 }
 
-// DeleteCart removes the cart from Redis.
+// storedVersion returns the current version of userID's cart, or 0 if it
+// has never been saved. stored is the stored cart, if any: carts saved
+// before versions were kept under their own key carry theirs in the cart.
+func storedVersion(ctx context.Context, c redis.Cmdable, userID string, stored *domain.Cart) (int64, error) {
+	version, err := c.Get(ctx, cartVersionKey(userID)).Int64()
+	if err != nil && err != redis.Nil {
+		return 0, fmt.Errorf("redis get cart version: %w", err)
+	}
+	if stored != nil && stored.Version > version {
+		version = stored.Version
+	}
+	return version, nil
+}
+
+// DeleteCart removes the cart from Redis and bumps its version, so that
+// writes based on the deleted cart are rejected.
 func (r *redisCartRepo) DeleteCart(ctx context.Context, userID string) error {
-	if err := r.client.Del(ctx, cartKey(userID)).Err(); err != nil {
-		return fmt.Errorf("redis delete cart: %w", err)
+	key := cartKey(userID)
+	versionKey := cartVersionKey(userID)
+
+	deleteCart := func(tx *redis.Tx) error {
+		stored, err := loadCart(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		current, err := storedVersion(ctx, tx, userID, stored)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			pipe.Set(ctx, versionKey, current+1, cartTTL)
+			return nil
+		})
+		return err
 	}
 
-	return nil
+	// The delete is unconditional, so it is simply retried if the cart
+	// changed while it was being applied
+	for attempt := 1; attempt <= maxDeleteAttempts; attempt++ {
+		err := r.client.Watch(ctx, deleteCart, key, versionKey)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, redis.TxFailedErr) {
+			return fmt.Errorf("redis delete cart: %w", err)
+		}
+	}
+	return domain.ErrCartVersionConflict
 }
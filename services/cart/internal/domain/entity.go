@@ -2,10 +2,13 @@ package domain
 
 import "time"
 
-// Cart represents a user's shopping cart.
+// Cart represents a user's shopping cart. Item prices are in the cart's
+// Currency, the buyer's presentment currency when the first item was added.
 type Cart struct {
 	UserID    string     `json:"user_id"`
+	Currency  string     `json:"currency"`
 	Items     []CartItem `json:"items"`
+	Version   int64      `json:"version"`
 	UpdatedAt time.Time  `json:"updated_at"`
 }
 
@@ -20,6 +23,7 @@ type CartItem struct {
 	Quantity    int    `json:"quantity"`
 	ImageURL    string `json:"image_url"`
 	SellerID    string `json:"seller_id"`
+	CategoryID  string `json:"category_id,omitempty"`
 }
 
 // SubtotalCents returns the total price of all items in the cart in cents.
@@ -49,3 +53,43 @@ func (c *Cart) FindItem(productID, variantID string) int {
 	}
 	return -1
 }
+
+// PromotionLine is a cart item as the promotion service prices it.
+type PromotionLine struct {
+	LineID         string
+	ProductID      string
+	VariantID      string
+	CategoryID     string
+	SellerID       string
+	Quantity       int
+	UnitPriceCents int64
+}
+
+// PromotionGift is a free item a promotion adds to the order placed from a
+// cart.
+type PromotionGift struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id"`
+	SellerID  string `json:"seller_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// PromotionResult holds the discounts promotions give the lines of a cart
+// and, for bundles, the units of each line the bundles are made of, both
+// keyed by line ID, and the free gifts promotions add.
+type PromotionResult struct {
+	LineDiscounts map[string]int64
+	BundledUnits  map[string]int
+	Gifts         []PromotionGift
+}
+
+// CartTotals is a cart priced with the promotions running, in the cart's
+// currency. ItemDiscounts holds the discount of each of the cart's items,
+// in order.
+type CartTotals struct {
+	SubtotalCents int64
+	DiscountCents int64
+	TotalCents    int64
+	ItemDiscounts []int64
+	Gifts         []PromotionGift
+}
@@ -1,13 +1,34 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrCartVersionConflict is returned by SaveCart when the stored cart has been
+// modified since it was read (its version no longer matches).
+var ErrCartVersionConflict = errors.New("cart was modified concurrently")
 
 // CartRepository defines the interface for cart persistence.
 type CartRepository interface {
 	// GetCart retrieves the cart for a given user. Returns an empty cart if none exists.
 	GetCart(ctx context.Context, userID string) (*Cart, error)
-	// SaveCart persists the cart state.
+	// SaveCart persists the cart state if the stored version still matches
+	// cart.Version, then increments cart.Version. Returns ErrCartVersionConflict
+	// if another writer saved the cart in between.
 	SaveCart(ctx context.Context, cart *Cart) error
-	// DeleteCart removes the cart for a given user.
+	// DeleteCart removes the cart for a given user. Versions keep increasing
+	// across deletes, so writes based on the deleted cart are rejected.
 	DeleteCart(ctx context.Context, userID string) error
 }
+
+// PromotionProvider prices carts with the promotions run by the promotion
+// service. Its amounts are in the marketplace's settlement currency.
+type PromotionProvider interface {
+	// ApplyBundles applies the sellers' active bundles completed by the
+	// lines.
+	ApplyBundles(ctx context.Context, lines []PromotionLine) (*PromotionResult, error)
+	// EvaluatePromotions applies the automatic promotions running to the
+	// lines of userID's cart.
+	EvaluatePromotions(ctx context.Context, userID string, lines []PromotionLine) (*PromotionResult, error)
+}
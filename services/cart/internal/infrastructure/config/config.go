@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 )
 
 // Config holds all configuration for the cart service.
@@ -16,6 +17,15 @@ type Config struct {
 	RedisURL         string
 	NATSURL          string
 	LogLevel         string
+	// PromotionGRPCAddr is the address of the promotion service's gRPC API,
+	// which carts are priced with.
+	PromotionGRPCAddr string
+	// SettlementCurrency is the currency the promotion service's amounts
+	// are in. Exchange rates are loaded from RatesSource, a JSON rates file
+	// path or URL; when empty the converter's built-in rates are used.
+	SettlementCurrency  string
+	RatesSource         string
+	RatesRefreshMinutes int
 }
 
 // Load reads configuration from environment variables with sensible defaults.
@@ -31,6 +41,11 @@ func Load() *Config {
 		RedisURL:         getEnv("REDIS_URL", "localhost:6379"),
 		NATSURL:          getEnv("NATS_URL", "nats://localhost:4222"),
 		LogLevel:         getEnv("LOG_LEVEL", "info"),
+
+		PromotionGRPCAddr:   getEnv("PROMOTION_GRPC_ADDR", "localhost:9093"),
+		SettlementCurrency:  getEnv("SETTLEMENT_CURRENCY", "USD"),
+		RatesSource:         getEnv("EXCHANGE_RATES_SOURCE", ""),
+		RatesRefreshMinutes: getEnvInt("EXCHANGE_RATES_REFRESH_MINUTES", 60),
 	}
 }
 
@@ -50,3 +65,12 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,157 @@
+package promotion
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/southern-martin/ecommerce/pkg/grpcjson"
+	"github.com/southern-martin/ecommerce/services/cart/internal/domain"
+)
+
+// Client implements domain.PromotionProvider on top of the promotion
+// service's gRPC API.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient creates a client of the promotion service's gRPC API at addr.
+// The connection is established lazily, on the first call.
+func NewClient(addr string) (*Client, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpcjson.CallOption()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to promotion service at %s: %w", addr, err)
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the gRPC connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// --- Messages of the promotion service's gRPC API ---
+
+type orderLine struct {
+	LineID         string
+	ProductID      string
+	VariantID      string
+	CategoryID     string
+	SellerID       string
+	Quantity       int32
+	UnitPriceCents int64
+}
+
+type giftItem struct {
+	PromotionID string
+	ProductID   string
+	VariantID   string
+	SellerID    string
+	Quantity    int32
+}
+
+type lineExplanation struct {
+	LineID        string
+	DiscountCents int64
+}
+
+type bundleAllocation struct {
+	LineID        string
+	Quantity      int32
+	DiscountCents int64
+}
+
+type appliedBundle struct {
+	BundleID    string
+	Allocations []bundleAllocation
+}
+
+type applyBundlesRequest struct {
+	Lines []orderLine
+}
+
+type applyBundlesResponse struct {
+	Applied            []appliedBundle
+	TotalDiscountCents int64
+}
+
+type evaluatePromotionsRequest struct {
+	UserID string
+	Lines  []orderLine
+}
+
+type evaluatePromotionsResponse struct {
+	Lines              []lineExplanation
+	Gifts              []giftItem
+	TotalDiscountCents int64
+}
+
+// ApplyBundles applies the sellers' active bundles completed by the lines.
+func (c *Client) ApplyBundles(ctx context.Context, lines []domain.PromotionLine) (*domain.PromotionResult, error) {
+	var resp applyBundlesResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/ApplyBundles",
+		&applyBundlesRequest{Lines: toOrderLines(lines)}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to apply bundles: %w", err)
+	}
+
+	result := &domain.PromotionResult{
+		LineDiscounts: make(map[string]int64),
+		BundledUnits:  make(map[string]int),
+	}
+	for _, bundle := range resp.Applied {
+		for _, allocation := range bundle.Allocations {
+			result.LineDiscounts[allocation.LineID] += allocation.DiscountCents
+			result.BundledUnits[allocation.LineID] += int(allocation.Quantity)
+		}
+	}
+	return result, nil
+}
+
+// EvaluatePromotions applies the automatic promotions running to the
+// lines of userID's cart.
+func (c *Client) EvaluatePromotions(ctx context.Context, userID string, lines []domain.PromotionLine) (*domain.PromotionResult, error) {
+	var resp evaluatePromotionsResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/EvaluatePromotions", &evaluatePromotionsRequest{
+		UserID: userID,
+		Lines:  toOrderLines(lines),
+	}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to evaluate promotions: %w", err)
+	}
+
+	result := &domain.PromotionResult{LineDiscounts: make(map[string]int64, len(resp.Lines))}
+	for _, line := range resp.Lines {
+		if line.DiscountCents > 0 {
+			result.LineDiscounts[line.LineID] += line.DiscountCents
+		}
+	}
+	for _, gift := range resp.Gifts {
+		result.Gifts = append(result.Gifts, domain.PromotionGift{
+			ProductID: gift.ProductID,
+			VariantID: gift.VariantID,
+			SellerID:  gift.SellerID,
+			Quantity:  int(gift.Quantity),
+		})
+	}
+	return result, nil
+}
+
+func toOrderLines(lines []domain.PromotionLine) []orderLine {
+	out := make([]orderLine, len(lines))
+	for i, line := range lines {
+		out[i] = orderLine{
+			LineID:         line.LineID,
+			ProductID:      line.ProductID,
+			VariantID:      line.VariantID,
+			CategoryID:     line.CategoryID,
+			SellerID:       line.SellerID,
+			Quantity:       int32(line.Quantity),
+			UnitPriceCents: line.UnitPriceCents,
+		}
+	}
+	return out
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	"github.com/southern-martin/ecommerce/services/cart/internal/domain"
 )
 
@@ -14,27 +15,50 @@ var (
 	ErrInvalidProduct = errors.New("product ID is required")
 	ErrInvalidQuantity = errors.New("quantity must be greater than zero")
 	ErrItemNotFound   = errors.New("item not found in cart")
+	ErrCartConflict   = errors.New("cart was modified concurrently, please retry")
+	ErrCurrencyMismatch = errors.New("cart is priced in another currency, clear it to change currency")
 )
 
+// maxSaveAttempts is the number of times a cart mutation is re-applied on a
+// fresh read when SaveCart reports a version conflict.
+const maxSaveAttempts = 5
+
 // CartUseCase implements cart business logic.
 type CartUseCase struct {
-	repo      domain.CartRepository
-	publisher domain.EventPublisher
-	logger    zerolog.Logger
+	repo               domain.CartRepository
+	publisher          domain.EventPublisher
+	promotions         domain.PromotionProvider
+	converter          *currency.Converter
+	settlementCurrency string
+	logger             zerolog.Logger
 }
 
-// NewCartUseCase creates a new CartUseCase.
-func NewCartUseCase(repo domain.CartRepository, publisher domain.EventPublisher, logger zerolog.Logger) *CartUseCase {
+// NewCartUseCase creates a new CartUseCase. Carts are priced with the
+// promotions of promotions, whose amounts are converted from
+// settlementCurrency with converter.
+func NewCartUseCase(
+	repo domain.CartRepository,
+	publisher domain.EventPublisher,
+	promotions domain.PromotionProvider,
+	converter *currency.Converter,
+	settlementCurrency string,
+	logger zerolog.Logger,
+) *CartUseCase {
 	return &CartUseCase{
-		repo:      repo,
-		publisher: publisher,
-		logger:    logger.With().Str("component", "cart_usecase").Logger(),
+		repo:               repo,
+		publisher:          publisher,
+		promotions:         promotions,
+		converter:          converter,
+		settlementCurrency: currency.Normalize(settlementCurrency),
+		logger:             logger.With().Str("component", "cart_usecase").Logger(),
 	}
 }
 
 // AddItem adds or increments an item in the cart. If the item already exists
-// (matched by productID + variantID), the quantity is incremented.
-func (uc *CartUseCase) AddItem(ctx context.Context, userID string, item domain.CartItem) (*domain.Cart, error) {
+// (matched by productID + variantID), the quantity is incremented. The item
+// is priced in currencyCode, which must be the cart's currency unless the
+// cart is empty.
+func (uc *CartUseCase) AddItem(ctx context.Context, userID string, currencyCode string, item domain.CartItem) (*domain.Cart, error) {
 	if userID == "" {
 		return nil, ErrInvalidUserID
 	}
@@ -45,30 +69,27 @@ func (uc *CartUseCase) AddItem(ctx context.Context, userID string, item domain.C
 		return nil, ErrInvalidQuantity
 	}
 
-	cart, err := uc.repo.GetCart(ctx, userID)
+	cart, err := uc.updateCart(ctx, userID, func(cart *domain.Cart) error {
+		if err := priceIn(cart, currencyCode); err != nil {
+			return err
+		}
+		idx := cart.FindItem(item.ProductID, item.VariantID)
+		if idx >= 0 {
+			cart.Items[idx].Quantity += item.Quantity
+			// Update denormalized fields in case they changed
+			cart.Items[idx].PriceCents = item.PriceCents
+			cart.Items[idx].ProductName = item.ProductName
+			cart.Items[idx].VariantName = item.VariantName
+			cart.Items[idx].ImageURL = item.ImageURL
+			cart.Items[idx].SKU = item.SKU
+			cart.Items[idx].SellerID = item.SellerID
+			cart.Items[idx].CategoryID = item.CategoryID
+		} else {
+			cart.Items = append(cart.Items, item)
+		}
+		return nil
+	})
 	if err != nil {
-		uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to get cart")
-		return nil, err
-	}
-
-	idx := cart.FindItem(item.ProductID, item.VariantID)
-	if idx >= 0 {
-		cart.Items[idx].Quantity += item.Quantity
-		// Update denormalized fields in case they changed
-		cart.Items[idx].PriceCents = item.PriceCents
-		cart.Items[idx].ProductName = item.ProductName
-		cart.Items[idx].VariantName = item.VariantName
-		cart.Items[idx].ImageURL = item.ImageURL
-		cart.Items[idx].SKU = item.SKU
-		cart.Items[idx].SellerID = item.SellerID
-	} else {
-		cart.Items = append(cart.Items, item)
-	}
-
-	cart.UpdatedAt = time.Now().UTC()
-
-	if err := uc.repo.SaveCart(ctx, cart); err != nil {
-		uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to save cart")
 		return nil, err
 	}
 
@@ -92,22 +113,15 @@ func (uc *CartUseCase) RemoveItem(ctx context.Context, userID, productID, varian
 		return nil, ErrInvalidProduct
 	}
 
-	cart, err := uc.repo.GetCart(ctx, userID)
+	cart, err := uc.updateCart(ctx, userID, func(cart *domain.Cart) error {
+		idx := cart.FindItem(productID, variantID)
+		if idx < 0 {
+			return ErrItemNotFound
+		}
+		cart.Items = append(cart.Items[:idx], cart.Items[idx+1:]...)
+		return nil
+	})
 	if err != nil {
-		uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to get cart")
-		return nil, err
-	}
-
-	idx := cart.FindItem(productID, variantID)
-	if idx < 0 {
-		return nil, ErrItemNotFound
-	}
-
-	cart.Items = append(cart.Items[:idx], cart.Items[idx+1:]...)
-	cart.UpdatedAt = time.Now().UTC()
-
-	if err := uc.repo.SaveCart(ctx, cart); err != nil {
-		uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to save cart")
 		return nil, err
 	}
 
@@ -133,22 +147,15 @@ func (uc *CartUseCase) UpdateQuantity(ctx context.Context, userID, productID, va
 		return nil, ErrInvalidQuantity
 	}
 
-	cart, err := uc.repo.GetCart(ctx, userID)
+	cart, err := uc.updateCart(ctx, userID, func(cart *domain.Cart) error {
+		idx := cart.FindItem(productID, variantID)
+		if idx < 0 {
+			return ErrItemNotFound
+		}
+		cart.Items[idx].Quantity = quantity
+		return nil
+	})
 	if err != nil {
-		uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to get cart")
-		return nil, err
-	}
-
-	idx := cart.FindItem(productID, variantID)
-	if idx < 0 {
-		return nil, ErrItemNotFound
-	}
-
-	cart.Items[idx].Quantity = quantity
-	cart.UpdatedAt = time.Now().UTC()
-
-	if err := uc.repo.SaveCart(ctx, cart); err != nil {
-		uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to save cart")
 		return nil, err
 	}
 
@@ -197,47 +204,100 @@ func (uc *CartUseCase) ClearCart(ctx context.Context, userID string) error {
 	return nil
 }
 
-// MergeCart merges guest cart items into the authenticated user's cart.
-// If an item already exists, its quantity is incremented.
-func (uc *CartUseCase) MergeCart(ctx context.Context, userID string, guestItems []domain.CartItem) (*domain.Cart, error) {
+// MergeCart merges guest cart items, priced in currencyCode, into the
+// authenticated user's cart. If an item already exists, its quantity is
+// incremented.
+func (uc *CartUseCase) MergeCart(ctx context.Context, userID string, currencyCode string, guestItems []domain.CartItem) (*domain.Cart, error) {
 	if userID == "" {
 		return nil, ErrInvalidUserID
 	}
 
-	cart, err := uc.repo.GetCart(ctx, userID)
+	cart, err := uc.updateCart(ctx, userID, func(cart *domain.Cart) error {
+		if err := priceIn(cart, currencyCode); err != nil {
+			return err
+		}
+		for _, guestItem := range guestItems {
+			if guestItem.ProductID == "" || guestItem.Quantity <= 0 {
+				continue
+			}
+
+			idx := cart.FindItem(guestItem.ProductID, guestItem.VariantID)
+			if idx >= 0 {
+				cart.Items[idx].Quantity += guestItem.Quantity
+				cart.Items[idx].PriceCents = guestItem.PriceCents
+				cart.Items[idx].ProductName = guestItem.ProductName
+				cart.Items[idx].VariantName = guestItem.VariantName
+				cart.Items[idx].ImageURL = guestItem.ImageURL
+				cart.Items[idx].SKU = guestItem.SKU
+				cart.Items[idx].SellerID = guestItem.SellerID
+			} else {
+				cart.Items = append(cart.Items, guestItem)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to get cart for merge")
 		return nil, err
 	}
 
-	for _, guestItem := range guestItems {
-		if guestItem.ProductID == "" || guestItem.Quantity <= 0 {
-			continue
+	uc.logger.Info().Str("user_id", userID).Int("merged_items", len(guestItems)).Msg("cart merged")
+	return cart, nil
+}
+
+// updateCart reads the user's cart, applies mutate and saves it. If another
+// writer saved the cart in between, the mutation is re-applied on a fresh copy
+// up to maxSaveAttempts times before ErrCartConflict is returned. Errors
+// returned by mutate abort the update unchanged.
+func (uc *CartUseCase) updateCart(ctx context.Context, userID string, mutate func(*domain.Cart) error) (*domain.Cart, error) {
+	for attempt := 1; attempt <= maxSaveAttempts; attempt++ {
+		cart, err := uc.repo.GetCart(ctx, userID)
+		if err != nil {
+			uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to get cart")
+			return nil, err
 		}
 
-		idx := cart.FindItem(guestItem.ProductID, guestItem.VariantID)
-		if idx >= 0 {
-			cart.Items[idx].Quantity += guestItem.Quantity
-			cart.Items[idx].PriceCents = guestItem.PriceCents
-			cart.Items[idx].ProductName = guestItem.ProductName
-			cart.Items[idx].VariantName = guestItem.VariantName
-			cart.Items[idx].ImageURL = guestItem.ImageURL
-			cart.Items[idx].SKU = guestItem.SKU
-			cart.Items[idx].SellerID = guestItem.SellerID
-		} else {
-			cart.Items = append(cart.Items, guestItem)
+		if err := mutate(cart); err != nil {
+			return nil, err
 		}
-	}
+		cart.UpdatedAt = time.Now().UTC()
 
-	cart.UpdatedAt = time.Now().UTC()
+		err = uc.repo.SaveCart(ctx, cart)
+		if err == nil {
+			return cart, nil
+		}
+		if !errors.Is(err, domain.ErrCartVersionConflict) {
+			uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to save cart")
+			return nil, err
+		}
 
-	if err := uc.repo.SaveCart(ctx, cart); err != nil {
-		uc.logger.Error().Err(err).Str("user_id", userID).Msg("failed to save merged cart")
-		return nil, err
+		uc.logger.Debug().Str("user_id", userID).Int("attempt", attempt).Msg("cart version conflict, retrying")
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 	}
 
-	uc.logger.Info().Str("user_id", userID).Int("merged_items", len(guestItems)).Msg("cart merged")
-	return cart, nil
+	uc.logger.Warn().Str("user_id", userID).Int("attempts", maxSaveAttempts).Msg("cart update retries exhausted")
+	return nil, ErrCartConflict
+}
+
+// priceIn checks that items priced in currencyCode can be added to the cart.
+// An empty cart takes the currency; carts saved before carts had a currency
+// are priced in the default currency.
+func priceIn(cart *domain.Cart, currencyCode string) error {
+	if currencyCode == "" {
+		currencyCode = currency.DefaultCurrency
+	}
+	if len(cart.Items) == 0 {
+		cart.Currency = currencyCode
+		return nil
+	}
+	if cart.Currency == "" {
+		cart.Currency = currency.DefaultCurrency
+	}
+	if cart.Currency != currencyCode {
+		return ErrCurrencyMismatch
+	}
+	return nil
 }
 
 // publishEvent publishes a domain event, logging any errors.
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/southern-martin/ecommerce/pkg/currency"
+	"github.com/southern-martin/ecommerce/services/cart/internal/domain"
+)
+
+// PriceCart prices a cart with the sellers' bundles and then, for the units
+// left out of bundles, with the automatic promotions running, as the order
+// placed from it would be. Items are priced for the promotion service in
+// the settlement currency and the discounts converted back into the cart's
+// currency.
+func (uc *CartUseCase) PriceCart(ctx context.Context, cart *domain.Cart) (*domain.CartTotals, error) {
+	totals := &domain.CartTotals{
+		SubtotalCents: cart.SubtotalCents(),
+		ItemDiscounts: make([]int64, len(cart.Items)),
+	}
+	totals.TotalCents = totals.SubtotalCents
+	if len(cart.Items) == 0 {
+		return totals, nil
+	}
+
+	code := currency.Normalize(cart.Currency)
+	if code == "" {
+		code = currency.DefaultCurrency
+	}
+	snapshot := uc.converter.Snapshot()
+
+	lines := make([]domain.PromotionLine, len(cart.Items))
+	for i, item := range cart.Items {
+		unitPrice, err := snapshot.Convert(item.PriceCents, code, uc.settlementCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert item price: %w", err)
+		}
+		lines[i] = domain.PromotionLine{
+			LineID:         strconv.Itoa(i),
+			ProductID:      item.ProductID,
+			VariantID:      item.VariantID,
+			CategoryID:     item.CategoryID,
+			SellerID:       item.SellerID,
+			Quantity:       item.Quantity,
+			UnitPriceCents: unitPrice,
+		}
+	}
+
+	bundles, err := uc.promotions.ApplyBundles(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+	var unbundled []domain.PromotionLine
+	for _, line := range lines {
+		line.Quantity -= bundles.BundledUnits[line.LineID]
+		if line.Quantity > 0 {
+			unbundled = append(unbundled, line)
+		}
+	}
+	result := &domain.PromotionResult{}
+	if len(unbundled) > 0 {
+		if result, err = uc.promotions.EvaluatePromotions(ctx, cart.UserID, unbundled); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, item := range cart.Items {
+		lineID := strconv.Itoa(i)
+		discount := bundles.LineDiscounts[lineID] + result.LineDiscounts[lineID]
+		if discount <= 0 {
+			continue
+		}
+		if discount, err = snapshot.Convert(discount, uc.settlementCurrency, code); err != nil {
+			return nil, fmt.Errorf("failed to convert promotion discount: %w", err)
+		}
+		totals.ItemDiscounts[i] = min(discount, item.PriceCents*int64(item.Quantity))
+		totals.DiscountCents += totals.ItemDiscounts[i]
+	}
+	totals.Gifts = result.Gifts
+	totals.TotalCents -= totals.DiscountCents
+	return totals, nil
+}
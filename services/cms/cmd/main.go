@@ -14,6 +14,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 	grpcAdapter "github.com/southern-martin/ecommerce/services/cms/internal/adapter/grpc"
 	httpAdapter "github.com/southern-martin/ecommerce/services/cms/internal/adapter/http"
 	"github.com/southern-martin/ecommerce/services/cms/internal/adapter/postgres"
@@ -39,6 +40,7 @@ func main() {
 	if err := db.AutoMigrate(
 		&postgres.BannerModel{},
 		&postgres.PageModel{},
+		&postgres.PageTranslationModel{},
 		&postgres.ContentScheduleModel{},
 	); err != nil {
 		log.Fatal().Err(err).Msg("failed to auto-migrate")
@@ -55,15 +57,21 @@ func main() {
 	bannerRepo := postgres.NewBannerRepo(db)
 	pageRepo := postgres.NewPageRepo(db)
 	scheduleRepo := postgres.NewScheduleRepo(db)
+	pageTranslationRepo := postgres.NewPageTranslationRepo(db)
+
+	// Languages pages can be translated into and served in
+	bundle := i18n.NewBundle()
+	bundle.SetupDefaults()
 
 	// Initialize use cases
 	bannerUC := usecase.NewBannerUseCase(bannerRepo, publisher)
 	pageUC := usecase.NewPageUseCase(pageRepo, publisher)
 	scheduleUC := usecase.NewScheduleUseCase(scheduleRepo, publisher)
+	pageTranslationUC := usecase.NewPageTranslationUseCase(pageRepo, pageTranslationRepo, bundle)
 
 	// Initialize HTTP handler and router
-	handler := httpAdapter.NewHandler(bannerUC, pageUC, scheduleUC)
-	router := httpAdapter.NewRouter(handler)
+	handler := httpAdapter.NewHandler(bannerUC, pageUC, scheduleUC, pageTranslationUC)
+	router := httpAdapter.NewRouter(handler, bundle)
 
 	// Start HTTP server
 	httpServer := &http.Server{
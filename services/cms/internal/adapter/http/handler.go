@@ -6,15 +6,17 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 	"github.com/southern-martin/ecommerce/services/cms/internal/domain"
 	"github.com/southern-martin/ecommerce/services/cms/internal/usecase"
 )
 
 // Handler holds all HTTP handlers for the CMS service.
 type Handler struct {
-	bannerUC   *usecase.BannerUseCase
-	pageUC     *usecase.PageUseCase
-	scheduleUC *usecase.ScheduleUseCase
+	bannerUC      *usecase.BannerUseCase
+	pageUC        *usecase.PageUseCase
+	scheduleUC    *usecase.ScheduleUseCase
+	translationUC *usecase.PageTranslationUseCase
 }
 
 // NewHandler creates a new Handler.
@@ -22,11 +24,13 @@ func NewHandler(
 	bannerUC *usecase.BannerUseCase,
 	pageUC *usecase.PageUseCase,
 	scheduleUC *usecase.ScheduleUseCase,
+	translationUC *usecase.PageTranslationUseCase,
 ) *Handler {
 	return &Handler{
-		bannerUC:   bannerUC,
-		pageUC:     pageUC,
-		scheduleUC: scheduleUC,
+		bannerUC:      bannerUC,
+		pageUC:        pageUC,
+		scheduleUC:    scheduleUC,
+		translationUC: translationUC,
 	}
 }
 
@@ -52,16 +56,19 @@ func (h *Handler) ListActiveBanners(c *gin.Context) {
 
 // --- Public Page Handlers ---
 
-// GetPageBySlug returns a page by its slug.
+// GetPageBySlug returns a page by its own or a translated slug, in the
+// language negotiated from Accept-Language.
 func (h *Handler) GetPageBySlug(c *gin.Context) {
 	slug := c.Param("slug")
+	locale := i18n.GetLanguage(c)
 
-	page, err := h.pageUC.GetPageBySlug(c.Request.Context(), slug)
+	page, err := h.translationUC.GetPageBySlug(c.Request.Context(), locale, slug)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "page not found"})
 		return
 	}
 
+	c.Header("Content-Language", locale)
 	c.JSON(http.StatusOK, gin.H{"page": page})
 }
 
@@ -258,6 +265,58 @@ func (h *Handler) DeletePage(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "page deleted"})
 }
 
+type pageTranslationRequest struct {
+	Title           string `json:"title"`
+	Slug            string `json:"slug"`
+	ContentHTML     string `json:"content_html"`
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+}
+
+func (h *Handler) SetPageTranslation(c *gin.Context) {
+	var req pageTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	translation := &domain.PageTranslation{
+		PageID:          c.Param("id"),
+		Locale:          c.Param("locale"),
+		Title:           req.Title,
+		Slug:            req.Slug,
+		ContentHTML:     req.ContentHTML,
+		MetaTitle:       req.MetaTitle,
+		MetaDescription: req.MetaDescription,
+	}
+
+	if err := h.translationUC.SetTranslation(c.Request.Context(), translation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"translation": translation})
+}
+
+func (h *Handler) ListPageTranslations(c *gin.Context) {
+	translations, err := h.translationUC.ListTranslations(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"translations": translations})
+}
+
+func (h *Handler) DeletePageTranslation(c *gin.Context) {
+	if err := h.translationUC.DeleteTranslation(c.Request.Context(), c.Param("id"), c.Param("locale")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "translation deleted"})
+}
+
 func (h *Handler) ListAllPages(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
@@ -101,6 +101,46 @@ func ToPageModel(p *domain.Page) *PageModel {
 	}
 }
 
+// PageTranslationModel is the GORM model for the page_translations table.
+type PageTranslationModel struct {
+	PageID          string    `gorm:"type:uuid;primaryKey"`
+	Locale          string    `gorm:"type:varchar(10);primaryKey;uniqueIndex:idx_page_translation_slug"`
+	Title           string    `gorm:"type:varchar(255)"`
+	Slug            string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_page_translation_slug"`
+	ContentHTML     string    `gorm:"type:text"`
+	MetaTitle       string    `gorm:"type:varchar(255)"`
+	MetaDescription string    `gorm:"type:text"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+func (PageTranslationModel) TableName() string { return "page_translations" }
+
+func (m *PageTranslationModel) ToDomain() *domain.PageTranslation {
+	return &domain.PageTranslation{
+		PageID:          m.PageID,
+		Locale:          m.Locale,
+		Title:           m.Title,
+		Slug:            m.Slug,
+		ContentHTML:     m.ContentHTML,
+		MetaTitle:       m.MetaTitle,
+		MetaDescription: m.MetaDescription,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
+
+func ToPageTranslationModel(t *domain.PageTranslation) *PageTranslationModel {
+	return &PageTranslationModel{
+		PageID:          t.PageID,
+		Locale:          t.Locale,
+		Title:           t.Title,
+		Slug:            t.Slug,
+		ContentHTML:     t.ContentHTML,
+		MetaTitle:       t.MetaTitle,
+		MetaDescription: t.MetaDescription,
+		UpdatedAt:       t.UpdatedAt,
+	}
+}
+
 // ContentScheduleModel is the GORM model for the content_schedules table.
 type ContentScheduleModel struct {
 	ID          string    `gorm:"type:uuid;primaryKey"`
@@ -86,5 +86,10 @@ func (r *PageRepo) Update(ctx context.Context, pg *domain.Page) error {
 }
 
 func (r *PageRepo) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&PageModel{}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("page_id = ?", id).Delete(&PageTranslationModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&PageModel{}).Error
+	})
 }
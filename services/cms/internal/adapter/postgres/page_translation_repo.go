@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/southern-martin/ecommerce/services/cms/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PageTranslationRepo implements domain.PageTranslationRepository.
+type PageTranslationRepo struct {
+	db *gorm.DB
+}
+
+// NewPageTranslationRepo creates a new PageTranslationRepo.
+func NewPageTranslationRepo(db *gorm.DB) *PageTranslationRepo {
+	return &PageTranslationRepo{db: db}
+}
+
+func (r *PageTranslationRepo) Upsert(ctx context.Context, t *domain.PageTranslation) error {
+	model := ToPageTranslationModel(t)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "page_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "slug", "content_html", "meta_title", "meta_description", "updated_at"}),
+	}).Create(model).Error
+}
+
+func (r *PageTranslationRepo) ListByPage(ctx context.Context, pageID string) ([]domain.PageTranslation, error) {
+	var models []PageTranslationModel
+	if err := r.db.WithContext(ctx).Where("page_id = ?", pageID).Order("locale ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	translations := make([]domain.PageTranslation, len(models))
+	for i, m := range models {
+		translations[i] = *m.ToDomain()
+	}
+	return translations, nil
+}
+
+func (r *PageTranslationRepo) GetBySlug(ctx context.Context, locale, slug string) (*domain.PageTranslation, error) {
+	query := r.db.WithContext(ctx).Where("slug = ?", slug)
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+	var model PageTranslationModel
+	if err := query.First(&model).Error; err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *PageTranslationRepo) Delete(ctx context.Context, pageID, locale string) error {
+	return r.db.WithContext(ctx).Where("page_id = ? AND locale = ?", pageID, locale).Delete(&PageTranslationModel{}).Error
+}
@@ -31,6 +31,19 @@ type Page struct {
 	UpdatedAt       time.Time
 }
 
+// PageTranslation holds a page's content in one locale. Empty fields fall
+// back along the locale's fallback chain to the page itself.
+type PageTranslation struct {
+	PageID          string
+	Locale          string
+	Title           string
+	Slug            string
+	ContentHTML     string
+	MetaTitle       string
+	MetaDescription string
+	UpdatedAt       time.Time
+}
+
 // PageStatus represents the publication status of a page.
 type PageStatus string
 
@@ -23,6 +23,16 @@ type PageRepository interface {
 	Delete(ctx context.Context, id string) error
 }
 
+// PageTranslationRepository defines the interface for page translation persistence.
+type PageTranslationRepository interface {
+	Upsert(ctx context.Context, t *PageTranslation) error
+	ListByPage(ctx context.Context, pageID string) ([]PageTranslation, error)
+	// GetBySlug finds a translation by its slug in locale, or in any locale
+	// when locale is empty.
+	GetBySlug(ctx context.Context, locale, slug string) (*PageTranslation, error)
+	Delete(ctx context.Context, pageID, locale string) error
+}
+
 // ScheduleRepository defines the interface for content schedule persistence.
 type ScheduleRepository interface {
 	GetPending(ctx context.Context) ([]ContentSchedule, error)
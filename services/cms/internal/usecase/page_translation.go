@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/southern-martin/ecommerce/pkg/i18n"
+	"github.com/southern-martin/ecommerce/services/cms/internal/domain"
+)
+
+// PageTranslationUseCase manages page translations and serves pages in the
+// language of the request, following the i18n fallback chain.
+type PageTranslationUseCase struct {
+	pageRepo        domain.PageRepository
+	translationRepo domain.PageTranslationRepository
+	bundle          *i18n.Bundle
+}
+
+// NewPageTranslationUseCase creates a new PageTranslationUseCase.
+func NewPageTranslationUseCase(pageRepo domain.PageRepository, translationRepo domain.PageTranslationRepository, bundle *i18n.Bundle) *PageTranslationUseCase {
+	return &PageTranslationUseCase{
+		pageRepo:        pageRepo,
+		translationRepo: translationRepo,
+		bundle:          bundle,
+	}
+}
+
+// SetTranslation creates or replaces a page's translation in a locale. The
+// slug is generated from the title when not given.
+func (uc *PageTranslationUseCase) SetTranslation(ctx context.Context, t *domain.PageTranslation) error {
+	if _, err := uc.pageRepo.GetByID(ctx, t.PageID); err != nil {
+		return fmt.Errorf("page not found: %w", err)
+	}
+	if !i18n.IsSupported(t.Locale) {
+		return fmt.Errorf("unsupported locale %q", t.Locale)
+	}
+	t.Locale = uc.bundle.MatchLanguage(t.Locale)
+
+	t.Slug = slugify(t.Slug)
+	if t.Slug == "" {
+		t.Slug = slugify(t.Title)
+	}
+	if t.Slug == "" {
+		return fmt.Errorf("translation needs a title or slug")
+	}
+	t.UpdatedAt = time.Now()
+
+	if err := uc.translationRepo.Upsert(ctx, t); err != nil {
+		return fmt.Errorf("failed to save translation: %w", err)
+	}
+	return nil
+}
+
+// ListTranslations lists every translation of a page.
+func (uc *PageTranslationUseCase) ListTranslations(ctx context.Context, pageID string) ([]domain.PageTranslation, error) {
+	return uc.translationRepo.ListByPage(ctx, pageID)
+}
+
+// DeleteTranslation removes a page's translation in a locale.
+func (uc *PageTranslationUseCase) DeleteTranslation(ctx context.Context, pageID, locale string) error {
+	if !i18n.IsSupported(locale) {
+		return fmt.Errorf("unsupported locale %q", locale)
+	}
+	return uc.translationRepo.Delete(ctx, pageID, uc.bundle.MatchLanguage(locale))
+}
+
+// GetPageBySlug finds a page by a translated slug in the locale's fallback
+// chain, then in any locale, then by the page's own slug, and returns it
+// with its content in locale.
+func (uc *PageTranslationUseCase) GetPageBySlug(ctx context.Context, locale, slug string) (*domain.Page, error) {
+	chain := uc.bundle.FallbackChain(locale)
+
+	var page *domain.Page
+	var err error
+	for _, l := range append(chain, "") {
+		t, lookupErr := uc.translationRepo.GetBySlug(ctx, l, slug)
+		if lookupErr == nil {
+			page, err = uc.pageRepo.GetByID(ctx, t.PageID)
+			break
+		}
+	}
+	if page == nil && err == nil {
+		page, err = uc.pageRepo.GetBySlug(ctx, slug)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	translations, err := uc.translationRepo.ListByPage(ctx, page.ID)
+	if err != nil {
+		return nil, err
+	}
+	byLocale := make(map[string]domain.PageTranslation, len(translations))
+	for _, t := range translations {
+		byLocale[t.Locale] = t
+	}
+	// Walk the chain from its end so nearer locales win.
+	for i := len(chain) - 1; i >= 0; i-- {
+		t, ok := byLocale[chain[i]]
+		if !ok {
+			continue
+		}
+		if t.Title != "" {
+			page.Title = t.Title
+		}
+		if t.Slug != "" {
+			page.Slug = t.Slug
+		}
+		if t.ContentHTML != "" {
+			page.ContentHTML = t.ContentHTML
+		}
+		if t.MetaTitle != "" {
+			page.MetaTitle = t.MetaTitle
+		}
+		if t.MetaDescription != "" {
+			page.MetaDescription = t.MetaDescription
+		}
+	}
+	return page, nil
+}
+
+// slugify lowercases s and joins its runs of letters and digits with
+// hyphens. Unlike generateSlug it keeps non-Latin scripts.
+func slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	return b.String()
+}
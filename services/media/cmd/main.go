@@ -57,7 +57,7 @@ func main() {
 	mediaRepo := postgres.NewMediaRepo(db)
 
 	// Initialize use cases
-	mediaUC := usecase.NewMediaUseCase(mediaRepo, storageClient, publisher)
+	mediaUC := usecase.NewMediaUseCase(mediaRepo, storageClient, publisher, cfg.DownloadSigningSecret)
 
 	// Initialize HTTP handler and router
 	handler := httpAdapter.NewHandler(mediaUC)
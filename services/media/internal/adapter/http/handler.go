@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -133,9 +134,34 @@ func (h *Handler) GetUploadURL(c *gin.Context) {
 func (h *Handler) GetDownloadURL(c *gin.Context) {
 	id := c.Param("id")
 	url, err := h.mediaUC.GenerateDownloadURL(c.Request.Context(), id)
+	if errors.Is(err, usecase.ErrPrivateMedia) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "media not found"})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"download_url": url})
 }
+
+// SignedDownload handles GET /api/v1/media/:id/signed-download.
+// It verifies the signed link and redirects to the storage download URL.
+func (h *Handler) SignedDownload(c *gin.Context) {
+	url, err := h.mediaUC.GenerateSignedDownloadURL(
+		c.Request.Context(),
+		c.Param("id"),
+		c.Query("owner"),
+		c.Query("expires"),
+		c.Query("signature"),
+	)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSignature), errors.Is(err, usecase.ErrLinkExpired):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusNotFound, gin.H{"error": "media not found"})
+		return
+	}
+	c.Redirect(http.StatusFound, url.URL)
+}
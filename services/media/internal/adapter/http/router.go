@@ -25,6 +25,7 @@ func NewRouter(handler *Handler) *gin.Engine {
 			media.DELETE("/:id", handler.DeleteMedia)
 			media.POST("/upload-url", handler.GetUploadURL)
 			media.GET("/:id/download-url", handler.GetDownloadURL)
+			media.GET("/:id/signed-download", handler.SignedDownload)
 		}
 	}
 
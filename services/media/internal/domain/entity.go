@@ -19,6 +19,15 @@ type Media struct {
 	CreatedAt    time.Time   `json:"created_at"`
 }
 
+// OwnerTypeDigitalProduct marks files sold as digital products. They are
+// private and may only be downloaded through signed links.
+const OwnerTypeDigitalProduct = "digital_product"
+
+// IsPrivate reports whether the media must not be served without a signed link.
+func (m *Media) IsPrivate() bool {
+	return m.OwnerType == OwnerTypeDigitalProduct
+}
+
 // MediaStatus represents the processing status of a media file.
 type MediaStatus string
 
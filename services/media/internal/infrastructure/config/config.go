@@ -13,6 +13,8 @@ type Config struct {
 	NATS     NATSConfig
 	S3       S3Config
 	LogLevel string
+	// DownloadSigningSecret verifies signed download links for private media.
+	DownloadSigningSecret string
 }
 
 // PostgresConfig holds Postgres connection configuration.
@@ -49,9 +51,10 @@ func (c PostgresConfig) DSN() string {
 // Load reads configuration from environment variables with sensible defaults.
 func Load() *Config {
 	return &Config{
-		HTTPPort: getEnv("HTTP_PORT", "8089"),
-		GRPCPort: getEnv("GRPC_PORT", "9089"),
-		LogLevel: getEnv("LOG_LEVEL", "info"),
+		HTTPPort:              getEnv("HTTP_PORT", "8089"),
+		GRPCPort:              getEnv("GRPC_PORT", "9089"),
+		LogLevel:              getEnv("LOG_LEVEL", "info"),
+		DownloadSigningSecret: getEnv("DOWNLOAD_SIGNING_SECRET", "dev-download-signing-secret"),
 		Postgres: PostgresConfig{
 			User:     getEnv("POSTGRES_USER", "postgres"),
 			Password: getEnv("POSTGRES_PASSWORD", "postgres"),
@@ -2,7 +2,12 @@ package usecase
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,14 +20,24 @@ type MediaUseCase struct {
 	repo      domain.MediaRepository
 	storage   domain.StorageClient
 	publisher domain.EventPublisher
+	// signingSecret is shared with the services that issue signed download links.
+	signingSecret string
 }
 
+// Errors returned for signed downloads.
+var (
+	ErrPrivateMedia     = errors.New("media is private and requires a signed download link")
+	ErrInvalidSignature = errors.New("invalid download signature")
+	ErrLinkExpired      = errors.New("download link has expired")
+)
+
 // NewMediaUseCase creates a new MediaUseCase.
-func NewMediaUseCase(repo domain.MediaRepository, storage domain.StorageClient, publisher domain.EventPublisher) *MediaUseCase {
+func NewMediaUseCase(repo domain.MediaRepository, storage domain.StorageClient, publisher domain.EventPublisher, signingSecret string) *MediaUseCase {
 	return &MediaUseCase{
-		repo:      repo,
-		storage:   storage,
-		publisher: publisher,
+		repo:          repo,
+		storage:       storage,
+		publisher:     publisher,
+		signingSecret: signingSecret,
 	}
 }
 
@@ -37,7 +52,7 @@ type CreateMediaRequest struct {
 
 // CreateMediaResponse holds the created media and its upload URL.
 type CreateMediaResponse struct {
-	Media     *domain.Media      `json:"media"`
+	Media     *domain.Media        `json:"media"`
 	UploadURL *domain.PresignedURL `json:"upload_url"`
 }
 
@@ -134,5 +149,40 @@ func (uc *MediaUseCase) GenerateDownloadURL(ctx context.Context, id string) (*do
 	if err != nil {
 		return nil, err
 	}
+	if media.IsPrivate() {
+		return nil, ErrPrivateMedia
+	}
+	return uc.storage.GenerateDownloadURL(ctx, media.FileName)
+}
+
+// GenerateSignedDownloadURL verifies a signed download link and returns a
+// presigned storage URL for the media it grants access to. The signature is
+// an HMAC-SHA256 over "mediaID|ownerID|expires" using the shared secret.
+func (uc *MediaUseCase) GenerateSignedDownloadURL(ctx context.Context, id, ownerID, expires, signature string) (*domain.PresignedURL, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	expected := signDownload(uc.signingSecret, id, ownerID, expiresAt)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+	if time.Now().Unix() > expiresAt {
+		return nil, ErrLinkExpired
+	}
+
+	media, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if media.OwnerID != ownerID {
+		return nil, ErrInvalidSignature
+	}
 	return uc.storage.GenerateDownloadURL(ctx, media.FileName)
 }
+
+func signDownload(secret, mediaID, ownerID string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%s|%s|%d", mediaID, ownerID, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
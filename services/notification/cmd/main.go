@@ -58,6 +58,17 @@ func main() {
 	notificationUC := usecase.NewNotificationUseCase(notificationRepo, publisher)
 	preferenceUC := usecase.NewPreferenceUseCase(preferenceRepo)
 
+	// Notify sellers of questions about their products
+	if err := natsInfra.StartQuestionSubscriber(publisher, notificationUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to question events")
+	}
+
+	// Notify buyers of renewals, failed payments and cancellations of
+	// their subscriptions
+	if err := natsInfra.StartSubscriptionSubscriber(publisher, notificationUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to subscription events")
+	}
+
 	// Initialize HTTP handler and router
 	handler := httpAdapter.NewHandler(notificationUC, preferenceUC)
 	router := httpAdapter.NewRouter(handler)
@@ -21,12 +21,14 @@ type Notification struct {
 type NotificationType string
 
 const (
-	TypeOrderUpdate    NotificationType = "order_update"
-	TypePaymentUpdate  NotificationType = "payment_update"
-	TypeShipmentUpdate NotificationType = "shipment_update"
-	TypeReturnUpdate   NotificationType = "return_update"
-	TypePromotion      NotificationType = "promotion"
-	TypeSystem         NotificationType = "system"
+	TypeOrderUpdate     NotificationType = "order_update"
+	TypePaymentUpdate   NotificationType = "payment_update"
+	TypeShipmentUpdate  NotificationType = "shipment_update"
+	TypeReturnUpdate    NotificationType = "return_update"
+	TypeProductQuestion NotificationType = "product_question"
+	TypeSubscription    NotificationType = "subscription"
+	TypePromotion       NotificationType = "promotion"
+	TypeSystem          NotificationType = "system"
 )
 
 // NotificationChannel represents the delivery channel for a notification.
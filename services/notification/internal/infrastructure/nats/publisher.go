@@ -40,6 +40,13 @@ func (p *Publisher) Publish(ctx context.Context, subject string, data interface{
 	return nil
 }
 
+// Subscribe registers a handler for messages on the given NATS subject.
+func (p *Publisher) Subscribe(subject string, handler func(data []byte)) (*nats.Subscription, error) {
+	return p.conn.Subscribe(subject, func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+}
+
 // Close closes the NATS connection.
 func (p *Publisher) Close() {
 	if p.conn != nil {
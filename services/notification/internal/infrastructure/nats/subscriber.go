@@ -0,0 +1,155 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/notification/internal/domain"
+	"github.com/southern-martin/ecommerce/services/notification/internal/usecase"
+)
+
+// subjectQuestionCreated is published by the review service when a buyer
+// asks a question about a product.
+const subjectQuestionCreated = "question.created"
+
+// questionCreatedEvent is the question.created payload.
+type questionCreatedEvent struct {
+	QuestionID  string `json:"question_id"`
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	SellerID    string `json:"seller_id"`
+	UserName    string `json:"user_name"`
+	Content     string `json:"content"`
+}
+
+// StartQuestionSubscriber notifies sellers in-app of new questions about
+// their products.
+func StartQuestionSubscriber(p *Publisher, notificationUC *usecase.NotificationUseCase) error {
+	_, err := p.Subscribe(subjectQuestionCreated, func(data []byte) {
+		var event questionCreatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode question.created event")
+			return
+		}
+		if event.SellerID == "" {
+			return
+		}
+
+		payload, _ := json.Marshal(map[string]string{
+			"question_id": event.QuestionID,
+			"product_id":  event.ProductID,
+		})
+		subject := "New question about your product"
+		if event.ProductName != "" {
+			subject = fmt.Sprintf("New question about %s", event.ProductName)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := notificationUC.SendNotification(ctx, usecase.SendNotificationRequest{
+			UserID:  event.SellerID,
+			Type:    string(domain.TypeProductQuestion),
+			Channel: string(domain.ChannelInApp),
+			Subject: subject,
+			Body:    event.Content,
+			Data:    string(payload),
+		}); err != nil {
+			log.Error().Err(err).Str("question_id", event.QuestionID).Msg("failed to notify seller of question")
+		}
+	})
+	return err
+}
+
+// Subjects published by the order service as buyers' subscriptions renew.
+const (
+	subjectSubscriptionRenewalUpcoming = "subscription.renewal.upcoming"
+	subjectSubscriptionPaymentFailed   = "subscription.payment.failed"
+	subjectSubscriptionCancelled       = "subscription.cancelled"
+)
+
+// subscriptionEvent is the payload of the subscription subjects.
+type subscriptionEvent struct {
+	SubscriptionID string     `json:"subscription_id"`
+	BuyerID        string     `json:"buyer_id"`
+	ProductID      string     `json:"product_id"`
+	ProductName    string     `json:"product_name"`
+	Quantity       int        `json:"quantity"`
+	Status         string     `json:"status"`
+	NextRunAt      *time.Time `json:"next_run_at"`
+	OrderID        string     `json:"order_id"`
+	FailedAttempts int        `json:"failed_attempts"`
+	NextRetryAt    *time.Time `json:"next_retry_at"`
+}
+
+// subscriptionMessage returns the subject and body of the notification
+// sent to the buyer for a subscription event.
+func subscriptionMessage(subject string, event subscriptionEvent) (string, string) {
+	switch subject {
+	case subjectSubscriptionRenewalUpcoming:
+		body := fmt.Sprintf("Your order of %d x %s will be placed soon.", event.Quantity, event.ProductName)
+		if event.NextRunAt != nil {
+			body = fmt.Sprintf("Your order of %d x %s will be placed on %s. You can skip or pause it before then.",
+				event.Quantity, event.ProductName, event.NextRunAt.Format("January 2, 2006"))
+		}
+		return fmt.Sprintf("Your %s subscription renews soon", event.ProductName), body
+	case subjectSubscriptionPaymentFailed:
+		body := "We could not charge your saved card for your latest order. Please check your payment method."
+		if event.NextRetryAt != nil {
+			body = fmt.Sprintf("We could not charge your saved card for your latest order. We will try again on %s; please check your payment method.",
+				event.NextRetryAt.Format("January 2, 2006"))
+		}
+		return fmt.Sprintf("Payment for your %s subscription failed", event.ProductName), body
+	default:
+		return fmt.Sprintf("Your %s subscription was cancelled", event.ProductName),
+			"Your subscription has been cancelled and no further orders will be placed."
+	}
+}
+
+// StartSubscriptionSubscriber notifies buyers in-app of upcoming renewals,
+// failed renewal payments and cancellations of their subscriptions.
+func StartSubscriptionSubscriber(p *Publisher, notificationUC *usecase.NotificationUseCase) error {
+	subjects := []string{
+		subjectSubscriptionRenewalUpcoming,
+		subjectSubscriptionPaymentFailed,
+		subjectSubscriptionCancelled,
+	}
+	for _, natsSubject := range subjects {
+		if _, err := p.Subscribe(natsSubject, func(data []byte) {
+			var event subscriptionEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				log.Error().Err(err).Str("subject", natsSubject).Msg("failed to decode subscription event")
+				return
+			}
+			if event.BuyerID == "" {
+				return
+			}
+
+			payload, _ := json.Marshal(map[string]string{
+				"subscription_id": event.SubscriptionID,
+				"product_id":      event.ProductID,
+				"order_id":        event.OrderID,
+			})
+			subject, body := subscriptionMessage(natsSubject, event)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if _, err := notificationUC.SendNotification(ctx, usecase.SendNotificationRequest{
+				UserID:  event.BuyerID,
+				Type:    string(domain.TypeSubscription),
+				Channel: string(domain.ChannelInApp),
+				Subject: subject,
+				Body:    body,
+				Data:    string(payload),
+			}); err != nil {
+				log.Error().Err(err).Str("subscription_id", event.SubscriptionID).Msg("failed to notify buyer of subscription")
+			}
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -14,12 +14,16 @@ import (
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	grpcAdapter "github.com/southern-martin/ecommerce/services/order/internal/adapter/grpc"
 	httpAdapter "github.com/southern-martin/ecommerce/services/order/internal/adapter/http"
 	"github.com/southern-martin/ecommerce/services/order/internal/adapter/postgres"
 	"github.com/southern-martin/ecommerce/services/order/internal/infrastructure/config"
 	"github.com/southern-martin/ecommerce/services/order/internal/infrastructure/database"
 	natsInfra "github.com/southern-martin/ecommerce/services/order/internal/infrastructure/nats"
+	"github.com/southern-martin/ecommerce/services/order/internal/infrastructure/product"
+	"github.com/southern-martin/ecommerce/services/order/internal/infrastructure/promotion"
+	"github.com/southern-martin/ecommerce/services/order/internal/infrastructure/scheduler"
 	"github.com/southern-martin/ecommerce/services/order/internal/usecase"
 )
 
@@ -48,15 +52,54 @@ func main() {
 	// Initialize repositories
 	orderRepo := postgres.NewOrderRepo(db)
 	sellerOrderRepo := postgres.NewSellerOrderRepo(db)
+	subscriptionRepo := postgres.NewSubscriptionRepo(db)
+
+	// Exchange rates locked onto orders when they are placed
+	ratesCtx, stopRates := context.WithCancel(context.Background())
+	defer stopRates()
+	converter := currency.NewConverter()
+	if src := currency.NewRateSource(cfg.Currency.RatesSource); src != nil {
+		if err := converter.LoadRates(ratesCtx, src); err != nil {
+			log.Warn().Err(err).Msg("failed to load exchange rates, using default rates")
+		}
+		converter.StartRefresher(ratesCtx, src, time.Duration(cfg.Currency.RatesRefreshMinutes)*time.Minute, func(err error) {
+			log.Error().Err(err).Msg("failed to refresh exchange rates")
+		})
+	}
+
+	// Orders are priced with the promotion service's promotions
+	promotions, err := promotion.NewClient(cfg.PromotionGRPCAddr)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create promotion client")
+	}
+	defer promotions.Close()
+
+	// Products, subscription plans and prices are read from the catalog
+	catalog := product.NewClient(cfg.ProductServiceURL)
 
 	// Initialize use cases
-	createOrderUC := usecase.NewCreateOrderUseCase(orderRepo, sellerOrderRepo, publisher)
+	createOrderUC := usecase.NewCreateOrderUseCase(orderRepo, sellerOrderRepo, publisher, catalog, promotions, converter, cfg.Currency.SettlementCurrency)
 	getOrderUC := usecase.NewGetOrderUseCase(orderRepo, sellerOrderRepo)
 	updateStatusUC := usecase.NewUpdateOrderStatusUseCase(orderRepo, sellerOrderRepo, publisher)
 	cancelOrderUC := usecase.NewCancelOrderUseCase(orderRepo, sellerOrderRepo, publisher)
+	subscriptionUC := usecase.NewSubscriptionUseCase(
+		subscriptionRepo, orderRepo, createOrderUC, cancelOrderUC,
+		catalog, publisher,
+		time.Duration(cfg.Subscription.ReminderHours)*time.Hour,
+	)
+
+	// Follow renewal payments for dunning
+	if err := natsInfra.StartPaymentSubscriber(publisher, subscriptionUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to start payment subscriber")
+	}
+
+	// Place subscription renewals as they fall due
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	scheduler.StartSubscriptionScheduler(schedulerCtx, subscriptionUC, time.Duration(cfg.Subscription.SchedulerIntervalSecs)*time.Second)
 
 	// Initialize HTTP handler and router
-	handler := httpAdapter.NewHandler(createOrderUC, getOrderUC, updateStatusUC, cancelOrderUC)
+	handler := httpAdapter.NewHandler(createOrderUC, getOrderUC, updateStatusUC, cancelOrderUC, subscriptionUC)
 	router := httpAdapter.NewRouter(handler)
 
 	// Start HTTP server
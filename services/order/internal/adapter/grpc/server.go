@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	_ "github.com/southern-martin/ecommerce/pkg/grpcjson"
 	"github.com/southern-martin/ecommerce/services/order/internal/domain"
 	"github.com/southern-martin/ecommerce/services/order/internal/usecase"
 	"google.golang.org/grpc"
@@ -1,10 +1,12 @@
 package http
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	"github.com/southern-martin/ecommerce/services/order/internal/domain"
 	"github.com/southern-martin/ecommerce/services/order/internal/usecase"
 )
@@ -15,6 +17,7 @@ type Handler struct {
 	getOrder      *usecase.GetOrderUseCase
 	updateStatus  *usecase.UpdateOrderStatusUseCase
 	cancelOrder   *usecase.CancelOrderUseCase
+	subscription  *usecase.SubscriptionUseCase
 }
 
 // NewHandler creates a new Handler instance.
@@ -23,12 +26,14 @@ func NewHandler(
 	getOrder *usecase.GetOrderUseCase,
 	updateStatus *usecase.UpdateOrderStatusUseCase,
 	cancelOrder *usecase.CancelOrderUseCase,
+	subscription *usecase.SubscriptionUseCase,
 ) *Handler {
 	return &Handler{
 		createOrder:  createOrder,
 		getOrder:     getOrder,
 		updateStatus: updateStatus,
 		cancelOrder:  cancelOrder,
+		subscription: subscription,
 	}
 }
 
@@ -37,8 +42,10 @@ func NewHandler(
 type createOrderRequest struct {
 	BuyerID         string             `json:"buyer_id" binding:"required"`
 	Currency        string             `json:"currency"`
-	ShippingAddress addressDTO         `json:"shipping_address" binding:"required"`
+	ShippingAddress addressDTO         `json:"shipping_address"`
 	Items           []orderItemRequest `json:"items" binding:"required,min=1"`
+	ShippingQuotes  []shippingQuoteDTO `json:"shipping_quotes"`
+	CouponCodes     []string           `json:"coupon_codes"`
 }
 
 type addressDTO struct {
@@ -52,6 +59,13 @@ type addressDTO struct {
 	Phone       string `json:"phone"`
 }
 
+type shippingQuoteDTO struct {
+	SellerID    string `json:"seller_id"`
+	CarrierCode string `json:"carrier_code"`
+	ServiceName string `json:"service_name"`
+	RateCents   int64  `json:"rate_cents" binding:"min=0"`
+}
+
 type orderItemRequest struct {
 	ProductID      string `json:"product_id" binding:"required"`
 	VariantID      string `json:"variant_id"`
@@ -69,21 +83,26 @@ type updateStatusRequest struct {
 }
 
 type orderResponse struct {
-	ID              string              `json:"id"`
-	OrderNumber     string              `json:"order_number"`
-	BuyerID         string              `json:"buyer_id"`
-	Status          string              `json:"status"`
-	SubtotalCents   int64               `json:"subtotal_cents"`
-	ShippingCents   int64               `json:"shipping_cents"`
-	TaxCents        int64               `json:"tax_cents"`
-	DiscountCents   int64               `json:"discount_cents"`
-	TotalCents      int64               `json:"total_cents"`
-	Currency        string              `json:"currency"`
-	ShippingAddress addressDTO          `json:"shipping_address"`
-	Items           []orderItemResponse `json:"items"`
-	SellerOrders    []sellerOrderResponse `json:"seller_orders"`
-	CreatedAt       string              `json:"created_at"`
-	UpdatedAt       string              `json:"updated_at"`
+	ID                   string                `json:"id"`
+	OrderNumber          string                `json:"order_number"`
+	BuyerID              string                `json:"buyer_id"`
+	Status               string                `json:"status"`
+	SubtotalCents        int64                 `json:"subtotal_cents"`
+	ShippingCents        int64                 `json:"shipping_cents"`
+	TaxCents             int64                 `json:"tax_cents"`
+	DiscountCents        int64                 `json:"discount_cents"`
+	TotalCents           int64                 `json:"total_cents"`
+	Currency             string                `json:"currency"`
+	SettlementCurrency   string                `json:"settlement_currency"`
+	SettlementTotalCents int64                 `json:"settlement_total_cents"`
+	ExchangeRate         float64               `json:"exchange_rate"`
+	RatesLockedAt        string                `json:"rates_locked_at,omitempty"`
+	ShippingAddress      addressDTO            `json:"shipping_address"`
+	Items                []orderItemResponse   `json:"items"`
+	SellerOrders         []sellerOrderResponse `json:"seller_orders"`
+	SubscriptionID       string                `json:"subscription_id,omitempty"`
+	CreatedAt            string                `json:"created_at"`
+	UpdatedAt            string                `json:"updated_at"`
 }
 
 type orderItemResponse struct {
@@ -97,18 +116,67 @@ type orderItemResponse struct {
 	Quantity       int    `json:"quantity"`
 	UnitPriceCents int64  `json:"unit_price_cents"`
 	TotalCents     int64  `json:"total_cents"`
+	DiscountCents  int64  `json:"discount_cents"`
 	SellerID       string `json:"seller_id"`
 	ImageURL       string `json:"image_url"`
+	IsDigital      bool   `json:"is_digital"`
 }
 
 type sellerOrderResponse struct {
-	ID            string `json:"id"`
-	OrderID       string `json:"order_id"`
-	SellerID      string `json:"seller_id"`
-	Status        string `json:"status"`
-	SubtotalCents int64  `json:"subtotal_cents"`
-	CreatedAt     string `json:"created_at"`
-	UpdatedAt     string `json:"updated_at"`
+	ID                      string `json:"id"`
+	OrderID                 string `json:"order_id"`
+	SellerID                string `json:"seller_id"`
+	Status                  string `json:"status"`
+	SubtotalCents           int64  `json:"subtotal_cents"`
+	DiscountCents           int64  `json:"discount_cents"`
+	SettlementSubtotalCents int64  `json:"settlement_subtotal_cents"`
+	CreatedAt               string `json:"created_at"`
+	UpdatedAt               string `json:"updated_at"`
+}
+
+type subscribeRequest struct {
+	BuyerID         string     `json:"buyer_id" binding:"required"`
+	ProductID       string     `json:"product_id" binding:"required"`
+	PlanID          string     `json:"plan_id" binding:"required"`
+	Quantity        int        `json:"quantity" binding:"required,min=1"`
+	Currency        string     `json:"currency"`
+	ShippingAddress addressDTO `json:"shipping_address"`
+	PaymentMethodID string     `json:"payment_method_id" binding:"required"`
+}
+
+type updateSubscriptionRequest struct {
+	BuyerID         string      `json:"buyer_id" binding:"required"`
+	Quantity        *int        `json:"quantity"`
+	ShippingAddress *addressDTO `json:"shipping_address"`
+	PaymentMethodID *string     `json:"payment_method_id"`
+}
+
+type subscriptionResponse struct {
+	ID              string     `json:"id"`
+	BuyerID         string     `json:"buyer_id"`
+	PlanID          string     `json:"plan_id"`
+	ProductID       string     `json:"product_id"`
+	VariantID       string     `json:"variant_id"`
+	SellerID        string     `json:"seller_id"`
+	ProductName     string     `json:"product_name"`
+	VariantName     string     `json:"variant_name"`
+	SKU             string     `json:"sku"`
+	ImageURL        string     `json:"image_url"`
+	Quantity        int        `json:"quantity"`
+	Currency        string     `json:"currency"`
+	IntervalUnit    string     `json:"interval_unit"`
+	IntervalCount   int        `json:"interval_count"`
+	PercentOff      int64      `json:"percent_off"`
+	ShippingAddress addressDTO `json:"shipping_address"`
+	PaymentMethodID string     `json:"payment_method_id"`
+	Status          string     `json:"status"`
+	NextRunAt       string     `json:"next_run_at"`
+	LastOrderID     string     `json:"last_order_id,omitempty"`
+	FailedAttempts  int        `json:"failed_attempts"`
+	NextRetryAt     string     `json:"next_retry_at,omitempty"`
+	CancelledAt     string     `json:"cancelled_at,omitempty"`
+	CreatedAt       string     `json:"created_at"`
+	UpdatedAt       string     `json:"updated_at"`
 }
 
 type listResponse struct {
@@ -144,9 +212,25 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 		})
 	}
 
+	var quotes []domain.ShippingQuote
+	for _, quote := range req.ShippingQuotes {
+		quotes = append(quotes, domain.ShippingQuote{
+			SellerID:    quote.SellerID,
+			CarrierCode: quote.CarrierCode,
+			ServiceName: quote.ServiceName,
+			RateCents:   quote.RateCents,
+		})
+	}
+
+	// The buyer's presentment currency, unless the request names one
+	orderCurrency := req.Currency
+	if orderCurrency == "" {
+		orderCurrency = currency.GetCurrency(c)
+	}
+
 	input := usecase.CreateOrderInput{
 		BuyerID:  req.BuyerID,
-		Currency: req.Currency,
+		Currency: orderCurrency,
 		ShippingAddress: domain.Address{
 			FullName:    req.ShippingAddress.FullName,
 			Line1:       req.ShippingAddress.Line1,
@@ -157,7 +241,9 @@ func (h *Handler) CreateOrder(c *gin.Context) {
 			CountryCode: req.ShippingAddress.CountryCode,
 			Phone:       req.ShippingAddress.Phone,
 		},
-		Items: items,
+		Items:          items,
+		ShippingQuotes: quotes,
+		CouponCodes:    req.CouponCodes,
 	}
 
 	order, err := h.createOrder.Execute(c.Request.Context(), input)
@@ -303,6 +389,150 @@ func (h *Handler) UpdateSellerOrderStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": toSellerOrderResponse(sellerOrder)})
 }
 
+// Subscribe handles POST /api/v1/subscriptions
+func (h *Handler) Subscribe(c *gin.Context) {
+	var req subscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The buyer's presentment currency, unless the request names one
+	subCurrency := req.Currency
+	if subCurrency == "" {
+		subCurrency = currency.GetCurrency(c)
+	}
+
+	sub, err := h.subscription.Subscribe(c.Request.Context(), usecase.SubscribeInput{
+		BuyerID:         req.BuyerID,
+		ProductID:       req.ProductID,
+		PlanID:          req.PlanID,
+		Quantity:        req.Quantity,
+		Currency:        subCurrency,
+		ShippingAddress: req.ShippingAddress.toDomain(),
+		PaymentMethodID: req.PaymentMethodID,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": toSubscriptionResponse(sub)})
+}
+
+// ListSubscriptions handles GET /api/v1/subscriptions
+func (h *Handler) ListSubscriptions(c *gin.Context) {
+	buyerID := c.Query("buyer_id")
+	if buyerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "buyer_id query parameter is required"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	subs, total, err := h.subscription.ListSubscriptions(c.Request.Context(), buyerID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := make([]subscriptionResponse, 0, len(subs))
+	for _, sub := range subs {
+		resp = append(resp, toSubscriptionResponse(sub))
+	}
+
+	totalPages := total / int64(pageSize)
+	if total%int64(pageSize) != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, listResponse{
+		Data:       resp,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	})
+}
+
+// GetSubscription handles GET /api/v1/subscriptions/:id
+func (h *Handler) GetSubscription(c *gin.Context) {
+	buyerID := c.Query("buyer_id")
+	if buyerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "buyer_id query parameter is required"})
+		return
+	}
+
+	sub, err := h.subscription.GetSubscription(c.Request.Context(), c.Param("id"), buyerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toSubscriptionResponse(sub)})
+}
+
+// UpdateSubscription handles PATCH /api/v1/subscriptions/:id
+func (h *Handler) UpdateSubscription(c *gin.Context) {
+	var req updateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	input := usecase.UpdateSubscriptionInput{
+		Quantity:        req.Quantity,
+		PaymentMethodID: req.PaymentMethodID,
+	}
+	if req.ShippingAddress != nil {
+		address := req.ShippingAddress.toDomain()
+		input.ShippingAddress = &address
+	}
+
+	sub, err := h.subscription.UpdateSubscription(c.Request.Context(), c.Param("id"), req.BuyerID, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toSubscriptionResponse(sub)})
+}
+
+// PauseSubscription handles POST /api/v1/subscriptions/:id/pause
+func (h *Handler) PauseSubscription(c *gin.Context) {
+	h.changeSubscription(c, h.subscription.PauseSubscription)
+}
+
+// ResumeSubscription handles POST /api/v1/subscriptions/:id/resume
+func (h *Handler) ResumeSubscription(c *gin.Context) {
+	h.changeSubscription(c, h.subscription.ResumeSubscription)
+}
+
+// SkipSubscriptionRenewal handles POST /api/v1/subscriptions/:id/skip
+func (h *Handler) SkipSubscriptionRenewal(c *gin.Context) {
+	h.changeSubscription(c, h.subscription.SkipRenewal)
+}
+
+// CancelSubscription handles POST /api/v1/subscriptions/:id/cancel
+func (h *Handler) CancelSubscription(c *gin.Context) {
+	h.changeSubscription(c, h.subscription.CancelSubscription)
+}
+
+// changeSubscription applies a change to the buyer's subscription named by
+// the path, the buyer given by the buyer_id query parameter.
+func (h *Handler) changeSubscription(c *gin.Context, change func(ctx context.Context, id string, buyerID string) (*domain.Subscription, error)) {
+	buyerID := c.Query("buyer_id")
+	if buyerID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "buyer_id query parameter is required"})
+		return
+	}
+
+	sub, err := change(c.Request.Context(), c.Param("id"), buyerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toSubscriptionResponse(sub)})
+}
+
 // Health handles GET /health
 func (h *Handler) Health(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
@@ -312,16 +542,20 @@ func (h *Handler) Health(c *gin.Context) {
 
 func toOrderResponse(o *domain.Order) orderResponse {
 	resp := orderResponse{
-		ID:            o.ID,
-		OrderNumber:   o.OrderNumber,
-		BuyerID:       o.BuyerID,
-		Status:        string(o.Status),
-		SubtotalCents: o.SubtotalCents,
-		ShippingCents: o.ShippingCents,
-		TaxCents:      o.TaxCents,
-		DiscountCents: o.DiscountCents,
-		TotalCents:    o.TotalCents,
-		Currency:      o.Currency,
+		ID:                   o.ID,
+		OrderNumber:          o.OrderNumber,
+		BuyerID:              o.BuyerID,
+		Status:               string(o.Status),
+		SubtotalCents:        o.SubtotalCents,
+		ShippingCents:        o.ShippingCents,
+		TaxCents:             o.TaxCents,
+		DiscountCents:        o.DiscountCents,
+		TotalCents:           o.TotalCents,
+		Currency:             o.Currency,
+		SettlementCurrency:   o.SettlementCurrency,
+		SettlementTotalCents: o.SettlementTotalCents,
+		ExchangeRate:         o.ExchangeRate,
+		SubscriptionID:       o.SubscriptionID,
 		ShippingAddress: addressDTO{
 			FullName:    o.ShippingAddress.FullName,
 			Line1:       o.ShippingAddress.Line1,
@@ -335,6 +569,9 @@ func toOrderResponse(o *domain.Order) orderResponse {
 		CreatedAt: o.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt: o.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
+	if !o.ExchangeRates.LockedAt.IsZero() {
+		resp.RatesLockedAt = o.ExchangeRates.LockedAt.Format("2006-01-02T15:04:05Z")
+	}
 
 	for _, item := range o.Items {
 		resp.Items = append(resp.Items, orderItemResponse{
@@ -348,20 +585,24 @@ func toOrderResponse(o *domain.Order) orderResponse {
 			Quantity:       item.Quantity,
 			UnitPriceCents: item.UnitPriceCents,
 			TotalCents:     item.TotalCents,
+			DiscountCents:  item.DiscountCents,
 			SellerID:       item.SellerID,
 			ImageURL:       item.ImageURL,
+			IsDigital:      item.IsDigital,
 		})
 	}
 
 	for _, so := range o.SellerOrders {
 		resp.SellerOrders = append(resp.SellerOrders, sellerOrderResponse{
-			ID:            so.ID,
-			OrderID:       so.OrderID,
-			SellerID:      so.SellerID,
-			Status:        string(so.Status),
-			SubtotalCents: so.SubtotalCents,
-			CreatedAt:     so.CreatedAt.Format("2006-01-02T15:04:05Z"),
-			UpdatedAt:     so.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+			ID:                      so.ID,
+			OrderID:                 so.OrderID,
+			SellerID:                so.SellerID,
+			Status:                  string(so.Status),
+			SubtotalCents:           so.SubtotalCents,
+			DiscountCents:           so.DiscountCents,
+			SettlementSubtotalCents: so.SettlementSubtotalCents,
+			CreatedAt:               so.CreatedAt.Format("2006-01-02T15:04:05Z"),
+			UpdatedAt:               so.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 		})
 	}
 
@@ -370,12 +611,75 @@ func toOrderResponse(o *domain.Order) orderResponse {
 
 func toSellerOrderResponse(so *domain.SellerOrder) sellerOrderResponse {
 	return sellerOrderResponse{
-		ID:            so.ID,
-		OrderID:       so.OrderID,
-		SellerID:      so.SellerID,
-		Status:        string(so.Status),
-		SubtotalCents: so.SubtotalCents,
-		CreatedAt:     so.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:     so.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:                      so.ID,
+		OrderID:                 so.OrderID,
+		SellerID:                so.SellerID,
+		Status:                  string(so.Status),
+		SubtotalCents:           so.SubtotalCents,
+		DiscountCents:           so.DiscountCents,
+		SettlementSubtotalCents: so.SettlementSubtotalCents,
+		CreatedAt:               so.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:               so.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+func toSubscriptionResponse(sub *domain.Subscription) subscriptionResponse {
+	resp := subscriptionResponse{
+		ID:              sub.ID,
+		BuyerID:         sub.BuyerID,
+		PlanID:          sub.PlanID,
+		ProductID:       sub.ProductID,
+		VariantID:       sub.VariantID,
+		SellerID:        sub.SellerID,
+		ProductName:     sub.ProductName,
+		VariantName:     sub.VariantName,
+		SKU:             sub.SKU,
+		ImageURL:        sub.ImageURL,
+		Quantity:        sub.Quantity,
+		Currency:        sub.Currency,
+		IntervalUnit:    string(sub.IntervalUnit),
+		IntervalCount:   sub.IntervalCount,
+		PercentOff:      sub.PercentOff,
+		ShippingAddress: toAddressDTO(sub.ShippingAddress),
+		PaymentMethodID: sub.PaymentMethodID,
+		Status:          string(sub.Status),
+		NextRunAt:       sub.NextRunAt.Format("2006-01-02T15:04:05Z"),
+		LastOrderID:     sub.LastOrderID,
+		FailedAttempts:  sub.FailedAttempts,
+		CreatedAt:       sub.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:       sub.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if sub.NextRetryAt != nil {
+		resp.NextRetryAt = sub.NextRetryAt.Format("2006-01-02T15:04:05Z")
+	}
+	if sub.CancelledAt != nil {
+		resp.CancelledAt = sub.CancelledAt.Format("2006-01-02T15:04:05Z")
+	}
+	return resp
+}
+
+func toAddressDTO(a domain.Address) addressDTO {
+	return addressDTO{
+		FullName:    a.FullName,
+		Line1:       a.Line1,
+		Line2:       a.Line2,
+		City:        a.City,
+		State:       a.State,
+		PostalCode:  a.PostalCode,
+		CountryCode: a.CountryCode,
+		Phone:       a.Phone,
+	}
+}
+
+func (a addressDTO) toDomain() domain.Address {
+	return domain.Address{
+		FullName:    a.FullName,
+		Line1:       a.Line1,
+		Line2:       a.Line2,
+		City:        a.City,
+		State:       a.State,
+		PostalCode:  a.PostalCode,
+		CountryCode: a.CountryCode,
+		Phone:       a.Phone,
 	}
 }
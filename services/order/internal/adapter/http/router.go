@@ -2,14 +2,18 @@ package http
 
 import (
 	"github.com/gin-gonic/gin"
+
+	"github.com/southern-martin/ecommerce/pkg/currency"
 )
 
 // NewRouter creates and configures the Gin router with all order service routes.
+// Orders are placed in the buyer's currency selected by the currency middleware.
 func NewRouter(handler *Handler) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(gin.Logger())
+	router.Use(currency.GinMiddleware())
 
 	// Health check
 	router.GET("/health", handler.Health)
@@ -26,6 +30,19 @@ func NewRouter(handler *Handler) *gin.Engine {
 			orders.POST("/:id/cancel", handler.CancelOrder)
 		}
 
+		// Buyer subscription routes
+		subscriptions := v1.Group("/subscriptions")
+		{
+			subscriptions.POST("", handler.Subscribe)
+			subscriptions.GET("", handler.ListSubscriptions)
+			subscriptions.GET("/:id", handler.GetSubscription)
+			subscriptions.PATCH("/:id", handler.UpdateSubscription)
+			subscriptions.POST("/:id/pause", handler.PauseSubscription)
+			subscriptions.POST("/:id/resume", handler.ResumeSubscription)
+			subscriptions.POST("/:id/skip", handler.SkipSubscriptionRenewal)
+			subscriptions.POST("/:id/cancel", handler.CancelSubscription)
+		}
+
 		// Seller order routes
 		seller := v1.Group("/seller")
 		{
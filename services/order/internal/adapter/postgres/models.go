@@ -26,23 +26,50 @@ func (a *AddressJSON) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, a)
 }
 
+// ExchangeRatesJSON is a GORM-compatible JSONB type for ExchangeRates.
+type ExchangeRatesJSON domain.ExchangeRates
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (r ExchangeRatesJSON) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval. Orders
+// placed before rates were locked have none.
+func (r *ExchangeRatesJSON) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan ExchangeRatesJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, r)
+}
+
 // OrderModel is the GORM model for the orders table.
 type OrderModel struct {
-	ID              string      `gorm:"type:uuid;primaryKey"`
-	OrderNumber     string      `gorm:"type:varchar(50);uniqueIndex;not null"`
-	BuyerID         string      `gorm:"type:uuid;index;not null"`
-	Status          string      `gorm:"type:varchar(20);index;not null;default:'pending'"`
-	SubtotalCents   int64       `gorm:"not null;default:0"`
-	ShippingCents   int64       `gorm:"not null;default:0"`
-	TaxCents        int64       `gorm:"not null;default:0"`
-	DiscountCents   int64       `gorm:"not null;default:0"`
-	TotalCents      int64       `gorm:"not null;default:0"`
-	Currency        string      `gorm:"type:varchar(3);not null;default:'USD'"`
-	ShippingAddress AddressJSON `gorm:"type:jsonb"`
-	Items           []OrderItemModel  `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
-	SellerOrders    []SellerOrderModel `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
-	CreatedAt       time.Time   `gorm:"autoCreateTime"`
-	UpdatedAt       time.Time   `gorm:"autoUpdateTime"`
+	ID                   string             `gorm:"type:uuid;primaryKey"`
+	OrderNumber          string             `gorm:"type:varchar(50);uniqueIndex;not null"`
+	BuyerID              string             `gorm:"type:uuid;index;not null"`
+	Status               string             `gorm:"type:varchar(20);index;not null;default:'pending'"`
+	SubtotalCents        int64              `gorm:"not null;default:0"`
+	ShippingCents        int64              `gorm:"not null;default:0"`
+	TaxCents             int64              `gorm:"not null;default:0"`
+	DiscountCents        int64              `gorm:"not null;default:0"`
+	TotalCents           int64              `gorm:"not null;default:0"`
+	Currency             string             `gorm:"type:varchar(3);not null;default:'USD'"`
+	SettlementCurrency   string             `gorm:"type:varchar(3);not null;default:'USD'"`
+	SettlementTotalCents int64              `gorm:"not null;default:0"`
+	ExchangeRate         float64            `gorm:"type:numeric(20,10);not null;default:1"`
+	ExchangeRates        ExchangeRatesJSON  `gorm:"type:jsonb"`
+	ShippingAddress      AddressJSON        `gorm:"type:jsonb"`
+	Items                []OrderItemModel   `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	SellerOrders         []SellerOrderModel `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	SubscriptionID       string             `gorm:"type:varchar(36);index;uniqueIndex:idx_orders_subscription_renewal"`
+	RenewalAt            *time.Time         `gorm:"uniqueIndex:idx_orders_subscription_renewal"`
+	CreatedAt            time.Time          `gorm:"autoCreateTime"`
+	UpdatedAt            time.Time          `gorm:"autoUpdateTime"`
 }
 
 // TableName returns the table name for OrderModel.
@@ -62,8 +89,10 @@ type OrderItemModel struct {
 	Quantity       int    `gorm:"not null;default:1"`
 	UnitPriceCents int64  `gorm:"not null;default:0"`
 	TotalCents     int64  `gorm:"not null;default:0"`
+	DiscountCents  int64  `gorm:"not null;default:0"`
 	SellerID       string `gorm:"type:uuid;index;not null"`
 	ImageURL       string `gorm:"type:text"`
+	IsDigital      bool   `gorm:"not null;default:false"`
 }
 
 // TableName returns the table name for OrderItemModel.
@@ -73,13 +102,15 @@ func (OrderItemModel) TableName() string {
 
 // SellerOrderModel is the GORM model for the seller_orders table.
 type SellerOrderModel struct {
-	ID            string `gorm:"type:uuid;primaryKey"`
-	OrderID       string `gorm:"type:uuid;index;not null"`
-	SellerID      string `gorm:"type:uuid;index;not null"`
-	Status        string `gorm:"type:varchar(20);not null;default:'pending'"`
-	SubtotalCents int64  `gorm:"not null;default:0"`
-	CreatedAt     time.Time `gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+	ID                      string    `gorm:"type:uuid;primaryKey"`
+	OrderID                 string    `gorm:"type:uuid;index;not null"`
+	SellerID                string    `gorm:"type:uuid;index;not null"`
+	Status                  string    `gorm:"type:varchar(20);not null;default:'pending'"`
+	SubtotalCents           int64     `gorm:"not null;default:0"`
+	DiscountCents           int64     `gorm:"not null;default:0"`
+	SettlementSubtotalCents int64     `gorm:"not null;default:0"`
+	CreatedAt               time.Time `gorm:"autoCreateTime"`
+	UpdatedAt               time.Time `gorm:"autoUpdateTime"`
 }
 
 // TableName returns the table name for SellerOrderModel.
@@ -90,19 +121,25 @@ func (SellerOrderModel) TableName() string {
 // ToDomain converts an OrderModel to a domain Order.
 func (m *OrderModel) ToDomain() *domain.Order {
 	order := &domain.Order{
-		ID:              m.ID,
-		OrderNumber:     m.OrderNumber,
-		BuyerID:         m.BuyerID,
-		Status:          domain.OrderStatus(m.Status),
-		SubtotalCents:   m.SubtotalCents,
-		ShippingCents:   m.ShippingCents,
-		TaxCents:        m.TaxCents,
-		DiscountCents:   m.DiscountCents,
-		TotalCents:      m.TotalCents,
-		Currency:        m.Currency,
-		ShippingAddress: domain.Address(m.ShippingAddress),
-		CreatedAt:       m.CreatedAt,
-		UpdatedAt:       m.UpdatedAt,
+		ID:                   m.ID,
+		OrderNumber:          m.OrderNumber,
+		BuyerID:              m.BuyerID,
+		Status:               domain.OrderStatus(m.Status),
+		SubtotalCents:        m.SubtotalCents,
+		ShippingCents:        m.ShippingCents,
+		TaxCents:             m.TaxCents,
+		DiscountCents:        m.DiscountCents,
+		TotalCents:           m.TotalCents,
+		Currency:             m.Currency,
+		SettlementCurrency:   m.SettlementCurrency,
+		SettlementTotalCents: m.SettlementTotalCents,
+		ExchangeRate:         m.ExchangeRate,
+		ExchangeRates:        domain.ExchangeRates(m.ExchangeRates),
+		ShippingAddress:      domain.Address(m.ShippingAddress),
+		SubscriptionID:       m.SubscriptionID,
+		RenewalAt:            m.RenewalAt,
+		CreatedAt:            m.CreatedAt,
+		UpdatedAt:            m.UpdatedAt,
 	}
 
 	for _, item := range m.Items {
@@ -119,19 +156,25 @@ func (m *OrderModel) ToDomain() *domain.Order {
 // ToModel converts a domain Order to an OrderModel.
 func ToOrderModel(o *domain.Order) *OrderModel {
 	model := &OrderModel{
-		ID:              o.ID,
-		OrderNumber:     o.OrderNumber,
-		BuyerID:         o.BuyerID,
-		Status:          string(o.Status),
-		SubtotalCents:   o.SubtotalCents,
-		ShippingCents:   o.ShippingCents,
-		TaxCents:        o.TaxCents,
-		DiscountCents:   o.DiscountCents,
-		TotalCents:      o.TotalCents,
-		Currency:        o.Currency,
-		ShippingAddress: AddressJSON(o.ShippingAddress),
-		CreatedAt:       o.CreatedAt,
-		UpdatedAt:       o.UpdatedAt,
+		ID:                   o.ID,
+		OrderNumber:          o.OrderNumber,
+		BuyerID:              o.BuyerID,
+		Status:               string(o.Status),
+		SubtotalCents:        o.SubtotalCents,
+		ShippingCents:        o.ShippingCents,
+		TaxCents:             o.TaxCents,
+		DiscountCents:        o.DiscountCents,
+		TotalCents:           o.TotalCents,
+		Currency:             o.Currency,
+		SettlementCurrency:   o.SettlementCurrency,
+		SettlementTotalCents: o.SettlementTotalCents,
+		ExchangeRate:         o.ExchangeRate,
+		ExchangeRates:        ExchangeRatesJSON(o.ExchangeRates),
+		ShippingAddress:      AddressJSON(o.ShippingAddress),
+		SubscriptionID:       o.SubscriptionID,
+		RenewalAt:            o.RenewalAt,
+		CreatedAt:            o.CreatedAt,
+		UpdatedAt:            o.UpdatedAt,
 	}
 
 	for _, item := range o.Items {
@@ -158,8 +201,10 @@ func (m *OrderItemModel) ToDomain() domain.OrderItem {
 		Quantity:       m.Quantity,
 		UnitPriceCents: m.UnitPriceCents,
 		TotalCents:     m.TotalCents,
+		DiscountCents:  m.DiscountCents,
 		SellerID:       m.SellerID,
 		ImageURL:       m.ImageURL,
+		IsDigital:      m.IsDigital,
 	}
 }
 
@@ -181,33 +226,138 @@ func ToOrderItemModel(item *domain.OrderItem) OrderItemModel {
 		Quantity:       item.Quantity,
 		UnitPriceCents: item.UnitPriceCents,
 		TotalCents:     item.TotalCents,
+		DiscountCents:  item.DiscountCents,
 		SellerID:       item.SellerID,
 		ImageURL:       item.ImageURL,
+		IsDigital:      item.IsDigital,
 	}
 }
 
 // ToDomain converts a SellerOrderModel to a domain SellerOrder.
 func (m *SellerOrderModel) ToDomain() *domain.SellerOrder {
 	return &domain.SellerOrder{
-		ID:            m.ID,
-		OrderID:       m.OrderID,
-		SellerID:      m.SellerID,
-		Status:        domain.OrderStatus(m.Status),
-		SubtotalCents: m.SubtotalCents,
-		CreatedAt:     m.CreatedAt,
-		UpdatedAt:     m.UpdatedAt,
+		ID:                      m.ID,
+		OrderID:                 m.OrderID,
+		SellerID:                m.SellerID,
+		Status:                  domain.OrderStatus(m.Status),
+		SubtotalCents:           m.SubtotalCents,
+		DiscountCents:           m.DiscountCents,
+		SettlementSubtotalCents: m.SettlementSubtotalCents,
+		CreatedAt:               m.CreatedAt,
+		UpdatedAt:               m.UpdatedAt,
 	}
 }
 
 // ToSellerOrderModel converts a domain SellerOrder to a SellerOrderModel.
 func ToSellerOrderModel(so *domain.SellerOrder) *SellerOrderModel {
 	return &SellerOrderModel{
-		ID:            so.ID,
-		OrderID:       so.OrderID,
-		SellerID:      so.SellerID,
-		Status:        string(so.Status),
-		SubtotalCents: so.SubtotalCents,
-		CreatedAt:     so.CreatedAt,
-		UpdatedAt:     so.UpdatedAt,
+		ID:                      so.ID,
+		OrderID:                 so.OrderID,
+		SellerID:                so.SellerID,
+		Status:                  string(so.Status),
+		SubtotalCents:           so.SubtotalCents,
+		DiscountCents:           so.DiscountCents,
+		SettlementSubtotalCents: so.SettlementSubtotalCents,
+		CreatedAt:               so.CreatedAt,
+		UpdatedAt:               so.UpdatedAt,
+	}
+}
+
+// SubscriptionModel is the GORM model for the subscriptions table.
+type SubscriptionModel struct {
+	ID              string      `gorm:"type:uuid;primaryKey"`
+	BuyerID         string      `gorm:"type:uuid;index;not null"`
+	PlanID          string      `gorm:"type:uuid;not null"`
+	ProductID       string      `gorm:"type:uuid;not null"`
+	VariantID       string      `gorm:"type:uuid;not null"`
+	SellerID        string      `gorm:"type:uuid;not null"`
+	ProductName     string      `gorm:"type:varchar(255);not null"`
+	VariantName     string      `gorm:"type:varchar(255)"`
+	SKU             string      `gorm:"type:varchar(100)"`
+	ImageURL        string      `gorm:"type:text"`
+	Quantity        int         `gorm:"not null;default:1"`
+	Currency        string      `gorm:"type:varchar(3);not null;default:'USD'"`
+	IntervalUnit    string      `gorm:"type:varchar(10);not null"`
+	IntervalCount   int         `gorm:"not null;default:1"`
+	PercentOff      int64       `gorm:"not null;default:0"`
+	ShippingAddress AddressJSON `gorm:"type:jsonb"`
+	PaymentMethodID string      `gorm:"type:varchar(36);not null"`
+	Status          string      `gorm:"type:varchar(20);index;not null;default:'active'"`
+	NextRunAt       time.Time   `gorm:"index;not null"`
+	ReminderSentAt  *time.Time
+	LastOrderID     string `gorm:"type:varchar(36)"`
+	FailedAttempts  int    `gorm:"not null;default:0"`
+	NextRetryAt     *time.Time
+	CancelledAt     *time.Time
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for SubscriptionModel.
+func (SubscriptionModel) TableName() string {
+	return "subscriptions"
+}
+
+// ToDomain converts a SubscriptionModel to a domain Subscription.
+func (m *SubscriptionModel) ToDomain() *domain.Subscription {
+	return &domain.Subscription{
+		ID:              m.ID,
+		BuyerID:         m.BuyerID,
+		PlanID:          m.PlanID,
+		ProductID:       m.ProductID,
+		VariantID:       m.VariantID,
+		SellerID:        m.SellerID,
+		ProductName:     m.ProductName,
+		VariantName:     m.VariantName,
+		SKU:             m.SKU,
+		ImageURL:        m.ImageURL,
+		Quantity:        m.Quantity,
+		Currency:        m.Currency,
+		IntervalUnit:    domain.SubscriptionInterval(m.IntervalUnit),
+		IntervalCount:   m.IntervalCount,
+		PercentOff:      m.PercentOff,
+		ShippingAddress: domain.Address(m.ShippingAddress),
+		PaymentMethodID: m.PaymentMethodID,
+		Status:          domain.SubscriptionStatus(m.Status),
+		NextRunAt:       m.NextRunAt,
+		ReminderSentAt:  m.ReminderSentAt,
+		LastOrderID:     m.LastOrderID,
+		FailedAttempts:  m.FailedAttempts,
+		NextRetryAt:     m.NextRetryAt,
+		CancelledAt:     m.CancelledAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
+
+// ToSubscriptionModel converts a domain Subscription to a SubscriptionModel.
+func ToSubscriptionModel(s *domain.Subscription) *SubscriptionModel {
+	return &SubscriptionModel{
+		ID:              s.ID,
+		BuyerID:         s.BuyerID,
+		PlanID:          s.PlanID,
+		ProductID:       s.ProductID,
+		VariantID:       s.VariantID,
+		SellerID:        s.SellerID,
+		ProductName:     s.ProductName,
+		VariantName:     s.VariantName,
+		SKU:             s.SKU,
+		ImageURL:        s.ImageURL,
+		Quantity:        s.Quantity,
+		Currency:        s.Currency,
+		IntervalUnit:    string(s.IntervalUnit),
+		IntervalCount:   s.IntervalCount,
+		PercentOff:      s.PercentOff,
+		ShippingAddress: AddressJSON(s.ShippingAddress),
+		PaymentMethodID: s.PaymentMethodID,
+		Status:          string(s.Status),
+		NextRunAt:       s.NextRunAt,
+		ReminderSentAt:  s.ReminderSentAt,
+		LastOrderID:     s.LastOrderID,
+		FailedAttempts:  s.FailedAttempts,
+		NextRetryAt:     s.NextRetryAt,
+		CancelledAt:     s.CancelledAt,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
 	}
 }
@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/southern-martin/ecommerce/services/order/internal/domain"
 	"gorm.io/gorm"
@@ -58,6 +59,24 @@ func (r *OrderRepo) GetByOrderNumber(ctx context.Context, orderNumber string) (*
 	return model.ToDomain(), nil
 }
 
+// GetRenewal retrieves the order placed for a subscription's renewal
+// scheduled at renewalAt, or nil if none was placed.
+func (r *OrderRepo) GetRenewal(ctx context.Context, subscriptionID string, renewalAt time.Time) (*domain.Order, error) {
+	var model OrderModel
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Preload("SellerOrders").
+		Where("subscription_id = ? AND renewal_at = ?", subscriptionID, renewalAt).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
 // List retrieves a paginated list of orders based on filter criteria.
 func (r *OrderRepo) List(ctx context.Context, filter domain.OrderFilter) ([]*domain.Order, int64, error) {
 	var models []OrderModel
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/order/internal/domain"
+	"gorm.io/gorm"
+)
+
+// SubscriptionRepo implements domain.SubscriptionRepository using GORM/Postgres.
+type SubscriptionRepo struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionRepo creates a new SubscriptionRepo.
+func NewSubscriptionRepo(db *gorm.DB) *SubscriptionRepo {
+	return &SubscriptionRepo{db: db}
+}
+
+// Create persists a new subscription.
+func (r *SubscriptionRepo) Create(ctx context.Context, sub *domain.Subscription) error {
+	return r.db.WithContext(ctx).Create(ToSubscriptionModel(sub)).Error
+}
+
+// GetByID retrieves a subscription by its UUID.
+func (r *SubscriptionRepo) GetByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	var model SubscriptionModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("subscription not found")
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// Update persists all changes to an existing subscription.
+func (r *SubscriptionRepo) Update(ctx context.Context, sub *domain.Subscription) error {
+	return r.db.WithContext(ctx).Save(ToSubscriptionModel(sub)).Error
+}
+
+// ListByBuyer retrieves a paginated list of a buyer's subscriptions.
+func (r *SubscriptionRepo) ListByBuyer(ctx context.Context, buyerID string, page, pageSize int) ([]*domain.Subscription, int64, error) {
+	var models []SubscriptionModel
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&SubscriptionModel{}).Where("buyer_id = ?", buyerID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	return toSubscriptions(models), total, nil
+}
+
+// ListDueRenewals lists active subscriptions whose renewal is due by before.
+func (r *SubscriptionRepo) ListDueRenewals(ctx context.Context, before time.Time, limit int) ([]*domain.Subscription, error) {
+	var models []SubscriptionModel
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_run_at <= ?", string(domain.SubscriptionStatusActive), before).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toSubscriptions(models), nil
+}
+
+// ListDueReminders lists active subscriptions renewing by before whose buyer
+// has not been reminded yet.
+func (r *SubscriptionRepo) ListDueReminders(ctx context.Context, before time.Time, limit int) ([]*domain.Subscription, error) {
+	var models []SubscriptionModel
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_run_at <= ? AND reminder_sent_at IS NULL", string(domain.SubscriptionStatusActive), before).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toSubscriptions(models), nil
+}
+
+// ListDueRetries lists past due subscriptions whose payment is due to be
+// retried by before.
+func (r *SubscriptionRepo) ListDueRetries(ctx context.Context, before time.Time, limit int) ([]*domain.Subscription, error) {
+	var models []SubscriptionModel
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_retry_at <= ?", string(domain.SubscriptionStatusPastDue), before).
+		Order("next_retry_at ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+	return toSubscriptions(models), nil
+}
+
+func toSubscriptions(models []SubscriptionModel) []*domain.Subscription {
+	subs := make([]*domain.Subscription, len(models))
+	for i := range models {
+		subs[i] = models[i].ToDomain()
+	}
+	return subs
+}
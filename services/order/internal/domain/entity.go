@@ -32,14 +32,31 @@ var AllowedTransitions = map[OrderStatus][]OrderStatus{
 	OrderStatusCompleted:  {OrderStatusRefunded},
 }
 
+// DigitalTransitions are the extra transitions allowed for orders that need
+// no shipping: digital goods are delivered without ever being shipped.
+var DigitalTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusConfirmed:  {OrderStatusDelivered},
+	OrderStatusProcessing: {OrderStatusDelivered},
+}
+
 // CanTransition checks whether a transition from one status to another is allowed.
 func CanTransition(from, to OrderStatus) bool {
-	allowed, ok := AllowedTransitions[from]
-	if !ok {
-		return false
+	return containsStatus(AllowedTransitions[from], to)
+}
+
+// CanTransitionItems checks whether an order or seller order holding items
+// may move from one status to another. Orders of digital items only may
+// skip shipping.
+func CanTransitionItems(from, to OrderStatus, items []OrderItem) bool {
+	if CanTransition(from, to) {
+		return true
 	}
-	for _, s := range allowed {
-		if s == to {
+	return !RequiresShipping(items) && containsStatus(DigitalTransitions[from], to)
+}
+
+func containsStatus(statuses []OrderStatus, status OrderStatus) bool {
+	for _, s := range statuses {
+		if s == status {
 			return true
 		}
 	}
@@ -59,25 +76,47 @@ type Address struct {
 }
 
 // Order represents a buyer's order which may contain items from multiple sellers.
+// Amounts are in Currency, the buyer's presentment currency. The marketplace
+// settles the order in SettlementCurrency at the exchange rates locked when
+// the order was placed.
 type Order struct {
-	ID              string
-	OrderNumber     string
-	BuyerID         string
-	Status          OrderStatus
-	SubtotalCents   int64
-	ShippingCents   int64
-	TaxCents        int64
-	DiscountCents   int64
-	TotalCents      int64
-	Currency        string
-	ShippingAddress Address
-	Items           []OrderItem
-	SellerOrders    []SellerOrder
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID                   string
+	OrderNumber          string
+	BuyerID              string
+	Status               OrderStatus
+	SubtotalCents        int64
+	ShippingCents        int64
+	TaxCents             int64
+	DiscountCents        int64
+	TotalCents           int64
+	Currency             string
+	SettlementCurrency   string
+	SettlementTotalCents int64
+	ExchangeRate         float64 // units of SettlementCurrency per unit of Currency
+	ExchangeRates        ExchangeRates
+	ShippingAddress      Address
+	Items                []OrderItem
+	SellerOrders         []SellerOrder
+	SubscriptionID       string     // set on subscription renewal orders
+	RenewalAt            *time.Time // the scheduled renewal a renewal order was placed for
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// ExchangeRates is the snapshot of exchange rates locked when an order is
+// placed, so that later settlement and refunds convert at the same rates
+// the buyer saw. Rates are relative to Base (1 Base = rate units of
+// currency) as of AsOf.
+type ExchangeRates struct {
+	Base     string             `json:"base"`
+	Rates    map[string]float64 `json:"rates"`
+	AsOf     time.Time          `json:"as_of"`
+	LockedAt time.Time          `json:"locked_at"`
 }
 
-// OrderItem represents a single line item in an order.
+// OrderItem represents a single line item in an order. DiscountCents is
+// the item's share of the order's promotion and coupon discounts;
+// TotalCents is before it.
 type OrderItem struct {
 	ID             string
 	OrderID        string
@@ -89,20 +128,42 @@ type OrderItem struct {
 	Quantity       int
 	UnitPriceCents int64
 	TotalCents     int64
+	DiscountCents  int64
 	SellerID       string
 	ImageURL       string
+	IsDigital      bool
+}
+
+// RequiresShipping reports whether any of the items is a physical good.
+func RequiresShipping(items []OrderItem) bool {
+	for _, item := range items {
+		if !item.IsDigital {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresShipping reports whether the order has anything to ship.
+func (o *Order) RequiresShipping() bool {
+	return RequiresShipping(o.Items)
 }
 
 // SellerOrder groups items by seller for multi-seller marketplace orders.
+// DiscountCents is the part of SubtotalCents taken off by promotions, and
+// SettlementSubtotalCents the seller's share of the order's settlement
+// total.
 type SellerOrder struct {
-	ID            string
-	OrderID       string
-	SellerID      string
-	Status        OrderStatus
-	SubtotalCents int64
-	Items         []OrderItem
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	ID                      string
+	OrderID                 string
+	SellerID                string
+	Status                  OrderStatus
+	SubtotalCents           int64
+	DiscountCents           int64
+	SettlementSubtotalCents int64
+	Items                   []OrderItem
+	CreatedAt               time.Time
+	UpdatedAt               time.Time
 }
 
 // NewOrder creates a new Order with generated ID and order number.
@@ -110,12 +171,9 @@ func NewOrder(buyerID string, currency string, shippingAddress Address, items []
 	now := time.Now()
 	orderID := uuid.New().String()
 
-	var subtotal int64
 	for i := range items {
 		items[i].ID = uuid.New().String()
 		items[i].OrderID = orderID
-		items[i].TotalCents = items[i].UnitPriceCents * int64(items[i].Quantity)
-		subtotal += items[i].TotalCents
 	}
 
 	order := &Order{
@@ -123,22 +181,44 @@ func NewOrder(buyerID string, currency string, shippingAddress Address, items []
 		OrderNumber:     generateOrderNumber(now),
 		BuyerID:         buyerID,
 		Status:          OrderStatusPending,
-		SubtotalCents:   subtotal,
 		ShippingCents:   0,
 		TaxCents:        0,
 		DiscountCents:   0,
-		TotalCents:      subtotal,
 		Currency:        currency,
 		ShippingAddress: shippingAddress,
 		Items:           items,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
+	order.Reprice()
+
+	return order
+}
+
+// Reprice recomputes the item totals, subtotal, discount and total of an
+// order that has not been placed yet, and splits its items by seller
+// again, after item prices or discounts changed.
+func (o *Order) Reprice() {
+	var subtotal, discount int64
+	for i := range o.Items {
+		o.Items[i].TotalCents = o.Items[i].UnitPriceCents * int64(o.Items[i].Quantity)
+		subtotal += o.Items[i].TotalCents
+		discount += o.Items[i].DiscountCents
+	}
+	o.SubtotalCents = subtotal
+	o.DiscountCents = discount
+	o.TotalCents = subtotal + o.ShippingCents + o.TaxCents - o.DiscountCents
 
 	// Split items by seller to create seller orders
-	order.SellerOrders = splitBySeller(order)
+	o.SellerOrders = splitBySeller(o)
+}
 
-	return order
+// AddItem adds an item to an order that has not been placed yet. Reprice
+// the order afterwards.
+func (o *Order) AddItem(item OrderItem) {
+	item.ID = uuid.New().String()
+	item.OrderID = o.ID
+	o.Items = append(o.Items, item)
 }
 
 // generateOrderNumber creates a human-readable order number like "ORD-20240101-ABCD".
@@ -160,9 +240,10 @@ func splitBySeller(order *Order) []SellerOrder {
 
 	var sellerOrders []SellerOrder
 	for sellerID, items := range sellerItemsMap {
-		var subtotal int64
+		var subtotal, discount int64
 		for _, item := range items {
 			subtotal += item.TotalCents
+			discount += item.DiscountCents
 		}
 		sellerOrders = append(sellerOrders, SellerOrder{
 			ID:            uuid.New().String(),
@@ -170,6 +251,7 @@ func splitBySeller(order *Order) []SellerOrder {
 			SellerID:      sellerID,
 			Status:        OrderStatusPending,
 			SubtotalCents: subtotal,
+			DiscountCents: discount,
 			Items:         items,
 			CreatedAt:     order.CreatedAt,
 			UpdatedAt:     order.UpdatedAt,
@@ -177,3 +259,153 @@ func splitBySeller(order *Order) []SellerOrder {
 	}
 	return sellerOrders
 }
+
+// SubscriptionStatus represents the state of a buyer's subscription.
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive SubscriptionStatus = "active"
+	SubscriptionStatusPaused SubscriptionStatus = "paused"
+	// SubscriptionStatusPastDue means the payment of the last renewal
+	// failed and is being retried. No renewals are placed meanwhile.
+	SubscriptionStatusPastDue   SubscriptionStatus = "past_due"
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled"
+)
+
+// SubscriptionInterval is the unit of a subscription's renewal interval.
+type SubscriptionInterval string
+
+const (
+	SubscriptionIntervalDay   SubscriptionInterval = "day"
+	SubscriptionIntervalWeek  SubscriptionInterval = "week"
+	SubscriptionIntervalMonth SubscriptionInterval = "month"
+)
+
+// Subscription is a buyer's repeat order of a product variant ("subscribe
+// & save") on one of the seller's subscription plans. The plan's interval
+// and discount are locked when the buyer subscribes. Every renewal places
+// an order at NextRunAt, priced at the variant's current price in Currency
+// less PercentOff (stored as percentage * 100, e.g. 1000 = 10%), and
+// charges it to the buyer's saved payment method PaymentMethodID.
+type Subscription struct {
+	ID              string
+	BuyerID         string
+	PlanID          string
+	ProductID       string
+	VariantID       string
+	SellerID        string
+	ProductName     string
+	VariantName     string
+	SKU             string
+	ImageURL        string
+	Quantity        int
+	Currency        string
+	IntervalUnit    SubscriptionInterval
+	IntervalCount   int
+	PercentOff      int64
+	ShippingAddress Address
+	PaymentMethodID string
+	Status          SubscriptionStatus
+	NextRunAt       time.Time
+	// ReminderSentAt is set once the buyer has been reminded of the
+	// renewal at NextRunAt.
+	ReminderSentAt *time.Time
+	LastOrderID    string
+	// FailedAttempts counts failed payments of the last renewal; while
+	// past due, the payment is charged again at NextRetryAt.
+	FailedAttempts int
+	NextRetryAt    *time.Time
+	CancelledAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// RenewalAfter returns the first renewal date after t, keeping to the
+// subscription's schedule from its current NextRunAt.
+func (s *Subscription) RenewalAfter(t time.Time) time.Time {
+	next := s.NextRunAt
+	for !next.After(t) {
+		switch s.IntervalUnit {
+		case SubscriptionIntervalDay:
+			next = next.AddDate(0, 0, s.IntervalCount)
+		case SubscriptionIntervalWeek:
+			next = next.AddDate(0, 0, 7*s.IntervalCount)
+		default:
+			next = next.AddDate(0, s.IntervalCount, 0)
+		}
+	}
+	return next
+}
+
+// CatalogProduct is the part of a catalog product orders and subscriptions
+// need.
+type CatalogProduct struct {
+	ID         string
+	SellerID   string
+	CategoryID string
+	Name       string
+	ImageURL   string
+	IsDigital  bool
+}
+
+// PromotionLine is an order item as the promotion service prices it.
+type PromotionLine struct {
+	LineID         string
+	ProductID      string
+	VariantID      string
+	CategoryID     string
+	SellerID       string
+	Quantity       int
+	UnitPriceCents int64
+}
+
+// ShippingQuote is a shipping rate quoted by the shipping service for an
+// order, or for one seller's part of it when SellerID is set.
+type ShippingQuote struct {
+	SellerID    string
+	CarrierCode string
+	ServiceName string
+	RateCents   int64
+}
+
+// PromotionGift is a free item a promotion adds to an order.
+type PromotionGift struct {
+	ProductID string
+	VariantID string
+	SellerID  string
+	Quantity  int
+}
+
+// AppliedBundle is a seller's bundle applied to an order and what it saved.
+type AppliedBundle struct {
+	BundleID      string
+	Name          string
+	SellerID      string
+	DiscountCents int64
+}
+
+// PromotionResult holds the discounts promotions give the lines of an
+// order and, for bundles, the units of each line the bundles are made of,
+// both keyed by line ID, the bundles applied and the free gifts promotions
+// add. For coupons, ShippingQuotes are the order's shipping quotes, in the
+// same order, after free shipping.
+type PromotionResult struct {
+	LineDiscounts  map[string]int64
+	BundledUnits   map[string]int
+	Bundles        []AppliedBundle
+	Gifts          []PromotionGift
+	ShippingQuotes []ShippingQuote
+}
+
+// SubscriptionPlan is a seller's offer of a variant on repeat delivery, as
+// published in the product catalog.
+type SubscriptionPlan struct {
+	ID            string
+	ProductID     string
+	VariantID     string
+	VariantName   string
+	SKU           string
+	IntervalUnit  SubscriptionInterval
+	IntervalCount int
+	PercentOff    int64
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // EventPublisher defines the interface for publishing domain events.
 type EventPublisher interface {
@@ -9,31 +12,75 @@ type EventPublisher interface {
 
 // Event subjects for order domain events.
 const (
-	EventOrderCreated   = "order.created"
-	EventOrderConfirmed = "order.confirmed"
-	EventOrderCancelled = "order.cancelled"
-	EventOrderShipped   = "order.shipped"
-	EventOrderDelivered = "order.delivered"
-	EventOrderCompleted = "order.completed"
+	EventOrderCreated         = "order.created"
+	EventOrderConfirmed       = "order.confirmed"
+	EventOrderCancelled       = "order.cancelled"
+	EventOrderShipped         = "order.shipped"
+	EventOrderDelivered       = "order.delivered"
+	EventOrderCompleted       = "order.completed"
+	EventSellerOrderCancelled = "order.seller_order.cancelled"
 )
 
-// OrderCreatedEvent is the payload published when an order is created.
+// Event subjects for subscription domain events.
+const (
+	EventSubscriptionRenewalUpcoming = "subscription.renewal.upcoming"
+	EventSubscriptionPaymentFailed   = "subscription.payment.failed"
+	EventSubscriptionPaymentRetry    = "subscription.payment.retry"
+	EventSubscriptionCancelled       = "subscription.cancelled"
+)
+
+// OrderCreatedEvent is the payload published when an order is created. It
+// carries the order's locked exchange rates for settlement, each seller
+// order's share of the settlement total and the bundles applied, whose
+// savings are in the settlement currency. Subscription renewals carry the
+// saved payment method the payment service charges.
 type OrderCreatedEvent struct {
-	OrderID     string      `json:"order_id"`
-	OrderNumber string      `json:"order_number"`
-	BuyerID     string      `json:"buyer_id"`
-	TotalCents  int64       `json:"total_cents"`
-	Currency    string      `json:"currency"`
-	Items       []ItemEvent `json:"items"`
+	OrderID              string             `json:"order_id"`
+	OrderNumber          string             `json:"order_number"`
+	BuyerID              string             `json:"buyer_id"`
+	TotalCents           int64              `json:"total_cents"`
+	Currency             string             `json:"currency"`
+	SettlementCurrency   string             `json:"settlement_currency"`
+	SettlementTotalCents int64              `json:"settlement_total_cents"`
+	ExchangeRates        ExchangeRates      `json:"exchange_rates"`
+	RequiresShipping     bool               `json:"requires_shipping"`
+	Items                []ItemEvent        `json:"items"`
+	SellerOrders         []SellerOrderEvent `json:"seller_orders"`
+	Bundles              []BundleEvent      `json:"bundles,omitempty"`
+	SubscriptionID       string             `json:"subscription_id,omitempty"`
+	PaymentMethodID      string             `json:"payment_method_id,omitempty"`
 }
 
-// ItemEvent represents an order item in an event payload.
+// ItemEvent represents an order item in an event payload. A flash sale
+// item's FlashSaleDiscountCents is what the sale took off its list price,
+// in the settlement currency.
 type ItemEvent struct {
-	ProductID      string `json:"product_id"`
-	VariantID      string `json:"variant_id"`
-	Quantity       int    `json:"quantity"`
-	UnitPriceCents int64  `json:"unit_price_cents"`
-	SellerID       string `json:"seller_id"`
+	ItemID                 string `json:"item_id"`
+	ProductID              string `json:"product_id"`
+	VariantID              string `json:"variant_id"`
+	Quantity               int    `json:"quantity"`
+	UnitPriceCents         int64  `json:"unit_price_cents"`
+	DiscountCents          int64  `json:"discount_cents"`
+	FlashSaleDiscountCents int64  `json:"flash_sale_discount_cents,omitempty"`
+	SellerID               string `json:"seller_id"`
+	IsDigital              bool   `json:"is_digital"`
+}
+
+// SellerOrderEvent represents a seller order in an order.created payload.
+type SellerOrderEvent struct {
+	SellerOrderID           string `json:"seller_order_id"`
+	SellerID                string `json:"seller_id"`
+	SubtotalCents           int64  `json:"subtotal_cents"`
+	DiscountCents           int64  `json:"discount_cents"`
+	SettlementSubtotalCents int64  `json:"settlement_subtotal_cents"`
+}
+
+// BundleEvent is a bundle applied to an order, in an order.created payload.
+type BundleEvent struct {
+	BundleID      string `json:"bundle_id"`
+	Name          string `json:"name"`
+	SellerID      string `json:"seller_id"`
+	DiscountCents int64  `json:"discount_cents"`
 }
 
 // OrderStatusEvent is the payload published when an order status changes.
@@ -43,3 +90,38 @@ type OrderStatusEvent struct {
 	BuyerID     string      `json:"buyer_id"`
 	Status      OrderStatus `json:"status"`
 }
+
+// SellerOrderCancelledEvent is the payload published when one seller's
+// part of an order is cancelled while the rest of the order goes ahead.
+// Items are the seller order's items.
+type SellerOrderCancelledEvent struct {
+	OrderID       string      `json:"order_id"`
+	OrderNumber   string      `json:"order_number"`
+	BuyerID       string      `json:"buyer_id"`
+	SellerOrderID string      `json:"seller_order_id"`
+	SellerID      string      `json:"seller_id"`
+	Items         []ItemEvent `json:"items"`
+}
+
+// SubscriptionEvent is the payload published as a subscription renews,
+// fails to be paid for or is cancelled, for notifying the buyer.
+type SubscriptionEvent struct {
+	SubscriptionID string     `json:"subscription_id"`
+	BuyerID        string     `json:"buyer_id"`
+	ProductID      string     `json:"product_id"`
+	ProductName    string     `json:"product_name"`
+	Quantity       int        `json:"quantity"`
+	Status         string     `json:"status"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
+	OrderID        string     `json:"order_id,omitempty"`
+	FailedAttempts int        `json:"failed_attempts,omitempty"`
+	NextRetryAt    *time.Time `json:"next_retry_at,omitempty"`
+}
+
+// PaymentRetryEvent is the payload published to charge the failed payment
+// of a renewal order again, to PaymentMethodID.
+type PaymentRetryEvent struct {
+	OrderID         string `json:"order_id"`
+	SubscriptionID  string `json:"subscription_id"`
+	PaymentMethodID string `json:"payment_method_id"`
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // OrderFilter provides filtering and pagination for order queries.
 type OrderFilter struct {
@@ -19,6 +22,10 @@ type OrderRepository interface {
 	List(ctx context.Context, filter OrderFilter) ([]*Order, int64, error)
 	UpdateStatus(ctx context.Context, id string, status OrderStatus) error
 	Update(ctx context.Context, order *Order) error
+	// GetRenewal returns the order placed for a subscription's renewal
+	// scheduled at renewalAt, or nil if none was placed. There is at most
+	// one.
+	GetRenewal(ctx context.Context, subscriptionID string, renewalAt time.Time) (*Order, error)
 }
 
 // SellerOrderRepository defines the interface for seller order persistence.
@@ -29,3 +36,53 @@ type SellerOrderRepository interface {
 	ListBySeller(ctx context.Context, sellerID string, page, pageSize int) ([]*SellerOrder, int64, error)
 	UpdateStatus(ctx context.Context, id string, status OrderStatus) error
 }
+
+// SubscriptionRepository defines the interface for subscription persistence.
+type SubscriptionRepository interface {
+	Create(ctx context.Context, sub *Subscription) error
+	GetByID(ctx context.Context, id string) (*Subscription, error)
+	Update(ctx context.Context, sub *Subscription) error
+	ListByBuyer(ctx context.Context, buyerID string, page, pageSize int) ([]*Subscription, int64, error)
+	// ListDueRenewals lists active subscriptions whose renewal is due by
+	// before.
+	ListDueRenewals(ctx context.Context, before time.Time, limit int) ([]*Subscription, error)
+	// ListDueReminders lists active subscriptions renewing by before whose
+	// buyer has not been reminded yet.
+	ListDueReminders(ctx context.Context, before time.Time, limit int) ([]*Subscription, error)
+	// ListDueRetries lists past due subscriptions whose payment is due to
+	// be retried by before.
+	ListDueRetries(ctx context.Context, before time.Time, limit int) ([]*Subscription, error)
+}
+
+// PromotionProvider prices order items with the promotions run by the
+// promotion service. Its amounts are in the marketplace's settlement
+// currency.
+type PromotionProvider interface {
+	// ClaimFlashSale claims quantity units of the flash sale running on a
+	// product variant for an order and returns the unit sale price. ok is
+	// false when no flash sale is running on the variant.
+	ClaimFlashSale(ctx context.Context, orderID, buyerID, productID, variantID string, quantity int) (salePriceCents int64, ok bool, err error)
+	// ReleaseFlashSales returns the flash sale units claimed by an order.
+	ReleaseFlashSales(ctx context.Context, orderID string) error
+	// ApplyBundles applies the sellers' active bundles completed by the
+	// lines.
+	ApplyBundles(ctx context.Context, lines []PromotionLine) (*PromotionResult, error)
+	// EvaluatePromotions applies the automatic promotions running to the
+	// lines bought by buyerID.
+	EvaluatePromotions(ctx context.Context, buyerID string, lines []PromotionLine) (*PromotionResult, error)
+	// ReserveCoupons applies the coupons buyerID entered to the lines and
+	// shipping quotes and reserves them for an order until it is confirmed
+	// or cancelled. It fails if any of the coupons is rejected.
+	ReserveCoupons(ctx context.Context, orderID, buyerID string, codes []string, lines []PromotionLine, quotes []ShippingQuote) (*PromotionResult, error)
+	// ReleaseCoupons returns the coupons reserved for an order.
+	ReleaseCoupons(ctx context.Context, orderID string) error
+}
+
+// CatalogProvider looks up products, subscription plans and current
+// prices in the product catalog.
+type CatalogProvider interface {
+	GetProduct(ctx context.Context, productID string) (*CatalogProduct, error)
+	GetSubscriptionPlan(ctx context.Context, productID, planID string) (*SubscriptionPlan, error)
+	// GetVariantPrice returns the variant's current price in currency.
+	GetVariantPrice(ctx context.Context, productID, variantID, currency string) (int64, error)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 // Config holds all configuration for the order service.
@@ -12,6 +13,14 @@ type Config struct {
 	Postgres PostgresConfig
 	NATS     NATSConfig
 	LogLevel string
+	Currency CurrencyConfig
+	// ProductServiceURL is the base URL of the product service, which
+	// ordered products, subscription plans and renewal prices are read from.
+	ProductServiceURL string
+	// PromotionGRPCAddr is the address of the promotion service's gRPC API,
+	// which orders are priced with.
+	PromotionGRPCAddr string
+	Subscription      SubscriptionConfig
 }
 
 // PostgresConfig holds Postgres connection configuration.
@@ -28,6 +37,24 @@ type NATSConfig struct {
 	URL string
 }
 
+// CurrencyConfig holds the marketplace's settlement currency and where
+// exchange rates are loaded from.
+type CurrencyConfig struct {
+	// SettlementCurrency is the currency orders are settled in.
+	SettlementCurrency string
+	// RatesSource is a JSON rates file path or URL; when empty the
+	// converter's built-in rates are used.
+	RatesSource         string
+	RatesRefreshMinutes int
+}
+
+// SubscriptionConfig holds the schedule of subscription renewals.
+type SubscriptionConfig struct {
+	// ReminderHours is how long before a renewal the buyer is reminded.
+	ReminderHours         int
+	SchedulerIntervalSecs int
+}
+
 // DSN returns the Postgres connection string.
 func (c PostgresConfig) DSN() string {
 	return fmt.Sprintf(
@@ -52,6 +79,17 @@ func Load() *Config {
 		NATS: NATSConfig{
 			URL: getEnv("NATS_URL", "nats://localhost:4222"),
 		},
+		Currency: CurrencyConfig{
+			SettlementCurrency:  getEnv("SETTLEMENT_CURRENCY", "USD"),
+			RatesSource:         getEnv("EXCHANGE_RATES_SOURCE", ""),
+			RatesRefreshMinutes: getEnvInt("EXCHANGE_RATES_REFRESH_MINUTES", 60),
+		},
+		ProductServiceURL: getEnv("PRODUCT_SERVICE_URL", "http://localhost:8081"),
+		PromotionGRPCAddr: getEnv("PROMOTION_GRPC_ADDR", "localhost:9093"),
+		Subscription: SubscriptionConfig{
+			ReminderHours:         getEnvInt("SUBSCRIPTION_REMINDER_HOURS", 72),
+			SchedulerIntervalSecs: getEnvInt("SUBSCRIPTION_SCHEDULER_INTERVAL_SECS", 60),
+		},
 	}
 }
 
@@ -61,3 +99,12 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
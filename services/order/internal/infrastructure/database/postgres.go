@@ -25,6 +25,7 @@ func NewPostgresDB(cfg config.PostgresConfig) (*gorm.DB, error) {
 		&postgres.OrderModel{},
 		&postgres.OrderItemModel{},
 		&postgres.SellerOrderModel{},
+		&postgres.SubscriptionModel{},
 	)
 	if err != nil {
 		return nil, err
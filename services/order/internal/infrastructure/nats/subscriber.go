@@ -0,0 +1,52 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/order/internal/usecase"
+)
+
+// Payment subjects subscription renewals listen to.
+const (
+	subjectPaymentCompleted = "payment.completed"
+	subjectPaymentFailed    = "payment.failed"
+)
+
+// paymentEvent holds the fields of a payment payload used for subscription
+// dunning.
+type paymentEvent struct {
+	OrderID string `json:"order_id"`
+	Status  string `json:"status"`
+}
+
+// StartPaymentSubscriber follows the payments of subscription renewal
+// orders: failed payments put the subscription past due and schedule a
+// retry, and a completed payment brings it back to active.
+func StartPaymentSubscriber(p *Publisher, subscriptionUC *usecase.SubscriptionUseCase) error {
+	handlers := map[string]func(context.Context, string) error{
+		subjectPaymentCompleted: subscriptionUC.HandlePaymentCompleted,
+		subjectPaymentFailed:    subscriptionUC.HandlePaymentFailed,
+	}
+	for subject, handle := range handlers {
+		if _, err := p.Subscribe(subject, func(data []byte) {
+			var event paymentEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				log.Error().Err(err).Str("subject", subject).Msg("failed to decode payment event")
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := handle(ctx, event.OrderID); err != nil {
+				log.Error().Err(err).Str("subject", subject).Str("order_id", event.OrderID).Msg("failed to handle renewal payment")
+			}
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/order/internal/domain"
+)
+
+// Client implements domain.CatalogProvider on top of the product service's
+// HTTP API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new product service client.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// productResponse is the part of the body of GET /api/v1/products/:id used
+// here.
+type productResponse struct {
+	ID         string   `json:"id"`
+	SellerID   string   `json:"seller_id"`
+	CategoryID string   `json:"category_id"`
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	ImageURLs  []string `json:"image_urls"`
+}
+
+// planResponse is the body of
+// GET /api/v1/products/:id/subscription-plans/:planId.
+type planResponse struct {
+	ID            string `json:"id"`
+	ProductID     string `json:"product_id"`
+	VariantID     string `json:"variant_id"`
+	VariantName   string `json:"variant_name"`
+	SKU           string `json:"sku"`
+	IntervalUnit  string `json:"interval_unit"`
+	IntervalCount int    `json:"interval_count"`
+	PercentOff    int64  `json:"percent_off"`
+}
+
+// priceResponse is the part of the body of
+// GET /api/v1/products/:id/variants/:variantId/price used here.
+type priceResponse struct {
+	PresentmentCurrency   string `json:"presentment_currency"`
+	PresentmentPriceCents int64  `json:"presentment_price_cents"`
+}
+
+// GetProduct fetches a product's seller, category, name, image and type.
+func (c *Client) GetProduct(ctx context.Context, productID string) (*domain.CatalogProduct, error) {
+	var body productResponse
+	endpoint := fmt.Sprintf("%s/api/v1/products/%s", c.baseURL, url.PathEscape(productID))
+	if err := c.get(ctx, endpoint, "", &body); err != nil {
+		return nil, fmt.Errorf("failed to fetch product: %w", err)
+	}
+
+	product := &domain.CatalogProduct{
+		ID:         body.ID,
+		SellerID:   body.SellerID,
+		CategoryID: body.CategoryID,
+		Name:       body.Name,
+		IsDigital:  body.Type == "digital",
+	}
+	if len(body.ImageURLs) > 0 {
+		product.ImageURL = body.ImageURLs[0]
+	}
+	return product, nil
+}
+
+// GetSubscriptionPlan fetches an active subscription plan of a product.
+func (c *Client) GetSubscriptionPlan(ctx context.Context, productID, planID string) (*domain.SubscriptionPlan, error) {
+	var body planResponse
+	endpoint := fmt.Sprintf("%s/api/v1/products/%s/subscription-plans/%s",
+		c.baseURL, url.PathEscape(productID), url.PathEscape(planID))
+	if err := c.get(ctx, endpoint, "", &body); err != nil {
+		return nil, fmt.Errorf("failed to fetch subscription plan: %w", err)
+	}
+
+	return &domain.SubscriptionPlan{
+		ID:            body.ID,
+		ProductID:     body.ProductID,
+		VariantID:     body.VariantID,
+		VariantName:   body.VariantName,
+		SKU:           body.SKU,
+		IntervalUnit:  domain.SubscriptionInterval(body.IntervalUnit),
+		IntervalCount: body.IntervalCount,
+		PercentOff:    body.PercentOff,
+	}, nil
+}
+
+// GetVariantPrice fetches a variant's current price in currency, as the
+// product service presents it to buyers shopping in that currency.
+func (c *Client) GetVariantPrice(ctx context.Context, productID, variantID, currency string) (int64, error) {
+	var body priceResponse
+	endpoint := fmt.Sprintf("%s/api/v1/products/%s/variants/%s/price",
+		c.baseURL, url.PathEscape(productID), url.PathEscape(variantID))
+	if err := c.get(ctx, endpoint, currency, &body); err != nil {
+		return 0, fmt.Errorf("failed to fetch variant price: %w", err)
+	}
+	if body.PresentmentPriceCents <= 0 {
+		return 0, fmt.Errorf("variant has no price in %s", currency)
+	}
+	return body.PresentmentPriceCents, nil
+}
+
+// get fetches endpoint, asking for prices in currency when set, and
+// decodes the JSON body into out.
+func (c *Client) get(ctx context.Context, endpoint, currency string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if currency != "" {
+		req.Header.Set("X-Currency", currency)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("product service returned %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,370 @@
+package promotion
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/southern-martin/ecommerce/pkg/grpcjson"
+	"github.com/southern-martin/ecommerce/services/order/internal/domain"
+)
+
+// Client implements domain.PromotionProvider on top of the promotion
+// service's gRPC API.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient creates a client of the promotion service's gRPC API at addr.
+// The connection is established lazily, on the first call.
+func NewClient(addr string) (*Client, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpcjson.CallOption()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to promotion service at %s: %w", addr, err)
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the gRPC connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// --- Messages of the promotion service's gRPC API ---
+
+type getFlashSalePriceRequest struct {
+	ProductID string
+	VariantID string
+}
+
+type getFlashSalePriceResponse struct {
+	HasFlashSale    bool
+	SalePriceCents  int64
+	FlashSaleItemID string
+}
+
+type claimFlashSaleItemRequest struct {
+	FlashSaleItemID string
+	BuyerID         string
+	OrderID         string
+	Quantity        int32
+}
+
+type claimFlashSaleItemResponse struct {
+	ClaimID        string
+	SalePriceCents int64
+}
+
+type releaseFlashSaleClaimsRequest struct {
+	OrderID string
+}
+
+type releaseFlashSaleClaimsResponse struct {
+	ReleasedCount int32
+}
+
+type orderLine struct {
+	LineID         string
+	ProductID      string
+	VariantID      string
+	CategoryID     string
+	SellerID       string
+	Quantity       int32
+	UnitPriceCents int64
+}
+
+type giftItem struct {
+	PromotionID string
+	ProductID   string
+	VariantID   string
+	SellerID    string
+	Quantity    int32
+}
+
+type lineExplanation struct {
+	LineID        string
+	DiscountCents int64
+}
+
+type bundleAllocation struct {
+	LineID        string
+	Quantity      int32
+	DiscountCents int64
+}
+
+type appliedBundle struct {
+	BundleID      string
+	Name          string
+	SellerID      string
+	DiscountCents int64
+	Allocations   []bundleAllocation
+}
+
+type applyBundlesRequest struct {
+	Lines []orderLine
+}
+
+type applyBundlesResponse struct {
+	Applied            []appliedBundle
+	TotalDiscountCents int64
+}
+
+type evaluatePromotionsRequest struct {
+	UserID string
+	Lines  []orderLine
+}
+
+type evaluatePromotionsResponse struct {
+	Lines              []lineExplanation
+	Gifts              []giftItem
+	TotalDiscountCents int64
+}
+
+type shippingQuote struct {
+	SellerID    string
+	CarrierCode string
+	ServiceName string
+	RateCents   int64
+}
+
+type lineDiscount struct {
+	LineID        string
+	DiscountCents int64
+}
+
+type appliedCoupon struct {
+	Allocations []lineDiscount
+}
+
+type rejectedCoupon struct {
+	Code   string
+	Reason string
+}
+
+type applyCouponsRequest struct {
+	Codes          []string
+	UserID         string
+	Lines          []orderLine
+	ShippingQuotes []shippingQuote
+}
+
+type applyCouponsResponse struct {
+	Applied                    []appliedCoupon
+	Rejected                   []rejectedCoupon
+	TotalDiscountCents         int64
+	TotalShippingDiscountCents int64
+	ShippingQuotes             []shippingQuote
+}
+
+type reserveCouponsRequest struct {
+	Codes          []string
+	UserID         string
+	OrderID        string
+	Lines          []orderLine
+	ShippingQuotes []shippingQuote
+}
+
+type couponUsage struct {
+	DiscountCents int64
+}
+
+type reserveCouponsResponse struct {
+	Usages []couponUsage
+}
+
+type releaseOrderCouponsRequest struct {
+	OrderID string
+}
+
+type releaseOrderCouponsResponse struct {
+	ReleasedCount int32
+}
+
+// ClaimFlashSale claims units of the flash sale running on a variant, if
+// any, for an order.
+func (c *Client) ClaimFlashSale(ctx context.Context, orderID, buyerID, productID, variantID string, quantity int) (int64, bool, error) {
+	var price getFlashSalePriceResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/GetFlashSalePrice",
+		&getFlashSalePriceRequest{ProductID: productID, VariantID: variantID}, &price); err != nil {
+		return 0, false, fmt.Errorf("failed to look up flash sale: %w", err)
+	}
+	if !price.HasFlashSale {
+		return 0, false, nil
+	}
+
+	var claim claimFlashSaleItemResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/ClaimFlashSaleItem", &claimFlashSaleItemRequest{
+		FlashSaleItemID: price.FlashSaleItemID,
+		BuyerID:         buyerID,
+		OrderID:         orderID,
+		Quantity:        int32(quantity),
+	}, &claim); err != nil {
+		return 0, false, fmt.Errorf("failed to claim flash sale item: %w", err)
+	}
+	return claim.SalePriceCents, true, nil
+}
+
+// ReleaseFlashSales returns the flash sale units claimed by an order.
+func (c *Client) ReleaseFlashSales(ctx context.Context, orderID string) error {
+	var resp releaseFlashSaleClaimsResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/ReleaseFlashSaleClaims",
+		&releaseFlashSaleClaimsRequest{OrderID: orderID}, &resp); err != nil {
+		return fmt.Errorf("failed to release flash sale claims: %w", err)
+	}
+	return nil
+}
+
+// ApplyBundles applies the sellers' active bundles completed by the lines.
+func (c *Client) ApplyBundles(ctx context.Context, lines []domain.PromotionLine) (*domain.PromotionResult, error) {
+	var resp applyBundlesResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/ApplyBundles",
+		&applyBundlesRequest{Lines: toOrderLines(lines)}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to apply bundles: %w", err)
+	}
+
+	result := &domain.PromotionResult{
+		LineDiscounts: make(map[string]int64),
+		BundledUnits:  make(map[string]int),
+	}
+	for _, bundle := range resp.Applied {
+		result.Bundles = append(result.Bundles, domain.AppliedBundle{
+			BundleID:      bundle.BundleID,
+			Name:          bundle.Name,
+			SellerID:      bundle.SellerID,
+			DiscountCents: bundle.DiscountCents,
+		})
+		for _, allocation := range bundle.Allocations {
+			result.LineDiscounts[allocation.LineID] += allocation.DiscountCents
+			result.BundledUnits[allocation.LineID] += int(allocation.Quantity)
+		}
+	}
+	return result, nil
+}
+
+// EvaluatePromotions applies the automatic promotions running to the
+// lines bought by buyerID.
+func (c *Client) EvaluatePromotions(ctx context.Context, buyerID string, lines []domain.PromotionLine) (*domain.PromotionResult, error) {
+	var resp evaluatePromotionsResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/EvaluatePromotions", &evaluatePromotionsRequest{
+		UserID: buyerID,
+		Lines:  toOrderLines(lines),
+	}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to evaluate promotions: %w", err)
+	}
+
+	result := &domain.PromotionResult{LineDiscounts: make(map[string]int64, len(resp.Lines))}
+	for _, line := range resp.Lines {
+		if line.DiscountCents > 0 {
+			result.LineDiscounts[line.LineID] += line.DiscountCents
+		}
+	}
+	for _, gift := range resp.Gifts {
+		result.Gifts = append(result.Gifts, domain.PromotionGift{
+			ProductID: gift.ProductID,
+			VariantID: gift.VariantID,
+			SellerID:  gift.SellerID,
+			Quantity:  int(gift.Quantity),
+		})
+	}
+	return result, nil
+}
+
+// ReserveCoupons applies the coupons buyerID entered to the lines and
+// shipping quotes and reserves them for an order until it is confirmed or
+// cancelled.
+func (c *Client) ReserveCoupons(ctx context.Context, orderID, buyerID string, codes []string, lines []domain.PromotionLine, quotes []domain.ShippingQuote) (*domain.PromotionResult, error) {
+	var applied applyCouponsResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/ApplyCoupons", &applyCouponsRequest{
+		Codes:          codes,
+		UserID:         buyerID,
+		Lines:          toOrderLines(lines),
+		ShippingQuotes: toShippingQuotes(quotes),
+	}, &applied); err != nil {
+		return nil, fmt.Errorf("failed to apply coupons: %w", err)
+	}
+	if len(applied.Rejected) > 0 {
+		rejected := applied.Rejected[0]
+		return nil, fmt.Errorf("coupon %s rejected: %s", rejected.Code, rejected.Reason)
+	}
+
+	// Reserving applies the coupons again, atomically with their usage
+	// limits, so it only reports the discount each coupon gives. The lines'
+	// shares come from the evaluation above, as long as both agree.
+	var reserved reserveCouponsResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/ReserveCoupons", &reserveCouponsRequest{
+		Codes:          codes,
+		UserID:         buyerID,
+		OrderID:        orderID,
+		Lines:          toOrderLines(lines),
+		ShippingQuotes: toShippingQuotes(quotes),
+	}, &reserved); err != nil {
+		return nil, fmt.Errorf("failed to reserve coupons: %w", err)
+	}
+	var reservedCents int64
+	for _, usage := range reserved.Usages {
+		reservedCents += usage.DiscountCents
+	}
+	if reservedCents != applied.TotalDiscountCents+applied.TotalShippingDiscountCents {
+		return nil, fmt.Errorf("coupon discounts changed while they were reserved")
+	}
+
+	result := &domain.PromotionResult{LineDiscounts: make(map[string]int64, len(lines))}
+	for _, coupon := range applied.Applied {
+		for _, allocation := range coupon.Allocations {
+			result.LineDiscounts[allocation.LineID] += allocation.DiscountCents
+		}
+	}
+	for _, quote := range applied.ShippingQuotes {
+		result.ShippingQuotes = append(result.ShippingQuotes, domain.ShippingQuote{
+			SellerID:    quote.SellerID,
+			CarrierCode: quote.CarrierCode,
+			ServiceName: quote.ServiceName,
+			RateCents:   quote.RateCents,
+		})
+	}
+	return result, nil
+}
+
+// ReleaseCoupons returns the coupons reserved for an order.
+func (c *Client) ReleaseCoupons(ctx context.Context, orderID string) error {
+	var resp releaseOrderCouponsResponse
+	if err := c.conn.Invoke(ctx, "/promotion.PromotionService/ReleaseOrderCoupons",
+		&releaseOrderCouponsRequest{OrderID: orderID}, &resp); err != nil {
+		return fmt.Errorf("failed to release coupons: %w", err)
+	}
+	return nil
+}
+
+func toOrderLines(lines []domain.PromotionLine) []orderLine {
+	out := make([]orderLine, len(lines))
+	for i, line := range lines {
+		out[i] = orderLine{
+			LineID:         line.LineID,
+			ProductID:      line.ProductID,
+			VariantID:      line.VariantID,
+			CategoryID:     line.CategoryID,
+			SellerID:       line.SellerID,
+			Quantity:       int32(line.Quantity),
+			UnitPriceCents: line.UnitPriceCents,
+		}
+	}
+	return out
+}
+
+func toShippingQuotes(quotes []domain.ShippingQuote) []shippingQuote {
+	out := make([]shippingQuote, len(quotes))
+	for i, quote := range quotes {
+		out[i] = shippingQuote{
+			SellerID:    quote.SellerID,
+			CarrierCode: quote.CarrierCode,
+			ServiceName: quote.ServiceName,
+			RateCents:   quote.RateCents,
+		}
+	}
+	return out
+}
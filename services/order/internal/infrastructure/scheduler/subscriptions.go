@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/order/internal/usecase"
+)
+
+// subscriptionRunTimeout bounds the time spent on one run of due
+// subscription work.
+const subscriptionRunTimeout = 5 * time.Minute
+
+// StartSubscriptionScheduler reminds buyers of upcoming renewals, places
+// due renewal orders and retries failed renewal payments every interval
+// until ctx is cancelled.
+func StartSubscriptionScheduler(ctx context.Context, subscriptionUC *usecase.SubscriptionUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runCtx, cancel := context.WithTimeout(ctx, subscriptionRunTimeout)
+			subscriptionUC.RunDue(runCtx, time.Now())
+			cancel()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -3,19 +3,35 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/pkg/currency"
+	"github.com/southern-martin/ecommerce/pkg/money"
 	"github.com/southern-martin/ecommerce/services/order/internal/domain"
 )
 
-// CreateOrderInput represents the input for creating a new order.
+// CreateOrderInput represents the input for creating a new order. Item
+// prices are in Currency, the buyer's presentment currency, as are the
+// ShippingQuotes the buyer chose at checkout. CouponCodes are the coupons
+// the buyer entered. Subscription renewals set SubscriptionID, the
+// scheduled RenewalAt and the saved PaymentMethodID to charge.
 type CreateOrderInput struct {
 	BuyerID         string
 	Currency        string
 	ShippingAddress domain.Address
 	Items           []CreateOrderItemInput
+	ShippingQuotes  []domain.ShippingQuote
+	CouponCodes     []string
+	SubscriptionID  string
+	RenewalAt       *time.Time
+	PaymentMethodID string
 }
 
-// CreateOrderItemInput represents a single item in the order creation request.
+// CreateOrderItemInput represents a single item in the order creation
+// request. Whether an item is digital is read from the product catalog.
 type CreateOrderItemInput struct {
 	ProductID      string
 	VariantID      string
@@ -28,23 +44,42 @@ type CreateOrderItemInput struct {
 	ImageURL       string
 }
 
+// releaseTimeout bounds releasing the promotions claimed for an order that
+// could not be placed.
+const releaseTimeout = 5 * time.Second
+
 // CreateOrderUseCase handles the creation of new orders.
 type CreateOrderUseCase struct {
-	orderRepo       domain.OrderRepository
-	sellerOrderRepo domain.SellerOrderRepository
-	publisher       domain.EventPublisher
+	orderRepo          domain.OrderRepository
+	sellerOrderRepo    domain.SellerOrderRepository
+	publisher          domain.EventPublisher
+	catalog            domain.CatalogProvider
+	promotions         domain.PromotionProvider
+	converter          *currency.Converter
+	settlementCurrency string
 }
 
-// NewCreateOrderUseCase creates a new CreateOrderUseCase instance.
+// NewCreateOrderUseCase creates a new CreateOrderUseCase instance. Items
+// are looked up in catalog, priced with the flash sales, bundles, automatic
+// promotions and coupons of promotions and settled in settlementCurrency at
+// rates locked from converter.
 func NewCreateOrderUseCase(
 	orderRepo domain.OrderRepository,
 	sellerOrderRepo domain.SellerOrderRepository,
 	publisher domain.EventPublisher,
+	catalog domain.CatalogProvider,
+	promotions domain.PromotionProvider,
+	converter *currency.Converter,
+	settlementCurrency string,
 ) *CreateOrderUseCase {
 	return &CreateOrderUseCase{
-		orderRepo:       orderRepo,
-		sellerOrderRepo: sellerOrderRepo,
-		publisher:       publisher,
+		orderRepo:          orderRepo,
+		sellerOrderRepo:    sellerOrderRepo,
+		publisher:          publisher,
+		catalog:            catalog,
+		promotions:         promotions,
+		converter:          converter,
+		settlementCurrency: currency.Normalize(settlementCurrency),
 	}
 }
 
@@ -56,12 +91,17 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, input CreateOrderInpu
 	if len(input.Items) == 0 {
 		return nil, errors.New("at least one item is required")
 	}
+	input.Currency = currency.Normalize(input.Currency)
 	if input.Currency == "" {
-		input.Currency = "USD"
+		input.Currency = currency.DefaultCurrency
+	}
+	if !currency.IsSupported(input.Currency) {
+		return nil, fmt.Errorf("unsupported currency: %s", input.Currency)
 	}
 
 	// Convert input items to domain items
 	var items []domain.OrderItem
+	products := make(map[string]*domain.CatalogProduct)
 	for _, item := range input.Items {
 		if item.Quantity <= 0 {
 			return nil, errors.New("item quantity must be greater than 0")
@@ -72,6 +112,13 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, input CreateOrderInpu
 		if item.SellerID == "" {
 			return nil, errors.New("seller_id is required for each item")
 		}
+
+		// Digital items skip shipping, so whether an item is digital is
+		// decided by the catalog rather than the client
+		product, err := uc.product(ctx, products, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
 		items = append(items, domain.OrderItem{
 			ProductID:      item.ProductID,
 			VariantID:      item.VariantID,
@@ -82,44 +129,406 @@ func (uc *CreateOrderUseCase) Execute(ctx context.Context, input CreateOrderInpu
 			UnitPriceCents: item.UnitPriceCents,
 			SellerID:       item.SellerID,
 			ImageURL:       item.ImageURL,
+			IsDigital:      product.IsDigital,
 		})
 	}
 
+	// Orders of digital items only have nothing to ship
+	var shippingQuotes []domain.ShippingQuote
+	if domain.RequiresShipping(items) {
+		if input.ShippingAddress.Line1 == "" {
+			return nil, errors.New("shipping address is required")
+		}
+		shippingQuotes = input.ShippingQuotes
+	}
+	for _, quote := range shippingQuotes {
+		if quote.RateCents < 0 {
+			return nil, errors.New("shipping rate must not be negative")
+		}
+	}
+
 	// Create the order with seller splitting
 	order := domain.NewOrder(input.BuyerID, input.Currency, input.ShippingAddress, items)
+	order.SubscriptionID = input.SubscriptionID
+	order.RenewalAt = input.RenewalAt
+	for _, quote := range shippingQuotes {
+		order.ShippingCents += quote.RateCents
+	}
+	order.Reprice()
+
+	// Promotions and settlement use the same exchange rates
+	snapshot := uc.converter.Snapshot()
+
+	// Claim the flash sale units the order is priced with
+	flashSaleDiscounts, err := uc.applyFlashSales(ctx, order, snapshot)
+	if err != nil {
+		uc.releasePromotions(order)
+		return nil, err
+	}
+
+	// Take bundles and the automatic promotions running off the items
+	bundles, err := uc.applyPromotions(ctx, order, products, snapshot)
+	if err != nil {
+		uc.releasePromotions(order)
+		return nil, err
+	}
+
+	// Reserve the buyer's coupons and take them off what is left
+	if err := uc.applyCoupons(ctx, order, products, snapshot, input.CouponCodes, shippingQuotes); err != nil {
+		uc.releasePromotions(order)
+		return nil, err
+	}
+
+	// Lock the exchange rates the order is settled at
+	if err := uc.lockRates(order, snapshot); err != nil {
+		uc.releasePromotions(order)
+		return nil, err
+	}
 
 	// Persist the order
 	if err := uc.orderRepo.Create(ctx, order); err != nil {
+		uc.releasePromotions(order)
 		return nil, err
 	}
 
 	// Persist seller orders
 	for i := range order.SellerOrders {
 		if err := uc.sellerOrderRepo.Create(ctx, &order.SellerOrders[i]); err != nil {
+			uc.releasePromotions(order)
 			return nil, err
 		}
 	}
 
 	// Publish order.created event
-	var eventItems []domain.ItemEvent
-	for _, item := range order.Items {
-		eventItems = append(eventItems, domain.ItemEvent{
+	eventItems := itemEvents(order.Items)
+	for i := range eventItems {
+		eventItems[i].FlashSaleDiscountCents = flashSaleDiscounts[eventItems[i].ItemID]
+	}
+	var sellerOrders []domain.SellerOrderEvent
+	for _, sellerOrder := range order.SellerOrders {
+		sellerOrders = append(sellerOrders, domain.SellerOrderEvent{
+			SellerOrderID:           sellerOrder.ID,
+			SellerID:                sellerOrder.SellerID,
+			SubtotalCents:           sellerOrder.SubtotalCents,
+			DiscountCents:           sellerOrder.DiscountCents,
+			SettlementSubtotalCents: sellerOrder.SettlementSubtotalCents,
+		})
+	}
+	var bundleEvents []domain.BundleEvent
+	for _, bundle := range bundles {
+		bundleEvents = append(bundleEvents, domain.BundleEvent{
+			BundleID:      bundle.BundleID,
+			Name:          bundle.Name,
+			SellerID:      bundle.SellerID,
+			DiscountCents: bundle.DiscountCents,
+		})
+	}
+	event := domain.OrderCreatedEvent{
+		OrderID:              order.ID,
+		OrderNumber:          order.OrderNumber,
+		BuyerID:              order.BuyerID,
+		TotalCents:           order.TotalCents,
+		Currency:             order.Currency,
+		SettlementCurrency:   order.SettlementCurrency,
+		SettlementTotalCents: order.SettlementTotalCents,
+		ExchangeRates:        order.ExchangeRates,
+		RequiresShipping:     order.RequiresShipping(),
+		Items:                eventItems,
+		SellerOrders:         sellerOrders,
+		Bundles:              bundleEvents,
+		SubscriptionID:       order.SubscriptionID,
+		PaymentMethodID:      input.PaymentMethodID,
+	}
+	_ = uc.publisher.Publish(ctx, domain.EventOrderCreated, event)
+
+	return order, nil
+}
+
+// itemEvents converts order items to their event payloads.
+func itemEvents(items []domain.OrderItem) []domain.ItemEvent {
+	var events []domain.ItemEvent
+	for _, item := range items {
+		events = append(events, domain.ItemEvent{
+			ItemID:         item.ID,
 			ProductID:      item.ProductID,
 			VariantID:      item.VariantID,
 			Quantity:       item.Quantity,
 			UnitPriceCents: item.UnitPriceCents,
+			DiscountCents:  item.DiscountCents,
 			SellerID:       item.SellerID,
+			IsDigital:      item.IsDigital,
 		})
 	}
-	event := domain.OrderCreatedEvent{
-		OrderID:     order.ID,
-		OrderNumber: order.OrderNumber,
-		BuyerID:     order.BuyerID,
-		TotalCents:  order.TotalCents,
-		Currency:    order.Currency,
-		Items:       eventItems,
+	return events
+}
+
+// applyFlashSales claims units of the flash sales running on the order's
+// items and prices the items at the sale price, converted from the
+// settlement currency, when it is lower. It returns what the sales took off
+// the items' list prices, in the settlement currency, by item ID.
+func (uc *CreateOrderUseCase) applyFlashSales(ctx context.Context, order *domain.Order, snapshot currency.RateSnapshot) (map[string]int64, error) {
+	discounts := make(map[string]int64)
+	repriced := false
+	for i := range order.Items {
+		item := &order.Items[i]
+		settlementPrice, ok, err := uc.promotions.ClaimFlashSale(ctx, order.ID, order.BuyerID, item.ProductID, item.VariantID, item.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		salePrice, err := snapshot.Convert(settlementPrice, uc.settlementCurrency, order.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert flash sale price: %w", err)
+		}
+		if salePrice <= 0 || salePrice >= item.UnitPriceCents {
+			continue
+		}
+		listPrice, err := snapshot.Convert(item.UnitPriceCents, order.Currency, uc.settlementCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert item price: %w", err)
+		}
+		if listPrice > settlementPrice {
+			discounts[item.ID] = (listPrice - settlementPrice) * int64(item.Quantity)
+		}
+		item.UnitPriceCents = salePrice
+		repriced = true
 	}
-	_ = uc.publisher.Publish(ctx, domain.EventOrderCreated, event)
+	if repriced {
+		order.Reprice()
+	}
+	return discounts, nil
+}
 
-	return order, nil
+// applyPromotions prices the order's items, in the settlement currency,
+// with the sellers' bundles and then, for the units left out of bundles,
+// with the automatic promotions running, and takes the discounts,
+// converted back into the order's currency, off the items. Free gifts are
+// added to the order at no charge. It returns the bundles applied.
+func (uc *CreateOrderUseCase) applyPromotions(ctx context.Context, order *domain.Order, products map[string]*domain.CatalogProduct, snapshot currency.RateSnapshot) ([]domain.AppliedBundle, error) {
+	lines := make([]domain.PromotionLine, 0, len(order.Items))
+	for _, item := range order.Items {
+		unitPrice, err := snapshot.Convert(item.UnitPriceCents, order.Currency, uc.settlementCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert item price: %w", err)
+		}
+		lines = append(lines, domain.PromotionLine{
+			LineID:         item.ID,
+			ProductID:      item.ProductID,
+			VariantID:      item.VariantID,
+			CategoryID:     products[item.ProductID].CategoryID,
+			SellerID:       item.SellerID,
+			Quantity:       item.Quantity,
+			UnitPriceCents: unitPrice,
+		})
+	}
+
+	bundles, err := uc.promotions.ApplyBundles(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+	var unbundled []domain.PromotionLine
+	for _, line := range lines {
+		line.Quantity -= bundles.BundledUnits[line.LineID]
+		if line.Quantity > 0 {
+			unbundled = append(unbundled, line)
+		}
+	}
+	result := &domain.PromotionResult{}
+	if len(unbundled) > 0 {
+		if result, err = uc.promotions.EvaluatePromotions(ctx, order.BuyerID, unbundled); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		discount := bundles.LineDiscounts[item.ID] + result.LineDiscounts[item.ID]
+		if discount <= 0 {
+			continue
+		}
+		if discount, err = snapshot.Convert(discount, uc.settlementCurrency, order.Currency); err != nil {
+			return nil, fmt.Errorf("failed to convert promotion discount: %w", err)
+		}
+		item.DiscountCents = min(item.DiscountCents+discount, item.UnitPriceCents*int64(item.Quantity))
+	}
+
+	for _, gift := range result.Gifts {
+		product, err := uc.product(ctx, products, gift.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		// Orders without a shipping address have nowhere to ship a
+		// physical gift to
+		if !product.IsDigital && order.ShippingAddress.Line1 == "" {
+			continue
+		}
+		sellerID := gift.SellerID
+		if sellerID == "" {
+			sellerID = product.SellerID
+		}
+		order.AddItem(domain.OrderItem{
+			ProductID:   gift.ProductID,
+			VariantID:   gift.VariantID,
+			ProductName: product.Name,
+			Quantity:    gift.Quantity,
+			SellerID:    sellerID,
+			ImageURL:    product.ImageURL,
+			IsDigital:   product.IsDigital,
+		})
+	}
+
+	order.Reprice()
+	return bundles.Bundles, nil
+}
+
+// applyCoupons reserves the coupons the buyer entered for the order and
+// takes their discounts, converted back into the order's currency, off the
+// items and, for free shipping, off the shipping quotes the order is
+// charged. Coupons discount what is left to pay for each item after
+// promotions, so each item is priced as a single line of that amount.
+func (uc *CreateOrderUseCase) applyCoupons(ctx context.Context, order *domain.Order, products map[string]*domain.CatalogProduct, snapshot currency.RateSnapshot, codes []string, quotes []domain.ShippingQuote) error {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	lines := make([]domain.PromotionLine, 0, len(order.Items))
+	for _, item := range order.Items {
+		net := item.TotalCents - item.DiscountCents
+		if net <= 0 {
+			continue
+		}
+		amount, err := snapshot.Convert(net, order.Currency, uc.settlementCurrency)
+		if err != nil {
+			return fmt.Errorf("failed to convert item price: %w", err)
+		}
+		lines = append(lines, domain.PromotionLine{
+			LineID:         item.ID,
+			ProductID:      item.ProductID,
+			VariantID:      item.VariantID,
+			CategoryID:     products[item.ProductID].CategoryID,
+			SellerID:       item.SellerID,
+			Quantity:       1,
+			UnitPriceCents: amount,
+		})
+	}
+
+	settlementQuotes := make([]domain.ShippingQuote, len(quotes))
+	for i, quote := range quotes {
+		rate, err := snapshot.Convert(quote.RateCents, order.Currency, uc.settlementCurrency)
+		if err != nil {
+			return fmt.Errorf("failed to convert shipping rate: %w", err)
+		}
+		quote.RateCents = rate
+		settlementQuotes[i] = quote
+	}
+
+	result, err := uc.promotions.ReserveCoupons(ctx, order.ID, order.BuyerID, codes, lines, settlementQuotes)
+	if err != nil {
+		return err
+	}
+
+	for i := range order.Items {
+		item := &order.Items[i]
+		discount := result.LineDiscounts[item.ID]
+		if discount <= 0 {
+			continue
+		}
+		if discount, err = snapshot.Convert(discount, uc.settlementCurrency, order.Currency); err != nil {
+			return fmt.Errorf("failed to convert coupon discount: %w", err)
+		}
+		item.DiscountCents = min(item.DiscountCents+discount, item.TotalCents)
+	}
+
+	for i, quote := range result.ShippingQuotes {
+		if i >= len(quotes) {
+			break
+		}
+		waived := settlementQuotes[i].RateCents - quote.RateCents
+		if waived <= 0 {
+			continue
+		}
+		if waived, err = snapshot.Convert(waived, uc.settlementCurrency, order.Currency); err != nil {
+			return fmt.Errorf("failed to convert shipping discount: %w", err)
+		}
+		order.ShippingCents -= min(waived, quotes[i].RateCents)
+	}
+
+	order.Reprice()
+	return nil
+}
+
+// product looks a product up in the catalog, once per order.
+func (uc *CreateOrderUseCase) product(ctx context.Context, products map[string]*domain.CatalogProduct, productID string) (*domain.CatalogProduct, error) {
+	if product, ok := products[productID]; ok {
+		return product, nil
+	}
+	product, err := uc.catalog.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up product %s: %w", productID, err)
+	}
+	products[productID] = product
+	return product, nil
+}
+
+// releasePromotions returns what was claimed for an order that could not
+// be placed.
+func (uc *CreateOrderUseCase) releasePromotions(order *domain.Order) {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+	if err := uc.promotions.ReleaseFlashSales(ctx, order.ID); err != nil {
+		log.Error().Err(err).Str("order_id", order.ID).Msg("failed to release flash sale claims")
+	}
+	if err := uc.promotions.ReleaseCoupons(ctx, order.ID); err != nil {
+		log.Error().Err(err).Str("order_id", order.ID).Msg("failed to release coupons")
+	}
+}
+
+// lockRates snapshots the exchange rates onto the order, converts its total
+// into the settlement currency at them and splits the settlement total
+// across the seller orders.
+func (uc *CreateOrderUseCase) lockRates(order *domain.Order, snapshot currency.RateSnapshot) error {
+	rate, err := snapshot.Rate(order.Currency, uc.settlementCurrency)
+	if err != nil {
+		return fmt.Errorf("failed to lock exchange rate: %w", err)
+	}
+	settlementTotal, err := snapshot.Convert(order.TotalCents, order.Currency, uc.settlementCurrency)
+	if err != nil {
+		return fmt.Errorf("failed to convert order total: %w", err)
+	}
+
+	order.SettlementCurrency = uc.settlementCurrency
+	order.SettlementTotalCents = settlementTotal
+	order.ExchangeRate = rate
+	order.ExchangeRates = domain.ExchangeRates{
+		Base:     snapshot.Base,
+		Rates:    snapshot.Rates,
+		AsOf:     snapshot.AsOf,
+		LockedAt: order.CreatedAt,
+	}
+
+	// Seller shares are allocated from the converted total rather than
+	// converted one by one, so that they add up to it exactly, in the ratio
+	// of what each seller is paid for after promotions. Orders discounted
+	// to nothing are split by the subtotals instead.
+	ratios := make([]int64, len(order.SellerOrders))
+	var net int64
+	for i, sellerOrder := range order.SellerOrders {
+		ratios[i] = sellerOrder.SubtotalCents - sellerOrder.DiscountCents
+		net += ratios[i]
+	}
+	if net == 0 {
+		for i, sellerOrder := range order.SellerOrders {
+			ratios[i] = sellerOrder.SubtotalCents
+		}
+	}
+	shares, err := money.NewMoney(settlementTotal, uc.settlementCurrency).Allocate(ratios...)
+	if err != nil {
+		return fmt.Errorf("failed to split settlement total: %w", err)
+	}
+	for i := range order.SellerOrders {
+		order.SellerOrders[i].SettlementSubtotalCents = shares[i].AmountCents
+	}
+	return nil
 }
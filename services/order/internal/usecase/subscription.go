@@ -0,0 +1,527 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/pkg/currency"
+	"github.com/southern-martin/ecommerce/pkg/money"
+	"github.com/southern-martin/ecommerce/services/order/internal/domain"
+)
+
+// dunningSchedule holds how long after each failed payment of a renewal
+// it is charged again. Once every retry has failed, the renewal order and
+// the subscription are cancelled.
+var dunningSchedule = []time.Duration{
+	24 * time.Hour,
+	72 * time.Hour,
+	120 * time.Hour,
+}
+
+// subscriptionBatchSize is how many due subscriptions are handled per
+// scheduler run and kind of work.
+const subscriptionBatchSize = 100
+
+// SubscriptionUseCase manages buyers' subscriptions to repeat orders and
+// places their renewal orders.
+type SubscriptionUseCase struct {
+	subRepo      domain.SubscriptionRepository
+	orderRepo    domain.OrderRepository
+	createOrder  *CreateOrderUseCase
+	cancelOrder  *CancelOrderUseCase
+	catalog      domain.CatalogProvider
+	publisher    domain.EventPublisher
+	reminderLead time.Duration
+}
+
+// NewSubscriptionUseCase creates a new SubscriptionUseCase. Buyers are
+// reminded of each renewal reminderLead before it is placed.
+func NewSubscriptionUseCase(
+	subRepo domain.SubscriptionRepository,
+	orderRepo domain.OrderRepository,
+	createOrder *CreateOrderUseCase,
+	cancelOrder *CancelOrderUseCase,
+	catalog domain.CatalogProvider,
+	publisher domain.EventPublisher,
+	reminderLead time.Duration,
+) *SubscriptionUseCase {
+	return &SubscriptionUseCase{
+		subRepo:      subRepo,
+		orderRepo:    orderRepo,
+		createOrder:  createOrder,
+		cancelOrder:  cancelOrder,
+		catalog:      catalog,
+		publisher:    publisher,
+		reminderLead: reminderLead,
+	}
+}
+
+// SubscribeInput represents the input for subscribing to a product.
+type SubscribeInput struct {
+	BuyerID         string
+	ProductID       string
+	PlanID          string
+	Quantity        int
+	Currency        string
+	ShippingAddress domain.Address
+	PaymentMethodID string
+}
+
+// UpdateSubscriptionInput holds the changes to a subscription. Nil fields
+// are left unchanged.
+type UpdateSubscriptionInput struct {
+	Quantity        *int
+	ShippingAddress *domain.Address
+	PaymentMethodID *string
+}
+
+// Subscribe subscribes a buyer to a product on one of its subscription
+// plans and places the first order straight away.
+func (uc *SubscriptionUseCase) Subscribe(ctx context.Context, input SubscribeInput) (*domain.Subscription, error) {
+	if input.BuyerID == "" {
+		return nil, errors.New("buyer_id is required")
+	}
+	if input.PaymentMethodID == "" {
+		return nil, errors.New("payment_method_id is required")
+	}
+	if input.Quantity <= 0 {
+		return nil, errors.New("quantity must be greater than 0")
+	}
+	if input.ShippingAddress.Line1 == "" {
+		return nil, errors.New("shipping address is required")
+	}
+	input.Currency = currency.Normalize(input.Currency)
+	if input.Currency == "" {
+		input.Currency = currency.DefaultCurrency
+	}
+	if !currency.IsSupported(input.Currency) {
+		return nil, fmt.Errorf("unsupported currency: %s", input.Currency)
+	}
+
+	plan, err := uc.catalog.GetSubscriptionPlan(ctx, input.ProductID, input.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := uc.catalog.GetProduct(ctx, input.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsDigital {
+		return nil, errors.New("digital products cannot be subscribed to")
+	}
+
+	now := time.Now().UTC()
+	sub := &domain.Subscription{
+		ID:              uuid.New().String(),
+		BuyerID:         input.BuyerID,
+		PlanID:          plan.ID,
+		ProductID:       product.ID,
+		VariantID:       plan.VariantID,
+		SellerID:        product.SellerID,
+		ProductName:     product.Name,
+		VariantName:     plan.VariantName,
+		SKU:             plan.SKU,
+		ImageURL:        product.ImageURL,
+		Quantity:        input.Quantity,
+		Currency:        input.Currency,
+		IntervalUnit:    plan.IntervalUnit,
+		IntervalCount:   plan.IntervalCount,
+		PercentOff:      plan.PercentOff,
+		ShippingAddress: input.ShippingAddress,
+		PaymentMethodID: input.PaymentMethodID,
+		Status:          domain.SubscriptionStatusActive,
+		NextRunAt:       now,
+		// The buyer is not reminded of the first order, placed right now
+		ReminderSentAt: &now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := uc.subRepo.Create(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	// The first order is due already, so the scheduler retries it if it
+	// cannot be placed now
+	if err := uc.renew(ctx, sub, now); err != nil {
+		log.Error().Err(err).Str("subscription_id", sub.ID).Msg("failed to place first subscription order")
+	}
+	return sub, nil
+}
+
+// GetSubscription returns a buyer's subscription.
+func (uc *SubscriptionUseCase) GetSubscription(ctx context.Context, id string, buyerID string) (*domain.Subscription, error) {
+	return uc.buyerSubscription(ctx, id, buyerID)
+}
+
+// ListSubscriptions returns a paginated list of a buyer's subscriptions.
+func (uc *SubscriptionUseCase) ListSubscriptions(ctx context.Context, buyerID string, page, pageSize int) ([]*domain.Subscription, int64, error) {
+	if buyerID == "" {
+		return nil, 0, errors.New("buyer_id is required")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return uc.subRepo.ListByBuyer(ctx, buyerID, page, pageSize)
+}
+
+// UpdateSubscription changes the quantity, shipping address or payment
+// method of a subscription, taking effect from the next renewal. A new
+// payment method is also charged by pending payment retries.
+func (uc *SubscriptionUseCase) UpdateSubscription(ctx context.Context, id string, buyerID string, input UpdateSubscriptionInput) (*domain.Subscription, error) {
+	sub, err := uc.buyerSubscription(ctx, id, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Status == domain.SubscriptionStatusCancelled {
+		return nil, errors.New("subscription is cancelled")
+	}
+
+	if input.Quantity != nil {
+		if *input.Quantity <= 0 {
+			return nil, errors.New("quantity must be greater than 0")
+		}
+		sub.Quantity = *input.Quantity
+	}
+	if input.ShippingAddress != nil {
+		if input.ShippingAddress.Line1 == "" {
+			return nil, errors.New("shipping address is required")
+		}
+		sub.ShippingAddress = *input.ShippingAddress
+	}
+	if input.PaymentMethodID != nil {
+		if *input.PaymentMethodID == "" {
+			return nil, errors.New("payment_method_id is required")
+		}
+		sub.PaymentMethodID = *input.PaymentMethodID
+	}
+
+	sub.UpdatedAt = time.Now().UTC()
+	if err := uc.subRepo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// PauseSubscription stops placing renewals of an active subscription until
+// it is resumed.
+func (uc *SubscriptionUseCase) PauseSubscription(ctx context.Context, id string, buyerID string) (*domain.Subscription, error) {
+	sub, err := uc.buyerSubscription(ctx, id, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Status != domain.SubscriptionStatusActive {
+		return nil, fmt.Errorf("subscription cannot be paused from status %s", sub.Status)
+	}
+
+	sub.Status = domain.SubscriptionStatusPaused
+	sub.UpdatedAt = time.Now().UTC()
+	if err := uc.subRepo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// ResumeSubscription resumes a paused subscription. Renewals missed while
+// it was paused are not placed; it renews next on its first scheduled date
+// from now on.
+func (uc *SubscriptionUseCase) ResumeSubscription(ctx context.Context, id string, buyerID string) (*domain.Subscription, error) {
+	sub, err := uc.buyerSubscription(ctx, id, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Status != domain.SubscriptionStatusPaused {
+		return nil, fmt.Errorf("subscription cannot be resumed from status %s", sub.Status)
+	}
+
+	now := time.Now().UTC()
+	if !sub.NextRunAt.After(now) {
+		sub.NextRunAt = sub.RenewalAfter(now)
+		sub.ReminderSentAt = nil
+	}
+	sub.Status = domain.SubscriptionStatusActive
+	sub.UpdatedAt = now
+	if err := uc.subRepo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// SkipRenewal skips the next renewal of an active subscription.
+func (uc *SubscriptionUseCase) SkipRenewal(ctx context.Context, id string, buyerID string) (*domain.Subscription, error) {
+	sub, err := uc.buyerSubscription(ctx, id, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Status != domain.SubscriptionStatusActive {
+		return nil, fmt.Errorf("renewals cannot be skipped from status %s", sub.Status)
+	}
+
+	sub.NextRunAt = sub.RenewalAfter(sub.NextRunAt)
+	sub.ReminderSentAt = nil
+	sub.UpdatedAt = time.Now().UTC()
+	if err := uc.subRepo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// CancelSubscription cancels a subscription. The unpaid renewal order of a
+// past due subscription is cancelled with it.
+func (uc *SubscriptionUseCase) CancelSubscription(ctx context.Context, id string, buyerID string) (*domain.Subscription, error) {
+	sub, err := uc.buyerSubscription(ctx, id, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Status == domain.SubscriptionStatusCancelled {
+		return nil, errors.New("subscription is already cancelled")
+	}
+	if err := uc.cancel(ctx, sub, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// RunDue does the subscription work due at now: it reminds buyers of
+// upcoming renewals, places due renewal orders and retries failed renewal
+// payments.
+func (uc *SubscriptionUseCase) RunDue(ctx context.Context, now time.Time) {
+	now = now.UTC()
+
+	reminders, err := uc.subRepo.ListDueReminders(ctx, now.Add(uc.reminderLead), subscriptionBatchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list due subscription reminders")
+	}
+	for _, sub := range reminders {
+		sub.ReminderSentAt = &now
+		sub.UpdatedAt = now
+		if err := uc.subRepo.Update(ctx, sub); err != nil {
+			log.Error().Err(err).Str("subscription_id", sub.ID).Msg("failed to record subscription reminder")
+			continue
+		}
+		_ = uc.publisher.Publish(ctx, domain.EventSubscriptionRenewalUpcoming, toSubscriptionEvent(sub))
+	}
+
+	renewals, err := uc.subRepo.ListDueRenewals(ctx, now, subscriptionBatchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list due subscription renewals")
+	}
+	for _, sub := range renewals {
+		if err := uc.renew(ctx, sub, now); err != nil {
+			log.Error().Err(err).Str("subscription_id", sub.ID).Msg("failed to renew subscription")
+		}
+	}
+
+	retries, err := uc.subRepo.ListDueRetries(ctx, now, subscriptionBatchSize)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list due subscription payment retries")
+	}
+	for _, sub := range retries {
+		// The next retry is scheduled when the payment service reports
+		// that this one failed
+		sub.NextRetryAt = nil
+		sub.UpdatedAt = now
+		if err := uc.subRepo.Update(ctx, sub); err != nil {
+			log.Error().Err(err).Str("subscription_id", sub.ID).Msg("failed to record subscription payment retry")
+			continue
+		}
+		_ = uc.publisher.Publish(ctx, domain.EventSubscriptionPaymentRetry, domain.PaymentRetryEvent{
+			OrderID:         sub.LastOrderID,
+			SubscriptionID:  sub.ID,
+			PaymentMethodID: sub.PaymentMethodID,
+		})
+	}
+}
+
+// HandlePaymentFailed puts a subscription whose renewal order could not be
+// charged past due and schedules a retry of the payment. Once every retry
+// has failed, the order and the subscription are cancelled.
+func (uc *SubscriptionUseCase) HandlePaymentFailed(ctx context.Context, orderID string) error {
+	sub, err := uc.renewalSubscription(ctx, orderID)
+	if err != nil || sub == nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	sub.FailedAttempts++
+	if sub.FailedAttempts > len(dunningSchedule) {
+		return uc.cancel(ctx, sub, now)
+	}
+
+	retryAt := now.Add(dunningSchedule[sub.FailedAttempts-1])
+	sub.Status = domain.SubscriptionStatusPastDue
+	sub.NextRetryAt = &retryAt
+	sub.UpdatedAt = now
+	if err := uc.subRepo.Update(ctx, sub); err != nil {
+		return err
+	}
+	_ = uc.publisher.Publish(ctx, domain.EventSubscriptionPaymentFailed, toSubscriptionEvent(sub))
+	return nil
+}
+
+// HandlePaymentCompleted reactivates a past due subscription once its
+// renewal order has been paid for.
+func (uc *SubscriptionUseCase) HandlePaymentCompleted(ctx context.Context, orderID string) error {
+	sub, err := uc.renewalSubscription(ctx, orderID)
+	if err != nil || sub == nil || sub.Status != domain.SubscriptionStatusPastDue {
+		return err
+	}
+
+	sub.Status = domain.SubscriptionStatusActive
+	sub.FailedAttempts = 0
+	sub.NextRetryAt = nil
+	sub.UpdatedAt = time.Now().UTC()
+	return uc.subRepo.Update(ctx, sub)
+}
+
+// renew places the renewal order of a subscription at the variant's
+// current price less the plan's discount, and schedules the next renewal.
+// Each scheduled renewal is placed at most once: if an order for it already
+// exists, because scheduling the next renewal failed or another replica
+// renewed the subscription concurrently, that order is kept instead.
+func (uc *SubscriptionUseCase) renew(ctx context.Context, sub *domain.Subscription, now time.Time) error {
+	// Postgres keeps microseconds, so match the stored renewal time
+	renewalAt := sub.NextRunAt.UTC().Truncate(time.Microsecond)
+	order, err := uc.orderRepo.GetRenewal(ctx, sub.ID, renewalAt)
+	if err != nil {
+		return fmt.Errorf("failed to look up renewal order: %w", err)
+	}
+	if order == nil {
+		order, err = uc.placeRenewal(ctx, sub, renewalAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	sub.LastOrderID = order.ID
+	sub.NextRunAt = sub.RenewalAfter(now)
+	sub.ReminderSentAt = nil
+	sub.FailedAttempts = 0
+	sub.NextRetryAt = nil
+	sub.UpdatedAt = now
+	return uc.subRepo.Update(ctx, sub)
+}
+
+// placeRenewal places the order of a subscription's renewal scheduled at
+// renewalAt, or returns the order another replica placed for it first.
+func (uc *SubscriptionUseCase) placeRenewal(ctx context.Context, sub *domain.Subscription, renewalAt time.Time) (*domain.Order, error) {
+	priceCents, err := uc.catalog.GetVariantPrice(ctx, sub.ProductID, sub.VariantID, sub.Currency)
+	if err != nil {
+		return nil, err
+	}
+	// PercentOff is in basis points
+	unitPrice, err := money.NewMoney(priceCents, sub.Currency).MultiplyRatio(10000-sub.PercentOff, 10000, money.RoundHalfUp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discount renewal price: %w", err)
+	}
+	if !unitPrice.IsPositive() {
+		return nil, fmt.Errorf("discounted price of variant %s is not positive", sub.VariantID)
+	}
+
+	order, err := uc.createOrder.Execute(ctx, CreateOrderInput{
+		BuyerID:         sub.BuyerID,
+		Currency:        sub.Currency,
+		ShippingAddress: sub.ShippingAddress,
+		Items: []CreateOrderItemInput{{
+			ProductID:      sub.ProductID,
+			VariantID:      sub.VariantID,
+			ProductName:    sub.ProductName,
+			VariantName:    sub.VariantName,
+			SKU:            sub.SKU,
+			Quantity:       sub.Quantity,
+			UnitPriceCents: unitPrice.AmountCents,
+			SellerID:       sub.SellerID,
+			ImageURL:       sub.ImageURL,
+		}},
+		SubscriptionID:  sub.ID,
+		RenewalAt:       &renewalAt,
+		PaymentMethodID: sub.PaymentMethodID,
+	})
+	if err != nil {
+		// Orders are unique per scheduled renewal, so placing one fails
+		// when another replica placed it first
+		existing, lookupErr := uc.orderRepo.GetRenewal(ctx, sub.ID, renewalAt)
+		if lookupErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, fmt.Errorf("failed to place renewal order: %w", err)
+	}
+	return order, nil
+}
+
+// cancel cancels a subscription, and its renewal order if it is unpaid.
+func (uc *SubscriptionUseCase) cancel(ctx context.Context, sub *domain.Subscription, now time.Time) error {
+	if sub.Status == domain.SubscriptionStatusPastDue && sub.LastOrderID != "" {
+		if _, err := uc.cancelOrder.Execute(ctx, sub.LastOrderID, sub.BuyerID); err != nil {
+			log.Error().Err(err).Str("order_id", sub.LastOrderID).Msg("failed to cancel unpaid renewal order")
+		}
+	}
+
+	sub.Status = domain.SubscriptionStatusCancelled
+	sub.NextRetryAt = nil
+	sub.CancelledAt = &now
+	sub.UpdatedAt = now
+	if err := uc.subRepo.Update(ctx, sub); err != nil {
+		return err
+	}
+	_ = uc.publisher.Publish(ctx, domain.EventSubscriptionCancelled, toSubscriptionEvent(sub))
+	return nil
+}
+
+// renewalSubscription returns the subscription whose latest renewal order
+// is orderID, or nil if the order is not one.
+func (uc *SubscriptionUseCase) renewalSubscription(ctx context.Context, orderID string) (*domain.Subscription, error) {
+	order, err := uc.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.SubscriptionID == "" {
+		return nil, nil
+	}
+
+	sub, err := uc.subRepo.GetByID(ctx, order.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.LastOrderID != orderID || sub.Status == domain.SubscriptionStatusCancelled {
+		return nil, nil
+	}
+	return sub, nil
+}
+
+func (uc *SubscriptionUseCase) buyerSubscription(ctx context.Context, id string, buyerID string) (*domain.Subscription, error) {
+	sub, err := uc.subRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.BuyerID != buyerID {
+		return nil, errors.New("subscription does not belong to this buyer")
+	}
+	return sub, nil
+}
+
+func toSubscriptionEvent(sub *domain.Subscription) domain.SubscriptionEvent {
+	event := domain.SubscriptionEvent{
+		SubscriptionID: sub.ID,
+		BuyerID:        sub.BuyerID,
+		ProductID:      sub.ProductID,
+		ProductName:    sub.ProductName,
+		Quantity:       sub.Quantity,
+		Status:         string(sub.Status),
+		OrderID:        sub.LastOrderID,
+		FailedAttempts: sub.FailedAttempts,
+		NextRetryAt:    sub.NextRetryAt,
+	}
+	if sub.Status == domain.SubscriptionStatusActive {
+		nextRunAt := sub.NextRunAt
+		event.NextRunAt = &nextRunAt
+	}
+	return event
+}
@@ -34,7 +34,19 @@ func (uc *UpdateOrderStatusUseCase) Execute(ctx context.Context, sellerOrderID s
 		return nil, err
 	}
 
-	if !domain.CanTransition(sellerOrder.Status, newStatus) {
+	// Only the seller's own items decide whether the seller order ships
+	var items []domain.OrderItem
+	order, err := uc.orderRepo.GetByID(ctx, sellerOrder.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range order.Items {
+		if item.SellerID == sellerOrder.SellerID {
+			items = append(items, item)
+		}
+	}
+
+	if !domain.CanTransitionItems(sellerOrder.Status, newStatus, items) {
 		return nil, fmt.Errorf("invalid status transition from %s to %s", sellerOrder.Status, newStatus)
 	}
 
@@ -44,24 +56,66 @@ func (uc *UpdateOrderStatusUseCase) Execute(ctx context.Context, sellerOrderID s
 
 	sellerOrder.Status = newStatus
 
-	// Publish status change event
-	order, err := uc.orderRepo.GetByID(ctx, sellerOrder.OrderID)
-	if err == nil {
-		statusEvent := domain.OrderStatusEvent{
-			OrderID:     order.ID,
-			OrderNumber: order.OrderNumber,
-			BuyerID:     order.BuyerID,
-			Status:      newStatus,
-		}
-		subject := statusToEventSubject(newStatus)
-		if subject != "" {
-			_ = uc.publisher.Publish(ctx, subject, statusEvent)
+	if newStatus == domain.OrderStatusCancelled {
+		if err := uc.sellerOrderCancelled(ctx, order, sellerOrder, items); err != nil {
+			return nil, err
 		}
+		return sellerOrder, nil
+	}
+
+	// Publish status change event
+	statusEvent := domain.OrderStatusEvent{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		BuyerID:     order.BuyerID,
+		Status:      newStatus,
+	}
+	subject := statusToEventSubject(newStatus)
+	if subject != "" {
+		_ = uc.publisher.Publish(ctx, subject, statusEvent)
 	}
 
 	return sellerOrder, nil
 }
 
+// sellerOrderCancelled publishes the cancellation of one seller's part of
+// an order, so that only what its items hold is released. Once every
+// seller order is cancelled, the order itself is cancelled.
+func (uc *UpdateOrderStatusUseCase) sellerOrderCancelled(ctx context.Context, order *domain.Order, sellerOrder *domain.SellerOrder, items []domain.OrderItem) error {
+	sellerOrders, err := uc.sellerOrderRepo.ListByOrder(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	for _, so := range sellerOrders {
+		if so.Status != domain.OrderStatusCancelled {
+			event := domain.SellerOrderCancelledEvent{
+				OrderID:       order.ID,
+				OrderNumber:   order.OrderNumber,
+				BuyerID:       order.BuyerID,
+				SellerOrderID: sellerOrder.ID,
+				SellerID:      sellerOrder.SellerID,
+				Items:         itemEvents(items),
+			}
+			_ = uc.publisher.Publish(ctx, domain.EventSellerOrderCancelled, event)
+			return nil
+		}
+	}
+
+	if domain.CanTransition(order.Status, domain.OrderStatusCancelled) {
+		if err := uc.orderRepo.UpdateStatus(ctx, order.ID, domain.OrderStatusCancelled); err != nil {
+			return err
+		}
+	}
+	event := domain.OrderStatusEvent{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		BuyerID:     order.BuyerID,
+		Status:      domain.OrderStatusCancelled,
+	}
+	_ = uc.publisher.Publish(ctx, domain.EventOrderCancelled, event)
+	return nil
+}
+
 // UpdateOrderStatus updates the status of an order directly (used by gRPC / inter-service).
 func (uc *UpdateOrderStatusUseCase) UpdateOrderStatus(ctx context.Context, orderID string, newStatus domain.OrderStatus) (*domain.Order, error) {
 	order, err := uc.orderRepo.GetByID(ctx, orderID)
@@ -69,7 +123,7 @@ func (uc *UpdateOrderStatusUseCase) UpdateOrderStatus(ctx context.Context, order
 		return nil, err
 	}
 
-	if !domain.CanTransition(order.Status, newStatus) {
+	if !domain.CanTransitionItems(order.Status, newStatus, order.Items) {
 		return nil, fmt.Errorf("invalid status transition from %s to %s", order.Status, newStatus)
 	}
 
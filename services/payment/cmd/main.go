@@ -15,6 +15,7 @@ import (
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	grpcAdapter "github.com/southern-martin/ecommerce/services/payment/internal/adapter/grpc"
 	httpAdapter "github.com/southern-martin/ecommerce/services/payment/internal/adapter/http"
 	"github.com/southern-martin/ecommerce/services/payment/internal/adapter/postgres"
@@ -58,6 +59,7 @@ func main() {
 		&postgres.SellerWalletModel{},
 		&postgres.WalletTransactionModel{},
 		&postgres.PayoutModel{},
+		&postgres.SavedPaymentMethodModel{},
 	); err != nil {
 		log.Fatal().Err(err).Msg("Failed to migrate database")
 	}
@@ -77,16 +79,36 @@ func main() {
 	paymentRepo := postgres.NewPaymentRepo(db)
 	walletRepo := postgres.NewWalletRepo(db)
 	payoutRepo := postgres.NewPayoutRepo(db)
+	methodRepo := postgres.NewSavedPaymentMethodRepo(db)
+
+	// Exchange rates for settling sales in seller wallet currencies; the
+	// rates locked on an order take precedence.
+	ratesCtx, stopRates := context.WithCancel(context.Background())
+	defer stopRates()
+	converter := currency.NewConverter()
+	if src := currency.NewRateSource(cfg.ExchangeRatesSource); src != nil {
+		if err := converter.LoadRates(ratesCtx, src); err != nil {
+			log.Warn().Err(err).Msg("Failed to load exchange rates, using default rates")
+		}
+		converter.StartRefresher(ratesCtx, src, time.Duration(cfg.ExchangeRatesRefreshMinutes)*time.Minute, func(err error) {
+			log.Error().Err(err).Msg("Failed to refresh exchange rates")
+		})
+	}
 
 	// Initialize use cases.
 	createPaymentUC := usecase.NewCreatePaymentUseCase(paymentRepo, stripeClient, publisher)
-	confirmPaymentUC := usecase.NewConfirmPaymentUseCase(paymentRepo, walletRepo, publisher, cfg.PlatformCommissionRate)
+	confirmPaymentUC := usecase.NewConfirmPaymentUseCase(paymentRepo, walletRepo, publisher, converter, cfg.PlatformCommissionRate)
 	walletUC := usecase.NewWalletUseCase(walletRepo)
 	payoutUC := usecase.NewPayoutUseCase(payoutRepo, walletRepo, stripeClient)
-	refundUC := usecase.NewRefundUseCase(paymentRepo, walletRepo, stripeClient, publisher)
+	refundUC := usecase.NewRefundUseCase(paymentRepo, walletRepo, stripeClient, publisher, converter)
+	paymentMethodUC := usecase.NewPaymentMethodUseCase(methodRepo, stripeClient)
+	chargeUC := usecase.NewChargeUseCase(paymentRepo, methodRepo, stripeClient, confirmPaymentUC)
 
 	// Subscribe to order.created events.
-	subscribeOrderCreated(publisher, paymentRepo)
+	subscribeOrderCreated(publisher, paymentRepo, chargeUC)
+
+	// Subscribe to dunning retries of subscription renewals.
+	subscribePaymentRetry(publisher, chargeUC)
 
 	// Initialize HTTP handler and router.
 	handler := httpAdapter.NewHandler(
@@ -96,6 +118,7 @@ func main() {
 		walletUC,
 		payoutUC,
 		refundUC,
+		paymentMethodUC,
 	)
 	router := httpAdapter.NewRouter(handler)
 
@@ -143,7 +166,8 @@ func main() {
 }
 
 // subscribeOrderCreated subscribes to order.created events and creates pending payment records.
-func subscribeOrderCreated(publisher *natsInfra.Publisher, paymentRepo domain.PaymentRepository) {
+// Orders placed with a saved payment method, such as subscription renewals, are charged to it.
+func subscribeOrderCreated(publisher *natsInfra.Publisher, paymentRepo domain.PaymentRepository, chargeUC *usecase.ChargeUseCase) {
 	_, err := publisher.Subscribe(domain.EventOrderCreated, func(data []byte) {
 		var event domain.OrderCreatedEvent
 		if err := json.Unmarshal(data, &event); err != nil {
@@ -154,18 +178,20 @@ func subscribeOrderCreated(publisher *natsInfra.Publisher, paymentRepo domain.Pa
 		log.Info().Str("order_id", event.OrderID).Msg("Received order.created event")
 
 		payment := &domain.Payment{
-			ID:          uuid.New().String(),
-			OrderID:     event.OrderID,
-			BuyerID:     event.BuyerID,
-			AmountCents: event.AmountCents,
-			Currency:    event.Currency,
-			Status:      domain.PaymentStatusPending,
-			Method:      domain.PaymentMethodCard,
-			CreatedAt:   time.Now(),
-			UpdatedAt:   time.Now(),
+			ID:            uuid.New().String(),
+			OrderID:       event.OrderID,
+			BuyerID:       event.BuyerID,
+			AmountCents:   event.AmountCents,
+			Currency:      event.Currency,
+			ExchangeRates: event.ExchangeRates,
+			SellerItems:   event.SellerAmounts(),
+			Status:        domain.PaymentStatusPending,
+			Method:        domain.PaymentMethodCard,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
 		if err := paymentRepo.Create(ctx, payment); err != nil {
@@ -174,8 +200,37 @@ func subscribeOrderCreated(publisher *natsInfra.Publisher, paymentRepo domain.Pa
 		}
 
 		log.Info().Str("payment_id", payment.ID).Str("order_id", event.OrderID).Msg("Created pending payment from order event")
+
+		if event.PaymentMethodID == "" {
+			return
+		}
+		if err := chargeUC.Charge(ctx, payment, event.PaymentMethodID); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("Failed to charge saved payment method")
+		}
 	})
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to subscribe to order.created")
 	}
 }
+
+// subscribePaymentRetry subscribes to subscription.payment.retry events and charges the failed
+// payments of subscription renewal orders again.
+func subscribePaymentRetry(publisher *natsInfra.Publisher, chargeUC *usecase.ChargeUseCase) {
+	_, err := publisher.Subscribe(domain.EventSubscriptionPaymentRetry, func(data []byte) {
+		var event domain.PaymentRetryEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("Failed to unmarshal subscription.payment.retry event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := chargeUC.Retry(ctx, event.OrderID, event.PaymentMethodID); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("Failed to retry payment")
+		}
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to subscribe to subscription.payment.retry")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	_ "github.com/southern-martin/ecommerce/pkg/grpcjson"
 	"github.com/southern-martin/ecommerce/services/payment/internal/domain"
 	"github.com/southern-martin/ecommerce/services/payment/internal/usecase"
 )
@@ -17,6 +18,7 @@ import (
 type PaymentService interface {
 	GetPayment(ctx context.Context, req *GetPaymentRequest) (*GetPaymentResponse, error)
 	ProcessRefund(ctx context.Context, req *ProcessRefundRequest) (*ProcessRefundResponse, error)
+	ListPayments(ctx context.Context, req *ListPaymentsRequest) (*ListPaymentsResponse, error)
 }
 
 // GetPaymentRequest is the request for GetPayment.
@@ -48,6 +50,19 @@ type ProcessRefundResponse struct {
 	Message string
 }
 
+// ListPaymentsRequest is the request for ListPayments.
+type ListPaymentsRequest struct {
+	Statuses []string
+	Page     int32
+	PageSize int32
+}
+
+// ListPaymentsResponse is the response for ListPayments.
+type ListPaymentsResponse struct {
+	Payments []*GetPaymentResponse
+	Total    int64
+}
+
 // PaymentGRPCServer implements the PaymentService gRPC interface.
 type PaymentGRPCServer struct {
 	paymentRepo domain.PaymentRepository
@@ -108,6 +123,45 @@ func (s *PaymentGRPCServer) ProcessRefund(ctx context.Context, req *ProcessRefun
 	}, nil
 }
 
+// ListPayments lists payments in any of the requested statuses, oldest
+// first, for services rebuilding state from payment history.
+func (s *PaymentGRPCServer) ListPayments(ctx context.Context, req *ListPaymentsRequest) (*ListPaymentsResponse, error) {
+	if len(req.Statuses) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "statuses are required")
+	}
+	statuses := make([]domain.PaymentStatus, len(req.Statuses))
+	for i, name := range req.Statuses {
+		statuses[i] = domain.PaymentStatus(name)
+	}
+	page, pageSize := int(req.Page), int(req.PageSize)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 100
+	}
+
+	payments, total, err := s.paymentRepo.ListByStatus(ctx, statuses, page, pageSize)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list payments")
+		return nil, status.Error(codes.Internal, "failed to list payments")
+	}
+
+	resp := &ListPaymentsResponse{Total: total}
+	for _, payment := range payments {
+		resp.Payments = append(resp.Payments, &GetPaymentResponse{
+			PaymentID:   payment.ID,
+			OrderID:     payment.OrderID,
+			BuyerID:     payment.BuyerID,
+			AmountCents: payment.AmountCents,
+			Currency:    payment.Currency,
+			Status:      string(payment.Status),
+			Method:      string(payment.Method),
+		})
+	}
+	return resp, nil
+}
+
 // paymentServiceDesc is the gRPC ServiceDesc for PaymentService.
 var paymentServiceDesc = grpc.ServiceDesc{
 	ServiceName: "payment.PaymentService",
@@ -121,6 +175,10 @@ var paymentServiceDesc = grpc.ServiceDesc{
 			MethodName: "ProcessRefund",
 			Handler:    processRefundHandler,
 		},
+		{
+			MethodName: "ListPayments",
+			Handler:    listPaymentsHandler,
+		},
 	},
 	Streams: []grpc.StreamDesc{},
 }
@@ -161,6 +219,24 @@ func processRefundHandler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, req, info, handler)
 }
 
+func listPaymentsHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &ListPaymentsRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentService).ListPayments(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/payment.PaymentService/ListPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentService).ListPayments(ctx, req.(*ListPaymentsRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
 // RegisterPaymentService registers the PaymentService with a gRPC server.
 func RegisterPaymentService(s *grpc.Server, srv PaymentService) {
 	s.RegisterService(&paymentServiceDesc, srv)
@@ -19,6 +19,7 @@ type Handler struct {
 	wallet         *usecase.WalletUseCase
 	payout         *usecase.PayoutUseCase
 	refund         *usecase.RefundUseCase
+	paymentMethod  *usecase.PaymentMethodUseCase
 }
 
 // NewHandler creates a new Handler.
@@ -29,6 +30,7 @@ func NewHandler(
 	wallet *usecase.WalletUseCase,
 	payout *usecase.PayoutUseCase,
 	refund *usecase.RefundUseCase,
+	paymentMethod *usecase.PaymentMethodUseCase,
 ) *Handler {
 	return &Handler{
 		paymentRepo:    paymentRepo,
@@ -37,6 +39,7 @@ func NewHandler(
 		wallet:         wallet,
 		payout:         payout,
 		refund:         refund,
+		paymentMethod:  paymentMethod,
 	}
 }
 
@@ -119,6 +122,81 @@ func (h *Handler) HandleStripeWebhook(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"received": true})
 }
 
+// SavePaymentMethod saves a payment method for the authenticated buyer.
+func (h *Handler) SavePaymentMethod(c *gin.Context) {
+	buyerID := c.GetString("user_id")
+	if buyerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var input usecase.SavePaymentMethodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	method, err := h.paymentMethod.Save(c.Request.Context(), buyerID, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, method)
+}
+
+// ListPaymentMethods lists the authenticated buyer's saved payment methods.
+func (h *Handler) ListPaymentMethods(c *gin.Context) {
+	buyerID := c.GetString("user_id")
+	if buyerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	methods, err := h.paymentMethod.List(c.Request.Context(), buyerID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to list payment methods")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list payment methods"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment_methods": methods})
+}
+
+// SetDefaultPaymentMethod makes a saved payment method the authenticated
+// buyer's default.
+func (h *Handler) SetDefaultPaymentMethod(c *gin.Context) {
+	buyerID := c.GetString("user_id")
+	if buyerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.paymentMethod.SetDefault(c.Request.Context(), buyerID, c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "default payment method updated"})
+}
+
+// DeletePaymentMethod removes one of the authenticated buyer's saved
+// payment methods.
+func (h *Handler) DeletePaymentMethod(c *gin.Context) {
+	buyerID := c.GetString("user_id")
+	if buyerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	if err := h.paymentMethod.Delete(c.Request.Context(), buyerID, c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "payment method deleted"})
+}
+
 // GetWalletBalance returns the wallet balance for the authenticated seller.
 func (h *Handler) GetWalletBalance(c *gin.Context) {
 	sellerID := c.GetString("user_id")
@@ -136,6 +214,30 @@ func (h *Handler) GetWalletBalance(c *gin.Context) {
 	c.JSON(http.StatusOK, wallet)
 }
 
+// SetWalletCurrency sets the currency of the authenticated seller's wallet.
+func (h *Handler) SetWalletCurrency(c *gin.Context) {
+	sellerID := c.GetString("user_id")
+	if sellerID == "" {
+		sellerID = c.Query("seller_id")
+	}
+
+	var req struct {
+		Currency string `json:"currency" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	wallet, err := h.wallet.SetCurrency(c.Request.Context(), sellerID, req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, wallet)
+}
+
 // ListWalletTransactions lists wallet transactions for the authenticated seller.
 func (h *Handler) ListWalletTransactions(c *gin.Context) {
 	sellerID := c.GetString("user_id")
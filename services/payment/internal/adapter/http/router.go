@@ -23,8 +23,15 @@ func NewRouter(handler *Handler) *gin.Engine {
 		// Webhook routes (no auth).
 		v1.POST("/webhooks/stripe", handler.HandleStripeWebhook)
 
+		// Saved payment method routes.
+		v1.POST("/methods", handler.SavePaymentMethod)
+		v1.GET("/methods", handler.ListPaymentMethods)
+		v1.PUT("/methods/:id/default", handler.SetDefaultPaymentMethod)
+		v1.DELETE("/methods/:id", handler.DeletePaymentMethod)
+
 		// Seller wallet routes.
 		v1.GET("/wallet", handler.GetWalletBalance)
+		v1.PUT("/wallet/currency", handler.SetWalletCurrency)
 		v1.GET("/wallet/transactions", handler.ListWalletTransactions)
 
 		// Payout routes.
@@ -1,22 +1,74 @@
 package postgres
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/southern-martin/ecommerce/services/payment/internal/domain"
 )
 
+// ExchangeRatesJSON is a GORM-compatible JSONB type for ExchangeRates.
+type ExchangeRatesJSON domain.ExchangeRates
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (r ExchangeRatesJSON) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval. Payments
+// of orders placed before rates were locked have none.
+func (r *ExchangeRatesJSON) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan ExchangeRatesJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+// SellerItemsJSON is a GORM-compatible JSONB type for the sellers' shares
+// of a payment.
+type SellerItemsJSON []domain.OrderSellerItem
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (s SellerItemsJSON) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval.
+func (s *SellerItemsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan SellerItemsJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
 // PaymentModel is the GORM model for the payments table.
 type PaymentModel struct {
-	ID              string `gorm:"type:varchar(36);primaryKey"`
-	OrderID         string `gorm:"type:varchar(36);index;not null"`
-	BuyerID         string `gorm:"type:varchar(36);index;not null"`
-	AmountCents     int64  `gorm:"not null"`
-	Currency        string `gorm:"type:varchar(3);not null;default:'usd'"`
-	Status          string `gorm:"type:varchar(20);not null;default:'pending';index"`
-	Method          string `gorm:"type:varchar(20);not null;default:'card'"`
-	StripePaymentID string `gorm:"type:varchar(255);index"`
-	FailureReason   string `gorm:"type:text"`
+	ID              string            `gorm:"type:varchar(36);primaryKey"`
+	OrderID         string            `gorm:"type:varchar(36);index;not null"`
+	BuyerID         string            `gorm:"type:varchar(36);index;not null"`
+	AmountCents     int64             `gorm:"not null"`
+	Currency        string            `gorm:"type:varchar(3);not null;default:'usd'"`
+	Status          string            `gorm:"type:varchar(20);not null;default:'pending';index"`
+	Method          string            `gorm:"type:varchar(20);not null;default:'card'"`
+	StripePaymentID string            `gorm:"type:varchar(255);index"`
+	FailureReason   string            `gorm:"type:text"`
+	ExchangeRates   ExchangeRatesJSON `gorm:"type:jsonb"`
+	PaymentMethodID string            `gorm:"type:varchar(36)"`
+	SellerItems     SellerItemsJSON   `gorm:"type:jsonb"`
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
@@ -38,6 +90,9 @@ func (m *PaymentModel) ToDomain() *domain.Payment {
 		Method:          domain.PaymentMethod(m.Method),
 		StripePaymentID: m.StripePaymentID,
 		FailureReason:   m.FailureReason,
+		ExchangeRates:   domain.ExchangeRates(m.ExchangeRates),
+		PaymentMethodID: m.PaymentMethodID,
+		SellerItems:     []domain.OrderSellerItem(m.SellerItems),
 		CreatedAt:       m.CreatedAt,
 		UpdatedAt:       m.UpdatedAt,
 	}
@@ -55,11 +110,62 @@ func PaymentModelFromDomain(p *domain.Payment) *PaymentModel {
 		Method:          string(p.Method),
 		StripePaymentID: p.StripePaymentID,
 		FailureReason:   p.FailureReason,
+		ExchangeRates:   ExchangeRatesJSON(p.ExchangeRates),
+		PaymentMethodID: p.PaymentMethodID,
+		SellerItems:     SellerItemsJSON(p.SellerItems),
 		CreatedAt:       p.CreatedAt,
 		UpdatedAt:       p.UpdatedAt,
 	}
 }
 
+// SavedPaymentMethodModel is the GORM model for the saved_payment_methods table.
+type SavedPaymentMethodModel struct {
+	ID                    string `gorm:"type:varchar(36);primaryKey"`
+	BuyerID               string `gorm:"type:varchar(36);index;not null"`
+	StripePaymentMethodID string `gorm:"type:varchar(255);not null"`
+	Brand                 string `gorm:"type:varchar(30)"`
+	Last4                 string `gorm:"type:varchar(4)"`
+	ExpMonth              int
+	ExpYear               int
+	IsDefault             bool `gorm:"not null;default:false"`
+	CreatedAt             time.Time
+}
+
+// TableName returns the table name for SavedPaymentMethodModel.
+func (SavedPaymentMethodModel) TableName() string {
+	return "saved_payment_methods"
+}
+
+// ToDomain converts the GORM model to a domain entity.
+func (m *SavedPaymentMethodModel) ToDomain() *domain.SavedPaymentMethod {
+	return &domain.SavedPaymentMethod{
+		ID:                    m.ID,
+		BuyerID:               m.BuyerID,
+		StripePaymentMethodID: m.StripePaymentMethodID,
+		Brand:                 m.Brand,
+		Last4:                 m.Last4,
+		ExpMonth:              m.ExpMonth,
+		ExpYear:               m.ExpYear,
+		IsDefault:             m.IsDefault,
+		CreatedAt:             m.CreatedAt,
+	}
+}
+
+// SavedPaymentMethodModelFromDomain creates a GORM model from a domain entity.
+func SavedPaymentMethodModelFromDomain(pm *domain.SavedPaymentMethod) *SavedPaymentMethodModel {
+	return &SavedPaymentMethodModel{
+		ID:                    pm.ID,
+		BuyerID:               pm.BuyerID,
+		StripePaymentMethodID: pm.StripePaymentMethodID,
+		Brand:                 pm.Brand,
+		Last4:                 pm.Last4,
+		ExpMonth:              pm.ExpMonth,
+		ExpYear:               pm.ExpYear,
+		IsDefault:             pm.IsDefault,
+		CreatedAt:             pm.CreatedAt,
+	}
+}
+
 // SellerWalletModel is the GORM model for the seller_wallets table.
 type SellerWalletModel struct {
 	SellerID         string `gorm:"type:varchar(36);primaryKey"`
@@ -87,13 +193,15 @@ func (m *SellerWalletModel) ToDomain() *domain.SellerWallet {
 
 // WalletTransactionModel is the GORM model for the wallet_transactions table.
 type WalletTransactionModel struct {
-	ID            string `gorm:"type:varchar(36);primaryKey"`
-	SellerID      string `gorm:"type:varchar(36);index;not null"`
-	Type          string `gorm:"type:varchar(30);not null"`
-	AmountCents   int64  `gorm:"not null"`
-	ReferenceType string `gorm:"type:varchar(20)"`
-	ReferenceID   string `gorm:"type:varchar(36);index"`
-	Description   string `gorm:"type:text"`
+	ID            string  `gorm:"type:varchar(36);primaryKey"`
+	SellerID      string  `gorm:"type:varchar(36);index;not null"`
+	Type          string  `gorm:"type:varchar(30);not null"`
+	AmountCents   int64   `gorm:"not null"`
+	Currency      string  `gorm:"type:varchar(3);not null;default:'usd'"`
+	ExchangeRate  float64 `gorm:"type:numeric(20,10);not null;default:1"`
+	ReferenceType string  `gorm:"type:varchar(20)"`
+	ReferenceID   string  `gorm:"type:varchar(36);index"`
+	Description   string  `gorm:"type:text"`
 	CreatedAt     time.Time
 }
 
@@ -109,6 +217,8 @@ func (m *WalletTransactionModel) ToDomain() *domain.WalletTransaction {
 		SellerID:      m.SellerID,
 		Type:          domain.WalletTransactionType(m.Type),
 		AmountCents:   m.AmountCents,
+		Currency:      m.Currency,
+		ExchangeRate:  m.ExchangeRate,
 		ReferenceType: m.ReferenceType,
 		ReferenceID:   m.ReferenceID,
 		Description:   m.Description,
@@ -123,6 +233,8 @@ func WalletTransactionModelFromDomain(tx *domain.WalletTransaction) *WalletTrans
 		SellerID:      tx.SellerID,
 		Type:          string(tx.Type),
 		AmountCents:   tx.AmountCents,
+		Currency:      tx.Currency,
+		ExchangeRate:  tx.ExchangeRate,
 		ReferenceType: tx.ReferenceType,
 		ReferenceID:   tx.ReferenceID,
 		Description:   tx.Description,
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/southern-martin/ecommerce/services/payment/internal/domain"
+)
+
+// SavedPaymentMethodRepo implements domain.SavedPaymentMethodRepository
+// using PostgreSQL via GORM.
+type SavedPaymentMethodRepo struct {
+	db *gorm.DB
+}
+
+// NewSavedPaymentMethodRepo creates a new SavedPaymentMethodRepo.
+func NewSavedPaymentMethodRepo(db *gorm.DB) *SavedPaymentMethodRepo {
+	return &SavedPaymentMethodRepo{db: db}
+}
+
+// Create persists a new saved payment method.
+func (r *SavedPaymentMethodRepo) Create(ctx context.Context, method *domain.SavedPaymentMethod) error {
+	model := SavedPaymentMethodModelFromDomain(method)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return fmt.Errorf("failed to save payment method: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a saved payment method by its ID.
+func (r *SavedPaymentMethodRepo) GetByID(ctx context.Context, id string) (*domain.SavedPaymentMethod, error) {
+	var model SavedPaymentMethodModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, fmt.Errorf("payment method not found: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+// ListByBuyer retrieves a buyer's saved payment methods, the default first.
+func (r *SavedPaymentMethodRepo) ListByBuyer(ctx context.Context, buyerID string) ([]*domain.SavedPaymentMethod, error) {
+	var models []SavedPaymentMethodModel
+	if err := r.db.WithContext(ctx).
+		Where("buyer_id = ?", buyerID).
+		Order("is_default DESC, created_at DESC").
+		Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to list payment methods: %w", err)
+	}
+
+	methods := make([]*domain.SavedPaymentMethod, len(models))
+	for i, m := range models {
+		methods[i] = m.ToDomain()
+	}
+	return methods, nil
+}
+
+// SetDefault makes a method the buyer's default, unsetting the others.
+func (r *SavedPaymentMethodRepo) SetDefault(ctx context.Context, buyerID string, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&SavedPaymentMethodModel{}).
+			Where("buyer_id = ? AND id <> ?", buyerID, id).
+			Update("is_default", false).Error; err != nil {
+			return fmt.Errorf("failed to unset default payment method: %w", err)
+		}
+		result := tx.Model(&SavedPaymentMethodModel{}).
+			Where("buyer_id = ? AND id = ?", buyerID, id).
+			Update("is_default", true)
+		if result.Error != nil {
+			return fmt.Errorf("failed to set default payment method: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("payment method %s not found", id)
+		}
+		return nil
+	})
+}
+
+// Delete removes a saved payment method.
+func (r *SavedPaymentMethodRepo) Delete(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&SavedPaymentMethodModel{}).Error; err != nil {
+		return fmt.Errorf("failed to delete payment method: %w", err)
+	}
+	return nil
+}
+
+// Ensure SavedPaymentMethodRepo implements domain.SavedPaymentMethodRepository.
+var _ domain.SavedPaymentMethodRepository = (*SavedPaymentMethodRepo)(nil)
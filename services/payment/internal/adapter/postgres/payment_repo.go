@@ -76,6 +76,23 @@ func (r *PaymentRepo) UpdateStatus(ctx context.Context, id string, status domain
 	return nil
 }
 
+// RecordCharge records the gateway charge made for a payment and the saved
+// payment method it was made to.
+func (r *PaymentRepo) RecordCharge(ctx context.Context, id string, stripePaymentID string, paymentMethodID string) error {
+	result := r.db.WithContext(ctx).Model(&PaymentModel{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"stripe_payment_id": stripePaymentID,
+		"payment_method_id": paymentMethodID,
+		"updated_at":        time.Now(),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to record payment charge: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("payment %s not found", id)
+	}
+	return nil
+}
+
 // List retrieves a paginated list of payments for a buyer.
 func (r *PaymentRepo) List(ctx context.Context, buyerID string, page, pageSize int) ([]*domain.Payment, int64, error) {
 	var total int64
@@ -102,5 +119,31 @@ func (r *PaymentRepo) List(ctx context.Context, buyerID string, page, pageSize i
 	return payments, total, nil
 }
 
+// ListByStatus returns paginated payments in any of statuses, oldest first.
+func (r *PaymentRepo) ListByStatus(ctx context.Context, statuses []domain.PaymentStatus, page, pageSize int) ([]*domain.Payment, int64, error) {
+	values := make([]string, len(statuses))
+	for i, s := range statuses {
+		values[i] = string(s)
+	}
+
+	var total int64
+	query := r.db.WithContext(ctx).Model(&PaymentModel{}).Where("status IN ?", values)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count payments: %w", err)
+	}
+
+	var models []PaymentModel
+	offset := (page - 1) * pageSize
+	if err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(pageSize).Find(&models).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	payments := make([]*domain.Payment, len(models))
+	for i, m := range models {
+		payments[i] = m.ToDomain()
+	}
+	return payments, total, nil
+}
+
 // Ensure PaymentRepo implements domain.PaymentRepository.
 var _ domain.PaymentRepository = (*PaymentRepo)(nil)
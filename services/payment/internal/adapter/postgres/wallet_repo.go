@@ -56,6 +56,25 @@ func (r *WalletRepo) GetOrCreate(ctx context.Context, sellerID string) (*domain.
 	return model.ToDomain(), nil
 }
 
+// SetCurrency changes a seller's wallet currency while its balances are zero.
+func (r *WalletRepo) SetCurrency(ctx context.Context, sellerID string, currency string) (bool, error) {
+	// Ensure wallet exists.
+	if _, err := r.GetOrCreate(ctx, sellerID); err != nil {
+		return false, err
+	}
+
+	result := r.db.WithContext(ctx).Model(&SellerWalletModel{}).
+		Where("seller_id = ? AND available_balance = 0 AND pending_balance = 0", sellerID).
+		Updates(map[string]interface{}{
+			"currency":   currency,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to set wallet currency: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
 // CreditPending adds to a seller's pending balance.
 func (r *WalletRepo) CreditPending(ctx context.Context, sellerID string, amountCents int64) error {
 	// Ensure wallet exists.
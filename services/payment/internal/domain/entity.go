@@ -2,6 +2,15 @@ package domain
 
 import "time"
 
+// ExchangeRates is the snapshot of exchange rates locked when an order is
+// placed. Rates are relative to Base (1 Base = rate units of currency).
+type ExchangeRates struct {
+	Base     string             `json:"base"`
+	Rates    map[string]float64 `json:"rates"`
+	AsOf     time.Time          `json:"as_of"`
+	LockedAt time.Time          `json:"locked_at"`
+}
+
 // PaymentStatus represents the status of a payment.
 type PaymentStatus string
 
@@ -20,7 +29,10 @@ const (
 	PaymentMethodWallet PaymentMethod = "wallet"
 )
 
-// Payment represents a payment transaction.
+// Payment represents a payment transaction. Sellers are settled at the
+// exchange rates locked on the paid order. Payments charged off-session to
+// a saved payment method, such as subscription renewals, record the method
+// and the sellers' shares to credit once the charge succeeds.
 type Payment struct {
 	ID              string
 	OrderID         string
@@ -31,11 +43,29 @@ type Payment struct {
 	Method          PaymentMethod
 	StripePaymentID string
 	FailureReason   string
+	ExchangeRates   ExchangeRates
+	PaymentMethodID string
+	SellerItems     []OrderSellerItem
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
 }
 
-// SellerWallet represents a seller's wallet balance.
+// SavedPaymentMethod is a buyer's card saved with the payment gateway so
+// that it can be charged without the buyer present.
+type SavedPaymentMethod struct {
+	ID                    string
+	BuyerID               string
+	StripePaymentMethodID string
+	Brand                 string
+	Last4                 string
+	ExpMonth              int
+	ExpYear               int
+	IsDefault             bool
+	CreatedAt             time.Time
+}
+
+// SellerWallet represents a seller's wallet balance. Sales are credited in
+// the wallet's Currency.
 type SellerWallet struct {
 	SellerID         string
 	AvailableBalance int64
@@ -56,11 +86,15 @@ const (
 )
 
 // WalletTransaction represents a transaction in a seller's wallet.
+// ExchangeRate is the rate the amount was converted at from the buyer's
+// currency, 1 when no conversion was needed.
 type WalletTransaction struct {
 	ID            string
 	SellerID      string
 	Type          WalletTransactionType
 	AmountCents   int64 // positive = credit, negative = debit
+	Currency      string
+	ExchangeRate  float64
 	ReferenceType string
 	ReferenceID   string
 	Description   string
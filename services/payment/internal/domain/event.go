@@ -9,6 +9,10 @@ const (
 	EventPaymentFailed    = "payment.failed"
 	EventPaymentRefunded  = "payment.refunded"
 	EventOrderCreated     = "order.created"
+
+	// EventSubscriptionPaymentRetry asks for the failed payment of a
+	// subscription renewal order to be charged again.
+	EventSubscriptionPaymentRetry = "subscription.payment.retry"
 )
 
 // EventPublisher defines the interface for publishing domain events.
@@ -16,9 +20,11 @@ type EventPublisher interface {
 	Publish(ctx context.Context, subject string, data interface{}) error
 }
 
-// PaymentEvent represents a payment-related event payload.
+// PaymentEvent represents a payment-related event payload. Refund events
+// carry the ID of the refund.
 type PaymentEvent struct {
 	PaymentID   string `json:"payment_id"`
+	RefundID    string `json:"refund_id,omitempty"`
 	OrderID     string `json:"order_id"`
 	BuyerID     string `json:"buyer_id"`
 	AmountCents int64  `json:"amount_cents"`
@@ -27,16 +33,83 @@ type PaymentEvent struct {
 }
 
 // OrderCreatedEvent represents the payload from an order.created event.
+// Orders with a PaymentMethodID, such as subscription renewals, are charged
+// to that saved payment method straight away.
 type OrderCreatedEvent struct {
-	OrderID     string             `json:"order_id"`
-	BuyerID     string             `json:"buyer_id"`
-	AmountCents int64              `json:"amount_cents"`
-	Currency    string             `json:"currency"`
-	SellerItems []OrderSellerItem  `json:"seller_items"`
+	OrderID            string             `json:"order_id"`
+	BuyerID            string             `json:"buyer_id"`
+	AmountCents        int64              `json:"total_cents"`
+	Currency           string             `json:"currency"`
+	SettlementCurrency string             `json:"settlement_currency"`
+	ExchangeRates      ExchangeRates      `json:"exchange_rates"`
+	SellerItems        []OrderSellerItem  `json:"seller_items"`
+	SellerOrders       []OrderSellerEvent `json:"seller_orders"`
+	Items              []OrderItemEvent   `json:"items"`
+	PaymentMethodID    string             `json:"payment_method_id,omitempty"`
+}
+
+// OrderSellerEvent represents a seller order in an order.created payload.
+// SettlementSubtotalCents is the seller's share of the order's settlement
+// total, locked when the order was placed.
+type OrderSellerEvent struct {
+	SellerID                string `json:"seller_id"`
+	SubtotalCents           int64  `json:"subtotal_cents"`
+	DiscountCents           int64  `json:"discount_cents"`
+	SettlementSubtotalCents int64  `json:"settlement_subtotal_cents"`
+}
+
+// OrderItemEvent represents an order item in an order.created payload.
+type OrderItemEvent struct {
+	Quantity       int    `json:"quantity"`
+	UnitPriceCents int64  `json:"unit_price_cents"`
+	SellerID       string `json:"seller_id"`
 }
 
-// OrderSellerItem represents a seller's portion of an order.
+// SellerAmounts returns each seller's portion of the order, from the
+// seller items when the event has them, from its seller orders, with their
+// settlement shares, when it has those, and from its items otherwise.
+func (e OrderCreatedEvent) SellerAmounts() []OrderSellerItem {
+	if len(e.SellerItems) > 0 {
+		return e.SellerItems
+	}
+	var sellerItems []OrderSellerItem
+	if len(e.SellerOrders) > 0 && e.SettlementCurrency != "" {
+		for _, sellerOrder := range e.SellerOrders {
+			sellerItems = append(sellerItems, OrderSellerItem{
+				SellerID:              sellerOrder.SellerID,
+				AmountCents:           sellerOrder.SubtotalCents - sellerOrder.DiscountCents,
+				SettlementCurrency:    e.SettlementCurrency,
+				SettlementAmountCents: sellerOrder.SettlementSubtotalCents,
+			})
+		}
+		return sellerItems
+	}
+	index := make(map[string]int)
+	for _, item := range e.Items {
+		amount := item.UnitPriceCents * int64(item.Quantity)
+		if i, ok := index[item.SellerID]; ok {
+			sellerItems[i].AmountCents += amount
+			continue
+		}
+		index[item.SellerID] = len(sellerItems)
+		sellerItems = append(sellerItems, OrderSellerItem{SellerID: item.SellerID, AmountCents: amount})
+	}
+	return sellerItems
+}
+
+// PaymentRetryEvent is the payload of subscription.payment.retry. A
+// PaymentMethodID replaces the saved payment method charged before.
+type PaymentRetryEvent struct {
+	OrderID         string `json:"order_id"`
+	PaymentMethodID string `json:"payment_method_id"`
+}
+
+// OrderSellerItem represents a seller's portion of an order, in the
+// payment's currency and, for orders that locked settlement shares, its
+// share of the order's settlement total.
 type OrderSellerItem struct {
-	SellerID    string `json:"seller_id"`
-	AmountCents int64  `json:"amount_cents"`
+	SellerID              string `json:"seller_id"`
+	AmountCents           int64  `json:"amount_cents"`
+	SettlementCurrency    string `json:"settlement_currency,omitempty"`
+	SettlementAmountCents int64  `json:"settlement_amount_cents,omitempty"`
 }
@@ -9,12 +9,31 @@ type PaymentRepository interface {
 	GetByOrderID(ctx context.Context, orderID string) (*Payment, error)
 	GetByStripeID(ctx context.Context, stripePaymentID string) (*Payment, error)
 	UpdateStatus(ctx context.Context, id string, status PaymentStatus, failureReason string) error
+	// RecordCharge records the gateway charge made for a payment and the
+	// saved payment method it was made to.
+	RecordCharge(ctx context.Context, id string, stripePaymentID string, paymentMethodID string) error
 	List(ctx context.Context, buyerID string, page, pageSize int) ([]*Payment, int64, error)
+	// ListByStatus lists payments in any of statuses, oldest first.
+	ListByStatus(ctx context.Context, statuses []PaymentStatus, page, pageSize int) ([]*Payment, int64, error)
+}
+
+// SavedPaymentMethodRepository defines the interface for saved payment
+// method persistence.
+type SavedPaymentMethodRepository interface {
+	Create(ctx context.Context, method *SavedPaymentMethod) error
+	GetByID(ctx context.Context, id string) (*SavedPaymentMethod, error)
+	ListByBuyer(ctx context.Context, buyerID string) ([]*SavedPaymentMethod, error)
+	// SetDefault makes a method the buyer's default, unsetting the others.
+	SetDefault(ctx context.Context, buyerID string, id string) error
+	Delete(ctx context.Context, id string) error
 }
 
 // WalletRepository defines the interface for seller wallet persistence.
 type WalletRepository interface {
 	GetOrCreate(ctx context.Context, sellerID string) (*SellerWallet, error)
+	// SetCurrency changes the wallet's currency, only while its balances
+	// are zero. It reports whether the currency was changed.
+	SetCurrency(ctx context.Context, sellerID string, currency string) (bool, error)
 	CreditPending(ctx context.Context, sellerID string, amountCents int64) error
 	MovePendingToAvailable(ctx context.Context, sellerID string, amountCents int64) error
 	DebitAvailable(ctx context.Context, sellerID string, amountCents int64) error
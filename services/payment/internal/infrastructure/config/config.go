@@ -16,6 +16,12 @@ type Config struct {
 	StripeSecretKey        string
 	StripeWebhookSecret    string
 	PlatformCommissionRate float64
+
+	// ExchangeRatesSource is a JSON rates file path or URL used to convert
+	// sales into seller wallet currencies; when empty the converter's
+	// built-in rates are used.
+	ExchangeRatesSource         string
+	ExchangeRatesRefreshMinutes int
 }
 
 // Load reads configuration from environment variables.
@@ -26,6 +32,12 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid PLATFORM_COMMISSION_RATE: %w", err)
 	}
 
+	refreshStr := getEnv("EXCHANGE_RATES_REFRESH_MINUTES", "60")
+	refreshMinutes, err := strconv.Atoi(refreshStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXCHANGE_RATES_REFRESH_MINUTES: %w", err)
+	}
+
 	pgHost := getEnv("POSTGRES_HOST", "localhost")
 	pgPort := getEnv("POSTGRES_PORT", "5432")
 	pgUser := getEnv("POSTGRES_USER", "postgres")
@@ -46,6 +58,9 @@ func Load() (*Config, error) {
 		StripeSecretKey:        getEnv("STRIPE_SECRET_KEY", "sk_test_mock"),
 		StripeWebhookSecret:    getEnv("STRIPE_WEBHOOK_SECRET", "whsec_mock"),
 		PlatformCommissionRate: commissionRate,
+
+		ExchangeRatesSource:         getEnv("EXCHANGE_RATES_SOURCE", ""),
+		ExchangeRatesRefreshMinutes: refreshMinutes,
 	}, nil
 }
 
@@ -2,6 +2,7 @@ package stripe
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -17,6 +18,20 @@ type StripeClient interface {
 	CreateRefund(paymentIntentID string, amountCents int64) (string, error)
 	// CreateTransfer creates a transfer to a connected account and returns (transferID, error).
 	CreateTransfer(amountCents int64, destinationAccountID string, metadata map[string]string) (string, error)
+	// GetPaymentMethod returns the card details of a payment method.
+	GetPaymentMethod(paymentMethodID string) (*PaymentMethodDetails, error)
+	// ChargePaymentMethod charges a saved payment method without the
+	// customer present and returns (paymentIntentID, error). Declined
+	// charges return an error.
+	ChargePaymentMethod(amountCents int64, currency string, paymentMethodID string, metadata map[string]string) (string, error)
+}
+
+// PaymentMethodDetails holds the card details of a payment method.
+type PaymentMethodDetails struct {
+	Brand    string
+	Last4    string
+	ExpMonth int
+	ExpYear  int
 }
 
 // MockStripeClient is used for development without actual Stripe integration.
@@ -70,5 +85,34 @@ func (m *MockStripeClient) CreateTransfer(amountCents int64, destinationAccountI
 	return transferID, nil
 }
 
+// GetPaymentMethod returns mock card details.
+func (m *MockStripeClient) GetPaymentMethod(paymentMethodID string) (*PaymentMethodDetails, error) {
+	if !strings.HasPrefix(paymentMethodID, "pm_") {
+		return nil, fmt.Errorf("no such payment method: %s", paymentMethodID)
+	}
+	return &PaymentMethodDetails{
+		Brand:    "visa",
+		Last4:    "4242",
+		ExpMonth: 12,
+		ExpYear:  2030,
+	}, nil
+}
+
+// ChargePaymentMethod creates a mock off-session charge. Like Stripe's test
+// payment methods, methods whose ID contains "chargeDeclined" are declined.
+func (m *MockStripeClient) ChargePaymentMethod(amountCents int64, currency string, paymentMethodID string, metadata map[string]string) (string, error) {
+	if strings.Contains(paymentMethodID, "chargeDeclined") {
+		return "", fmt.Errorf("your card was declined")
+	}
+	id := "pi_mock_" + uuid.New().String()[:8]
+	log.Debug().
+		Str("payment_intent_id", id).
+		Str("payment_method_id", paymentMethodID).
+		Int64("amount_cents", amountCents).
+		Str("currency", currency).
+		Msg("Mock: Charged payment method")
+	return id, nil
+}
+
 // Ensure MockStripeClient implements StripeClient.
 var _ StripeClient = (*MockStripeClient)(nil)
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/payment/internal/domain"
+	"github.com/southern-martin/ecommerce/services/payment/internal/infrastructure/stripe"
+)
+
+// ChargeUseCase charges payments to buyers' saved payment methods without
+// them present. Successful and declined charges are settled like webhook
+// confirmations, so a decline publishes payment.failed for dunning.
+type ChargeUseCase struct {
+	paymentRepo domain.PaymentRepository
+	methodRepo  domain.SavedPaymentMethodRepository
+	stripe      stripe.StripeClient
+	confirm     *ConfirmPaymentUseCase
+}
+
+// NewChargeUseCase creates a new ChargeUseCase.
+func NewChargeUseCase(
+	paymentRepo domain.PaymentRepository,
+	methodRepo domain.SavedPaymentMethodRepository,
+	stripeClient stripe.StripeClient,
+	confirm *ConfirmPaymentUseCase,
+) *ChargeUseCase {
+	return &ChargeUseCase{
+		paymentRepo: paymentRepo,
+		methodRepo:  methodRepo,
+		stripe:      stripeClient,
+		confirm:     confirm,
+	}
+}
+
+// Charge charges a payment to one of its buyer's saved payment methods.
+func (uc *ChargeUseCase) Charge(ctx context.Context, payment *domain.Payment, paymentMethodID string) error {
+	if payment.Status == domain.PaymentStatusCompleted || payment.Status == domain.PaymentStatusRefunded {
+		return nil
+	}
+
+	method, err := uc.methodRepo.GetByID(ctx, paymentMethodID)
+	if err != nil || method.BuyerID != payment.BuyerID {
+		return uc.confirm.handleFailure(ctx, payment, "saved payment method not found")
+	}
+
+	metadata := map[string]string{
+		"payment_id": payment.ID,
+		"order_id":   payment.OrderID,
+		"buyer_id":   payment.BuyerID,
+	}
+	stripeID, err := uc.stripe.ChargePaymentMethod(payment.AmountCents, payment.Currency, method.StripePaymentMethodID, metadata)
+	if err != nil {
+		log.Warn().Err(err).Str("payment_id", payment.ID).Msg("Off-session charge declined")
+		if recErr := uc.paymentRepo.RecordCharge(ctx, payment.ID, "", method.ID); recErr != nil {
+			log.Error().Err(recErr).Str("payment_id", payment.ID).Msg("Failed to record declined charge")
+		}
+		return uc.confirm.handleFailure(ctx, payment, err.Error())
+	}
+
+	if err := uc.paymentRepo.RecordCharge(ctx, payment.ID, stripeID, method.ID); err != nil {
+		return err
+	}
+	payment.StripePaymentID = stripeID
+	payment.PaymentMethodID = method.ID
+	return uc.confirm.handleSuccess(ctx, payment, payment.SellerItems)
+}
+
+// Retry charges the failed payment of an order again, to paymentMethodID
+// or, when it is empty, to the method charged before.
+func (uc *ChargeUseCase) Retry(ctx context.Context, orderID string, paymentMethodID string) error {
+	payment, err := uc.paymentRepo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if payment.Status != domain.PaymentStatusFailed {
+		return fmt.Errorf("payment of order %s is %s, not failed", orderID, payment.Status)
+	}
+	if paymentMethodID == "" {
+		paymentMethodID = payment.PaymentMethodID
+	}
+	return uc.Charge(ctx, payment, paymentMethodID)
+}
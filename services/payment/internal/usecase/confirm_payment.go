@@ -3,12 +3,12 @@ package usecase
 import (
 	"context"
 	"fmt"
-	"math"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	"github.com/southern-martin/ecommerce/services/payment/internal/domain"
 )
 
@@ -27,6 +27,7 @@ type ConfirmPaymentUseCase struct {
 	paymentRepo    domain.PaymentRepository
 	walletRepo     domain.WalletRepository
 	publisher      domain.EventPublisher
+	converter      *currency.Converter
 	commissionRate float64
 }
 
@@ -35,12 +36,14 @@ func NewConfirmPaymentUseCase(
 	paymentRepo domain.PaymentRepository,
 	walletRepo domain.WalletRepository,
 	publisher domain.EventPublisher,
+	converter *currency.Converter,
 	commissionRate float64,
 ) *ConfirmPaymentUseCase {
 	return &ConfirmPaymentUseCase{
 		paymentRepo:    paymentRepo,
 		walletRepo:     walletRepo,
 		publisher:      publisher,
+		converter:      converter,
 		commissionRate: commissionRate,
 	}
 }
@@ -64,14 +67,35 @@ func (uc *ConfirmPaymentUseCase) Execute(ctx context.Context, event WebhookEvent
 }
 
 func (uc *ConfirmPaymentUseCase) handleSuccess(ctx context.Context, payment *domain.Payment, sellerItems []domain.OrderSellerItem) error {
+	// Sellers are credited once, however often success is reported.
+	if payment.Status == domain.PaymentStatusCompleted {
+		return nil
+	}
+
 	if err := uc.paymentRepo.UpdateStatus(ctx, payment.ID, domain.PaymentStatusCompleted, ""); err != nil {
 		return fmt.Errorf("failed to update payment status: %w", err)
 	}
 
-	// Credit seller wallets with platform commission deducted.
+	// Credit seller wallets with platform commission deducted, converting
+	// each seller's locked settlement share into the wallet's currency at
+	// the rates locked on the order.
 	for _, item := range sellerItems {
-		commission := int64(math.Round(float64(item.AmountCents) * uc.commissionRate))
-		sellerAmount := item.AmountCents - commission
+		wallet, err := uc.walletRepo.GetOrCreate(ctx, item.SellerID)
+		if err != nil {
+			log.Error().Err(err).Str("seller_id", item.SellerID).Msg("Failed to get seller wallet")
+			continue
+		}
+		amount, rate, err := settleSale(uc.converter, payment, item, wallet.Currency)
+		if err != nil {
+			log.Error().Err(err).Str("seller_id", item.SellerID).Msg("Failed to convert sale into wallet currency")
+			continue
+		}
+
+		sellerAmount, commission, err := splitCommission(amount, wallet.Currency, uc.commissionRate)
+		if err != nil {
+			log.Error().Err(err).Str("seller_id", item.SellerID).Msg("Failed to split sale commission")
+			continue
+		}
 
 		// Credit seller's pending balance.
 		if err := uc.walletRepo.CreditPending(ctx, item.SellerID, sellerAmount); err != nil {
@@ -85,6 +109,8 @@ func (uc *ConfirmPaymentUseCase) handleSuccess(ctx context.Context, payment *dom
 			SellerID:      item.SellerID,
 			Type:          domain.WalletTxSale,
 			AmountCents:   sellerAmount,
+			Currency:      wallet.Currency,
+			ExchangeRate:  rate,
 			ReferenceType: "order",
 			ReferenceID:   payment.OrderID,
 			Description:   fmt.Sprintf("Sale from order %s", payment.OrderID),
@@ -100,6 +126,8 @@ func (uc *ConfirmPaymentUseCase) handleSuccess(ctx context.Context, payment *dom
 			SellerID:      item.SellerID,
 			Type:          domain.WalletTxCommissionDeducted,
 			AmountCents:   -commission,
+			Currency:      wallet.Currency,
+			ExchangeRate:  rate,
 			ReferenceType: "order",
 			ReferenceID:   payment.OrderID,
 			Description:   fmt.Sprintf("Platform commission (%.0f%%) for order %s", uc.commissionRate*100, payment.OrderID),
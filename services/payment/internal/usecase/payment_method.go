@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/southern-martin/ecommerce/services/payment/internal/domain"
+	"github.com/southern-martin/ecommerce/services/payment/internal/infrastructure/stripe"
+)
+
+// SavePaymentMethodInput holds the input for saving a payment method.
+type SavePaymentMethodInput struct {
+	StripePaymentMethodID string `json:"stripe_payment_method_id" binding:"required"`
+	MakeDefault           bool   `json:"make_default"`
+}
+
+// PaymentMethodUseCase manages the payment methods buyers save for charges
+// made without them present, such as subscription renewals.
+type PaymentMethodUseCase struct {
+	methodRepo domain.SavedPaymentMethodRepository
+	stripe     stripe.StripeClient
+}
+
+// NewPaymentMethodUseCase creates a new PaymentMethodUseCase.
+func NewPaymentMethodUseCase(methodRepo domain.SavedPaymentMethodRepository, stripeClient stripe.StripeClient) *PaymentMethodUseCase {
+	return &PaymentMethodUseCase{
+		methodRepo: methodRepo,
+		stripe:     stripeClient,
+	}
+}
+
+// Save saves a payment method tokenized by the gateway for a buyer. A
+// buyer's first payment method becomes their default.
+func (uc *PaymentMethodUseCase) Save(ctx context.Context, buyerID string, input SavePaymentMethodInput) (*domain.SavedPaymentMethod, error) {
+	details, err := uc.stripe.GetPaymentMethod(input.StripePaymentMethodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve payment method: %w", err)
+	}
+
+	existing, err := uc.methodRepo.ListByBuyer(ctx, buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	method := &domain.SavedPaymentMethod{
+		ID:                    uuid.New().String(),
+		BuyerID:               buyerID,
+		StripePaymentMethodID: input.StripePaymentMethodID,
+		Brand:                 details.Brand,
+		Last4:                 details.Last4,
+		ExpMonth:              details.ExpMonth,
+		ExpYear:               details.ExpYear,
+		CreatedAt:             time.Now(),
+	}
+	if err := uc.methodRepo.Create(ctx, method); err != nil {
+		return nil, err
+	}
+
+	if input.MakeDefault || len(existing) == 0 {
+		if err := uc.methodRepo.SetDefault(ctx, buyerID, method.ID); err != nil {
+			return nil, err
+		}
+		method.IsDefault = true
+	}
+	return method, nil
+}
+
+// List returns a buyer's saved payment methods.
+func (uc *PaymentMethodUseCase) List(ctx context.Context, buyerID string) ([]*domain.SavedPaymentMethod, error) {
+	return uc.methodRepo.ListByBuyer(ctx, buyerID)
+}
+
+// SetDefault makes one of a buyer's payment methods their default.
+func (uc *PaymentMethodUseCase) SetDefault(ctx context.Context, buyerID string, id string) error {
+	if _, err := uc.buyerMethod(ctx, buyerID, id); err != nil {
+		return err
+	}
+	return uc.methodRepo.SetDefault(ctx, buyerID, id)
+}
+
+// Delete removes one of a buyer's payment methods. Renewals charged to it
+// fail and go through dunning until another method is chosen.
+func (uc *PaymentMethodUseCase) Delete(ctx context.Context, buyerID string, id string) error {
+	if _, err := uc.buyerMethod(ctx, buyerID, id); err != nil {
+		return err
+	}
+	return uc.methodRepo.Delete(ctx, id)
+}
+
+func (uc *PaymentMethodUseCase) buyerMethod(ctx context.Context, buyerID string, id string) (*domain.SavedPaymentMethod, error) {
+	method, err := uc.methodRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if method.BuyerID != buyerID {
+		return nil, fmt.Errorf("payment method does not belong to this buyer")
+	}
+	return method, nil
+}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -42,9 +43,6 @@ func NewPayoutUseCase(
 
 // RequestPayout creates a new payout request.
 func (uc *PayoutUseCase) RequestPayout(ctx context.Context, input RequestPayoutInput) (*domain.Payout, error) {
-	if input.Currency == "" {
-		input.Currency = "usd"
-	}
 	if input.Method == "" {
 		input.Method = "stripe_connect"
 	}
@@ -55,6 +53,14 @@ func (uc *PayoutUseCase) RequestPayout(ctx context.Context, input RequestPayoutI
 		return nil, fmt.Errorf("failed to get wallet: %w", err)
 	}
 
+	// Payouts are made in the wallet's currency.
+	if input.Currency == "" {
+		input.Currency = wallet.Currency
+	}
+	if !strings.EqualFold(input.Currency, wallet.Currency) {
+		return nil, fmt.Errorf("payout currency %s does not match wallet currency %s", input.Currency, wallet.Currency)
+	}
+
 	if wallet.AvailableBalance < input.AmountCents {
 		return nil, fmt.Errorf("insufficient available balance: have %d, need %d", wallet.AvailableBalance, input.AmountCents)
 	}
@@ -71,6 +77,8 @@ func (uc *PayoutUseCase) RequestPayout(ctx context.Context, input RequestPayoutI
 		SellerID:      input.SellerID,
 		Type:          domain.WalletTxPayout,
 		AmountCents:   -input.AmountCents,
+		Currency:      wallet.Currency,
+		ExchangeRate:  1,
 		ReferenceType: "payout",
 		ReferenceID:   payoutID,
 		Description:   fmt.Sprintf("Payout request %s", payoutID),
@@ -84,7 +92,7 @@ func (uc *PayoutUseCase) RequestPayout(ctx context.Context, input RequestPayoutI
 		ID:          payoutID,
 		SellerID:    input.SellerID,
 		AmountCents: input.AmountCents,
-		Currency:    input.Currency,
+		Currency:    wallet.Currency,
 		Method:      input.Method,
 		Status:      domain.PayoutStatusRequested,
 		RequestedAt: time.Now(),
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	"github.com/southern-martin/ecommerce/services/payment/internal/domain"
 	"github.com/southern-martin/ecommerce/services/payment/internal/infrastructure/stripe"
 )
@@ -18,6 +19,7 @@ type RefundUseCase struct {
 	walletRepo  domain.WalletRepository
 	stripe      stripe.StripeClient
 	publisher   domain.EventPublisher
+	converter   *currency.Converter
 }
 
 // NewRefundUseCase creates a new RefundUseCase.
@@ -26,12 +28,14 @@ func NewRefundUseCase(
 	walletRepo domain.WalletRepository,
 	stripeClient stripe.StripeClient,
 	publisher domain.EventPublisher,
+	converter *currency.Converter,
 ) *RefundUseCase {
 	return &RefundUseCase{
 		paymentRepo: paymentRepo,
 		walletRepo:  walletRepo,
 		stripe:      stripeClient,
 		publisher:   publisher,
+		converter:   converter,
 	}
 }
 
@@ -79,30 +83,15 @@ func (uc *RefundUseCase) ProcessRefund(ctx context.Context, input RefundInput) e
 		return fmt.Errorf("failed to update payment status: %w", err)
 	}
 
-	// Debit seller wallet if seller ID is provided.
+	// Debit seller wallet if seller ID is provided, in the wallet's currency.
 	if input.SellerID != "" {
-		if err := uc.walletRepo.DebitAvailable(ctx, input.SellerID, refundAmount); err != nil {
-			log.Error().Err(err).Str("seller_id", input.SellerID).Msg("Failed to debit seller wallet for refund")
-		}
-
-		tx := &domain.WalletTransaction{
-			ID:            uuid.New().String(),
-			SellerID:      input.SellerID,
-			Type:          domain.WalletTxRefundDebit,
-			AmountCents:   -refundAmount,
-			ReferenceType: "refund",
-			ReferenceID:   payment.OrderID,
-			Description:   fmt.Sprintf("Refund for order %s", payment.OrderID),
-			CreatedAt:     time.Now(),
-		}
-		if err := uc.walletRepo.CreateTransaction(ctx, tx); err != nil {
-			log.Error().Err(err).Msg("Failed to create refund wallet transaction")
-		}
+		uc.debitSeller(ctx, payment, input.SellerID, refundAmount)
 	}
 
 	// Publish payment.refunded event.
 	evt := domain.PaymentEvent{
 		PaymentID:   payment.ID,
+		RefundID:    refundID,
 		OrderID:     payment.OrderID,
 		BuyerID:     payment.BuyerID,
 		AmountCents: refundAmount,
@@ -115,3 +104,38 @@ func (uc *RefundUseCase) ProcessRefund(ctx context.Context, input RefundInput) e
 
 	return nil
 }
+
+// debitSeller debits a refund from a seller's wallet, converted into the
+// wallet's currency at the rates the order was settled at.
+func (uc *RefundUseCase) debitSeller(ctx context.Context, payment *domain.Payment, sellerID string, refundAmount int64) {
+	wallet, err := uc.walletRepo.GetOrCreate(ctx, sellerID)
+	if err != nil {
+		log.Error().Err(err).Str("seller_id", sellerID).Msg("Failed to get seller wallet for refund")
+		return
+	}
+	amount, rate, err := settleAmount(uc.converter, payment, refundAmount, wallet.Currency)
+	if err != nil {
+		log.Error().Err(err).Str("seller_id", sellerID).Msg("Failed to convert refund into wallet currency")
+		return
+	}
+
+	if err := uc.walletRepo.DebitAvailable(ctx, sellerID, amount); err != nil {
+		log.Error().Err(err).Str("seller_id", sellerID).Msg("Failed to debit seller wallet for refund")
+	}
+
+	tx := &domain.WalletTransaction{
+		ID:            uuid.New().String(),
+		SellerID:      sellerID,
+		Type:          domain.WalletTxRefundDebit,
+		AmountCents:   -amount,
+		Currency:      wallet.Currency,
+		ExchangeRate:  rate,
+		ReferenceType: "refund",
+		ReferenceID:   payment.OrderID,
+		Description:   fmt.Sprintf("Refund for order %s", payment.OrderID),
+		CreatedAt:     time.Now(),
+	}
+	if err := uc.walletRepo.CreateTransaction(ctx, tx); err != nil {
+		log.Error().Err(err).Msg("Failed to create refund wallet transaction")
+	}
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"math"
+
+	"github.com/southern-martin/ecommerce/pkg/currency"
+	"github.com/southern-martin/ecommerce/pkg/money"
+	"github.com/southern-martin/ecommerce/services/payment/internal/domain"
+)
+
+// basisPoints is the precision commission rates are applied at.
+const basisPoints = 10000
+
+// settlementRates returns the exchange rates a payment is settled at: the
+// rates locked on its order, or the converter's current rates for payments
+// of orders placed before rates were locked.
+func settlementRates(converter *currency.Converter, payment *domain.Payment) currency.RateSnapshot {
+	if len(payment.ExchangeRates.Rates) == 0 {
+		return converter.Snapshot()
+	}
+	return currency.RateSnapshot{
+		Base:  payment.ExchangeRates.Base,
+		Rates: payment.ExchangeRates.Rates,
+		AsOf:  payment.ExchangeRates.AsOf,
+	}
+}
+
+// settleAmount converts an amount of a payment into a seller wallet's
+// currency. It returns the converted amount and the rate applied.
+func settleAmount(converter *currency.Converter, payment *domain.Payment, amountCents int64, walletCurrency string) (int64, float64, error) {
+	return convertAmount(converter, payment, amountCents, payment.Currency, walletCurrency)
+}
+
+// settleSale returns a seller's sale in a payment in the seller wallet's
+// currency: the seller's share of the order's settlement total, locked
+// when the order was placed, or for orders placed before shares were
+// locked, the seller's amount of the payment. It returns the amount and
+// the rate applied.
+func settleSale(converter *currency.Converter, payment *domain.Payment, item domain.OrderSellerItem, walletCurrency string) (int64, float64, error) {
+	if item.SettlementCurrency != "" {
+		return convertAmount(converter, payment, item.SettlementAmountCents, item.SettlementCurrency, walletCurrency)
+	}
+	return settleAmount(converter, payment, item.AmountCents, walletCurrency)
+}
+
+// convertAmount converts an amount from one currency into a seller wallet's
+// currency at the rates a payment is settled at.
+func convertAmount(converter *currency.Converter, payment *domain.Payment, amountCents int64, fromCurrency, walletCurrency string) (int64, float64, error) {
+	from := currency.Normalize(fromCurrency)
+	to := currency.Normalize(walletCurrency)
+	if from == to {
+		return amountCents, 1, nil
+	}
+
+	rates := settlementRates(converter, payment)
+	rate, err := rates.Rate(from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+	settled, err := rates.Convert(amountCents, from, to)
+	if err != nil {
+		return 0, 0, err
+	}
+	return settled, rate, nil
+}
+
+// splitCommission splits a sale between the seller and the platform
+// commission. The two parts always add up to the sale.
+func splitCommission(amountCents int64, walletCurrency string, commissionRate float64) (int64, int64, error) {
+	commissionBps := int64(math.Round(commissionRate * basisPoints))
+	parts, err := money.NewMoney(amountCents, walletCurrency).Allocate(basisPoints-commissionBps, commissionBps)
+	if err != nil {
+		return 0, 0, err
+	}
+	return parts[0].AmountCents, parts[1].AmountCents, nil
+}
@@ -3,7 +3,9 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/southern-martin/ecommerce/pkg/currency"
 	"github.com/southern-martin/ecommerce/services/payment/internal/domain"
 )
 
@@ -26,6 +28,25 @@ func (uc *WalletUseCase) GetBalance(ctx context.Context, sellerID string) (*doma
 	return wallet, nil
 }
 
+// SetCurrency sets the currency a seller's wallet is credited and paid out
+// in. Sales in other currencies are converted into it. The currency can only
+// be changed while the wallet's balances are zero.
+func (uc *WalletUseCase) SetCurrency(ctx context.Context, sellerID string, code string) (*domain.SellerWallet, error) {
+	code = currency.Normalize(code)
+	if !currency.IsSupported(code) {
+		return nil, fmt.Errorf("unsupported currency: %s", code)
+	}
+
+	ok, err := uc.walletRepo.SetCurrency(ctx, sellerID, strings.ToLower(code))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set wallet currency: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("wallet currency can only be changed while its balances are zero")
+	}
+	return uc.GetBalance(ctx, sellerID)
+}
+
 // ListTransactions returns wallet transactions for a seller.
 func (uc *WalletUseCase) ListTransactions(ctx context.Context, sellerID string, page, pageSize int) ([]*domain.WalletTransaction, int64, error) {
 	if page < 1 {
@@ -12,12 +12,16 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"github.com/southern-martin/ecommerce/pkg/currency"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 	"github.com/southern-martin/ecommerce/services/product/internal/adapter/grpc"
 	producthttp "github.com/southern-martin/ecommerce/services/product/internal/adapter/http"
 	"github.com/southern-martin/ecommerce/services/product/internal/adapter/postgres"
 	"github.com/southern-martin/ecommerce/services/product/internal/infrastructure/config"
 	"github.com/southern-martin/ecommerce/services/product/internal/infrastructure/database"
 	natspub "github.com/southern-martin/ecommerce/services/product/internal/infrastructure/nats"
+	"github.com/southern-martin/ecommerce/services/product/internal/infrastructure/review"
+	"github.com/southern-martin/ecommerce/services/product/internal/infrastructure/scheduler"
 	"github.com/southern-martin/ecommerce/services/product/internal/usecase"
 )
 
@@ -52,6 +56,21 @@ func main() {
 		&postgres.ProductOptionValueModel{},
 		&postgres.VariantModel{},
 		&postgres.VariantOptionValueModel{},
+		&postgres.ImportJobModel{},
+		&postgres.FeedItemModel{},
+		&postgres.FeedCategoryMappingModel{},
+		&postgres.ModerationReviewModel{},
+		&postgres.ProhibitedKeywordModel{},
+		&postgres.PriceHistoryModel{},
+		&postgres.PriceScheduleModel{},
+		&postgres.DigitalFileModel{},
+		&postgres.LicenseKeyModel{},
+		&postgres.DigitalEntitlementModel{},
+		&postgres.ProductTranslationModel{},
+		&postgres.CategoryTranslationModel{},
+		&postgres.OptionValueTranslationModel{},
+		&postgres.VariantPriceModel{},
+		&postgres.SubscriptionPlanModel{},
 	); err != nil {
 		log.Fatal().Err(err).Msg("Failed to auto-migrate database")
 	}
@@ -69,16 +88,97 @@ func main() {
 	attributeRepo := postgres.NewAttributeRepo(db)
 	optionRepo := postgres.NewOptionRepo(db)
 	variantRepo := postgres.NewVariantRepo(db)
+	importJobRepo := postgres.NewImportJobRepo(db)
+	feedItemRepo := postgres.NewFeedItemRepo(db)
+	feedCategoryRepo := postgres.NewFeedCategoryMappingRepo(db)
+	moderationReviewRepo := postgres.NewModerationReviewRepo(db)
+	keywordRepo := postgres.NewProhibitedKeywordRepo(db)
+	priceHistoryRepo := postgres.NewPriceHistoryRepo(db)
+	priceScheduleRepo := postgres.NewPriceScheduleRepo(db)
+	digitalFileRepo := postgres.NewDigitalFileRepo(db)
+	licenseKeyRepo := postgres.NewLicenseKeyRepo(db)
+	entitlementRepo := postgres.NewDigitalEntitlementRepo(db)
+	translationRepo := postgres.NewTranslationRepo(db)
+	variantPriceRepo := postgres.NewVariantPriceRepo(db)
+	subscriptionPlanRepo := postgres.NewSubscriptionPlanRepo(db)
+
+	// Languages catalog content can be translated into and served in
+	bundle := i18n.NewBundle()
+	bundle.SetupDefaults()
 
 	// Initialize use cases
-	productUC := usecase.NewProductUseCase(productRepo, categoryRepo, attributeRepo, optionRepo, variantRepo, publisher)
-	categoryUC := usecase.NewCategoryUseCase(categoryRepo)
+	moderationUC := usecase.NewModerationUseCase(productRepo, categoryRepo, attributeRepo, moderationReviewRepo, keywordRepo, publisher, cfg.MinProductImages)
+	productUC := usecase.NewProductUseCase(productRepo, categoryRepo, attributeRepo, optionRepo, variantRepo, moderationUC, publisher)
 	attributeUC := usecase.NewAttributeUseCase(attributeRepo, categoryRepo)
-	variantUC := usecase.NewVariantUseCase(productRepo, optionRepo, variantRepo, publisher)
+	pricingUC := usecase.NewPricingUseCase(productRepo, variantRepo, priceHistoryRepo, priceScheduleRepo, publisher)
+	variantUC := usecase.NewVariantUseCase(productRepo, optionRepo, variantRepo, pricingUC, publisher)
+	catalogUC := usecase.NewCatalogUseCase(productRepo, categoryRepo, attributeRepo, optionRepo, variantRepo, importJobRepo, postgres.NewCatalogTransactor(db), moderationUC, pricingUC, publisher)
+	feedUC := usecase.NewFeedUseCase(productRepo, categoryRepo, attributeRepo, variantRepo, feedItemRepo, feedCategoryRepo, cfg.StorefrontURL)
+	categoryUC := usecase.NewCategoryUseCase(categoryRepo, productRepo, feedUC)
+	digitalUC := usecase.NewDigitalUseCase(productRepo, variantRepo, digitalFileRepo, licenseKeyRepo, entitlementRepo, publisher,
+		cfg.MediaPublicURL, cfg.DownloadSigningSecret, time.Duration(cfg.DownloadLinkTTLMinutes)*time.Minute)
+	ratingUC := usecase.NewRatingUseCase(productRepo, review.NewClient(cfg.ReviewServiceURL), publisher)
+	translationUC := usecase.NewTranslationUseCase(productRepo, categoryRepo, optionRepo, translationRepo, bundle)
+	subscriptionPlanUC := usecase.NewSubscriptionPlanUseCase(productRepo, variantRepo, subscriptionPlanRepo)
+
+	// Start catalog import job runner
+	runnerCtx, stopRunner := context.WithCancel(context.Background())
+	defer stopRunner()
+
+	// Exchange rates for presenting prices in the buyer's currency
+	converter := currency.NewConverter()
+	if src := currency.NewRateSource(cfg.ExchangeRatesSource); src != nil {
+		if err := converter.LoadRates(runnerCtx, src); err != nil {
+			log.Warn().Err(err).Msg("Failed to load exchange rates, using default rates")
+		}
+		converter.StartRefresher(runnerCtx, src, time.Duration(cfg.ExchangeRatesRefreshMinutes)*time.Minute, func(err error) {
+			log.Error().Err(err).Msg("Failed to refresh exchange rates")
+		})
+	}
+	currencyUC := usecase.NewCurrencyUseCase(productRepo, variantRepo, variantPriceRepo, converter)
+	scheduler.StartImportJobRunner(runnerCtx, catalogUC, 5*time.Second)
+
+	// Keep product feeds current from product events, with a periodic full
+	// rebuild to repair anything missed
+	if publisher != nil {
+		if err := natspub.StartFeedSubscriber(publisher, feedUC); err != nil {
+			log.Error().Err(err).Msg("Failed to start product feed subscriber")
+		}
+	}
+	scheduler.StartFeedRebuilder(runnerCtx, feedUC, 6*time.Hour)
+
+	// Deliver digital products once their orders are paid
+	if publisher != nil {
+		if err := natspub.StartDigitalSubscriber(publisher, digitalUC); err != nil {
+			log.Error().Err(err).Msg("Failed to start digital fulfilment subscriber")
+		}
+	}
+
+	// Keep product and seller ratings in line with approved reviews
+	if publisher != nil {
+		if err := natspub.StartReviewSubscriber(publisher, ratingUC); err != nil {
+			log.Error().Err(err).Msg("Failed to start review rating subscriber")
+		}
+	}
+
+	// Start and revert scheduled price changes
+	scheduler.StartPriceScheduler(runnerCtx, pricingUC, time.Minute)
+
+	// Products that went live before moderation existed count as approved
+	go func() {
+		n, err := moderationUC.ApproveLegacyProducts(runnerCtx)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to approve legacy products")
+			return
+		}
+		if n > 0 {
+			log.Info().Int("products", n).Msg("Approved legacy products for moderation")
+		}
+	}()
 
 	// Initialize HTTP handler and router
-	handler := producthttp.NewHandler(productUC, categoryUC, attributeUC, variantUC)
-	router := producthttp.NewRouter(handler)
+	handler := producthttp.NewHandler(productUC, categoryUC, attributeUC, variantUC, catalogUC, feedUC, moderationUC, pricingUC, digitalUC, ratingUC, translationUC, currencyUC, subscriptionPlanUC)
+	router := producthttp.NewRouter(handler, bundle)
 
 	// Start HTTP server
 	httpServer := &http.Server{
@@ -97,7 +197,7 @@ func main() {
 	}()
 
 	// Start gRPC server
-	grpcServer := grpc.NewServer(productUC, variantUC)
+	grpcServer := grpc.NewServer(productUC, variantUC, attributeUC, translationUC)
 	go func() {
 		if err := grpcServer.Start(cfg.GRPCPort); err != nil {
 			log.Fatal().Err(err).Msg("gRPC server failed")
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
@@ -11,6 +12,9 @@ import (
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 
+	// Answers calls made with the JSON codec
+	_ "github.com/southern-martin/ecommerce/pkg/grpcjson"
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
 	"github.com/southern-martin/ecommerce/services/product/internal/usecase"
 )
 
@@ -20,6 +24,7 @@ type ProductServiceServer interface {
 	GetVariant(ctx context.Context, req *GetVariantRequest) (*GetVariantResponse, error)
 	UpdateStock(ctx context.Context, req *UpdateStockRequest) (*UpdateStockResponse, error)
 	ListVariantsByProduct(ctx context.Context, req *ListVariantsByProductRequest) (*ListVariantsByProductResponse, error)
+	ListProducts(ctx context.Context, req *ListProductsRequest) (*ListProductsResponse, error)
 }
 
 // --- Request/Response Types ---
@@ -39,6 +44,30 @@ type GetProductResponse struct {
 	Currency       string
 	Status         string
 	HasVariants    bool
+	Tags           []string
+	ImageURLs      []string
+	RatingAvg      float64
+	RatingCount    int
+	Attributes     []AttributeValueInfo
+	Translations   []TranslationInfo
+	CreatedAt      time.Time
+}
+
+// AttributeValueInfo is a product's value of an attribute. Values holds
+// the choices of multi-select attributes.
+type AttributeValueInfo struct {
+	Slug   string
+	Name   string
+	Value  string
+	Values []string
+}
+
+// TranslationInfo is a product's content in one locale.
+type TranslationInfo struct {
+	Locale      string
+	Name        string
+	Slug        string
+	Description string
 }
 
 type GetVariantRequest struct {
@@ -82,20 +111,42 @@ type ListVariantsByProductResponse struct {
 	Variants []VariantInfo
 }
 
+// ListProductsRequest pages through the catalog, optionally only products
+// with Status.
+type ListProductsRequest struct {
+	Status   string
+	Page     int32
+	PageSize int32
+}
+
+type ListProductsResponse struct {
+	ProductIDs []string
+	Total      int64
+}
+
 // --- Server Implementation ---
 
 // Server implements the gRPC product service.
 type Server struct {
-	productUC *usecase.ProductUseCase
-	variantUC *usecase.VariantUseCase
-	server    *grpc.Server
+	productUC     *usecase.ProductUseCase
+	variantUC     *usecase.VariantUseCase
+	attributeUC   *usecase.AttributeUseCase
+	translationUC *usecase.TranslationUseCase
+	server        *grpc.Server
 }
 
 // NewServer creates a new gRPC server.
-func NewServer(productUC *usecase.ProductUseCase, variantUC *usecase.VariantUseCase) *Server {
+func NewServer(
+	productUC *usecase.ProductUseCase,
+	variantUC *usecase.VariantUseCase,
+	attributeUC *usecase.AttributeUseCase,
+	translationUC *usecase.TranslationUseCase,
+) *Server {
 	return &Server{
-		productUC: productUC,
-		variantUC: variantUC,
+		productUC:     productUC,
+		variantUC:     variantUC,
+		attributeUC:   attributeUC,
+		translationUC: translationUC,
 	}
 }
 
@@ -109,7 +160,17 @@ func (s *Server) GetProduct(ctx context.Context, req *GetProductRequest) (*GetPr
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
-	return &GetProductResponse{
+	attributes, err := s.attributeValues(ctx, product.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	translations, err := s.translationUC.GetProductTranslations(ctx, product.ID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &GetProductResponse{
 		ID:             product.ID,
 		SellerID:       product.SellerID,
 		CategoryID:     product.CategoryID,
@@ -120,7 +181,57 @@ func (s *Server) GetProduct(ctx context.Context, req *GetProductRequest) (*GetPr
 		Currency:       product.Currency,
 		Status:         string(product.Status),
 		HasVariants:    product.HasVariants,
-	}, nil
+		Tags:           product.Tags,
+		ImageURLs:      product.ImageURLs,
+		RatingAvg:      product.RatingAvg,
+		RatingCount:    product.RatingCount,
+		Attributes:     attributes,
+		CreatedAt:      product.CreatedAt,
+	}
+	for _, t := range translations {
+		resp.Translations = append(resp.Translations, TranslationInfo{
+			Locale:      t.Locale,
+			Name:        t.Name,
+			Slug:        t.Slug,
+			Description: t.Description,
+		})
+	}
+	return resp, nil
+}
+
+// attributeValues returns a product's attribute values with the slugs of
+// their definitions.
+func (s *Server) attributeValues(ctx context.Context, productID string) ([]AttributeValueInfo, error) {
+	values, err := s.attributeUC.GetProductAttributeValues(ctx, productID)
+	if err != nil || len(values) == 0 {
+		return nil, err
+	}
+
+	definitions, err := s.attributeUC.ListAttributeDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.AttributeDefinition, len(definitions))
+	for _, def := range definitions {
+		byID[def.ID] = def
+	}
+
+	infos := make([]AttributeValueInfo, 0, len(values))
+	for _, v := range values {
+		info := AttributeValueInfo{
+			Name:   v.AttributeName,
+			Value:  v.Value,
+			Values: v.Values,
+		}
+		if def, ok := byID[v.AttributeID]; ok {
+			info.Slug = def.Slug
+			if info.Name == "" {
+				info.Name = def.Name
+			}
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
 }
 
 func (s *Server) GetVariant(ctx context.Context, req *GetVariantRequest) (*GetVariantResponse, error) {
@@ -187,6 +298,23 @@ func (s *Server) ListVariantsByProduct(ctx context.Context, req *ListVariantsByP
 	return &ListVariantsByProductResponse{Variants: infos}, nil
 }
 
+func (s *Server) ListProducts(ctx context.Context, req *ListProductsRequest) (*ListProductsResponse, error) {
+	products, total, err := s.productUC.ListProducts(ctx, domain.ProductFilter{
+		Status:   req.Status,
+		Page:     int(req.Page),
+		PageSize: int(req.PageSize),
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	ids := make([]string, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	return &ListProductsResponse{ProductIDs: ids, Total: total}, nil
+}
+
 // --- gRPC ServiceDesc (manual registration, no proto codegen) ---
 
 var _ProductService_serviceDesc = grpc.ServiceDesc{
@@ -209,6 +337,10 @@ var _ProductService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ListVariantsByProduct",
 			Handler:    _ProductService_ListVariantsByProduct_Handler,
 		},
+		{
+			MethodName: "ListProducts",
+			Handler:    _ProductService_ListProducts_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{},
 }
@@ -285,6 +417,24 @@ func _ProductService_ListVariantsByProduct_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, req, info, handler)
 }
 
+func _ProductService_ListProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := new(ListProductsRequest)
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServiceServer).ListProducts(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/product.ProductService/ListProducts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServiceServer).ListProducts(ctx, req.(*ListProductsRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
+
 // Start starts the gRPC server on the given port.
 func (s *Server) Start(port string) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
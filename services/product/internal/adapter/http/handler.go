@@ -1,21 +1,38 @@
 package http
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/southern-martin/ecommerce/pkg/currency"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 	"github.com/southern-martin/ecommerce/services/product/internal/domain"
 	"github.com/southern-martin/ecommerce/services/product/internal/usecase"
 )
 
 // Handler holds HTTP handlers for the product service.
 type Handler struct {
-	productUC   *usecase.ProductUseCase
-	categoryUC  *usecase.CategoryUseCase
-	attributeUC *usecase.AttributeUseCase
-	variantUC   *usecase.VariantUseCase
+	productUC     *usecase.ProductUseCase
+	categoryUC    *usecase.CategoryUseCase
+	attributeUC   *usecase.AttributeUseCase
+	variantUC     *usecase.VariantUseCase
+	catalogUC     *usecase.CatalogUseCase
+	feedUC        *usecase.FeedUseCase
+	moderationUC  *usecase.ModerationUseCase
+	pricingUC     *usecase.PricingUseCase
+	digitalUC     *usecase.DigitalUseCase
+	ratingUC      *usecase.RatingUseCase
+	translationUC *usecase.TranslationUseCase
+	currencyUC    *usecase.CurrencyUseCase
+	planUC        *usecase.SubscriptionPlanUseCase
 }
 
 // NewHandler creates a new Handler.
@@ -24,12 +41,30 @@ func NewHandler(
 	categoryUC *usecase.CategoryUseCase,
 	attributeUC *usecase.AttributeUseCase,
 	variantUC *usecase.VariantUseCase,
+	catalogUC *usecase.CatalogUseCase,
+	feedUC *usecase.FeedUseCase,
+	moderationUC *usecase.ModerationUseCase,
+	pricingUC *usecase.PricingUseCase,
+	digitalUC *usecase.DigitalUseCase,
+	ratingUC *usecase.RatingUseCase,
+	translationUC *usecase.TranslationUseCase,
+	currencyUC *usecase.CurrencyUseCase,
+	planUC *usecase.SubscriptionPlanUseCase,
 ) *Handler {
 	return &Handler{
-		productUC:   productUC,
-		categoryUC:  categoryUC,
-		attributeUC: attributeUC,
-		variantUC:   variantUC,
+		productUC:     productUC,
+		categoryUC:    categoryUC,
+		attributeUC:   attributeUC,
+		variantUC:     variantUC,
+		catalogUC:     catalogUC,
+		feedUC:        feedUC,
+		moderationUC:  moderationUC,
+		pricingUC:     pricingUC,
+		digitalUC:     digitalUC,
+		ratingUC:      ratingUC,
+		translationUC: translationUC,
+		currencyUC:    currencyUC,
+		planUC:        planUC,
 	}
 }
 
@@ -51,6 +86,7 @@ func (h *Handler) ListProducts(c *gin.Context) {
 		Query:      c.Query("q"),
 		SortBy:     c.Query("sort_by"),
 	}
+	filter.IncludeSubcategories, _ = strconv.ParseBool(c.Query("include_subcategories"))
 
 	if v := c.Query("min_price"); v != "" {
 		if price, err := strconv.ParseInt(v, 10, 64); err == nil {
@@ -83,6 +119,13 @@ func (h *Handler) ListProducts(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	locale := i18n.GetLanguage(c)
+	if err := h.translationUC.LocalizeProducts(c.Request.Context(), locale, products...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.currencyUC.PresentProducts(currency.GetCurrency(c), products...)
+	c.Header("Content-Language", locale)
 
 	c.JSON(http.StatusOK, gin.H{
 		"products": products,
@@ -100,20 +143,43 @@ func (h *Handler) GetProduct(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	locale := i18n.GetLanguage(c)
+	if err := h.translationUC.LocalizeProducts(c.Request.Context(), locale, product); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.currencyUC.PresentProducts(currency.GetCurrency(c), product)
+	c.Header("Content-Language", locale)
 	c.JSON(http.StatusOK, product)
 }
 
 // GetProductBySlug handles GET /api/v1/products/slug/:slug
+// The slug may be the base slug or a slug of any of the product's translations.
 func (h *Handler) GetProductBySlug(c *gin.Context) {
 	slug := c.Param("slug")
-	product, err := h.productUC.GetProductBySlug(c.Request.Context(), slug)
+	locale := i18n.GetLanguage(c)
+	product, err := h.translationUC.GetProductBySlug(c.Request.Context(), locale, slug)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	h.currencyUC.PresentProducts(currency.GetCurrency(c), product)
+	c.Header("Content-Language", locale)
 	c.JSON(http.StatusOK, product)
 }
 
+// ListProductOptions handles GET /api/v1/products/:id/options
+func (h *Handler) ListProductOptions(c *gin.Context) {
+	locale := i18n.GetLanguage(c)
+	options, err := h.translationUC.ListOptions(c.Request.Context(), c.Param("id"), locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Language", locale)
+	c.JSON(http.StatusOK, gin.H{"options": options})
+}
+
 // --- Public Category Endpoints ---
 
 // ListCategories handles GET /api/v1/categories
@@ -123,9 +189,47 @@ func (h *Handler) ListCategories(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	locale := i18n.GetLanguage(c)
+	if err := h.translationUC.LocalizeCategories(c.Request.Context(), locale, categories...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Language", locale)
 	c.JSON(http.StatusOK, gin.H{"categories": categories})
 }
 
+// GetCategoryTree handles GET /api/v1/categories/tree?root_id=
+func (h *Handler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryUC.GetCategoryTree(c.Request.Context(), c.Query("root_id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	locale := i18n.GetLanguage(c)
+	if err := h.translationUC.LocalizeCategoryTree(c.Request.Context(), locale, tree); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Language", locale)
+	c.JSON(http.StatusOK, gin.H{"categories": tree})
+}
+
+// GetCategoryPath handles GET /api/v1/categories/:id/path
+func (h *Handler) GetCategoryPath(c *gin.Context) {
+	path, err := h.categoryUC.GetCategoryPath(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	locale := i18n.GetLanguage(c)
+	if err := h.translationUC.LocalizeCategories(c.Request.Context(), locale, path...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Language", locale)
+	c.JSON(http.StatusOK, gin.H{"path": path})
+}
+
 // --- Seller Product Endpoints ---
 
 type createProductRequest struct {
@@ -137,6 +241,10 @@ type createProductRequest struct {
 	Tags           []string                      `json:"tags"`
 	ImageURLs      []string                      `json:"image_urls"`
 	Attributes     []attributeValueInputRequest  `json:"attributes"`
+	Type               domain.ProductType `json:"type"`
+	DownloadLimit      int                `json:"download_limit"`
+	DownloadExpiryDays int                `json:"download_expiry_days"`
+	RequiresLicenseKey bool               `json:"requires_license_key"`
 }
 
 type attributeValueInputRequest struct {
@@ -178,11 +286,15 @@ func (h *Handler) CreateProduct(c *gin.Context) {
 		Tags:           req.Tags,
 		ImageURLs:      req.ImageURLs,
 		Attributes:     attrs,
+		Type:               req.Type,
+		DownloadLimit:      req.DownloadLimit,
+		DownloadExpiryDays: req.DownloadExpiryDays,
+		RequiresLicenseKey: req.RequiresLicenseKey,
 	}
 
 	product, err := h.productUC.CreateProduct(c.Request.Context(), input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeProductError(c, err)
 		return
 	}
 
@@ -198,6 +310,11 @@ type updateProductRequest struct {
 	Tags           []string           `json:"tags"`
 	ImageURLs      []string           `json:"image_urls"`
 	CategoryID     *string            `json:"category_id"`
+	Attributes     []attributeValueInputRequest `json:"attributes"`
+	Type               *domain.ProductType `json:"type"`
+	DownloadLimit      *int                `json:"download_limit"`
+	DownloadExpiryDays *int                `json:"download_expiry_days"`
+	RequiresLicenseKey *bool               `json:"requires_license_key"`
 }
 
 // UpdateProduct handles PATCH /api/v1/seller/products/:id
@@ -224,17 +341,42 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 		Tags:           req.Tags,
 		ImageURLs:      req.ImageURLs,
 		CategoryID:     req.CategoryID,
+		Type:               req.Type,
+		DownloadLimit:      req.DownloadLimit,
+		DownloadExpiryDays: req.DownloadExpiryDays,
+		RequiresLicenseKey: req.RequiresLicenseKey,
+	}
+	if req.Attributes != nil {
+		input.Attributes = make([]usecase.AttributeValueInput, 0, len(req.Attributes))
+		for _, a := range req.Attributes {
+			input.Attributes = append(input.Attributes, usecase.AttributeValueInput{
+				AttributeID: a.AttributeID,
+				Value:       a.Value,
+				Values:      a.Values,
+			})
+		}
 	}
 
 	product, err := h.productUC.UpdateProduct(c.Request.Context(), id, sellerID, input)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		writeProductError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, product)
 }
 
+// writeProductError responds to a failed product write. Attribute schema
+// violations are listed per attribute so clients can point at each field.
+func writeProductError(c *gin.Context, err error) {
+	var verr *usecase.AttributeValidationError
+	if errors.As(err, &verr) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": verr.Error(), "details": verr.Errors})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
 // DeleteProduct handles DELETE /api/v1/seller/products/:id
 func (h *Handler) DeleteProduct(c *gin.Context) {
 	sellerID := c.GetHeader("X-User-ID")
@@ -421,172 +563,1291 @@ func (h *Handler) UpdateVariantStock(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "stock updated"})
 }
 
-// --- Admin Category Endpoints ---
+// --- Variant Price Endpoints ---
 
-type createCategoryRequest struct {
-	Name      string `json:"name" binding:"required"`
-	ParentID  string `json:"parent_id"`
-	SortOrder int    `json:"sort_order"`
-	ImageURL  string `json:"image_url"`
+// GetVariantPrice handles GET /api/v1/products/:id/variants/:variantId/price
+func (h *Handler) GetVariantPrice(c *gin.Context) {
+	summary, err := h.pricingUC.GetPriceSummary(c.Request.Context(), c.Param("id"), c.Param("variantId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.currencyUC.PresentPriceSummary(c.Request.Context(), currency.GetCurrency(c), summary); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
 }
 
-// CreateCategory handles POST /api/v1/admin/categories
-func (h *Handler) CreateCategory(c *gin.Context) {
-	var req createCategoryRequest
+// GetVariantPriceHistory handles GET /api/v1/products/:id/variants/:variantId/price-history?days=
+func (h *Handler) GetVariantPriceHistory(c *gin.Context) {
+	days, _ := strconv.Atoi(c.Query("days"))
+	history, err := h.pricingUC.GetPriceHistory(c.Request.Context(), c.Param("id"), c.Param("variantId"), days)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"history": history})
+}
+
+type schedulePriceRequest struct {
+	PriceCents     int64      `json:"price_cents" binding:"required"`
+	CompareAtCents *int64     `json:"compare_at_cents"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at"`
+}
+
+// SchedulePrice handles POST /api/v1/seller/products/:id/variants/:variantId/price-schedules
+func (h *Handler) SchedulePrice(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req schedulePriceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	category, err := h.categoryUC.CreateCategory(c.Request.Context(), usecase.CreateCategoryInput{
-		Name:      req.Name,
-		ParentID:  req.ParentID,
-		SortOrder: req.SortOrder,
-		ImageURL:  req.ImageURL,
+	schedule, err := h.pricingUC.SchedulePrice(c.Request.Context(), c.Param("id"), c.Param("variantId"), sellerID, usecase.SchedulePriceInput{
+		PriceCents:     req.PriceCents,
+		CompareAtCents: req.CompareAtCents,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, category)
+	c.JSON(http.StatusCreated, schedule)
 }
 
-// --- Admin Attribute Endpoints ---
+// ListPriceSchedules handles GET /api/v1/seller/products/:id/variants/:variantId/price-schedules
+func (h *Handler) ListPriceSchedules(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
 
-type createAttributeRequest struct {
-	Name       string              `json:"name" binding:"required"`
-	Type       domain.AttributeType `json:"type" binding:"required"`
-	Required   bool                `json:"required"`
-	Filterable bool                `json:"filterable"`
-	Options    []string            `json:"options"`
-	Unit       string              `json:"unit"`
-	SortOrder  int                 `json:"sort_order"`
+	schedules, err := h.pricingUC.ListPriceSchedules(c.Request.Context(), c.Param("id"), c.Param("variantId"), sellerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules})
 }
 
-// CreateAttributeDefinition handles POST /api/v1/admin/attributes
-func (h *Handler) CreateAttributeDefinition(c *gin.Context) {
-	var req createAttributeRequest
+// CancelPriceSchedule handles POST /api/v1/seller/products/:id/price-schedules/:scheduleId/cancel
+func (h *Handler) CancelPriceSchedule(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	schedule, err := h.pricingUC.CancelPriceSchedule(c.Request.Context(), c.Param("id"), c.Param("scheduleId"), sellerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+type variantPriceRequest struct {
+	PriceCents     int64 `json:"price_cents" binding:"required"`
+	CompareAtCents int64 `json:"compare_at_cents"`
+}
+
+// SetVariantCurrencyPrice handles PUT /api/v1/seller/products/:id/variants/:variantId/prices/:currency
+func (h *Handler) SetVariantCurrencyPrice(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req variantPriceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	attr, err := h.attributeUC.CreateAttributeDefinition(c.Request.Context(), usecase.CreateAttributeInput{
-		Name:       req.Name,
-		Type:       req.Type,
-		Required:   req.Required,
-		Filterable: req.Filterable,
-		Options:    req.Options,
-		Unit:       req.Unit,
-		SortOrder:  req.SortOrder,
+	price, err := h.currencyUC.SetVariantPrice(c.Request.Context(), c.Param("id"), c.Param("variantId"), sellerID, c.Param("currency"), usecase.SetVariantPriceInput{
+		PriceCents:     req.PriceCents,
+		CompareAtCents: req.CompareAtCents,
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, price)
+}
 
-	c.JSON(http.StatusCreated, attr)
+// ListVariantCurrencyPrices handles GET /api/v1/seller/products/:id/variants/:variantId/prices
+func (h *Handler) ListVariantCurrencyPrices(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	prices, err := h.currencyUC.ListVariantPrices(c.Request.Context(), c.Param("id"), c.Param("variantId"), sellerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"prices": prices})
 }
 
-// ListAttributeDefinitions handles GET /api/v1/admin/attributes
-func (h *Handler) ListAttributeDefinitions(c *gin.Context) {
-	attrs, err := h.attributeUC.ListAttributeDefinitions(c.Request.Context())
+// DeleteVariantCurrencyPrice handles DELETE /api/v1/seller/products/:id/variants/:variantId/prices/:currency
+func (h *Handler) DeleteVariantCurrencyPrice(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	if err := h.currencyUC.DeleteVariantPrice(c.Request.Context(), c.Param("id"), c.Param("variantId"), sellerID, c.Param("currency")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "variant price deleted"})
+}
+
+// --- Subscription Plan Endpoints ---
+
+// ListSubscriptionPlans handles GET /api/v1/products/:id/subscription-plans
+func (h *Handler) ListSubscriptionPlans(c *gin.Context) {
+	plans, err := h.planUC.ListPlans(c.Request.Context(), c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"attributes": attrs})
+	c.JSON(http.StatusOK, gin.H{"plans": plans})
 }
 
-type updateAttributeRequest struct {
-	Name       *string  `json:"name"`
-	Required   *bool    `json:"required"`
-	Filterable *bool    `json:"filterable"`
-	Options    []string `json:"options"`
-	Unit       *string  `json:"unit"`
-	SortOrder  *int     `json:"sort_order"`
+// GetSubscriptionPlan handles GET /api/v1/products/:id/subscription-plans/:planId
+func (h *Handler) GetSubscriptionPlan(c *gin.Context) {
+	plan, err := h.planUC.GetPlan(c.Request.Context(), c.Param("id"), c.Param("planId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
 }
 
-// UpdateAttributeDefinition handles PATCH /api/v1/admin/attributes/:id
-func (h *Handler) UpdateAttributeDefinition(c *gin.Context) {
-	id := c.Param("id")
-	var req updateAttributeRequest
+type createSubscriptionPlanRequest struct {
+	IntervalUnit  domain.SubscriptionInterval `json:"interval_unit" binding:"required"`
+	IntervalCount int                         `json:"interval_count" binding:"required"`
+	PercentOff    int64                       `json:"percent_off"`
+}
+
+// CreateSubscriptionPlan handles POST /api/v1/seller/products/:id/variants/:variantId/subscription-plans
+func (h *Handler) CreateSubscriptionPlan(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req createSubscriptionPlanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	attr, err := h.attributeUC.UpdateAttributeDefinition(c.Request.Context(), id, usecase.UpdateAttributeInput{
-		Name:       req.Name,
-		Required:   req.Required,
-		Filterable: req.Filterable,
-		Options:    req.Options,
-		Unit:       req.Unit,
-		SortOrder:  req.SortOrder,
+	plan, err := h.planUC.CreatePlan(c.Request.Context(), c.Param("id"), c.Param("variantId"), sellerID, usecase.CreateSubscriptionPlanInput{
+		IntervalUnit:  req.IntervalUnit,
+		IntervalCount: req.IntervalCount,
+		PercentOff:    req.PercentOff,
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	c.JSON(http.StatusOK, attr)
+	c.JSON(http.StatusCreated, plan)
 }
 
-// DeleteAttributeDefinition handles DELETE /api/v1/admin/attributes/:id
-func (h *Handler) DeleteAttributeDefinition(c *gin.Context) {
-	id := c.Param("id")
-	if err := h.attributeUC.DeleteAttributeDefinition(c.Request.Context(), id); err != nil {
+// ListSellerSubscriptionPlans handles GET /api/v1/seller/products/:id/subscription-plans
+func (h *Handler) ListSellerSubscriptionPlans(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	plans, err := h.planUC.ListSellerPlans(c.Request.Context(), c.Param("id"), sellerID)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "attribute definition deleted"})
+	c.JSON(http.StatusOK, gin.H{"plans": plans})
 }
 
-// --- Category Attribute Assignment Endpoints ---
-
-type assignAttributeRequest struct {
-	AttributeID string `json:"attribute_id" binding:"required"`
-	SortOrder   int    `json:"sort_order"`
+type updateSubscriptionPlanRequest struct {
+	IntervalUnit  *domain.SubscriptionInterval `json:"interval_unit"`
+	IntervalCount *int                         `json:"interval_count"`
+	PercentOff    *int64                       `json:"percent_off"`
+	IsActive      *bool                        `json:"is_active"`
 }
 
-// AssignAttributeToCategory handles POST /api/v1/categories/:id/attributes
-func (h *Handler) AssignAttributeToCategory(c *gin.Context) {
-	categoryID := c.Param("id")
-	var req assignAttributeRequest
+// UpdateSubscriptionPlan handles PATCH /api/v1/seller/products/:id/subscription-plans/:planId
+func (h *Handler) UpdateSubscriptionPlan(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req updateSubscriptionPlanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.attributeUC.AssignAttributeToCategory(c.Request.Context(), categoryID, req.AttributeID, req.SortOrder); err != nil {
+	plan, err := h.planUC.UpdatePlan(c.Request.Context(), c.Param("id"), c.Param("planId"), sellerID, usecase.UpdateSubscriptionPlanInput{
+		IntervalUnit:  req.IntervalUnit,
+		IntervalCount: req.IntervalCount,
+		PercentOff:    req.PercentOff,
+		IsActive:      req.IsActive,
+	})
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "attribute assigned to category"})
+	c.JSON(http.StatusOK, plan)
 }
 
-// RemoveAttributeFromCategory handles DELETE /api/v1/categories/:id/attributes/:attrId
-func (h *Handler) RemoveAttributeFromCategory(c *gin.Context) {
-	categoryID := c.Param("id")
-	attrID := c.Param("attrId")
+// DeleteSubscriptionPlan handles DELETE /api/v1/seller/products/:id/subscription-plans/:planId
+func (h *Handler) DeleteSubscriptionPlan(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
 
-	if err := h.attributeUC.RemoveAttributeFromCategory(c.Request.Context(), categoryID, attrID); err != nil {
+	if err := h.planUC.DeletePlan(c.Request.Context(), c.Param("id"), c.Param("planId"), sellerID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"message": "subscription plan deleted"})
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "attribute removed from category"})
+// --- Seller Digital Product Endpoints ---
+
+type attachFileRequest struct {
+	VariantID   string `json:"variant_id"`
+	MediaID     string `json:"media_id" binding:"required"`
+	FileName    string `json:"file_name" binding:"required"`
+	ContentType string `json:"content_type"`
+	SizeBytes   int64  `json:"size_bytes"`
 }
 
-// ListCategoryAttributes handles GET /api/v1/categories/:id/attributes
-func (h *Handler) ListCategoryAttributes(c *gin.Context) {
-	categoryID := c.Param("id")
-	attrs, err := h.attributeUC.ListCategoryAttributes(c.Request.Context(), categoryID)
+// AttachDigitalFile handles POST /api/v1/seller/products/:id/files
+func (h *Handler) AttachDigitalFile(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req attachFileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := h.digitalUC.AttachFile(c.Request.Context(), c.Param("id"), sellerID, usecase.AttachFileInput{
+		VariantID:   req.VariantID,
+		MediaID:     req.MediaID,
+		FileName:    req.FileName,
+		ContentType: req.ContentType,
+		SizeBytes:   req.SizeBytes,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"attributes": attrs})
+	c.JSON(http.StatusCreated, file)
+}
+
+// ListDigitalFiles handles GET /api/v1/seller/products/:id/files
+func (h *Handler) ListDigitalFiles(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	files, err := h.digitalUC.ListFiles(c.Request.Context(), c.Param("id"), sellerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"files": files})
+}
+
+// DeleteDigitalFile handles DELETE /api/v1/seller/products/:id/files/:fileId
+func (h *Handler) DeleteDigitalFile(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	if err := h.digitalUC.DeleteFile(c.Request.Context(), c.Param("id"), c.Param("fileId"), sellerID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "file deleted"})
+}
+
+type addLicenseKeysRequest struct {
+	VariantID string   `json:"variant_id"`
+	Keys      []string `json:"keys" binding:"required"`
+}
+
+// AddLicenseKeys handles POST /api/v1/seller/products/:id/license-keys
+func (h *Handler) AddLicenseKeys(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req addLicenseKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	added, err := h.digitalUC.AddLicenseKeys(c.Request.Context(), c.Param("id"), sellerID, usecase.AddLicenseKeysInput{
+		VariantID: req.VariantID,
+		Keys:      req.Keys,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"added": added})
+}
+
+// ListLicenseKeys handles GET /api/v1/seller/products/:id/license-keys?status=
+func (h *Handler) ListLicenseKeys(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	keys, err := h.digitalUC.ListLicenseKeys(c.Request.Context(), c.Param("id"), sellerID, domain.LicenseKeyStatus(c.Query("status")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"license_keys": keys})
+}
+
+// DeleteLicenseKey handles DELETE /api/v1/seller/products/:id/license-keys/:keyId
+func (h *Handler) DeleteLicenseKey(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	if err := h.digitalUC.DeleteLicenseKey(c.Request.Context(), c.Param("id"), c.Param("keyId"), sellerID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "license key deleted"})
+}
+
+// --- Buyer Digital Purchase Endpoints ---
+
+// ListEntitlements handles GET /api/v1/digital/entitlements
+func (h *Handler) ListEntitlements(c *gin.Context) {
+	buyerID := c.GetHeader("X-User-ID")
+	if buyerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	entitlements, total, err := h.digitalUC.ListEntitlements(c.Request.Context(), buyerID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entitlements": entitlements,
+		"total":        total,
+		"page":         page,
+		"pageSize":     pageSize,
+	})
+}
+
+// GetEntitlement handles GET /api/v1/digital/entitlements/:id
+func (h *Handler) GetEntitlement(c *gin.Context) {
+	buyerID := c.GetHeader("X-User-ID")
+	if buyerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	entitlement, err := h.digitalUC.GetEntitlement(c.Request.Context(), c.Param("id"), buyerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entitlement)
+}
+
+// CreateDownloadLink handles POST /api/v1/digital/entitlements/:id/files/:fileId/download-link
+func (h *Handler) CreateDownloadLink(c *gin.Context) {
+	buyerID := c.GetHeader("X-User-ID")
+	if buyerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	link, err := h.digitalUC.CreateDownloadLink(c.Request.Context(), c.Param("id"), c.Param("fileId"), buyerID)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, link)
+}
+
+// --- Seller Catalog Import/Export Endpoints ---
+
+// ImportCatalog handles POST /api/v1/seller/catalog/imports
+//
+// The file is sent either as the multipart field "file" or as the raw request
+// body. The format comes from the format query parameter, the file extension
+// or the content type. With dry_run=true the file is only validated.
+func (h *Handler) ImportCatalog(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	data, filename, err := readCatalogUpload(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := catalogFormat(c.Query("format"), filename, c.ContentType())
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+
+	job, err := h.catalogUC.SubmitImport(c.Request.Context(), usecase.SubmitImportInput{
+		SellerID: sellerID,
+		Format:   format,
+		DryRun:   dryRun,
+		Data:     data,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ListImportJobs handles GET /api/v1/seller/catalog/imports
+func (h *Handler) ListImportJobs(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	jobs, total, err := h.catalogUC.ListImportJobs(c.Request.Context(), sellerID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":     jobs,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// GetImportJob handles GET /api/v1/seller/catalog/imports/:id
+func (h *Handler) GetImportJob(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	job, err := h.catalogUC.GetImportJob(c.Request.Context(), c.Param("id"), sellerID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// ExportCatalog handles GET /api/v1/seller/catalog/export?format=csv|json
+func (h *Handler) ExportCatalog(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	format := domain.ImportFormat(c.DefaultQuery("format", string(domain.ImportFormatCSV)))
+	contentType := "text/csv"
+	switch format {
+	case domain.ImportFormatCSV:
+	case domain.ImportFormatJSON:
+		contentType = "application/json"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog.%s"`, format))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure can only cut the file short.
+	if err := h.catalogUC.ExportCatalog(c.Request.Context(), sellerID, format, c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// readCatalogUpload reads an uploaded catalog file and its name, if any.
+func readCatalogUpload(c *gin.Context) ([]byte, string, error) {
+	var r io.Reader = c.Request.Body
+	filename := ""
+
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", fmt.Errorf("missing file field: %w", err)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+		r = file
+		filename = header.Filename
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, usecase.MaxImportBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	return data, filename, nil
+}
+
+// catalogFormat picks the import format from, in order, an explicit format,
+// the file extension and the content type.
+func catalogFormat(explicit, filename, contentType string) domain.ImportFormat {
+	if explicit != "" {
+		return domain.ImportFormat(strings.ToLower(explicit))
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return domain.ImportFormatCSV
+	case ".json":
+		return domain.ImportFormatJSON
+	}
+	if strings.Contains(contentType, "json") {
+		return domain.ImportFormatJSON
+	}
+	return domain.ImportFormatCSV
+}
+
+// --- Admin Category Endpoints ---
+
+type createCategoryRequest struct {
+	Name      string `json:"name" binding:"required"`
+	ParentID  string `json:"parent_id"`
+	SortOrder int    `json:"sort_order"`
+	ImageURL  string `json:"image_url"`
+}
+
+// CreateCategory handles POST /api/v1/admin/categories
+func (h *Handler) CreateCategory(c *gin.Context) {
+	var req createCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.categoryUC.CreateCategory(c.Request.Context(), usecase.CreateCategoryInput{
+		Name:      req.Name,
+		ParentID:  req.ParentID,
+		SortOrder: req.SortOrder,
+		ImageURL:  req.ImageURL,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+type moveCategoryRequest struct {
+	ParentID  string `json:"parent_id"`
+	SortOrder *int   `json:"sort_order"`
+}
+
+// MoveCategory handles POST /api/v1/admin/categories/:id/move
+func (h *Handler) MoveCategory(c *gin.Context) {
+	var req moveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.categoryUC.MoveCategory(c.Request.Context(), c.Param("id"), usecase.MoveCategoryInput{
+		ParentID:  req.ParentID,
+		SortOrder: req.SortOrder,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+type reorderCategoriesRequest struct {
+	ParentID    string   `json:"parent_id"`
+	CategoryIDs []string `json:"category_ids" binding:"required"`
+}
+
+// ReorderCategories handles PUT /api/v1/admin/categories/order
+func (h *Handler) ReorderCategories(c *gin.Context) {
+	var req reorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.categoryUC.ReorderCategories(c.Request.Context(), req.ParentID, req.CategoryIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "categories reordered"})
+}
+
+// --- Admin Attribute Endpoints ---
+
+type createAttributeRequest struct {
+	Name       string              `json:"name" binding:"required"`
+	Type       domain.AttributeType `json:"type" binding:"required"`
+	Required   bool                `json:"required"`
+	Filterable bool                `json:"filterable"`
+	Options    []string            `json:"options"`
+	Unit       string              `json:"unit"`
+	MinValue   *float64            `json:"min_value"`
+	MaxValue   *float64            `json:"max_value"`
+	SortOrder  int                 `json:"sort_order"`
+}
+
+// CreateAttributeDefinition handles POST /api/v1/admin/attributes
+func (h *Handler) CreateAttributeDefinition(c *gin.Context) {
+	var req createAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attr, err := h.attributeUC.CreateAttributeDefinition(c.Request.Context(), usecase.CreateAttributeInput{
+		Name:       req.Name,
+		Type:       req.Type,
+		Required:   req.Required,
+		Filterable: req.Filterable,
+		Options:    req.Options,
+		Unit:       req.Unit,
+		MinValue:   req.MinValue,
+		MaxValue:   req.MaxValue,
+		SortOrder:  req.SortOrder,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, attr)
+}
+
+// ListAttributeDefinitions handles GET /api/v1/admin/attributes
+func (h *Handler) ListAttributeDefinitions(c *gin.Context) {
+	attrs, err := h.attributeUC.ListAttributeDefinitions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"attributes": attrs})
+}
+
+type updateAttributeRequest struct {
+	Name       *string  `json:"name"`
+	Required   *bool    `json:"required"`
+	Filterable *bool    `json:"filterable"`
+	Options    []string `json:"options"`
+	Unit       *string  `json:"unit"`
+	MinValue   *float64 `json:"min_value"`
+	MaxValue   *float64 `json:"max_value"`
+	ClearRange bool     `json:"clear_range"`
+	SortOrder  *int     `json:"sort_order"`
+}
+
+// UpdateAttributeDefinition handles PATCH /api/v1/admin/attributes/:id
+func (h *Handler) UpdateAttributeDefinition(c *gin.Context) {
+	id := c.Param("id")
+	var req updateAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attr, err := h.attributeUC.UpdateAttributeDefinition(c.Request.Context(), id, usecase.UpdateAttributeInput{
+		Name:       req.Name,
+		Required:   req.Required,
+		Filterable: req.Filterable,
+		Options:    req.Options,
+		Unit:       req.Unit,
+		MinValue:   req.MinValue,
+		MaxValue:   req.MaxValue,
+		ClearRange: req.ClearRange,
+		SortOrder:  req.SortOrder,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, attr)
+}
+
+// DeleteAttributeDefinition handles DELETE /api/v1/admin/attributes/:id
+func (h *Handler) DeleteAttributeDefinition(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.attributeUC.DeleteAttributeDefinition(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "attribute definition deleted"})
+}
+
+// --- Category Attribute Assignment Endpoints ---
+
+type assignAttributeRequest struct {
+	AttributeID string `json:"attribute_id" binding:"required"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// AssignAttributeToCategory handles POST /api/v1/categories/:id/attributes
+func (h *Handler) AssignAttributeToCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+	var req assignAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.attributeUC.AssignAttributeToCategory(c.Request.Context(), categoryID, req.AttributeID, req.SortOrder); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "attribute assigned to category"})
+}
+
+// RemoveAttributeFromCategory handles DELETE /api/v1/categories/:id/attributes/:attrId
+func (h *Handler) RemoveAttributeFromCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+	attrID := c.Param("attrId")
+
+	if err := h.attributeUC.RemoveAttributeFromCategory(c.Request.Context(), categoryID, attrID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "attribute removed from category"})
+}
+
+// ListCategoryAttributes handles GET /api/v1/categories/:id/attributes
+func (h *Handler) ListCategoryAttributes(c *gin.Context) {
+	categoryID := c.Param("id")
+	attrs, err := h.attributeUC.ListCategoryAttributes(c.Request.Context(), categoryID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"attributes": attrs})
+}
+
+// GetCategorySchema handles GET /api/v1/categories/:id/schema
+func (h *Handler) GetCategorySchema(c *gin.Context) {
+	attrs, err := h.attributeUC.GetCategorySchema(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"attributes": attrs})
+}
+
+// --- Product Feed Endpoints ---
+
+// feedFiles maps the feed file names served to their format and content type.
+var feedFiles = map[string]struct {
+	format      domain.FeedFormat
+	contentType string
+}{
+	"google.xml":    {domain.FeedFormatGoogleXML, "application/xml; charset=utf-8"},
+	"google.tsv":    {domain.FeedFormatGoogleTSV, "text/tab-separated-values; charset=utf-8"},
+	"products.json": {domain.FeedFormatJSON, "application/json"},
+}
+
+// GetFeed handles GET /api/v1/feeds/:file?seller_id=
+// Serves google.xml, google.tsv or products.json for one seller, or for the
+// whole platform when seller_id is omitted.
+func (h *Handler) GetFeed(c *gin.Context) {
+	file, ok := feedFiles[c.Param("file")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "feed not found, use google.xml, google.tsv or products.json"})
+		return
+	}
+
+	c.Header("Content-Type", file.contentType)
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure can only cut the feed short.
+	if err := h.feedUC.WriteFeed(c.Request.Context(), c.Query("seller_id"), file.format, c.Writer); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// ListFeedCategoryMappings handles GET /api/v1/admin/feeds/categories
+func (h *Handler) ListFeedCategoryMappings(c *gin.Context) {
+	mappings, err := h.feedUC.ListCategoryMappings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"mappings": mappings})
+}
+
+type setFeedCategoryRequest struct {
+	GoogleCategory string `json:"google_category" binding:"required"`
+}
+
+// SetFeedCategoryMapping handles PUT /api/v1/admin/feeds/categories/:id
+func (h *Handler) SetFeedCategoryMapping(c *gin.Context) {
+	var req setFeedCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	mapping, err := h.feedUC.SetCategoryMapping(c.Request.Context(), c.Param("id"), req.GoogleCategory)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, mapping)
+}
+
+// DeleteFeedCategoryMapping handles DELETE /api/v1/admin/feeds/categories/:id
+func (h *Handler) DeleteFeedCategoryMapping(c *gin.Context) {
+	if err := h.feedUC.DeleteCategoryMapping(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "feed category mapping deleted"})
+}
+
+// RebuildFeeds handles POST /api/v1/admin/feeds/rebuild
+func (h *Handler) RebuildFeeds(c *gin.Context) {
+	n, err := h.feedUC.Rebuild(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"products_refreshed": n})
+}
+
+// --- Rating Endpoints ---
+
+// RebuildRatings handles POST /api/v1/admin/ratings/rebuild
+// Recomputes all product and seller ratings from the review service.
+func (h *Handler) RebuildRatings(c *gin.Context) {
+	n, err := h.ratingUC.RebuildRatings(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "products_updated": n})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"products_updated": n})
+}
+
+// RebuildProductRating handles POST /api/v1/admin/ratings/products/:id/rebuild
+func (h *Handler) RebuildProductRating(c *gin.Context) {
+	product, err := h.ratingUC.RebuildProductRating(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"product_id":   product.ID,
+		"rating_avg":   product.RatingAvg,
+		"rating_count": product.RatingCount,
+	})
+}
+
+// --- Product Moderation Endpoints ---
+
+// SubmitProduct handles POST /api/v1/seller/products/:id/submit
+// Submits a product for moderation; it goes live once approved.
+func (h *Handler) SubmitProduct(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	status := domain.ProductStatusActive
+	product, err := h.productUC.UpdateProduct(c.Request.Context(), c.Param("id"), sellerID, usecase.UpdateProductInput{Status: &status})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+// ListProductModeration handles GET /api/v1/seller/products/:id/moderation
+func (h *Handler) ListProductModeration(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	reviews, err := h.moderationUC.ListProductReviews(c.Request.Context(), c.Param("id"), sellerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews})
+}
+
+// ListModerationReviews handles GET /api/v1/admin/moderation/reviews
+func (h *Handler) ListModerationReviews(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	reviews, total, err := h.moderationUC.ListReviews(c.Request.Context(), usecase.ModerationQueueFilter{
+		Status:     c.Query("status"),
+		ReviewerID: c.Query("reviewer_id"),
+		SellerID:   c.Query("seller_id"),
+		Page:       page,
+		PageSize:   pageSize,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reviews":  reviews,
+		"total":    total,
+		"page":     page,
+		"pageSize": pageSize,
+	})
+}
+
+// GetModerationReview handles GET /api/v1/admin/moderation/reviews/:id
+func (h *Handler) GetModerationReview(c *gin.Context) {
+	review, err := h.moderationUC.GetReview(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+type assignReviewRequest struct {
+	ReviewerID string `json:"reviewer_id"`
+}
+
+// AssignModerationReview handles POST /api/v1/admin/moderation/reviews/:id/assign
+// Assigns the review to reviewer_id, or to the caller when it is omitted.
+func (h *Handler) AssignModerationReview(c *gin.Context) {
+	var req assignReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.ReviewerID == "" {
+		req.ReviewerID = c.GetHeader("X-User-ID")
+	}
+
+	review, err := h.moderationUC.AssignReview(c.Request.Context(), c.Param("id"), req.ReviewerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+type approveReviewRequest struct {
+	Note string `json:"note"`
+}
+
+// ApproveModerationReview handles POST /api/v1/admin/moderation/reviews/:id/approve
+func (h *Handler) ApproveModerationReview(c *gin.Context) {
+	reviewerID := c.GetHeader("X-User-ID")
+	if reviewerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req approveReviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	review, err := h.moderationUC.ApproveReview(c.Request.Context(), c.Param("id"), reviewerID, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+type rejectReviewRequest struct {
+	Reasons []domain.RejectionReason `json:"reasons" binding:"required"`
+	Note    string                   `json:"note"`
+}
+
+// RejectModerationReview handles POST /api/v1/admin/moderation/reviews/:id/reject
+func (h *Handler) RejectModerationReview(c *gin.Context) {
+	reviewerID := c.GetHeader("X-User-ID")
+	if reviewerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req rejectReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	review, err := h.moderationUC.RejectReview(c.Request.Context(), c.Param("id"), reviewerID, req.Reasons, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, review)
+}
+
+// ListProhibitedKeywords handles GET /api/v1/admin/moderation/keywords
+func (h *Handler) ListProhibitedKeywords(c *gin.Context) {
+	keywords, err := h.moderationUC.ListKeywords(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"keywords": keywords})
+}
+
+type addKeywordRequest struct {
+	Keyword string `json:"keyword" binding:"required"`
+}
+
+// AddProhibitedKeyword handles POST /api/v1/admin/moderation/keywords
+func (h *Handler) AddProhibitedKeyword(c *gin.Context) {
+	var req addKeywordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	keyword, err := h.moderationUC.AddKeyword(c.Request.Context(), req.Keyword)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, keyword)
+}
+
+// DeleteProhibitedKeyword handles DELETE /api/v1/admin/moderation/keywords/:id
+func (h *Handler) DeleteProhibitedKeyword(c *gin.Context) {
+	if err := h.moderationUC.DeleteKeyword(c.Request.Context(), c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "keyword deleted"})
+}
+
+// --- Translation Endpoints ---
+
+type productTranslationRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Slug        string `json:"slug"`
+}
+
+// SetProductTranslation handles PUT /api/v1/seller/products/:id/translations/:locale
+func (h *Handler) SetProductTranslation(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req productTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	translation, err := h.translationUC.SetProductTranslation(c.Request.Context(), c.Param("id"), sellerID, c.Param("locale"), usecase.ProductTranslationInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Slug:        req.Slug,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+// ListProductTranslations handles GET /api/v1/seller/products/:id/translations
+func (h *Handler) ListProductTranslations(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	translations, err := h.translationUC.ListProductTranslations(c.Request.Context(), c.Param("id"), sellerID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"translations": translations})
+}
+
+// DeleteProductTranslation handles DELETE /api/v1/seller/products/:id/translations/:locale
+func (h *Handler) DeleteProductTranslation(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	if err := h.translationUC.DeleteProductTranslation(c.Request.Context(), c.Param("id"), sellerID, c.Param("locale")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "translation deleted"})
+}
+
+type optionValueTranslationRequest struct {
+	Value string `json:"value" binding:"required"`
+}
+
+// SetOptionValueTranslation handles PUT /api/v1/seller/products/:id/option-values/:valueId/translations/:locale
+func (h *Handler) SetOptionValueTranslation(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	var req optionValueTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	translation, err := h.translationUC.SetOptionValueTranslation(c.Request.Context(), c.Param("id"), sellerID, c.Param("valueId"), c.Param("locale"), req.Value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+// DeleteOptionValueTranslation handles DELETE /api/v1/seller/products/:id/option-values/:valueId/translations/:locale
+func (h *Handler) DeleteOptionValueTranslation(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing X-User-ID header"})
+		return
+	}
+
+	if err := h.translationUC.DeleteOptionValueTranslation(c.Request.Context(), c.Param("id"), sellerID, c.Param("valueId"), c.Param("locale")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "translation deleted"})
+}
+
+type categoryTranslationRequest struct {
+	Name string `json:"name" binding:"required"`
+	Slug string `json:"slug"`
+}
+
+// SetCategoryTranslation handles PUT /api/v1/admin/categories/:id/translations/:locale
+func (h *Handler) SetCategoryTranslation(c *gin.Context) {
+	var req categoryTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	translation, err := h.translationUC.SetCategoryTranslation(c.Request.Context(), c.Param("id"), c.Param("locale"), usecase.CategoryTranslationInput{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, translation)
+}
+
+// DeleteCategoryTranslation handles DELETE /api/v1/admin/categories/:id/translations/:locale
+func (h *Handler) DeleteCategoryTranslation(c *gin.Context) {
+	if err := h.translationUC.DeleteCategoryTranslation(c.Request.Context(), c.Param("id"), c.Param("locale")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "translation deleted"})
 }
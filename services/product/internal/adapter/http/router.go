@@ -2,13 +2,20 @@ package http
 
 import (
 	"github.com/gin-gonic/gin"
+
+	"github.com/southern-martin/ecommerce/pkg/currency"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 )
 
 // NewRouter creates and configures the Gin router with all product service routes.
-func NewRouter(h *Handler) *gin.Engine {
+// Catalog content is served in the language negotiated from Accept-Language
+// and priced in the currency selected by the currency middleware.
+func NewRouter(h *Handler, bundle *i18n.Bundle) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
+	r.Use(i18n.GinMiddleware(bundle))
+	r.Use(currency.GinMiddleware())
 
 	// Health check
 	r.GET("/health", h.Health)
@@ -21,10 +28,19 @@ func NewRouter(h *Handler) *gin.Engine {
 			products.GET("", h.ListProducts)
 			products.GET("/:id", h.GetProduct)
 			products.GET("/slug/:slug", h.GetProductBySlug)
+			products.GET("/:id/options", h.ListProductOptions)
+			products.GET("/:id/variants/:variantId/price", h.GetVariantPrice)
+			products.GET("/:id/variants/:variantId/price-history", h.GetVariantPriceHistory)
+			products.GET("/:id/subscription-plans", h.ListSubscriptionPlans)
+			products.GET("/:id/subscription-plans/:planId", h.GetSubscriptionPlan)
 		}
 
 		// Public category endpoints
 		v1.GET("/categories", h.ListCategories)
+		v1.GET("/categories/tree", h.GetCategoryTree)
+
+		// Public product feeds for shopping channels
+		v1.GET("/feeds/:file", h.GetFeed)
 
 		// Seller endpoints (X-User-ID header required via Kong)
 		seller := v1.Group("/seller")
@@ -34,22 +50,83 @@ func NewRouter(h *Handler) *gin.Engine {
 				sellerProducts.POST("", h.CreateProduct)
 				sellerProducts.PATCH("/:id", h.UpdateProduct)
 				sellerProducts.DELETE("/:id", h.DeleteProduct)
+				sellerProducts.POST("/:id/submit", h.SubmitProduct)
+				sellerProducts.GET("/:id/moderation", h.ListProductModeration)
 				sellerProducts.POST("/:id/options", h.AddOption)
 				sellerProducts.DELETE("/:id/options/:optionId", h.RemoveOption)
 				sellerProducts.POST("/:id/variants/generate", h.GenerateVariants)
 				sellerProducts.PATCH("/:id/variants/:variantId", h.UpdateVariant)
 				sellerProducts.PATCH("/:id/variants/:variantId/stock", h.UpdateVariantStock)
+				sellerProducts.POST("/:id/variants/:variantId/price-schedules", h.SchedulePrice)
+				sellerProducts.GET("/:id/variants/:variantId/price-schedules", h.ListPriceSchedules)
+				sellerProducts.POST("/:id/price-schedules/:scheduleId/cancel", h.CancelPriceSchedule)
+				sellerProducts.GET("/:id/variants/:variantId/prices", h.ListVariantCurrencyPrices)
+				sellerProducts.PUT("/:id/variants/:variantId/prices/:currency", h.SetVariantCurrencyPrice)
+				sellerProducts.DELETE("/:id/variants/:variantId/prices/:currency", h.DeleteVariantCurrencyPrice)
+				sellerProducts.POST("/:id/variants/:variantId/subscription-plans", h.CreateSubscriptionPlan)
+				sellerProducts.GET("/:id/subscription-plans", h.ListSellerSubscriptionPlans)
+				sellerProducts.PATCH("/:id/subscription-plans/:planId", h.UpdateSubscriptionPlan)
+				sellerProducts.DELETE("/:id/subscription-plans/:planId", h.DeleteSubscriptionPlan)
+				sellerProducts.POST("/:id/files", h.AttachDigitalFile)
+				sellerProducts.GET("/:id/files", h.ListDigitalFiles)
+				sellerProducts.DELETE("/:id/files/:fileId", h.DeleteDigitalFile)
+				sellerProducts.POST("/:id/license-keys", h.AddLicenseKeys)
+				sellerProducts.GET("/:id/license-keys", h.ListLicenseKeys)
+				sellerProducts.DELETE("/:id/license-keys/:keyId", h.DeleteLicenseKey)
+				sellerProducts.GET("/:id/translations", h.ListProductTranslations)
+				sellerProducts.PUT("/:id/translations/:locale", h.SetProductTranslation)
+				sellerProducts.DELETE("/:id/translations/:locale", h.DeleteProductTranslation)
+				sellerProducts.PUT("/:id/option-values/:valueId/translations/:locale", h.SetOptionValueTranslation)
+				sellerProducts.DELETE("/:id/option-values/:valueId/translations/:locale", h.DeleteOptionValueTranslation)
+			}
+
+			sellerCatalog := seller.Group("/catalog")
+			{
+				sellerCatalog.POST("/imports", h.ImportCatalog)
+				sellerCatalog.GET("/imports", h.ListImportJobs)
+				sellerCatalog.GET("/imports/:id", h.GetImportJob)
+				sellerCatalog.GET("/export", h.ExportCatalog)
 			}
 		}
 
+		// Buyer digital purchase endpoints (X-User-ID header required via Kong)
+		digital := v1.Group("/digital")
+		{
+			digital.GET("/entitlements", h.ListEntitlements)
+			digital.GET("/entitlements/:id", h.GetEntitlement)
+			digital.POST("/entitlements/:id/files/:fileId/download-link", h.CreateDownloadLink)
+		}
+
 		// Admin endpoints
 		admin := v1.Group("/admin")
 		{
 			admin.POST("/categories", h.CreateCategory)
+			admin.POST("/categories/:id/move", h.MoveCategory)
+			admin.PUT("/categories/order", h.ReorderCategories)
+			admin.PUT("/categories/:id/translations/:locale", h.SetCategoryTranslation)
+			admin.DELETE("/categories/:id/translations/:locale", h.DeleteCategoryTranslation)
 			admin.POST("/attributes", h.CreateAttributeDefinition)
 			admin.GET("/attributes", h.ListAttributeDefinitions)
 			admin.PATCH("/attributes/:id", h.UpdateAttributeDefinition)
 			admin.DELETE("/attributes/:id", h.DeleteAttributeDefinition)
+			admin.GET("/feeds/categories", h.ListFeedCategoryMappings)
+			admin.PUT("/feeds/categories/:id", h.SetFeedCategoryMapping)
+			admin.DELETE("/feeds/categories/:id", h.DeleteFeedCategoryMapping)
+			admin.POST("/feeds/rebuild", h.RebuildFeeds)
+			admin.POST("/ratings/rebuild", h.RebuildRatings)
+			admin.POST("/ratings/products/:id/rebuild", h.RebuildProductRating)
+
+			moderation := admin.Group("/moderation")
+			{
+				moderation.GET("/reviews", h.ListModerationReviews)
+				moderation.GET("/reviews/:id", h.GetModerationReview)
+				moderation.POST("/reviews/:id/assign", h.AssignModerationReview)
+				moderation.POST("/reviews/:id/approve", h.ApproveModerationReview)
+				moderation.POST("/reviews/:id/reject", h.RejectModerationReview)
+				moderation.GET("/keywords", h.ListProhibitedKeywords)
+				moderation.POST("/keywords", h.AddProhibitedKeyword)
+				moderation.DELETE("/keywords/:id", h.DeleteProhibitedKeyword)
+			}
 		}
 
 		// Category attribute assignment endpoints
@@ -58,6 +135,8 @@ func NewRouter(h *Handler) *gin.Engine {
 			categories.POST("/:id/attributes", h.AssignAttributeToCategory)
 			categories.DELETE("/:id/attributes/:attrId", h.RemoveAttributeFromCategory)
 			categories.GET("/:id/attributes", h.ListCategoryAttributes)
+			categories.GET("/:id/schema", h.GetCategorySchema)
+			categories.GET("/:id/path", h.GetCategoryPath)
 		}
 	}
 
@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// CatalogTransactor implements domain.CatalogTransactor using GORM.
+type CatalogTransactor struct {
+	db *gorm.DB
+}
+
+// NewCatalogTransactor creates a new CatalogTransactor.
+func NewCatalogTransactor(db *gorm.DB) *CatalogTransactor {
+	return &CatalogTransactor{db: db}
+}
+
+// Transaction calls fn with repositories bound to one transaction.
+func (t *CatalogTransactor) Transaction(ctx context.Context, fn func(repos domain.CatalogRepositories) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(domain.CatalogRepositories{
+			Products:          NewProductRepo(tx),
+			Categories:        NewCategoryRepo(tx),
+			Attributes:        NewAttributeRepo(tx),
+			Options:           NewOptionRepo(tx),
+			Variants:          NewVariantRepo(tx),
+			ModerationReviews: NewModerationReviewRepo(tx),
+			PriceHistory:      NewPriceHistoryRepo(tx),
+		})
+	})
+}
@@ -50,6 +50,17 @@ func (r *CategoryRepo) Update(ctx context.Context, c *domain.Category) error {
 	return r.db.WithContext(ctx).Save(model).Error
 }
 
+func (r *CategoryRepo) UpdateSortOrders(ctx context.Context, sortOrders map[string]int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, sortOrder := range sortOrders {
+			if err := tx.Model(&CategoryModel{}).Where("id = ?", id).Update("sort_order", sortOrder).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *CategoryRepo) Delete(ctx context.Context, id string) error {
 	return r.db.WithContext(ctx).Delete(&CategoryModel{}, "id = ?", id).Error
 }
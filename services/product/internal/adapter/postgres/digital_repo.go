@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// DigitalFileRepo implements domain.DigitalFileRepository using GORM.
+type DigitalFileRepo struct {
+	db *gorm.DB
+}
+
+// NewDigitalFileRepo creates a new DigitalFileRepo.
+func NewDigitalFileRepo(db *gorm.DB) *DigitalFileRepo {
+	return &DigitalFileRepo{db: db}
+}
+
+func (r *DigitalFileRepo) Create(ctx context.Context, f *domain.DigitalFile) error {
+	model := DigitalFileModelFromDomain(f)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+func (r *DigitalFileRepo) GetByID(ctx context.Context, id string) (*domain.DigitalFile, error) {
+	var model DigitalFileModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, fmt.Errorf("digital file not found: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *DigitalFileRepo) ListByProduct(ctx context.Context, productID string) ([]*domain.DigitalFile, error) {
+	var models []DigitalFileModel
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	files := make([]*domain.DigitalFile, len(models))
+	for i := range models {
+		files[i] = models[i].ToDomain()
+	}
+	return files, nil
+}
+
+func (r *DigitalFileRepo) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&DigitalFileModel{}, "id = ?", id).Error
+}
+
+// LicenseKeyRepo implements domain.LicenseKeyRepository using GORM.
+type LicenseKeyRepo struct {
+	db *gorm.DB
+}
+
+// NewLicenseKeyRepo creates a new LicenseKeyRepo.
+func NewLicenseKeyRepo(db *gorm.DB) *LicenseKeyRepo {
+	return &LicenseKeyRepo{db: db}
+}
+
+func (r *LicenseKeyRepo) CreateBatch(ctx context.Context, keys []*domain.LicenseKey) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	models := make([]*LicenseKeyModel, len(keys))
+	for i, k := range keys {
+		models[i] = LicenseKeyModelFromDomain(k)
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return int(result.RowsAffected), nil
+}
+
+func (r *LicenseKeyRepo) GetByID(ctx context.Context, id string) (*domain.LicenseKey, error) {
+	var model LicenseKeyModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, fmt.Errorf("license key not found: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *LicenseKeyRepo) ListByProduct(ctx context.Context, productID string, status domain.LicenseKeyStatus) ([]*domain.LicenseKey, error) {
+	query := r.db.WithContext(ctx).Where("product_id = ?", productID)
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+	var models []LicenseKeyModel
+	if err := query.Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return licenseKeysToDomain(models), nil
+}
+
+func (r *LicenseKeyRepo) ListByEntitlement(ctx context.Context, entitlementID string) ([]*domain.LicenseKey, error) {
+	var models []LicenseKeyModel
+	if err := r.db.WithContext(ctx).Where("entitlement_id = ?", entitlementID).Order("assigned_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return licenseKeysToDomain(models), nil
+}
+
+func (r *LicenseKeyRepo) ClaimAvailable(ctx context.Context, productID, variantID, entitlementID string, n int) ([]*domain.LicenseKey, error) {
+	var models []LicenseKeyModel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED keeps concurrent fulfilments from handing out the same key.
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("product_id = ? AND status = ?", productID, string(domain.LicenseKeyStatusAvailable))
+		if variantID != "" {
+			query = query.Where("(variant_id IS NULL OR variant_id = ?)", variantID)
+		} else {
+			query = query.Where("variant_id IS NULL")
+		}
+		if err := query.Order("created_at ASC").Limit(n).Find(&models).Error; err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		ids := make([]string, len(models))
+		for i := range models {
+			ids[i] = models[i].ID
+			models[i].Status = string(domain.LicenseKeyStatusAssigned)
+			models[i].EntitlementID = &entitlementID
+			models[i].AssignedAt = &now
+		}
+		return tx.Model(&LicenseKeyModel{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":         string(domain.LicenseKeyStatusAssigned),
+				"entitlement_id": entitlementID,
+				"assigned_at":    now,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return licenseKeysToDomain(models), nil
+}
+
+func (r *LicenseKeyRepo) RevokeByEntitlement(ctx context.Context, entitlementID string) error {
+	return r.db.WithContext(ctx).Model(&LicenseKeyModel{}).
+		Where("entitlement_id = ?", entitlementID).
+		Update("status", string(domain.LicenseKeyStatusRevoked)).Error
+}
+
+func (r *LicenseKeyRepo) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&LicenseKeyModel{}, "id = ?", id).Error
+}
+
+func licenseKeysToDomain(models []LicenseKeyModel) []*domain.LicenseKey {
+	keys := make([]*domain.LicenseKey, len(models))
+	for i := range models {
+		keys[i] = models[i].ToDomain()
+	}
+	return keys
+}
+
+// DigitalEntitlementRepo implements domain.DigitalEntitlementRepository using GORM.
+type DigitalEntitlementRepo struct {
+	db *gorm.DB
+}
+
+// NewDigitalEntitlementRepo creates a new DigitalEntitlementRepo.
+func NewDigitalEntitlementRepo(db *gorm.DB) *DigitalEntitlementRepo {
+	return &DigitalEntitlementRepo{db: db}
+}
+
+func (r *DigitalEntitlementRepo) Create(ctx context.Context, e *domain.DigitalEntitlement) error {
+	model := DigitalEntitlementModelFromDomain(e)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+func (r *DigitalEntitlementRepo) GetByID(ctx context.Context, id string) (*domain.DigitalEntitlement, error) {
+	var model DigitalEntitlementModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, fmt.Errorf("entitlement not found: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *DigitalEntitlementRepo) Update(ctx context.Context, e *domain.DigitalEntitlement) error {
+	model := DigitalEntitlementModelFromDomain(e)
+	return r.db.WithContext(ctx).Save(model).Error
+}
+
+func (r *DigitalEntitlementRepo) ListByOrder(ctx context.Context, orderID string) ([]*domain.DigitalEntitlement, error) {
+	var models []DigitalEntitlementModel
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return entitlementsToDomain(models), nil
+}
+
+func (r *DigitalEntitlementRepo) ListByBuyer(ctx context.Context, buyerID string, page, pageSize int) ([]*domain.DigitalEntitlement, int64, error) {
+	query := r.db.WithContext(ctx).Model(&DigitalEntitlementModel{}).
+		Where("buyer_id = ? AND status <> ?", buyerID, string(domain.EntitlementStatusPending))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []DigitalEntitlementModel
+	if err := query.Order("created_at DESC").Offset((page - 1) * pageSize).Limit(pageSize).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+	return entitlementsToDomain(models), total, nil
+}
+
+func (r *DigitalEntitlementRepo) ListAwaitingKeys(ctx context.Context, productID string) ([]*domain.DigitalEntitlement, error) {
+	var models []DigitalEntitlementModel
+	if err := r.db.WithContext(ctx).
+		Where("product_id = ? AND status = ? AND keys_pending > 0", productID, string(domain.EntitlementStatusActive)).
+		Order("activated_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return entitlementsToDomain(models), nil
+}
+
+func (r *DigitalEntitlementRepo) IncrementDownloads(ctx context.Context, id string) (bool, error) {
+	// The limit is checked in the UPDATE itself so concurrent requests
+	// cannot exceed it.
+	result := r.db.WithContext(ctx).Model(&DigitalEntitlementModel{}).
+		Where("id = ? AND (download_limit = 0 OR download_count < download_limit)", id).
+		Updates(map[string]interface{}{
+			"download_count": gorm.Expr("download_count + 1"),
+			"updated_at":     time.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func entitlementsToDomain(models []DigitalEntitlementModel) []*domain.DigitalEntitlement {
+	entitlements := make([]*domain.DigitalEntitlement, len(models))
+	for i := range models {
+		entitlements[i] = models[i].ToDomain()
+	}
+	return entitlements
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// FeedItemRepo implements domain.FeedItemRepository using GORM.
+type FeedItemRepo struct {
+	db *gorm.DB
+}
+
+// NewFeedItemRepo creates a new FeedItemRepo.
+func NewFeedItemRepo(db *gorm.DB) *FeedItemRepo {
+	return &FeedItemRepo{db: db}
+}
+
+func (r *FeedItemRepo) ReplaceForProduct(ctx context.Context, productID string, items []domain.FeedItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&FeedItemModel{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		models := make([]*FeedItemModel, len(items))
+		for i := range items {
+			models[i] = FeedItemModelFromDomain(&items[i])
+		}
+		return tx.Create(models).Error
+	})
+}
+
+func (r *FeedItemRepo) DeleteByProduct(ctx context.Context, productID string) error {
+	return r.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&FeedItemModel{}).Error
+}
+
+func (r *FeedItemRepo) DeleteUpdatedBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("updated_at < ?", before).Delete(&FeedItemModel{}).Error
+}
+
+func (r *FeedItemRepo) ListAfter(ctx context.Context, sellerID string, afterID string, limit int) ([]domain.FeedItem, error) {
+	query := r.db.WithContext(ctx).Model(&FeedItemModel{})
+	if sellerID != "" {
+		query = query.Where("seller_id = ?", sellerID)
+	}
+	if afterID != "" {
+		query = query.Where("id > ?", afterID)
+	}
+
+	var models []FeedItemModel
+	if err := query.Order("id ASC").Limit(limit).Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.FeedItem, len(models))
+	for i := range models {
+		items[i] = models[i].ToDomain()
+	}
+	return items, nil
+}
+
+// FeedCategoryMappingRepo implements domain.FeedCategoryMappingRepository using GORM.
+type FeedCategoryMappingRepo struct {
+	db *gorm.DB
+}
+
+// NewFeedCategoryMappingRepo creates a new FeedCategoryMappingRepo.
+func NewFeedCategoryMappingRepo(db *gorm.DB) *FeedCategoryMappingRepo {
+	return &FeedCategoryMappingRepo{db: db}
+}
+
+func (r *FeedCategoryMappingRepo) Upsert(ctx context.Context, m *domain.FeedCategoryMapping) error {
+	model := FeedCategoryMappingModelFromDomain(m)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"google_category", "updated_at"}),
+	}).Create(model).Error
+}
+
+func (r *FeedCategoryMappingRepo) Delete(ctx context.Context, categoryID string) error {
+	return r.db.WithContext(ctx).Delete(&FeedCategoryMappingModel{}, "category_id = ?", categoryID).Error
+}
+
+func (r *FeedCategoryMappingRepo) List(ctx context.Context) ([]*domain.FeedCategoryMapping, error) {
+	var models []FeedCategoryMappingModel
+	if err := r.db.WithContext(ctx).Order("category_id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	mappings := make([]*domain.FeedCategoryMapping, len(models))
+	for i := range models {
+		mappings[i] = models[i].ToDomain()
+	}
+	return mappings, nil
+}
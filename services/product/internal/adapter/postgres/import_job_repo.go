@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// ImportJobRepo implements domain.ImportJobRepository using GORM.
+type ImportJobRepo struct {
+	db *gorm.DB
+}
+
+// NewImportJobRepo creates a new ImportJobRepo.
+func NewImportJobRepo(db *gorm.DB) *ImportJobRepo {
+	return &ImportJobRepo{db: db}
+}
+
+func (r *ImportJobRepo) Create(ctx context.Context, job *domain.ImportJob) error {
+	model := ImportJobModelFromDomain(job)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+func (r *ImportJobRepo) GetByID(ctx context.Context, id string) (*domain.ImportJob, error) {
+	var model ImportJobModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, fmt.Errorf("import job not found: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *ImportJobRepo) ListBySeller(ctx context.Context, sellerID string, page, pageSize int) ([]*domain.ImportJob, int64, error) {
+	query := r.db.WithContext(ctx).Model(&ImportJobModel{}).Where("seller_id = ?", sellerID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []ImportJobModel
+	offset := (page - 1) * pageSize
+	if err := query.Omit("Payload").Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	jobs := make([]*domain.ImportJob, len(models))
+	for i := range models {
+		jobs[i] = models[i].ToDomain()
+	}
+	return jobs, total, nil
+}
+
+func (r *ImportJobRepo) ClaimNext(ctx context.Context, staleBefore time.Time) (*domain.ImportJob, error) {
+	var model ImportJobModel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED lets several replicas claim different jobs concurrently.
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND started_at < ?)",
+				domain.ImportJobStatusPending, domain.ImportJobStatusRunning, staleBefore).
+			Order("created_at ASC").
+			First(&model).Error; err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		model.Status = string(domain.ImportJobStatusRunning)
+		model.StartedAt = &now
+		return tx.Model(&ImportJobModel{}).
+			Where("id = ?", model.ID).
+			Updates(map[string]interface{}{"status": model.Status, "started_at": now}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *ImportJobRepo) Update(ctx context.Context, job *domain.ImportJob) error {
+	model := ImportJobModelFromDomain(job)
+	return r.db.WithContext(ctx).Save(model).Error
+}
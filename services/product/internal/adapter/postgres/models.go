@@ -1,6 +1,9 @@
 package postgres
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/lib/pq"
@@ -10,22 +13,26 @@ import (
 
 // ProductModel is the GORM model for the products table.
 type ProductModel struct {
-	ID             string         `gorm:"type:uuid;primaryKey"`
-	SellerID       string         `gorm:"type:uuid;not null;index"`
-	CategoryID     *string        `gorm:"type:uuid;index"`
-	Name           string         `gorm:"type:varchar(500);not null"`
-	Slug           string         `gorm:"type:varchar(600);uniqueIndex;not null"`
-	Description    string         `gorm:"type:text"`
-	BasePriceCents int64          `gorm:"not null;default:0"`
-	Currency       string         `gorm:"type:varchar(3);not null;default:'USD'"`
-	Status         string         `gorm:"type:varchar(20);not null;default:'draft';index"`
-	HasVariants    bool           `gorm:"not null;default:false"`
-	Tags           pq.StringArray `gorm:"type:text[]"`
-	ImageURLs      pq.StringArray `gorm:"type:text[];column:image_urls"`
-	RatingAvg      float64        `gorm:"not null;default:0"`
-	RatingCount    int            `gorm:"not null;default:0"`
-	CreatedAt      time.Time      `gorm:"not null"`
-	UpdatedAt      time.Time      `gorm:"not null"`
+	ID                 string         `gorm:"type:uuid;primaryKey"`
+	SellerID           string         `gorm:"type:uuid;not null;index"`
+	CategoryID         *string        `gorm:"type:uuid;index"`
+	Name               string         `gorm:"type:varchar(500);not null"`
+	Slug               string         `gorm:"type:varchar(600);uniqueIndex;not null"`
+	Description        string         `gorm:"type:text"`
+	BasePriceCents     int64          `gorm:"not null;default:0"`
+	Currency           string         `gorm:"type:varchar(3);not null;default:'USD'"`
+	Status             string         `gorm:"type:varchar(20);not null;default:'draft';index"`
+	Type               string         `gorm:"type:varchar(20);not null;default:'physical'"`
+	HasVariants        bool           `gorm:"not null;default:false"`
+	Tags               pq.StringArray `gorm:"type:text[]"`
+	ImageURLs          pq.StringArray `gorm:"type:text[];column:image_urls"`
+	RatingAvg          float64        `gorm:"not null;default:0"`
+	RatingCount        int            `gorm:"not null;default:0"`
+	DownloadLimit      int            `gorm:"not null;default:0"`
+	DownloadExpiryDays int            `gorm:"not null;default:0"`
+	RequiresLicenseKey bool           `gorm:"not null;default:false"`
+	CreatedAt          time.Time      `gorm:"not null"`
+	UpdatedAt          time.Time      `gorm:"not null"`
 }
 
 func (ProductModel) TableName() string { return "products" }
@@ -35,23 +42,31 @@ func (m *ProductModel) ToDomain() *domain.Product {
 	if m.CategoryID != nil {
 		catID = *m.CategoryID
 	}
+	productType := domain.ProductType(m.Type)
+	if productType == "" {
+		productType = domain.ProductTypePhysical
+	}
 	return &domain.Product{
-		ID:             m.ID,
-		SellerID:       m.SellerID,
-		CategoryID:     catID,
-		Name:           m.Name,
-		Slug:           m.Slug,
-		Description:    m.Description,
-		BasePriceCents: m.BasePriceCents,
-		Currency:       m.Currency,
-		Status:         domain.ProductStatus(m.Status),
-		HasVariants:    m.HasVariants,
-		Tags:           m.Tags,
-		ImageURLs:      m.ImageURLs,
-		RatingAvg:      m.RatingAvg,
-		RatingCount:    m.RatingCount,
-		CreatedAt:      m.CreatedAt,
-		UpdatedAt:      m.UpdatedAt,
+		ID:                 m.ID,
+		SellerID:           m.SellerID,
+		CategoryID:         catID,
+		Name:               m.Name,
+		Slug:               m.Slug,
+		Description:        m.Description,
+		BasePriceCents:     m.BasePriceCents,
+		Currency:           m.Currency,
+		Status:             domain.ProductStatus(m.Status),
+		Type:               productType,
+		HasVariants:        m.HasVariants,
+		Tags:               m.Tags,
+		ImageURLs:          m.ImageURLs,
+		RatingAvg:          m.RatingAvg,
+		RatingCount:        m.RatingCount,
+		DownloadLimit:      m.DownloadLimit,
+		DownloadExpiryDays: m.DownloadExpiryDays,
+		RequiresLicenseKey: m.RequiresLicenseKey,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
 	}
 }
 
@@ -60,23 +75,31 @@ func ProductModelFromDomain(p *domain.Product) *ProductModel {
 	if p.CategoryID != "" {
 		catID = &p.CategoryID
 	}
+	productType := string(p.Type)
+	if productType == "" {
+		productType = string(domain.ProductTypePhysical)
+	}
 	return &ProductModel{
-		ID:             p.ID,
-		SellerID:       p.SellerID,
-		CategoryID:     catID,
-		Name:           p.Name,
-		Slug:           p.Slug,
-		Description:    p.Description,
-		BasePriceCents: p.BasePriceCents,
-		Currency:       p.Currency,
-		Status:         string(p.Status),
-		HasVariants:    p.HasVariants,
-		Tags:           p.Tags,
-		ImageURLs:      p.ImageURLs,
-		RatingAvg:      p.RatingAvg,
-		RatingCount:    p.RatingCount,
-		CreatedAt:      p.CreatedAt,
-		UpdatedAt:      p.UpdatedAt,
+		ID:                 p.ID,
+		SellerID:           p.SellerID,
+		CategoryID:         catID,
+		Name:               p.Name,
+		Slug:               p.Slug,
+		Description:        p.Description,
+		BasePriceCents:     p.BasePriceCents,
+		Currency:           p.Currency,
+		Status:             string(p.Status),
+		Type:               productType,
+		HasVariants:        p.HasVariants,
+		Tags:               p.Tags,
+		ImageURLs:          p.ImageURLs,
+		RatingAvg:          p.RatingAvg,
+		RatingCount:        p.RatingCount,
+		DownloadLimit:      p.DownloadLimit,
+		DownloadExpiryDays: p.DownloadExpiryDays,
+		RequiresLicenseKey: p.RequiresLicenseKey,
+		CreatedAt:          p.CreatedAt,
+		UpdatedAt:          p.UpdatedAt,
 	}
 }
 
@@ -138,8 +161,10 @@ type AttributeDefinitionModel struct {
 	Filterable bool           `gorm:"not null;default:false"`
 	Options    pq.StringArray `gorm:"type:text[]"`
 	Unit       string         `gorm:"type:varchar(50)"`
-	SortOrder  int            `gorm:"not null;default:0"`
-	CreatedAt  time.Time      `gorm:"not null"`
+	MinValue   *float64
+	MaxValue   *float64
+	SortOrder  int       `gorm:"not null;default:0"`
+	CreatedAt  time.Time `gorm:"not null"`
 }
 
 func (AttributeDefinitionModel) TableName() string { return "attribute_definitions" }
@@ -154,6 +179,8 @@ func (m *AttributeDefinitionModel) ToDomain() *domain.AttributeDefinition {
 		Filterable: m.Filterable,
 		Options:    m.Options,
 		Unit:       m.Unit,
+		MinValue:   m.MinValue,
+		MaxValue:   m.MaxValue,
 		SortOrder:  m.SortOrder,
 		CreatedAt:  m.CreatedAt,
 	}
@@ -169,6 +196,8 @@ func AttributeDefinitionModelFromDomain(a *domain.AttributeDefinition) *Attribut
 		Filterable: a.Filterable,
 		Options:    a.Options,
 		Unit:       a.Unit,
+		MinValue:   a.MinValue,
+		MaxValue:   a.MaxValue,
 		SortOrder:  a.SortOrder,
 		CreatedAt:  a.CreatedAt,
 	}
@@ -351,3 +380,763 @@ func (m *VariantOptionValueModel) ToDomain() domain.VariantOptionValue {
 		Value:         m.Value,
 	}
 }
+
+// ImportRowErrorsJSON is a GORM-compatible JSONB type for import row errors.
+type ImportRowErrorsJSON []domain.ImportRowError
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (e ImportRowErrorsJSON) Value() (driver.Value, error) {
+	if e == nil {
+		return json.Marshal([]domain.ImportRowError{})
+	}
+	return json.Marshal([]domain.ImportRowError(e))
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval.
+func (e *ImportRowErrorsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*e = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan ImportRowErrorsJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, e)
+}
+
+// ImportJobModel is the GORM model for the product_import_jobs table.
+type ImportJobModel struct {
+	ID              string              `gorm:"type:uuid;primaryKey"`
+	SellerID        string              `gorm:"type:uuid;not null;index"`
+	Format          string              `gorm:"type:varchar(10);not null"`
+	DryRun          bool                `gorm:"not null;default:false"`
+	Status          string              `gorm:"type:varchar(20);not null;default:'pending';index"`
+	Payload         []byte              `gorm:"type:bytea"`
+	TotalRows       int                 `gorm:"not null;default:0"`
+	ProductsCreated int                 `gorm:"not null;default:0"`
+	ProductsUpdated int                 `gorm:"not null;default:0"`
+	VariantsCreated int                 `gorm:"not null;default:0"`
+	VariantsUpdated int                 `gorm:"not null;default:0"`
+	FailedRows      int                 `gorm:"not null;default:0"`
+	RowErrors       ImportRowErrorsJSON `gorm:"type:jsonb"`
+	Error           string              `gorm:"type:text"`
+	CreatedAt       time.Time           `gorm:"not null;index"`
+	StartedAt       *time.Time
+	FinishedAt      *time.Time
+}
+
+func (ImportJobModel) TableName() string { return "product_import_jobs" }
+
+func (m *ImportJobModel) ToDomain() *domain.ImportJob {
+	return &domain.ImportJob{
+		ID:              m.ID,
+		SellerID:        m.SellerID,
+		Format:          domain.ImportFormat(m.Format),
+		DryRun:          m.DryRun,
+		Status:          domain.ImportJobStatus(m.Status),
+		Payload:         m.Payload,
+		TotalRows:       m.TotalRows,
+		ProductsCreated: m.ProductsCreated,
+		ProductsUpdated: m.ProductsUpdated,
+		VariantsCreated: m.VariantsCreated,
+		VariantsUpdated: m.VariantsUpdated,
+		FailedRows:      m.FailedRows,
+		RowErrors:       m.RowErrors,
+		Error:           m.Error,
+		CreatedAt:       m.CreatedAt,
+		StartedAt:       m.StartedAt,
+		FinishedAt:      m.FinishedAt,
+	}
+}
+
+func ImportJobModelFromDomain(j *domain.ImportJob) *ImportJobModel {
+	return &ImportJobModel{
+		ID:              j.ID,
+		SellerID:        j.SellerID,
+		Format:          string(j.Format),
+		DryRun:          j.DryRun,
+		Status:          string(j.Status),
+		Payload:         j.Payload,
+		TotalRows:       j.TotalRows,
+		ProductsCreated: j.ProductsCreated,
+		ProductsUpdated: j.ProductsUpdated,
+		VariantsCreated: j.VariantsCreated,
+		VariantsUpdated: j.VariantsUpdated,
+		FailedRows:      j.FailedRows,
+		RowErrors:       j.RowErrors,
+		Error:           j.Error,
+		CreatedAt:       j.CreatedAt,
+		StartedAt:       j.StartedAt,
+		FinishedAt:      j.FinishedAt,
+	}
+}
+
+// FeedItemModel is the GORM model for the product_feed_items table.
+type FeedItemModel struct {
+	ID                    string         `gorm:"type:uuid;primaryKey"`
+	ItemGroupID           string         `gorm:"type:varchar(64)"`
+	ProductID             string         `gorm:"type:uuid;not null;index"`
+	SellerID              string         `gorm:"type:uuid;not null;index"`
+	SKU                   string         `gorm:"type:varchar(100);not null"`
+	Title                 string         `gorm:"type:varchar(500);not null"`
+	Description           string         `gorm:"type:text"`
+	Link                  string         `gorm:"type:text;not null"`
+	ImageURL              string         `gorm:"type:text;column:image_url"`
+	AdditionalImageURLs   pq.StringArray `gorm:"type:text[];column:additional_image_urls"`
+	PriceCents            int64          `gorm:"not null"`
+	SalePriceCents        int64          `gorm:"not null;default:0"`
+	Currency              string         `gorm:"type:varchar(3);not null"`
+	Availability          string         `gorm:"type:varchar(20);not null"`
+	Stock                 int            `gorm:"not null;default:0"`
+	GTIN                  string         `gorm:"type:varchar(50);column:gtin"`
+	Brand                 string         `gorm:"type:varchar(200)"`
+	Color                 string         `gorm:"type:varchar(100)"`
+	Size                  string         `gorm:"type:varchar(100)"`
+	WeightGrams           int            `gorm:"not null;default:0"`
+	CategoryID            string         `gorm:"type:varchar(64)"`
+	GoogleProductCategory string         `gorm:"type:varchar(500)"`
+	ProductType           string         `gorm:"type:varchar(750)"`
+	UpdatedAt             time.Time      `gorm:"not null;index"`
+}
+
+func (FeedItemModel) TableName() string { return "product_feed_items" }
+
+func (m *FeedItemModel) ToDomain() domain.FeedItem {
+	return domain.FeedItem{
+		ID:                    m.ID,
+		ItemGroupID:           m.ItemGroupID,
+		ProductID:             m.ProductID,
+		SellerID:              m.SellerID,
+		SKU:                   m.SKU,
+		Title:                 m.Title,
+		Description:           m.Description,
+		Link:                  m.Link,
+		ImageURL:              m.ImageURL,
+		AdditionalImageURLs:   m.AdditionalImageURLs,
+		PriceCents:            m.PriceCents,
+		SalePriceCents:        m.SalePriceCents,
+		Currency:              m.Currency,
+		Availability:          domain.FeedAvailability(m.Availability),
+		Stock:                 m.Stock,
+		GTIN:                  m.GTIN,
+		Brand:                 m.Brand,
+		Color:                 m.Color,
+		Size:                  m.Size,
+		WeightGrams:           m.WeightGrams,
+		CategoryID:            m.CategoryID,
+		GoogleProductCategory: m.GoogleProductCategory,
+		ProductType:           m.ProductType,
+		UpdatedAt:             m.UpdatedAt,
+	}
+}
+
+func FeedItemModelFromDomain(i *domain.FeedItem) *FeedItemModel {
+	return &FeedItemModel{
+		ID:                    i.ID,
+		ItemGroupID:           i.ItemGroupID,
+		ProductID:             i.ProductID,
+		SellerID:              i.SellerID,
+		SKU:                   i.SKU,
+		Title:                 i.Title,
+		Description:           i.Description,
+		Link:                  i.Link,
+		ImageURL:              i.ImageURL,
+		AdditionalImageURLs:   i.AdditionalImageURLs,
+		PriceCents:            i.PriceCents,
+		SalePriceCents:        i.SalePriceCents,
+		Currency:              i.Currency,
+		Availability:          string(i.Availability),
+		Stock:                 i.Stock,
+		GTIN:                  i.GTIN,
+		Brand:                 i.Brand,
+		Color:                 i.Color,
+		Size:                  i.Size,
+		WeightGrams:           i.WeightGrams,
+		CategoryID:            i.CategoryID,
+		GoogleProductCategory: i.GoogleProductCategory,
+		ProductType:           i.ProductType,
+		UpdatedAt:             i.UpdatedAt,
+	}
+}
+
+// FeedCategoryMappingModel is the GORM model for the feed_category_mappings table.
+type FeedCategoryMappingModel struct {
+	CategoryID     string    `gorm:"type:uuid;primaryKey"`
+	GoogleCategory string    `gorm:"type:varchar(500);not null"`
+	UpdatedAt      time.Time `gorm:"not null"`
+}
+
+func (FeedCategoryMappingModel) TableName() string { return "feed_category_mappings" }
+
+func (m *FeedCategoryMappingModel) ToDomain() *domain.FeedCategoryMapping {
+	return &domain.FeedCategoryMapping{
+		CategoryID:     m.CategoryID,
+		GoogleCategory: m.GoogleCategory,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func FeedCategoryMappingModelFromDomain(fm *domain.FeedCategoryMapping) *FeedCategoryMappingModel {
+	return &FeedCategoryMappingModel{
+		CategoryID:     fm.CategoryID,
+		GoogleCategory: fm.GoogleCategory,
+		UpdatedAt:      fm.UpdatedAt,
+	}
+}
+
+// ModerationChecksJSON is a GORM-compatible JSONB type for moderation check results.
+type ModerationChecksJSON []domain.ModerationCheck
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (c ModerationChecksJSON) Value() (driver.Value, error) {
+	if c == nil {
+		return json.Marshal([]domain.ModerationCheck{})
+	}
+	return json.Marshal([]domain.ModerationCheck(c))
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval.
+func (c *ModerationChecksJSON) Scan(value interface{}) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan ModerationChecksJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, c)
+}
+
+// ModerationReviewModel is the GORM model for the product_moderation_reviews table.
+type ModerationReviewModel struct {
+	ID               string               `gorm:"type:uuid;primaryKey"`
+	ProductID        string               `gorm:"type:uuid;not null;index"`
+	SellerID         string               `gorm:"type:uuid;not null;index"`
+	Status           string               `gorm:"type:varchar(20);not null;default:'pending';index"`
+	Trigger          string               `gorm:"type:varchar(30);not null"`
+	ContentHash      string               `gorm:"type:varchar(64);not null"`
+	Checks           ModerationChecksJSON `gorm:"type:jsonb"`
+	Flagged          bool                 `gorm:"not null;default:false"`
+	ReviewerID       string               `gorm:"type:varchar(64);index"`
+	AssignedAt       *time.Time
+	RejectionReasons pq.StringArray `gorm:"type:text[]"`
+	Note             string         `gorm:"type:text"`
+	DecidedAt        *time.Time
+	CreatedAt        time.Time `gorm:"not null;index"`
+	UpdatedAt        time.Time `gorm:"not null"`
+}
+
+func (ModerationReviewModel) TableName() string { return "product_moderation_reviews" }
+
+func (m *ModerationReviewModel) ToDomain() *domain.ModerationReview {
+	reasons := make([]domain.RejectionReason, len(m.RejectionReasons))
+	for i, r := range m.RejectionReasons {
+		reasons[i] = domain.RejectionReason(r)
+	}
+	return &domain.ModerationReview{
+		ID:               m.ID,
+		ProductID:        m.ProductID,
+		SellerID:         m.SellerID,
+		Status:           domain.ModerationReviewStatus(m.Status),
+		Trigger:          domain.ModerationTrigger(m.Trigger),
+		ContentHash:      m.ContentHash,
+		Checks:           m.Checks,
+		Flagged:          m.Flagged,
+		ReviewerID:       m.ReviewerID,
+		AssignedAt:       m.AssignedAt,
+		RejectionReasons: reasons,
+		Note:             m.Note,
+		DecidedAt:        m.DecidedAt,
+		CreatedAt:        m.CreatedAt,
+		UpdatedAt:        m.UpdatedAt,
+	}
+}
+
+func ModerationReviewModelFromDomain(r *domain.ModerationReview) *ModerationReviewModel {
+	reasons := make(pq.StringArray, len(r.RejectionReasons))
+	for i, reason := range r.RejectionReasons {
+		reasons[i] = string(reason)
+	}
+	return &ModerationReviewModel{
+		ID:               r.ID,
+		ProductID:        r.ProductID,
+		SellerID:         r.SellerID,
+		Status:           string(r.Status),
+		Trigger:          string(r.Trigger),
+		ContentHash:      r.ContentHash,
+		Checks:           r.Checks,
+		Flagged:          r.Flagged,
+		ReviewerID:       r.ReviewerID,
+		AssignedAt:       r.AssignedAt,
+		RejectionReasons: reasons,
+		Note:             r.Note,
+		DecidedAt:        r.DecidedAt,
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+	}
+}
+
+// ProhibitedKeywordModel is the GORM model for the prohibited_keywords table.
+type ProhibitedKeywordModel struct {
+	ID        string    `gorm:"type:uuid;primaryKey"`
+	Keyword   string    `gorm:"type:varchar(200);uniqueIndex;not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (ProhibitedKeywordModel) TableName() string { return "prohibited_keywords" }
+
+func (m *ProhibitedKeywordModel) ToDomain() *domain.ProhibitedKeyword {
+	return &domain.ProhibitedKeyword{
+		ID:        m.ID,
+		Keyword:   m.Keyword,
+		CreatedAt: m.CreatedAt,
+	}
+}
+
+// PriceHistoryModel is the GORM model for the variant_price_history table.
+type PriceHistoryModel struct {
+	ID                     string    `gorm:"type:uuid;primaryKey"`
+	VariantID              string    `gorm:"type:uuid;not null;index:idx_price_history_variant_changed,priority:1"`
+	ProductID              string    `gorm:"type:uuid;not null;index"`
+	PriceCents             int64     `gorm:"not null"`
+	PreviousPriceCents     int64     `gorm:"not null"`
+	CompareAtCents         int64     `gorm:"not null;default:0"`
+	PreviousCompareAtCents int64     `gorm:"not null;default:0"`
+	Source                 string    `gorm:"type:varchar(20);not null"`
+	ScheduleID             *string   `gorm:"type:uuid"`
+	ChangedAt              time.Time `gorm:"not null;index:idx_price_history_variant_changed,priority:2"`
+}
+
+func (PriceHistoryModel) TableName() string { return "variant_price_history" }
+
+func (m *PriceHistoryModel) ToDomain() *domain.PriceHistoryEntry {
+	e := &domain.PriceHistoryEntry{
+		ID:                     m.ID,
+		VariantID:              m.VariantID,
+		ProductID:              m.ProductID,
+		PriceCents:             m.PriceCents,
+		PreviousPriceCents:     m.PreviousPriceCents,
+		CompareAtCents:         m.CompareAtCents,
+		PreviousCompareAtCents: m.PreviousCompareAtCents,
+		Source:                 domain.PriceChangeSource(m.Source),
+		ChangedAt:              m.ChangedAt,
+	}
+	if m.ScheduleID != nil {
+		e.ScheduleID = *m.ScheduleID
+	}
+	return e
+}
+
+func PriceHistoryModelFromDomain(e *domain.PriceHistoryEntry) *PriceHistoryModel {
+	m := &PriceHistoryModel{
+		ID:                     e.ID,
+		VariantID:              e.VariantID,
+		ProductID:              e.ProductID,
+		PriceCents:             e.PriceCents,
+		PreviousPriceCents:     e.PreviousPriceCents,
+		CompareAtCents:         e.CompareAtCents,
+		PreviousCompareAtCents: e.PreviousCompareAtCents,
+		Source:                 string(e.Source),
+		ChangedAt:              e.ChangedAt,
+	}
+	if e.ScheduleID != "" {
+		m.ScheduleID = &e.ScheduleID
+	}
+	return m
+}
+
+// PriceScheduleModel is the GORM model for the variant_price_schedules table.
+type PriceScheduleModel struct {
+	ID                   string `gorm:"type:uuid;primaryKey"`
+	ProductID            string `gorm:"type:uuid;not null;index"`
+	VariantID            string `gorm:"type:uuid;not null;index"`
+	SellerID             string `gorm:"type:uuid;not null"`
+	PriceCents           int64  `gorm:"not null"`
+	CompareAtCents       *int64
+	StartsAt             time.Time  `gorm:"not null;index"`
+	EndsAt               *time.Time `gorm:"index"`
+	Status               string     `gorm:"type:varchar(20);not null;default:'scheduled';index"`
+	RevertPriceCents     int64      `gorm:"not null;default:0"`
+	RevertCompareAtCents int64      `gorm:"not null;default:0"`
+	CreatedAt            time.Time  `gorm:"not null"`
+	UpdatedAt            time.Time  `gorm:"not null"`
+}
+
+func (PriceScheduleModel) TableName() string { return "variant_price_schedules" }
+
+func (m *PriceScheduleModel) ToDomain() *domain.PriceSchedule {
+	return &domain.PriceSchedule{
+		ID:                   m.ID,
+		ProductID:            m.ProductID,
+		VariantID:            m.VariantID,
+		SellerID:             m.SellerID,
+		PriceCents:           m.PriceCents,
+		CompareAtCents:       m.CompareAtCents,
+		StartsAt:             m.StartsAt,
+		EndsAt:               m.EndsAt,
+		Status:               domain.PriceScheduleStatus(m.Status),
+		RevertPriceCents:     m.RevertPriceCents,
+		RevertCompareAtCents: m.RevertCompareAtCents,
+		CreatedAt:            m.CreatedAt,
+		UpdatedAt:            m.UpdatedAt,
+	}
+}
+
+func PriceScheduleModelFromDomain(s *domain.PriceSchedule) *PriceScheduleModel {
+	return &PriceScheduleModel{
+		ID:                   s.ID,
+		ProductID:            s.ProductID,
+		VariantID:            s.VariantID,
+		SellerID:             s.SellerID,
+		PriceCents:           s.PriceCents,
+		CompareAtCents:       s.CompareAtCents,
+		StartsAt:             s.StartsAt,
+		EndsAt:               s.EndsAt,
+		Status:               string(s.Status),
+		RevertPriceCents:     s.RevertPriceCents,
+		RevertCompareAtCents: s.RevertCompareAtCents,
+		CreatedAt:            s.CreatedAt,
+		UpdatedAt:            s.UpdatedAt,
+	}
+}
+
+// VariantPriceModel is the GORM model for the product_variant_prices table.
+type VariantPriceModel struct {
+	VariantID      string    `gorm:"type:uuid;primaryKey"`
+	Currency       string    `gorm:"type:varchar(3);primaryKey"`
+	ProductID      string    `gorm:"type:uuid;not null;index"`
+	PriceCents     int64     `gorm:"not null"`
+	CompareAtCents int64     `gorm:"not null;default:0"`
+	UpdatedAt      time.Time `gorm:"not null"`
+}
+
+func (VariantPriceModel) TableName() string { return "product_variant_prices" }
+
+func (m *VariantPriceModel) ToDomain() *domain.VariantPrice {
+	return &domain.VariantPrice{
+		VariantID:      m.VariantID,
+		ProductID:      m.ProductID,
+		Currency:       m.Currency,
+		PriceCents:     m.PriceCents,
+		CompareAtCents: m.CompareAtCents,
+		UpdatedAt:      m.UpdatedAt,
+	}
+}
+
+func VariantPriceModelFromDomain(p *domain.VariantPrice) *VariantPriceModel {
+	return &VariantPriceModel{
+		VariantID:      p.VariantID,
+		ProductID:      p.ProductID,
+		Currency:       p.Currency,
+		PriceCents:     p.PriceCents,
+		CompareAtCents: p.CompareAtCents,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
+
+// SubscriptionPlanModel is the GORM model for the product_subscription_plans table.
+type SubscriptionPlanModel struct {
+	ID            string    `gorm:"type:uuid;primaryKey"`
+	ProductID     string    `gorm:"type:uuid;not null;index"`
+	VariantID     string    `gorm:"type:uuid;not null;index"`
+	IntervalUnit  string    `gorm:"type:varchar(10);not null"`
+	IntervalCount int       `gorm:"not null;default:1"`
+	PercentOff    int64     `gorm:"not null;default:0"`
+	IsActive      bool      `gorm:"not null;default:true"`
+	CreatedAt     time.Time `gorm:"not null"`
+	UpdatedAt     time.Time `gorm:"not null"`
+}
+
+func (SubscriptionPlanModel) TableName() string { return "product_subscription_plans" }
+
+func (m *SubscriptionPlanModel) ToDomain() *domain.SubscriptionPlan {
+	return &domain.SubscriptionPlan{
+		ID:            m.ID,
+		ProductID:     m.ProductID,
+		VariantID:     m.VariantID,
+		IntervalUnit:  domain.SubscriptionInterval(m.IntervalUnit),
+		IntervalCount: m.IntervalCount,
+		PercentOff:    m.PercentOff,
+		IsActive:      m.IsActive,
+		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+func SubscriptionPlanModelFromDomain(p *domain.SubscriptionPlan) *SubscriptionPlanModel {
+	return &SubscriptionPlanModel{
+		ID:            p.ID,
+		ProductID:     p.ProductID,
+		VariantID:     p.VariantID,
+		IntervalUnit:  string(p.IntervalUnit),
+		IntervalCount: p.IntervalCount,
+		PercentOff:    p.PercentOff,
+		IsActive:      p.IsActive,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+// DigitalFileModel is the GORM model for the product_digital_files table.
+type DigitalFileModel struct {
+	ID          string    `gorm:"type:uuid;primaryKey"`
+	ProductID   string    `gorm:"type:uuid;not null;index"`
+	VariantID   *string   `gorm:"type:uuid"`
+	MediaID     string    `gorm:"type:uuid;not null"`
+	FileName    string    `gorm:"type:varchar(500);not null"`
+	ContentType string    `gorm:"type:varchar(255)"`
+	SizeBytes   int64     `gorm:"not null;default:0"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func (DigitalFileModel) TableName() string { return "product_digital_files" }
+
+func (m *DigitalFileModel) ToDomain() *domain.DigitalFile {
+	f := &domain.DigitalFile{
+		ID:          m.ID,
+		ProductID:   m.ProductID,
+		MediaID:     m.MediaID,
+		FileName:    m.FileName,
+		ContentType: m.ContentType,
+		SizeBytes:   m.SizeBytes,
+		CreatedAt:   m.CreatedAt,
+	}
+	if m.VariantID != nil {
+		f.VariantID = *m.VariantID
+	}
+	return f
+}
+
+func DigitalFileModelFromDomain(f *domain.DigitalFile) *DigitalFileModel {
+	m := &DigitalFileModel{
+		ID:          f.ID,
+		ProductID:   f.ProductID,
+		MediaID:     f.MediaID,
+		FileName:    f.FileName,
+		ContentType: f.ContentType,
+		SizeBytes:   f.SizeBytes,
+		CreatedAt:   f.CreatedAt,
+	}
+	if f.VariantID != "" {
+		m.VariantID = &f.VariantID
+	}
+	return m
+}
+
+// LicenseKeyModel is the GORM model for the product_license_keys table.
+type LicenseKeyModel struct {
+	ID            string  `gorm:"type:uuid;primaryKey"`
+	ProductID     string  `gorm:"type:uuid;not null;uniqueIndex:idx_license_key_product_key"`
+	VariantID     *string `gorm:"type:uuid"`
+	Key           string  `gorm:"type:varchar(500);not null;uniqueIndex:idx_license_key_product_key"`
+	Status        string  `gorm:"type:varchar(20);not null;default:'available';index"`
+	EntitlementID *string `gorm:"type:uuid;index"`
+	AssignedAt    *time.Time
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+func (LicenseKeyModel) TableName() string { return "product_license_keys" }
+
+func (m *LicenseKeyModel) ToDomain() *domain.LicenseKey {
+	k := &domain.LicenseKey{
+		ID:         m.ID,
+		ProductID:  m.ProductID,
+		Key:        m.Key,
+		Status:     domain.LicenseKeyStatus(m.Status),
+		AssignedAt: m.AssignedAt,
+		CreatedAt:  m.CreatedAt,
+	}
+	if m.VariantID != nil {
+		k.VariantID = *m.VariantID
+	}
+	if m.EntitlementID != nil {
+		k.EntitlementID = *m.EntitlementID
+	}
+	return k
+}
+
+func LicenseKeyModelFromDomain(k *domain.LicenseKey) *LicenseKeyModel {
+	m := &LicenseKeyModel{
+		ID:         k.ID,
+		ProductID:  k.ProductID,
+		Key:        k.Key,
+		Status:     string(k.Status),
+		AssignedAt: k.AssignedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+	if k.VariantID != "" {
+		m.VariantID = &k.VariantID
+	}
+	if k.EntitlementID != "" {
+		m.EntitlementID = &k.EntitlementID
+	}
+	return m
+}
+
+// DigitalEntitlementModel is the GORM model for the digital_entitlements table.
+type DigitalEntitlementModel struct {
+	ID              string  `gorm:"type:uuid;primaryKey"`
+	OrderID         string  `gorm:"type:uuid;not null;uniqueIndex:idx_entitlement_order_line"`
+	BuyerID         string  `gorm:"type:uuid;not null;index"`
+	SellerID        string  `gorm:"type:uuid;not null"`
+	ProductID       string  `gorm:"type:uuid;not null;index;uniqueIndex:idx_entitlement_order_line"`
+	VariantID       *string `gorm:"type:uuid;uniqueIndex:idx_entitlement_order_line"`
+	Quantity        int     `gorm:"not null;default:1"`
+	AmountCents     int64   `gorm:"not null;default:0"`
+	OrderTotalCents int64   `gorm:"not null;default:0"`
+	Status          string  `gorm:"type:varchar(20);not null;default:'pending';index"`
+	DownloadLimit   int     `gorm:"not null;default:0"`
+	DownloadCount   int     `gorm:"not null;default:0"`
+	ExpiresAt       *time.Time
+	KeysPending     int `gorm:"not null;default:0"`
+	ActivatedAt     *time.Time
+	CreatedAt       time.Time `gorm:"not null"`
+	UpdatedAt       time.Time `gorm:"not null"`
+}
+
+func (DigitalEntitlementModel) TableName() string { return "digital_entitlements" }
+
+func (m *DigitalEntitlementModel) ToDomain() *domain.DigitalEntitlement {
+	e := &domain.DigitalEntitlement{
+		ID:              m.ID,
+		OrderID:         m.OrderID,
+		BuyerID:         m.BuyerID,
+		SellerID:        m.SellerID,
+		ProductID:       m.ProductID,
+		Quantity:        m.Quantity,
+		AmountCents:     m.AmountCents,
+		OrderTotalCents: m.OrderTotalCents,
+		Status:          domain.EntitlementStatus(m.Status),
+		DownloadLimit:   m.DownloadLimit,
+		DownloadCount:   m.DownloadCount,
+		ExpiresAt:       m.ExpiresAt,
+		KeysPending:     m.KeysPending,
+		ActivatedAt:     m.ActivatedAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+	if m.VariantID != nil {
+		e.VariantID = *m.VariantID
+	}
+	return e
+}
+
+func DigitalEntitlementModelFromDomain(e *domain.DigitalEntitlement) *DigitalEntitlementModel {
+	m := &DigitalEntitlementModel{
+		ID:              e.ID,
+		OrderID:         e.OrderID,
+		BuyerID:         e.BuyerID,
+		SellerID:        e.SellerID,
+		ProductID:       e.ProductID,
+		Quantity:        e.Quantity,
+		AmountCents:     e.AmountCents,
+		OrderTotalCents: e.OrderTotalCents,
+		Status:          string(e.Status),
+		DownloadLimit:   e.DownloadLimit,
+		DownloadCount:   e.DownloadCount,
+		ExpiresAt:       e.ExpiresAt,
+		KeysPending:     e.KeysPending,
+		ActivatedAt:     e.ActivatedAt,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}
+	if e.VariantID != "" {
+		m.VariantID = &e.VariantID
+	}
+	return m
+}
+
+// ProductTranslationModel is the GORM model for the product_translations table.
+type ProductTranslationModel struct {
+	ProductID   string    `gorm:"type:uuid;primaryKey"`
+	Locale      string    `gorm:"type:varchar(10);primaryKey;uniqueIndex:idx_product_translation_slug"`
+	Name        string    `gorm:"type:varchar(500)"`
+	Slug        string    `gorm:"type:varchar(600);not null;uniqueIndex:idx_product_translation_slug"`
+	Description string    `gorm:"type:text"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+func (ProductTranslationModel) TableName() string { return "product_translations" }
+
+func (m *ProductTranslationModel) ToDomain() *domain.ProductTranslation {
+	return &domain.ProductTranslation{
+		ProductID:   m.ProductID,
+		Locale:      m.Locale,
+		Name:        m.Name,
+		Slug:        m.Slug,
+		Description: m.Description,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+func ProductTranslationModelFromDomain(t *domain.ProductTranslation) *ProductTranslationModel {
+	return &ProductTranslationModel{
+		ProductID:   t.ProductID,
+		Locale:      t.Locale,
+		Name:        t.Name,
+		Slug:        t.Slug,
+		Description: t.Description,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+// CategoryTranslationModel is the GORM model for the category_translations table.
+type CategoryTranslationModel struct {
+	CategoryID string    `gorm:"type:uuid;primaryKey"`
+	Locale     string    `gorm:"type:varchar(10);primaryKey;uniqueIndex:idx_category_translation_slug"`
+	Name       string    `gorm:"type:varchar(255);not null"`
+	Slug       string    `gorm:"type:varchar(300);not null;uniqueIndex:idx_category_translation_slug"`
+	UpdatedAt  time.Time `gorm:"not null"`
+}
+
+func (CategoryTranslationModel) TableName() string { return "category_translations" }
+
+func (m *CategoryTranslationModel) ToDomain() *domain.CategoryTranslation {
+	return &domain.CategoryTranslation{
+		CategoryID: m.CategoryID,
+		Locale:     m.Locale,
+		Name:       m.Name,
+		Slug:       m.Slug,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func CategoryTranslationModelFromDomain(t *domain.CategoryTranslation) *CategoryTranslationModel {
+	return &CategoryTranslationModel{
+		CategoryID: t.CategoryID,
+		Locale:     t.Locale,
+		Name:       t.Name,
+		Slug:       t.Slug,
+		UpdatedAt:  t.UpdatedAt,
+	}
+}
+
+// OptionValueTranslationModel is the GORM model for the product_option_value_translations table.
+type OptionValueTranslationModel struct {
+	OptionValueID string    `gorm:"type:uuid;primaryKey"`
+	Locale        string    `gorm:"type:varchar(10);primaryKey"`
+	Value         string    `gorm:"type:varchar(255);not null"`
+	UpdatedAt     time.Time `gorm:"not null"`
+}
+
+func (OptionValueTranslationModel) TableName() string { return "product_option_value_translations" }
+
+func (m *OptionValueTranslationModel) ToDomain() *domain.OptionValueTranslation {
+	return &domain.OptionValueTranslation{
+		OptionValueID: m.OptionValueID,
+		Locale:        m.Locale,
+		Value:         m.Value,
+		UpdatedAt:     m.UpdatedAt,
+	}
+}
+
+func OptionValueTranslationModelFromDomain(t *domain.OptionValueTranslation) *OptionValueTranslationModel {
+	return &OptionValueTranslationModel{
+		OptionValueID: t.OptionValueID,
+		Locale:        t.Locale,
+		Value:         t.Value,
+		UpdatedAt:     t.UpdatedAt,
+	}
+}
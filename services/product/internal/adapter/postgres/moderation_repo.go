@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// ModerationReviewRepo implements domain.ModerationReviewRepository using GORM.
+type ModerationReviewRepo struct {
+	db *gorm.DB
+}
+
+// NewModerationReviewRepo creates a new ModerationReviewRepo.
+func NewModerationReviewRepo(db *gorm.DB) *ModerationReviewRepo {
+	return &ModerationReviewRepo{db: db}
+}
+
+func (r *ModerationReviewRepo) Create(ctx context.Context, review *domain.ModerationReview) error {
+	model := ModerationReviewModelFromDomain(review)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+func (r *ModerationReviewRepo) GetByID(ctx context.Context, id string) (*domain.ModerationReview, error) {
+	var model ModerationReviewModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, fmt.Errorf("moderation review not found: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *ModerationReviewRepo) Update(ctx context.Context, review *domain.ModerationReview) error {
+	model := ModerationReviewModelFromDomain(review)
+	return r.db.WithContext(ctx).Save(model).Error
+}
+
+func (r *ModerationReviewRepo) GetOpenByProduct(ctx context.Context, productID string) (*domain.ModerationReview, error) {
+	return r.latest(ctx, productID, domain.ModerationReviewStatusPending)
+}
+
+func (r *ModerationReviewRepo) GetLatestApproved(ctx context.Context, productID string) (*domain.ModerationReview, error) {
+	return r.latest(ctx, productID, domain.ModerationReviewStatusApproved)
+}
+
+func (r *ModerationReviewRepo) latest(ctx context.Context, productID string, status domain.ModerationReviewStatus) (*domain.ModerationReview, error) {
+	var model ModerationReviewModel
+	err := r.db.WithContext(ctx).
+		Where("product_id = ? AND status = ?", productID, status).
+		Order("updated_at DESC").
+		First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *ModerationReviewRepo) ListByProduct(ctx context.Context, productID string) ([]*domain.ModerationReview, error) {
+	var models []ModerationReviewModel
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	reviews := make([]*domain.ModerationReview, len(models))
+	for i := range models {
+		reviews[i] = models[i].ToDomain()
+	}
+	return reviews, nil
+}
+
+func (r *ModerationReviewRepo) List(ctx context.Context, filter domain.ModerationReviewFilter) ([]*domain.ModerationReview, int64, error) {
+	query := r.db.WithContext(ctx).Model(&ModerationReviewModel{})
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ReviewerID != "" {
+		query = query.Where("reviewer_id = ?", filter.ReviewerID)
+	}
+	if filter.SellerID != "" {
+		query = query.Where("seller_id = ?", filter.SellerID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Flagged reviews first, then oldest first so the queue is worked in order.
+	var models []ModerationReviewModel
+	offset := (filter.Page - 1) * filter.PageSize
+	if err := query.Order("flagged DESC, created_at ASC").Offset(offset).Limit(filter.PageSize).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	reviews := make([]*domain.ModerationReview, len(models))
+	for i := range models {
+		reviews[i] = models[i].ToDomain()
+	}
+	return reviews, total, nil
+}
+
+// ProhibitedKeywordRepo implements domain.ProhibitedKeywordRepository using GORM.
+type ProhibitedKeywordRepo struct {
+	db *gorm.DB
+}
+
+// NewProhibitedKeywordRepo creates a new ProhibitedKeywordRepo.
+func NewProhibitedKeywordRepo(db *gorm.DB) *ProhibitedKeywordRepo {
+	return &ProhibitedKeywordRepo{db: db}
+}
+
+func (r *ProhibitedKeywordRepo) Create(ctx context.Context, k *domain.ProhibitedKeyword) error {
+	model := &ProhibitedKeywordModel{ID: k.ID, Keyword: k.Keyword, CreatedAt: k.CreatedAt}
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+func (r *ProhibitedKeywordRepo) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&ProhibitedKeywordModel{}, "id = ?", id).Error
+}
+
+func (r *ProhibitedKeywordRepo) List(ctx context.Context) ([]*domain.ProhibitedKeyword, error) {
+	var models []ProhibitedKeywordModel
+	if err := r.db.WithContext(ctx).Order("keyword ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	keywords := make([]*domain.ProhibitedKeyword, len(models))
+	for i := range models {
+		keywords[i] = models[i].ToDomain()
+	}
+	return keywords, nil
+}
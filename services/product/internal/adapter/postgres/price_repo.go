@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// PriceHistoryRepo implements domain.PriceHistoryRepository using GORM.
+type PriceHistoryRepo struct {
+	db *gorm.DB
+}
+
+// NewPriceHistoryRepo creates a new PriceHistoryRepo.
+func NewPriceHistoryRepo(db *gorm.DB) *PriceHistoryRepo {
+	return &PriceHistoryRepo{db: db}
+}
+
+func (r *PriceHistoryRepo) Create(ctx context.Context, e *domain.PriceHistoryEntry) error {
+	model := PriceHistoryModelFromDomain(e)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+func (r *PriceHistoryRepo) ListByVariant(ctx context.Context, variantID string, since time.Time) ([]*domain.PriceHistoryEntry, error) {
+	var models []PriceHistoryModel
+	if err := r.db.WithContext(ctx).
+		Where("variant_id = ? AND changed_at > ?", variantID, since).
+		Order("changed_at ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]*domain.PriceHistoryEntry, len(models))
+	for i := range models {
+		entries[i] = models[i].ToDomain()
+	}
+	return entries, nil
+}
+
+func (r *PriceHistoryRepo) GetLatestAt(ctx context.Context, variantID string, at time.Time) (*domain.PriceHistoryEntry, error) {
+	var model PriceHistoryModel
+	err := r.db.WithContext(ctx).
+		Where("variant_id = ? AND changed_at <= ?", variantID, at).
+		Order("changed_at DESC").
+		First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// PriceScheduleRepo implements domain.PriceScheduleRepository using GORM.
+type PriceScheduleRepo struct {
+	db *gorm.DB
+}
+
+// NewPriceScheduleRepo creates a new PriceScheduleRepo.
+func NewPriceScheduleRepo(db *gorm.DB) *PriceScheduleRepo {
+	return &PriceScheduleRepo{db: db}
+}
+
+func (r *PriceScheduleRepo) Create(ctx context.Context, s *domain.PriceSchedule) error {
+	model := PriceScheduleModelFromDomain(s)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+func (r *PriceScheduleRepo) GetByID(ctx context.Context, id string) (*domain.PriceSchedule, error) {
+	var model PriceScheduleModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, fmt.Errorf("price schedule not found: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *PriceScheduleRepo) ListByVariant(ctx context.Context, variantID string) ([]*domain.PriceSchedule, error) {
+	var models []PriceScheduleModel
+	if err := r.db.WithContext(ctx).Where("variant_id = ?", variantID).Order("starts_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return priceSchedulesToDomain(models), nil
+}
+
+func (r *PriceScheduleRepo) UpdateIfStatus(ctx context.Context, s *domain.PriceSchedule, from domain.PriceScheduleStatus) (bool, error) {
+	model := PriceScheduleModelFromDomain(s)
+	result := r.db.WithContext(ctx).Model(&PriceScheduleModel{}).
+		Where("id = ? AND status = ?", s.ID, string(from)).
+		Select("*").
+		Updates(model)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *PriceScheduleRepo) ListDueToStart(ctx context.Context, now time.Time, limit int) ([]*domain.PriceSchedule, error) {
+	var models []PriceScheduleModel
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND starts_at <= ?", string(domain.PriceScheduleStatusScheduled), now).
+		Order("starts_at ASC").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return priceSchedulesToDomain(models), nil
+}
+
+func (r *PriceScheduleRepo) ListDueToEnd(ctx context.Context, now time.Time, limit int) ([]*domain.PriceSchedule, error) {
+	var models []PriceScheduleModel
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND ends_at <= ?", string(domain.PriceScheduleStatusActive), now).
+		Order("ends_at ASC").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return priceSchedulesToDomain(models), nil
+}
+
+func priceSchedulesToDomain(models []PriceScheduleModel) []*domain.PriceSchedule {
+	schedules := make([]*domain.PriceSchedule, len(models))
+	for i := range models {
+		schedules[i] = models[i].ToDomain()
+	}
+	return schedules
+}
+
+// VariantPriceRepo implements domain.VariantPriceRepository using GORM.
+type VariantPriceRepo struct {
+	db *gorm.DB
+}
+
+// NewVariantPriceRepo creates a new VariantPriceRepo.
+func NewVariantPriceRepo(db *gorm.DB) *VariantPriceRepo {
+	return &VariantPriceRepo{db: db}
+}
+
+func (r *VariantPriceRepo) Upsert(ctx context.Context, p *domain.VariantPrice) error {
+	model := VariantPriceModelFromDomain(p)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "variant_id"}, {Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"price_cents", "compare_at_cents", "updated_at"}),
+	}).Create(model).Error
+}
+
+func (r *VariantPriceRepo) Get(ctx context.Context, variantID, currency string) (*domain.VariantPrice, error) {
+	var model VariantPriceModel
+	err := r.db.WithContext(ctx).Where("variant_id = ? AND currency = ?", variantID, currency).First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *VariantPriceRepo) ListByVariant(ctx context.Context, variantID string) ([]*domain.VariantPrice, error) {
+	var models []VariantPriceModel
+	if err := r.db.WithContext(ctx).Where("variant_id = ?", variantID).Order("currency ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	prices := make([]*domain.VariantPrice, len(models))
+	for i := range models {
+		prices[i] = models[i].ToDomain()
+	}
+	return prices, nil
+}
+
+func (r *VariantPriceRepo) Delete(ctx context.Context, variantID, currency string) error {
+	return r.db.WithContext(ctx).Delete(&VariantPriceModel{}, "variant_id = ? AND currency = ?", variantID, currency).Error
+}
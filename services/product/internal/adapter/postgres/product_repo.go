@@ -46,7 +46,9 @@ func (r *ProductRepo) List(ctx context.Context, filter domain.ProductFilter) ([]
 	if filter.SellerID != "" {
 		query = query.Where("seller_id = ?", filter.SellerID)
 	}
-	if filter.CategoryID != "" {
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	} else if filter.CategoryID != "" {
 		query = query.Where("category_id = ?", filter.CategoryID)
 	}
 	if filter.Status != "" {
@@ -102,9 +104,69 @@ func (r *ProductRepo) List(ctx context.Context, filter domain.ProductFilter) ([]
 
 func (r *ProductRepo) Update(ctx context.Context, p *domain.Product) error {
 	model := ProductModelFromDomain(p)
-	return r.db.WithContext(ctx).Save(model).Error
+	// Ratings are only written by AdjustRating and SetRating, so a product
+	// update never overwrites a concurrent rating change.
+	return r.db.WithContext(ctx).Omit("rating_avg", "rating_count").Save(model).Error
 }
 
 func (r *ProductRepo) Delete(ctx context.Context, id string) error {
 	return r.db.WithContext(ctx).Delete(&ProductModel{}, "id = ?", id).Error
 }
+
+func (r *ProductRepo) CountByCategory(ctx context.Context, status string) (map[string]int64, error) {
+	query := r.db.WithContext(ctx).Model(&ProductModel{}).Where("category_id IS NOT NULL")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var rows []struct {
+		CategoryID string
+		Count      int64
+	}
+	if err := query.Select("category_id, COUNT(*) AS count").Group("category_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *ProductRepo) AdjustRating(ctx context.Context, productID string, countDelta int, ratingDelta int) error {
+	// Every SET expression reads the row as it was before the update.
+	return r.db.WithContext(ctx).Model(&ProductModel{}).
+		Where("id = ?", productID).
+		Updates(map[string]interface{}{
+			"rating_avg": gorm.Expr(
+				"CASE WHEN rating_count + ? > 0 THEN (rating_avg * rating_count + ?) / (rating_count + ?) ELSE 0 END",
+				countDelta, ratingDelta, countDelta),
+			"rating_count": gorm.Expr("GREATEST(rating_count + ?, 0)", countDelta),
+		}).Error
+}
+
+func (r *ProductRepo) SetRating(ctx context.Context, productID string, avg float64, count int) error {
+	return r.db.WithContext(ctx).Model(&ProductModel{}).
+		Where("id = ?", productID).
+		Updates(map[string]interface{}{"rating_avg": avg, "rating_count": count}).Error
+}
+
+func (r *ProductRepo) GetSellerRating(ctx context.Context, sellerID string) (*domain.SellerRating, error) {
+	var result struct {
+		RatingAvg   float64
+		RatingCount int
+	}
+	if err := r.db.WithContext(ctx).Model(&ProductModel{}).
+		Where("seller_id = ?", sellerID).
+		Select("COALESCE(SUM(rating_avg * rating_count) / NULLIF(SUM(rating_count), 0), 0) AS rating_avg, " +
+			"COALESCE(SUM(rating_count), 0) AS rating_count").
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+	return &domain.SellerRating{
+		SellerID:    sellerID,
+		RatingAvg:   result.RatingAvg,
+		RatingCount: result.RatingCount,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// SubscriptionPlanRepo implements domain.SubscriptionPlanRepository using GORM.
+type SubscriptionPlanRepo struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionPlanRepo creates a new SubscriptionPlanRepo.
+func NewSubscriptionPlanRepo(db *gorm.DB) *SubscriptionPlanRepo {
+	return &SubscriptionPlanRepo{db: db}
+}
+
+func (r *SubscriptionPlanRepo) Create(ctx context.Context, p *domain.SubscriptionPlan) error {
+	model := SubscriptionPlanModelFromDomain(p)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+func (r *SubscriptionPlanRepo) GetByID(ctx context.Context, id string) (*domain.SubscriptionPlan, error) {
+	var model SubscriptionPlanModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, fmt.Errorf("subscription plan not found: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *SubscriptionPlanRepo) ListByProduct(ctx context.Context, productID string, activeOnly bool) ([]*domain.SubscriptionPlan, error) {
+	query := r.db.WithContext(ctx).Where("product_id = ?", productID)
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	var models []SubscriptionPlanModel
+	if err := query.Order("created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	plans := make([]*domain.SubscriptionPlan, len(models))
+	for i := range models {
+		plans[i] = models[i].ToDomain()
+	}
+	return plans, nil
+}
+
+func (r *SubscriptionPlanRepo) Update(ctx context.Context, p *domain.SubscriptionPlan) error {
+	model := SubscriptionPlanModelFromDomain(p)
+	return r.db.WithContext(ctx).Save(model).Error
+}
+
+func (r *SubscriptionPlanRepo) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&SubscriptionPlanModel{}, "id = ?", id).Error
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// TranslationRepo implements domain.TranslationRepository using GORM.
+type TranslationRepo struct {
+	db *gorm.DB
+}
+
+// NewTranslationRepo creates a new TranslationRepo.
+func NewTranslationRepo(db *gorm.DB) *TranslationRepo {
+	return &TranslationRepo{db: db}
+}
+
+func (r *TranslationRepo) UpsertProductTranslation(ctx context.Context, t *domain.ProductTranslation) error {
+	model := ProductTranslationModelFromDomain(t)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "slug", "description", "updated_at"}),
+	}).Create(model).Error
+}
+
+func (r *TranslationRepo) ListProductTranslations(ctx context.Context, productID string) ([]*domain.ProductTranslation, error) {
+	var models []ProductTranslationModel
+	if err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("locale ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return productTranslationsToDomain(models), nil
+}
+
+func (r *TranslationRepo) ListProductTranslationsIn(ctx context.Context, productIDs []string, locales []string) ([]*domain.ProductTranslation, error) {
+	if len(productIDs) == 0 || len(locales) == 0 {
+		return nil, nil
+	}
+	var models []ProductTranslationModel
+	if err := r.db.WithContext(ctx).
+		Where("product_id IN ? AND locale IN ?", productIDs, locales).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return productTranslationsToDomain(models), nil
+}
+
+func (r *TranslationRepo) GetProductTranslationBySlug(ctx context.Context, locale, slug string) (*domain.ProductTranslation, error) {
+	query := r.db.WithContext(ctx).Where("slug = ?", slug)
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+	var model ProductTranslationModel
+	if err := query.First(&model).Error; err != nil {
+		return nil, fmt.Errorf("product translation not found: %w", err)
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *TranslationRepo) DeleteProductTranslation(ctx context.Context, productID, locale string) error {
+	return r.db.WithContext(ctx).Delete(&ProductTranslationModel{}, "product_id = ? AND locale = ?", productID, locale).Error
+}
+
+func (r *TranslationRepo) UpsertCategoryTranslation(ctx context.Context, t *domain.CategoryTranslation) error {
+	model := CategoryTranslationModelFromDomain(t)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "slug", "updated_at"}),
+	}).Create(model).Error
+}
+
+func (r *TranslationRepo) ListCategoryTranslations(ctx context.Context, locales []string) ([]*domain.CategoryTranslation, error) {
+	var models []CategoryTranslationModel
+	if err := r.db.WithContext(ctx).Where("locale IN ?", locales).Find(&models).Error; err != nil {
+		return nil, err
+	}
+	translations := make([]*domain.CategoryTranslation, len(models))
+	for i := range models {
+		translations[i] = models[i].ToDomain()
+	}
+	return translations, nil
+}
+
+func (r *TranslationRepo) DeleteCategoryTranslation(ctx context.Context, categoryID, locale string) error {
+	return r.db.WithContext(ctx).Delete(&CategoryTranslationModel{}, "category_id = ? AND locale = ?", categoryID, locale).Error
+}
+
+func (r *TranslationRepo) UpsertOptionValueTranslation(ctx context.Context, t *domain.OptionValueTranslation) error {
+	model := OptionValueTranslationModelFromDomain(t)
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "option_value_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(model).Error
+}
+
+func (r *TranslationRepo) ListOptionValueTranslations(ctx context.Context, optionValueIDs []string, locales []string) ([]*domain.OptionValueTranslation, error) {
+	if len(optionValueIDs) == 0 || len(locales) == 0 {
+		return nil, nil
+	}
+	var models []OptionValueTranslationModel
+	if err := r.db.WithContext(ctx).
+		Where("option_value_id IN ? AND locale IN ?", optionValueIDs, locales).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+	translations := make([]*domain.OptionValueTranslation, len(models))
+	for i := range models {
+		translations[i] = models[i].ToDomain()
+	}
+	return translations, nil
+}
+
+func (r *TranslationRepo) DeleteOptionValueTranslation(ctx context.Context, optionValueID, locale string) error {
+	return r.db.WithContext(ctx).Delete(&OptionValueTranslationModel{}, "option_value_id = ? AND locale = ?", optionValueID, locale).Error
+}
+
+func productTranslationsToDomain(models []ProductTranslationModel) []*domain.ProductTranslation {
+	translations := make([]*domain.ProductTranslation, len(models))
+	for i := range models {
+		translations[i] = models[i].ToDomain()
+	}
+	return translations
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"gorm.io/gorm"
@@ -54,11 +55,15 @@ func (r *VariantRepo) GetByID(ctx context.Context, id string) (*domain.Variant,
 
 func (r *VariantRepo) GetBySKU(ctx context.Context, sku string) (*domain.Variant, error) {
 	var model VariantModel
-	if err := r.db.WithContext(ctx).
+	err := r.db.WithContext(ctx).
 		Preload("OptionValues").
 		Where("sku = ?", sku).
-		First(&model).Error; err != nil {
-		return nil, fmt.Errorf("variant not found: %w", err)
+		First(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 	return model.ToDomain(), nil
 }
@@ -6,10 +6,12 @@ import "time"
 type ProductStatus string
 
 const (
-	ProductStatusDraft    ProductStatus = "draft"
-	ProductStatusActive   ProductStatus = "active"
-	ProductStatusInactive ProductStatus = "inactive"
-	ProductStatusArchived ProductStatus = "archived"
+	ProductStatusDraft         ProductStatus = "draft"
+	ProductStatusPendingReview ProductStatus = "pending_review"
+	ProductStatusActive        ProductStatus = "active"
+	ProductStatusRejected      ProductStatus = "rejected"
+	ProductStatusInactive      ProductStatus = "inactive"
+	ProductStatusArchived      ProductStatus = "archived"
 )
 
 // Product represents a product in the catalog.
@@ -23,13 +25,38 @@ type Product struct {
 	BasePriceCents int64         `json:"base_price_cents"`
 	Currency       string        `json:"currency"`
 	Status         ProductStatus `json:"status"`
+	Type           ProductType   `json:"type"`
 	HasVariants    bool          `json:"has_variants"`
 	Tags           []string      `json:"tags"`
 	ImageURLs      []string      `json:"image_urls"`
 	RatingAvg      float64       `json:"rating_avg"`
 	RatingCount    int           `json:"rating_count"`
-	CreatedAt      time.Time     `json:"created_at"`
-	UpdatedAt      time.Time     `json:"updated_at"`
+	// Base price in the buyer's currency, set when products are presented
+	// in a currency other than their own.
+	PresentmentCurrency   string `json:"presentment_currency,omitempty"`
+	PresentmentPriceCents int64  `json:"presentment_price_cents,omitempty"`
+	// Digital products only: downloads allowed per purchase (0 is
+	// unlimited), days downloads stay available after payment (0 never
+	// expires) and whether each unit sold is given a license key.
+	DownloadLimit      int       `json:"download_limit,omitempty"`
+	DownloadExpiryDays int       `json:"download_expiry_days,omitempty"`
+	RequiresLicenseKey bool      `json:"requires_license_key,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// ProductType distinguishes physical goods from digital downloads.
+type ProductType string
+
+const (
+	ProductTypePhysical ProductType = "physical"
+	ProductTypeDigital  ProductType = "digital"
+)
+
+// IsDigital reports whether the product is delivered as a download rather
+// than shipped.
+func (p *Product) IsDigital() bool {
+	return p.Type == ProductTypeDigital
 }
 
 // Category represents a product category with optional parent for hierarchy.
@@ -44,6 +71,16 @@ type Category struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// CategoryNode is a category with its subcategories. ProductCount counts the
+// products filed directly under the category, SubtreeProductCount those under
+// it or any of its descendants.
+type CategoryNode struct {
+	*Category
+	ProductCount        int64           `json:"product_count"`
+	SubtreeProductCount int64           `json:"subtree_product_count"`
+	Children            []*CategoryNode `json:"children"`
+}
+
 // AttributeType defines the type of an attribute definition.
 type AttributeType string
 
@@ -56,7 +93,8 @@ const (
 	AttributeTypeBool        AttributeType = "bool"
 )
 
-// AttributeDefinition defines a product attribute schema.
+// AttributeDefinition defines a product attribute schema. MinValue and
+// MaxValue bound number attributes, expressed in Unit.
 type AttributeDefinition struct {
 	ID         string        `json:"id"`
 	Name       string        `json:"name"`
@@ -66,6 +104,8 @@ type AttributeDefinition struct {
 	Filterable bool          `json:"filterable"`
 	Options    []string      `json:"options,omitempty"`
 	Unit       string        `json:"unit,omitempty"`
+	MinValue   *float64      `json:"min_value,omitempty"`
+	MaxValue   *float64      `json:"max_value,omitempty"`
 	SortOrder  int           `json:"sort_order"`
 	CreatedAt  time.Time     `json:"created_at"`
 }
@@ -127,3 +167,434 @@ type VariantOptionValue struct {
 	OptionName    string `json:"option_name"`
 	Value         string `json:"value"`
 }
+
+// ImportFormat is the file format of a catalog import or export.
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "csv"
+	ImportFormatJSON ImportFormat = "json"
+)
+
+// ImportJobStatus represents the lifecycle status of a catalog import job.
+type ImportJobStatus string
+
+const (
+	ImportJobStatusPending   ImportJobStatus = "pending"
+	ImportJobStatusRunning   ImportJobStatus = "running"
+	ImportJobStatusCompleted ImportJobStatus = "completed"
+	ImportJobStatusFailed    ImportJobStatus = "failed"
+)
+
+// ImportRowError describes why a row of an import file was rejected.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportJob is an asynchronous bulk import of a seller's catalog file. A dry
+// run validates the file and reports what would change without writing.
+type ImportJob struct {
+	ID              string           `json:"id"`
+	SellerID        string           `json:"seller_id"`
+	Format          ImportFormat     `json:"format"`
+	DryRun          bool             `json:"dry_run"`
+	Status          ImportJobStatus  `json:"status"`
+	Payload         []byte           `json:"-"`
+	TotalRows       int              `json:"total_rows"`
+	ProductsCreated int              `json:"products_created"`
+	ProductsUpdated int              `json:"products_updated"`
+	VariantsCreated int              `json:"variants_created"`
+	VariantsUpdated int              `json:"variants_updated"`
+	FailedRows      int              `json:"failed_rows"`
+	RowErrors       []ImportRowError `json:"row_errors"`
+	Error           string           `json:"error,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	StartedAt       *time.Time       `json:"started_at,omitempty"`
+	FinishedAt      *time.Time       `json:"finished_at,omitempty"`
+}
+
+// FeedFormat is the output format of a product feed.
+type FeedFormat string
+
+const (
+	FeedFormatGoogleXML FeedFormat = "google_xml"
+	FeedFormatGoogleTSV FeedFormat = "google_tsv"
+	FeedFormatJSON      FeedFormat = "json"
+)
+
+// FeedAvailability is the stock status published to shopping channels.
+type FeedAvailability string
+
+const (
+	FeedAvailabilityInStock    FeedAvailability = "in_stock"
+	FeedAvailabilityOutOfStock FeedAvailability = "out_of_stock"
+)
+
+// FeedItem is one purchasable variant of an active product as published to
+// shopping channels. Items are precomputed whenever the product changes so
+// that serving a feed never has to join the catalog tables.
+type FeedItem struct {
+	ID                    string           `json:"id"`
+	ItemGroupID           string           `json:"item_group_id,omitempty"`
+	ProductID             string           `json:"product_id"`
+	SellerID              string           `json:"seller_id"`
+	SKU                   string           `json:"sku"`
+	Title                 string           `json:"title"`
+	Description           string           `json:"description"`
+	Link                  string           `json:"link"`
+	ImageURL              string           `json:"image_link,omitempty"`
+	AdditionalImageURLs   []string         `json:"additional_image_links,omitempty"`
+	PriceCents            int64            `json:"price_cents"`
+	SalePriceCents        int64            `json:"sale_price_cents,omitempty"`
+	Currency              string           `json:"currency"`
+	Availability          FeedAvailability `json:"availability"`
+	Stock                 int              `json:"stock"`
+	GTIN                  string           `json:"gtin,omitempty"`
+	Brand                 string           `json:"brand,omitempty"`
+	Color                 string           `json:"color,omitempty"`
+	Size                  string           `json:"size,omitempty"`
+	WeightGrams           int              `json:"shipping_weight_grams,omitempty"`
+	CategoryID            string           `json:"category_id,omitempty"`
+	GoogleProductCategory string           `json:"google_product_category,omitempty"`
+	ProductType           string           `json:"product_type,omitempty"`
+	UpdatedAt             time.Time        `json:"updated_at"`
+}
+
+// FeedCategoryMapping maps a marketplace category to an entry of the Google
+// product taxonomy, either its numeric ID or its full path.
+type FeedCategoryMapping struct {
+	CategoryID     string    `json:"category_id"`
+	GoogleCategory string    `json:"google_category"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ModerationReviewStatus represents the lifecycle status of a moderation review.
+type ModerationReviewStatus string
+
+const (
+	ModerationReviewStatusPending   ModerationReviewStatus = "pending"
+	ModerationReviewStatusApproved  ModerationReviewStatus = "approved"
+	ModerationReviewStatusRejected  ModerationReviewStatus = "rejected"
+	ModerationReviewStatusWithdrawn ModerationReviewStatus = "withdrawn"
+)
+
+// ModerationTrigger is what caused a product to be reviewed.
+type ModerationTrigger string
+
+const (
+	ModerationTriggerSubmission      ModerationTrigger = "submission"
+	ModerationTriggerSignificantEdit ModerationTrigger = "significant_edit"
+)
+
+// RejectionReason is a reason code given when a product is rejected.
+type RejectionReason string
+
+const (
+	RejectionReasonProhibitedContent  RejectionReason = "prohibited_content"
+	RejectionReasonMissingCategory    RejectionReason = "missing_category"
+	RejectionReasonMissingAttributes  RejectionReason = "missing_attributes"
+	RejectionReasonInsufficientImages RejectionReason = "insufficient_images"
+	RejectionReasonMisleadingContent  RejectionReason = "misleading_content"
+	RejectionReasonCounterfeit        RejectionReason = "counterfeit"
+	RejectionReasonOther              RejectionReason = "other"
+)
+
+// ModerationCheck is the result of an automated check run on a product when
+// it is submitted for review. A failed blocking check rejects the product
+// outright; other failed checks flag the review for the reviewer.
+type ModerationCheck struct {
+	Code     string          `json:"code"`
+	Passed   bool            `json:"passed"`
+	Blocking bool            `json:"blocking"`
+	Reason   RejectionReason `json:"reason,omitempty"`
+	Message  string          `json:"message,omitempty"`
+}
+
+// ModerationReview is one pass of a product through the moderation queue.
+// ContentHash fingerprints the reviewed content so that edits which do not
+// change it never send an approved product back to the queue.
+type ModerationReview struct {
+	ID               string                 `json:"id"`
+	ProductID        string                 `json:"product_id"`
+	SellerID         string                 `json:"seller_id"`
+	Status           ModerationReviewStatus `json:"status"`
+	Trigger          ModerationTrigger      `json:"trigger"`
+	ContentHash      string                 `json:"-"`
+	Checks           []ModerationCheck      `json:"checks"`
+	Flagged          bool                   `json:"flagged"`
+	ReviewerID       string                 `json:"reviewer_id,omitempty"`
+	AssignedAt       *time.Time             `json:"assigned_at,omitempty"`
+	RejectionReasons []RejectionReason      `json:"rejection_reasons,omitempty"`
+	Note             string                 `json:"note,omitempty"`
+	DecidedAt        *time.Time             `json:"decided_at,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+// ProhibitedKeyword is a term that flags products mentioning it for review.
+type ProhibitedKeyword struct {
+	ID        string    `json:"id"`
+	Keyword   string    `json:"keyword"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PriceChangeSource is what caused a variant price to change.
+type PriceChangeSource string
+
+const (
+	PriceChangeSourceManual        PriceChangeSource = "manual"
+	PriceChangeSourceImport        PriceChangeSource = "import"
+	PriceChangeSourceScheduleStart PriceChangeSource = "schedule_start"
+	PriceChangeSourceScheduleEnd   PriceChangeSource = "schedule_end"
+)
+
+// PriceHistoryEntry records a change of a variant's price. The new price is
+// in effect from ChangedAt until the next entry.
+type PriceHistoryEntry struct {
+	ID                     string            `json:"id"`
+	VariantID              string            `json:"variant_id"`
+	ProductID              string            `json:"product_id"`
+	PriceCents             int64             `json:"price_cents"`
+	PreviousPriceCents     int64             `json:"previous_price_cents"`
+	CompareAtCents         int64             `json:"compare_at_cents"`
+	PreviousCompareAtCents int64             `json:"previous_compare_at_cents"`
+	Source                 PriceChangeSource `json:"source"`
+	ScheduleID             string            `json:"schedule_id,omitempty"`
+	ChangedAt              time.Time         `json:"changed_at"`
+}
+
+// PriceScheduleStatus represents the lifecycle status of a scheduled price.
+type PriceScheduleStatus string
+
+const (
+	PriceScheduleStatusScheduled PriceScheduleStatus = "scheduled"
+	PriceScheduleStatusActive    PriceScheduleStatus = "active"
+	PriceScheduleStatusCompleted PriceScheduleStatus = "completed"
+	PriceScheduleStatusCancelled PriceScheduleStatus = "cancelled"
+)
+
+// PriceSchedule is a future price change of a variant. At StartsAt the
+// variant takes PriceCents (and CompareAtCents when set); at EndsAt it goes
+// back to the prices it had when the schedule started, unless the price was
+// changed again in the meantime. A schedule without an end is permanent.
+type PriceSchedule struct {
+	ID                   string              `json:"id"`
+	ProductID            string              `json:"product_id"`
+	VariantID            string              `json:"variant_id"`
+	SellerID             string              `json:"seller_id"`
+	PriceCents           int64               `json:"price_cents"`
+	CompareAtCents       *int64              `json:"compare_at_cents,omitempty"`
+	StartsAt             time.Time           `json:"starts_at"`
+	EndsAt               *time.Time          `json:"ends_at,omitempty"`
+	Status               PriceScheduleStatus `json:"status"`
+	RevertPriceCents     int64               `json:"revert_price_cents,omitempty"`
+	RevertCompareAtCents int64               `json:"revert_compare_at_cents,omitempty"`
+	CreatedAt            time.Time           `json:"created_at"`
+	UpdatedAt            time.Time           `json:"updated_at"`
+}
+
+// PriceSummary is the current price of a variant with the lowest prices
+// needed for "previous price" display under the EU Omnibus directive.
+// LowestPriceCents is the lowest price in effect during the last 30 days.
+// PriorLowestPriceCents is the lowest price in effect during the 30 days
+// before the current price took effect, the reference to show next to a
+// price reduction.
+type PriceSummary struct {
+	VariantID             string     `json:"variant_id"`
+	PriceCents            int64      `json:"price_cents"`
+	CompareAtCents        int64      `json:"compare_at_cents"`
+	Currency              string     `json:"currency"`
+	PriceSince            *time.Time `json:"price_since,omitempty"`
+	LowestPriceCents      int64      `json:"lowest_price_30d_cents"`
+	PriorLowestPriceCents int64      `json:"prior_lowest_price_30d_cents"`
+	// Presentment prices are in the buyer's currency: the seller's price
+	// for that currency when set, otherwise converted at ExchangeRate.
+	PresentmentCurrency       string  `json:"presentment_currency,omitempty"`
+	PresentmentPriceCents     int64   `json:"presentment_price_cents,omitempty"`
+	PresentmentCompareAtCents int64   `json:"presentment_compare_at_cents,omitempty"`
+	ExchangeRate              float64 `json:"exchange_rate,omitempty"`
+	PriceOverride             bool    `json:"price_override,omitempty"`
+}
+
+// VariantPrice is a seller-set price of a variant in a currency other than
+// its product's. Buyers shopping in that currency pay it instead of the
+// converted price.
+type VariantPrice struct {
+	VariantID      string    `json:"variant_id"`
+	ProductID      string    `json:"product_id"`
+	Currency       string    `json:"currency"`
+	PriceCents     int64     `json:"price_cents"`
+	CompareAtCents int64     `json:"compare_at_cents"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// DigitalFile is a downloadable file of a digital product. The file itself
+// is private media in the media service; buyers reach it through expiring
+// signed links. A file with a VariantID is only delivered to buyers of that
+// variant.
+type DigitalFile struct {
+	ID          string    `json:"id"`
+	ProductID   string    `json:"product_id"`
+	VariantID   string    `json:"variant_id,omitempty"`
+	MediaID     string    `json:"media_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// LicenseKeyStatus represents the state of a license key in a product's pool.
+type LicenseKeyStatus string
+
+const (
+	LicenseKeyStatusAvailable LicenseKeyStatus = "available"
+	LicenseKeyStatusAssigned  LicenseKeyStatus = "assigned"
+	LicenseKeyStatusRevoked   LicenseKeyStatus = "revoked"
+)
+
+// LicenseKey is a key from a digital product's pool. Keys without a
+// VariantID can be given to buyers of any variant.
+type LicenseKey struct {
+	ID            string           `json:"id"`
+	ProductID     string           `json:"product_id"`
+	VariantID     string           `json:"variant_id,omitempty"`
+	Key           string           `json:"key"`
+	Status        LicenseKeyStatus `json:"status"`
+	EntitlementID string           `json:"entitlement_id,omitempty"`
+	AssignedAt    *time.Time       `json:"assigned_at,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// EntitlementStatus represents the lifecycle of a buyer's access to a
+// digital product.
+type EntitlementStatus string
+
+const (
+	EntitlementStatusPending EntitlementStatus = "pending"
+	EntitlementStatusActive  EntitlementStatus = "active"
+	EntitlementStatusRevoked EntitlementStatus = "revoked"
+)
+
+// DigitalEntitlement grants the buyer of an order line access to a digital
+// product. It is recorded when the order is placed and becomes active once
+// the payment completes. KeysPending counts license keys still owed because
+// the pool ran dry. AmountCents is what the buyer paid for the line and
+// OrderTotalCents what they paid for the whole order, both in the order's
+// currency, to tell refunds of the line from refunds of the rest.
+type DigitalEntitlement struct {
+	ID              string            `json:"id"`
+	OrderID         string            `json:"order_id"`
+	BuyerID         string            `json:"buyer_id"`
+	SellerID        string            `json:"seller_id"`
+	ProductID       string            `json:"product_id"`
+	VariantID       string            `json:"variant_id,omitempty"`
+	Quantity        int               `json:"quantity"`
+	AmountCents     int64             `json:"amount_cents"`
+	OrderTotalCents int64             `json:"-"`
+	Status          EntitlementStatus `json:"status"`
+	DownloadLimit   int               `json:"download_limit"`
+	DownloadCount   int               `json:"download_count"`
+	ExpiresAt       *time.Time        `json:"expires_at,omitempty"`
+	KeysPending     int               `json:"keys_pending"`
+	ActivatedAt     *time.Time        `json:"activated_at,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	Files           []DigitalFile     `json:"files,omitempty"`
+	LicenseKeys     []LicenseKey      `json:"license_keys,omitempty"`
+}
+
+// DownloadLink is an expiring signed link to one file of an entitlement.
+// DownloadsRemaining is nil when downloads are unlimited.
+type DownloadLink struct {
+	FileID             string    `json:"file_id"`
+	FileName           string    `json:"file_name"`
+	URL                string    `json:"url"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	DownloadsRemaining *int      `json:"downloads_remaining,omitempty"`
+}
+
+// SellerRating is the rating of a seller rolled up from the approved reviews
+// of all its products.
+type SellerRating struct {
+	SellerID    string  `json:"seller_id"`
+	RatingAvg   float64 `json:"rating_avg"`
+	RatingCount int     `json:"rating_count"`
+}
+
+// ReviewSummary is the review service's aggregate of a product's approved
+// reviews. RatingDistribution counts reviews per star rating.
+type ReviewSummary struct {
+	ProductID          string      `json:"product_id"`
+	AverageRating      float64     `json:"average_rating"`
+	TotalReviews       int         `json:"total_reviews"`
+	RatingDistribution map[int]int `json:"rating_distribution"`
+}
+
+// ProductTranslation holds a product's content in one locale. Empty fields
+// fall back along the locale's fallback chain to the base content.
+type ProductTranslation struct {
+	ProductID   string    `json:"product_id"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CategoryTranslation holds a category's name and slug in one locale.
+type CategoryTranslation struct {
+	CategoryID string    `json:"category_id"`
+	Locale     string    `json:"locale"`
+	Name       string    `json:"name"`
+	Slug       string    `json:"slug"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// OptionValueTranslation holds an option value (e.g. "Large") in one locale.
+type OptionValueTranslation struct {
+	OptionValueID string    `json:"option_value_id"`
+	Locale        string    `json:"locale"`
+	Value         string    `json:"value"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// SubscriptionInterval is the unit of a subscription plan's delivery
+// interval.
+type SubscriptionInterval string
+
+const (
+	SubscriptionIntervalDay   SubscriptionInterval = "day"
+	SubscriptionIntervalWeek  SubscriptionInterval = "week"
+	SubscriptionIntervalMonth SubscriptionInterval = "month"
+)
+
+// IsValid reports whether the interval is a known unit.
+func (i SubscriptionInterval) IsValid() bool {
+	switch i {
+	case SubscriptionIntervalDay, SubscriptionIntervalWeek, SubscriptionIntervalMonth:
+		return true
+	}
+	return false
+}
+
+// SubscriptionPlan offers a variant as a repeat delivery ("subscribe &
+// save"): subscribers receive it every IntervalCount IntervalUnits at
+// PercentOff off its price. Percentages are stored as percentage * 100
+// (e.g. 1000 = 10%). Only active plans can be subscribed to.
+type SubscriptionPlan struct {
+	ID            string               `json:"id"`
+	ProductID     string               `json:"product_id"`
+	VariantID     string               `json:"variant_id"`
+	IntervalUnit  SubscriptionInterval `json:"interval_unit"`
+	IntervalCount int                  `json:"interval_count"`
+	PercentOff    int64                `json:"percent_off"`
+	IsActive      bool                 `json:"is_active"`
+	// The variant's name and SKU, set when plans are presented to buyers.
+	VariantName string    `json:"variant_name,omitempty"`
+	SKU         string    `json:"sku,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -2,10 +2,24 @@ package domain
 
 import "context"
 
+// Product event subjects.
+const (
+	EventProductCreated   = "product.created"
+	EventProductUpdated   = "product.updated"
+	EventProductDeleted   = "product.deleted"
+	EventStockUpdated     = "product.stock.updated"
+	EventPriceUpdated     = "product.price.updated"
+	EventDigitalDelivered = "product.digital.delivered"
+	EventRatingUpdated    = "product.rating.updated"
+)
+
 // EventPublisher defines the interface for publishing domain events.
 type EventPublisher interface {
 	PublishProductCreated(ctx context.Context, product *Product) error
 	PublishProductUpdated(ctx context.Context, product *Product) error
 	PublishProductDeleted(ctx context.Context, productID string) error
 	PublishStockUpdated(ctx context.Context, variantID string, newStock int, delta int) error
+	PublishPriceUpdated(ctx context.Context, product *Product, change *PriceHistoryEntry) error
+	PublishDigitalDelivered(ctx context.Context, orderID, buyerID string, entitlements []*DigitalEntitlement) error
+	PublishRatingUpdated(ctx context.Context, product *Product, seller *SellerRating) error
 }
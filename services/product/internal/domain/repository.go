@@ -1,18 +1,25 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ProductFilter defines filtering and pagination for product listing.
 type ProductFilter struct {
 	SellerID   string
 	CategoryID string
-	Status     string
-	Query      string
-	MinPrice   int64
-	MaxPrice   int64
-	SortBy     string
-	Page       int
-	PageSize   int
+	// IncludeSubcategories widens CategoryID to the category's descendants.
+	IncludeSubcategories bool
+	// CategoryIDs, when set, takes precedence over CategoryID.
+	CategoryIDs []string
+	Status      string
+	Query       string
+	MinPrice    int64
+	MaxPrice    int64
+	SortBy      string
+	Page        int
+	PageSize    int
 }
 
 // ProductRepository defines persistence operations for products.
@@ -23,6 +30,15 @@ type ProductRepository interface {
 	List(ctx context.Context, filter ProductFilter) ([]*Product, int64, error)
 	Update(ctx context.Context, p *Product) error
 	Delete(ctx context.Context, id string) error
+	// CountByCategory counts products per category, optionally only those
+	// with the given status.
+	CountByCategory(ctx context.Context, status string) (map[string]int64, error)
+	// AdjustRating atomically adds reviews to (or, with negative deltas,
+	// removes them from) a product's rating aggregates.
+	AdjustRating(ctx context.Context, productID string, countDelta int, ratingDelta int) error
+	SetRating(ctx context.Context, productID string, avg float64, count int) error
+	// GetSellerRating rolls the ratings of a seller's products up into one.
+	GetSellerRating(ctx context.Context, sellerID string) (*SellerRating, error)
 }
 
 // CategoryRepository defines persistence operations for categories.
@@ -31,6 +47,8 @@ type CategoryRepository interface {
 	GetByID(ctx context.Context, id string) (*Category, error)
 	List(ctx context.Context) ([]*Category, error)
 	Update(ctx context.Context, c *Category) error
+	// UpdateSortOrders sets the sort order of several categories at once.
+	UpdateSortOrders(ctx context.Context, sortOrders map[string]int) error
 	Delete(ctx context.Context, id string) error
 }
 
@@ -59,10 +77,32 @@ type OptionRepository interface {
 	DeleteOptionValue(ctx context.Context, valueID string) error
 }
 
+// TranslationRepository defines persistence operations for localized
+// catalog content. Translations are keyed by entity and locale; upserts
+// replace any existing translation in the same locale.
+type TranslationRepository interface {
+	UpsertProductTranslation(ctx context.Context, t *ProductTranslation) error
+	ListProductTranslations(ctx context.Context, productID string) ([]*ProductTranslation, error)
+	// ListProductTranslationsIn returns the translations of several products
+	// in any of the given locales.
+	ListProductTranslationsIn(ctx context.Context, productIDs []string, locales []string) ([]*ProductTranslation, error)
+	// GetProductTranslationBySlug finds a translation by its slug in locale,
+	// or in any locale when locale is empty.
+	GetProductTranslationBySlug(ctx context.Context, locale, slug string) (*ProductTranslation, error)
+	DeleteProductTranslation(ctx context.Context, productID, locale string) error
+	UpsertCategoryTranslation(ctx context.Context, t *CategoryTranslation) error
+	ListCategoryTranslations(ctx context.Context, locales []string) ([]*CategoryTranslation, error)
+	DeleteCategoryTranslation(ctx context.Context, categoryID, locale string) error
+	UpsertOptionValueTranslation(ctx context.Context, t *OptionValueTranslation) error
+	ListOptionValueTranslations(ctx context.Context, optionValueIDs []string, locales []string) ([]*OptionValueTranslation, error)
+	DeleteOptionValueTranslation(ctx context.Context, optionValueID, locale string) error
+}
+
 // VariantRepository defines persistence operations for product variants.
 type VariantRepository interface {
 	Create(ctx context.Context, v *Variant) error
 	GetByID(ctx context.Context, id string) (*Variant, error)
+	// GetBySKU returns the variant with a SKU, or nil if there is none.
 	GetBySKU(ctx context.Context, sku string) (*Variant, error)
 	ListByProduct(ctx context.Context, productID string) ([]Variant, error)
 	Update(ctx context.Context, v *Variant) error
@@ -71,3 +111,177 @@ type VariantRepository interface {
 	UpdateStock(ctx context.Context, variantID string, delta int) error
 	SetOptionValues(ctx context.Context, variantID string, values []VariantOptionValue) error
 }
+
+// ImportJobRepository defines persistence operations for catalog import jobs.
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *ImportJob) error
+	GetByID(ctx context.Context, id string) (*ImportJob, error)
+	ListBySeller(ctx context.Context, sellerID string, page, pageSize int) ([]*ImportJob, int64, error)
+	// ClaimNext marks the oldest pending job, or a running job started before
+	// staleBefore whose worker died, as running and returns it. It returns
+	// nil when there is nothing to run.
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*ImportJob, error)
+	Update(ctx context.Context, job *ImportJob) error
+}
+
+// CatalogRepositories are the repositories a catalog import writes a
+// product through.
+type CatalogRepositories struct {
+	Products          ProductRepository
+	Categories        CategoryRepository
+	Attributes        AttributeRepository
+	Options           OptionRepository
+	Variants          VariantRepository
+	ModerationReviews ModerationReviewRepository
+	PriceHistory      PriceHistoryRepository
+}
+
+// CatalogTransactor writes catalog changes in one database transaction.
+type CatalogTransactor interface {
+	// Transaction calls fn with repositories bound to a transaction, which
+	// is committed if fn returns nil and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(repos CatalogRepositories) error) error
+}
+
+// FeedItemRepository defines persistence operations for precomputed feed items.
+type FeedItemRepository interface {
+	// ReplaceForProduct swaps all items of a product for the given set.
+	ReplaceForProduct(ctx context.Context, productID string, items []FeedItem) error
+	DeleteByProduct(ctx context.Context, productID string) error
+	// DeleteUpdatedBefore removes items not refreshed since before, which
+	// after a full rebuild are those of products that no longer exist.
+	DeleteUpdatedBefore(ctx context.Context, before time.Time) error
+	// ListAfter returns up to limit items ordered by ID, starting after
+	// afterID, optionally limited to one seller.
+	ListAfter(ctx context.Context, sellerID string, afterID string, limit int) ([]FeedItem, error)
+}
+
+// FeedCategoryMappingRepository defines persistence operations for feed category mappings.
+type FeedCategoryMappingRepository interface {
+	Upsert(ctx context.Context, m *FeedCategoryMapping) error
+	Delete(ctx context.Context, categoryID string) error
+	List(ctx context.Context) ([]*FeedCategoryMapping, error)
+}
+
+// ModerationReviewFilter defines filtering and pagination for the moderation queue.
+type ModerationReviewFilter struct {
+	Status     string
+	ReviewerID string
+	SellerID   string
+	Page       int
+	PageSize   int
+}
+
+// ModerationReviewRepository defines persistence operations for moderation reviews.
+type ModerationReviewRepository interface {
+	Create(ctx context.Context, r *ModerationReview) error
+	GetByID(ctx context.Context, id string) (*ModerationReview, error)
+	Update(ctx context.Context, r *ModerationReview) error
+	// GetOpenByProduct returns the product's pending review, or nil if none.
+	GetOpenByProduct(ctx context.Context, productID string) (*ModerationReview, error)
+	// GetLatestApproved returns the product's most recent approved review,
+	// or nil if it was never approved.
+	GetLatestApproved(ctx context.Context, productID string) (*ModerationReview, error)
+	ListByProduct(ctx context.Context, productID string) ([]*ModerationReview, error)
+	List(ctx context.Context, filter ModerationReviewFilter) ([]*ModerationReview, int64, error)
+}
+
+// ProhibitedKeywordRepository defines persistence operations for prohibited keywords.
+type ProhibitedKeywordRepository interface {
+	Create(ctx context.Context, k *ProhibitedKeyword) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]*ProhibitedKeyword, error)
+}
+
+// PriceHistoryRepository defines persistence operations for variant price history.
+type PriceHistoryRepository interface {
+	Create(ctx context.Context, e *PriceHistoryEntry) error
+	// ListByVariant returns the entries after since, oldest first.
+	ListByVariant(ctx context.Context, variantID string, since time.Time) ([]*PriceHistoryEntry, error)
+	// GetLatestAt returns the most recent entry at or before at, or nil if
+	// the price had not changed by then.
+	GetLatestAt(ctx context.Context, variantID string, at time.Time) (*PriceHistoryEntry, error)
+}
+
+// PriceScheduleRepository defines persistence operations for scheduled prices.
+type PriceScheduleRepository interface {
+	Create(ctx context.Context, s *PriceSchedule) error
+	GetByID(ctx context.Context, id string) (*PriceSchedule, error)
+	ListByVariant(ctx context.Context, variantID string) ([]*PriceSchedule, error)
+	// UpdateIfStatus saves the schedule only if it still has the status
+	// from, so that concurrent runners apply each transition once. It
+	// reports whether the schedule was saved.
+	UpdateIfStatus(ctx context.Context, s *PriceSchedule, from PriceScheduleStatus) (bool, error)
+	// ListDueToStart returns scheduled schedules starting at or before now.
+	ListDueToStart(ctx context.Context, now time.Time, limit int) ([]*PriceSchedule, error)
+	// ListDueToEnd returns active schedules ending at or before now.
+	ListDueToEnd(ctx context.Context, now time.Time, limit int) ([]*PriceSchedule, error)
+}
+
+// VariantPriceRepository defines persistence operations for sellers'
+// per-currency variant prices. Upserts replace any existing price in the
+// same currency.
+type VariantPriceRepository interface {
+	Upsert(ctx context.Context, p *VariantPrice) error
+	// Get returns the variant's price in currency, or nil if it has none.
+	Get(ctx context.Context, variantID, currency string) (*VariantPrice, error)
+	ListByVariant(ctx context.Context, variantID string) ([]*VariantPrice, error)
+	Delete(ctx context.Context, variantID, currency string) error
+}
+
+// SubscriptionPlanRepository defines persistence operations for variant
+// subscription plans.
+type SubscriptionPlanRepository interface {
+	Create(ctx context.Context, p *SubscriptionPlan) error
+	GetByID(ctx context.Context, id string) (*SubscriptionPlan, error)
+	// ListByProduct lists a product's plans, only the active ones when
+	// activeOnly is set.
+	ListByProduct(ctx context.Context, productID string, activeOnly bool) ([]*SubscriptionPlan, error)
+	Update(ctx context.Context, p *SubscriptionPlan) error
+	Delete(ctx context.Context, id string) error
+}
+
+// DigitalFileRepository defines persistence operations for digital product files.
+type DigitalFileRepository interface {
+	Create(ctx context.Context, f *DigitalFile) error
+	GetByID(ctx context.Context, id string) (*DigitalFile, error)
+	ListByProduct(ctx context.Context, productID string) ([]*DigitalFile, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// LicenseKeyRepository defines persistence operations for license key pools.
+type LicenseKeyRepository interface {
+	// CreateBatch adds keys to the pool, skipping keys the product already
+	// has, and returns how many were added.
+	CreateBatch(ctx context.Context, keys []*LicenseKey) (int, error)
+	GetByID(ctx context.Context, id string) (*LicenseKey, error)
+	ListByProduct(ctx context.Context, productID string, status LicenseKeyStatus) ([]*LicenseKey, error)
+	ListByEntitlement(ctx context.Context, entitlementID string) ([]*LicenseKey, error)
+	// ClaimAvailable atomically assigns up to n available keys usable for
+	// the variant to the entitlement and returns them.
+	ClaimAvailable(ctx context.Context, productID, variantID, entitlementID string, n int) ([]*LicenseKey, error)
+	RevokeByEntitlement(ctx context.Context, entitlementID string) error
+	Delete(ctx context.Context, id string) error
+}
+
+// DigitalEntitlementRepository defines persistence operations for buyers'
+// access to digital products.
+type DigitalEntitlementRepository interface {
+	Create(ctx context.Context, e *DigitalEntitlement) error
+	GetByID(ctx context.Context, id string) (*DigitalEntitlement, error)
+	Update(ctx context.Context, e *DigitalEntitlement) error
+	ListByOrder(ctx context.Context, orderID string) ([]*DigitalEntitlement, error)
+	ListByBuyer(ctx context.Context, buyerID string, page, pageSize int) ([]*DigitalEntitlement, int64, error)
+	// ListAwaitingKeys lists active entitlements of a product still owed
+	// license keys, oldest first.
+	ListAwaitingKeys(ctx context.Context, productID string) ([]*DigitalEntitlement, error)
+	// IncrementDownloads counts a download unless the entitlement's limit
+	// is reached, reporting whether it was counted.
+	IncrementDownloads(ctx context.Context, id string) (bool, error)
+}
+
+// ReviewSummaryProvider fetches the approved review summary of a product
+// from the review service.
+type ReviewSummaryProvider interface {
+	GetProductSummary(ctx context.Context, productID string) (*ReviewSummary, error)
+}
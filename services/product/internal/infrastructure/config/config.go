@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 )
 
 // Config holds all configuration for the product service.
@@ -16,20 +17,41 @@ type Config struct {
 	HTTPPort         string
 	GRPCPort         string
 	LogLevel         string
+	StorefrontURL    string
+	MinProductImages int
+	// Digital products are downloaded from the media service through links
+	// signed with a secret it shares.
+	MediaPublicURL         string
+	DownloadSigningSecret  string
+	DownloadLinkTTLMinutes int
+	// ReviewServiceURL is where review summaries are fetched to rebuild ratings.
+	ReviewServiceURL string
+	// ExchangeRatesSource is a JSON rates file path or URL; when empty the
+	// converter's built-in rates are used.
+	ExchangeRatesSource         string
+	ExchangeRatesRefreshMinutes int
 }
 
 // Load reads configuration from environment variables with sensible defaults.
 func Load() *Config {
 	return &Config{
-		PostgresUser:     getEnv("POSTGRES_USER", "postgres"),
-		PostgresPassword: getEnv("POSTGRES_PASSWORD", "postgres"),
-		PostgresHost:     getEnv("POSTGRES_HOST", "localhost"),
-		PostgresPort:     getEnv("POSTGRES_PORT", "5432"),
-		DBName:           getEnv("DB_NAME", "ecommerce_products"),
-		NatsURL:          getEnv("NATS_URL", "nats://localhost:4222"),
-		HTTPPort:         getEnv("HTTP_PORT", "8081"),
-		GRPCPort:         getEnv("GRPC_PORT", "9081"),
-		LogLevel:         getEnv("LOG_LEVEL", "info"),
+		PostgresUser:                getEnv("POSTGRES_USER", "postgres"),
+		PostgresPassword:            getEnv("POSTGRES_PASSWORD", "postgres"),
+		PostgresHost:                getEnv("POSTGRES_HOST", "localhost"),
+		PostgresPort:                getEnv("POSTGRES_PORT", "5432"),
+		DBName:                      getEnv("DB_NAME", "ecommerce_products"),
+		NatsURL:                     getEnv("NATS_URL", "nats://localhost:4222"),
+		HTTPPort:                    getEnv("HTTP_PORT", "8081"),
+		GRPCPort:                    getEnv("GRPC_PORT", "9081"),
+		LogLevel:                    getEnv("LOG_LEVEL", "info"),
+		StorefrontURL:               getEnv("STOREFRONT_URL", "http://localhost:3000"),
+		MinProductImages:            getEnvInt("MODERATION_MIN_IMAGES", 1),
+		MediaPublicURL:              getEnv("MEDIA_PUBLIC_URL", "http://localhost:8089"),
+		DownloadSigningSecret:       getEnv("DOWNLOAD_SIGNING_SECRET", "dev-download-signing-secret"),
+		DownloadLinkTTLMinutes:      getEnvInt("DOWNLOAD_LINK_TTL_MINUTES", 15),
+		ReviewServiceURL:            getEnv("REVIEW_SERVICE_URL", "http://localhost:8088"),
+		ExchangeRatesSource:         getEnv("EXCHANGE_RATES_SOURCE", ""),
+		ExchangeRatesRefreshMinutes: getEnvInt("EXCHANGE_RATES_REFRESH_MINUTES", 60),
 	}
 }
 
@@ -47,3 +69,12 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
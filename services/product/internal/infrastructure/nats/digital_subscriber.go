@@ -0,0 +1,159 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/usecase"
+)
+
+// Order and payment subjects the digital fulfilment listens to.
+const (
+	subjectOrderCreated         = "order.created"
+	subjectOrderCancelled       = "order.cancelled"
+	subjectSellerOrderCancelled = "order.seller_order.cancelled"
+	subjectPaymentCompleted     = "payment.completed"
+	subjectPaymentRefunded      = "payment.refunded"
+)
+
+// orderCreatedEvent holds the fields of an order.created payload used for
+// digital fulfilment.
+type orderCreatedEvent struct {
+	OrderID    string `json:"order_id"`
+	BuyerID    string `json:"buyer_id"`
+	TotalCents int64  `json:"total_cents"`
+	Items      []struct {
+		ProductID      string `json:"product_id"`
+		VariantID      string `json:"variant_id"`
+		Quantity       int    `json:"quantity"`
+		UnitPriceCents int64  `json:"unit_price_cents"`
+		DiscountCents  int64  `json:"discount_cents"`
+		SellerID       string `json:"seller_id"`
+		IsDigital      bool   `json:"is_digital"`
+	} `json:"items"`
+}
+
+// orderIDEvent holds the order ID shared by payment and order status payloads.
+type orderIDEvent struct {
+	OrderID string `json:"order_id"`
+}
+
+// refundEvent holds the fields of a payment.refunded payload. AmountCents
+// is the amount of this refund.
+type refundEvent struct {
+	OrderID     string `json:"order_id"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+// sellerOrderEvent holds the fields of an order.seller_order.cancelled
+// payload.
+type sellerOrderEvent struct {
+	OrderID  string `json:"order_id"`
+	SellerID string `json:"seller_id"`
+}
+
+// StartDigitalSubscriber delivers digital products: entitlements are recorded
+// when an order is placed, activated when its payment completes and revoked
+// when the order, or the seller's part of it, is cancelled, or when a refund
+// reaches into them.
+func StartDigitalSubscriber(p *Publisher, digitalUC *usecase.DigitalUseCase) error {
+	if _, err := p.Subscribe(subjectOrderCreated, func(data []byte) {
+		handleDigitalEvent(subjectOrderCreated, data, func(ctx context.Context, data []byte) (string, int, error) {
+			var event orderCreatedEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", 0, err
+			}
+			var items []usecase.DigitalOrderItem
+			for _, item := range event.Items {
+				if !item.IsDigital {
+					continue
+				}
+				items = append(items, usecase.DigitalOrderItem{
+					ProductID:   item.ProductID,
+					VariantID:   item.VariantID,
+					SellerID:    item.SellerID,
+					Quantity:    item.Quantity,
+					AmountCents: item.UnitPriceCents*int64(item.Quantity) - item.DiscountCents,
+				})
+			}
+			if len(items) == 0 {
+				return event.OrderID, 0, nil
+			}
+			n, err := digitalUC.RecordOrder(ctx, event.OrderID, event.BuyerID, event.TotalCents, items)
+			return event.OrderID, n, err
+		})
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(subjectPaymentCompleted, func(data []byte) {
+		handleDigitalEvent(subjectPaymentCompleted, data, func(ctx context.Context, data []byte) (string, int, error) {
+			var event orderIDEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", 0, err
+			}
+			n, err := digitalUC.FulfilOrder(ctx, event.OrderID)
+			return event.OrderID, n, err
+		})
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(subjectOrderCancelled, func(data []byte) {
+		handleDigitalEvent(subjectOrderCancelled, data, func(ctx context.Context, data []byte) (string, int, error) {
+			var event orderIDEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", 0, err
+			}
+			n, err := digitalUC.RevokeOrder(ctx, event.OrderID)
+			return event.OrderID, n, err
+		})
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(subjectSellerOrderCancelled, func(data []byte) {
+		handleDigitalEvent(subjectSellerOrderCancelled, data, func(ctx context.Context, data []byte) (string, int, error) {
+			var event sellerOrderEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", 0, err
+			}
+			n, err := digitalUC.RevokeSellerOrder(ctx, event.OrderID, event.SellerID)
+			return event.OrderID, n, err
+		})
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(subjectPaymentRefunded, func(data []byte) {
+		handleDigitalEvent(subjectPaymentRefunded, data, func(ctx context.Context, data []byte) (string, int, error) {
+			var event refundEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", 0, err
+			}
+			n, err := digitalUC.RevokeRefund(ctx, event.OrderID, event.AmountCents)
+			return event.OrderID, n, err
+		})
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func handleDigitalEvent(subject string, data []byte, apply func(ctx context.Context, data []byte) (string, int, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	orderID, n, err := apply(ctx, data)
+	if err != nil {
+		log.Error().Err(err).Str("subject", subject).Str("order_id", orderID).Msg("failed to process digital order event")
+		return
+	}
+	if n > 0 {
+		log.Info().Str("subject", subject).Str("order_id", orderID).Int("entitlements", n).Msg("processed digital order event")
+	}
+}
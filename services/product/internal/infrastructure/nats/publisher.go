@@ -30,6 +30,20 @@ func NewPublisher(url string) (*Publisher, error) {
 	return &Publisher{conn: nc}, nil
 }
 
+// Subscribe subscribes to a NATS subject with a handler function.
+func (p *Publisher) Subscribe(subject string, handler func(data []byte)) (*nats.Subscription, error) {
+	sub, err := p.conn.Subscribe(subject, func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("subject", subject).Msg("failed to subscribe")
+		return nil, err
+	}
+
+	log.Info().Str("subject", subject).Msg("subscribed to subject")
+	return sub, nil
+}
+
 // Close closes the NATS connection.
 func (p *Publisher) Close() {
 	if p.conn != nil {
@@ -70,6 +84,51 @@ type StockUpdatedEvent struct {
 	UpdatedAt string `json:"updated_at"`
 }
 
+// PriceUpdatedEvent is the payload for product.price.updated events.
+type PriceUpdatedEvent struct {
+	ProductID              string `json:"product_id"`
+	VariantID              string `json:"variant_id"`
+	PriceCents             int64  `json:"price_cents"`
+	PreviousPriceCents     int64  `json:"previous_price_cents"`
+	CompareAtCents         int64  `json:"compare_at_cents"`
+	PreviousCompareAtCents int64  `json:"previous_compare_at_cents"`
+	Currency               string `json:"currency"`
+	Source                 string `json:"source"`
+	UpdatedAt              string `json:"updated_at"`
+}
+
+// DigitalDeliveredEvent is the payload for product.digital.delivered events.
+type DigitalDeliveredEvent struct {
+	OrderID     string                 `json:"order_id"`
+	BuyerID     string                 `json:"buyer_id"`
+	Items       []DigitalDeliveredItem `json:"items"`
+	DeliveredAt string                 `json:"delivered_at"`
+}
+
+// DigitalDeliveredItem is one delivered entitlement of a
+// product.digital.delivered event.
+type DigitalDeliveredItem struct {
+	EntitlementID string `json:"entitlement_id"`
+	ProductID     string `json:"product_id"`
+	VariantID     string `json:"variant_id,omitempty"`
+	Quantity      int    `json:"quantity"`
+	DownloadLimit int    `json:"download_limit"`
+	ExpiresAt     string `json:"expires_at,omitempty"`
+	KeysPending   int    `json:"keys_pending"`
+}
+
+// RatingUpdatedEvent is the payload for product.rating.updated events. It
+// carries the seller's rolled up rating along with the product's.
+type RatingUpdatedEvent struct {
+	ProductID         string  `json:"product_id"`
+	SellerID          string  `json:"seller_id"`
+	RatingAvg         float64 `json:"rating_avg"`
+	RatingCount       int     `json:"rating_count"`
+	SellerRatingAvg   float64 `json:"seller_rating_avg"`
+	SellerRatingCount int     `json:"seller_rating_count"`
+	UpdatedAt         string  `json:"updated_at"`
+}
+
 func (p *Publisher) publish(subject string, data interface{}) error {
 	bytes, err := json.Marshal(data)
 	if err != nil {
@@ -89,7 +148,7 @@ func (p *Publisher) PublishProductCreated(_ context.Context, product *domain.Pro
 		Status:     string(product.Status),
 		CreatedAt:  product.CreatedAt.Format(time.RFC3339),
 	}
-	if err := p.publish("product.created", event); err != nil {
+	if err := p.publish(domain.EventProductCreated, event); err != nil {
 		log.Error().Err(err).Str("product_id", product.ID).Msg("Failed to publish product.created event")
 		return err
 	}
@@ -105,7 +164,7 @@ func (p *Publisher) PublishProductUpdated(_ context.Context, product *domain.Pro
 		Status:    string(product.Status),
 		UpdatedAt: product.UpdatedAt.Format(time.RFC3339),
 	}
-	if err := p.publish("product.updated", event); err != nil {
+	if err := p.publish(domain.EventProductUpdated, event); err != nil {
 		log.Error().Err(err).Str("product_id", product.ID).Msg("Failed to publish product.updated event")
 		return err
 	}
@@ -119,7 +178,7 @@ func (p *Publisher) PublishProductDeleted(_ context.Context, productID string) e
 		ID:        productID,
 		DeletedAt: time.Now().UTC().Format(time.RFC3339),
 	}
-	if err := p.publish("product.deleted", event); err != nil {
+	if err := p.publish(domain.EventProductDeleted, event); err != nil {
 		log.Error().Err(err).Str("product_id", productID).Msg("Failed to publish product.deleted event")
 		return err
 	}
@@ -135,10 +194,80 @@ func (p *Publisher) PublishStockUpdated(_ context.Context, variantID string, new
 		Delta:     delta,
 		UpdatedAt: time.Now().UTC().Format(time.RFC3339),
 	}
-	if err := p.publish("product.stock.updated", event); err != nil {
+	if err := p.publish(domain.EventStockUpdated, event); err != nil {
 		log.Error().Err(err).Str("variant_id", variantID).Msg("Failed to publish product.stock.updated event")
 		return err
 	}
 	log.Debug().Str("variant_id", variantID).Int("delta", delta).Msg("Published product.stock.updated event")
 	return nil
 }
+
+// PublishPriceUpdated publishes a product.price.updated event.
+func (p *Publisher) PublishPriceUpdated(_ context.Context, product *domain.Product, change *domain.PriceHistoryEntry) error {
+	event := PriceUpdatedEvent{
+		ProductID:              change.ProductID,
+		VariantID:              change.VariantID,
+		PriceCents:             change.PriceCents,
+		PreviousPriceCents:     change.PreviousPriceCents,
+		CompareAtCents:         change.CompareAtCents,
+		PreviousCompareAtCents: change.PreviousCompareAtCents,
+		Currency:               product.Currency,
+		Source:                 string(change.Source),
+		UpdatedAt:              change.ChangedAt.Format(time.RFC3339),
+	}
+	if err := p.publish(domain.EventPriceUpdated, event); err != nil {
+		log.Error().Err(err).Str("variant_id", change.VariantID).Msg("Failed to publish product.price.updated event")
+		return err
+	}
+	log.Debug().Str("variant_id", change.VariantID).Int64("price_cents", change.PriceCents).Msg("Published product.price.updated event")
+	return nil
+}
+
+// PublishDigitalDelivered publishes a product.digital.delivered event.
+func (p *Publisher) PublishDigitalDelivered(_ context.Context, orderID, buyerID string, entitlements []*domain.DigitalEntitlement) error {
+	items := make([]DigitalDeliveredItem, len(entitlements))
+	for i, e := range entitlements {
+		items[i] = DigitalDeliveredItem{
+			EntitlementID: e.ID,
+			ProductID:     e.ProductID,
+			VariantID:     e.VariantID,
+			Quantity:      e.Quantity,
+			DownloadLimit: e.DownloadLimit,
+			KeysPending:   e.KeysPending,
+		}
+		if e.ExpiresAt != nil {
+			items[i].ExpiresAt = e.ExpiresAt.Format(time.RFC3339)
+		}
+	}
+	event := DigitalDeliveredEvent{
+		OrderID:     orderID,
+		BuyerID:     buyerID,
+		Items:       items,
+		DeliveredAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := p.publish(domain.EventDigitalDelivered, event); err != nil {
+		log.Error().Err(err).Str("order_id", orderID).Msg("Failed to publish product.digital.delivered event")
+		return err
+	}
+	log.Debug().Str("order_id", orderID).Int("items", len(items)).Msg("Published product.digital.delivered event")
+	return nil
+}
+
+// PublishRatingUpdated publishes a product.rating.updated event.
+func (p *Publisher) PublishRatingUpdated(_ context.Context, product *domain.Product, seller *domain.SellerRating) error {
+	event := RatingUpdatedEvent{
+		ProductID:         product.ID,
+		SellerID:          product.SellerID,
+		RatingAvg:         product.RatingAvg,
+		RatingCount:       product.RatingCount,
+		SellerRatingAvg:   seller.RatingAvg,
+		SellerRatingCount: seller.RatingCount,
+		UpdatedAt:         time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := p.publish(domain.EventRatingUpdated, event); err != nil {
+		log.Error().Err(err).Str("product_id", product.ID).Msg("Failed to publish product.rating.updated event")
+		return err
+	}
+	log.Debug().Str("product_id", product.ID).Float64("rating_avg", product.RatingAvg).Msg("Published product.rating.updated event")
+	return nil
+}
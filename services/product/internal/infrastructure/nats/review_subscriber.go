@@ -0,0 +1,62 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/usecase"
+)
+
+// Review subjects that change product ratings.
+const (
+	subjectReviewApproved = "review.approved"
+	subjectReviewUpdated  = "review.updated"
+	subjectReviewDeleted  = "review.deleted"
+)
+
+// reviewEvent holds the fields of review.approved, review.updated and
+// review.deleted payloads: the review before and after the change.
+type reviewEvent struct {
+	ReviewID       string `json:"review_id"`
+	ProductID      string `json:"product_id"`
+	Rating         int    `json:"rating"`
+	PreviousRating int    `json:"previous_rating"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status"`
+}
+
+// StartReviewSubscriber keeps product and seller ratings current as reviews
+// are approved, edited and deleted.
+func StartReviewSubscriber(p *Publisher, ratingUC *usecase.RatingUseCase) error {
+	for _, subject := range []string{subjectReviewApproved, subjectReviewUpdated, subjectReviewDeleted} {
+		if _, err := p.Subscribe(subject, func(data []byte) {
+			handleReviewEvent(subject, data, ratingUC)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handleReviewEvent(subject string, data []byte, ratingUC *usecase.RatingUseCase) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var event reviewEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		log.Error().Err(err).Str("subject", subject).Msg("failed to decode review event")
+		return
+	}
+	if _, err := ratingUC.ApplyReviewChange(ctx, usecase.ReviewChange{
+		ProductID:      event.ProductID,
+		Rating:         event.Rating,
+		PreviousRating: event.PreviousRating,
+		Status:         event.Status,
+		PreviousStatus: event.PreviousStatus,
+	}); err != nil {
+		log.Error().Err(err).Str("subject", subject).Str("review_id", event.ReviewID).Msg("failed to update product rating")
+	}
+}
@@ -0,0 +1,84 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+	"github.com/southern-martin/ecommerce/services/product/internal/usecase"
+)
+
+// productIDEvent holds the product ID shared by product.created,
+// product.updated and product.deleted payloads.
+type productIDEvent struct {
+	ID string `json:"id"`
+}
+
+// StartFeedSubscriber keeps the product feeds up to date by refreshing the
+// feed items of every product that is created, updated, deleted or has its
+// stock or prices changed.
+func StartFeedSubscriber(p *Publisher, feedUC *usecase.FeedUseCase) error {
+	for _, subject := range []string{domain.EventProductCreated, domain.EventProductUpdated} {
+		if _, err := p.Subscribe(subject, func(data []byte) {
+			handleFeedEvent(subject, data, func(ctx context.Context, data []byte) (string, error) {
+				var event productIDEvent
+				if err := json.Unmarshal(data, &event); err != nil {
+					return "", err
+				}
+				return event.ID, feedUC.RefreshProduct(ctx, event.ID)
+			})
+		}); err != nil {
+			return err
+		}
+	}
+
+	if _, err := p.Subscribe(domain.EventProductDeleted, func(data []byte) {
+		handleFeedEvent(domain.EventProductDeleted, data, func(ctx context.Context, data []byte) (string, error) {
+			var event productIDEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", err
+			}
+			return event.ID, feedUC.RemoveProduct(ctx, event.ID)
+		})
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(domain.EventStockUpdated, func(data []byte) {
+		handleFeedEvent(domain.EventStockUpdated, data, func(ctx context.Context, data []byte) (string, error) {
+			var event StockUpdatedEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", err
+			}
+			return event.VariantID, feedUC.RefreshVariant(ctx, event.VariantID)
+		})
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(domain.EventPriceUpdated, func(data []byte) {
+		handleFeedEvent(domain.EventPriceUpdated, data, func(ctx context.Context, data []byte) (string, error) {
+			var event PriceUpdatedEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return "", err
+			}
+			return event.VariantID, feedUC.RefreshVariant(ctx, event.VariantID)
+		})
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func handleFeedEvent(subject string, data []byte, apply func(ctx context.Context, data []byte) (string, error)) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if id, err := apply(ctx, data); err != nil {
+		log.Error().Err(err).Str("subject", subject).Str("id", id).Msg("failed to refresh product feed")
+	}
+}
@@ -0,0 +1,69 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// Client implements domain.ReviewSummaryProvider on top of the review
+// service's HTTP API, which serves the same summary as its gRPC
+// GetProductSummary.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new review service client.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// summaryResponse is the body of GET /api/v1/products/:product_id/reviews/summary.
+type summaryResponse struct {
+	Summary struct {
+		ProductID          string
+		AverageRating      float64
+		TotalReviews       int
+		RatingDistribution map[int]int
+	} `json:"summary"`
+}
+
+// GetProductSummary fetches the approved review summary of a product.
+func (c *Client) GetProductSummary(ctx context.Context, productID string) (*domain.ReviewSummary, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/products/%s/reviews/summary", c.baseURL, url.PathEscape(productID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("review service returned %s", resp.Status)
+	}
+
+	var body summaryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode review summary: %w", err)
+	}
+	return &domain.ReviewSummary{
+		ProductID:          productID,
+		AverageRating:      body.Summary.AverageRating,
+		TotalReviews:       body.Summary.TotalReviews,
+		RatingDistribution: body.Summary.RatingDistribution,
+	}, nil
+}
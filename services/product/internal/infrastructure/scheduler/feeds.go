@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/usecase"
+)
+
+// feedRebuildTimeout bounds the time spent on a full feed rebuild.
+const feedRebuildTimeout = 30 * time.Minute
+
+// StartFeedRebuilder rebuilds the product feeds every interval until ctx is
+// cancelled. Feeds are normally kept current from product events; the
+// periodic rebuild repairs items whose events were missed.
+func StartFeedRebuilder(ctx context.Context, feedUC *usecase.FeedUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			runCtx, cancel := context.WithTimeout(ctx, feedRebuildTimeout)
+			n, err := feedUC.Rebuild(runCtx)
+			cancel()
+			if err != nil {
+				log.Error().Err(err).Msg("Product feed rebuild failed")
+				continue
+			}
+			log.Info().Int("products", n).Msg("Product feeds rebuilt")
+		}
+	}()
+}
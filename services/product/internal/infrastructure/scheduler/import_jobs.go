@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/usecase"
+)
+
+// importJobTimeout bounds the time spent on a single import job.
+const importJobTimeout = 10 * time.Minute
+
+// StartImportJobRunner processes queued catalog import jobs until ctx is
+// cancelled. Every interval it drains the queue one job at a time.
+func StartImportJobRunner(ctx context.Context, catalogUC *usecase.CatalogUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			for runNextImport(ctx, catalogUC) {
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runNextImport runs one job and reports whether the queue may hold more.
+func runNextImport(ctx context.Context, catalogUC *usecase.CatalogUseCase) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, importJobTimeout)
+	defer cancel()
+
+	ran, err := catalogUC.RunNextImport(runCtx)
+	if err != nil {
+		log.Error().Err(err).Msg("Catalog import job failed")
+	}
+	return ran
+}
@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/usecase"
+)
+
+// priceScheduleTimeout bounds the time spent applying due price schedules.
+const priceScheduleTimeout = 5 * time.Minute
+
+// StartPriceScheduler applies scheduled price changes every interval until
+// ctx is cancelled, starting the schedules that are due and reverting the
+// ones that have ended.
+func StartPriceScheduler(ctx context.Context, pricingUC *usecase.PricingUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runCtx, cancel := context.WithTimeout(ctx, priceScheduleTimeout)
+			started, ended, err := pricingUC.ApplyDueSchedules(runCtx)
+			cancel()
+			if err != nil {
+				log.Error().Err(err).Msg("Applying price schedules failed")
+			}
+			if started > 0 || ended > 0 {
+				log.Info().Int("started", started).Int("ended", ended).Msg("Applied price schedules")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
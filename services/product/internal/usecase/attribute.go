@@ -32,6 +32,8 @@ type CreateAttributeInput struct {
 	Filterable bool
 	Options    []string
 	Unit       string
+	MinValue   *float64
+	MaxValue   *float64
 	SortOrder  int
 }
 
@@ -43,6 +45,9 @@ func (uc *AttributeUseCase) CreateAttributeDefinition(ctx context.Context, input
 	if input.Type == "" {
 		return nil, fmt.Errorf("attribute type is required")
 	}
+	if !validAttributeType(input.Type) {
+		return nil, fmt.Errorf("invalid attribute type: %s", input.Type)
+	}
 
 	attr := &domain.AttributeDefinition{
 		ID:         uuid.New().String(),
@@ -53,9 +58,14 @@ func (uc *AttributeUseCase) CreateAttributeDefinition(ctx context.Context, input
 		Filterable: input.Filterable,
 		Options:    input.Options,
 		Unit:       input.Unit,
+		MinValue:   input.MinValue,
+		MaxValue:   input.MaxValue,
 		SortOrder:  input.SortOrder,
 		CreatedAt:  time.Now().UTC(),
 	}
+	if err := checkAttributeDefinition(attr); err != nil {
+		return nil, err
+	}
 
 	if err := uc.attributeRepo.CreateDefinition(ctx, attr); err != nil {
 		return nil, fmt.Errorf("failed to create attribute definition: %w", err)
@@ -76,6 +86,9 @@ type UpdateAttributeInput struct {
 	Filterable *bool
 	Options    []string
 	Unit       *string
+	MinValue   *float64
+	MaxValue   *float64
+	ClearRange bool // removes the numeric range before MinValue and MaxValue apply
 	SortOrder  *int
 }
 
@@ -102,9 +115,22 @@ func (uc *AttributeUseCase) UpdateAttributeDefinition(ctx context.Context, id st
 	if input.Unit != nil {
 		attr.Unit = *input.Unit
 	}
+	if input.ClearRange {
+		attr.MinValue = nil
+		attr.MaxValue = nil
+	}
+	if input.MinValue != nil {
+		attr.MinValue = input.MinValue
+	}
+	if input.MaxValue != nil {
+		attr.MaxValue = input.MaxValue
+	}
 	if input.SortOrder != nil {
 		attr.SortOrder = *input.SortOrder
 	}
+	if err := checkAttributeDefinition(attr); err != nil {
+		return nil, err
+	}
 
 	if err := uc.attributeRepo.UpdateDefinition(ctx, attr); err != nil {
 		return nil, fmt.Errorf("failed to update attribute definition: %w", err)
@@ -152,18 +178,50 @@ func (uc *AttributeUseCase) ListCategoryAttributes(ctx context.Context, category
 	return uc.attributeRepo.ListByCategory(ctx, categoryID)
 }
 
-// SetProductAttributeValues sets attribute values on a product.
-func (uc *AttributeUseCase) SetProductAttributeValues(ctx context.Context, productID string, values []domain.ProductAttributeValue) error {
-	for i := range values {
-		if values[i].ID == "" {
-			values[i].ID = uuid.New().String()
-		}
-		values[i].ProductID = productID
+// GetCategorySchema lists the attributes products of a category carry,
+// including those inherited from its ancestors.
+func (uc *AttributeUseCase) GetCategorySchema(ctx context.Context, categoryID string) ([]*domain.AttributeDefinition, error) {
+	if _, err := uc.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, fmt.Errorf("category not found: %w", err)
 	}
-	return uc.attributeRepo.SetProductValues(ctx, productID, values)
+	schema, err := loadAttributeSchema(ctx, uc.attributeRepo, uc.categoryRepo, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return schema.defs, nil
 }
 
 // GetProductAttributeValues retrieves attribute values for a product.
 func (uc *AttributeUseCase) GetProductAttributeValues(ctx context.Context, productID string) ([]domain.ProductAttributeValue, error) {
 	return uc.attributeRepo.GetProductValues(ctx, productID)
 }
+
+func validAttributeType(t domain.AttributeType) bool {
+	switch t {
+	case domain.AttributeTypeText, domain.AttributeTypeNumber, domain.AttributeTypeSelect,
+		domain.AttributeTypeMultiSelect, domain.AttributeTypeColor, domain.AttributeTypeBool:
+		return true
+	}
+	return false
+}
+
+// checkAttributeDefinition checks that a definition can validate values:
+// select attributes need options and only numbers have a range.
+func checkAttributeDefinition(attr *domain.AttributeDefinition) error {
+	switch attr.Type {
+	case domain.AttributeTypeSelect, domain.AttributeTypeMultiSelect:
+		if len(attr.Options) == 0 {
+			return fmt.Errorf("options are required for %s attributes", attr.Type)
+		}
+	}
+	if attr.MinValue == nil && attr.MaxValue == nil {
+		return nil
+	}
+	if attr.Type != domain.AttributeTypeNumber {
+		return fmt.Errorf("min and max values are only allowed on number attributes")
+	}
+	if attr.MinValue != nil && attr.MaxValue != nil && *attr.MinValue > *attr.MaxValue {
+		return fmt.Errorf("min value must not exceed max value")
+	}
+	return nil
+}
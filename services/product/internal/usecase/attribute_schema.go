@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// Attribute validation error codes.
+const (
+	AttributeErrorRequired      = "required"
+	AttributeErrorUnknown       = "unknown_attribute"
+	AttributeErrorNotInCategory = "not_in_category"
+	AttributeErrorDuplicate     = "duplicate"
+	AttributeErrorEmpty         = "empty"
+	AttributeErrorTooManyValues = "too_many_values"
+	AttributeErrorInvalidType   = "invalid_type"
+	AttributeErrorInvalidOption = "invalid_option"
+	AttributeErrorInvalidUnit   = "invalid_unit"
+	AttributeErrorOutOfRange    = "out_of_range"
+)
+
+// AttributeValueError describes why one attribute value was rejected.
+type AttributeValueError struct {
+	AttributeID string `json:"attribute_id,omitempty"`
+	Slug        string `json:"slug,omitempty"`
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+}
+
+// AttributeValidationError is returned when product attribute values do not
+// match the attribute schema of the product's category.
+type AttributeValidationError struct {
+	Errors []AttributeValueError `json:"errors"`
+}
+
+func (e *AttributeValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		name := fe.Slug
+		if name == "" {
+			name = fe.AttributeID
+		}
+		msgs[i] = name + ": " + fe.Message
+	}
+	return "invalid attribute values: " + strings.Join(msgs, "; ")
+}
+
+// attributeSchema is the set of attributes products of a category carry:
+// those assigned to the category and to each of its ancestors.
+type attributeSchema struct {
+	defs []*domain.AttributeDefinition
+	byID map[string]*domain.AttributeDefinition
+}
+
+// loadAttributeSchema resolves the attribute schema of a category, walking
+// up its parents. A product without a category has an empty schema.
+func loadAttributeSchema(ctx context.Context, attributeRepo domain.AttributeRepository, categoryRepo domain.CategoryRepository, categoryID string) (*attributeSchema, error) {
+	schema := &attributeSchema{byID: make(map[string]*domain.AttributeDefinition)}
+
+	seen := make(map[string]bool)
+	for id := categoryID; id != "" && !seen[id]; {
+		seen[id] = true
+		defs, err := attributeRepo.ListByCategory(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list category attributes: %w", err)
+		}
+		// A category's own assignments come before the ones it inherits.
+		for _, d := range defs {
+			if _, ok := schema.byID[d.ID]; ok {
+				continue
+			}
+			schema.byID[d.ID] = d
+			schema.defs = append(schema.defs, d)
+		}
+
+		category, err := categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("category not found: %w", err)
+		}
+		id = category.ParentID
+	}
+	return schema, nil
+}
+
+// validate checks product attribute values against the schema and returns
+// them normalized: select values take the option's spelling, numbers drop
+// their unit and booleans are lower-cased. Every required attribute of the
+// schema must have a value.
+func (s *attributeSchema) validate(values []domain.ProductAttributeValue) ([]domain.ProductAttributeValue, error) {
+	var errs []AttributeValueError
+	fail := func(def *domain.AttributeDefinition, attributeID, code, msg string) {
+		e := AttributeValueError{AttributeID: attributeID, Code: code, Message: msg}
+		if def != nil {
+			e.Slug = def.Slug
+		}
+		errs = append(errs, e)
+	}
+
+	out := make([]domain.ProductAttributeValue, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		def, ok := s.byID[v.AttributeID]
+		if !ok {
+			fail(nil, v.AttributeID, AttributeErrorNotInCategory, "attribute is not part of the category's schema")
+			continue
+		}
+		if seen[v.AttributeID] {
+			fail(def, v.AttributeID, AttributeErrorDuplicate, "attribute is given more than once")
+			continue
+		}
+		seen[v.AttributeID] = true
+
+		raw := v.Values
+		if len(raw) == 0 && strings.TrimSpace(v.Value) != "" {
+			raw = []string{v.Value}
+		}
+		normalized, code, msg := normalizeAttributeValues(def, raw)
+		if code != "" {
+			fail(def, v.AttributeID, code, msg)
+			continue
+		}
+
+		value := v
+		value.AttributeName = def.Name
+		value.Value = ""
+		value.Values = nil
+		if def.Type == domain.AttributeTypeMultiSelect {
+			value.Values = normalized
+		} else {
+			value.Value = normalized[0]
+		}
+		if value.ID == "" {
+			value.ID = uuid.New().String()
+		}
+		out = append(out, value)
+	}
+
+	for _, def := range s.defs {
+		if def.Required && !seen[def.ID] {
+			fail(def, def.ID, AttributeErrorRequired, def.Name+" is required")
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &AttributeValidationError{Errors: errs}
+	}
+	return out, nil
+}
+
+// normalizeAttributeValues checks raw values against an attribute
+// definition. It returns the normalized values, or an error code and message.
+func normalizeAttributeValues(def *domain.AttributeDefinition, raw []string) ([]string, string, string) {
+	values := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return nil, AttributeErrorEmpty, "a value is required"
+	}
+	if def.Type != domain.AttributeTypeMultiSelect && len(values) > 1 {
+		return nil, AttributeErrorTooManyValues, "only one value is allowed"
+	}
+
+	switch def.Type {
+	case domain.AttributeTypeNumber:
+		n, code, msg := parseNumberWithUnit(values[0], def.Unit)
+		if code != "" {
+			return nil, code, msg
+		}
+		if def.MinValue != nil && n < *def.MinValue {
+			return nil, AttributeErrorOutOfRange, "must be at least " + formatWithUnit(*def.MinValue, def.Unit)
+		}
+		if def.MaxValue != nil && n > *def.MaxValue {
+			return nil, AttributeErrorOutOfRange, "must be at most " + formatWithUnit(*def.MaxValue, def.Unit)
+		}
+		return []string{strconv.FormatFloat(n, 'f', -1, 64)}, "", ""
+
+	case domain.AttributeTypeBool:
+		switch strings.ToLower(values[0]) {
+		case "true":
+			return []string{"true"}, "", ""
+		case "false":
+			return []string{"false"}, "", ""
+		}
+		return nil, AttributeErrorInvalidType, "must be true or false"
+
+	case domain.AttributeTypeSelect, domain.AttributeTypeMultiSelect, domain.AttributeTypeColor:
+		// Colors without a palette accept any value.
+		if def.Type == domain.AttributeTypeColor && len(def.Options) == 0 {
+			return values, "", ""
+		}
+		out := make([]string, 0, len(values))
+		picked := make(map[string]bool, len(values))
+		for _, v := range values {
+			option, ok := matchOption(def.Options, v)
+			if !ok {
+				return nil, AttributeErrorInvalidOption, fmt.Sprintf("%q is not one of [%s]", v, strings.Join(def.Options, ", "))
+			}
+			if !picked[option] {
+				picked[option] = true
+				out = append(out, option)
+			}
+		}
+		return out, "", ""
+	}
+
+	return values, "", ""
+}
+
+// parseNumberWithUnit parses values such as "12.5", "12.5cm" or "12.5 cm".
+// A unit, when given, must be the attribute's unit.
+func parseNumberWithUnit(value string, unit string) (float64, string, string) {
+	end := len(value)
+	for i, r := range value {
+		if !(unicode.IsDigit(r) || r == '.' || r == '-' || r == '+') {
+			end = i
+			break
+		}
+	}
+	number := value[:end]
+	suffix := strings.TrimSpace(value[end:])
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, AttributeErrorInvalidType, "must be a number"
+	}
+	if suffix != "" && !strings.EqualFold(suffix, unit) {
+		if unit == "" {
+			return 0, AttributeErrorInvalidUnit, "must be a plain number"
+		}
+		return 0, AttributeErrorInvalidUnit, fmt.Sprintf("unit must be %s", unit)
+	}
+	return n, "", ""
+}
+
+func formatWithUnit(n float64, unit string) string {
+	s := strconv.FormatFloat(n, 'f', -1, 64)
+	if unit != "" {
+		s += " " + unit
+	}
+	return s
+}
+
+// matchOption finds an option ignoring case and returns its spelling.
+func matchOption(options []string, value string) (string, bool) {
+	for _, o := range options {
+		if strings.EqualFold(o, value) {
+			return o, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,997 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+const (
+	// MaxImportBytes bounds the size of an uploaded catalog file.
+	MaxImportBytes = 10 << 20
+	// maxImportRows bounds the number of data rows processed by one job.
+	maxImportRows = 5000
+	// staleImportAfter is how long a job may stay running before another
+	// worker assumes its worker died and picks it up again.
+	staleImportAfter = 15 * time.Minute
+	// exportPageSize is the number of products read per page while exporting.
+	exportPageSize = 100
+)
+
+// CatalogUseCase handles bulk import and export of a seller's catalog.
+type CatalogUseCase struct {
+	productRepo   domain.ProductRepository
+	categoryRepo  domain.CategoryRepository
+	attributeRepo domain.AttributeRepository
+	optionRepo    domain.OptionRepository
+	variantRepo   domain.VariantRepository
+	importJobRepo domain.ImportJobRepository
+	transactor    domain.CatalogTransactor
+	moderationUC  *ModerationUseCase
+	pricingUC     *PricingUseCase
+	eventPub      domain.EventPublisher
+}
+
+// NewCatalogUseCase creates a new CatalogUseCase.
+func NewCatalogUseCase(
+	productRepo domain.ProductRepository,
+	categoryRepo domain.CategoryRepository,
+	attributeRepo domain.AttributeRepository,
+	optionRepo domain.OptionRepository,
+	variantRepo domain.VariantRepository,
+	importJobRepo domain.ImportJobRepository,
+	transactor domain.CatalogTransactor,
+	moderationUC *ModerationUseCase,
+	pricingUC *PricingUseCase,
+	eventPub domain.EventPublisher,
+) *CatalogUseCase {
+	return &CatalogUseCase{
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+		attributeRepo: attributeRepo,
+		optionRepo:    optionRepo,
+		variantRepo:   variantRepo,
+		importJobRepo: importJobRepo,
+		transactor:    transactor,
+		moderationUC:  moderationUC,
+		pricingUC:     pricingUC,
+		eventPub:      eventPub,
+	}
+}
+
+// SubmitImportInput holds the input for queueing a catalog import.
+type SubmitImportInput struct {
+	SellerID string
+	Format   domain.ImportFormat
+	DryRun   bool
+	Data     []byte
+}
+
+// SubmitImport queues a catalog file for import. The file is processed in
+// the background; poll the returned job for its report.
+func (uc *CatalogUseCase) SubmitImport(ctx context.Context, input SubmitImportInput) (*domain.ImportJob, error) {
+	if input.SellerID == "" {
+		return nil, fmt.Errorf("seller ID is required")
+	}
+	if input.Format != domain.ImportFormatCSV && input.Format != domain.ImportFormatJSON {
+		return nil, fmt.Errorf("format must be csv or json")
+	}
+	if len(input.Data) == 0 {
+		return nil, fmt.Errorf("import file is empty")
+	}
+	if len(input.Data) > MaxImportBytes {
+		return nil, fmt.Errorf("import file exceeds %d bytes", MaxImportBytes)
+	}
+
+	job := &domain.ImportJob{
+		ID:        uuid.New().String(),
+		SellerID:  input.SellerID,
+		Format:    input.Format,
+		DryRun:    input.DryRun,
+		Status:    domain.ImportJobStatusPending,
+		Payload:   input.Data,
+		RowErrors: []domain.ImportRowError{},
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := uc.importJobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+	return job, nil
+}
+
+// GetImportJob retrieves an import job owned by the seller.
+func (uc *CatalogUseCase) GetImportJob(ctx context.Context, id string, sellerID string) (*domain.ImportJob, error) {
+	job, err := uc.importJobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.SellerID != sellerID {
+		return nil, fmt.Errorf("import job not found")
+	}
+	return job, nil
+}
+
+// ListImportJobs lists a seller's import jobs, newest first.
+func (uc *CatalogUseCase) ListImportJobs(ctx context.Context, sellerID string, page, pageSize int) ([]*domain.ImportJob, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return uc.importJobRepo.ListBySeller(ctx, sellerID, page, pageSize)
+}
+
+// RunNextImport claims and processes the next queued import job. It reports
+// whether a job was run.
+func (uc *CatalogUseCase) RunNextImport(ctx context.Context) (bool, error) {
+	job, err := uc.importJobRepo.ClaimNext(ctx, time.Now().UTC().Add(-staleImportAfter))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim import job: %w", err)
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	uc.runImport(ctx, job)
+
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	if err := uc.importJobRepo.Update(ctx, job); err != nil {
+		return true, fmt.Errorf("failed to save import job %s: %w", job.ID, err)
+	}
+	return true, nil
+}
+
+// runImport validates every product of the job's file and, unless the job
+// is a dry run, writes the valid ones. A product with any invalid row is
+// skipped as a whole; the other products are still imported.
+func (uc *CatalogUseCase) runImport(ctx context.Context, job *domain.ImportJob) {
+	job.ProductsCreated, job.ProductsUpdated, job.VariantsCreated, job.VariantsUpdated = 0, 0, 0, 0
+
+	products, total, rowErrs, err := parseCatalogFile(job.Format, job.Payload)
+	job.TotalRows = total
+	if err == nil && total > maxImportRows {
+		err = fmt.Errorf("import file has %d rows, the limit is %d", total, maxImportRows)
+	}
+	if err != nil {
+		job.Status = domain.ImportJobStatusFailed
+		job.Error = err.Error()
+		job.RowErrors = []domain.ImportRowError{}
+		return
+	}
+
+	refs, err := uc.loadImportRefs(ctx)
+	if err != nil {
+		job.Status = domain.ImportJobStatusFailed
+		job.Error = err.Error()
+		job.RowErrors = []domain.ImportRowError{}
+		return
+	}
+
+	failed := make(map[int]bool)
+	for _, e := range rowErrs {
+		failed[e.Row] = true
+	}
+	markFailed := func(p *catalogProduct) {
+		failed[p.Row] = true
+		for _, v := range p.Variants {
+			failed[v.Row] = true
+		}
+	}
+
+	seenSKUs := make(map[string]int)
+	for i := range products {
+		p := &products[i]
+		if p.invalid {
+			markFailed(p)
+			continue
+		}
+		plan, errs := uc.planProduct(ctx, job.SellerID, p, refs, seenSKUs)
+		if len(errs) > 0 {
+			rowErrs = append(rowErrs, errs...)
+			markFailed(p)
+			continue
+		}
+
+		if !job.DryRun {
+			if err := uc.applyProduct(ctx, plan); err != nil {
+				rowErrs = append(rowErrs, domain.ImportRowError{Row: p.Row, Message: err.Error()})
+				markFailed(p)
+				continue
+			}
+		}
+
+		if plan.isNew {
+			job.ProductsCreated++
+		} else {
+			job.ProductsUpdated++
+		}
+		for _, v := range plan.variants {
+			if v.existing == nil {
+				job.VariantsCreated++
+			} else {
+				job.VariantsUpdated++
+			}
+		}
+	}
+
+	if rowErrs == nil {
+		rowErrs = []domain.ImportRowError{}
+	}
+	job.RowErrors = rowErrs
+	job.FailedRows = len(failed)
+	job.Status = domain.ImportJobStatusCompleted
+}
+
+// importRefs holds the reference data used to validate an import.
+type importRefs struct {
+	categories map[string]bool
+	attributes map[string]*domain.AttributeDefinition // by slug
+	schemas    map[string]*attributeSchema            // by category ID
+}
+
+// schema returns the attribute schema of a category, loading it once per import.
+func (r *importRefs) schema(ctx context.Context, uc *CatalogUseCase, categoryID string) (*attributeSchema, error) {
+	if schema, ok := r.schemas[categoryID]; ok {
+		return schema, nil
+	}
+	schema, err := loadAttributeSchema(ctx, uc.attributeRepo, uc.categoryRepo, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	r.schemas[categoryID] = schema
+	return schema, nil
+}
+
+func (uc *CatalogUseCase) loadImportRefs(ctx context.Context) (*importRefs, error) {
+	categories, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load categories: %w", err)
+	}
+	defs, err := uc.attributeRepo.ListDefinitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attribute definitions: %w", err)
+	}
+
+	refs := &importRefs{
+		categories: make(map[string]bool, len(categories)),
+		attributes: make(map[string]*domain.AttributeDefinition, len(defs)),
+		schemas:    make(map[string]*attributeSchema),
+	}
+	for _, c := range categories {
+		refs.categories[c.ID] = true
+	}
+	for _, d := range defs {
+		refs.attributes[d.Slug] = d
+	}
+	return refs, nil
+}
+
+// productPlan is a validated product ready to be written.
+type productPlan struct {
+	source     *catalogProduct
+	sellerID   string
+	product    *domain.Product
+	isNew      bool
+	options    []domain.ProductOption // existing options of the product
+	attributes []domain.ProductAttributeValue
+	variants   []variantPlan
+}
+
+// variantPlan is a validated variant; existing is nil for new SKUs.
+type variantPlan struct {
+	source   *catalogVariant
+	existing *domain.Variant
+}
+
+// planProduct resolves the product a file entry refers to and validates it.
+// Existing products are matched by product_id or, failing that, through the
+// SKUs of their variants.
+func (uc *CatalogUseCase) planProduct(ctx context.Context, sellerID string, p *catalogProduct, refs *importRefs, seenSKUs map[string]int) (*productPlan, []domain.ImportRowError) {
+	var errs []domain.ImportRowError
+	fail := func(row int, sku, field, msg string) {
+		errs = append(errs, domain.ImportRowError{Row: row, SKU: sku, Field: field, Message: msg})
+	}
+
+	plan := &productPlan{source: p, sellerID: sellerID}
+
+	// Resolve variants by SKU first; they may identify the product.
+	for i := range p.Variants {
+		v := &p.Variants[i]
+		if v.SKU == "" {
+			fail(v.Row, "", "sku", "sku is required")
+			continue
+		}
+		if row, dup := seenSKUs[v.SKU]; dup {
+			fail(v.Row, v.SKU, "sku", fmt.Sprintf("sku already used on row %d", row))
+			continue
+		}
+		seenSKUs[v.SKU] = v.Row
+
+		existing, err := uc.variantRepo.GetBySKU(ctx, v.SKU)
+		if err != nil {
+			fail(v.Row, v.SKU, "sku", fmt.Sprintf("failed to look up sku: %v", err))
+			continue
+		}
+		plan.variants = append(plan.variants, variantPlan{source: v, existing: existing})
+	}
+
+	productID := p.ProductID
+	for _, vp := range plan.variants {
+		if vp.existing == nil {
+			continue
+		}
+		if productID == "" {
+			productID = vp.existing.ProductID
+		} else if vp.existing.ProductID != productID {
+			fail(vp.source.Row, vp.source.SKU, "sku", "sku belongs to another product")
+		}
+	}
+
+	if productID != "" {
+		product, err := uc.productRepo.GetByID(ctx, productID)
+		if err != nil {
+			fail(p.Row, "", "product_id", "product not found")
+			return nil, errs
+		}
+		if product.SellerID != sellerID {
+			fail(p.Row, "", "product_id", "product belongs to another seller")
+			return nil, errs
+		}
+		plan.product = product
+		plan.options, err = uc.optionRepo.ListByProduct(ctx, productID)
+		if err != nil {
+			fail(p.Row, "", "", fmt.Sprintf("failed to load options: %v", err))
+			return nil, errs
+		}
+	} else {
+		plan.isNew = true
+		if p.Name == "" {
+			fail(p.Row, "", "name", "name is required for new products")
+		}
+		if p.BasePriceCents == nil {
+			fail(p.Row, "", "base_price_cents", "base_price_cents is required for new products")
+		}
+	}
+
+	if p.BasePriceCents != nil && *p.BasePriceCents < 0 {
+		fail(p.Row, "", "base_price_cents", "must be non-negative")
+	}
+	if p.CategoryID != "" && !refs.categories[p.CategoryID] {
+		fail(p.Row, "", "category_id", "category not found")
+	}
+	if p.Status != "" && !validProductStatus(domain.ProductStatus(p.Status)) {
+		fail(p.Row, "", "status", "must be one of draft, pending_review, active, rejected, inactive, archived")
+	}
+	if p.Currency != "" && len(p.Currency) != 3 {
+		fail(p.Row, "", "currency", "must be a 3-letter ISO code")
+	}
+
+	// Attributes are checked against the schema of the product's category
+	// whenever they are given, the category changes or the product is new.
+	categoryID := p.CategoryID
+	if categoryID == "" && plan.product != nil {
+		categoryID = plan.product.CategoryID
+	}
+	categoryChanged := plan.product != nil && categoryID != plan.product.CategoryID
+	if (p.Attributes != nil || plan.isNew || categoryChanged) && (categoryID == "" || refs.categories[categoryID]) {
+		var values []domain.ProductAttributeValue
+		if p.Attributes != nil || plan.isNew {
+			values = make([]domain.ProductAttributeValue, 0, len(p.Attributes))
+			for slug, raw := range p.Attributes {
+				def, ok := refs.attributes[slug]
+				if !ok {
+					fail(p.Row, "", "attributes", fmt.Sprintf("%s: unknown attribute", slug))
+					continue
+				}
+				values = append(values, domain.ProductAttributeValue{AttributeID: def.ID, Values: raw})
+			}
+		} else {
+			existing, err := uc.attributeRepo.GetProductValues(ctx, plan.product.ID)
+			if err != nil {
+				fail(p.Row, "", "", fmt.Sprintf("failed to load attributes: %v", err))
+				return nil, errs
+			}
+			values = existing
+		}
+
+		schema, err := refs.schema(ctx, uc, categoryID)
+		if err != nil {
+			fail(p.Row, "", "", err.Error())
+			return nil, errs
+		}
+		normalized, err := schema.validate(values)
+		var verr *AttributeValidationError
+		switch {
+		case errors.As(err, &verr):
+			for _, fe := range verr.Errors {
+				fail(p.Row, "", "attributes", fmt.Sprintf("%s: %s", fe.Slug, fe.Message))
+			}
+		case err != nil:
+			fail(p.Row, "", "attributes", err.Error())
+		default:
+			plan.attributes = normalized
+		}
+	}
+
+	errs = append(errs, checkVariantOptions(plan)...)
+	for _, vp := range plan.variants {
+		v := vp.source
+		for field, value := range map[string]*int64{
+			"price_cents":      v.PriceCents,
+			"compare_at_cents": v.CompareAtCents,
+			"cost_cents":       v.CostCents,
+		} {
+			if value != nil && *value < 0 {
+				fail(v.Row, v.SKU, field, "must be non-negative")
+			}
+		}
+		if v.Stock != nil && *v.Stock < 0 {
+			fail(v.Row, v.SKU, "stock", "must be non-negative")
+		}
+		if v.WeightGrams != nil && *v.WeightGrams < 0 {
+			fail(v.Row, v.SKU, "weight_grams", "must be non-negative")
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return plan, nil
+}
+
+// checkVariantOptions verifies that all variants of a product use the same
+// option names, matching the product's existing options if it has any, and
+// that no two variants share a combination of values.
+func checkVariantOptions(plan *productPlan) []domain.ImportRowError {
+	var errs []domain.ImportRowError
+	if len(plan.variants) == 0 {
+		return nil
+	}
+
+	expected := optionNames(plan.variants[0].source.Options)
+	if len(plan.options) > 0 {
+		expected = nil
+		for _, o := range plan.options {
+			expected = append(expected, o.Name)
+		}
+	}
+
+	seen := make(map[string]string)
+	for _, vp := range plan.variants {
+		v := vp.source
+		names := optionNames(v.Options)
+		if !sameNames(names, expected) {
+			errs = append(errs, domain.ImportRowError{
+				Row: v.Row, SKU: v.SKU, Field: "options",
+				Message: fmt.Sprintf("variant options must be [%s]", strings.Join(expected, ", ")),
+			})
+			continue
+		}
+		if len(v.Options) == 0 {
+			continue
+		}
+
+		combo := comboKey(v.Options)
+		if other, dup := seen[combo]; dup {
+			errs = append(errs, domain.ImportRowError{
+				Row: v.Row, SKU: v.SKU, Field: "options",
+				Message: fmt.Sprintf("same option values as sku %s", other),
+			})
+			continue
+		}
+		seen[combo] = v.SKU
+	}
+	return errs
+}
+
+// applyProduct writes a validated product, its options and its variants in
+// one transaction, so that a product that fails to import is left as it was.
+func (uc *CatalogUseCase) applyProduct(ctx context.Context, plan *productPlan) error {
+	events := &eventQueue{}
+	if err := uc.transactor.Transaction(ctx, func(repos domain.CatalogRepositories) error {
+		return uc.inTransaction(repos, events).writeProduct(ctx, plan)
+	}); err != nil {
+		return err
+	}
+	events.flush(ctx, uc.eventPub)
+	return nil
+}
+
+// inTransaction returns a copy of the use case that writes through repos
+// and publishes its events to events.
+func (uc *CatalogUseCase) inTransaction(repos domain.CatalogRepositories, events domain.EventPublisher) *CatalogUseCase {
+	tx := *uc
+	tx.productRepo = repos.Products
+	tx.categoryRepo = repos.Categories
+	tx.attributeRepo = repos.Attributes
+	tx.optionRepo = repos.Options
+	tx.variantRepo = repos.Variants
+	tx.moderationUC = uc.moderationUC.inTransaction(repos, events)
+	tx.pricingUC = uc.pricingUC.inTransaction(repos, events)
+	tx.eventPub = events
+	return &tx
+}
+
+// writeProduct writes a validated product, its options and its variants.
+func (uc *CatalogUseCase) writeProduct(ctx context.Context, plan *productPlan) error {
+	p := plan.source
+	now := time.Now().UTC()
+
+	product := plan.product
+	if plan.isNew {
+		product = &domain.Product{
+			ID:        uuid.New().String(),
+			SellerID:  plan.sellerID,
+			Status:    domain.ProductStatusDraft,
+			Currency:  "USD",
+			CreatedAt: now,
+		}
+	}
+	applyProductFields(product, p)
+	product.UpdatedAt = now
+	if len(plan.options) > 0 || (len(plan.variants) > 0 && len(plan.variants[0].source.Options) > 0) {
+		product.HasVariants = true
+	}
+
+	if plan.isNew {
+		if err := uc.productRepo.Create(ctx, product); err != nil {
+			return fmt.Errorf("failed to create product: %w", err)
+		}
+	} else {
+		if err := uc.productRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+	}
+
+	if plan.attributes != nil {
+		for i := range plan.attributes {
+			plan.attributes[i].ProductID = product.ID
+		}
+		if err := uc.attributeRepo.SetProductValues(ctx, product.ID, plan.attributes); err != nil {
+			return fmt.Errorf("failed to set attribute values: %w", err)
+		}
+	}
+
+	// Moderation looks at the saved attribute values, so the product status
+	// is settled once they are in place.
+	status := product.Status
+	activate := p.Status == string(domain.ProductStatusActive) || p.Status == string(domain.ProductStatusPendingReview)
+	if err := uc.moderationUC.Reconcile(ctx, product, activate); err != nil {
+		return err
+	}
+	if product.Status != status {
+		if err := uc.productRepo.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product status: %w", err)
+		}
+	}
+
+	options, err := uc.ensureOptions(ctx, product.ID, plan)
+	if err != nil {
+		return err
+	}
+
+	hasDefault := false
+	for _, vp := range plan.variants {
+		if vp.existing != nil && vp.existing.IsDefault {
+			hasDefault = true
+		}
+	}
+	if !plan.isNew && !hasDefault {
+		existing, err := uc.variantRepo.ListByProduct(ctx, product.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list variants: %w", err)
+		}
+		hasDefault = len(existing) > 0
+	}
+
+	for _, vp := range plan.variants {
+		optionValues := resolveOptionValues(options, vp.source.Options)
+		if vp.existing == nil {
+			variant := newImportedVariant(product, vp.source, now)
+			variant.IsDefault = !hasDefault
+			hasDefault = true
+			for i := range optionValues {
+				optionValues[i].VariantID = variant.ID
+			}
+			variant.OptionValues = optionValues
+			if err := uc.variantRepo.Create(ctx, variant); err != nil {
+				return fmt.Errorf("failed to create variant %s: %w", variant.SKU, err)
+			}
+			continue
+		}
+
+		if err := uc.updateImportedVariant(ctx, product, vp, optionValues, now); err != nil {
+			return err
+		}
+	}
+
+	if plan.isNew {
+		_ = uc.eventPub.PublishProductCreated(ctx, product)
+	} else {
+		_ = uc.eventPub.PublishProductUpdated(ctx, product)
+	}
+	return nil
+}
+
+// applyProductFields copies the non-empty fields of a file entry onto a
+// product.
+func applyProductFields(product *domain.Product, p *catalogProduct) {
+	if p.Name != "" && p.Name != product.Name {
+		product.Name = p.Name
+		product.Slug = generateSlug(p.Name)
+	}
+	if p.Description != "" {
+		product.Description = p.Description
+	}
+	if p.CategoryID != "" {
+		product.CategoryID = p.CategoryID
+	}
+	if p.BasePriceCents != nil {
+		product.BasePriceCents = *p.BasePriceCents
+	}
+	if p.Currency != "" {
+		product.Currency = strings.ToUpper(p.Currency)
+	}
+	// Activation goes through moderation, see applyProduct, and only
+	// moderation rejects, so those statuses are not copied. Exported files
+	// carry them, so they are still accepted on re-import.
+	switch status := domain.ProductStatus(p.Status); status {
+	case domain.ProductStatusDraft, domain.ProductStatusInactive, domain.ProductStatusArchived:
+		product.Status = status
+	}
+	if p.Tags != nil {
+		product.Tags = p.Tags
+	}
+	if p.ImageURLs != nil {
+		product.ImageURLs = p.ImageURLs
+	}
+}
+
+// ensureOptions creates the options and option values the imported variants
+// use but the product does not have yet, and returns the full option set.
+func (uc *CatalogUseCase) ensureOptions(ctx context.Context, productID string, plan *productPlan) ([]domain.ProductOption, error) {
+	options := append([]domain.ProductOption(nil), plan.options...)
+
+	for _, vp := range plan.variants {
+		for i, o := range vp.source.Options {
+			idx := findOption(options, o.Name)
+			if idx < 0 {
+				option := domain.ProductOption{
+					ID:        uuid.New().String(),
+					ProductID: productID,
+					Name:      o.Name,
+					SortOrder: i,
+				}
+				if err := uc.optionRepo.CreateOption(ctx, &option); err != nil {
+					return nil, fmt.Errorf("failed to create option %s: %w", o.Name, err)
+				}
+				options = append(options, option)
+				idx = len(options) - 1
+			}
+
+			if findOptionValue(options[idx], o.Value) >= 0 {
+				continue
+			}
+			value := domain.ProductOptionValue{
+				ID:        uuid.New().String(),
+				OptionID:  options[idx].ID,
+				Value:     o.Value,
+				SortOrder: len(options[idx].Values),
+			}
+			if err := uc.optionRepo.CreateOptionValue(ctx, &value); err != nil {
+				return nil, fmt.Errorf("failed to create option value %s: %w", o.Value, err)
+			}
+			options[idx].Values = append(options[idx].Values, value)
+		}
+	}
+	return options, nil
+}
+
+func newImportedVariant(product *domain.Product, v *catalogVariant, now time.Time) *domain.Variant {
+	variant := &domain.Variant{
+		ID:         uuid.New().String(),
+		ProductID:  product.ID,
+		SKU:        v.SKU,
+		Name:       v.Name,
+		PriceCents: product.BasePriceCents,
+		IsActive:   true,
+		Barcode:    v.Barcode,
+		ImageURLs:  v.ImageURLs,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if variant.Name == "" {
+		var values []string
+		for _, o := range v.Options {
+			values = append(values, o.Value)
+		}
+		variant.Name = joinNames(values)
+	}
+	if v.PriceCents != nil {
+		variant.PriceCents = *v.PriceCents
+	}
+	if v.CompareAtCents != nil {
+		variant.CompareAtCents = *v.CompareAtCents
+	}
+	if v.CostCents != nil {
+		variant.CostCents = *v.CostCents
+	}
+	if v.Stock != nil {
+		variant.Stock = *v.Stock
+	}
+	if v.WeightGrams != nil {
+		variant.WeightGrams = *v.WeightGrams
+	}
+	if v.IsActive != nil {
+		variant.IsActive = *v.IsActive
+	}
+	return variant
+}
+
+// updateImportedVariant applies a file entry to an existing variant. Stock is
+// set through an atomic adjustment so that concurrent sales are not lost.
+func (uc *CatalogUseCase) updateImportedVariant(ctx context.Context, product *domain.Product, vp variantPlan, optionValues []domain.VariantOptionValue, now time.Time) error {
+	variant := vp.existing
+	v := vp.source
+	previousPrice, previousCompareAt := variant.PriceCents, variant.CompareAtCents
+
+	if v.Name != "" {
+		variant.Name = v.Name
+	}
+	if v.PriceCents != nil {
+		variant.PriceCents = *v.PriceCents
+	}
+	if v.CompareAtCents != nil {
+		variant.CompareAtCents = *v.CompareAtCents
+	}
+	if v.CostCents != nil {
+		variant.CostCents = *v.CostCents
+	}
+	if v.WeightGrams != nil {
+		variant.WeightGrams = *v.WeightGrams
+	}
+	if v.Barcode != "" {
+		variant.Barcode = v.Barcode
+	}
+	if v.ImageURLs != nil {
+		variant.ImageURLs = v.ImageURLs
+	}
+	if v.IsActive != nil {
+		variant.IsActive = *v.IsActive
+	}
+	variant.UpdatedAt = now
+
+	if err := uc.variantRepo.Update(ctx, variant); err != nil {
+		return fmt.Errorf("failed to update variant %s: %w", variant.SKU, err)
+	}
+	if err := uc.pricingUC.recordPriceChange(ctx, product, variant, previousPrice, previousCompareAt, domain.PriceChangeSourceImport, ""); err != nil {
+		return err
+	}
+
+	if len(optionValues) > 0 {
+		if err := uc.variantRepo.SetOptionValues(ctx, variant.ID, optionValues); err != nil {
+			return fmt.Errorf("failed to set option values of %s: %w", variant.SKU, err)
+		}
+	}
+
+	if v.Stock != nil && *v.Stock != variant.Stock {
+		delta := *v.Stock - variant.Stock
+		if err := uc.variantRepo.UpdateStock(ctx, variant.ID, delta); err != nil {
+			return fmt.Errorf("failed to update stock of %s: %w", variant.SKU, err)
+		}
+		_ = uc.eventPub.PublishStockUpdated(ctx, variant.ID, *v.Stock, delta)
+	}
+	return nil
+}
+
+// ExportCatalog writes all of a seller's products, with their attributes,
+// options and variants, in the given format. The output can be imported back.
+func (uc *CatalogUseCase) ExportCatalog(ctx context.Context, sellerID string, format domain.ImportFormat, w io.Writer) error {
+	writer, err := newCatalogWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	defs, err := uc.attributeRepo.ListDefinitions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load attribute definitions: %w", err)
+	}
+	slugs := make(map[string]string, len(defs))
+	for _, d := range defs {
+		slugs[d.ID] = d.Slug
+	}
+
+	filter := domain.ProductFilter{SellerID: sellerID, SortBy: "newest", PageSize: exportPageSize}
+	for page := 1; ; page++ {
+		filter.Page = page
+		products, total, err := uc.productRepo.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list products: %w", err)
+		}
+
+		for _, product := range products {
+			entry, err := uc.exportProduct(ctx, product, slugs)
+			if err != nil {
+				return err
+			}
+			if err := writer.Write(entry); err != nil {
+				return err
+			}
+		}
+
+		if int64(page*exportPageSize) >= total || len(products) == 0 {
+			break
+		}
+	}
+
+	return writer.Close()
+}
+
+func (uc *CatalogUseCase) exportProduct(ctx context.Context, product *domain.Product, slugs map[string]string) (catalogProduct, error) {
+	basePrice := product.BasePriceCents
+	entry := catalogProduct{
+		ProductID:      product.ID,
+		Name:           product.Name,
+		Description:    product.Description,
+		CategoryID:     product.CategoryID,
+		BasePriceCents: &basePrice,
+		Currency:       product.Currency,
+		Status:         string(product.Status),
+		Tags:           product.Tags,
+		ImageURLs:      product.ImageURLs,
+	}
+
+	values, err := uc.attributeRepo.GetProductValues(ctx, product.ID)
+	if err != nil {
+		return entry, fmt.Errorf("failed to load attributes of %s: %w", product.ID, err)
+	}
+	for _, v := range values {
+		slug, ok := slugs[v.AttributeID]
+		if !ok {
+			continue
+		}
+		if entry.Attributes == nil {
+			entry.Attributes = make(map[string]attributeValues)
+		}
+		if len(v.Values) > 0 {
+			entry.Attributes[slug] = v.Values
+		} else {
+			entry.Attributes[slug] = attributeValues{v.Value}
+		}
+	}
+
+	variants, err := uc.variantRepo.ListByProduct(ctx, product.ID)
+	if err != nil {
+		return entry, fmt.Errorf("failed to load variants of %s: %w", product.ID, err)
+	}
+	for i := range variants {
+		v := variants[i]
+		cv := catalogVariant{
+			SKU:            v.SKU,
+			Name:           v.Name,
+			PriceCents:     &v.PriceCents,
+			CompareAtCents: &v.CompareAtCents,
+			CostCents:      &v.CostCents,
+			Stock:          &v.Stock,
+			WeightGrams:    &v.WeightGrams,
+			Barcode:        v.Barcode,
+			ImageURLs:      v.ImageURLs,
+			IsActive:       &v.IsActive,
+		}
+		for _, ov := range v.OptionValues {
+			cv.Options = append(cv.Options, catalogOption{Name: ov.OptionName, Value: ov.Value})
+		}
+		entry.Variants = append(entry.Variants, cv)
+	}
+	return entry, nil
+}
+
+func validProductStatus(status domain.ProductStatus) bool {
+	switch status {
+	case domain.ProductStatusDraft, domain.ProductStatusPendingReview, domain.ProductStatusActive,
+		domain.ProductStatusRejected, domain.ProductStatusInactive, domain.ProductStatusArchived:
+		return true
+	}
+	return false
+}
+
+func optionNames(options []catalogOption) []string {
+	names := make([]string, 0, len(options))
+	for _, o := range options {
+		names = append(names, o.Name)
+	}
+	return names
+}
+
+// sameNames reports whether a and b hold the same names, in any order and
+// ignoring case.
+func sameNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, name := range a {
+		found := false
+		for _, other := range b {
+			if strings.EqualFold(name, other) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func comboKey(options []catalogOption) string {
+	parts := make([]string, 0, len(options))
+	for _, o := range options {
+		parts = append(parts, o.Name+"="+o.Value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ";")
+}
+
+func findOption(options []domain.ProductOption, name string) int {
+	for i, o := range options {
+		if strings.EqualFold(o.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func findOptionValue(option domain.ProductOption, value string) int {
+	for i, v := range option.Values {
+		if strings.EqualFold(v.Value, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolveOptionValues maps a variant's option values to the product's option
+// and option value IDs.
+func resolveOptionValues(options []domain.ProductOption, values []catalogOption) []domain.VariantOptionValue {
+	var resolved []domain.VariantOptionValue
+	for _, o := range values {
+		idx := findOption(options, o.Name)
+		if idx < 0 {
+			continue
+		}
+		vIdx := findOptionValue(options[idx], o.Value)
+		if vIdx < 0 {
+			continue
+		}
+		resolved = append(resolved, domain.VariantOptionValue{
+			OptionID:      options[idx].ID,
+			OptionValueID: options[idx].Values[vIdx].ID,
+			OptionName:    options[idx].Name,
+			Value:         options[idx].Values[vIdx].Value,
+		})
+	}
+	return resolved
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,469 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// catalogProduct is a product as it appears in an import or export file.
+// On import, empty fields leave the existing value of a product unchanged.
+type catalogProduct struct {
+	Row            int                        `json:"-"`
+	ProductID      string                     `json:"product_id,omitempty"`
+	Name           string                     `json:"name"`
+	Description    string                     `json:"description,omitempty"`
+	CategoryID     string                     `json:"category_id,omitempty"`
+	BasePriceCents *int64                     `json:"base_price_cents,omitempty"`
+	Currency       string                     `json:"currency,omitempty"`
+	Status         string                     `json:"status,omitempty"`
+	Tags           []string                   `json:"tags,omitempty"`
+	ImageURLs      []string                   `json:"image_urls,omitempty"`
+	Attributes     map[string]attributeValues `json:"attributes,omitempty"`
+	Variants       []catalogVariant           `json:"variants,omitempty"`
+
+	// invalid is set when one of the product's rows could not be decoded.
+	invalid bool
+}
+
+// catalogVariant is a variant as it appears in an import or export file.
+type catalogVariant struct {
+	Row            int             `json:"-"`
+	SKU            string          `json:"sku"`
+	Name           string          `json:"name,omitempty"`
+	Options        []catalogOption `json:"options,omitempty"`
+	PriceCents     *int64          `json:"price_cents,omitempty"`
+	CompareAtCents *int64          `json:"compare_at_cents,omitempty"`
+	CostCents      *int64          `json:"cost_cents,omitempty"`
+	Stock          *int            `json:"stock,omitempty"`
+	WeightGrams    *int            `json:"weight_grams,omitempty"`
+	Barcode        string          `json:"barcode,omitempty"`
+	ImageURLs      []string        `json:"image_urls,omitempty"`
+	IsActive       *bool           `json:"is_active,omitempty"`
+}
+
+// catalogOption is the value a variant takes for one product option.
+type catalogOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// attributeValues holds the values of one attribute. In JSON files it may be
+// written as a single string or as a list.
+type attributeValues []string
+
+// UnmarshalJSON accepts either a string or a list of strings.
+func (a *attributeValues) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = attributeValues{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("attribute value must be a string or a list of strings")
+	}
+	*a = list
+	return nil
+}
+
+// maxCatalogOptions is the number of option columns in the CSV layout.
+const maxCatalogOptions = 3
+
+// catalogCSVColumns is the CSV layout: one row per variant, with the product
+// columns repeated (or left blank) on every row of the same product.
+var catalogCSVColumns = []string{
+	"product_id", "name", "description", "category_id", "base_price_cents", "currency", "status",
+	"tags", "image_urls", "attributes",
+	"sku", "variant_name",
+	"option1_name", "option1_value", "option2_name", "option2_value", "option3_name", "option3_value",
+	"price_cents", "compare_at_cents", "cost_cents", "stock", "weight_grams", "barcode",
+	"variant_image_urls", "is_active",
+}
+
+// parseCatalogFile decodes an import file into products. It returns the
+// number of data rows, the rows that could not be decoded, and an error when
+// the file as a whole is unreadable. Products with undecodable rows are
+// returned marked invalid. Rows are numbered from 1; in CSV files
+// the header is row 1, in JSON files each product is one row.
+func parseCatalogFile(format domain.ImportFormat, data []byte) ([]catalogProduct, int, []domain.ImportRowError, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		return parseCatalogCSV(data)
+	case domain.ImportFormatJSON:
+		return parseCatalogJSON(data)
+	default:
+		return nil, 0, nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+func parseCatalogJSON(data []byte) ([]catalogProduct, int, []domain.ImportRowError, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid JSON: expected an array of products: %w", err)
+	}
+
+	var products []catalogProduct
+	var rowErrs []domain.ImportRowError
+	for i, item := range raw {
+		var p catalogProduct
+		if err := json.Unmarshal(item, &p); err != nil {
+			rowErrs = append(rowErrs, domain.ImportRowError{Row: i + 1, Message: err.Error()})
+			continue
+		}
+		p.Row = i + 1
+		for j := range p.Variants {
+			p.Variants[j].Row = i + 1
+		}
+		products = append(products, p)
+	}
+	return products, len(raw), rowErrs, nil
+}
+
+func parseCatalogCSV(data []byte) ([]catalogProduct, int, []domain.ImportRowError, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("invalid CSV: missing header row: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	known := make(map[string]bool, len(catalogCSVColumns))
+	for _, col := range catalogCSVColumns {
+		known[col] = true
+	}
+	for i, col := range header {
+		col = strings.ToLower(strings.TrimSpace(col))
+		if !known[col] {
+			return nil, 0, nil, fmt.Errorf("invalid CSV: unknown column %q", col)
+		}
+		columns[col] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		if _, ok := columns["product_id"]; !ok {
+			return nil, 0, nil, fmt.Errorf("invalid CSV: a name or product_id column is required")
+		}
+	}
+
+	// Rows are grouped into products by product_id, or by name for new
+	// products, in order of first appearance.
+	var products []catalogProduct
+	index := make(map[string]int)
+	var rowErrs []domain.ImportRowError
+	total := 0
+	for rowNum := 2; ; rowNum++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		total++
+		if err != nil {
+			rowErrs = append(rowErrs, domain.ImportRowError{Row: rowNum, Message: err.Error()})
+			continue
+		}
+
+		row := csvRow{record: record, columns: columns}
+		product, variant, errs := row.decode(rowNum)
+		if product.ProductID == "" && product.Name == "" {
+			rowErrs = append(rowErrs, errs...)
+			continue
+		}
+
+		key := "id:" + product.ProductID
+		if product.ProductID == "" {
+			key = "name:" + product.Name
+		}
+		i, ok := index[key]
+		if !ok {
+			product.Row = rowNum
+			products = append(products, product)
+			i = len(products) - 1
+			index[key] = i
+		}
+		if len(errs) > 0 {
+			rowErrs = append(rowErrs, errs...)
+			products[i].invalid = true
+			continue
+		}
+		if variant != nil {
+			products[i].Variants = append(products[i].Variants, *variant)
+		}
+	}
+	return products, total, rowErrs, nil
+}
+
+// csvRow reads cells of a CSV record by column name.
+type csvRow struct {
+	record  []string
+	columns map[string]int
+}
+
+func (r csvRow) get(col string) string {
+	i, ok := r.columns[col]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r csvRow) decode(rowNum int) (catalogProduct, *catalogVariant, []domain.ImportRowError) {
+	var errs []domain.ImportRowError
+	fail := func(field, sku, msg string) {
+		errs = append(errs, domain.ImportRowError{Row: rowNum, SKU: sku, Field: field, Message: msg})
+	}
+
+	product := catalogProduct{
+		ProductID:   r.get("product_id"),
+		Name:        r.get("name"),
+		Description: r.get("description"),
+		CategoryID:  r.get("category_id"),
+		Currency:    r.get("currency"),
+		Status:      r.get("status"),
+		Tags:        splitList(r.get("tags")),
+		ImageURLs:   splitList(r.get("image_urls")),
+	}
+	if product.ProductID == "" && product.Name == "" {
+		fail("name", "", "name or product_id is required")
+	}
+	product.BasePriceCents = r.int64Cell("base_price_cents", "", fail)
+	if raw := r.get("attributes"); raw != "" {
+		attrs, err := parseAttributeCell(raw)
+		if err != nil {
+			fail("attributes", "", err.Error())
+		}
+		product.Attributes = attrs
+	}
+
+	sku := r.get("sku")
+	if sku == "" {
+		return product, nil, errs
+	}
+
+	variant := &catalogVariant{
+		Row:       rowNum,
+		SKU:       sku,
+		Name:      r.get("variant_name"),
+		Barcode:   r.get("barcode"),
+		ImageURLs: splitList(r.get("variant_image_urls")),
+	}
+	for i := 1; i <= maxCatalogOptions; i++ {
+		name := r.get(fmt.Sprintf("option%d_name", i))
+		value := r.get(fmt.Sprintf("option%d_value", i))
+		if name == "" && value == "" {
+			continue
+		}
+		if name == "" || value == "" {
+			fail(fmt.Sprintf("option%d", i), sku, "option name and value must both be set")
+			continue
+		}
+		variant.Options = append(variant.Options, catalogOption{Name: name, Value: value})
+	}
+	variant.PriceCents = r.int64Cell("price_cents", sku, fail)
+	variant.CompareAtCents = r.int64Cell("compare_at_cents", sku, fail)
+	variant.CostCents = r.int64Cell("cost_cents", sku, fail)
+	variant.Stock = r.intCell("stock", sku, fail)
+	variant.WeightGrams = r.intCell("weight_grams", sku, fail)
+	if raw := r.get("is_active"); raw != "" {
+		active, err := strconv.ParseBool(raw)
+		if err != nil {
+			fail("is_active", sku, "must be true or false")
+		} else {
+			variant.IsActive = &active
+		}
+	}
+
+	return product, variant, errs
+}
+
+func (r csvRow) int64Cell(col, sku string, fail func(field, sku, msg string)) *int64 {
+	raw := r.get(col)
+	if raw == "" {
+		return nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		fail(col, sku, "must be a whole number")
+		return nil
+	}
+	return &v
+}
+
+func (r csvRow) intCell(col, sku string, fail func(field, sku, msg string)) *int {
+	raw := r.get(col)
+	if raw == "" {
+		return nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		fail(col, sku, "must be a whole number")
+		return nil
+	}
+	return &v
+}
+
+// splitList splits a "|"-separated cell.
+func splitList(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(raw, "|") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseAttributeCell parses "slug=value;slug=value1|value2".
+func parseAttributeCell(raw string) (map[string]attributeValues, error) {
+	attrs := make(map[string]attributeValues)
+	for _, pair := range strings.Split(raw, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		slug, value, ok := strings.Cut(pair, "=")
+		slug = strings.TrimSpace(slug)
+		if !ok || slug == "" {
+			return nil, fmt.Errorf("attributes must be written as slug=value;slug=value1|value2")
+		}
+		attrs[slug] = splitList(value)
+	}
+	return attrs, nil
+}
+
+// catalogWriter writes exported products as they are produced.
+type catalogWriter interface {
+	Write(p catalogProduct) error
+	Close() error
+}
+
+func newCatalogWriter(format domain.ImportFormat, w io.Writer) (catalogWriter, error) {
+	switch format {
+	case domain.ImportFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(catalogCSVColumns); err != nil {
+			return nil, err
+		}
+		return &csvCatalogWriter{w: cw}, nil
+	case domain.ImportFormatJSON:
+		return &jsonCatalogWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type csvCatalogWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvCatalogWriter) Write(p catalogProduct) error {
+	productCells := []string{
+		p.ProductID, p.Name, p.Description, p.CategoryID, formatInt64(p.BasePriceCents), p.Currency, p.Status,
+		strings.Join(p.Tags, "|"), strings.Join(p.ImageURLs, "|"), formatAttributeCell(p.Attributes),
+	}
+
+	if len(p.Variants) == 0 {
+		record := append(productCells, make([]string, len(catalogCSVColumns)-len(productCells))...)
+		return c.w.Write(record)
+	}
+
+	for _, v := range p.Variants {
+		record := append([]string{}, productCells...)
+		record = append(record, v.SKU, v.Name)
+		for i := 0; i < maxCatalogOptions; i++ {
+			if i < len(v.Options) {
+				record = append(record, v.Options[i].Name, v.Options[i].Value)
+			} else {
+				record = append(record, "", "")
+			}
+		}
+		active := ""
+		if v.IsActive != nil {
+			active = strconv.FormatBool(*v.IsActive)
+		}
+		record = append(record,
+			formatInt64(v.PriceCents), formatInt64(v.CompareAtCents), formatInt64(v.CostCents),
+			formatInt(v.Stock), formatInt(v.WeightGrams), v.Barcode,
+			strings.Join(v.ImageURLs, "|"), active,
+		)
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvCatalogWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonCatalogWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (j *jsonCatalogWriter) Write(p catalogProduct) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if j.written == 0 {
+		sep = "[\n"
+	}
+	j.written++
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonCatalogWriter) Close() error {
+	end := "\n]\n"
+	if j.written == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+func formatInt64(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
+
+func formatInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func formatAttributeCell(attrs map[string]attributeValues) string {
+	slugs := make([]string, 0, len(attrs))
+	for slug := range attrs {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	pairs := make([]string, 0, len(slugs))
+	for _, slug := range slugs {
+		pairs = append(pairs, slug+"="+strings.Join(attrs[slug], "|"))
+	}
+	return strings.Join(pairs, ";")
+}
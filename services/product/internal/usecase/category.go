@@ -13,11 +13,17 @@ import (
 // CategoryUseCase handles category business logic.
 type CategoryUseCase struct {
 	categoryRepo domain.CategoryRepository
+	productRepo  domain.ProductRepository
+	feedUC       *FeedUseCase
 }
 
 // NewCategoryUseCase creates a new CategoryUseCase.
-func NewCategoryUseCase(categoryRepo domain.CategoryRepository) *CategoryUseCase {
-	return &CategoryUseCase{categoryRepo: categoryRepo}
+func NewCategoryUseCase(categoryRepo domain.CategoryRepository, productRepo domain.ProductRepository, feedUC *FeedUseCase) *CategoryUseCase {
+	return &CategoryUseCase{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		feedUC:       feedUC,
+	}
 }
 
 // CreateCategoryInput holds the input for creating a category.
@@ -64,3 +70,196 @@ func (uc *CategoryUseCase) CreateCategory(ctx context.Context, input CreateCateg
 func (uc *CategoryUseCase) GetCategories(ctx context.Context) ([]*domain.Category, error) {
 	return uc.categoryRepo.List(ctx)
 }
+
+// GetCategoryTree returns the active categories as a tree ordered by sort
+// order, with counts of active products. With a rootID only the subtree of
+// that category is returned.
+func (uc *CategoryUseCase) GetCategoryTree(ctx context.Context, rootID string) ([]*domain.CategoryNode, error) {
+	categories, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	counts, err := uc.productRepo.CountByCategory(ctx, string(domain.ProductStatusActive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count products: %w", err)
+	}
+
+	nodes := make(map[string]*domain.CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &domain.CategoryNode{
+			Category:     c,
+			ProductCount: counts[c.ID],
+			Children:     []*domain.CategoryNode{},
+		}
+	}
+
+	// Categories whose parent is inactive or missing are shown as roots.
+	var roots []*domain.CategoryNode
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if parent, ok := nodes[c.ParentID]; ok && c.ParentID != c.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	for _, root := range roots {
+		sumSubtreeCounts(root)
+	}
+
+	if rootID == "" {
+		return roots, nil
+	}
+	root, ok := nodes[rootID]
+	if !ok {
+		return nil, fmt.Errorf("category not found: %s", rootID)
+	}
+	return []*domain.CategoryNode{root}, nil
+}
+
+// GetCategoryPath returns the breadcrumb of a category: its ancestors from
+// the root down, followed by the category itself.
+func (uc *CategoryUseCase) GetCategoryPath(ctx context.Context, id string) ([]*domain.Category, error) {
+	var path []*domain.Category
+	seen := make(map[string]bool)
+	for current := id; current != "" && !seen[current]; {
+		seen[current] = true
+		category, err := uc.categoryRepo.GetByID(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("category not found: %w", err)
+		}
+		path = append(path, category)
+		current = category.ParentID
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// MoveCategoryInput holds the input for moving a category.
+type MoveCategoryInput struct {
+	ParentID  string // empty moves the category to the top level
+	SortOrder *int   // nil places it after its new siblings
+}
+
+// MoveCategory moves a category, with its subtree, under another parent. A
+// category cannot be moved under itself or one of its descendants.
+func (uc *CategoryUseCase) MoveCategory(ctx context.Context, id string, input MoveCategoryInput) (*domain.Category, error) {
+	category, err := uc.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("category not found: %w", err)
+	}
+
+	// Walking up from the new parent must not reach the category itself.
+	seen := make(map[string]bool)
+	for current := input.ParentID; current != "" && !seen[current]; {
+		if current == id {
+			return nil, fmt.Errorf("cannot move a category under itself or one of its descendants")
+		}
+		seen[current] = true
+		parent, err := uc.categoryRepo.GetByID(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("parent category not found: %w", err)
+		}
+		current = parent.ParentID
+	}
+
+	if input.SortOrder != nil {
+		category.SortOrder = *input.SortOrder
+	} else if category.ParentID != input.ParentID {
+		categories, err := uc.categoryRepo.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list categories: %w", err)
+		}
+		category.SortOrder = 0
+		for _, c := range categories {
+			if c.ParentID == input.ParentID && c.ID != id && c.SortOrder >= category.SortOrder {
+				category.SortOrder = c.SortOrder + 1
+			}
+		}
+	}
+	category.ParentID = input.ParentID
+
+	if err := uc.categoryRepo.Update(ctx, category); err != nil {
+		return nil, fmt.Errorf("failed to move category: %w", err)
+	}
+
+	// Feed items carry the category path and inherited Google category; the
+	// periodic feed rebuild catches up if this refresh fails.
+	_ = uc.feedUC.refreshCategory(ctx, id)
+
+	return category, nil
+}
+
+// ReorderCategories sets the order of the active children of a parent, or of
+// the top-level categories when parentID is empty. categoryIDs must list
+// every one of them exactly once.
+func (uc *CategoryUseCase) ReorderCategories(ctx context.Context, parentID string, categoryIDs []string) error {
+	if parentID != "" {
+		if _, err := uc.categoryRepo.GetByID(ctx, parentID); err != nil {
+			return fmt.Errorf("parent category not found: %w", err)
+		}
+	}
+
+	categories, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list categories: %w", err)
+	}
+	siblings := make(map[string]bool)
+	for _, c := range categories {
+		if c.ParentID == parentID {
+			siblings[c.ID] = true
+		}
+	}
+
+	sortOrders := make(map[string]int, len(categoryIDs))
+	for i, id := range categoryIDs {
+		if !siblings[id] {
+			return fmt.Errorf("category %s is not a child of the parent", id)
+		}
+		if _, ok := sortOrders[id]; ok {
+			return fmt.Errorf("category %s is listed more than once", id)
+		}
+		sortOrders[id] = i
+	}
+	if len(sortOrders) != len(siblings) {
+		return fmt.Errorf("category IDs must list all %d children of the parent", len(siblings))
+	}
+
+	if err := uc.categoryRepo.UpdateSortOrders(ctx, sortOrders); err != nil {
+		return fmt.Errorf("failed to reorder categories: %w", err)
+	}
+	return nil
+}
+
+// sumSubtreeCounts fills in the subtree product counts below node.
+func sumSubtreeCounts(node *domain.CategoryNode) int64 {
+	node.SubtreeProductCount = node.ProductCount
+	for _, child := range node.Children {
+		node.SubtreeProductCount += sumSubtreeCounts(child)
+	}
+	return node.SubtreeProductCount
+}
+
+// descendantCategoryIDs returns rootID followed by the IDs of all categories
+// below it.
+func descendantCategoryIDs(categories []*domain.Category, rootID string) []string {
+	children := make(map[string][]string)
+	for _, c := range categories {
+		children[c.ParentID] = append(children[c.ParentID], c.ID)
+	}
+
+	ids := []string{rootID}
+	seen := map[string]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids
+}
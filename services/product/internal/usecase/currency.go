@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/southern-martin/ecommerce/pkg/currency"
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// CurrencyUseCase presents catalog prices in the buyer's currency and
+// manages the prices sellers set for variants in other currencies.
+type CurrencyUseCase struct {
+	productRepo domain.ProductRepository
+	variantRepo domain.VariantRepository
+	priceRepo   domain.VariantPriceRepository
+	converter   *currency.Converter
+}
+
+// NewCurrencyUseCase creates a new CurrencyUseCase.
+func NewCurrencyUseCase(
+	productRepo domain.ProductRepository,
+	variantRepo domain.VariantRepository,
+	priceRepo domain.VariantPriceRepository,
+	converter *currency.Converter,
+) *CurrencyUseCase {
+	return &CurrencyUseCase{
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+		priceRepo:   priceRepo,
+		converter:   converter,
+	}
+}
+
+// SetVariantPriceInput holds the price of a variant in another currency.
+type SetVariantPriceInput struct {
+	PriceCents     int64
+	CompareAtCents int64
+}
+
+// SetVariantPrice sets the price buyers shopping in code pay for a variant,
+// replacing the converted price.
+func (uc *CurrencyUseCase) SetVariantPrice(ctx context.Context, productID string, variantID string, sellerID string, code string, input SetVariantPriceInput) (*domain.VariantPrice, error) {
+	product, err := uc.sellerVariant(ctx, productID, variantID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	code = currency.Normalize(code)
+	if !currency.IsSupported(code) {
+		return nil, fmt.Errorf("unsupported currency: %s", code)
+	}
+	if code == currency.Normalize(product.Currency) {
+		return nil, fmt.Errorf("prices in %s are set on the variant itself", code)
+	}
+	if input.PriceCents <= 0 {
+		return nil, fmt.Errorf("price must be greater than 0")
+	}
+	if input.CompareAtCents < 0 {
+		return nil, fmt.Errorf("compare-at price must be non-negative")
+	}
+
+	price := &domain.VariantPrice{
+		VariantID:      variantID,
+		ProductID:      productID,
+		Currency:       code,
+		PriceCents:     input.PriceCents,
+		CompareAtCents: input.CompareAtCents,
+		UpdatedAt:      time.Now().UTC(),
+	}
+	if err := uc.priceRepo.Upsert(ctx, price); err != nil {
+		return nil, fmt.Errorf("failed to save variant price: %w", err)
+	}
+	return price, nil
+}
+
+// ListVariantPrices lists the prices a seller set for a variant in other
+// currencies.
+func (uc *CurrencyUseCase) ListVariantPrices(ctx context.Context, productID string, variantID string, sellerID string) ([]*domain.VariantPrice, error) {
+	if _, err := uc.sellerVariant(ctx, productID, variantID, sellerID); err != nil {
+		return nil, err
+	}
+	return uc.priceRepo.ListByVariant(ctx, variantID)
+}
+
+// DeleteVariantPrice removes a variant's price in code, so buyers shopping
+// in it pay the converted price again.
+func (uc *CurrencyUseCase) DeleteVariantPrice(ctx context.Context, productID string, variantID string, sellerID string, code string) error {
+	if _, err := uc.sellerVariant(ctx, productID, variantID, sellerID); err != nil {
+		return err
+	}
+	return uc.priceRepo.Delete(ctx, variantID, currency.Normalize(code))
+}
+
+// PresentProducts sets the base price of products in code. Products whose
+// currency cannot be converted are left without a presentment price.
+func (uc *CurrencyUseCase) PresentProducts(code string, products ...*domain.Product) {
+	for _, product := range products {
+		price, err := uc.converter.Convert(product.BasePriceCents, currency.Normalize(product.Currency), code)
+		if err != nil {
+			continue
+		}
+		product.PresentmentCurrency = code
+		product.PresentmentPriceCents = price
+	}
+}
+
+// PresentPriceSummary sets the prices of a variant's price summary in code,
+// preferring the seller's price in that currency over conversion.
+func (uc *CurrencyUseCase) PresentPriceSummary(ctx context.Context, code string, summary *domain.PriceSummary) error {
+	summary.PresentmentCurrency = code
+
+	from := currency.Normalize(summary.Currency)
+	if from != code {
+		override, err := uc.priceRepo.Get(ctx, summary.VariantID, code)
+		if err != nil {
+			return fmt.Errorf("failed to load variant price: %w", err)
+		}
+		if override != nil {
+			summary.PresentmentPriceCents = override.PriceCents
+			summary.PresentmentCompareAtCents = override.CompareAtCents
+			summary.PriceOverride = true
+			return nil
+		}
+	}
+
+	rate, err := uc.converter.GetRate(from, code)
+	if err != nil {
+		return err
+	}
+	if summary.PresentmentPriceCents, err = uc.converter.Convert(summary.PriceCents, from, code); err != nil {
+		return err
+	}
+	if summary.PresentmentCompareAtCents, err = uc.converter.Convert(summary.CompareAtCents, from, code); err != nil {
+		return err
+	}
+	summary.ExchangeRate = rate
+	return nil
+}
+
+func (uc *CurrencyUseCase) sellerVariant(ctx context.Context, productID string, variantID string, sellerID string) (*domain.Product, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("unauthorized: product belongs to another seller")
+	}
+	variant, err := uc.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return nil, fmt.Errorf("variant not found: %w", err)
+	}
+	if variant.ProductID != productID {
+		return nil, fmt.Errorf("variant does not belong to this product")
+	}
+	return product, nil
+}
@@ -0,0 +1,580 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// DigitalUseCase manages the files and license keys of digital products and
+// delivers them to buyers once their order is paid.
+type DigitalUseCase struct {
+	productRepo     domain.ProductRepository
+	variantRepo     domain.VariantRepository
+	fileRepo        domain.DigitalFileRepository
+	keyRepo         domain.LicenseKeyRepository
+	entitlementRepo domain.DigitalEntitlementRepository
+	eventPub        domain.EventPublisher
+	// mediaURL is the public base URL of the media service, which serves
+	// the files behind signed links verified with signingSecret.
+	mediaURL      string
+	signingSecret string
+	linkTTL       time.Duration
+}
+
+// NewDigitalUseCase creates a new DigitalUseCase.
+func NewDigitalUseCase(
+	productRepo domain.ProductRepository,
+	variantRepo domain.VariantRepository,
+	fileRepo domain.DigitalFileRepository,
+	keyRepo domain.LicenseKeyRepository,
+	entitlementRepo domain.DigitalEntitlementRepository,
+	eventPub domain.EventPublisher,
+	mediaURL string,
+	signingSecret string,
+	linkTTL time.Duration,
+) *DigitalUseCase {
+	return &DigitalUseCase{
+		productRepo:     productRepo,
+		variantRepo:     variantRepo,
+		fileRepo:        fileRepo,
+		keyRepo:         keyRepo,
+		entitlementRepo: entitlementRepo,
+		eventPub:        eventPub,
+		mediaURL:        strings.TrimRight(mediaURL, "/"),
+		signingSecret:   signingSecret,
+		linkTTL:         linkTTL,
+	}
+}
+
+// AttachFileInput holds the data for attaching a file to a digital product.
+// The file must already be uploaded to the media service by the seller with
+// owner type "digital_product", which keeps it private.
+type AttachFileInput struct {
+	VariantID   string
+	MediaID     string
+	FileName    string
+	ContentType string
+	SizeBytes   int64
+}
+
+// AttachFile attaches an uploaded file to a digital product.
+func (uc *DigitalUseCase) AttachFile(ctx context.Context, productID string, sellerID string, input AttachFileInput) (*domain.DigitalFile, error) {
+	if _, err := uc.sellerDigitalProduct(ctx, productID, sellerID); err != nil {
+		return nil, err
+	}
+	if input.MediaID == "" {
+		return nil, fmt.Errorf("media ID is required")
+	}
+	if input.FileName == "" {
+		return nil, fmt.Errorf("file name is required")
+	}
+	if input.VariantID != "" {
+		if err := uc.checkVariant(ctx, productID, input.VariantID); err != nil {
+			return nil, err
+		}
+	}
+
+	file := &domain.DigitalFile{
+		ID:          uuid.New().String(),
+		ProductID:   productID,
+		VariantID:   input.VariantID,
+		MediaID:     input.MediaID,
+		FileName:    input.FileName,
+		ContentType: input.ContentType,
+		SizeBytes:   input.SizeBytes,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := uc.fileRepo.Create(ctx, file); err != nil {
+		return nil, fmt.Errorf("failed to attach file: %w", err)
+	}
+	return file, nil
+}
+
+// ListFiles lists the files of a seller's digital product.
+func (uc *DigitalUseCase) ListFiles(ctx context.Context, productID string, sellerID string) ([]*domain.DigitalFile, error) {
+	if _, err := uc.sellerDigitalProduct(ctx, productID, sellerID); err != nil {
+		return nil, err
+	}
+	return uc.fileRepo.ListByProduct(ctx, productID)
+}
+
+// DeleteFile detaches a file from a digital product. Buyers lose access to
+// it; the media itself is left to the seller to delete.
+func (uc *DigitalUseCase) DeleteFile(ctx context.Context, productID string, fileID string, sellerID string) error {
+	if _, err := uc.sellerDigitalProduct(ctx, productID, sellerID); err != nil {
+		return err
+	}
+	file, err := uc.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return err
+	}
+	if file.ProductID != productID {
+		return fmt.Errorf("file does not belong to this product")
+	}
+	return uc.fileRepo.Delete(ctx, fileID)
+}
+
+// AddLicenseKeysInput holds license keys to add to a product's pool.
+// Keys with a VariantID are only given to buyers of that variant.
+type AddLicenseKeysInput struct {
+	VariantID string
+	Keys      []string
+}
+
+// AddLicenseKeys adds keys to a digital product's pool and hands them to
+// buyers still waiting for keys. It returns how many new keys were added;
+// keys already in the pool are skipped.
+func (uc *DigitalUseCase) AddLicenseKeys(ctx context.Context, productID string, sellerID string, input AddLicenseKeysInput) (int, error) {
+	product, err := uc.sellerDigitalProduct(ctx, productID, sellerID)
+	if err != nil {
+		return 0, err
+	}
+	if input.VariantID != "" {
+		if err := uc.checkVariant(ctx, productID, input.VariantID); err != nil {
+			return 0, err
+		}
+	}
+
+	now := time.Now().UTC()
+	seen := make(map[string]bool, len(input.Keys))
+	var keys []*domain.LicenseKey
+	for _, k := range input.Keys {
+		k = strings.TrimSpace(k)
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, &domain.LicenseKey{
+			ID:        uuid.New().String(),
+			ProductID: productID,
+			VariantID: input.VariantID,
+			Key:       k,
+			Status:    domain.LicenseKeyStatusAvailable,
+			CreatedAt: now,
+		})
+	}
+	if len(keys) == 0 {
+		return 0, fmt.Errorf("at least one license key is required")
+	}
+
+	added, err := uc.keyRepo.CreateBatch(ctx, keys)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add license keys: %w", err)
+	}
+
+	waiting, err := uc.entitlementRepo.ListAwaitingKeys(ctx, productID)
+	if err != nil {
+		return added, fmt.Errorf("failed to list entitlements awaiting keys: %w", err)
+	}
+	for _, e := range waiting {
+		if err := uc.assignKeys(ctx, product, e); err != nil {
+			return added, err
+		}
+	}
+	return added, nil
+}
+
+// ListLicenseKeys lists the keys in a digital product's pool, optionally
+// only those with the given status.
+func (uc *DigitalUseCase) ListLicenseKeys(ctx context.Context, productID string, sellerID string, status domain.LicenseKeyStatus) ([]*domain.LicenseKey, error) {
+	if _, err := uc.sellerDigitalProduct(ctx, productID, sellerID); err != nil {
+		return nil, err
+	}
+	return uc.keyRepo.ListByProduct(ctx, productID, status)
+}
+
+// DeleteLicenseKey removes an unassigned key from a product's pool.
+func (uc *DigitalUseCase) DeleteLicenseKey(ctx context.Context, productID string, keyID string, sellerID string) error {
+	if _, err := uc.sellerDigitalProduct(ctx, productID, sellerID); err != nil {
+		return err
+	}
+	key, err := uc.keyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return err
+	}
+	if key.ProductID != productID {
+		return fmt.Errorf("license key does not belong to this product")
+	}
+	if key.Status != domain.LicenseKeyStatusAvailable {
+		return fmt.Errorf("license key is %s and cannot be deleted", key.Status)
+	}
+	return uc.keyRepo.Delete(ctx, keyID)
+}
+
+// DigitalOrderItem is a digital line of a placed order. AmountCents is
+// what the buyer paid for it, after discounts.
+type DigitalOrderItem struct {
+	ProductID   string
+	VariantID   string
+	SellerID    string
+	Quantity    int
+	AmountCents int64
+}
+
+// RecordOrder records pending entitlements for the digital lines of a newly
+// placed order, which cost totalCents in all. Lines already recorded are
+// skipped, so recording an order again only records what a failed attempt
+// left out.
+func (uc *DigitalUseCase) RecordOrder(ctx context.Context, orderID string, buyerID string, totalCents int64, items []DigitalOrderItem) (int, error) {
+	existing, err := uc.entitlementRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list entitlements: %w", err)
+	}
+	recorded := make(map[string]bool, len(existing))
+	for _, e := range existing {
+		recorded[e.ProductID+"|"+e.VariantID] = true
+	}
+
+	// Lines of the same product and variant share one entitlement
+	now := time.Now().UTC()
+	byLine := make(map[string]*domain.DigitalEntitlement)
+	var entitlements []*domain.DigitalEntitlement
+	for _, item := range items {
+		lineKey := item.ProductID + "|" + item.VariantID
+		if recorded[lineKey] {
+			continue
+		}
+		if e, ok := byLine[lineKey]; ok {
+			e.Quantity += item.Quantity
+			e.AmountCents += item.AmountCents
+			continue
+		}
+		product, err := uc.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to look up product %s: %w", item.ProductID, err)
+		}
+		e := &domain.DigitalEntitlement{
+			ID:              uuid.New().String(),
+			OrderID:         orderID,
+			BuyerID:         buyerID,
+			SellerID:        product.SellerID,
+			ProductID:       product.ID,
+			VariantID:       item.VariantID,
+			Quantity:        item.Quantity,
+			AmountCents:     item.AmountCents,
+			OrderTotalCents: totalCents,
+			Status:          domain.EntitlementStatusPending,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		byLine[lineKey] = e
+		entitlements = append(entitlements, e)
+	}
+
+	for _, e := range entitlements {
+		if e.Quantity <= 0 {
+			e.Quantity = 1
+		}
+		if err := uc.entitlementRepo.Create(ctx, e); err != nil {
+			return 0, fmt.Errorf("failed to record entitlement: %w", err)
+		}
+	}
+	return len(entitlements), nil
+}
+
+// FulfilOrder activates the entitlements of a paid order: downloads open,
+// the expiry clock starts and license keys are assigned. Buyers are told
+// through a product.digital.delivered event. Fulfilling an order twice has
+// no effect.
+func (uc *DigitalUseCase) FulfilOrder(ctx context.Context, orderID string) (int, error) {
+	entitlements, err := uc.entitlementRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list entitlements: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var delivered []*domain.DigitalEntitlement
+	for _, e := range entitlements {
+		if e.Status != domain.EntitlementStatusPending {
+			continue
+		}
+		product, err := uc.productRepo.GetByID(ctx, e.ProductID)
+		if err != nil {
+			return len(delivered), fmt.Errorf("product not found: %w", err)
+		}
+
+		e.Status = domain.EntitlementStatusActive
+		e.ActivatedAt = &now
+		e.DownloadLimit = product.DownloadLimit
+		if product.DownloadExpiryDays > 0 {
+			expiresAt := now.AddDate(0, 0, product.DownloadExpiryDays)
+			e.ExpiresAt = &expiresAt
+		}
+		if product.RequiresLicenseKey {
+			e.KeysPending = e.Quantity
+		}
+		e.UpdatedAt = now
+		if err := uc.entitlementRepo.Update(ctx, e); err != nil {
+			return len(delivered), fmt.Errorf("failed to activate entitlement: %w", err)
+		}
+		if e.KeysPending > 0 {
+			if err := uc.assignKeys(ctx, product, e); err != nil {
+				return len(delivered), err
+			}
+		}
+		delivered = append(delivered, e)
+	}
+
+	if len(delivered) > 0 {
+		_ = uc.eventPub.PublishDigitalDelivered(ctx, orderID, delivered[0].BuyerID, delivered)
+	}
+	return len(delivered), nil
+}
+
+// RevokeOrder revokes the entitlements of a cancelled order, including the
+// license keys given out for it.
+func (uc *DigitalUseCase) RevokeOrder(ctx context.Context, orderID string) (int, error) {
+	entitlements, err := uc.entitlementRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list entitlements: %w", err)
+	}
+	return uc.revoke(ctx, entitlements)
+}
+
+// RevokeSellerOrder revokes the entitlements sold by sellerID in an order
+// whose part from that seller was cancelled.
+func (uc *DigitalUseCase) RevokeSellerOrder(ctx context.Context, orderID string, sellerID string) (int, error) {
+	entitlements, err := uc.entitlementRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list entitlements: %w", err)
+	}
+	var sold []*domain.DigitalEntitlement
+	for _, e := range entitlements {
+		if e.SellerID == sellerID {
+			sold = append(sold, e)
+		}
+	}
+	return uc.revoke(ctx, sold)
+}
+
+// RevokeRefund revokes the entitlements of an order refunded refundCents
+// when the refund reaches into its digital lines, that is when it is more
+// than the buyer paid for the rest of the order. Refunds of physical lines
+// or shipping only leave the entitlements in place. Entitlements recorded
+// before amounts were kept are revoked by any refund.
+func (uc *DigitalUseCase) RevokeRefund(ctx context.Context, orderID string, refundCents int64) (int, error) {
+	entitlements, err := uc.entitlementRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list entitlements: %w", err)
+	}
+	if len(entitlements) == 0 {
+		return 0, nil
+	}
+
+	rest := entitlements[0].OrderTotalCents
+	for _, e := range entitlements {
+		rest -= e.AmountCents
+	}
+	if refundCents <= rest {
+		return 0, nil
+	}
+	return uc.revoke(ctx, entitlements)
+}
+
+// revoke revokes entitlements, including the license keys given out for
+// them. Entitlements already revoked are skipped.
+func (uc *DigitalUseCase) revoke(ctx context.Context, entitlements []*domain.DigitalEntitlement) (int, error) {
+	revoked := 0
+	for _, e := range entitlements {
+		if e.Status == domain.EntitlementStatusRevoked {
+			continue
+		}
+		e.Status = domain.EntitlementStatusRevoked
+		e.KeysPending = 0
+		e.UpdatedAt = time.Now().UTC()
+		if err := uc.entitlementRepo.Update(ctx, e); err != nil {
+			return revoked, fmt.Errorf("failed to revoke entitlement: %w", err)
+		}
+		if err := uc.keyRepo.RevokeByEntitlement(ctx, e.ID); err != nil {
+			return revoked, fmt.Errorf("failed to revoke license keys: %w", err)
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// ListEntitlements lists a buyer's digital purchases.
+func (uc *DigitalUseCase) ListEntitlements(ctx context.Context, buyerID string, page, pageSize int) ([]*domain.DigitalEntitlement, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return uc.entitlementRepo.ListByBuyer(ctx, buyerID, page, pageSize)
+}
+
+// GetEntitlement returns a buyer's digital purchase with its files and
+// license keys.
+func (uc *DigitalUseCase) GetEntitlement(ctx context.Context, id string, buyerID string) (*domain.DigitalEntitlement, error) {
+	e, err := uc.buyerEntitlement(ctx, id, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != domain.EntitlementStatusActive {
+		return e, nil
+	}
+
+	files, err := uc.entitlementFiles(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		e.Files = append(e.Files, *f)
+	}
+	keys, err := uc.keyRepo.ListByEntitlement(ctx, e.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list license keys: %w", err)
+	}
+	for _, k := range keys {
+		e.LicenseKeys = append(e.LicenseKeys, *k)
+	}
+	return e, nil
+}
+
+// CreateDownloadLink issues an expiring signed link to a file of a buyer's
+// digital purchase. Every link issued counts as one download.
+func (uc *DigitalUseCase) CreateDownloadLink(ctx context.Context, entitlementID string, fileID string, buyerID string) (*domain.DownloadLink, error) {
+	e, err := uc.buyerEntitlement(ctx, entitlementID, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != domain.EntitlementStatusActive {
+		return nil, fmt.Errorf("entitlement is %s", e.Status)
+	}
+	now := time.Now().UTC()
+	if e.ExpiresAt != nil && now.After(*e.ExpiresAt) {
+		return nil, fmt.Errorf("download period has expired")
+	}
+
+	file, err := uc.fileRepo.GetByID(ctx, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.ProductID != e.ProductID || (file.VariantID != "" && file.VariantID != e.VariantID) {
+		return nil, fmt.Errorf("file is not part of this purchase")
+	}
+
+	counted, err := uc.entitlementRepo.IncrementDownloads(ctx, e.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count download: %w", err)
+	}
+	if !counted {
+		return nil, fmt.Errorf("download limit reached")
+	}
+
+	expiresAt := now.Add(uc.linkTTL)
+	if e.ExpiresAt != nil && e.ExpiresAt.Before(expiresAt) {
+		expiresAt = *e.ExpiresAt
+	}
+	link := &domain.DownloadLink{
+		FileID:    file.ID,
+		FileName:  file.FileName,
+		URL:       uc.signedURL(file.MediaID, e.SellerID, expiresAt),
+		ExpiresAt: expiresAt,
+	}
+	if e.DownloadLimit > 0 {
+		remaining := e.DownloadLimit - e.DownloadCount - 1
+		link.DownloadsRemaining = &remaining
+	}
+	return link, nil
+}
+
+// signedURL builds a media service link to a private file. The media service
+// checks the HMAC-SHA256 signature over "mediaID|ownerID|expires" with the
+// shared secret before serving the file.
+func (uc *DigitalUseCase) signedURL(mediaID string, ownerID string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(uc.signingSecret))
+	mac.Write([]byte(mediaID + "|" + ownerID + "|" + expires))
+
+	query := url.Values{}
+	query.Set("owner", ownerID)
+	query.Set("expires", expires)
+	query.Set("signature", hex.EncodeToString(mac.Sum(nil)))
+	return fmt.Sprintf("%s/api/v1/media/%s/signed-download?%s", uc.mediaURL, url.PathEscape(mediaID), query.Encode())
+}
+
+// assignKeys hands available license keys to an entitlement still owed
+// some. Whatever the pool cannot cover stays pending until keys are added.
+func (uc *DigitalUseCase) assignKeys(ctx context.Context, product *domain.Product, e *domain.DigitalEntitlement) error {
+	keys, err := uc.keyRepo.ClaimAvailable(ctx, product.ID, e.VariantID, e.ID, e.KeysPending)
+	if err != nil {
+		return fmt.Errorf("failed to assign license keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	e.KeysPending -= len(keys)
+	e.UpdatedAt = time.Now().UTC()
+	if err := uc.entitlementRepo.Update(ctx, e); err != nil {
+		return fmt.Errorf("failed to update entitlement: %w", err)
+	}
+	return nil
+}
+
+// entitlementFiles lists the files of the purchased product that apply to
+// the purchased variant.
+func (uc *DigitalUseCase) entitlementFiles(ctx context.Context, e *domain.DigitalEntitlement) ([]*domain.DigitalFile, error) {
+	files, err := uc.fileRepo.ListByProduct(ctx, e.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+	var result []*domain.DigitalFile
+	for _, f := range files {
+		if f.VariantID == "" || f.VariantID == e.VariantID {
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+func (uc *DigitalUseCase) buyerEntitlement(ctx context.Context, id string, buyerID string) (*domain.DigitalEntitlement, error) {
+	e, err := uc.entitlementRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if e.BuyerID != buyerID {
+		return nil, fmt.Errorf("unauthorized: entitlement belongs to another buyer")
+	}
+	return e, nil
+}
+
+func (uc *DigitalUseCase) sellerDigitalProduct(ctx context.Context, productID string, sellerID string) (*domain.Product, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("unauthorized: product belongs to another seller")
+	}
+	if !product.IsDigital() {
+		return nil, fmt.Errorf("product is not digital")
+	}
+	return product, nil
+}
+
+func (uc *DigitalUseCase) checkVariant(ctx context.Context, productID string, variantID string) error {
+	variant, err := uc.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return fmt.Errorf("variant not found: %w", err)
+	}
+	if variant.ProductID != productID {
+		return fmt.Errorf("variant does not belong to this product")
+	}
+	return nil
+}
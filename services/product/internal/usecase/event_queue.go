@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// eventQueue is a domain.EventPublisher that holds events back until flush,
+// so that the events of writes made in a transaction are only published
+// once it commits.
+type eventQueue struct {
+	events []func(ctx context.Context, pub domain.EventPublisher) error
+}
+
+func (q *eventQueue) add(event func(ctx context.Context, pub domain.EventPublisher) error) error {
+	q.events = append(q.events, event)
+	return nil
+}
+
+// flush publishes the queued events in order.
+func (q *eventQueue) flush(ctx context.Context, pub domain.EventPublisher) {
+	for _, event := range q.events {
+		_ = event(ctx, pub)
+	}
+	q.events = nil
+}
+
+func (q *eventQueue) PublishProductCreated(_ context.Context, product *domain.Product) error {
+	return q.add(func(ctx context.Context, pub domain.EventPublisher) error {
+		return pub.PublishProductCreated(ctx, product)
+	})
+}
+
+func (q *eventQueue) PublishProductUpdated(_ context.Context, product *domain.Product) error {
+	return q.add(func(ctx context.Context, pub domain.EventPublisher) error {
+		return pub.PublishProductUpdated(ctx, product)
+	})
+}
+
+func (q *eventQueue) PublishProductDeleted(_ context.Context, productID string) error {
+	return q.add(func(ctx context.Context, pub domain.EventPublisher) error {
+		return pub.PublishProductDeleted(ctx, productID)
+	})
+}
+
+func (q *eventQueue) PublishStockUpdated(_ context.Context, variantID string, newStock int, delta int) error {
+	return q.add(func(ctx context.Context, pub domain.EventPublisher) error {
+		return pub.PublishStockUpdated(ctx, variantID, newStock, delta)
+	})
+}
+
+func (q *eventQueue) PublishPriceUpdated(_ context.Context, product *domain.Product, change *domain.PriceHistoryEntry) error {
+	return q.add(func(ctx context.Context, pub domain.EventPublisher) error {
+		return pub.PublishPriceUpdated(ctx, product, change)
+	})
+}
+
+func (q *eventQueue) PublishDigitalDelivered(_ context.Context, orderID, buyerID string, entitlements []*domain.DigitalEntitlement) error {
+	return q.add(func(ctx context.Context, pub domain.EventPublisher) error {
+		return pub.PublishDigitalDelivered(ctx, orderID, buyerID, entitlements)
+	})
+}
+
+func (q *eventQueue) PublishRatingUpdated(_ context.Context, product *domain.Product, seller *domain.SellerRating) error {
+	return q.add(func(ctx context.Context, pub domain.EventPublisher) error {
+		return pub.PublishRatingUpdated(ctx, product, seller)
+	})
+}
@@ -0,0 +1,517 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+const (
+	// feedPageSize is the number of rows read per page while building or
+	// serving feeds.
+	feedPageSize = 500
+	// maxFeedTitle and maxFeedDescription are the Google Merchant Center
+	// limits, in characters.
+	maxFeedTitle       = 150
+	maxFeedDescription = 5000
+	// maxFeedAdditionalImages is the number of extra images Merchant Center accepts.
+	maxFeedAdditionalImages = 10
+	// brandAttributeSlug is the attribute whose value is published as brand.
+	brandAttributeSlug = "brand"
+)
+
+// FeedUseCase maintains and serves the product feeds published to shopping
+// channels. Feed items are kept up to date per product from product events,
+// so serving a feed only reads the precomputed items.
+type FeedUseCase struct {
+	productRepo   domain.ProductRepository
+	categoryRepo  domain.CategoryRepository
+	attributeRepo domain.AttributeRepository
+	variantRepo   domain.VariantRepository
+	feedItemRepo  domain.FeedItemRepository
+	mappingRepo   domain.FeedCategoryMappingRepository
+	storefrontURL string
+}
+
+// NewFeedUseCase creates a new FeedUseCase. storefrontURL is the base URL
+// product links in the feed point to.
+func NewFeedUseCase(
+	productRepo domain.ProductRepository,
+	categoryRepo domain.CategoryRepository,
+	attributeRepo domain.AttributeRepository,
+	variantRepo domain.VariantRepository,
+	feedItemRepo domain.FeedItemRepository,
+	mappingRepo domain.FeedCategoryMappingRepository,
+	storefrontURL string,
+) *FeedUseCase {
+	return &FeedUseCase{
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+		attributeRepo: attributeRepo,
+		variantRepo:   variantRepo,
+		feedItemRepo:  feedItemRepo,
+		mappingRepo:   mappingRepo,
+		storefrontURL: strings.TrimRight(storefrontURL, "/"),
+	}
+}
+
+// feedLookups holds the reference data needed to build feed items, loaded
+// once per refresh or rebuild.
+type feedLookups struct {
+	categories map[string]*domain.Category
+	mappings   map[string]string
+	attrSlugs  map[string]string
+}
+
+func (uc *FeedUseCase) loadLookups(ctx context.Context) (*feedLookups, error) {
+	categories, err := uc.categoryRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list categories: %w", err)
+	}
+	mappings, err := uc.mappingRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed category mappings: %w", err)
+	}
+	defs, err := uc.attributeRepo.ListDefinitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attribute definitions: %w", err)
+	}
+
+	l := &feedLookups{
+		categories: make(map[string]*domain.Category, len(categories)),
+		mappings:   make(map[string]string, len(mappings)),
+		attrSlugs:  make(map[string]string, len(defs)),
+	}
+	for _, c := range categories {
+		l.categories[c.ID] = c
+	}
+	for _, m := range mappings {
+		l.mappings[m.CategoryID] = m.GoogleCategory
+	}
+	for _, d := range defs {
+		l.attrSlugs[d.ID] = d.Slug
+	}
+	return l, nil
+}
+
+// RefreshProduct rebuilds the feed items of a product. Products that are not
+// active, or have no active variants, are removed from the feed.
+func (uc *FeedUseCase) RefreshProduct(ctx context.Context, productID string) error {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	lookups, err := uc.loadLookups(ctx)
+	if err != nil {
+		return err
+	}
+	return uc.refresh(ctx, product, lookups, time.Now().UTC())
+}
+
+// RefreshVariant rebuilds the feed items of the product a variant belongs to.
+func (uc *FeedUseCase) RefreshVariant(ctx context.Context, variantID string) error {
+	variant, err := uc.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return fmt.Errorf("variant not found: %w", err)
+	}
+	return uc.RefreshProduct(ctx, variant.ProductID)
+}
+
+// RemoveProduct drops a deleted product from the feed.
+func (uc *FeedUseCase) RemoveProduct(ctx context.Context, productID string) error {
+	return uc.feedItemRepo.DeleteByProduct(ctx, productID)
+}
+
+// Rebuild regenerates every feed item from the catalog and drops items of
+// products that are no longer active. It returns the number of active
+// products refreshed.
+func (uc *FeedUseCase) Rebuild(ctx context.Context) (int, error) {
+	lookups, err := uc.loadLookups(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now().UTC()
+	refreshed := 0
+	err = uc.eachActiveProduct(ctx, domain.ProductFilter{}, func(product *domain.Product) error {
+		if err := uc.refresh(ctx, product, lookups, time.Now().UTC()); err != nil {
+			return err
+		}
+		refreshed++
+		return nil
+	})
+	if err != nil {
+		return refreshed, err
+	}
+
+	// Every product still active was refreshed after start; anything older
+	// belongs to a product that was deactivated or deleted.
+	if err := uc.feedItemRepo.DeleteUpdatedBefore(ctx, start); err != nil {
+		return refreshed, fmt.Errorf("failed to prune feed items: %w", err)
+	}
+	return refreshed, nil
+}
+
+// ListCategoryMappings lists the Google product categories assigned to
+// marketplace categories.
+func (uc *FeedUseCase) ListCategoryMappings(ctx context.Context) ([]*domain.FeedCategoryMapping, error) {
+	return uc.mappingRepo.List(ctx)
+}
+
+// SetCategoryMapping assigns a Google product category to a marketplace
+// category and refreshes the products it applies to.
+func (uc *FeedUseCase) SetCategoryMapping(ctx context.Context, categoryID string, googleCategory string) (*domain.FeedCategoryMapping, error) {
+	googleCategory = strings.TrimSpace(googleCategory)
+	if googleCategory == "" {
+		return nil, fmt.Errorf("google category is required")
+	}
+	if _, err := uc.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, fmt.Errorf("category not found: %w", err)
+	}
+
+	mapping := &domain.FeedCategoryMapping{
+		CategoryID:     categoryID,
+		GoogleCategory: googleCategory,
+		UpdatedAt:      time.Now().UTC(),
+	}
+	if err := uc.mappingRepo.Upsert(ctx, mapping); err != nil {
+		return nil, fmt.Errorf("failed to save feed category mapping: %w", err)
+	}
+
+	if err := uc.refreshCategory(ctx, categoryID); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// DeleteCategoryMapping removes the Google product category of a marketplace
+// category and refreshes the products it applied to.
+func (uc *FeedUseCase) DeleteCategoryMapping(ctx context.Context, categoryID string) error {
+	if err := uc.mappingRepo.Delete(ctx, categoryID); err != nil {
+		return fmt.Errorf("failed to delete feed category mapping: %w", err)
+	}
+	return uc.refreshCategory(ctx, categoryID)
+}
+
+// refreshCategory refreshes the products of a category and of its
+// subcategories, since those inherit the nearest mapped ancestor.
+func (uc *FeedUseCase) refreshCategory(ctx context.Context, categoryID string) error {
+	lookups, err := uc.loadLookups(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for id := range lookups.categories {
+		if !lookups.hasAncestor(id, categoryID) {
+			continue
+		}
+		err := uc.eachActiveProduct(ctx, domain.ProductFilter{CategoryID: id}, func(product *domain.Product) error {
+			return uc.refresh(ctx, product, lookups, now)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFeed streams the feed of a seller, or of the whole platform when
+// sellerID is empty, in the given format.
+func (uc *FeedUseCase) WriteFeed(ctx context.Context, sellerID string, format domain.FeedFormat, w io.Writer) error {
+	channel := feedChannel{
+		Title:       "Marketplace products",
+		Link:        uc.storefrontURL,
+		Description: "All active products",
+	}
+	if sellerID != "" {
+		channel.Title = "Seller " + sellerID + " products"
+		channel.Description = "Active products of seller " + sellerID
+	}
+
+	writer, err := newFeedWriter(format, w, channel)
+	if err != nil {
+		return err
+	}
+
+	afterID := ""
+	for {
+		items, err := uc.feedItemRepo.ListAfter(ctx, sellerID, afterID, feedPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to list feed items: %w", err)
+		}
+		for _, item := range items {
+			if err := writer.Write(item); err != nil {
+				return err
+			}
+		}
+		if len(items) < feedPageSize {
+			break
+		}
+		afterID = items[len(items)-1].ID
+	}
+
+	return writer.Close()
+}
+
+func (uc *FeedUseCase) eachActiveProduct(ctx context.Context, filter domain.ProductFilter, fn func(product *domain.Product) error) error {
+	filter.Status = string(domain.ProductStatusActive)
+	filter.SortBy = "newest"
+	filter.PageSize = feedPageSize
+	for page := 1; ; page++ {
+		filter.Page = page
+		products, total, err := uc.productRepo.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("failed to list products: %w", err)
+		}
+		for _, product := range products {
+			if err := fn(product); err != nil {
+				return err
+			}
+		}
+		if int64(page*feedPageSize) >= total || len(products) == 0 {
+			return nil
+		}
+	}
+}
+
+func (uc *FeedUseCase) refresh(ctx context.Context, product *domain.Product, lookups *feedLookups, now time.Time) error {
+	items, err := uc.buildItems(ctx, product, lookups, now)
+	if err != nil {
+		return err
+	}
+	if err := uc.feedItemRepo.ReplaceForProduct(ctx, product.ID, items); err != nil {
+		return fmt.Errorf("failed to save feed items of %s: %w", product.ID, err)
+	}
+	return nil
+}
+
+// buildItems turns a product into one feed item per active variant.
+func (uc *FeedUseCase) buildItems(ctx context.Context, product *domain.Product, lookups *feedLookups, now time.Time) ([]domain.FeedItem, error) {
+	if product.Status != domain.ProductStatusActive {
+		return nil, nil
+	}
+
+	variants, err := uc.variantRepo.ListByProduct(ctx, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list variants of %s: %w", product.ID, err)
+	}
+	var active []domain.Variant
+	for _, v := range variants {
+		if v.IsActive {
+			active = append(active, v)
+		}
+	}
+	if len(active) == 0 {
+		return nil, nil
+	}
+
+	brand, err := uc.productBrand(ctx, product.ID, lookups)
+	if err != nil {
+		return nil, err
+	}
+
+	grouped := product.HasVariants || len(active) > 1
+	link := uc.storefrontURL + "/products/" + product.Slug
+	description := product.Description
+	if strings.TrimSpace(description) == "" {
+		description = product.Name
+	}
+
+	items := make([]domain.FeedItem, 0, len(active))
+	for _, v := range active {
+		item := domain.FeedItem{
+			ID:                    v.ID,
+			ProductID:             product.ID,
+			SellerID:              product.SellerID,
+			SKU:                   v.SKU,
+			Title:                 truncateRunes(product.Name, maxFeedTitle),
+			Description:           truncateRunes(description, maxFeedDescription),
+			Link:                  link,
+			Currency:              product.Currency,
+			Stock:                 v.Stock,
+			Availability:          domain.FeedAvailabilityOutOfStock,
+			GTIN:                  normalizeGTIN(v.Barcode),
+			Brand:                 brand,
+			WeightGrams:           v.WeightGrams,
+			CategoryID:            product.CategoryID,
+			GoogleProductCategory: lookups.googleCategory(product.CategoryID),
+			ProductType:           lookups.categoryPath(product.CategoryID),
+			UpdatedAt:             now,
+		}
+		if grouped {
+			item.ItemGroupID = product.ID
+			item.Link = link + "?variant=" + v.ID
+			if v.Name != "" {
+				item.Title = truncateRunes(product.Name+" - "+v.Name, maxFeedTitle)
+			}
+		}
+		if v.Stock > 0 {
+			item.Availability = domain.FeedAvailabilityInStock
+		}
+
+		// A compare-at price above the selling price is published as the
+		// regular price with the selling price as the sale price.
+		price := v.PriceCents
+		if price == 0 {
+			price = product.BasePriceCents
+		}
+		item.PriceCents = price
+		if v.CompareAtCents > price {
+			item.PriceCents = v.CompareAtCents
+			item.SalePriceCents = price
+		}
+
+		images := mergeImages(v.ImageURLs, product.ImageURLs)
+		if len(images) > 0 {
+			item.ImageURL = images[0]
+			extra := images[1:]
+			if len(extra) > maxFeedAdditionalImages {
+				extra = extra[:maxFeedAdditionalImages]
+			}
+			item.AdditionalImageURLs = extra
+		}
+
+		for _, ov := range v.OptionValues {
+			switch strings.ToLower(ov.OptionName) {
+			case "color", "colour":
+				item.Color = ov.Value
+			case "size":
+				item.Size = ov.Value
+			}
+		}
+
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (uc *FeedUseCase) productBrand(ctx context.Context, productID string, lookups *feedLookups) (string, error) {
+	values, err := uc.attributeRepo.GetProductValues(ctx, productID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load attributes of %s: %w", productID, err)
+	}
+	for _, v := range values {
+		if lookups.attrSlugs[v.AttributeID] != brandAttributeSlug {
+			continue
+		}
+		if v.Value != "" {
+			return v.Value, nil
+		}
+		if len(v.Values) > 0 {
+			return v.Values[0], nil
+		}
+	}
+	return "", nil
+}
+
+// googleCategory returns the Google category mapped to a category or, failing
+// that, to its nearest mapped ancestor.
+func (l *feedLookups) googleCategory(categoryID string) string {
+	seen := make(map[string]bool)
+	for id := categoryID; id != "" && !seen[id]; {
+		if g, ok := l.mappings[id]; ok {
+			return g
+		}
+		seen[id] = true
+		c, ok := l.categories[id]
+		if !ok {
+			break
+		}
+		id = c.ParentID
+	}
+	return ""
+}
+
+// categoryPath returns the category breadcrumb, e.g. "Home > Kitchen > Knives".
+func (l *feedLookups) categoryPath(categoryID string) string {
+	var names []string
+	seen := make(map[string]bool)
+	for id := categoryID; id != "" && !seen[id]; {
+		seen[id] = true
+		c, ok := l.categories[id]
+		if !ok {
+			break
+		}
+		names = append([]string{c.Name}, names...)
+		id = c.ParentID
+	}
+	return strings.Join(names, " > ")
+}
+
+// hasAncestor reports whether categoryID is ancestorID or one of its descendants.
+func (l *feedLookups) hasAncestor(categoryID string, ancestorID string) bool {
+	seen := make(map[string]bool)
+	for id := categoryID; id != "" && !seen[id]; {
+		if id == ancestorID {
+			return true
+		}
+		seen[id] = true
+		c, ok := l.categories[id]
+		if !ok {
+			break
+		}
+		id = c.ParentID
+	}
+	return false
+}
+
+// mergeImages returns the variant images followed by the product images,
+// without duplicates.
+func mergeImages(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, list := range lists {
+		for _, u := range list {
+			u = strings.TrimSpace(u)
+			if u == "" || seen[u] {
+				continue
+			}
+			seen[u] = true
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// normalizeGTIN returns the barcode if it is a valid GTIN-8, -12, -13 or -14
+// and empty otherwise, since Merchant Center disapproves items with invalid
+// GTINs but accepts items without one.
+func normalizeGTIN(barcode string) string {
+	code := strings.ReplaceAll(strings.TrimSpace(barcode), " ", "")
+	switch len(code) {
+	case 8, 12, 13, 14:
+	default:
+		return ""
+	}
+
+	sum := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		d := code[i]
+		if d < '0' || d > '9' {
+			return ""
+		}
+		n := int(d - '0')
+		// Weights alternate 1 (check digit), 3, 1, 3... from the right.
+		if (len(code)-1-i)%2 == 1 {
+			n *= 3
+		}
+		sum += n
+	}
+	if sum%10 != 0 {
+		return ""
+	}
+	return code
+}
+
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max])
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/southern-martin/ecommerce/pkg/money"
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// feedChannel describes a feed as a whole.
+type feedChannel struct {
+	Title       string
+	Link        string
+	Description string
+}
+
+// feedWriter writes feed items as they are read.
+type feedWriter interface {
+	Write(item domain.FeedItem) error
+	Close() error
+}
+
+func newFeedWriter(format domain.FeedFormat, w io.Writer, channel feedChannel) (feedWriter, error) {
+	switch format {
+	case domain.FeedFormatGoogleXML:
+		return newGoogleXMLFeedWriter(w, channel)
+	case domain.FeedFormatGoogleTSV:
+		return newGoogleTSVFeedWriter(w)
+	case domain.FeedFormatJSON:
+		return &jsonFeedWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("format must be google_xml, google_tsv or json")
+	}
+}
+
+// googleItem is a feed item in Google Merchant Center attribute names.
+type googleItem struct {
+	ID                    string   `xml:"g:id"`
+	ItemGroupID           string   `xml:"g:item_group_id,omitempty"`
+	Title                 string   `xml:"g:title"`
+	Description           string   `xml:"g:description"`
+	Link                  string   `xml:"g:link"`
+	ImageLink             string   `xml:"g:image_link,omitempty"`
+	AdditionalImageLinks  []string `xml:"g:additional_image_link,omitempty"`
+	Availability          string   `xml:"g:availability"`
+	Price                 string   `xml:"g:price"`
+	SalePrice             string   `xml:"g:sale_price,omitempty"`
+	GTIN                  string   `xml:"g:gtin,omitempty"`
+	MPN                   string   `xml:"g:mpn,omitempty"`
+	Brand                 string   `xml:"g:brand,omitempty"`
+	IdentifierExists      string   `xml:"g:identifier_exists,omitempty"`
+	Condition             string   `xml:"g:condition"`
+	Color                 string   `xml:"g:color,omitempty"`
+	Size                  string   `xml:"g:size,omitempty"`
+	ShippingWeight        string   `xml:"g:shipping_weight,omitempty"`
+	GoogleProductCategory string   `xml:"g:google_product_category,omitempty"`
+	ProductType           string   `xml:"g:product_type,omitempty"`
+}
+
+func toGoogleItem(item domain.FeedItem) googleItem {
+	g := googleItem{
+		ID:                    item.ID,
+		ItemGroupID:           item.ItemGroupID,
+		Title:                 item.Title,
+		Description:           item.Description,
+		Link:                  item.Link,
+		ImageLink:             item.ImageURL,
+		AdditionalImageLinks:  item.AdditionalImageURLs,
+		Availability:          string(item.Availability),
+		Price:                 formatFeedPrice(item.PriceCents, item.Currency),
+		GTIN:                  item.GTIN,
+		MPN:                   item.SKU,
+		Brand:                 item.Brand,
+		Condition:             "new",
+		Color:                 item.Color,
+		Size:                  item.Size,
+		GoogleProductCategory: item.GoogleProductCategory,
+		ProductType:           item.ProductType,
+	}
+	if item.SalePriceCents > 0 {
+		g.SalePrice = formatFeedPrice(item.SalePriceCents, item.Currency)
+	}
+	if item.WeightGrams > 0 {
+		g.ShippingWeight = strconv.Itoa(item.WeightGrams) + " g"
+	}
+	// Without a GTIN or brand the SKU alone is not a manufacturer identifier.
+	if item.GTIN == "" && item.Brand == "" {
+		g.MPN = ""
+		g.IdentifierExists = "no"
+	}
+	return g
+}
+
+// formatFeedPrice formats an amount in minor units the way Merchant Center
+// expects, with the currency's number of decimals, e.g. "12.50 USD" or
+// "1500 JPY".
+func formatFeedPrice(cents int64, currency string) string {
+	decimals := money.Decimals(currency)
+	if decimals == 0 {
+		return fmt.Sprintf("%d %s", cents, currency)
+	}
+	unit := int64(math.Pow10(decimals))
+	return fmt.Sprintf("%d.%0*d %s", cents/unit, decimals, cents%unit, currency)
+}
+
+// googleXMLFeedWriter writes an RSS 2.0 feed with the Google namespace.
+type googleXMLFeedWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+func newGoogleXMLFeedWriter(w io.Writer, channel feedChannel) (*googleXMLFeedWriter, error) {
+	header := xml.Header +
+		`<rss version="2.0" xmlns:g="http://base.google.com/ns/1.0">` + "\n<channel>\n"
+	if _, err := io.WriteString(w, header); err != nil {
+		return nil, err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	for _, el := range []struct{ name, value string }{
+		{"title", channel.Title}, {"link", channel.Link}, {"description", channel.Description},
+	} {
+		if err := enc.EncodeElement(el.value, xml.StartElement{Name: xml.Name{Local: el.name}}); err != nil {
+			return nil, err
+		}
+	}
+	return &googleXMLFeedWriter{w: w, enc: enc}, nil
+}
+
+func (g *googleXMLFeedWriter) Write(item domain.FeedItem) error {
+	return g.enc.EncodeElement(toGoogleItem(item), xml.StartElement{Name: xml.Name{Local: "item"}})
+}
+
+func (g *googleXMLFeedWriter) Close() error {
+	if err := g.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(g.w, "\n</channel>\n</rss>\n")
+	return err
+}
+
+// googleTSVColumns are the Merchant Center attributes written to TSV feeds.
+var googleTSVColumns = []string{
+	"id", "item_group_id", "title", "description", "link", "image_link",
+	"additional_image_link", "availability", "price", "sale_price", "gtin",
+	"mpn", "brand", "identifier_exists", "condition", "color", "size",
+	"shipping_weight", "google_product_category", "product_type",
+}
+
+// googleTSVFeedWriter writes a tab-separated feed. Merchant Center TSV feeds
+// are not quoted, so tabs and line breaks inside values become spaces.
+type googleTSVFeedWriter struct {
+	w io.Writer
+}
+
+func newGoogleTSVFeedWriter(w io.Writer) (*googleTSVFeedWriter, error) {
+	if _, err := io.WriteString(w, strings.Join(googleTSVColumns, "\t")+"\n"); err != nil {
+		return nil, err
+	}
+	return &googleTSVFeedWriter{w: w}, nil
+}
+
+func (t *googleTSVFeedWriter) Write(item domain.FeedItem) error {
+	g := toGoogleItem(item)
+	row := []string{
+		g.ID, g.ItemGroupID, g.Title, g.Description, g.Link, g.ImageLink,
+		strings.Join(g.AdditionalImageLinks, ","), g.Availability, g.Price, g.SalePrice, g.GTIN,
+		g.MPN, g.Brand, g.IdentifierExists, g.Condition, g.Color, g.Size,
+		g.ShippingWeight, g.GoogleProductCategory, g.ProductType,
+	}
+	for i, v := range row {
+		row[i] = tsvReplacer.Replace(v)
+	}
+	_, err := io.WriteString(t.w, strings.Join(row, "\t")+"\n")
+	return err
+}
+
+func (t *googleTSVFeedWriter) Close() error {
+	return nil
+}
+
+var tsvReplacer = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// jsonFeedWriter writes the generic feed as a JSON array of feed items.
+type jsonFeedWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (j *jsonFeedWriter) Write(item domain.FeedItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if j.written == 0 {
+		sep = "[\n"
+	}
+	j.written++
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonFeedWriter) Close() error {
+	end := "\n]\n"
+	if j.written == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
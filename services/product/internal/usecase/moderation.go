@@ -0,0 +1,617 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// systemReviewerID is recorded as the reviewer of reviews decided by the
+// automated checks.
+const systemReviewerID = "system"
+
+// Automated moderation check codes.
+const (
+	checkCategory           = "category"
+	checkRequiredAttributes = "required_attributes"
+	checkImageCount         = "image_count"
+	checkProhibitedKeywords = "prohibited_keywords"
+)
+
+// ModerationUseCase gates product activation behind a review. Sellers submit
+// products for review instead of activating them; significant edits to a
+// live product send it back to the queue until a reviewer approves it.
+type ModerationUseCase struct {
+	productRepo   domain.ProductRepository
+	categoryRepo  domain.CategoryRepository
+	attributeRepo domain.AttributeRepository
+	reviewRepo    domain.ModerationReviewRepository
+	keywordRepo   domain.ProhibitedKeywordRepository
+	eventPub      domain.EventPublisher
+	minImages     int
+}
+
+// NewModerationUseCase creates a new ModerationUseCase. minImages is the
+// number of product images required to pass review.
+func NewModerationUseCase(
+	productRepo domain.ProductRepository,
+	categoryRepo domain.CategoryRepository,
+	attributeRepo domain.AttributeRepository,
+	reviewRepo domain.ModerationReviewRepository,
+	keywordRepo domain.ProhibitedKeywordRepository,
+	eventPub domain.EventPublisher,
+	minImages int,
+) *ModerationUseCase {
+	return &ModerationUseCase{
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+		attributeRepo: attributeRepo,
+		reviewRepo:    reviewRepo,
+		keywordRepo:   keywordRepo,
+		eventPub:      eventPub,
+		minImages:     minImages,
+	}
+}
+
+// inTransaction returns a copy of the use case that reconciles products
+// inside a catalog import's transaction.
+func (uc *ModerationUseCase) inTransaction(repos domain.CatalogRepositories, events domain.EventPublisher) *ModerationUseCase {
+	tx := *uc
+	tx.productRepo = repos.Products
+	tx.categoryRepo = repos.Categories
+	tx.attributeRepo = repos.Attributes
+	tx.reviewRepo = repos.ModerationReviews
+	tx.eventPub = events
+	return &tx
+}
+
+// Reconcile settles the status of a product after a seller change and before
+// it is saved. activate is true when the seller asked for the product to go
+// live. The product goes live directly only when its content matches the
+// last approved review; otherwise it is queued for review, or rejected if a
+// blocking automated check fails. Taking a product offline withdraws its
+// pending review.
+func (uc *ModerationUseCase) Reconcile(ctx context.Context, product *domain.Product, activate bool) error {
+	open, err := uc.reviewRepo.GetOpenByProduct(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load moderation review: %w", err)
+	}
+
+	live := activate || product.Status == domain.ProductStatusActive || product.Status == domain.ProductStatusPendingReview
+	if !live {
+		if open != nil {
+			return uc.withdraw(ctx, open)
+		}
+		return nil
+	}
+
+	values, err := uc.attributeRepo.GetProductValues(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load attribute values: %w", err)
+	}
+	hash := contentHash(product, values)
+
+	approved, err := uc.reviewRepo.GetLatestApproved(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load moderation review: %w", err)
+	}
+	if approved != nil && approved.ContentHash == hash {
+		if open != nil {
+			if err := uc.withdraw(ctx, open); err != nil {
+				return err
+			}
+		}
+		product.Status = domain.ProductStatusActive
+		return nil
+	}
+	if open != nil && open.ContentHash == hash {
+		product.Status = domain.ProductStatusPendingReview
+		return nil
+	}
+
+	checks, err := uc.runChecks(ctx, product, values)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	review := open
+	if review == nil {
+		trigger := domain.ModerationTriggerSubmission
+		if product.Status == domain.ProductStatusActive {
+			trigger = domain.ModerationTriggerSignificantEdit
+		}
+		review = &domain.ModerationReview{
+			ID:        uuid.New().String(),
+			ProductID: product.ID,
+			SellerID:  product.SellerID,
+			Status:    domain.ModerationReviewStatusPending,
+			Trigger:   trigger,
+			CreatedAt: now,
+		}
+	}
+	review.ContentHash = hash
+	review.Checks = checks
+	review.Flagged = false
+	review.UpdatedAt = now
+
+	var reasons []domain.RejectionReason
+	for _, c := range checks {
+		switch {
+		case c.Passed:
+		case c.Blocking:
+			reasons = append(reasons, c.Reason)
+		default:
+			review.Flagged = true
+		}
+	}
+
+	product.Status = domain.ProductStatusPendingReview
+	if len(reasons) > 0 {
+		review.Status = domain.ModerationReviewStatusRejected
+		review.ReviewerID = systemReviewerID
+		review.RejectionReasons = reasons
+		review.Note = "Rejected by automated checks"
+		review.DecidedAt = &now
+		product.Status = domain.ProductStatusRejected
+	}
+
+	if open == nil {
+		err = uc.reviewRepo.Create(ctx, review)
+	} else {
+		err = uc.reviewRepo.Update(ctx, review)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save moderation review: %w", err)
+	}
+	return nil
+}
+
+// Withdraw closes the pending review of a product that is being deleted.
+func (uc *ModerationUseCase) Withdraw(ctx context.Context, productID string) error {
+	open, err := uc.reviewRepo.GetOpenByProduct(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to load moderation review: %w", err)
+	}
+	if open == nil {
+		return nil
+	}
+	return uc.withdraw(ctx, open)
+}
+
+func (uc *ModerationUseCase) withdraw(ctx context.Context, review *domain.ModerationReview) error {
+	now := time.Now().UTC()
+	review.Status = domain.ModerationReviewStatusWithdrawn
+	review.DecidedAt = &now
+	review.UpdatedAt = now
+	if err := uc.reviewRepo.Update(ctx, review); err != nil {
+		return fmt.Errorf("failed to withdraw moderation review: %w", err)
+	}
+	return nil
+}
+
+// ApproveLegacyProducts records a system approval for active products that
+// have never been reviewed, i.e. that went live before moderation existed,
+// so that only significant edits send them to review. It returns the
+// number of products approved.
+func (uc *ModerationUseCase) ApproveLegacyProducts(ctx context.Context) (int, error) {
+	approved := 0
+	filter := domain.ProductFilter{Status: string(domain.ProductStatusActive), SortBy: "newest", PageSize: exportPageSize}
+	for page := 1; ; page++ {
+		filter.Page = page
+		products, total, err := uc.productRepo.List(ctx, filter)
+		if err != nil {
+			return approved, fmt.Errorf("failed to list products: %w", err)
+		}
+
+		for _, product := range products {
+			existing, err := uc.reviewRepo.GetLatestApproved(ctx, product.ID)
+			if err != nil {
+				return approved, fmt.Errorf("failed to load moderation review: %w", err)
+			}
+			if existing != nil {
+				continue
+			}
+			values, err := uc.attributeRepo.GetProductValues(ctx, product.ID)
+			if err != nil {
+				return approved, fmt.Errorf("failed to load attribute values: %w", err)
+			}
+
+			now := time.Now().UTC()
+			review := &domain.ModerationReview{
+				ID:          uuid.New().String(),
+				ProductID:   product.ID,
+				SellerID:    product.SellerID,
+				Status:      domain.ModerationReviewStatusApproved,
+				Trigger:     domain.ModerationTriggerSubmission,
+				ContentHash: contentHash(product, values),
+				ReviewerID:  systemReviewerID,
+				Note:        "Active before moderation was introduced",
+				DecidedAt:   &now,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+			if err := uc.reviewRepo.Create(ctx, review); err != nil {
+				return approved, fmt.Errorf("failed to save moderation review: %w", err)
+			}
+			approved++
+		}
+
+		if int64(page*exportPageSize) >= total || len(products) == 0 {
+			return approved, nil
+		}
+	}
+}
+
+// runChecks runs the automated checks on a product's content.
+func (uc *ModerationUseCase) runChecks(ctx context.Context, product *domain.Product, values []domain.ProductAttributeValue) ([]domain.ModerationCheck, error) {
+	checks := make([]domain.ModerationCheck, 0, 4)
+
+	category := domain.ModerationCheck{
+		Code:     checkCategory,
+		Passed:   product.CategoryID != "",
+		Blocking: true,
+		Reason:   domain.RejectionReasonMissingCategory,
+	}
+	if !category.Passed {
+		category.Message = "product has no category"
+	}
+	checks = append(checks, category)
+
+	if product.CategoryID != "" {
+		schema, err := loadAttributeSchema(ctx, uc.attributeRepo, uc.categoryRepo, product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		filled := make(map[string]bool, len(values))
+		for _, v := range values {
+			if strings.TrimSpace(v.Value) != "" || len(v.Values) > 0 {
+				filled[v.AttributeID] = true
+			}
+		}
+		var missing []string
+		for _, d := range schema.defs {
+			if d.Required && !filled[d.ID] {
+				missing = append(missing, d.Name)
+			}
+		}
+		attrs := domain.ModerationCheck{
+			Code:     checkRequiredAttributes,
+			Passed:   len(missing) == 0,
+			Blocking: true,
+			Reason:   domain.RejectionReasonMissingAttributes,
+		}
+		if !attrs.Passed {
+			attrs.Message = "missing required attributes: " + strings.Join(missing, ", ")
+		}
+		checks = append(checks, attrs)
+	}
+
+	images := len(mergeImages(product.ImageURLs))
+	imageCheck := domain.ModerationCheck{
+		Code:     checkImageCount,
+		Passed:   images >= uc.minImages,
+		Blocking: true,
+		Reason:   domain.RejectionReasonInsufficientImages,
+	}
+	if !imageCheck.Passed {
+		imageCheck.Message = fmt.Sprintf("at least %d images required, found %d", uc.minImages, images)
+	}
+	checks = append(checks, imageCheck)
+
+	keywords, err := uc.keywordRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prohibited keywords: %w", err)
+	}
+	text := moderationText(product, values)
+	var found []string
+	for _, k := range keywords {
+		if containsTerm(text, k.Keyword) {
+			found = append(found, k.Keyword)
+		}
+	}
+	keywordCheck := domain.ModerationCheck{
+		Code:   checkProhibitedKeywords,
+		Passed: len(found) == 0,
+		Reason: domain.RejectionReasonProhibitedContent,
+	}
+	if !keywordCheck.Passed {
+		keywordCheck.Message = "mentions prohibited keywords: " + strings.Join(found, ", ")
+	}
+	checks = append(checks, keywordCheck)
+
+	return checks, nil
+}
+
+// ModerationQueueFilter holds the filters for listing moderation reviews.
+type ModerationQueueFilter struct {
+	Status     string
+	ReviewerID string
+	SellerID   string
+	Page       int
+	PageSize   int
+}
+
+// ListReviews lists moderation reviews, flagged and oldest first. It lists
+// pending reviews unless another status is asked for.
+func (uc *ModerationUseCase) ListReviews(ctx context.Context, filter ModerationQueueFilter) ([]*domain.ModerationReview, int64, error) {
+	if filter.Status == "" {
+		filter.Status = string(domain.ModerationReviewStatusPending)
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = 20
+	}
+	if filter.PageSize > 100 {
+		filter.PageSize = 100
+	}
+	return uc.reviewRepo.List(ctx, domain.ModerationReviewFilter{
+		Status:     filter.Status,
+		ReviewerID: filter.ReviewerID,
+		SellerID:   filter.SellerID,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+	})
+}
+
+// GetReview retrieves a moderation review.
+func (uc *ModerationUseCase) GetReview(ctx context.Context, id string) (*domain.ModerationReview, error) {
+	return uc.reviewRepo.GetByID(ctx, id)
+}
+
+// ListProductReviews lists the moderation history of a seller's product,
+// newest first.
+func (uc *ModerationUseCase) ListProductReviews(ctx context.Context, productID string, sellerID string) ([]*domain.ModerationReview, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("unauthorized: product belongs to another seller")
+	}
+	return uc.reviewRepo.ListByProduct(ctx, productID)
+}
+
+// AssignReview assigns a pending review to a reviewer.
+func (uc *ModerationUseCase) AssignReview(ctx context.Context, id string, reviewerID string) (*domain.ModerationReview, error) {
+	if reviewerID == "" {
+		return nil, fmt.Errorf("reviewer ID is required")
+	}
+	review, err := uc.pendingReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	review.ReviewerID = reviewerID
+	review.AssignedAt = &now
+	review.UpdatedAt = now
+	if err := uc.reviewRepo.Update(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to assign moderation review: %w", err)
+	}
+	return review, nil
+}
+
+// ApproveReview approves a pending review and activates the product.
+func (uc *ModerationUseCase) ApproveReview(ctx context.Context, id string, reviewerID string, note string) (*domain.ModerationReview, error) {
+	review, product, err := uc.decidable(ctx, id, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := uc.attributeRepo.GetProductValues(ctx, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attribute values: %w", err)
+	}
+	if contentHash(product, values) != review.ContentHash {
+		return nil, fmt.Errorf("product changed since the review was opened")
+	}
+
+	now := time.Now().UTC()
+	review.Status = domain.ModerationReviewStatusApproved
+	review.Note = note
+	review.DecidedAt = &now
+	review.UpdatedAt = now
+	if err := uc.reviewRepo.Update(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to approve moderation review: %w", err)
+	}
+
+	if err := uc.setProductStatus(ctx, product, domain.ProductStatusActive, now); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// RejectReview rejects a pending review with at least one reason. The
+// product stays offline until the seller resubmits it.
+func (uc *ModerationUseCase) RejectReview(ctx context.Context, id string, reviewerID string, reasons []domain.RejectionReason, note string) (*domain.ModerationReview, error) {
+	if len(reasons) == 0 {
+		return nil, fmt.Errorf("at least one rejection reason is required")
+	}
+	for _, r := range reasons {
+		if !validRejectionReason(r) {
+			return nil, fmt.Errorf("unknown rejection reason: %s", r)
+		}
+		if r == domain.RejectionReasonOther && strings.TrimSpace(note) == "" {
+			return nil, fmt.Errorf("a note is required when the reason is other")
+		}
+	}
+
+	review, product, err := uc.decidable(ctx, id, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	review.Status = domain.ModerationReviewStatusRejected
+	review.RejectionReasons = reasons
+	review.Note = note
+	review.DecidedAt = &now
+	review.UpdatedAt = now
+	if err := uc.reviewRepo.Update(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to reject moderation review: %w", err)
+	}
+
+	if err := uc.setProductStatus(ctx, product, domain.ProductStatusRejected, now); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (uc *ModerationUseCase) pendingReview(ctx context.Context, id string) (*domain.ModerationReview, error) {
+	review, err := uc.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != domain.ModerationReviewStatusPending {
+		return nil, fmt.Errorf("moderation review is %s, not pending", review.Status)
+	}
+	return review, nil
+}
+
+// decidable loads a pending review the reviewer may decide, assigning it to
+// them if nobody has claimed it yet.
+func (uc *ModerationUseCase) decidable(ctx context.Context, id string, reviewerID string) (*domain.ModerationReview, *domain.Product, error) {
+	if reviewerID == "" {
+		return nil, nil, fmt.Errorf("reviewer ID is required")
+	}
+	review, err := uc.pendingReview(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if review.ReviewerID != "" && review.ReviewerID != reviewerID {
+		return nil, nil, fmt.Errorf("moderation review is assigned to another reviewer")
+	}
+	if review.ReviewerID == "" {
+		now := time.Now().UTC()
+		review.ReviewerID = reviewerID
+		review.AssignedAt = &now
+	}
+
+	product, err := uc.productRepo.GetByID(ctx, review.ProductID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("product not found: %w", err)
+	}
+	return review, product, nil
+}
+
+func (uc *ModerationUseCase) setProductStatus(ctx context.Context, product *domain.Product, status domain.ProductStatus, now time.Time) error {
+	product.Status = status
+	product.UpdatedAt = now
+	if err := uc.productRepo.Update(ctx, product); err != nil {
+		return fmt.Errorf("failed to update product: %w", err)
+	}
+	_ = uc.eventPub.PublishProductUpdated(ctx, product)
+	return nil
+}
+
+// ListKeywords lists the prohibited keywords.
+func (uc *ModerationUseCase) ListKeywords(ctx context.Context) ([]*domain.ProhibitedKeyword, error) {
+	return uc.keywordRepo.List(ctx)
+}
+
+// AddKeyword adds a prohibited keyword. Matching is case-insensitive and on
+// whole words, so a keyword may also be a phrase.
+func (uc *ModerationUseCase) AddKeyword(ctx context.Context, keyword string) (*domain.ProhibitedKeyword, error) {
+	keyword = strings.ToLower(strings.Join(strings.Fields(keyword), " "))
+	if keyword == "" {
+		return nil, fmt.Errorf("keyword is required")
+	}
+	k := &domain.ProhibitedKeyword{
+		ID:        uuid.New().String(),
+		Keyword:   keyword,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := uc.keywordRepo.Create(ctx, k); err != nil {
+		return nil, fmt.Errorf("failed to add keyword: %w", err)
+	}
+	return k, nil
+}
+
+// DeleteKeyword removes a prohibited keyword.
+func (uc *ModerationUseCase) DeleteKeyword(ctx context.Context, id string) error {
+	return uc.keywordRepo.Delete(ctx, id)
+}
+
+// contentHash fingerprints the product content a review covers. Price, stock
+// and variant changes are not part of it and never trigger a re-review.
+func contentHash(product *domain.Product, values []domain.ProductAttributeValue) string {
+	tags := append([]string(nil), product.Tags...)
+	sort.Strings(tags)
+
+	attrs := make([]string, 0, len(values))
+	for _, v := range values {
+		attrs = append(attrs, v.AttributeID+"="+v.Value+"|"+strings.Join(v.Values, "|"))
+	}
+	sort.Strings(attrs)
+
+	data, _ := json.Marshal(struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		CategoryID  string   `json:"category_id"`
+		ImageURLs   []string `json:"image_urls"`
+		Tags        []string `json:"tags"`
+		Attributes  []string `json:"attributes"`
+	}{product.Name, product.Description, product.CategoryID, product.ImageURLs, tags, attrs})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// moderationText is the lower-cased text scanned for prohibited keywords.
+func moderationText(product *domain.Product, values []domain.ProductAttributeValue) string {
+	parts := []string{product.Name, product.Description}
+	parts = append(parts, product.Tags...)
+	for _, v := range values {
+		parts = append(parts, v.Value)
+		parts = append(parts, v.Values...)
+	}
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
+
+// containsTerm reports whether term occurs in text as whole words.
+func containsTerm(text, term string) bool {
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return false
+		}
+		start := offset + i
+		end := start + len(term)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+func validRejectionReason(r domain.RejectionReason) bool {
+	switch r {
+	case domain.RejectionReasonProhibitedContent, domain.RejectionReasonMissingCategory,
+		domain.RejectionReasonMissingAttributes, domain.RejectionReasonInsufficientImages,
+		domain.RejectionReasonMisleadingContent, domain.RejectionReasonCounterfeit,
+		domain.RejectionReasonOther:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,469 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// omnibusWindow is the period over which the lowest previous price is taken
+// under the EU Omnibus directive.
+const omnibusWindow = 30 * 24 * time.Hour
+
+// priceScheduleBatchSize is the number of due schedules applied per query.
+const priceScheduleBatchSize = 100
+
+// PricingUseCase keeps the price history of variants and applies scheduled
+// price changes.
+type PricingUseCase struct {
+	productRepo  domain.ProductRepository
+	variantRepo  domain.VariantRepository
+	historyRepo  domain.PriceHistoryRepository
+	scheduleRepo domain.PriceScheduleRepository
+	eventPub     domain.EventPublisher
+}
+
+// NewPricingUseCase creates a new PricingUseCase.
+func NewPricingUseCase(
+	productRepo domain.ProductRepository,
+	variantRepo domain.VariantRepository,
+	historyRepo domain.PriceHistoryRepository,
+	scheduleRepo domain.PriceScheduleRepository,
+	eventPub domain.EventPublisher,
+) *PricingUseCase {
+	return &PricingUseCase{
+		productRepo:  productRepo,
+		variantRepo:  variantRepo,
+		historyRepo:  historyRepo,
+		scheduleRepo: scheduleRepo,
+		eventPub:     eventPub,
+	}
+}
+
+// inTransaction returns a copy of the use case that records price changes
+// through a transaction's repositories.
+func (uc *PricingUseCase) inTransaction(repos domain.CatalogRepositories, events domain.EventPublisher) *PricingUseCase {
+	tx := *uc
+	tx.productRepo = repos.Products
+	tx.variantRepo = repos.Variants
+	tx.historyRepo = repos.PriceHistory
+	tx.eventPub = events
+	return &tx
+}
+
+// recordPriceChange records a saved change of a variant's prices and
+// publishes it. Nothing is recorded when the prices did not change.
+func (uc *PricingUseCase) recordPriceChange(ctx context.Context, product *domain.Product, variant *domain.Variant, previousPrice, previousCompareAt int64, source domain.PriceChangeSource, scheduleID string) error {
+	if variant.PriceCents == previousPrice && variant.CompareAtCents == previousCompareAt {
+		return nil
+	}
+
+	entry := &domain.PriceHistoryEntry{
+		ID:                     uuid.New().String(),
+		VariantID:              variant.ID,
+		ProductID:              variant.ProductID,
+		PriceCents:             variant.PriceCents,
+		PreviousPriceCents:     previousPrice,
+		CompareAtCents:         variant.CompareAtCents,
+		PreviousCompareAtCents: previousCompareAt,
+		Source:                 source,
+		ScheduleID:             scheduleID,
+		ChangedAt:              time.Now().UTC(),
+	}
+	if err := uc.historyRepo.Create(ctx, entry); err != nil {
+		return fmt.Errorf("failed to record price change: %w", err)
+	}
+
+	_ = uc.eventPub.PublishPriceUpdated(ctx, product, entry)
+
+	return nil
+}
+
+// GetPriceHistory lists the price changes of a variant over the last days.
+func (uc *PricingUseCase) GetPriceHistory(ctx context.Context, productID string, variantID string, days int) ([]*domain.PriceHistoryEntry, error) {
+	if days <= 0 {
+		days = 90
+	}
+	if days > 365 {
+		days = 365
+	}
+	if _, err := uc.variantOf(ctx, productID, variantID); err != nil {
+		return nil, err
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	return uc.historyRepo.ListByVariant(ctx, variantID, since)
+}
+
+// GetPriceSummary returns the current price of a variant with its lowest
+// prices over the Omnibus window.
+func (uc *PricingUseCase) GetPriceSummary(ctx context.Context, productID string, variantID string) (*domain.PriceSummary, error) {
+	variant, err := uc.variantOf(ctx, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+
+	now := time.Now().UTC()
+	summary := &domain.PriceSummary{
+		VariantID:             variant.ID,
+		PriceCents:            variant.PriceCents,
+		CompareAtCents:        variant.CompareAtCents,
+		Currency:              product.Currency,
+		LowestPriceCents:      variant.PriceCents,
+		PriorLowestPriceCents: variant.PriceCents,
+	}
+
+	latest, err := uc.historyRepo.GetLatestAt(ctx, variantID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load price history: %w", err)
+	}
+	if latest == nil {
+		// The price never changed.
+		return summary, nil
+	}
+	since := latest.ChangedAt
+	summary.PriceSince = &since
+
+	// The timeline covers the 30 days before the current price took effect
+	// and the last 30 days, whichever starts earlier.
+	from := latest.ChangedAt.Add(-omnibusWindow)
+	base, err := uc.historyRepo.GetLatestAt(ctx, variantID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load price history: %w", err)
+	}
+	entries, err := uc.historyRepo.ListByVariant(ctx, variantID, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load price history: %w", err)
+	}
+
+	timeline := priceTimeline{entries: entries}
+	switch {
+	case base != nil:
+		timeline.initial = base.PriceCents
+	case len(entries) > 0:
+		timeline.initial = entries[0].PreviousPriceCents
+	default:
+		timeline.initial = variant.PriceCents
+	}
+
+	summary.LowestPriceCents = timeline.lowestBetween(now.Add(-omnibusWindow), now)
+	summary.PriorLowestPriceCents = timeline.lowestBetween(from, latest.ChangedAt)
+	return summary, nil
+}
+
+// priceTimeline is the price of a variant over time: initial until the first
+// entry, then the price of each entry from its change on.
+type priceTimeline struct {
+	initial int64
+	entries []*domain.PriceHistoryEntry // oldest first
+}
+
+// lowestBetween returns the lowest price in effect at any time in [from, to).
+func (t priceTimeline) lowestBetween(from, to time.Time) int64 {
+	current := t.initial
+	for _, e := range t.entries {
+		if e.ChangedAt.After(from) {
+			break
+		}
+		current = e.PriceCents
+	}
+
+	lowest := current
+	for _, e := range t.entries {
+		if !e.ChangedAt.After(from) {
+			continue
+		}
+		if !e.ChangedAt.Before(to) {
+			break
+		}
+		if e.PriceCents < lowest {
+			lowest = e.PriceCents
+		}
+	}
+	return lowest
+}
+
+// SchedulePriceInput holds the input for scheduling a price change.
+type SchedulePriceInput struct {
+	PriceCents     int64
+	CompareAtCents *int64
+	StartsAt       time.Time  // zero starts the schedule now
+	EndsAt         *time.Time // nil makes the change permanent
+}
+
+// SchedulePrice schedules a price change of a variant. Schedules of a variant
+// may not overlap. A schedule starting now is applied immediately.
+func (uc *PricingUseCase) SchedulePrice(ctx context.Context, productID string, variantID string, sellerID string, input SchedulePriceInput) (*domain.PriceSchedule, error) {
+	product, err := uc.sellerProduct(ctx, productID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.variantOf(ctx, productID, variantID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if input.PriceCents < 0 {
+		return nil, fmt.Errorf("price must be non-negative")
+	}
+	if input.CompareAtCents != nil && *input.CompareAtCents < 0 {
+		return nil, fmt.Errorf("compare-at price must be non-negative")
+	}
+	startsAt := input.StartsAt.UTC()
+	if input.StartsAt.IsZero() || startsAt.Before(now) {
+		startsAt = now
+	}
+	var endsAt *time.Time
+	if input.EndsAt != nil {
+		end := input.EndsAt.UTC()
+		if !end.After(startsAt) {
+			return nil, fmt.Errorf("end must be after start")
+		}
+		endsAt = &end
+	}
+
+	existing, err := uc.scheduleRepo.ListByVariant(ctx, variantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price schedules: %w", err)
+	}
+	for _, s := range existing {
+		if s.Status != domain.PriceScheduleStatusScheduled && s.Status != domain.PriceScheduleStatusActive {
+			continue
+		}
+		if overlaps(startsAt, endsAt, s.StartsAt, s.EndsAt) {
+			return nil, fmt.Errorf("overlaps price schedule %s", s.ID)
+		}
+	}
+
+	schedule := &domain.PriceSchedule{
+		ID:             uuid.New().String(),
+		ProductID:      productID,
+		VariantID:      variantID,
+		SellerID:       product.SellerID,
+		PriceCents:     input.PriceCents,
+		CompareAtCents: input.CompareAtCents,
+		StartsAt:       startsAt,
+		EndsAt:         endsAt,
+		Status:         domain.PriceScheduleStatusScheduled,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := uc.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to create price schedule: %w", err)
+	}
+
+	if !startsAt.After(now) {
+		if err := uc.startSchedule(ctx, schedule); err != nil {
+			return nil, err
+		}
+	}
+	return schedule, nil
+}
+
+// ListPriceSchedules lists the price schedules of a variant.
+func (uc *PricingUseCase) ListPriceSchedules(ctx context.Context, productID string, variantID string, sellerID string) ([]*domain.PriceSchedule, error) {
+	if _, err := uc.sellerProduct(ctx, productID, sellerID); err != nil {
+		return nil, err
+	}
+	if _, err := uc.variantOf(ctx, productID, variantID); err != nil {
+		return nil, err
+	}
+	return uc.scheduleRepo.ListByVariant(ctx, variantID)
+}
+
+// CancelPriceSchedule cancels a price schedule. Cancelling a running
+// schedule reverts the variant to its prices from before the schedule.
+func (uc *PricingUseCase) CancelPriceSchedule(ctx context.Context, productID string, scheduleID string, sellerID string) (*domain.PriceSchedule, error) {
+	if _, err := uc.sellerProduct(ctx, productID, sellerID); err != nil {
+		return nil, err
+	}
+	schedule, err := uc.scheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.ProductID != productID {
+		return nil, fmt.Errorf("price schedule does not belong to this product")
+	}
+
+	switch schedule.Status {
+	case domain.PriceScheduleStatusScheduled:
+		schedule.Status = domain.PriceScheduleStatusCancelled
+		schedule.UpdatedAt = time.Now().UTC()
+		ok, err := uc.scheduleRepo.UpdateIfStatus(ctx, schedule, domain.PriceScheduleStatusScheduled)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cancel price schedule: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("price schedule changed, try again")
+		}
+	case domain.PriceScheduleStatusActive:
+		if err := uc.endSchedule(ctx, schedule, domain.PriceScheduleStatusCancelled); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("price schedule is already %s", schedule.Status)
+	}
+	return schedule, nil
+}
+
+// ApplyDueSchedules starts the schedules whose start has passed and ends
+// those whose end has passed. It returns how many were started and ended.
+func (uc *PricingUseCase) ApplyDueSchedules(ctx context.Context) (int, int, error) {
+	started, ended := 0, 0
+
+	// Ending first frees a variant for a schedule that starts as it ends.
+	for {
+		due, err := uc.scheduleRepo.ListDueToEnd(ctx, time.Now().UTC(), priceScheduleBatchSize)
+		if err != nil {
+			return started, ended, fmt.Errorf("failed to list ending price schedules: %w", err)
+		}
+		for _, s := range due {
+			if err := uc.endSchedule(ctx, s, domain.PriceScheduleStatusCompleted); err != nil {
+				return started, ended, err
+			}
+			ended++
+		}
+		if len(due) < priceScheduleBatchSize {
+			break
+		}
+	}
+
+	for {
+		due, err := uc.scheduleRepo.ListDueToStart(ctx, time.Now().UTC(), priceScheduleBatchSize)
+		if err != nil {
+			return started, ended, fmt.Errorf("failed to list starting price schedules: %w", err)
+		}
+		for _, s := range due {
+			if err := uc.startSchedule(ctx, s); err != nil {
+				return started, ended, err
+			}
+			started++
+		}
+		if len(due) < priceScheduleBatchSize {
+			break
+		}
+	}
+
+	return started, ended, nil
+}
+
+// startSchedule applies a schedule's prices to its variant, remembering the
+// prices to go back to. Permanent schedules complete right away.
+func (uc *PricingUseCase) startSchedule(ctx context.Context, schedule *domain.PriceSchedule) error {
+	product, err := uc.productRepo.GetByID(ctx, schedule.ProductID)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+	variant, err := uc.variantRepo.GetByID(ctx, schedule.VariantID)
+	if err != nil {
+		return fmt.Errorf("variant not found: %w", err)
+	}
+
+	now := time.Now().UTC()
+	schedule.RevertPriceCents = variant.PriceCents
+	schedule.RevertCompareAtCents = variant.CompareAtCents
+	schedule.Status = domain.PriceScheduleStatusActive
+	if schedule.EndsAt == nil {
+		schedule.Status = domain.PriceScheduleStatusCompleted
+	}
+	schedule.UpdatedAt = now
+	ok, err := uc.scheduleRepo.UpdateIfStatus(ctx, schedule, domain.PriceScheduleStatusScheduled)
+	if err != nil {
+		return fmt.Errorf("failed to start price schedule: %w", err)
+	}
+	if !ok {
+		// Another runner started or a seller cancelled it.
+		return nil
+	}
+
+	previousPrice, previousCompareAt := variant.PriceCents, variant.CompareAtCents
+	variant.PriceCents = schedule.PriceCents
+	if schedule.CompareAtCents != nil {
+		variant.CompareAtCents = *schedule.CompareAtCents
+	}
+	variant.UpdatedAt = now
+	if err := uc.variantRepo.Update(ctx, variant); err != nil {
+		return fmt.Errorf("failed to update variant price: %w", err)
+	}
+	return uc.recordPriceChange(ctx, product, variant, previousPrice, previousCompareAt, domain.PriceChangeSourceScheduleStart, schedule.ID)
+}
+
+// endSchedule moves a running schedule to status and reverts the variant to
+// its prices from before the schedule. Prices changed since the schedule
+// started are left alone.
+func (uc *PricingUseCase) endSchedule(ctx context.Context, schedule *domain.PriceSchedule, status domain.PriceScheduleStatus) error {
+	product, err := uc.productRepo.GetByID(ctx, schedule.ProductID)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+	variant, err := uc.variantRepo.GetByID(ctx, schedule.VariantID)
+	if err != nil {
+		return fmt.Errorf("variant not found: %w", err)
+	}
+
+	now := time.Now().UTC()
+	schedule.Status = status
+	schedule.UpdatedAt = now
+	ok, err := uc.scheduleRepo.UpdateIfStatus(ctx, schedule, domain.PriceScheduleStatusActive)
+	if err != nil {
+		return fmt.Errorf("failed to end price schedule: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	if variant.PriceCents != schedule.PriceCents {
+		return nil
+	}
+	previousPrice, previousCompareAt := variant.PriceCents, variant.CompareAtCents
+	variant.PriceCents = schedule.RevertPriceCents
+	if schedule.CompareAtCents != nil && variant.CompareAtCents == *schedule.CompareAtCents {
+		variant.CompareAtCents = schedule.RevertCompareAtCents
+	}
+	variant.UpdatedAt = now
+	if err := uc.variantRepo.Update(ctx, variant); err != nil {
+		return fmt.Errorf("failed to update variant price: %w", err)
+	}
+	return uc.recordPriceChange(ctx, product, variant, previousPrice, previousCompareAt, domain.PriceChangeSourceScheduleEnd, schedule.ID)
+}
+
+func (uc *PricingUseCase) sellerProduct(ctx context.Context, productID string, sellerID string) (*domain.Product, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("unauthorized: product belongs to another seller")
+	}
+	return product, nil
+}
+
+func (uc *PricingUseCase) variantOf(ctx context.Context, productID string, variantID string) (*domain.Variant, error) {
+	variant, err := uc.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return nil, fmt.Errorf("variant not found: %w", err)
+	}
+	if variant.ProductID != productID {
+		return nil, fmt.Errorf("variant does not belong to this product")
+	}
+	return variant, nil
+}
+
+// overlaps reports whether two periods intersect. A nil end is open-ended.
+func overlaps(startA time.Time, endA *time.Time, startB time.Time, endB *time.Time) bool {
+	if endA != nil && !endA.After(startB) {
+		return false
+	}
+	if endB != nil && !endB.After(startA) {
+		return false
+	}
+	return true
+}
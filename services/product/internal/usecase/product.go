@@ -19,6 +19,7 @@ type ProductUseCase struct {
 	attributeRepo domain.AttributeRepository
 	optionRepo    domain.OptionRepository
 	variantRepo   domain.VariantRepository
+	moderationUC  *ModerationUseCase
 	eventPub      domain.EventPublisher
 }
 
@@ -29,6 +30,7 @@ func NewProductUseCase(
 	attributeRepo domain.AttributeRepository,
 	optionRepo domain.OptionRepository,
 	variantRepo domain.VariantRepository,
+	moderationUC *ModerationUseCase,
 	eventPub domain.EventPublisher,
 ) *ProductUseCase {
 	return &ProductUseCase{
@@ -37,6 +39,7 @@ func NewProductUseCase(
 		attributeRepo: attributeRepo,
 		optionRepo:    optionRepo,
 		variantRepo:   variantRepo,
+		moderationUC:  moderationUC,
 		eventPub:      eventPub,
 	}
 }
@@ -52,6 +55,12 @@ type CreateProductInput struct {
 	Tags           []string
 	ImageURLs      []string
 	Attributes     []AttributeValueInput
+	// Type defaults to physical. The download settings only apply to
+	// digital products.
+	Type               domain.ProductType
+	DownloadLimit      int
+	DownloadExpiryDays int
+	RequiresLicenseKey bool
 }
 
 // AttributeValueInput holds attribute values for product creation.
@@ -83,42 +92,47 @@ func (uc *ProductUseCase) CreateProduct(ctx context.Context, input CreateProduct
 
 	now := time.Now().UTC()
 	product := &domain.Product{
-		ID:             uuid.New().String(),
-		SellerID:       input.SellerID,
-		CategoryID:     input.CategoryID,
-		Name:           input.Name,
-		Slug:           generateSlug(input.Name),
-		Description:    input.Description,
-		BasePriceCents: input.BasePriceCents,
-		Currency:       input.Currency,
-		Status:         domain.ProductStatusDraft,
-		HasVariants:    false,
-		Tags:           input.Tags,
-		ImageURLs:      input.ImageURLs,
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		ID:                 uuid.New().String(),
+		SellerID:           input.SellerID,
+		CategoryID:         input.CategoryID,
+		Name:               input.Name,
+		Slug:               generateSlug(input.Name),
+		Description:        input.Description,
+		BasePriceCents:     input.BasePriceCents,
+		Currency:           input.Currency,
+		Status:             domain.ProductStatusDraft,
+		Type:               input.Type,
+		HasVariants:        false,
+		Tags:               input.Tags,
+		ImageURLs:          input.ImageURLs,
+		DownloadLimit:      input.DownloadLimit,
+		DownloadExpiryDays: input.DownloadExpiryDays,
+		RequiresLicenseKey: input.RequiresLicenseKey,
+		CreatedAt:          now,
+		UpdatedAt:          now,
 	}
 
 	if product.Currency == "" {
 		product.Currency = "USD"
 	}
+	if product.Type == "" {
+		product.Type = domain.ProductTypePhysical
+	}
+	if err := checkProductType(product); err != nil {
+		return nil, err
+	}
+
+	values, err := uc.validateAttributes(ctx, product.ID, product.CategoryID, attributeValuesFromInput(input.Attributes))
+	if err != nil {
+		return nil, err
+	}
 
 	if err := uc.productRepo.Create(ctx, product); err != nil {
 		return nil, fmt.Errorf("failed to create product: %w", err)
 	}
 
 	// Save attribute values
-	if len(input.Attributes) > 0 {
-		var values []domain.ProductAttributeValue
-		for _, av := range input.Attributes {
-			values = append(values, domain.ProductAttributeValue{
-				ID:          uuid.New().String(),
-				ProductID:   product.ID,
-				AttributeID: av.AttributeID,
-				Value:       av.Value,
-				Values:      av.Values,
-			})
-		}
+	if len(values) > 0 {
 		if err := uc.attributeRepo.SetProductValues(ctx, product.ID, values); err != nil {
 			return nil, fmt.Errorf("failed to set attribute values: %w", err)
 		}
@@ -158,19 +172,31 @@ func (uc *ProductUseCase) ListProducts(ctx context.Context, filter domain.Produc
 	if filter.PageSize > 100 {
 		filter.PageSize = 100
 	}
+	if filter.IncludeSubcategories && filter.CategoryID != "" {
+		categories, err := uc.categoryRepo.List(ctx)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list categories: %w", err)
+		}
+		filter.CategoryIDs = descendantCategoryIDs(categories, filter.CategoryID)
+	}
 	return uc.productRepo.List(ctx, filter)
 }
 
 // UpdateProductInput holds the input for updating a product.
 type UpdateProductInput struct {
-	Name           *string
-	Description    *string
-	BasePriceCents *int64
-	Currency       *string
-	Status         *domain.ProductStatus
-	Tags           []string
-	ImageURLs      []string
-	CategoryID     *string
+	Name               *string
+	Description        *string
+	BasePriceCents     *int64
+	Currency           *string
+	Status             *domain.ProductStatus
+	Tags               []string
+	ImageURLs          []string
+	CategoryID         *string
+	Attributes         []AttributeValueInput // nil leaves attribute values unchanged
+	Type               *domain.ProductType
+	DownloadLimit      *int
+	DownloadExpiryDays *int
+	RequiresLicenseKey *bool
 }
 
 // UpdateProduct updates an existing product.
@@ -184,6 +210,20 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id string, sellerID
 		return nil, fmt.Errorf("unauthorized: product belongs to another seller")
 	}
 
+	// Sellers cannot activate a product themselves: asking for active
+	// submits it for moderation.
+	activate := false
+	if input.Status != nil {
+		switch *input.Status {
+		case domain.ProductStatusActive, domain.ProductStatusPendingReview:
+			activate = true
+		case domain.ProductStatusDraft, domain.ProductStatusInactive, domain.ProductStatusArchived:
+			product.Status = *input.Status
+		default:
+			return nil, fmt.Errorf("invalid status: %s", *input.Status)
+		}
+	}
+
 	if input.Name != nil {
 		product.Name = *input.Name
 		product.Slug = generateSlug(*input.Name)
@@ -197,20 +237,65 @@ func (uc *ProductUseCase) UpdateProduct(ctx context.Context, id string, sellerID
 	if input.Currency != nil {
 		product.Currency = *input.Currency
 	}
-	if input.Status != nil {
-		product.Status = *input.Status
-	}
 	if input.Tags != nil {
 		product.Tags = input.Tags
 	}
 	if input.ImageURLs != nil {
 		product.ImageURLs = input.ImageURLs
 	}
-	if input.CategoryID != nil {
+	if input.Type != nil {
+		product.Type = *input.Type
+	}
+	if input.DownloadLimit != nil {
+		product.DownloadLimit = *input.DownloadLimit
+	}
+	if input.DownloadExpiryDays != nil {
+		product.DownloadExpiryDays = *input.DownloadExpiryDays
+	}
+	if input.RequiresLicenseKey != nil {
+		product.RequiresLicenseKey = *input.RequiresLicenseKey
+	}
+	if err := checkProductType(product); err != nil {
+		return nil, err
+	}
+	categoryChanged := input.CategoryID != nil && *input.CategoryID != product.CategoryID
+	if categoryChanged {
+		if *input.CategoryID != "" {
+			if _, err := uc.categoryRepo.GetByID(ctx, *input.CategoryID); err != nil {
+				return nil, fmt.Errorf("category not found: %w", err)
+			}
+		}
 		product.CategoryID = *input.CategoryID
 	}
+
+	// Attribute values are checked when they are replaced and, because a
+	// new category brings a different schema, when the category changes.
+	var values []domain.ProductAttributeValue
+	if input.Attributes != nil || categoryChanged {
+		values = attributeValuesFromInput(input.Attributes)
+		if input.Attributes == nil {
+			values, err = uc.attributeRepo.GetProductValues(ctx, product.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get attribute values: %w", err)
+			}
+		}
+		values, err = uc.validateAttributes(ctx, product.ID, product.CategoryID, values)
+		if err != nil {
+			return nil, err
+		}
+	}
 	product.UpdatedAt = time.Now().UTC()
 
+	if values != nil {
+		if err := uc.attributeRepo.SetProductValues(ctx, product.ID, values); err != nil {
+			return nil, fmt.Errorf("failed to set attribute values: %w", err)
+		}
+	}
+
+	if err := uc.moderationUC.Reconcile(ctx, product, activate); err != nil {
+		return nil, err
+	}
+
 	if err := uc.productRepo.Update(ctx, product); err != nil {
 		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
@@ -231,6 +316,10 @@ func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id string, sellerID
 		return fmt.Errorf("unauthorized: product belongs to another seller")
 	}
 
+	if err := uc.moderationUC.Withdraw(ctx, id); err != nil {
+		return err
+	}
+
 	if err := uc.productRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete product: %w", err)
 	}
@@ -240,6 +329,56 @@ func (uc *ProductUseCase) DeleteProduct(ctx context.Context, id string, sellerID
 	return nil
 }
 
+// checkProductType validates the type of a product and its download
+// settings, which only digital products may have.
+func checkProductType(p *domain.Product) error {
+	switch p.Type {
+	case domain.ProductTypePhysical:
+		if p.DownloadLimit != 0 || p.DownloadExpiryDays != 0 || p.RequiresLicenseKey {
+			return fmt.Errorf("download settings are only allowed on digital products")
+		}
+	case domain.ProductTypeDigital:
+		if p.DownloadLimit < 0 {
+			return fmt.Errorf("download limit must be non-negative")
+		}
+		if p.DownloadExpiryDays < 0 {
+			return fmt.Errorf("download expiry days must be non-negative")
+		}
+	default:
+		return fmt.Errorf("invalid product type: %s", p.Type)
+	}
+	return nil
+}
+
+// validateAttributes checks attribute values against the schema of a
+// category and returns them normalized and bound to the product.
+func (uc *ProductUseCase) validateAttributes(ctx context.Context, productID, categoryID string, values []domain.ProductAttributeValue) ([]domain.ProductAttributeValue, error) {
+	schema, err := loadAttributeSchema(ctx, uc.attributeRepo, uc.categoryRepo, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	values, err = schema.validate(values)
+	if err != nil {
+		return nil, err
+	}
+	for i := range values {
+		values[i].ProductID = productID
+	}
+	return values, nil
+}
+
+func attributeValuesFromInput(inputs []AttributeValueInput) []domain.ProductAttributeValue {
+	values := make([]domain.ProductAttributeValue, 0, len(inputs))
+	for _, av := range inputs {
+		values = append(values, domain.ProductAttributeValue{
+			AttributeID: av.AttributeID,
+			Value:       av.Value,
+			Values:      av.Values,
+		})
+	}
+	return values
+}
+
 // generateSlug creates a URL-friendly slug from a name with a short UUID suffix.
 func generateSlug(name string) string {
 	slug := strings.ToLower(name)
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// ratingPageSize is the number of products rebuilt per page.
+const ratingPageSize = 100
+
+// reviewStatusApproved is the review service status of reviews that count
+// towards ratings.
+const reviewStatusApproved = "approved"
+
+// RatingUseCase keeps product and seller ratings in line with the approved
+// reviews held by the review service.
+type RatingUseCase struct {
+	productRepo domain.ProductRepository
+	reviews     domain.ReviewSummaryProvider
+	eventPub    domain.EventPublisher
+}
+
+// NewRatingUseCase creates a new RatingUseCase.
+func NewRatingUseCase(productRepo domain.ProductRepository, reviews domain.ReviewSummaryProvider, eventPub domain.EventPublisher) *RatingUseCase {
+	return &RatingUseCase{
+		productRepo: productRepo,
+		reviews:     reviews,
+		eventPub:    eventPub,
+	}
+}
+
+// ReviewChange is a review as it was before and after a review event. A
+// deleted review has no status after the event.
+type ReviewChange struct {
+	ProductID      string
+	Rating         int
+	PreviousRating int
+	Status         string
+	PreviousStatus string
+}
+
+// ApplyReviewChange updates the rating of the reviewed product by what the
+// review adds or takes away, and publishes the new product and seller
+// ratings. It reports whether the rating changed.
+func (uc *RatingUseCase) ApplyReviewChange(ctx context.Context, change ReviewChange) (bool, error) {
+	count, rating := reviewContribution(change.Status, change.Rating)
+	previousCount, previousRating := reviewContribution(change.PreviousStatus, change.PreviousRating)
+	if count == previousCount && rating == previousRating {
+		return false, nil
+	}
+
+	if err := uc.productRepo.AdjustRating(ctx, change.ProductID, count-previousCount, rating-previousRating); err != nil {
+		return false, fmt.Errorf("failed to adjust rating: %w", err)
+	}
+	product, err := uc.productRepo.GetByID(ctx, change.ProductID)
+	if err != nil {
+		return true, fmt.Errorf("product not found: %w", err)
+	}
+	return true, uc.publishRating(ctx, product, map[string]*domain.SellerRating{})
+}
+
+// RebuildRatings recomputes the rating of every product from the review
+// service's summaries, repairing anything the incremental updates missed.
+// It returns the number of products whose rating changed.
+func (uc *RatingUseCase) RebuildRatings(ctx context.Context) (int, error) {
+	var changed []*domain.Product
+	for page := 1; ; page++ {
+		products, total, err := uc.productRepo.List(ctx, domain.ProductFilter{
+			SortBy:   "newest",
+			Page:     page,
+			PageSize: ratingPageSize,
+		})
+		if err != nil {
+			return len(changed), fmt.Errorf("failed to list products: %w", err)
+		}
+		for _, product := range products {
+			ok, err := uc.rebuildProduct(ctx, product)
+			if err != nil {
+				return len(changed), err
+			}
+			if ok {
+				changed = append(changed, product)
+			}
+		}
+		if int64(page*ratingPageSize) >= total || len(products) == 0 {
+			break
+		}
+	}
+
+	// Seller ratings are rolled up once all products are rebuilt
+	sellers := make(map[string]*domain.SellerRating)
+	for _, product := range changed {
+		if err := uc.publishRating(ctx, product, sellers); err != nil {
+			return len(changed), err
+		}
+	}
+	return len(changed), nil
+}
+
+// RebuildProductRating recomputes the rating of one product from the review
+// service's summary.
+func (uc *RatingUseCase) RebuildProductRating(ctx context.Context, productID string) (*domain.Product, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	ok, err := uc.rebuildProduct(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if err := uc.publishRating(ctx, product, map[string]*domain.SellerRating{}); err != nil {
+			return nil, err
+		}
+	}
+	return product, nil
+}
+
+// rebuildProduct sets the rating of a product from its review summary and
+// reports whether it changed.
+func (uc *RatingUseCase) rebuildProduct(ctx context.Context, product *domain.Product) (bool, error) {
+	summary, err := uc.reviews.GetProductSummary(ctx, product.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get review summary of %s: %w", product.ID, err)
+	}
+
+	// The distribution gives the exact average; the summary's own average
+	// is only a fallback.
+	count, total := 0, 0
+	for stars, n := range summary.RatingDistribution {
+		count += n
+		total += stars * n
+	}
+	avg := summary.AverageRating
+	if count > 0 {
+		avg = float64(total) / float64(count)
+	} else {
+		count = summary.TotalReviews
+	}
+
+	if count == product.RatingCount && math.Abs(avg-product.RatingAvg) < 1e-9 {
+		return false, nil
+	}
+	if err := uc.productRepo.SetRating(ctx, product.ID, avg, count); err != nil {
+		return false, fmt.Errorf("failed to set rating of %s: %w", product.ID, err)
+	}
+	product.RatingAvg = avg
+	product.RatingCount = count
+	return true, nil
+}
+
+// publishRating publishes the rating of a product together with the rolled
+// up rating of its seller. Seller ratings are cached in sellers.
+func (uc *RatingUseCase) publishRating(ctx context.Context, product *domain.Product, sellers map[string]*domain.SellerRating) error {
+	seller, ok := sellers[product.SellerID]
+	if !ok {
+		var err error
+		seller, err = uc.productRepo.GetSellerRating(ctx, product.SellerID)
+		if err != nil {
+			return fmt.Errorf("failed to get seller rating: %w", err)
+		}
+		sellers[product.SellerID] = seller
+	}
+	_ = uc.eventPub.PublishRatingUpdated(ctx, product, seller)
+	return nil
+}
+
+// reviewContribution is what a review adds to its product's rating: only
+// approved reviews count.
+func reviewContribution(status string, rating int) (int, int) {
+	if status != reviewStatusApproved {
+		return 0, 0
+	}
+	return 1, rating
+}
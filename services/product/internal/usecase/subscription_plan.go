@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// maxIntervalCount bounds how far apart subscription deliveries can be in
+// each interval unit: at most a year.
+var maxIntervalCount = map[domain.SubscriptionInterval]int{
+	domain.SubscriptionIntervalDay:   365,
+	domain.SubscriptionIntervalWeek:  52,
+	domain.SubscriptionIntervalMonth: 12,
+}
+
+// SubscriptionPlanUseCase manages the subscription plans sellers offer on
+// variants and presents them to buyers.
+type SubscriptionPlanUseCase struct {
+	productRepo domain.ProductRepository
+	variantRepo domain.VariantRepository
+	planRepo    domain.SubscriptionPlanRepository
+}
+
+// NewSubscriptionPlanUseCase creates a new SubscriptionPlanUseCase.
+func NewSubscriptionPlanUseCase(
+	productRepo domain.ProductRepository,
+	variantRepo domain.VariantRepository,
+	planRepo domain.SubscriptionPlanRepository,
+) *SubscriptionPlanUseCase {
+	return &SubscriptionPlanUseCase{
+		productRepo: productRepo,
+		variantRepo: variantRepo,
+		planRepo:    planRepo,
+	}
+}
+
+// CreateSubscriptionPlanInput holds the terms of a new subscription plan.
+type CreateSubscriptionPlanInput struct {
+	IntervalUnit  domain.SubscriptionInterval
+	IntervalCount int
+	PercentOff    int64
+}
+
+// UpdateSubscriptionPlanInput holds the changes to a subscription plan.
+// Existing subscriptions keep the terms they were started on.
+type UpdateSubscriptionPlanInput struct {
+	IntervalUnit  *domain.SubscriptionInterval
+	IntervalCount *int
+	PercentOff    *int64
+	IsActive      *bool
+}
+
+// CreatePlan offers a variant on a repeat delivery plan.
+func (uc *SubscriptionPlanUseCase) CreatePlan(ctx context.Context, productID string, variantID string, sellerID string, input CreateSubscriptionPlanInput) (*domain.SubscriptionPlan, error) {
+	product, err := uc.sellerProduct(ctx, productID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsDigital() {
+		return nil, fmt.Errorf("subscription plans are only available for physical products")
+	}
+	if _, err := uc.variantOf(ctx, productID, variantID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	plan := &domain.SubscriptionPlan{
+		ID:            uuid.New().String(),
+		ProductID:     productID,
+		VariantID:     variantID,
+		IntervalUnit:  input.IntervalUnit,
+		IntervalCount: input.IntervalCount,
+		PercentOff:    input.PercentOff,
+		IsActive:      true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := validatePlan(plan); err != nil {
+		return nil, err
+	}
+	if err := uc.planRepo.Create(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to create subscription plan: %w", err)
+	}
+	return plan, nil
+}
+
+// UpdatePlan changes a subscription plan's terms or takes it on or off
+// offer.
+func (uc *SubscriptionPlanUseCase) UpdatePlan(ctx context.Context, productID string, planID string, sellerID string, input UpdateSubscriptionPlanInput) (*domain.SubscriptionPlan, error) {
+	plan, err := uc.sellerPlan(ctx, productID, planID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.IntervalUnit != nil {
+		plan.IntervalUnit = *input.IntervalUnit
+	}
+	if input.IntervalCount != nil {
+		plan.IntervalCount = *input.IntervalCount
+	}
+	if input.PercentOff != nil {
+		plan.PercentOff = *input.PercentOff
+	}
+	if input.IsActive != nil {
+		plan.IsActive = *input.IsActive
+	}
+	if err := validatePlan(plan); err != nil {
+		return nil, err
+	}
+
+	plan.UpdatedAt = time.Now().UTC()
+	if err := uc.planRepo.Update(ctx, plan); err != nil {
+		return nil, fmt.Errorf("failed to update subscription plan: %w", err)
+	}
+	return plan, nil
+}
+
+// DeletePlan removes a subscription plan. Existing subscriptions carry on
+// with the terms they were started on.
+func (uc *SubscriptionPlanUseCase) DeletePlan(ctx context.Context, productID string, planID string, sellerID string) error {
+	if _, err := uc.sellerPlan(ctx, productID, planID, sellerID); err != nil {
+		return err
+	}
+	return uc.planRepo.Delete(ctx, planID)
+}
+
+// ListSellerPlans lists all of a seller's plans for a product, including
+// inactive ones.
+func (uc *SubscriptionPlanUseCase) ListSellerPlans(ctx context.Context, productID string, sellerID string) ([]*domain.SubscriptionPlan, error) {
+	if _, err := uc.sellerProduct(ctx, productID, sellerID); err != nil {
+		return nil, err
+	}
+	return uc.planRepo.ListByProduct(ctx, productID, false)
+}
+
+// ListPlans lists the plans buyers can subscribe to for a product.
+func (uc *SubscriptionPlanUseCase) ListPlans(ctx context.Context, productID string) ([]*domain.SubscriptionPlan, error) {
+	plans, err := uc.planRepo.ListByProduct(ctx, productID, true)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := uc.variantRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]domain.Variant, len(variants))
+	for _, v := range variants {
+		byID[v.ID] = v
+	}
+	for _, plan := range plans {
+		if v, ok := byID[plan.VariantID]; ok {
+			plan.VariantName = v.Name
+			plan.SKU = v.SKU
+		}
+	}
+	return plans, nil
+}
+
+// GetPlan returns an active plan of a product for buyers to subscribe to.
+func (uc *SubscriptionPlanUseCase) GetPlan(ctx context.Context, productID string, planID string) (*domain.SubscriptionPlan, error) {
+	plan, err := uc.planRepo.GetByID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.ProductID != productID || !plan.IsActive {
+		return nil, fmt.Errorf("subscription plan not found")
+	}
+	variant, err := uc.variantOf(ctx, productID, plan.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	plan.VariantName = variant.Name
+	plan.SKU = variant.SKU
+	return plan, nil
+}
+
+func validatePlan(plan *domain.SubscriptionPlan) error {
+	if !plan.IntervalUnit.IsValid() {
+		return fmt.Errorf("invalid interval unit: %q", plan.IntervalUnit)
+	}
+	if limit := maxIntervalCount[plan.IntervalUnit]; plan.IntervalCount < 1 || plan.IntervalCount > limit {
+		return fmt.Errorf("interval count must be between 1 and %d for %s intervals", limit, plan.IntervalUnit)
+	}
+	if plan.PercentOff < 0 || plan.PercentOff >= 10000 {
+		return fmt.Errorf("percent off must be at least 0 and below 10000 (100%%)")
+	}
+	return nil
+}
+
+func (uc *SubscriptionPlanUseCase) sellerProduct(ctx context.Context, productID string, sellerID string) (*domain.Product, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("unauthorized: product belongs to another seller")
+	}
+	return product, nil
+}
+
+func (uc *SubscriptionPlanUseCase) sellerPlan(ctx context.Context, productID string, planID string, sellerID string) (*domain.SubscriptionPlan, error) {
+	if _, err := uc.sellerProduct(ctx, productID, sellerID); err != nil {
+		return nil, err
+	}
+	plan, err := uc.planRepo.GetByID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.ProductID != productID {
+		return nil, fmt.Errorf("subscription plan does not belong to this product")
+	}
+	return plan, nil
+}
+
+func (uc *SubscriptionPlanUseCase) variantOf(ctx context.Context, productID string, variantID string) (*domain.Variant, error) {
+	variant, err := uc.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return nil, fmt.Errorf("variant not found: %w", err)
+	}
+	if variant.ProductID != productID {
+		return nil, fmt.Errorf("variant does not belong to this product")
+	}
+	return variant, nil
+}
@@ -0,0 +1,449 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/southern-martin/ecommerce/pkg/i18n"
+	"github.com/southern-martin/ecommerce/services/product/internal/domain"
+)
+
+// TranslationUseCase manages localized catalog content and resolves what a
+// request sees in its locale. Content is looked up along the locale's
+// i18n fallback chain and falls back to the base (untranslated) content.
+type TranslationUseCase struct {
+	productRepo     domain.ProductRepository
+	categoryRepo    domain.CategoryRepository
+	optionRepo      domain.OptionRepository
+	translationRepo domain.TranslationRepository
+	bundle          *i18n.Bundle
+}
+
+// NewTranslationUseCase creates a new TranslationUseCase.
+func NewTranslationUseCase(
+	productRepo domain.ProductRepository,
+	categoryRepo domain.CategoryRepository,
+	optionRepo domain.OptionRepository,
+	translationRepo domain.TranslationRepository,
+	bundle *i18n.Bundle,
+) *TranslationUseCase {
+	return &TranslationUseCase{
+		productRepo:     productRepo,
+		categoryRepo:    categoryRepo,
+		optionRepo:      optionRepo,
+		translationRepo: translationRepo,
+		bundle:          bundle,
+	}
+}
+
+// ProductTranslationInput holds a product's content in one locale. Slug is
+// generated from the name when empty.
+type ProductTranslationInput struct {
+	Name        string
+	Description string
+	Slug        string
+}
+
+// SetProductTranslation creates or replaces a product's translation in a
+// locale. An existing translation keeps its slug unless a new one is given,
+// so localized URLs stay stable when the text is edited.
+func (uc *TranslationUseCase) SetProductTranslation(ctx context.Context, productID, sellerID, locale string, input ProductTranslationInput) (*domain.ProductTranslation, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("unauthorized: product belongs to another seller")
+	}
+	locale, err = uc.normalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name == "" && input.Description == "" {
+		return nil, fmt.Errorf("translation needs a name or description")
+	}
+
+	slug := slugify(input.Slug)
+	if slug == "" {
+		existing, err := uc.translationRepo.ListProductTranslations(ctx, productID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list translations: %w", err)
+		}
+		for _, t := range existing {
+			if t.Locale == locale {
+				slug = t.Slug
+			}
+		}
+	}
+	if slug == "" {
+		name := input.Name
+		if name == "" {
+			name = product.Name
+		}
+		slug = localizedSlug(name)
+	}
+
+	t := &domain.ProductTranslation{
+		ProductID:   productID,
+		Locale:      locale,
+		Name:        input.Name,
+		Slug:        slug,
+		Description: input.Description,
+		UpdatedAt:   time.Now().UTC(),
+	}
+	if err := uc.translationRepo.UpsertProductTranslation(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+	return t, nil
+}
+
+// ListProductTranslations lists every translation of a product.
+func (uc *TranslationUseCase) ListProductTranslations(ctx context.Context, productID, sellerID string) ([]*domain.ProductTranslation, error) {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID != sellerID {
+		return nil, fmt.Errorf("unauthorized: product belongs to another seller")
+	}
+	return uc.translationRepo.ListProductTranslations(ctx, productID)
+}
+
+// GetProductTranslations lists every translation of a product for other
+// services, without checking who owns it.
+func (uc *TranslationUseCase) GetProductTranslations(ctx context.Context, productID string) ([]*domain.ProductTranslation, error) {
+	return uc.translationRepo.ListProductTranslations(ctx, productID)
+}
+
+// DeleteProductTranslation removes a product's translation in a locale.
+func (uc *TranslationUseCase) DeleteProductTranslation(ctx context.Context, productID, sellerID, locale string) error {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID != sellerID {
+		return fmt.Errorf("unauthorized: product belongs to another seller")
+	}
+	locale, err = uc.normalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+	return uc.translationRepo.DeleteProductTranslation(ctx, productID, locale)
+}
+
+// CategoryTranslationInput holds a category's name in one locale. Slug is
+// generated from the name when empty.
+type CategoryTranslationInput struct {
+	Name string
+	Slug string
+}
+
+// SetCategoryTranslation creates or replaces a category's translation in a
+// locale.
+func (uc *TranslationUseCase) SetCategoryTranslation(ctx context.Context, categoryID, locale string, input CategoryTranslationInput) (*domain.CategoryTranslation, error) {
+	if _, err := uc.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, fmt.Errorf("category not found: %w", err)
+	}
+	locale, err := uc.normalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name == "" {
+		return nil, fmt.Errorf("category name is required")
+	}
+
+	slug := slugify(input.Slug)
+	if slug == "" {
+		slug = slugify(input.Name)
+	}
+	if slug == "" {
+		return nil, fmt.Errorf("category slug is required")
+	}
+
+	t := &domain.CategoryTranslation{
+		CategoryID: categoryID,
+		Locale:     locale,
+		Name:       input.Name,
+		Slug:       slug,
+		UpdatedAt:  time.Now().UTC(),
+	}
+	if err := uc.translationRepo.UpsertCategoryTranslation(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+	return t, nil
+}
+
+// DeleteCategoryTranslation removes a category's translation in a locale.
+func (uc *TranslationUseCase) DeleteCategoryTranslation(ctx context.Context, categoryID, locale string) error {
+	locale, err := uc.normalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+	return uc.translationRepo.DeleteCategoryTranslation(ctx, categoryID, locale)
+}
+
+// SetOptionValueTranslation creates or replaces the translation of one of a
+// product's option values in a locale.
+func (uc *TranslationUseCase) SetOptionValueTranslation(ctx context.Context, productID, sellerID, valueID, locale, value string) (*domain.OptionValueTranslation, error) {
+	if err := uc.checkOptionValue(ctx, productID, sellerID, valueID); err != nil {
+		return nil, err
+	}
+	locale, err := uc.normalizeLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return nil, fmt.Errorf("option value is required")
+	}
+
+	t := &domain.OptionValueTranslation{
+		OptionValueID: valueID,
+		Locale:        locale,
+		Value:         value,
+		UpdatedAt:     time.Now().UTC(),
+	}
+	if err := uc.translationRepo.UpsertOptionValueTranslation(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to save translation: %w", err)
+	}
+	return t, nil
+}
+
+// DeleteOptionValueTranslation removes an option value's translation in a
+// locale.
+func (uc *TranslationUseCase) DeleteOptionValueTranslation(ctx context.Context, productID, sellerID, valueID, locale string) error {
+	if err := uc.checkOptionValue(ctx, productID, sellerID, valueID); err != nil {
+		return err
+	}
+	locale, err := uc.normalizeLocale(locale)
+	if err != nil {
+		return err
+	}
+	return uc.translationRepo.DeleteOptionValueTranslation(ctx, valueID, locale)
+}
+
+// GetProductBySlug finds a product by a slug in the request's locale chain,
+// then in any other locale, and finally by its base slug, so localized links
+// keep working whatever language they are opened in.
+func (uc *TranslationUseCase) GetProductBySlug(ctx context.Context, locale, slug string) (*domain.Product, error) {
+	productID := ""
+	for _, l := range append(uc.bundle.FallbackChain(locale), "") {
+		if t, err := uc.translationRepo.GetProductTranslationBySlug(ctx, l, slug); err == nil {
+			productID = t.ProductID
+			break
+		}
+	}
+
+	var product *domain.Product
+	var err error
+	if productID != "" {
+		product, err = uc.productRepo.GetByID(ctx, productID)
+	} else {
+		product, err = uc.productRepo.GetBySlug(ctx, slug)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if err := uc.LocalizeProducts(ctx, locale, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// LocalizeProducts replaces the name, slug and description of products with
+// their translations for locale. Each field falls back separately, so a
+// translation may leave e.g. the description to the next locale.
+func (uc *TranslationUseCase) LocalizeProducts(ctx context.Context, locale string, products ...*domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	chain := uc.bundle.FallbackChain(locale)
+	ids := make([]string, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+	translations, err := uc.translationRepo.ListProductTranslationsIn(ctx, ids, chain)
+	if err != nil {
+		return fmt.Errorf("failed to load translations: %w", err)
+	}
+
+	byProduct := make(map[string]map[string]*domain.ProductTranslation)
+	for _, t := range translations {
+		if byProduct[t.ProductID] == nil {
+			byProduct[t.ProductID] = make(map[string]*domain.ProductTranslation)
+		}
+		byProduct[t.ProductID][t.Locale] = t
+	}
+	for _, p := range products {
+		byLocale := byProduct[p.ID]
+		// Walk the chain from its end so nearer locales win.
+		for i := len(chain) - 1; i >= 0; i-- {
+			t, ok := byLocale[chain[i]]
+			if !ok {
+				continue
+			}
+			if t.Name != "" {
+				p.Name = t.Name
+			}
+			if t.Slug != "" {
+				p.Slug = t.Slug
+			}
+			if t.Description != "" {
+				p.Description = t.Description
+			}
+		}
+	}
+	return nil
+}
+
+// LocalizeCategories replaces the name and slug of categories with their
+// translations for locale.
+func (uc *TranslationUseCase) LocalizeCategories(ctx context.Context, locale string, categories ...*domain.Category) error {
+	if len(categories) == 0 {
+		return nil
+	}
+	chain := uc.bundle.FallbackChain(locale)
+	translations, err := uc.translationRepo.ListCategoryTranslations(ctx, chain)
+	if err != nil {
+		return fmt.Errorf("failed to load translations: %w", err)
+	}
+
+	byCategory := make(map[string]map[string]*domain.CategoryTranslation)
+	for _, t := range translations {
+		if byCategory[t.CategoryID] == nil {
+			byCategory[t.CategoryID] = make(map[string]*domain.CategoryTranslation)
+		}
+		byCategory[t.CategoryID][t.Locale] = t
+	}
+	for _, c := range categories {
+		byLocale := byCategory[c.ID]
+		if byLocale == nil {
+			continue
+		}
+		for _, l := range chain {
+			if t, ok := byLocale[l]; ok {
+				c.Name = t.Name
+				c.Slug = t.Slug
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// LocalizeCategoryTree localizes every category in a category tree.
+func (uc *TranslationUseCase) LocalizeCategoryTree(ctx context.Context, locale string, nodes []*domain.CategoryNode) error {
+	var categories []*domain.Category
+	var collect func([]*domain.CategoryNode)
+	collect = func(nodes []*domain.CategoryNode) {
+		for _, n := range nodes {
+			categories = append(categories, n.Category)
+			collect(n.Children)
+		}
+	}
+	collect(nodes)
+	return uc.LocalizeCategories(ctx, locale, categories...)
+}
+
+// ListOptions lists a product's options with their values in locale.
+func (uc *TranslationUseCase) ListOptions(ctx context.Context, productID, locale string) ([]domain.ProductOption, error) {
+	options, err := uc.optionRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list options: %w", err)
+	}
+
+	var valueIDs []string
+	for _, o := range options {
+		for _, v := range o.Values {
+			valueIDs = append(valueIDs, v.ID)
+		}
+	}
+	chain := uc.bundle.FallbackChain(locale)
+	translations, err := uc.translationRepo.ListOptionValueTranslations(ctx, valueIDs, chain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load translations: %w", err)
+	}
+
+	byValue := make(map[string]map[string]string, len(valueIDs))
+	for _, t := range translations {
+		if byValue[t.OptionValueID] == nil {
+			byValue[t.OptionValueID] = make(map[string]string)
+		}
+		byValue[t.OptionValueID][t.Locale] = t.Value
+	}
+	for i := range options {
+		for j := range options[i].Values {
+			v := &options[i].Values[j]
+			for _, l := range chain {
+				if value, ok := byValue[v.ID][l]; ok {
+					v.Value = value
+					break
+				}
+			}
+		}
+	}
+	return options, nil
+}
+
+// checkOptionValue verifies that valueID is a value of one of the seller's
+// product's options.
+func (uc *TranslationUseCase) checkOptionValue(ctx context.Context, productID, sellerID, valueID string) error {
+	product, err := uc.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID != sellerID {
+		return fmt.Errorf("unauthorized: product belongs to another seller")
+	}
+	options, err := uc.optionRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to list options: %w", err)
+	}
+	for _, o := range options {
+		for _, v := range o.Values {
+			if v.ID == valueID {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("option value not found")
+}
+
+// normalizeLocale rejects unsupported locales and reduces the rest to the
+// language code translations are stored under (e.g. "fr-CA" to "fr").
+func (uc *TranslationUseCase) normalizeLocale(locale string) (string, error) {
+	if !i18n.IsSupported(locale) {
+		return "", fmt.Errorf("unsupported locale %q", locale)
+	}
+	return uc.bundle.MatchLanguage(locale), nil
+}
+
+// slugify lowercases s and joins its runs of letters and digits with
+// hyphens. Unlike generateSlug it keeps non-Latin scripts, so slugs in
+// e.g. Japanese or Arabic are not emptied.
+func slugify(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// localizedSlug creates a slug for a translated name with a short UUID
+// suffix for uniqueness within its locale.
+func localizedSlug(name string) string {
+	return fmt.Sprintf("%s-%s", slugify(name), uuid.New().String()[:8])
+}
@@ -15,6 +15,7 @@ type VariantUseCase struct {
 	productRepo domain.ProductRepository
 	optionRepo  domain.OptionRepository
 	variantRepo domain.VariantRepository
+	pricingUC   *PricingUseCase
 	eventPub    domain.EventPublisher
 }
 
@@ -23,12 +24,14 @@ func NewVariantUseCase(
 	productRepo domain.ProductRepository,
 	optionRepo domain.OptionRepository,
 	variantRepo domain.VariantRepository,
+	pricingUC *PricingUseCase,
 	eventPub domain.EventPublisher,
 ) *VariantUseCase {
 	return &VariantUseCase{
 		productRepo: productRepo,
 		optionRepo:  optionRepo,
 		variantRepo: variantRepo,
+		pricingUC:   pricingUC,
 		eventPub:    eventPub,
 	}
 }
@@ -174,6 +177,7 @@ func (uc *VariantUseCase) GenerateVariants(ctx context.Context, productID string
 	product.HasVariants = true
 	product.UpdatedAt = now
 	_ = uc.productRepo.Update(ctx, product)
+	_ = uc.eventPub.PublishProductUpdated(ctx, product)
 
 	return variants, nil
 }
@@ -255,6 +259,7 @@ func (uc *VariantUseCase) UpdateVariant(ctx context.Context, productID string, v
 		return nil, fmt.Errorf("variant does not belong to this product")
 	}
 
+	previousPrice, previousCompareAt := variant.PriceCents, variant.CompareAtCents
 	if input.Name != nil {
 		variant.Name = *input.Name
 	}
@@ -287,6 +292,11 @@ func (uc *VariantUseCase) UpdateVariant(ctx context.Context, productID string, v
 	if err := uc.variantRepo.Update(ctx, variant); err != nil {
 		return nil, fmt.Errorf("failed to update variant: %w", err)
 	}
+	if err := uc.pricingUC.recordPriceChange(ctx, product, variant, previousPrice, previousCompareAt, domain.PriceChangeSourceManual, ""); err != nil {
+		return nil, err
+	}
+
+	_ = uc.eventPub.PublishProductUpdated(ctx, product)
 
 	return variant, nil
 }
@@ -10,6 +10,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
@@ -17,9 +18,12 @@ import (
 	grpcAdapter "github.com/southern-martin/ecommerce/services/promotion/internal/adapter/grpc"
 	httpAdapter "github.com/southern-martin/ecommerce/services/promotion/internal/adapter/http"
 	"github.com/southern-martin/ecommerce/services/promotion/internal/adapter/postgres"
+	promoredis "github.com/southern-martin/ecommerce/services/promotion/internal/adapter/redis"
 	"github.com/southern-martin/ecommerce/services/promotion/internal/infrastructure/config"
 	"github.com/southern-martin/ecommerce/services/promotion/internal/infrastructure/database"
 	natsInfra "github.com/southern-martin/ecommerce/services/promotion/internal/infrastructure/nats"
+	"github.com/southern-martin/ecommerce/services/promotion/internal/infrastructure/product"
+	"github.com/southern-martin/ecommerce/services/promotion/internal/infrastructure/scheduler"
 	"github.com/southern-martin/ecommerce/services/promotion/internal/usecase"
 )
 
@@ -38,6 +42,15 @@ func main() {
 		log.Fatal().Err(err).Msg("failed to connect to database")
 	}
 
+	// Initialize Redis (flash sale counters)
+	rdb := redis.NewClient(&redis.Options{
+		Addr: cfg.Redis.URL,
+	})
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to Redis")
+	}
+	defer rdb.Close()
+
 	// Initialize NATS publisher
 	publisher, err := natsInfra.NewPublisher(cfg.NATS.URL)
 	if err != nil {
@@ -48,17 +61,57 @@ func main() {
 	// Initialize repositories
 	couponRepo := postgres.NewCouponRepo(db)
 	couponUsageRepo := postgres.NewCouponUsageRepo(db)
+	couponRedemptionRepo := postgres.NewCouponRedemptionRepo(db)
 	flashSaleRepo := postgres.NewFlashSaleRepo(db)
 	flashSaleItemRepo := postgres.NewFlashSaleItemRepo(db)
+	flashSaleClaimRepo := postgres.NewFlashSaleClaimRepo(db)
+	flashSaleStock := promoredis.NewFlashSaleStock(rdb)
 	bundleRepo := postgres.NewBundleRepo(db)
+	automaticPromotionRepo := postgres.NewAutomaticPromotionRepo(db)
+	productSellerRepo := postgres.NewProductSellerRepo(db)
+	orderFactRepo := postgres.NewOrderFactRepo(db)
+	attributionRepo := postgres.NewPromotionAttributionRepo(db)
+
+	// Sellers of products the product events have not announced are looked
+	// up in the catalog
+	catalog, err := product.NewClient(cfg.ProductGRPCAddr)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create product client")
+	}
+	defer catalog.Close()
 
 	// Initialize use cases
-	couponUC := usecase.NewCouponUseCase(couponRepo, couponUsageRepo, publisher)
-	flashSaleUC := usecase.NewFlashSaleUseCase(flashSaleRepo, flashSaleItemRepo, publisher)
-	bundleUC := usecase.NewBundleUseCase(bundleRepo)
+	couponUC := usecase.NewCouponUseCase(couponRepo, couponUsageRepo, couponRedemptionRepo, publisher)
+	flashSaleUC := usecase.NewFlashSaleUseCase(flashSaleRepo, flashSaleItemRepo, flashSaleClaimRepo, flashSaleStock, publisher)
+	bundleUC := usecase.NewBundleUseCase(bundleRepo, productSellerRepo, catalog)
+	promotionUC := usecase.NewAutomaticPromotionUseCase(automaticPromotionRepo)
+	analyticsUC := usecase.NewAnalyticsUseCase(orderFactRepo, attributionRepo, flashSaleRepo, flashSaleItemRepo, flashSaleClaimRepo)
+
+	// Subscribe to order lifecycle events
+	if err := natsInfra.StartOrderSubscriber(publisher, couponUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to order events")
+	}
+	if err := natsInfra.StartFlashSaleOrderSubscriber(publisher, flashSaleUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to order events")
+	}
+
+	// Subscribe to product events for bundle seller validation
+	if err := natsInfra.StartProductSubscriber(publisher, bundleUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to product events")
+	}
+
+	// Subscribe to order and coupon events for promotion analytics
+	if err := natsInfra.StartAnalyticsSubscriber(publisher, analyticsUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to analytics events")
+	}
+
+	// Start flash sale scheduler
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	scheduler.StartFlashSaleScheduler(schedulerCtx, flashSaleUC, 15*time.Second)
 
 	// Initialize HTTP handler and router
-	handler := httpAdapter.NewHandler(couponUC, flashSaleUC, bundleUC)
+	handler := httpAdapter.NewHandler(couponUC, flashSaleUC, bundleUC, promotionUC, analyticsUC)
 	router := httpAdapter.NewRouter(handler)
 
 	// Start HTTP server
@@ -76,7 +129,7 @@ func main() {
 
 	// Start gRPC server
 	grpcServer := grpc.NewServer()
-	grpcSrv := grpcAdapter.NewServer(couponUC, flashSaleUC)
+	grpcSrv := grpcAdapter.NewServer(couponUC, flashSaleUC, promotionUC, bundleUC)
 	grpcAdapter.RegisterPromotionServiceServer(grpcServer, grpcSrv)
 
 	go func() {
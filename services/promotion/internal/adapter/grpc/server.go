@@ -2,8 +2,13 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	// Answers calls made with the JSON codec
+	_ "github.com/southern-martin/ecommerce/pkg/grpcjson"
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
 	"github.com/southern-martin/ecommerce/services/promotion/internal/usecase"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -14,16 +19,26 @@ import (
 type PromotionService interface {
 	ValidateCoupon(ctx context.Context, req *ValidateCouponRequest) (*ValidateCouponResponse, error)
 	RedeemCoupon(ctx context.Context, req *RedeemCouponRequest) (*RedeemCouponResponse, error)
+	ApplyCoupons(ctx context.Context, req *ApplyCouponsRequest) (*ApplyCouponsResponse, error)
+	RedeemCoupons(ctx context.Context, req *RedeemCouponsRequest) (*RedeemCouponsResponse, error)
+	ReserveCoupons(ctx context.Context, req *RedeemCouponsRequest) (*RedeemCouponsResponse, error)
+	ReleaseOrderCoupons(ctx context.Context, req *ReleaseOrderCouponsRequest) (*ReleaseOrderCouponsResponse, error)
 	GetFlashSalePrice(ctx context.Context, req *GetFlashSalePriceRequest) (*GetFlashSalePriceResponse, error)
+	ClaimFlashSaleItem(ctx context.Context, req *ClaimFlashSaleItemRequest) (*ClaimFlashSaleItemResponse, error)
+	ReleaseFlashSaleClaims(ctx context.Context, req *ReleaseFlashSaleClaimsRequest) (*ReleaseFlashSaleClaimsResponse, error)
+	EvaluatePromotions(ctx context.Context, req *EvaluatePromotionsRequest) (*EvaluatePromotionsResponse, error)
+	ApplyBundles(ctx context.Context, req *ApplyBundlesRequest) (*ApplyBundlesResponse, error)
 }
 
 // --- Request/Response types ---
 
 // ValidateCouponRequest is the gRPC request for ValidateCoupon.
 type ValidateCouponRequest struct {
-	Code       string
-	UserID     string
-	OrderCents int64
+	Code           string
+	UserID         string
+	OrderCents     int64
+	Lines          []OrderLine
+	ShippingQuotes []ShippingQuote
 }
 
 // ValidateCouponResponse is the gRPC response for ValidateCoupon.
@@ -33,14 +48,20 @@ type ValidateCouponResponse struct {
 	CouponCode    string
 	CouponType    string
 	DiscountCents int64
+	Allocations   []LineDiscount
+	// ShippingDiscountCents and ShippingQuotes are set for free-shipping coupons.
+	ShippingDiscountCents int64
+	ShippingQuotes        []ShippingQuote
 }
 
 // RedeemCouponRequest is the gRPC request for RedeemCoupon.
 type RedeemCouponRequest struct {
-	Code       string
-	UserID     string
-	OrderID    string
-	OrderCents int64
+	Code           string
+	UserID         string
+	OrderID        string
+	OrderCents     int64
+	Lines          []OrderLine
+	ShippingQuotes []ShippingQuote
 }
 
 // RedeemCouponResponse is the gRPC response for RedeemCoupon.
@@ -50,6 +71,91 @@ type RedeemCouponResponse struct {
 	DiscountCents int64
 }
 
+// OrderLine is an order line in coupon gRPC requests.
+type OrderLine struct {
+	LineID         string
+	ProductID      string
+	VariantID      string
+	CategoryID     string
+	SellerID       string
+	Quantity       int32
+	UnitPriceCents int64
+}
+
+// ShippingQuote is a shipping rate quoted by the shipping service.
+type ShippingQuote struct {
+	SellerID    string
+	CarrierCode string
+	ServiceName string
+	RateCents   int64
+}
+
+// LineDiscount is the share of a coupon discount allocated to an order line.
+type LineDiscount struct {
+	LineID        string
+	ProductID     string
+	VariantID     string
+	SellerID      string
+	DiscountCents int64
+}
+
+// ApplyCouponsRequest is the gRPC request for ApplyCoupons.
+type ApplyCouponsRequest struct {
+	Codes          []string
+	UserID         string
+	Lines          []OrderLine
+	ShippingQuotes []ShippingQuote
+}
+
+// AppliedCoupon is a coupon accepted by ApplyCoupons.
+type AppliedCoupon struct {
+	CouponID              string
+	CouponCode            string
+	CouponType            string
+	DiscountCents         int64
+	ShippingDiscountCents int64
+	Allocations           []LineDiscount
+}
+
+// RejectedCoupon is a coupon rejected by ApplyCoupons.
+type RejectedCoupon struct {
+	Code   string
+	Reason string
+}
+
+// ApplyCouponsResponse is the gRPC response for ApplyCoupons.
+type ApplyCouponsResponse struct {
+	Applied                    []AppliedCoupon
+	Rejected                   []RejectedCoupon
+	TotalDiscountCents         int64
+	TotalShippingDiscountCents int64
+	ShippingQuotes             []ShippingQuote
+}
+
+// RedeemCouponsRequest is the gRPC request for RedeemCoupons.
+type RedeemCouponsRequest struct {
+	Codes          []string
+	UserID         string
+	OrderID        string
+	Lines          []OrderLine
+	ShippingQuotes []ShippingQuote
+}
+
+// RedeemCouponsResponse is the gRPC response for RedeemCoupons.
+type RedeemCouponsResponse struct {
+	Usages []RedeemCouponResponse
+}
+
+// ReleaseOrderCouponsRequest is the gRPC request for ReleaseOrderCoupons.
+type ReleaseOrderCouponsRequest struct {
+	OrderID string
+}
+
+// ReleaseOrderCouponsResponse is the gRPC response for ReleaseOrderCoupons.
+type ReleaseOrderCouponsResponse struct {
+	ReleasedCount int32
+}
+
 // GetFlashSalePriceRequest is the gRPC request for GetFlashSalePrice.
 type GetFlashSalePriceRequest struct {
 	ProductID string
@@ -58,26 +164,136 @@ type GetFlashSalePriceRequest struct {
 
 // GetFlashSalePriceResponse is the gRPC response for GetFlashSalePrice.
 type GetFlashSalePriceResponse struct {
-	HasFlashSale   bool
-	SalePriceCents int64
-	FlashSaleID    string
-	FlashSaleName  string
+	HasFlashSale    bool
+	SalePriceCents  int64
+	FlashSaleID     string
+	FlashSaleName   string
+	FlashSaleItemID string
+	// RemainingQuantity is -1 for items without a quantity limit.
+	RemainingQuantity int32
+	PerBuyerLimit     int32
+	EndsAt            string
+}
+
+// ClaimFlashSaleItemRequest is the gRPC request for ClaimFlashSaleItem.
+type ClaimFlashSaleItemRequest struct {
+	FlashSaleItemID string
+	BuyerID         string
+	OrderID         string
+	Quantity        int32
+}
+
+// ClaimFlashSaleItemResponse is the gRPC response for ClaimFlashSaleItem.
+type ClaimFlashSaleItemResponse struct {
+	ClaimID           string
+	SalePriceCents    int64
+	RemainingQuantity int32
+}
+
+// ReleaseFlashSaleClaimsRequest is the gRPC request for ReleaseFlashSaleClaims.
+type ReleaseFlashSaleClaimsRequest struct {
+	OrderID string
+}
+
+// ReleaseFlashSaleClaimsResponse is the gRPC response for ReleaseFlashSaleClaims.
+type ReleaseFlashSaleClaimsResponse struct {
+	ReleasedCount int32
+}
+
+// EvaluatePromotionsRequest is the gRPC request for EvaluatePromotions.
+type EvaluatePromotionsRequest struct {
+	UserID string
+	Lines  []OrderLine
+}
+
+// AppliedPromotion is an automatic promotion applied by EvaluatePromotions.
+type AppliedPromotion struct {
+	PromotionID   string
+	Name          string
+	DiscountCents int64
+	Allocations   []LineDiscount
+	Gifts         []GiftItem
+}
+
+// LineExplanation lists the promotions applied to one order line.
+type LineExplanation struct {
+	LineID        string
+	DiscountCents int64
+	Explanations  []string
+}
+
+// GiftItem is a free item added to the order by a promotion.
+type GiftItem struct {
+	PromotionID string
+	ProductID   string
+	VariantID   string
+	SellerID    string
+	Quantity    int32
+}
+
+// EvaluatePromotionsResponse is the gRPC response for EvaluatePromotions.
+type EvaluatePromotionsResponse struct {
+	Applied            []AppliedPromotion
+	Lines              []LineExplanation
+	Gifts              []GiftItem
+	TotalDiscountCents int64
+}
+
+// ApplyBundlesRequest is the gRPC request for ApplyBundles.
+type ApplyBundlesRequest struct {
+	Lines []OrderLine
+}
+
+// BundleAllocation is the share of a bundle price allocated to an order line.
+type BundleAllocation struct {
+	LineID         string
+	ProductID      string
+	VariantID      string
+	SellerID       string
+	Quantity       int32
+	ListPriceCents int64
+	PriceCents     int64
+	DiscountCents  int64
+}
+
+// AppliedBundle is a bundle detected by ApplyBundles.
+type AppliedBundle struct {
+	BundleID       string
+	Name           string
+	SellerID       string
+	Sets           int32
+	ListPriceCents int64
+	PriceCents     int64
+	DiscountCents  int64
+	Allocations    []BundleAllocation
+}
+
+// ApplyBundlesResponse is the gRPC response for ApplyBundles.
+type ApplyBundlesResponse struct {
+	Applied            []AppliedBundle
+	TotalDiscountCents int64
 }
 
 // Server implements the PromotionService gRPC interface.
 type Server struct {
 	couponUC    *usecase.CouponUseCase
 	flashSaleUC *usecase.FlashSaleUseCase
+	promotionUC *usecase.AutomaticPromotionUseCase
+	bundleUC    *usecase.BundleUseCase
 }
 
 // NewServer creates a new gRPC Server.
 func NewServer(
 	couponUC *usecase.CouponUseCase,
 	flashSaleUC *usecase.FlashSaleUseCase,
+	promotionUC *usecase.AutomaticPromotionUseCase,
+	bundleUC *usecase.BundleUseCase,
 ) *Server {
 	return &Server{
 		couponUC:    couponUC,
 		flashSaleUC: flashSaleUC,
+		promotionUC: promotionUC,
+		bundleUC:    bundleUC,
 	}
 }
 
@@ -90,21 +306,26 @@ func (s *Server) ValidateCoupon(ctx context.Context, req *ValidateCouponRequest)
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
 
-	coupon, discountCents, err := s.couponUC.ValidateCoupon(ctx, usecase.ValidateCouponInput{
-		Code:       req.Code,
-		UserID:     req.UserID,
-		OrderCents: req.OrderCents,
+	coupon, discount, err := s.couponUC.ValidateCoupon(ctx, usecase.ValidateCouponInput{
+		Code:           req.Code,
+		UserID:         req.UserID,
+		OrderCents:     req.OrderCents,
+		Lines:          toDomainLines(req.Lines),
+		ShippingQuotes: toDomainQuotes(req.ShippingQuotes),
 	})
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	return &ValidateCouponResponse{
-		Valid:         true,
-		CouponID:      coupon.ID,
-		CouponCode:    coupon.Code,
-		CouponType:    string(coupon.Type),
-		DiscountCents: discountCents,
+		Valid:                 true,
+		CouponID:              coupon.ID,
+		CouponCode:            coupon.Code,
+		CouponType:            string(coupon.Type),
+		DiscountCents:         discount.DiscountCents,
+		Allocations:           toLineDiscounts(discount.Allocations),
+		ShippingDiscountCents: discount.ShippingDiscountCents,
+		ShippingQuotes:        toQuotes(discount.ShippingQuotes),
 	}, nil
 }
 
@@ -120,8 +341,17 @@ func (s *Server) RedeemCoupon(ctx context.Context, req *RedeemCouponRequest) (*R
 		return nil, status.Error(codes.InvalidArgument, "order_id is required")
 	}
 
-	usage, err := s.couponUC.RedeemCoupon(ctx, req.Code, req.UserID, req.OrderID, req.OrderCents)
+	usage, err := s.couponUC.RedeemCoupon(ctx, usecase.ValidateCouponInput{
+		Code:           req.Code,
+		UserID:         req.UserID,
+		OrderCents:     req.OrderCents,
+		Lines:          toDomainLines(req.Lines),
+		ShippingQuotes: toDomainQuotes(req.ShippingQuotes),
+	}, req.OrderID)
 	if err != nil {
+		if errors.Is(err, domain.ErrCouponUsageLimitReached) || errors.Is(err, domain.ErrCouponPerUserLimitReached) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -132,6 +362,104 @@ func (s *Server) RedeemCoupon(ctx context.Context, req *RedeemCouponRequest) (*R
 	}, nil
 }
 
+// ApplyCoupons evaluates several coupons against an order via gRPC.
+func (s *Server) ApplyCoupons(ctx context.Context, req *ApplyCouponsRequest) (*ApplyCouponsResponse, error) {
+	if req.UserID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	result, err := s.couponUC.ApplyCoupons(ctx, usecase.ApplyCouponsInput{
+		Codes:          req.Codes,
+		UserID:         req.UserID,
+		Lines:          toDomainLines(req.Lines),
+		ShippingQuotes: toDomainQuotes(req.ShippingQuotes),
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &ApplyCouponsResponse{
+		TotalDiscountCents:         result.TotalDiscountCents,
+		TotalShippingDiscountCents: result.TotalShippingDiscountCents,
+		ShippingQuotes:             toQuotes(result.ShippingQuotes),
+	}
+	for _, a := range result.Applied {
+		resp.Applied = append(resp.Applied, AppliedCoupon{
+			CouponID:              a.Coupon.ID,
+			CouponCode:            a.Coupon.Code,
+			CouponType:            string(a.Coupon.Type),
+			DiscountCents:         a.DiscountCents,
+			ShippingDiscountCents: a.ShippingDiscountCents,
+			Allocations:           toLineDiscounts(a.Allocations),
+		})
+	}
+	for _, r := range result.Rejected {
+		resp.Rejected = append(resp.Rejected, RejectedCoupon{Code: r.Code, Reason: r.Reason})
+	}
+	return resp, nil
+}
+
+// RedeemCoupons redeems several coupons for one order via gRPC.
+func (s *Server) RedeemCoupons(ctx context.Context, req *RedeemCouponsRequest) (*RedeemCouponsResponse, error) {
+	return s.redeemCoupons(ctx, req, s.couponUC.RedeemCoupons)
+}
+
+// ReserveCoupons reserves several coupons for an unconfirmed order via gRPC.
+// The reservation is confirmed or released by order lifecycle events.
+func (s *Server) ReserveCoupons(ctx context.Context, req *RedeemCouponsRequest) (*RedeemCouponsResponse, error) {
+	return s.redeemCoupons(ctx, req, s.couponUC.ReserveCoupons)
+}
+
+func (s *Server) redeemCoupons(
+	ctx context.Context,
+	req *RedeemCouponsRequest,
+	redeem func(context.Context, usecase.ApplyCouponsInput, string) ([]*domain.CouponUsage, error),
+) (*RedeemCouponsResponse, error) {
+	if req.UserID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.OrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	usages, err := redeem(ctx, usecase.ApplyCouponsInput{
+		Codes:          req.Codes,
+		UserID:         req.UserID,
+		Lines:          toDomainLines(req.Lines),
+		ShippingQuotes: toDomainQuotes(req.ShippingQuotes),
+	}, req.OrderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrCouponUsageLimitReached) || errors.Is(err, domain.ErrCouponPerUserLimitReached) {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	resp := &RedeemCouponsResponse{}
+	for _, u := range usages {
+		resp.Usages = append(resp.Usages, RedeemCouponResponse{
+			UsageID:       u.ID,
+			CouponID:      u.CouponID,
+			DiscountCents: u.DiscountCents,
+		})
+	}
+	return resp, nil
+}
+
+// ReleaseOrderCoupons returns an order's coupon usages to their coupons via gRPC.
+func (s *Server) ReleaseOrderCoupons(ctx context.Context, req *ReleaseOrderCouponsRequest) (*ReleaseOrderCouponsResponse, error) {
+	if req.OrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	usages, err := s.couponUC.ReleaseOrderCoupons(ctx, req.OrderID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &ReleaseOrderCouponsResponse{ReleasedCount: int32(len(usages))}, nil
+}
+
 // GetFlashSalePrice checks if a product is in an active flash sale via gRPC.
 func (s *Server) GetFlashSalePrice(ctx context.Context, req *GetFlashSalePriceRequest) (*GetFlashSalePriceResponse, error) {
 	if req.ProductID == "" {
@@ -146,14 +474,18 @@ func (s *Server) GetFlashSalePrice(ctx context.Context, req *GetFlashSalePriceRe
 	for _, fs := range flashSales {
 		for _, item := range fs.Items {
 			if item.ProductID == req.ProductID && (req.VariantID == "" || item.VariantID == req.VariantID) {
-				if item.QuantityLimit > 0 && item.SoldCount >= item.QuantityLimit {
+				if item.Remaining() == 0 {
 					continue // sold out
 				}
 				return &GetFlashSalePriceResponse{
-					HasFlashSale:   true,
-					SalePriceCents: item.SalePriceCents,
-					FlashSaleID:    fs.ID,
-					FlashSaleName:  fs.Name,
+					HasFlashSale:      true,
+					SalePriceCents:    item.SalePriceCents,
+					FlashSaleID:       fs.ID,
+					FlashSaleName:     fs.Name,
+					FlashSaleItemID:   item.ID,
+					RemainingQuantity: int32(item.Remaining()),
+					PerBuyerLimit:     int32(item.PerBuyerLimit),
+					EndsAt:            fs.EndsAt.Format(time.RFC3339),
 				}, nil
 			}
 		}
@@ -164,6 +496,186 @@ func (s *Server) GetFlashSalePrice(ctx context.Context, req *GetFlashSalePriceRe
 	}, nil
 }
 
+// ClaimFlashSaleItem claims flash sale units for an order via gRPC.
+func (s *Server) ClaimFlashSaleItem(ctx context.Context, req *ClaimFlashSaleItemRequest) (*ClaimFlashSaleItemResponse, error) {
+	claim, item, err := s.flashSaleUC.ClaimFlashSaleItem(ctx, usecase.ClaimFlashSaleItemInput{
+		FlashSaleItemID: req.FlashSaleItemID,
+		BuyerID:         req.BuyerID,
+		OrderID:         req.OrderID,
+		Quantity:        int(req.Quantity),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrFlashSaleSoldOut), errors.Is(err, domain.ErrFlashSaleBuyerLimitReached):
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		case errors.Is(err, usecase.ErrFlashSaleNotLive):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, domain.ErrFlashSaleClaimHeld):
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &ClaimFlashSaleItemResponse{
+		ClaimID:           claim.ID,
+		SalePriceCents:    item.SalePriceCents,
+		RemainingQuantity: int32(item.Remaining()),
+	}, nil
+}
+
+// ReleaseFlashSaleClaims returns an order's flash sale units via gRPC.
+func (s *Server) ReleaseFlashSaleClaims(ctx context.Context, req *ReleaseFlashSaleClaimsRequest) (*ReleaseFlashSaleClaimsResponse, error) {
+	if req.OrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	claims, err := s.flashSaleUC.ReleaseOrderClaims(ctx, req.OrderID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &ReleaseFlashSaleClaimsResponse{ReleasedCount: int32(len(claims))}, nil
+}
+
+// EvaluatePromotions applies the running automatic promotions to a cart or
+// order via gRPC.
+func (s *Server) EvaluatePromotions(ctx context.Context, req *EvaluatePromotionsRequest) (*EvaluatePromotionsResponse, error) {
+	result, err := s.promotionUC.EvaluatePromotions(ctx, usecase.EvaluatePromotionsInput{
+		UserID: req.UserID,
+		Lines:  toDomainLines(req.Lines),
+	})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &EvaluatePromotionsResponse{
+		Gifts:              toGiftItems(result.Gifts),
+		TotalDiscountCents: result.TotalDiscountCents,
+	}
+	for _, a := range result.Applied {
+		resp.Applied = append(resp.Applied, AppliedPromotion{
+			PromotionID:   a.Promotion.ID,
+			Name:          a.Promotion.Name,
+			DiscountCents: a.DiscountCents,
+			Allocations:   toLineDiscounts(a.Allocations),
+			Gifts:         toGiftItems(a.Gifts),
+		})
+	}
+	for _, l := range result.Lines {
+		resp.Lines = append(resp.Lines, LineExplanation{
+			LineID:        l.LineID,
+			DiscountCents: l.DiscountCents,
+			Explanations:  l.Explanations,
+		})
+	}
+	return resp, nil
+}
+
+// ApplyBundles detects and prices the bundles in a cart or order via gRPC.
+func (s *Server) ApplyBundles(ctx context.Context, req *ApplyBundlesRequest) (*ApplyBundlesResponse, error) {
+	result, err := s.bundleUC.ApplyBundles(ctx, toDomainLines(req.Lines))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &ApplyBundlesResponse{TotalDiscountCents: result.TotalDiscountCents}
+	for _, a := range result.Applied {
+		applied := AppliedBundle{
+			BundleID:       a.Bundle.ID,
+			Name:           a.Bundle.Name,
+			SellerID:       a.Bundle.SellerID,
+			Sets:           int32(a.Sets),
+			ListPriceCents: a.ListPriceCents,
+			PriceCents:     a.PriceCents,
+			DiscountCents:  a.DiscountCents,
+		}
+		for _, al := range a.Allocations {
+			applied.Allocations = append(applied.Allocations, BundleAllocation{
+				LineID:         al.LineID,
+				ProductID:      al.ProductID,
+				VariantID:      al.VariantID,
+				SellerID:       al.SellerID,
+				Quantity:       int32(al.Quantity),
+				ListPriceCents: al.ListPriceCents,
+				PriceCents:     al.PriceCents,
+				DiscountCents:  al.DiscountCents,
+			})
+		}
+		resp.Applied = append(resp.Applied, applied)
+	}
+	return resp, nil
+}
+
+func toDomainLines(lines []OrderLine) []domain.OrderLine {
+	out := make([]domain.OrderLine, len(lines))
+	for i, l := range lines {
+		out[i] = domain.OrderLine{
+			LineID:         l.LineID,
+			ProductID:      l.ProductID,
+			VariantID:      l.VariantID,
+			CategoryID:     l.CategoryID,
+			SellerID:       l.SellerID,
+			Quantity:       int(l.Quantity),
+			UnitPriceCents: l.UnitPriceCents,
+		}
+	}
+	return out
+}
+
+func toDomainQuotes(quotes []ShippingQuote) []domain.ShippingQuote {
+	out := make([]domain.ShippingQuote, len(quotes))
+	for i, q := range quotes {
+		out[i] = domain.ShippingQuote{
+			SellerID:    q.SellerID,
+			CarrierCode: q.CarrierCode,
+			ServiceName: q.ServiceName,
+			RateCents:   q.RateCents,
+		}
+	}
+	return out
+}
+
+func toQuotes(quotes []domain.ShippingQuote) []ShippingQuote {
+	out := make([]ShippingQuote, len(quotes))
+	for i, q := range quotes {
+		out[i] = ShippingQuote{
+			SellerID:    q.SellerID,
+			CarrierCode: q.CarrierCode,
+			ServiceName: q.ServiceName,
+			RateCents:   q.RateCents,
+		}
+	}
+	return out
+}
+
+func toLineDiscounts(allocs []domain.LineDiscount) []LineDiscount {
+	out := make([]LineDiscount, len(allocs))
+	for i, a := range allocs {
+		out[i] = LineDiscount{
+			LineID:        a.LineID,
+			ProductID:     a.ProductID,
+			VariantID:     a.VariantID,
+			SellerID:      a.SellerID,
+			DiscountCents: a.DiscountCents,
+		}
+	}
+	return out
+}
+
+func toGiftItems(gifts []domain.GiftItem) []GiftItem {
+	out := make([]GiftItem, len(gifts))
+	for i, g := range gifts {
+		out[i] = GiftItem{
+			PromotionID: g.PromotionID,
+			ProductID:   g.ProductID,
+			VariantID:   g.VariantID,
+			SellerID:    g.SellerID,
+			Quantity:    int32(g.Quantity),
+		}
+	}
+	return out
+}
+
 // --- gRPC ServiceDesc for manual registration ---
 
 // handlerValidateCoupon is the gRPC handler wrapper for ValidateCoupon.
@@ -184,6 +696,42 @@ func handlerRedeemCoupon(srv interface{}, ctx context.Context, dec func(interfac
 	return srv.(PromotionService).RedeemCoupon(ctx, req)
 }
 
+// handlerApplyCoupons is the gRPC handler wrapper for ApplyCoupons.
+func handlerApplyCoupons(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &ApplyCouponsRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	return srv.(PromotionService).ApplyCoupons(ctx, req)
+}
+
+// handlerRedeemCoupons is the gRPC handler wrapper for RedeemCoupons.
+func handlerRedeemCoupons(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &RedeemCouponsRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	return srv.(PromotionService).RedeemCoupons(ctx, req)
+}
+
+// handlerReserveCoupons is the gRPC handler wrapper for ReserveCoupons.
+func handlerReserveCoupons(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &RedeemCouponsRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	return srv.(PromotionService).ReserveCoupons(ctx, req)
+}
+
+// handlerReleaseOrderCoupons is the gRPC handler wrapper for ReleaseOrderCoupons.
+func handlerReleaseOrderCoupons(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &ReleaseOrderCouponsRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	return srv.(PromotionService).ReleaseOrderCoupons(ctx, req)
+}
+
 // handlerGetFlashSalePrice is the gRPC handler wrapper for GetFlashSalePrice.
 func handlerGetFlashSalePrice(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
 	req := &GetFlashSalePriceRequest{}
@@ -193,6 +741,42 @@ func handlerGetFlashSalePrice(srv interface{}, ctx context.Context, dec func(int
 	return srv.(PromotionService).GetFlashSalePrice(ctx, req)
 }
 
+// handlerClaimFlashSaleItem is the gRPC handler wrapper for ClaimFlashSaleItem.
+func handlerClaimFlashSaleItem(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &ClaimFlashSaleItemRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	return srv.(PromotionService).ClaimFlashSaleItem(ctx, req)
+}
+
+// handlerReleaseFlashSaleClaims is the gRPC handler wrapper for ReleaseFlashSaleClaims.
+func handlerReleaseFlashSaleClaims(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &ReleaseFlashSaleClaimsRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	return srv.(PromotionService).ReleaseFlashSaleClaims(ctx, req)
+}
+
+// handlerEvaluatePromotions is the gRPC handler wrapper for EvaluatePromotions.
+func handlerEvaluatePromotions(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &EvaluatePromotionsRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	return srv.(PromotionService).EvaluatePromotions(ctx, req)
+}
+
+// handlerApplyBundles is the gRPC handler wrapper for ApplyBundles.
+func handlerApplyBundles(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &ApplyBundlesRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	return srv.(PromotionService).ApplyBundles(ctx, req)
+}
+
 // PromotionServiceDesc is the gRPC service descriptor for manual registration.
 var PromotionServiceDesc = grpc.ServiceDesc{
 	ServiceName: "promotion.PromotionService",
@@ -206,10 +790,42 @@ var PromotionServiceDesc = grpc.ServiceDesc{
 			MethodName: "RedeemCoupon",
 			Handler:    handlerRedeemCoupon,
 		},
+		{
+			MethodName: "ApplyCoupons",
+			Handler:    handlerApplyCoupons,
+		},
+		{
+			MethodName: "RedeemCoupons",
+			Handler:    handlerRedeemCoupons,
+		},
+		{
+			MethodName: "ReserveCoupons",
+			Handler:    handlerReserveCoupons,
+		},
+		{
+			MethodName: "ReleaseOrderCoupons",
+			Handler:    handlerReleaseOrderCoupons,
+		},
 		{
 			MethodName: "GetFlashSalePrice",
 			Handler:    handlerGetFlashSalePrice,
 		},
+		{
+			MethodName: "ClaimFlashSaleItem",
+			Handler:    handlerClaimFlashSaleItem,
+		},
+		{
+			MethodName: "ReleaseFlashSaleClaims",
+			Handler:    handlerReleaseFlashSaleClaims,
+		},
+		{
+			MethodName: "EvaluatePromotions",
+			Handler:    handlerEvaluatePromotions,
+		},
+		{
+			MethodName: "ApplyBundles",
+			Handler:    handlerApplyBundles,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: fmt.Sprintf("promotion_service.proto"),
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -15,6 +16,8 @@ type Handler struct {
 	couponUC    *usecase.CouponUseCase
 	flashSaleUC *usecase.FlashSaleUseCase
 	bundleUC    *usecase.BundleUseCase
+	promotionUC *usecase.AutomaticPromotionUseCase
+	analyticsUC *usecase.AnalyticsUseCase
 }
 
 // NewHandler creates a new Handler instance.
@@ -22,11 +25,15 @@ func NewHandler(
 	couponUC *usecase.CouponUseCase,
 	flashSaleUC *usecase.FlashSaleUseCase,
 	bundleUC *usecase.BundleUseCase,
+	promotionUC *usecase.AutomaticPromotionUseCase,
+	analyticsUC *usecase.AnalyticsUseCase,
 ) *Handler {
 	return &Handler{
 		couponUC:    couponUC,
 		flashSaleUC: flashSaleUC,
 		bundleUC:    bundleUC,
+		promotionUC: promotionUC,
+		analyticsUC: analyticsUC,
 	}
 }
 
@@ -42,17 +49,54 @@ type createCouponRequest struct {
 	PerUserLimit     int      `json:"per_user_limit"`
 	Scope            string   `json:"scope"`
 	ScopeIDs         []string `json:"scope_ids"`
+	Stacking         string   `json:"stacking"`
+	Priority         int      `json:"priority"`
 	StartsAt         string   `json:"starts_at"`
 	ExpiresAt        string   `json:"expires_at" binding:"required"`
 }
 
 type validateCouponRequest struct {
-	Code       string `json:"code" binding:"required"`
-	OrderCents int64  `json:"order_cents"`
+	Code           string                 `json:"code" binding:"required"`
+	OrderCents     int64                  `json:"order_cents"`
+	Lines          []orderLineDTO         `json:"lines"`
+	ShippingQuotes []domain.ShippingQuote `json:"shipping_quotes"`
+}
+
+type generateCodesRequest struct {
+	Type             string   `json:"type" binding:"required"`
+	DiscountValue    int64    `json:"discount_value" binding:"required"`
+	MinOrderCents    int64    `json:"min_order_cents"`
+	MaxDiscountCents int64    `json:"max_discount_cents"`
+	Scope            string   `json:"scope"`
+	ScopeIDs         []string `json:"scope_ids"`
+	Stacking         string   `json:"stacking"`
+	Priority         int      `json:"priority"`
+	StartsAt         string   `json:"starts_at"`
+	ExpiresAt        string   `json:"expires_at" binding:"required"`
+	Prefix           string   `json:"prefix"`
+	Count            int      `json:"count" binding:"required,min=1"`
+	CodeLength       int      `json:"code_length"`
+}
+
+type applyCouponsRequest struct {
+	Codes          []string               `json:"codes" binding:"required,min=1"`
+	Lines          []orderLineDTO         `json:"lines" binding:"required,min=1"`
+	ShippingQuotes []domain.ShippingQuote `json:"shipping_quotes"`
+}
+
+type orderLineDTO struct {
+	LineID         string `json:"line_id"`
+	ProductID      string `json:"product_id" binding:"required"`
+	VariantID      string `json:"variant_id"`
+	CategoryID     string `json:"category_id"`
+	SellerID       string `json:"seller_id"`
+	Quantity       int    `json:"quantity" binding:"required,min=1"`
+	UnitPriceCents int64  `json:"unit_price_cents"`
 }
 
 type updateCouponRequest struct {
 	IsActive         *bool   `json:"is_active"`
+	Priority         *int    `json:"priority"`
 	UsageLimit       *int    `json:"usage_limit"`
 	PerUserLimit     *int    `json:"per_user_limit"`
 	MaxDiscountCents *int64  `json:"max_discount_cents"`
@@ -71,6 +115,7 @@ type flashSaleItemRequestDTO struct {
 	VariantID      string `json:"variant_id"`
 	SalePriceCents int64  `json:"sale_price_cents" binding:"required"`
 	QuantityLimit  int    `json:"quantity_limit"`
+	PerBuyerLimit  int    `json:"per_buyer_limit"`
 }
 
 type updateFlashSaleRequest struct {
@@ -79,10 +124,16 @@ type updateFlashSaleRequest struct {
 }
 
 type createBundleRequest struct {
-	Name             string   `json:"name" binding:"required"`
-	ProductIDs       []string `json:"product_ids" binding:"required,min=2"`
-	BundlePriceCents int64    `json:"bundle_price_cents" binding:"required"`
-	SavingsCents     int64    `json:"savings_cents"`
+	Name             string              `json:"name" binding:"required"`
+	SellerID         string              `json:"seller_id"`
+	ProductIDs       []string            `json:"product_ids"`
+	Items            []domain.BundleItem `json:"items"`
+	BundlePriceCents int64               `json:"bundle_price_cents" binding:"required"`
+	SavingsCents     int64               `json:"savings_cents"`
+}
+
+type applyBundlesRequest struct {
+	Lines []orderLineDTO `json:"lines" binding:"required,min=1"`
 }
 
 type updateBundleRequest struct {
@@ -104,6 +155,9 @@ type couponResponse struct {
 	PerUserLimit     int      `json:"per_user_limit"`
 	Scope            string   `json:"scope"`
 	ScopeIDs         []string `json:"scope_ids"`
+	CampaignID       string   `json:"campaign_id,omitempty"`
+	Stacking         string   `json:"stacking"`
+	Priority         int      `json:"priority"`
 	CreatedBy        string   `json:"created_by"`
 	StartsAt         string   `json:"starts_at"`
 	ExpiresAt        string   `json:"expires_at"`
@@ -117,6 +171,7 @@ type flashSaleResponse struct {
 	StartsAt  string                  `json:"starts_at"`
 	EndsAt    string                  `json:"ends_at"`
 	IsActive  bool                    `json:"is_active"`
+	Status    string                  `json:"status"`
 	Items     []flashSaleItemResponse `json:"items"`
 	CreatedAt string                  `json:"created_at"`
 }
@@ -128,18 +183,89 @@ type flashSaleItemResponse struct {
 	VariantID      string `json:"variant_id"`
 	SalePriceCents int64  `json:"sale_price_cents"`
 	QuantityLimit  int    `json:"quantity_limit"`
+	PerBuyerLimit  int    `json:"per_buyer_limit"`
 	SoldCount      int    `json:"sold_count"`
+	// Remaining is -1 for items without a quantity limit.
+	Remaining int `json:"remaining"`
 }
 
 type bundleResponse struct {
 	ID               string   `json:"id"`
 	Name             string   `json:"name"`
 	SellerID         string   `json:"seller_id"`
-	ProductIDs       []string `json:"product_ids"`
-	BundlePriceCents int64    `json:"bundle_price_cents"`
-	SavingsCents     int64    `json:"savings_cents"`
-	IsActive         bool     `json:"is_active"`
-	CreatedAt        string   `json:"created_at"`
+	ProductIDs       []string            `json:"product_ids"`
+	Items            []domain.BundleItem `json:"items"`
+	BundlePriceCents int64               `json:"bundle_price_cents"`
+	SavingsCents     int64               `json:"savings_cents"`
+	IsActive         bool                `json:"is_active"`
+	CreatedAt        string              `json:"created_at"`
+}
+
+type createPromotionRequest struct {
+	Name        string                      `json:"name" binding:"required"`
+	Description string                      `json:"description"`
+	Conditions  []domain.PromotionCondition `json:"conditions"`
+	Actions     []domain.PromotionAction    `json:"actions" binding:"required,min=1"`
+	Priority    int                         `json:"priority"`
+	Exclusive   bool                        `json:"exclusive"`
+	StartsAt    string                      `json:"starts_at"`
+	EndsAt      string                      `json:"ends_at" binding:"required"`
+}
+
+type updatePromotionRequest struct {
+	Name        *string                     `json:"name"`
+	Description *string                     `json:"description"`
+	Conditions  []domain.PromotionCondition `json:"conditions"`
+	Actions     []domain.PromotionAction    `json:"actions"`
+	Priority    *int                        `json:"priority"`
+	Exclusive   *bool                       `json:"exclusive"`
+	IsActive    *bool                       `json:"is_active"`
+	EndsAt      *string                     `json:"ends_at"`
+}
+
+type evaluatePromotionsRequest struct {
+	Lines []orderLineDTO `json:"lines" binding:"required,min=1"`
+}
+
+type promotionResponse struct {
+	ID          string                      `json:"id"`
+	Name        string                      `json:"name"`
+	Description string                      `json:"description"`
+	CreatedBy   string                      `json:"created_by"`
+	Conditions  []domain.PromotionCondition `json:"conditions"`
+	Actions     []domain.PromotionAction    `json:"actions"`
+	Priority    int                         `json:"priority"`
+	Exclusive   bool                        `json:"exclusive"`
+	StartsAt    string                      `json:"starts_at"`
+	EndsAt      string                      `json:"ends_at"`
+	IsActive    bool                        `json:"is_active"`
+	CreatedAt   string                      `json:"created_at"`
+}
+
+type performanceMetricsResponse struct {
+	Redemptions             int   `json:"redemptions"`
+	CancelledRedemptions    int   `json:"cancelled_redemptions"`
+	DiscountCostCents       int64 `json:"discount_cost_cents"`
+	AttributedRevenueCents  int64 `json:"attributed_revenue_cents"`
+	RefundedCents           int64 `json:"refunded_cents"`
+	NetRevenueCents         int64 `json:"net_revenue_cents"`
+	AverageOrderValueCents  int64 `json:"average_order_value_cents"`
+	BaselineOrderValueCents int64 `json:"baseline_order_value_cents"`
+	AOVUpliftBasisPoints    int64 `json:"aov_uplift_basis_points"`
+}
+
+type promotionPerformanceResponse struct {
+	Kind          string `json:"kind"`
+	PromotionID   string `json:"promotion_id"`
+	PromotionName string `json:"promotion_name"`
+	SellerID      string `json:"seller_id"`
+	performanceMetricsResponse
+}
+
+type sellerPerformanceResponse struct {
+	SellerID   string `json:"seller_id"`
+	Promotions int    `json:"promotions"`
+	performanceMetricsResponse
 }
 
 type listResponse struct {
@@ -166,20 +292,75 @@ func (h *Handler) ValidateCoupon(c *gin.Context) {
 		return
 	}
 
-	coupon, discountCents, err := h.couponUC.ValidateCoupon(c.Request.Context(), usecase.ValidateCouponInput{
-		Code:       req.Code,
-		UserID:     userID,
-		OrderCents: req.OrderCents,
+	coupon, discount, err := h.couponUC.ValidateCoupon(c.Request.Context(), usecase.ValidateCouponInput{
+		Code:           req.Code,
+		UserID:         userID,
+		OrderCents:     req.OrderCents,
+		Lines:          toOrderLines(req.Lines),
+		ShippingQuotes: req.ShippingQuotes,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"coupon":                  toCouponResponse(coupon),
+			"discount_cents":          discount.DiscountCents,
+			"allocations":             discount.Allocations,
+			"shipping_discount_cents": discount.ShippingDiscountCents,
+			"shipping_quotes":         discount.ShippingQuotes,
+		},
+	})
+}
+
+// ApplyCoupons handles POST /api/v1/coupons/apply
+func (h *Handler) ApplyCoupons(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return
+	}
+
+	var req applyCouponsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.couponUC.ApplyCoupons(c.Request.Context(), usecase.ApplyCouponsInput{
+		Codes:          req.Codes,
+		UserID:         userID,
+		Lines:          toOrderLines(req.Lines),
+		ShippingQuotes: req.ShippingQuotes,
 	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	applied := []gin.H{}
+	for _, a := range result.Applied {
+		applied = append(applied, gin.H{
+			"coupon":                  toCouponResponse(a.Coupon),
+			"discount_cents":          a.DiscountCents,
+			"shipping_discount_cents": a.ShippingDiscountCents,
+			"allocations":             a.Allocations,
+		})
+	}
+	rejected := []gin.H{}
+	for _, r := range result.Rejected {
+		rejected = append(rejected, gin.H{"code": r.Code, "reason": r.Reason})
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"data": gin.H{
-			"coupon":         toCouponResponse(coupon),
-			"discount_cents": discountCents,
+			"applied":                       applied,
+			"rejected":                      rejected,
+			"total_discount_cents":          result.TotalDiscountCents,
+			"total_shipping_discount_cents": result.TotalShippingDiscountCents,
+			"shipping_quotes":               result.ShippingQuotes,
 		},
 	})
 }
@@ -254,6 +435,8 @@ func (h *Handler) CreateSellerCoupon(c *gin.Context) {
 		PerUserLimit:     req.PerUserLimit,
 		Scope:            req.Scope,
 		ScopeIDs:         req.ScopeIDs,
+		Stacking:         req.Stacking,
+		Priority:         req.Priority,
 		CreatedBy:        sellerID,
 		StartsAt:         startsAt,
 		ExpiresAt:        expiresAt,
@@ -332,6 +515,9 @@ func (h *Handler) UpdateSellerCoupon(c *gin.Context) {
 	if req.IsActive != nil {
 		coupon.IsActive = *req.IsActive
 	}
+	if req.Priority != nil {
+		coupon.Priority = *req.Priority
+	}
 	if req.UsageLimit != nil {
 		coupon.UsageLimit = *req.UsageLimit
 	}
@@ -413,6 +599,8 @@ func (h *Handler) AdminCreateCoupon(c *gin.Context) {
 		PerUserLimit:     req.PerUserLimit,
 		Scope:            req.Scope,
 		ScopeIDs:         req.ScopeIDs,
+		Stacking:         req.Stacking,
+		Priority:         req.Priority,
 		CreatedBy:        "platform",
 		StartsAt:         startsAt,
 		ExpiresAt:        expiresAt,
@@ -425,6 +613,98 @@ func (h *Handler) AdminCreateCoupon(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"data": toCouponResponse(coupon)})
 }
 
+// AdminGenerateCoupons handles POST /api/v1/admin/promotions/coupons/bulk
+func (h *Handler) AdminGenerateCoupons(c *gin.Context) {
+	var req generateCodesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var startsAt time.Time
+	if req.StartsAt != "" {
+		var err error
+		startsAt, err = time.Parse(time.RFC3339, req.StartsAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid starts_at format"})
+			return
+		}
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, req.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expires_at format"})
+		return
+	}
+
+	campaignID, coupons, err := h.couponUC.GenerateCouponCodes(c.Request.Context(), usecase.GenerateCodesInput{
+		Template: usecase.CreateCouponInput{
+			Type:             req.Type,
+			DiscountValue:    req.DiscountValue,
+			MinOrderCents:    req.MinOrderCents,
+			MaxDiscountCents: req.MaxDiscountCents,
+			Scope:            req.Scope,
+			ScopeIDs:         req.ScopeIDs,
+			Stacking:         req.Stacking,
+			Priority:         req.Priority,
+			CreatedBy:        domain.PlatformCreator,
+			StartsAt:         startsAt,
+			ExpiresAt:        expiresAt,
+		},
+		Prefix:     req.Prefix,
+		Count:      req.Count,
+		CodeLength: req.CodeLength,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes := make([]string, len(coupons))
+	for i, cp := range coupons {
+		codes[i] = cp.Code
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data": gin.H{
+			"campaign_id": campaignID,
+			"count":       len(codes),
+			"codes":       codes,
+		},
+	})
+}
+
+// AdminListCampaignCoupons handles GET /api/v1/admin/promotions/campaigns/:id/coupons
+func (h *Handler) AdminListCampaignCoupons(c *gin.Context) {
+	campaignID := c.Param("id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "100"))
+
+	coupons, total, err := h.couponUC.ListCampaignCoupons(c.Request.Context(), campaignID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var resp []couponResponse
+	for _, cp := range coupons {
+		resp = append(resp, toCouponResponse(cp))
+	}
+
+	totalPages := total / int64(pageSize)
+	if total%int64(pageSize) != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, listResponse{
+		Data:       resp,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	})
+}
+
 // AdminListCoupons handles GET /api/v1/admin/promotions/coupons
 func (h *Handler) AdminListCoupons(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -485,6 +765,9 @@ func (h *Handler) AdminUpdateCoupon(c *gin.Context) {
 	if req.IsActive != nil {
 		coupon.IsActive = *req.IsActive
 	}
+	if req.Priority != nil {
+		coupon.Priority = *req.Priority
+	}
 	if req.UsageLimit != nil {
 		coupon.UsageLimit = *req.UsageLimit
 	}
@@ -548,6 +831,17 @@ func (h *Handler) ListActiveFlashSales(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
+// GetFlashSale handles GET /api/v1/flash-sales/:id
+// It returns a live sale with the remaining quantity of each item.
+func (h *Handler) GetFlashSale(c *gin.Context) {
+	flashSale, err := h.flashSaleUC.GetLiveFlashSale(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toFlashSaleResponse(flashSale)})
+}
+
 // AdminCreateFlashSale handles POST /api/v1/admin/promotions/flash-sales
 func (h *Handler) AdminCreateFlashSale(c *gin.Context) {
 	var req createFlashSaleRequest
@@ -575,6 +869,7 @@ func (h *Handler) AdminCreateFlashSale(c *gin.Context) {
 			VariantID:      item.VariantID,
 			SalePriceCents: item.SalePriceCents,
 			QuantityLimit:  item.QuantityLimit,
+			PerBuyerLimit:  item.PerBuyerLimit,
 		})
 	}
 
@@ -696,6 +991,40 @@ func (h *Handler) ListActiveBundles(c *gin.Context) {
 	})
 }
 
+// ApplyBundles handles POST /api/v1/bundles/apply
+func (h *Handler) ApplyBundles(c *gin.Context) {
+	var req applyBundlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.bundleUC.ApplyBundles(c.Request.Context(), toOrderLines(req.Lines))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	applied := []gin.H{}
+	for _, a := range result.Applied {
+		applied = append(applied, gin.H{
+			"bundle":           toBundleResponse(a.Bundle),
+			"sets":             a.Sets,
+			"list_price_cents": a.ListPriceCents,
+			"price_cents":      a.PriceCents,
+			"discount_cents":   a.DiscountCents,
+			"allocations":      a.Allocations,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"applied":              applied,
+			"total_discount_cents": result.TotalDiscountCents,
+		},
+	})
+}
+
 // AdminCreateBundle handles POST /api/v1/admin/promotions/bundles
 func (h *Handler) AdminCreateBundle(c *gin.Context) {
 	var req createBundleRequest
@@ -704,15 +1033,18 @@ func (h *Handler) AdminCreateBundle(c *gin.Context) {
 		return
 	}
 
-	sellerID := c.GetHeader("X-User-ID")
+	// Bundles are always priced by one seller; admins create them on a
+	// seller's behalf.
+	sellerID := req.SellerID
 	if sellerID == "" {
-		sellerID = "platform"
+		sellerID = c.GetHeader("X-User-ID")
 	}
 
 	bundle, err := h.bundleUC.CreateBundle(c.Request.Context(), usecase.CreateBundleInput{
 		Name:             req.Name,
 		SellerID:         sellerID,
 		ProductIDs:       req.ProductIDs,
+		Items:            req.Items,
 		BundlePriceCents: req.BundlePriceCents,
 		SavingsCents:     req.SavingsCents,
 	})
@@ -795,7 +1127,7 @@ func (h *Handler) AdminUpdateBundle(c *gin.Context) {
 	}
 
 	if err := h.bundleUC.UpdateBundle(c.Request.Context(), bundle); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -821,6 +1153,336 @@ func (h *Handler) AdminDeleteBundle(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "bundle deactivated"})
 }
 
+// --- Automatic Promotion Handlers ---
+
+// EvaluatePromotions handles POST /api/v1/promotions/evaluate
+func (h *Handler) EvaluatePromotions(c *gin.Context) {
+	var req evaluatePromotionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.promotionUC.EvaluatePromotions(c.Request.Context(), usecase.EvaluatePromotionsInput{
+		UserID: c.GetHeader("X-User-ID"),
+		Lines:  toOrderLines(req.Lines),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	applied := []gin.H{}
+	for _, a := range result.Applied {
+		applied = append(applied, gin.H{
+			"promotion_id":   a.Promotion.ID,
+			"name":           a.Promotion.Name,
+			"discount_cents": a.DiscountCents,
+			"allocations":    a.Allocations,
+			"gifts":          a.Gifts,
+		})
+	}
+	lines := []gin.H{}
+	for _, l := range result.Lines {
+		explanations := l.Explanations
+		if explanations == nil {
+			explanations = []string{}
+		}
+		lines = append(lines, gin.H{
+			"line_id":        l.LineID,
+			"discount_cents": l.DiscountCents,
+			"explanations":   explanations,
+		})
+	}
+	gifts := result.Gifts
+	if gifts == nil {
+		gifts = []domain.GiftItem{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"applied":              applied,
+			"lines":                lines,
+			"gifts":                gifts,
+			"total_discount_cents": result.TotalDiscountCents,
+		},
+	})
+}
+
+// AdminCreatePromotion handles POST /api/v1/admin/promotions/automatic
+func (h *Handler) AdminCreatePromotion(c *gin.Context) {
+	var req createPromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	endsAt, err := time.Parse(time.RFC3339, req.EndsAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ends_at format"})
+		return
+	}
+
+	var startsAt time.Time
+	if req.StartsAt != "" {
+		startsAt, err = time.Parse(time.RFC3339, req.StartsAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid starts_at format"})
+			return
+		}
+	}
+
+	promotion, err := h.promotionUC.CreatePromotion(c.Request.Context(), usecase.CreateAutomaticPromotionInput{
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedBy:   domain.PlatformCreator,
+		Conditions:  req.Conditions,
+		Actions:     req.Actions,
+		Priority:    req.Priority,
+		Exclusive:   req.Exclusive,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"data": toPromotionResponse(promotion)})
+}
+
+// AdminListPromotions handles GET /api/v1/admin/promotions/automatic
+func (h *Handler) AdminListPromotions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	promotions, total, err := h.promotionUC.ListPromotions(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var resp []promotionResponse
+	for _, p := range promotions {
+		resp = append(resp, toPromotionResponse(p))
+	}
+
+	totalPages := total / int64(pageSize)
+	if total%int64(pageSize) != 0 {
+		totalPages++
+	}
+
+	c.JSON(http.StatusOK, listResponse{
+		Data:       resp,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	})
+}
+
+// AdminGetPromotion handles GET /api/v1/admin/promotions/automatic/:id
+func (h *Handler) AdminGetPromotion(c *gin.Context) {
+	promotion, err := h.promotionUC.GetPromotion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toPromotionResponse(promotion)})
+}
+
+// AdminUpdatePromotion handles PATCH /api/v1/admin/promotions/automatic/:id
+func (h *Handler) AdminUpdatePromotion(c *gin.Context) {
+	promotion, err := h.promotionUC.GetPromotion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req updatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Name != nil {
+		promotion.Name = *req.Name
+	}
+	if req.Description != nil {
+		promotion.Description = *req.Description
+	}
+	if req.Conditions != nil {
+		promotion.Conditions = req.Conditions
+	}
+	if req.Actions != nil {
+		promotion.Actions = req.Actions
+	}
+	if req.Priority != nil {
+		promotion.Priority = *req.Priority
+	}
+	if req.Exclusive != nil {
+		promotion.Exclusive = *req.Exclusive
+	}
+	if req.IsActive != nil {
+		promotion.IsActive = *req.IsActive
+	}
+	if req.EndsAt != nil {
+		endsAt, err := time.Parse(time.RFC3339, *req.EndsAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ends_at format"})
+			return
+		}
+		promotion.EndsAt = endsAt
+	}
+
+	if err := h.promotionUC.UpdatePromotion(c.Request.Context(), promotion); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": toPromotionResponse(promotion)})
+}
+
+// --- Analytics Handlers ---
+
+// AdminPromotionAnalytics handles GET /api/v1/admin/promotions/analytics
+func (h *Handler) AdminPromotionAnalytics(c *gin.Context) {
+	query, err := parseAnalyticsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query.SellerID = c.Query("seller_id")
+
+	report, err := h.analyticsUC.PromotionReport(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(analyticsErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": toPromotionPerformanceResponses(report),
+		"from": query.From.Format("2006-01-02T15:04:05Z"),
+		"to":   query.To.Format("2006-01-02T15:04:05Z"),
+	})
+}
+
+// AdminSellerAnalytics handles GET /api/v1/admin/promotions/analytics/sellers
+func (h *Handler) AdminSellerAnalytics(c *gin.Context) {
+	query, err := parseAnalyticsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.analyticsUC.SellerReport(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(analyticsErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := make([]sellerPerformanceResponse, 0, len(report))
+	for _, perf := range report {
+		resp = append(resp, toSellerPerformanceResponse(perf))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": resp,
+		"from": query.From.Format("2006-01-02T15:04:05Z"),
+		"to":   query.To.Format("2006-01-02T15:04:05Z"),
+	})
+}
+
+// SellerPromotionAnalytics handles GET /api/v1/seller/promotions/analytics
+func (h *Handler) SellerPromotionAnalytics(c *gin.Context) {
+	sellerID := c.GetHeader("X-User-ID")
+	if sellerID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "X-User-ID header is required"})
+		return
+	}
+
+	query, err := parseAnalyticsQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	query.SellerID = sellerID
+
+	promotions, err := h.analyticsUC.PromotionReport(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(analyticsErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	sellers, err := h.analyticsUC.SellerReport(c.Request.Context(), query)
+	if err != nil {
+		c.JSON(analyticsErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	summary := usecase.SellerPerformance{SellerID: sellerID}
+	if len(sellers) > 0 {
+		summary = sellers[0]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"summary":    toSellerPerformanceResponse(summary),
+			"promotions": toPromotionPerformanceResponses(promotions),
+		},
+		"from": query.From.Format("2006-01-02T15:04:05Z"),
+		"to":   query.To.Format("2006-01-02T15:04:05Z"),
+	})
+}
+
+// parseAnalyticsQuery reads the from, to and kind query parameters. Dates
+// may be given as RFC 3339 timestamps or as YYYY-MM-DD, in which case to is
+// inclusive. The range defaults to the last 30 days.
+func parseAnalyticsQuery(c *gin.Context) (usecase.AnalyticsQuery, error) {
+	query := usecase.AnalyticsQuery{
+		To:   time.Now().UTC(),
+		Kind: domain.PromotionKind(c.Query("kind")),
+	}
+
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			day, dayErr := time.Parse("2006-01-02", raw)
+			if dayErr != nil {
+				return query, errors.New("invalid to format")
+			}
+			to = day.AddDate(0, 0, 1)
+		}
+		query.To = to
+	}
+
+	query.From = query.To.AddDate(0, 0, -30)
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			from, err = time.Parse("2006-01-02", raw)
+			if err != nil {
+				return query, errors.New("invalid from format")
+			}
+		}
+		query.From = from
+	}
+
+	switch query.Kind {
+	case "", domain.PromotionKindCoupon, domain.PromotionKindFlashSale, domain.PromotionKindBundle:
+	default:
+		return query, errors.New("kind must be one of coupon, flash_sale, bundle")
+	}
+
+	return query, nil
+}
+
+func analyticsErrorStatus(err error) int {
+	if errors.Is(err, usecase.ErrInvalidAnalyticsRange) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
 // Health handles GET /health
 func (h *Handler) Health(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
@@ -845,6 +1507,9 @@ func toCouponResponse(c *domain.Coupon) couponResponse {
 		PerUserLimit:     c.PerUserLimit,
 		Scope:            string(c.Scope),
 		ScopeIDs:         scopeIDs,
+		CampaignID:       c.CampaignID,
+		Stacking:         string(c.Stacking),
+		Priority:         c.Priority,
 		CreatedBy:        c.CreatedBy,
 		StartsAt:         c.StartsAt.Format("2006-01-02T15:04:05Z"),
 		ExpiresAt:        c.ExpiresAt.Format("2006-01-02T15:04:05Z"),
@@ -853,6 +1518,22 @@ func toCouponResponse(c *domain.Coupon) couponResponse {
 	}
 }
 
+func toOrderLines(dtos []orderLineDTO) []domain.OrderLine {
+	lines := make([]domain.OrderLine, len(dtos))
+	for i, l := range dtos {
+		lines[i] = domain.OrderLine{
+			LineID:         l.LineID,
+			ProductID:      l.ProductID,
+			VariantID:      l.VariantID,
+			CategoryID:     l.CategoryID,
+			SellerID:       l.SellerID,
+			Quantity:       l.Quantity,
+			UnitPriceCents: l.UnitPriceCents,
+		}
+	}
+	return lines
+}
+
 func toFlashSaleResponse(fs *domain.FlashSale) flashSaleResponse {
 	resp := flashSaleResponse{
 		ID:        fs.ID,
@@ -860,6 +1541,7 @@ func toFlashSaleResponse(fs *domain.FlashSale) flashSaleResponse {
 		StartsAt:  fs.StartsAt.Format("2006-01-02T15:04:05Z"),
 		EndsAt:    fs.EndsAt.Format("2006-01-02T15:04:05Z"),
 		IsActive:  fs.IsActive,
+		Status:    string(fs.Status),
 		CreatedAt: fs.CreatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 	for _, item := range fs.Items {
@@ -870,7 +1552,9 @@ func toFlashSaleResponse(fs *domain.FlashSale) flashSaleResponse {
 			VariantID:      item.VariantID,
 			SalePriceCents: item.SalePriceCents,
 			QuantityLimit:  item.QuantityLimit,
+			PerBuyerLimit:  item.PerBuyerLimit,
 			SoldCount:      item.SoldCount,
+			Remaining:      item.Remaining(),
 		})
 	}
 	if resp.Items == nil {
@@ -879,19 +1563,85 @@ func toFlashSaleResponse(fs *domain.FlashSale) flashSaleResponse {
 	return resp
 }
 
+func toPromotionResponse(p *domain.AutomaticPromotion) promotionResponse {
+	conditions := p.Conditions
+	if conditions == nil {
+		conditions = []domain.PromotionCondition{}
+	}
+	actions := p.Actions
+	if actions == nil {
+		actions = []domain.PromotionAction{}
+	}
+	return promotionResponse{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		CreatedBy:   p.CreatedBy,
+		Conditions:  conditions,
+		Actions:     actions,
+		Priority:    p.Priority,
+		Exclusive:   p.Exclusive,
+		StartsAt:    p.StartsAt.Format("2006-01-02T15:04:05Z"),
+		EndsAt:      p.EndsAt.Format("2006-01-02T15:04:05Z"),
+		IsActive:    p.IsActive,
+		CreatedAt:   p.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
 func toBundleResponse(b *domain.Bundle) bundleResponse {
 	productIDs := b.ProductIDs
 	if productIDs == nil {
 		productIDs = []string{}
 	}
+	items := b.Items
+	if items == nil {
+		items = []domain.BundleItem{}
+	}
 	return bundleResponse{
 		ID:               b.ID,
 		Name:             b.Name,
 		SellerID:         b.SellerID,
 		ProductIDs:       productIDs,
+		Items:            items,
 		BundlePriceCents: b.BundlePriceCents,
 		SavingsCents:     b.SavingsCents,
 		IsActive:         b.IsActive,
 		CreatedAt:        b.CreatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
+
+func toPerformanceMetricsResponse(m usecase.PerformanceMetrics) performanceMetricsResponse {
+	return performanceMetricsResponse{
+		Redemptions:             m.Redemptions,
+		CancelledRedemptions:    m.CancelledRedemptions,
+		DiscountCostCents:       m.DiscountCostCents,
+		AttributedRevenueCents:  m.AttributedRevenueCents,
+		RefundedCents:           m.RefundedCents,
+		NetRevenueCents:         m.NetRevenueCents,
+		AverageOrderValueCents:  m.AverageOrderValueCents,
+		BaselineOrderValueCents: m.BaselineOrderValueCents,
+		AOVUpliftBasisPoints:    m.AOVUpliftBasisPoints,
+	}
+}
+
+func toPromotionPerformanceResponses(report []usecase.PromotionPerformance) []promotionPerformanceResponse {
+	resp := make([]promotionPerformanceResponse, 0, len(report))
+	for _, perf := range report {
+		resp = append(resp, promotionPerformanceResponse{
+			Kind:                       string(perf.Kind),
+			PromotionID:                perf.PromotionID,
+			PromotionName:              perf.PromotionName,
+			SellerID:                   perf.SellerID,
+			performanceMetricsResponse: toPerformanceMetricsResponse(perf.PerformanceMetrics),
+		})
+	}
+	return resp
+}
+
+func toSellerPerformanceResponse(perf usecase.SellerPerformance) sellerPerformanceResponse {
+	return sellerPerformanceResponse{
+		SellerID:                   perf.SellerID,
+		Promotions:                 perf.Promotions,
+		performanceMetricsResponse: toPerformanceMetricsResponse(perf.PerformanceMetrics),
+	}
+}
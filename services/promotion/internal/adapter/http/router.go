@@ -21,6 +21,7 @@ func NewRouter(handler *Handler) *gin.Engine {
 		coupons := v1.Group("/coupons")
 		{
 			coupons.POST("/validate", handler.ValidateCoupon)
+			coupons.POST("/apply", handler.ApplyCoupons)
 			coupons.GET("", handler.ListActiveCoupons)
 		}
 
@@ -32,13 +33,19 @@ func NewRouter(handler *Handler) *gin.Engine {
 			seller.GET("/coupons/:id", handler.GetSellerCoupon)
 			seller.PATCH("/coupons/:id", handler.UpdateSellerCoupon)
 			seller.DELETE("/coupons/:id", handler.DeleteSellerCoupon)
+			seller.GET("/promotions/analytics", handler.SellerPromotionAnalytics)
 		}
 
 		// Public flash sale routes
 		v1.GET("/flash-sales", handler.ListActiveFlashSales)
+		v1.GET("/flash-sales/:id", handler.GetFlashSale)
+
+		// Public automatic promotion routes
+		v1.POST("/promotions/evaluate", handler.EvaluatePromotions)
 
 		// Public bundle routes
 		v1.GET("/bundles", handler.ListActiveBundles)
+		v1.POST("/bundles/apply", handler.ApplyBundles)
 
 		// Admin routes
 		admin := v1.Group("/admin/promotions")
@@ -47,12 +54,16 @@ func NewRouter(handler *Handler) *gin.Engine {
 			adminCoupons := admin.Group("/coupons")
 			{
 				adminCoupons.POST("", handler.AdminCreateCoupon)
+				adminCoupons.POST("/bulk", handler.AdminGenerateCoupons)
 				adminCoupons.GET("", handler.AdminListCoupons)
 				adminCoupons.GET("/:id", handler.AdminGetCoupon)
 				adminCoupons.PATCH("/:id", handler.AdminUpdateCoupon)
 				adminCoupons.DELETE("/:id", handler.AdminDeleteCoupon)
 			}
 
+			// Admin campaign code routes
+			admin.GET("/campaigns/:id/coupons", handler.AdminListCampaignCoupons)
+
 			// Admin flash sale routes
 			adminFlashSales := admin.Group("/flash-sales")
 			{
@@ -62,6 +73,19 @@ func NewRouter(handler *Handler) *gin.Engine {
 				adminFlashSales.PATCH("/:id", handler.AdminUpdateFlashSale)
 			}
 
+			// Admin automatic promotion routes
+			adminPromotions := admin.Group("/automatic")
+			{
+				adminPromotions.POST("", handler.AdminCreatePromotion)
+				adminPromotions.GET("", handler.AdminListPromotions)
+				adminPromotions.GET("/:id", handler.AdminGetPromotion)
+				adminPromotions.PATCH("/:id", handler.AdminUpdatePromotion)
+			}
+
+			// Admin analytics routes
+			admin.GET("/analytics", handler.AdminPromotionAnalytics)
+			admin.GET("/analytics/sellers", handler.AdminSellerAnalytics)
+
 			// Admin bundle routes
 			adminBundles := admin.Group("/bundles")
 			{
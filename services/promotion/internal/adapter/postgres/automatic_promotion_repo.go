@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AutomaticPromotionRepo implements domain.AutomaticPromotionRepository using GORM/Postgres.
+type AutomaticPromotionRepo struct {
+	db *gorm.DB
+}
+
+// NewAutomaticPromotionRepo creates a new AutomaticPromotionRepo.
+func NewAutomaticPromotionRepo(db *gorm.DB) *AutomaticPromotionRepo {
+	return &AutomaticPromotionRepo{db: db}
+}
+
+// GetByID retrieves an automatic promotion by its UUID.
+func (r *AutomaticPromotionRepo) GetByID(ctx context.Context, id string) (*domain.AutomaticPromotion, error) {
+	var model AutomaticPromotionModel
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("promotion not found")
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// ListAll retrieves a paginated list of all automatic promotions.
+func (r *AutomaticPromotionRepo) ListAll(ctx context.Context, page, pageSize int) ([]*domain.AutomaticPromotion, int64, error) {
+	var models []AutomaticPromotionModel
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&AutomaticPromotionModel{})
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var promotions []*domain.AutomaticPromotion
+	for i := range models {
+		promotions = append(promotions, models[i].ToDomain())
+	}
+	return promotions, total, nil
+}
+
+// ListActive retrieves the active promotions running at now, highest priority first.
+func (r *AutomaticPromotionRepo) ListActive(ctx context.Context, now time.Time) ([]*domain.AutomaticPromotion, error) {
+	var models []AutomaticPromotionModel
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND starts_at <= ? AND ends_at > ?", true, now, now).
+		Order("priority DESC, created_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var promotions []*domain.AutomaticPromotion
+	for i := range models {
+		promotions = append(promotions, models[i].ToDomain())
+	}
+	return promotions, nil
+}
+
+// Create persists a new automatic promotion.
+func (r *AutomaticPromotionRepo) Create(ctx context.Context, promotion *domain.AutomaticPromotion) error {
+	model := ToAutomaticPromotionModel(promotion)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+// Update persists all changes to an existing automatic promotion.
+func (r *AutomaticPromotionRepo) Update(ctx context.Context, promotion *domain.AutomaticPromotion) error {
+	model := ToAutomaticPromotionModel(promotion)
+	return r.db.WithContext(ctx).Save(model).Error
+}
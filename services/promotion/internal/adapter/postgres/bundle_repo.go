@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/lib/pq"
 	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
 	"gorm.io/gorm"
 )
@@ -95,6 +96,23 @@ func (r *BundleRepo) Create(ctx context.Context, bundle *domain.Bundle) error {
 	return r.db.WithContext(ctx).Create(model).Error
 }
 
+// ListActiveForProducts retrieves the active bundles containing any of the given products.
+func (r *BundleRepo) ListActiveForProducts(ctx context.Context, productIDs []string) ([]*domain.Bundle, error) {
+	var models []BundleModel
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND product_ids && ?", true, pq.StringArray(productIDs)).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var bundles []*domain.Bundle
+	for i := range models {
+		bundles = append(bundles, models[i].ToDomain())
+	}
+	return bundles, nil
+}
+
 // Update persists all changes to an existing bundle.
 func (r *BundleRepo) Update(ctx context.Context, bundle *domain.Bundle) error {
 	model := ToBundleModel(bundle)
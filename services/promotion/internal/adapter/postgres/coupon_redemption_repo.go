@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CouponRedemptionRepo implements domain.CouponRedemptionRepository using
+// GORM/Postgres transactions with row locks on the coupons table.
+type CouponRedemptionRepo struct {
+	db *gorm.DB
+}
+
+// NewCouponRedemptionRepo creates a new CouponRedemptionRepo.
+func NewCouponRedemptionRepo(db *gorm.DB) *CouponRedemptionRepo {
+	return &CouponRedemptionRepo{db: db}
+}
+
+// Reserve locks each usage's coupon row, checks its limits, increments the
+// usage count and upserts the usage record, all in one transaction. Coupon
+// rows are locked in coupon ID order so that concurrent reservations of
+// the same coupons cannot deadlock. A usage already reserved or confirmed
+// for the same coupon and order is kept as it is and not counted again, so
+// retried reservations are idempotent; usage is then filled in from it.
+func (r *CouponRedemptionRepo) Reserve(ctx context.Context, usages []*domain.CouponUsage) error {
+	ordered := append([]*domain.CouponUsage(nil), usages...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].CouponID < ordered[j].CouponID
+	})
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, usage := range ordered {
+			var coupon CouponModel
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", usage.CouponID).
+				First(&coupon).Error
+			if err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("coupon not found")
+				}
+				return err
+			}
+
+			var existing []CouponUsageModel
+			err = tx.Where("coupon_id = ? AND order_id = ?", usage.CouponID, usage.OrderID).
+				Limit(1).
+				Find(&existing).Error
+			if err != nil {
+				return err
+			}
+			if len(existing) > 0 && existing[0].Status != string(domain.CouponUsageReleased) {
+				*usage = *existing[0].ToDomain()
+				continue
+			}
+
+			if coupon.UsageLimit > 0 && coupon.UsageCount >= coupon.UsageLimit {
+				return domain.ErrCouponUsageLimitReached
+			}
+			if coupon.PerUserLimit > 0 {
+				count, err := countActiveUsages(tx, usage.UserID, usage.CouponID)
+				if err != nil {
+					return err
+				}
+				if count >= int64(coupon.PerUserLimit) {
+					return domain.ErrCouponPerUserLimitReached
+				}
+			}
+
+			err = tx.Model(&CouponModel{}).
+				Where("id = ?", usage.CouponID).
+				UpdateColumn("usage_count", gorm.Expr("usage_count + 1")).Error
+			if err != nil {
+				return err
+			}
+
+			// A usage released for the order is taken over rather than
+			// duplicated
+			if len(existing) > 0 {
+				usage.ID = existing[0].ID
+				usage.CreatedAt = existing[0].CreatedAt
+			}
+			err = tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "coupon_id"}, {Name: "order_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"user_id", "discount_cents", "allocations", "status", "updated_at"}),
+			}).Create(ToCouponUsageModel(usage)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ConfirmByOrder marks the order's reserved usages as confirmed.
+func (r *CouponRedemptionRepo) ConfirmByOrder(ctx context.Context, orderID string) ([]*domain.CouponUsage, error) {
+	var confirmed []*domain.CouponUsage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var models []CouponUsageModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_id = ? AND status = ?", orderID, domain.CouponUsageReserved).
+			Find(&models).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range models {
+			err := tx.Model(&CouponUsageModel{}).
+				Where("id = ?", models[i].ID).
+				Update("status", domain.CouponUsageConfirmed).Error
+			if err != nil {
+				return err
+			}
+			models[i].Status = string(domain.CouponUsageConfirmed)
+			confirmed = append(confirmed, models[i].ToDomain())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return confirmed, nil
+}
+
+// ReleaseByOrder marks the order's reserved and confirmed usages as released
+// and decrements the usage count of their coupons.
+func (r *CouponRedemptionRepo) ReleaseByOrder(ctx context.Context, orderID string) ([]*domain.CouponUsage, error) {
+	return r.release(ctx, "order_id = ?", orderID)
+}
+
+// ReleaseByIDs marks the given reserved and confirmed usages as released
+// and decrements the usage count of their coupons.
+func (r *CouponRedemptionRepo) ReleaseByIDs(ctx context.Context, ids []string) ([]*domain.CouponUsage, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.release(ctx, "id IN ?", ids)
+}
+
+func (r *CouponRedemptionRepo) release(ctx context.Context, query string, arg interface{}) ([]*domain.CouponUsage, error) {
+	var released []*domain.CouponUsage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var models []CouponUsageModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where(query+" AND status <> ?", arg, domain.CouponUsageReleased).
+			Find(&models).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range models {
+			err := tx.Model(&CouponUsageModel{}).
+				Where("id = ?", models[i].ID).
+				Update("status", domain.CouponUsageReleased).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&CouponModel{}).
+				Where("id = ? AND usage_count > 0", models[i].CouponID).
+				UpdateColumn("usage_count", gorm.Expr("usage_count - 1")).Error
+			if err != nil {
+				return err
+			}
+
+			models[i].Status = string(domain.CouponUsageReleased)
+			released = append(released, models[i].ToDomain())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+// DedupeCouponUsages removes all but the oldest usage of each coupon by
+// each order, returning the removed usages to their coupons' usage counts,
+// so that the unique (coupon_id, order_id) index can be created on tables
+// filled before reservations were idempotent.
+func DedupeCouponUsages(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&CouponUsageModel{}) ||
+		db.Migrator().HasIndex(&CouponUsageModel{}, "idx_coupon_usages_coupon_order") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		const duplicates = `
+			SELECT id, coupon_id, status FROM (
+				SELECT id, coupon_id, status,
+					row_number() OVER (PARTITION BY coupon_id, order_id ORDER BY created_at, id) AS n
+				FROM coupon_usages
+			) AS usage WHERE n > 1`
+
+		var rows []CouponUsageModel
+		if err := tx.Raw(duplicates).Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if row.Status != string(domain.CouponUsageReleased) {
+				err := tx.Model(&CouponModel{}).
+					Where("id = ? AND usage_count > 0", row.CouponID).
+					UpdateColumn("usage_count", gorm.Expr("usage_count - 1")).Error
+				if err != nil {
+					return err
+				}
+			}
+			if err := tx.Delete(&CouponUsageModel{}, "id = ?", row.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
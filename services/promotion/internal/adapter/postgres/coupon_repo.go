@@ -104,12 +104,59 @@ func (r *CouponRepo) ListBySeller(ctx context.Context, sellerID string, page, pa
 	return coupons, total, nil
 }
 
+// ListByCampaign retrieves a paginated list of the coupons generated for a campaign.
+func (r *CouponRepo) ListByCampaign(ctx context.Context, campaignID string, page, pageSize int) ([]*domain.Coupon, int64, error) {
+	var models []CouponModel
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&CouponModel{}).Where("campaign_id = ?", campaignID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.
+		Order("code ASC").
+		Offset(offset).
+		Limit(pageSize).
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var coupons []*domain.Coupon
+	for i := range models {
+		coupons = append(coupons, models[i].ToDomain())
+	}
+	return coupons, total, nil
+}
+
 // Create persists a new coupon.
 func (r *CouponRepo) Create(ctx context.Context, coupon *domain.Coupon) error {
 	model := ToCouponModel(coupon)
 	return r.db.WithContext(ctx).Create(model).Error
 }
 
+// CreateBatch persists many coupons in a single transaction.
+func (r *CouponRepo) CreateBatch(ctx context.Context, coupons []*domain.Coupon) error {
+	models := make([]*CouponModel, len(coupons))
+	for i, c := range coupons {
+		models[i] = ToCouponModel(c)
+	}
+	return r.db.WithContext(ctx).CreateInBatches(models, 500).Error
+}
+
+// ExistingCodes returns the subset of codes that already belong to a coupon.
+func (r *CouponRepo) ExistingCodes(ctx context.Context, codes []string) ([]string, error) {
+	var existing []string
+	err := r.db.WithContext(ctx).
+		Model(&CouponModel{}).
+		Where("code IN ?", codes).
+		Pluck("code", &existing).Error
+	return existing, err
+}
+
 // Update persists all changes to an existing coupon.
 func (r *CouponRepo) Update(ctx context.Context, coupon *domain.Coupon) error {
 	model := ToCouponModel(coupon)
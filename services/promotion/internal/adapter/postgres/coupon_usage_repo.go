@@ -34,12 +34,36 @@ func (r *CouponUsageRepo) GetByUserAndCoupon(ctx context.Context, userID, coupon
 	return usages, nil
 }
 
-// CountByUser counts the number of times a user has used a specific coupon.
+// CountByUser counts the number of times a user has used a specific coupon,
+// ignoring released usages.
 func (r *CouponUsageRepo) CountByUser(ctx context.Context, userID, couponID string) (int64, error) {
-	var count int64
+	return countActiveUsages(r.db.WithContext(ctx), userID, couponID)
+}
+
+// ListByOrder retrieves all usage records for an order.
+func (r *CouponUsageRepo) ListByOrder(ctx context.Context, orderID string) ([]*domain.CouponUsage, error) {
+	var models []CouponUsageModel
 	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var usages []*domain.CouponUsage
+	for i := range models {
+		usages = append(usages, models[i].ToDomain())
+	}
+	return usages, nil
+}
+
+// countActiveUsages counts a user's reserved and confirmed usages of a coupon.
+func countActiveUsages(db *gorm.DB, userID, couponID string) (int64, error) {
+	var count int64
+	err := db.
 		Model(&CouponUsageModel{}).
-		Where("user_id = ? AND coupon_id = ?", userID, couponID).
+		Where("user_id = ? AND coupon_id = ? AND status <> ?", userID, couponID, domain.CouponUsageReleased).
 		Count(&count).Error
 	return count, err
 }
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+	"gorm.io/gorm"
+)
+
+// FlashSaleClaimRepo implements domain.FlashSaleClaimRepository using GORM/Postgres.
+type FlashSaleClaimRepo struct {
+	db *gorm.DB
+}
+
+// NewFlashSaleClaimRepo creates a new FlashSaleClaimRepo.
+func NewFlashSaleClaimRepo(db *gorm.DB) *FlashSaleClaimRepo {
+	return &FlashSaleClaimRepo{db: db}
+}
+
+// Create persists a new flash sale claim.
+func (r *FlashSaleClaimRepo) Create(ctx context.Context, claim *domain.FlashSaleClaim) error {
+	model := ToFlashSaleClaimModel(claim)
+	return r.db.WithContext(ctx).Create(model).Error
+}
+
+// ListActiveByOrder retrieves the active claims held by an order.
+func (r *FlashSaleClaimRepo) ListActiveByOrder(ctx context.Context, orderID string) ([]*domain.FlashSaleClaim, error) {
+	var models []FlashSaleClaimModel
+	err := r.db.WithContext(ctx).
+		Where("order_id = ? AND status = ?", orderID, domain.FlashSaleClaimActive).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var claims []*domain.FlashSaleClaim
+	for i := range models {
+		claims = append(claims, models[i].ToDomain())
+	}
+	return claims, nil
+}
+
+// Release marks an active claim as released. It reports false when the claim
+// had already been released.
+func (r *FlashSaleClaimRepo) Release(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&FlashSaleClaimModel{}).
+		Where("id = ? AND status = ?", id, domain.FlashSaleClaimActive).
+		Update("status", domain.FlashSaleClaimReleased)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListActiveByItem retrieves the active claims on an item.
+func (r *FlashSaleClaimRepo) ListActiveByItem(ctx context.Context, itemID string) ([]*domain.FlashSaleClaim, error) {
+	var models []FlashSaleClaimModel
+	err := r.db.WithContext(ctx).
+		Where("flash_sale_item_id = ? AND status = ?", itemID, domain.FlashSaleClaimActive).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var claims []*domain.FlashSaleClaim
+	for i := range models {
+		claims = append(claims, models[i].ToDomain())
+	}
+	return claims, nil
+}
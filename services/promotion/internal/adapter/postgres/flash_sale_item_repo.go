@@ -18,6 +18,19 @@ func NewFlashSaleItemRepo(db *gorm.DB) *FlashSaleItemRepo {
 	return &FlashSaleItemRepo{db: db}
 }
 
+// GetByID retrieves a flash sale item by its UUID.
+func (r *FlashSaleItemRepo) GetByID(ctx context.Context, id string) (*domain.FlashSaleItem, error) {
+	var model FlashSaleItemModel
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("flash sale item not found")
+		}
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
 // GetByFlashSaleID retrieves all items for a given flash sale.
 func (r *FlashSaleItemRepo) GetByFlashSaleID(ctx context.Context, flashSaleID string) ([]*domain.FlashSaleItem, error) {
 	var models []FlashSaleItemModel
@@ -55,3 +68,12 @@ func (r *FlashSaleItemRepo) IncrementSoldCount(ctx context.Context, id string) e
 	}
 	return nil
 }
+
+// SetSoldCount overwrites the sold count of a flash sale item, e.g. when
+// reconciling it with the live claim counters.
+func (r *FlashSaleItemRepo) SetSoldCount(ctx context.Context, id string, soldCount int) error {
+	return r.db.WithContext(ctx).
+		Model(&FlashSaleItemModel{}).
+		Where("id = ?", id).
+		UpdateColumn("sold_count", soldCount).Error
+}
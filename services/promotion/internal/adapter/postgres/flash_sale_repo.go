@@ -35,13 +35,13 @@ func (r *FlashSaleRepo) GetByID(ctx context.Context, id string) (*domain.FlashSa
 	return model.ToDomain(), nil
 }
 
-// ListActive retrieves all currently active flash sales.
+// ListActive retrieves all live flash sales whose window is open.
 func (r *FlashSaleRepo) ListActive(ctx context.Context) ([]*domain.FlashSale, error) {
 	var models []FlashSaleModel
 	now := time.Now()
 	err := r.db.WithContext(ctx).
 		Preload("Items").
-		Where("is_active = ? AND starts_at <= ? AND ends_at >= ?", true, now, now).
+		Where("is_active = ? AND status = ? AND starts_at <= ? AND ends_at >= ?", true, domain.FlashSaleLive, now, now).
 		Order("starts_at ASC").
 		Find(&models).Error
 	if err != nil {
@@ -90,8 +90,58 @@ func (r *FlashSaleRepo) Create(ctx context.Context, flashSale *domain.FlashSale)
 	return r.db.WithContext(ctx).Create(model).Error
 }
 
-// Update persists all changes to an existing flash sale.
+// Update persists all changes to an existing flash sale. The status is only
+// changed through TransitionStatus so a concurrent schedule run is not undone.
 func (r *FlashSaleRepo) Update(ctx context.Context, flashSale *domain.FlashSale) error {
 	model := ToFlashSaleModel(flashSale)
-	return r.db.WithContext(ctx).Save(model).Error
+	return r.db.WithContext(ctx).Omit("Status").Save(model).Error
+}
+
+// ListDueToStart retrieves scheduled, active flash sales whose window is open.
+func (r *FlashSaleRepo) ListDueToStart(ctx context.Context, now time.Time) ([]*domain.FlashSale, error) {
+	var models []FlashSaleModel
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("status = ? AND is_active = ? AND starts_at <= ? AND ends_at > ?", domain.FlashSaleScheduled, true, now, now).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var flashSales []*domain.FlashSale
+	for i := range models {
+		flashSales = append(flashSales, models[i].ToDomain())
+	}
+	return flashSales, nil
+}
+
+// ListDueToEnd retrieves live flash sales that have expired or been deactivated.
+func (r *FlashSaleRepo) ListDueToEnd(ctx context.Context, now time.Time) ([]*domain.FlashSale, error) {
+	var models []FlashSaleModel
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("status = ? AND (ends_at <= ? OR is_active = ?)", domain.FlashSaleLive, now, false).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var flashSales []*domain.FlashSale
+	for i := range models {
+		flashSales = append(flashSales, models[i].ToDomain())
+	}
+	return flashSales, nil
+}
+
+// TransitionStatus moves a flash sale from one status to another. It reports
+// false when the sale was no longer in the expected status.
+func (r *FlashSaleRepo) TransitionStatus(ctx context.Context, id string, from, to domain.FlashSaleStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&FlashSaleModel{}).
+		Where("id = ? AND status = ?", id, from).
+		Update("status", to)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
 }
@@ -1,12 +1,135 @@
 package postgres
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/lib/pq"
 	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
 )
 
+// LineDiscountsJSON is a GORM-compatible JSONB type for coupon discount allocations.
+type LineDiscountsJSON []domain.LineDiscount
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (l LineDiscountsJSON) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]domain.LineDiscount{})
+	}
+	return json.Marshal([]domain.LineDiscount(l))
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval.
+func (l *LineDiscountsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan LineDiscountsJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// SellerTotalsJSON is a GORM-compatible JSONB type for per-seller order totals.
+type SellerTotalsJSON map[string]int64
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (s SellerTotalsJSON) Value() (driver.Value, error) {
+	if s == nil {
+		return json.Marshal(map[string]int64{})
+	}
+	return json.Marshal(map[string]int64(s))
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval.
+func (s *SellerTotalsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*s = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan SellerTotalsJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// PromotionConditionsJSON is a GORM-compatible JSONB type for automatic promotion conditions.
+type PromotionConditionsJSON []domain.PromotionCondition
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (p PromotionConditionsJSON) Value() (driver.Value, error) {
+	if p == nil {
+		return json.Marshal([]domain.PromotionCondition{})
+	}
+	return json.Marshal([]domain.PromotionCondition(p))
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval.
+func (p *PromotionConditionsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan PromotionConditionsJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// PromotionActionsJSON is a GORM-compatible JSONB type for automatic promotion actions.
+type PromotionActionsJSON []domain.PromotionAction
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (p PromotionActionsJSON) Value() (driver.Value, error) {
+	if p == nil {
+		return json.Marshal([]domain.PromotionAction{})
+	}
+	return json.Marshal([]domain.PromotionAction(p))
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval.
+func (p *PromotionActionsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan PromotionActionsJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// BundleItemsJSON is a GORM-compatible JSONB type for bundle components.
+type BundleItemsJSON []domain.BundleItem
+
+// Value implements the driver.Valuer interface for JSONB storage.
+func (b BundleItemsJSON) Value() (driver.Value, error) {
+	if b == nil {
+		return json.Marshal([]domain.BundleItem{})
+	}
+	return json.Marshal([]domain.BundleItem(b))
+}
+
+// Scan implements the sql.Scanner interface for JSONB retrieval.
+func (b *BundleItemsJSON) Scan(value interface{}) error {
+	if value == nil {
+		*b = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("failed to scan BundleItemsJSON: not a byte slice")
+	}
+	return json.Unmarshal(bytes, b)
+}
+
 // CouponModel is the GORM model for the coupons table.
 type CouponModel struct {
 	ID               string         `gorm:"type:uuid;primaryKey"`
@@ -20,6 +143,9 @@ type CouponModel struct {
 	PerUserLimit     int            `gorm:"not null;default:0"`
 	Scope            string         `gorm:"type:varchar(20);not null;default:'all'"`
 	ScopeIDs         pq.StringArray `gorm:"type:text[]"`
+	CampaignID       string         `gorm:"type:varchar(100);index"`
+	Stacking         string         `gorm:"type:varchar(30);not null;default:'exclusive'"`
+	Priority         int            `gorm:"not null;default:0"`
 	CreatedBy        string         `gorm:"type:varchar(255);not null"`
 	StartsAt         time.Time      `gorm:"not null"`
 	ExpiresAt        time.Time      `gorm:"not null"`
@@ -46,6 +172,9 @@ func (m *CouponModel) ToDomain() *domain.Coupon {
 		PerUserLimit:     m.PerUserLimit,
 		Scope:            domain.CouponScope(m.Scope),
 		ScopeIDs:         []string(m.ScopeIDs),
+		CampaignID:       m.CampaignID,
+		Stacking:         domain.CouponStacking(m.Stacking),
+		Priority:         m.Priority,
 		CreatedBy:        m.CreatedBy,
 		StartsAt:         m.StartsAt,
 		ExpiresAt:        m.ExpiresAt,
@@ -68,6 +197,9 @@ func ToCouponModel(c *domain.Coupon) *CouponModel {
 		PerUserLimit:     c.PerUserLimit,
 		Scope:            string(c.Scope),
 		ScopeIDs:         pq.StringArray(c.ScopeIDs),
+		CampaignID:       c.CampaignID,
+		Stacking:         string(c.Stacking),
+		Priority:         c.Priority,
 		CreatedBy:        c.CreatedBy,
 		StartsAt:         c.StartsAt,
 		ExpiresAt:        c.ExpiresAt,
@@ -78,12 +210,15 @@ func ToCouponModel(c *domain.Coupon) *CouponModel {
 
 // CouponUsageModel is the GORM model for the coupon_usages table.
 type CouponUsageModel struct {
-	ID            string    `gorm:"type:uuid;primaryKey"`
-	CouponID      string    `gorm:"type:uuid;index;not null"`
-	UserID        string    `gorm:"type:uuid;index;not null"`
-	OrderID       string    `gorm:"type:uuid;not null"`
-	DiscountCents int64     `gorm:"not null;default:0"`
-	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	ID            string            `gorm:"type:uuid;primaryKey"`
+	CouponID      string            `gorm:"type:uuid;index;uniqueIndex:idx_coupon_usages_coupon_order;not null"`
+	UserID        string            `gorm:"type:uuid;index;not null"`
+	OrderID       string            `gorm:"type:uuid;index;uniqueIndex:idx_coupon_usages_coupon_order;not null"`
+	DiscountCents int64             `gorm:"not null;default:0"`
+	Allocations   LineDiscountsJSON `gorm:"type:jsonb"`
+	Status        string            `gorm:"type:varchar(20);index;not null;default:'confirmed'"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time         `gorm:"autoUpdateTime"`
 }
 
 // TableName returns the table name for CouponUsageModel.
@@ -99,7 +234,10 @@ func (m *CouponUsageModel) ToDomain() *domain.CouponUsage {
 		UserID:        m.UserID,
 		OrderID:       m.OrderID,
 		DiscountCents: m.DiscountCents,
+		Allocations:   []domain.LineDiscount(m.Allocations),
+		Status:        domain.CouponUsageStatus(m.Status),
 		CreatedAt:     m.CreatedAt,
+		UpdatedAt:     m.UpdatedAt,
 	}
 }
 
@@ -111,19 +249,23 @@ func ToCouponUsageModel(u *domain.CouponUsage) *CouponUsageModel {
 		UserID:        u.UserID,
 		OrderID:       u.OrderID,
 		DiscountCents: u.DiscountCents,
+		Allocations:   LineDiscountsJSON(u.Allocations),
+		Status:        string(u.Status),
 		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
 	}
 }
 
 // FlashSaleModel is the GORM model for the flash_sales table.
 type FlashSaleModel struct {
-	ID        string              `gorm:"type:uuid;primaryKey"`
-	Name      string              `gorm:"type:varchar(255);not null"`
-	StartsAt  time.Time           `gorm:"not null"`
-	EndsAt    time.Time           `gorm:"not null"`
-	IsActive  bool                `gorm:"not null;default:true"`
+	ID        string               `gorm:"type:uuid;primaryKey"`
+	Name      string               `gorm:"type:varchar(255);not null"`
+	StartsAt  time.Time            `gorm:"not null"`
+	EndsAt    time.Time            `gorm:"not null"`
+	IsActive  bool                 `gorm:"not null;default:true"`
+	Status    string               `gorm:"type:varchar(20);not null;default:'scheduled';index"`
 	Items     []FlashSaleItemModel `gorm:"foreignKey:FlashSaleID;constraint:OnDelete:CASCADE"`
-	CreatedAt time.Time           `gorm:"autoCreateTime"`
+	CreatedAt time.Time            `gorm:"autoCreateTime"`
 }
 
 // TableName returns the table name for FlashSaleModel.
@@ -139,6 +281,7 @@ func (m *FlashSaleModel) ToDomain() *domain.FlashSale {
 		StartsAt:  m.StartsAt,
 		EndsAt:    m.EndsAt,
 		IsActive:  m.IsActive,
+		Status:    domain.FlashSaleStatus(m.Status),
 		CreatedAt: m.CreatedAt,
 	}
 	for _, item := range m.Items {
@@ -155,6 +298,7 @@ func ToFlashSaleModel(fs *domain.FlashSale) *FlashSaleModel {
 		StartsAt:  fs.StartsAt,
 		EndsAt:    fs.EndsAt,
 		IsActive:  fs.IsActive,
+		Status:    string(fs.Status),
 		CreatedAt: fs.CreatedAt,
 	}
 	for _, item := range fs.Items {
@@ -171,6 +315,7 @@ type FlashSaleItemModel struct {
 	VariantID      string `gorm:"type:uuid"`
 	SalePriceCents int64  `gorm:"not null;default:0"`
 	QuantityLimit  int    `gorm:"not null;default:0"`
+	PerBuyerLimit  int    `gorm:"not null;default:0"`
 	SoldCount      int    `gorm:"not null;default:0"`
 }
 
@@ -188,6 +333,7 @@ func (m *FlashSaleItemModel) ToDomain() *domain.FlashSaleItem {
 		VariantID:      m.VariantID,
 		SalePriceCents: m.SalePriceCents,
 		QuantityLimit:  m.QuantityLimit,
+		PerBuyerLimit:  m.PerBuyerLimit,
 		SoldCount:      m.SoldCount,
 	}
 }
@@ -201,20 +347,67 @@ func ToFlashSaleItemModel(item *domain.FlashSaleItem) *FlashSaleItemModel {
 		VariantID:      item.VariantID,
 		SalePriceCents: item.SalePriceCents,
 		QuantityLimit:  item.QuantityLimit,
+		PerBuyerLimit:  item.PerBuyerLimit,
 		SoldCount:      item.SoldCount,
 	}
 }
 
+// FlashSaleClaimModel is the GORM model for the flash_sale_claims table.
+type FlashSaleClaimModel struct {
+	ID              string    `gorm:"type:uuid;primaryKey"`
+	FlashSaleID     string    `gorm:"type:uuid;index;not null"`
+	FlashSaleItemID string    `gorm:"type:uuid;index:idx_flash_sale_claims_item_status;uniqueIndex:idx_flash_sale_claims_order_item,where:status = 'claimed';not null"`
+	BuyerID         string    `gorm:"type:uuid;not null"`
+	OrderID         string    `gorm:"type:uuid;index;uniqueIndex:idx_flash_sale_claims_order_item,where:status = 'claimed';not null"`
+	Quantity        int       `gorm:"not null"`
+	Status          string    `gorm:"type:varchar(20);not null;default:'claimed';index:idx_flash_sale_claims_item_status"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for FlashSaleClaimModel.
+func (FlashSaleClaimModel) TableName() string {
+	return "flash_sale_claims"
+}
+
+// ToDomain converts a FlashSaleClaimModel to a domain FlashSaleClaim.
+func (m *FlashSaleClaimModel) ToDomain() *domain.FlashSaleClaim {
+	return &domain.FlashSaleClaim{
+		ID:              m.ID,
+		FlashSaleID:     m.FlashSaleID,
+		FlashSaleItemID: m.FlashSaleItemID,
+		BuyerID:         m.BuyerID,
+		OrderID:         m.OrderID,
+		Quantity:        m.Quantity,
+		Status:          domain.FlashSaleClaimStatus(m.Status),
+		CreatedAt:       m.CreatedAt,
+	}
+}
+
+// ToFlashSaleClaimModel converts a domain FlashSaleClaim to a FlashSaleClaimModel.
+func ToFlashSaleClaimModel(claim *domain.FlashSaleClaim) *FlashSaleClaimModel {
+	return &FlashSaleClaimModel{
+		ID:              claim.ID,
+		FlashSaleID:     claim.FlashSaleID,
+		FlashSaleItemID: claim.FlashSaleItemID,
+		BuyerID:         claim.BuyerID,
+		OrderID:         claim.OrderID,
+		Quantity:        claim.Quantity,
+		Status:          string(claim.Status),
+		CreatedAt:       claim.CreatedAt,
+	}
+}
+
 // BundleModel is the GORM model for the bundles table.
 type BundleModel struct {
-	ID               string         `gorm:"type:uuid;primaryKey"`
-	Name             string         `gorm:"type:varchar(255);not null"`
-	SellerID         string         `gorm:"type:uuid;index;not null"`
-	ProductIDs       pq.StringArray `gorm:"type:text[]"`
-	BundlePriceCents int64          `gorm:"not null;default:0"`
-	SavingsCents     int64          `gorm:"not null;default:0"`
-	IsActive         bool           `gorm:"not null;default:true"`
-	CreatedAt        time.Time      `gorm:"autoCreateTime"`
+	ID               string          `gorm:"type:uuid;primaryKey"`
+	Name             string          `gorm:"type:varchar(255);not null"`
+	SellerID         string          `gorm:"type:uuid;index;not null"`
+	ProductIDs       pq.StringArray  `gorm:"type:text[]"`
+	Items            BundleItemsJSON `gorm:"type:jsonb"`
+	BundlePriceCents int64           `gorm:"not null;default:0"`
+	SavingsCents     int64           `gorm:"not null;default:0"`
+	IsActive         bool            `gorm:"not null;default:true"`
+	CreatedAt        time.Time       `gorm:"autoCreateTime"`
 }
 
 // TableName returns the table name for BundleModel.
@@ -222,13 +415,21 @@ func (BundleModel) TableName() string {
 	return "bundles"
 }
 
-// ToDomain converts a BundleModel to a domain Bundle.
+// ToDomain converts a BundleModel to a domain Bundle. Bundles stored before
+// components were introduced get one unit of each product.
 func (m *BundleModel) ToDomain() *domain.Bundle {
+	items := []domain.BundleItem(m.Items)
+	if len(items) == 0 {
+		for _, productID := range m.ProductIDs {
+			items = append(items, domain.BundleItem{ProductID: productID, Quantity: 1})
+		}
+	}
 	return &domain.Bundle{
 		ID:               m.ID,
 		Name:             m.Name,
 		SellerID:         m.SellerID,
 		ProductIDs:       []string(m.ProductIDs),
+		Items:            items,
 		BundlePriceCents: m.BundlePriceCents,
 		SavingsCents:     m.SavingsCents,
 		IsActive:         m.IsActive,
@@ -243,9 +444,195 @@ func ToBundleModel(b *domain.Bundle) *BundleModel {
 		Name:             b.Name,
 		SellerID:         b.SellerID,
 		ProductIDs:       pq.StringArray(b.ProductIDs),
+		Items:            BundleItemsJSON(b.Items),
 		BundlePriceCents: b.BundlePriceCents,
 		SavingsCents:     b.SavingsCents,
 		IsActive:         b.IsActive,
 		CreatedAt:        b.CreatedAt,
 	}
 }
+
+// AutomaticPromotionModel is the GORM model for the automatic_promotions table.
+type AutomaticPromotionModel struct {
+	ID          string                  `gorm:"type:uuid;primaryKey"`
+	Name        string                  `gorm:"type:varchar(255);not null"`
+	Description string                  `gorm:"type:text"`
+	CreatedBy   string                  `gorm:"type:varchar(255);index;not null"`
+	Conditions  PromotionConditionsJSON `gorm:"type:jsonb;not null"`
+	Actions     PromotionActionsJSON    `gorm:"type:jsonb;not null"`
+	Priority    int                     `gorm:"not null;default:0"`
+	Exclusive   bool                    `gorm:"not null;default:false"`
+	StartsAt    time.Time               `gorm:"not null;index:idx_automatic_promotions_window"`
+	EndsAt      time.Time               `gorm:"not null;index:idx_automatic_promotions_window"`
+	IsActive    bool                    `gorm:"not null;default:true"`
+	CreatedAt   time.Time               `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time               `gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for AutomaticPromotionModel.
+func (AutomaticPromotionModel) TableName() string {
+	return "automatic_promotions"
+}
+
+// ToDomain converts an AutomaticPromotionModel to a domain AutomaticPromotion.
+func (m *AutomaticPromotionModel) ToDomain() *domain.AutomaticPromotion {
+	return &domain.AutomaticPromotion{
+		ID:          m.ID,
+		Name:        m.Name,
+		Description: m.Description,
+		CreatedBy:   m.CreatedBy,
+		Conditions:  []domain.PromotionCondition(m.Conditions),
+		Actions:     []domain.PromotionAction(m.Actions),
+		Priority:    m.Priority,
+		Exclusive:   m.Exclusive,
+		StartsAt:    m.StartsAt,
+		EndsAt:      m.EndsAt,
+		IsActive:    m.IsActive,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// ToAutomaticPromotionModel converts a domain AutomaticPromotion to an AutomaticPromotionModel.
+func ToAutomaticPromotionModel(p *domain.AutomaticPromotion) *AutomaticPromotionModel {
+	return &AutomaticPromotionModel{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		CreatedBy:   p.CreatedBy,
+		Conditions:  PromotionConditionsJSON(p.Conditions),
+		Actions:     PromotionActionsJSON(p.Actions),
+		Priority:    p.Priority,
+		Exclusive:   p.Exclusive,
+		StartsAt:    p.StartsAt,
+		EndsAt:      p.EndsAt,
+		IsActive:    p.IsActive,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+// ProductSellerModel is the GORM model for the product_sellers table.
+type ProductSellerModel struct {
+	ProductID string    `gorm:"type:uuid;primaryKey"`
+	SellerID  string    `gorm:"type:uuid;index;not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for ProductSellerModel.
+func (ProductSellerModel) TableName() string {
+	return "product_sellers"
+}
+
+// OrderFactModel is the GORM model for the promotion_order_facts table.
+type OrderFactModel struct {
+	OrderID               string           `gorm:"type:uuid;primaryKey"`
+	BuyerID               string           `gorm:"type:uuid;index"`
+	Currency              string           `gorm:"type:varchar(3)"`
+	TotalCents            int64            `gorm:"not null;default:0"`
+	PresentmentCurrency   string           `gorm:"type:varchar(3)"`
+	PresentmentTotalCents int64            `gorm:"not null;default:0"`
+	SellerTotals          SellerTotalsJSON `gorm:"type:jsonb"`
+	Cancelled             bool             `gorm:"not null;default:false"`
+	RefundedCents         int64            `gorm:"not null;default:0"`
+	OrderedAt             time.Time        `gorm:"index;not null"`
+	UpdatedAt             time.Time        `gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for OrderFactModel.
+func (OrderFactModel) TableName() string {
+	return "promotion_order_facts"
+}
+
+// ToDomain converts an OrderFactModel to a domain OrderFact.
+func (m *OrderFactModel) ToDomain() *domain.OrderFact {
+	return &domain.OrderFact{
+		OrderID:               m.OrderID,
+		BuyerID:               m.BuyerID,
+		Currency:              m.Currency,
+		TotalCents:            m.TotalCents,
+		PresentmentCurrency:   m.PresentmentCurrency,
+		PresentmentTotalCents: m.PresentmentTotalCents,
+		SellerTotals:          map[string]int64(m.SellerTotals),
+		Cancelled:             m.Cancelled,
+		RefundedCents:         m.RefundedCents,
+		OrderedAt:             m.OrderedAt,
+		UpdatedAt:             m.UpdatedAt,
+	}
+}
+
+// ToOrderFactModel converts a domain OrderFact to an OrderFactModel.
+func ToOrderFactModel(fact *domain.OrderFact) *OrderFactModel {
+	return &OrderFactModel{
+		OrderID:               fact.OrderID,
+		BuyerID:               fact.BuyerID,
+		Currency:              fact.Currency,
+		TotalCents:            fact.TotalCents,
+		PresentmentCurrency:   fact.PresentmentCurrency,
+		PresentmentTotalCents: fact.PresentmentTotalCents,
+		SellerTotals:          SellerTotalsJSON(fact.SellerTotals),
+		Cancelled:             fact.Cancelled,
+		RefundedCents:         fact.RefundedCents,
+		OrderedAt:             fact.OrderedAt,
+		UpdatedAt:             fact.UpdatedAt,
+	}
+}
+
+// RefundFactModel is the GORM model for the promotion_refund_facts table,
+// which records the refunds already added to order facts.
+type RefundFactModel struct {
+	RefundID    string    `gorm:"type:varchar(255);primaryKey"`
+	OrderID     string    `gorm:"type:uuid;index;not null"`
+	AmountCents int64     `gorm:"not null"`
+	RefundedAt  time.Time `gorm:"not null"`
+}
+
+// TableName returns the table name for RefundFactModel.
+func (RefundFactModel) TableName() string {
+	return "promotion_refund_facts"
+}
+
+// PromotionAttributionModel is the GORM model for the promotion_attributions table.
+type PromotionAttributionModel struct {
+	ID            string    `gorm:"type:uuid;primaryKey"`
+	OrderID       string    `gorm:"type:uuid;not null;uniqueIndex:idx_promotion_attributions_order_promotion"`
+	Kind          string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_promotion_attributions_order_promotion"`
+	PromotionID   string    `gorm:"type:uuid;not null;index;uniqueIndex:idx_promotion_attributions_order_promotion"`
+	PromotionName string    `gorm:"type:varchar(255)"`
+	SellerID      string    `gorm:"type:varchar(255);index;not null"`
+	DiscountCents int64     `gorm:"not null;default:0"`
+	AttributedAt  time.Time `gorm:"not null"`
+}
+
+// TableName returns the table name for PromotionAttributionModel.
+func (PromotionAttributionModel) TableName() string {
+	return "promotion_attributions"
+}
+
+// ToDomain converts a PromotionAttributionModel to a domain PromotionAttribution.
+func (m *PromotionAttributionModel) ToDomain() *domain.PromotionAttribution {
+	return &domain.PromotionAttribution{
+		ID:            m.ID,
+		OrderID:       m.OrderID,
+		Kind:          domain.PromotionKind(m.Kind),
+		PromotionID:   m.PromotionID,
+		PromotionName: m.PromotionName,
+		SellerID:      m.SellerID,
+		DiscountCents: m.DiscountCents,
+		AttributedAt:  m.AttributedAt,
+	}
+}
+
+// ToPromotionAttributionModel converts a domain PromotionAttribution to a PromotionAttributionModel.
+func ToPromotionAttributionModel(a *domain.PromotionAttribution) *PromotionAttributionModel {
+	return &PromotionAttributionModel{
+		ID:            a.ID,
+		OrderID:       a.OrderID,
+		Kind:          string(a.Kind),
+		PromotionID:   a.PromotionID,
+		PromotionName: a.PromotionName,
+		SellerID:      a.SellerID,
+		DiscountCents: a.DiscountCents,
+		AttributedAt:  a.AttributedAt,
+	}
+}
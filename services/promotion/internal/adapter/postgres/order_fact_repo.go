@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderFactRepo implements domain.OrderFactRepository using GORM/Postgres.
+type OrderFactRepo struct {
+	db *gorm.DB
+}
+
+// NewOrderFactRepo creates a new OrderFactRepo.
+func NewOrderFactRepo(db *gorm.DB) *OrderFactRepo {
+	return &OrderFactRepo{db: db}
+}
+
+// GetByID retrieves an order fact by order ID.
+func (r *OrderFactRepo) GetByID(ctx context.Context, orderID string) (*domain.OrderFact, error) {
+	var model OrderFactModel
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&model).Error; err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+// Upsert records an order. Replaying order.created refreshes the order's
+// totals but leaves when it was placed, its cancellation and its refunds
+// untouched.
+func (r *OrderFactRepo) Upsert(ctx context.Context, fact *domain.OrderFact) error {
+	model := ToOrderFactModel(fact)
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "order_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"buyer_id", "currency", "total_cents", "presentment_currency", "presentment_total_cents", "seller_totals", "updated_at"}),
+		}).
+		Create(model).Error
+}
+
+// MarkCancelled flags an order as cancelled. It reports false when the order
+// is unknown or was already cancelled.
+func (r *OrderFactRepo) MarkCancelled(ctx context.Context, orderID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&OrderFactModel{}).
+		Where("order_id = ? AND cancelled = ?", orderID, false).
+		Update("cancelled", true)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// AddRefund adds a refund of amountCents, converted from the currency the
+// buyer paid in at the order's own totals, to the refunded amount of an
+// order, never exceeding the order total. It reports false when the order
+// is unknown or the refund was already added.
+func (r *OrderFactRepo) AddRefund(ctx context.Context, refundID, orderID string, amountCents int64) (bool, error) {
+	added := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		refund := &RefundFactModel{
+			RefundID:    refundID,
+			OrderID:     orderID,
+			AmountCents: amountCents,
+			RefundedAt:  time.Now(),
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(refund)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		result = tx.Model(&OrderFactModel{}).
+			Where("order_id = ?", orderID).
+			Update("refunded_cents", gorm.Expr(`LEAST(total_cents, refunded_cents + CASE
+				WHEN presentment_total_cents > 0 THEN ROUND(?::numeric * total_cents / presentment_total_cents)::bigint
+				ELSE ? END)`, amountCents, amountCents))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Forget the refund of an unknown order so it can be added
+			// once the order is recorded
+			return tx.Delete(refund).Error
+		}
+		added = true
+		return nil
+	})
+	return added, err
+}
+
+// AverageUnpromotedValue returns the average share sold by sellerID of the
+// uncancelled orders placed in [from, to) that no promotion of sellerID
+// discounted. Platform averages use whole orders without any promotion.
+func (r *OrderFactRepo) AverageUnpromotedValue(ctx context.Context, sellerID string, from, to time.Time) (int64, error) {
+	query := `
+SELECT COALESCE(DIV(SUM(share), COUNT(*)), 0)::bigint
+FROM (
+	SELECT ` + sellerShareSQL("@seller") + ` AS share
+	FROM promotion_order_facts f
+	WHERE f.ordered_at >= @from AND f.ordered_at < @to AND NOT f.cancelled
+		AND NOT EXISTS (
+			SELECT 1 FROM promotion_attributions a
+			WHERE a.order_id = f.order_id AND (@seller IN ('', @platform) OR a.seller_id = @seller)
+		)
+) orders
+WHERE share > 0`
+
+	var value int64
+	err := r.db.WithContext(ctx).Raw(query, map[string]interface{}{
+		"from":     from,
+		"to":       to,
+		"seller":   sellerID,
+		"platform": domain.PlatformCreator,
+	}).Scan(&value).Error
+	return value, err
+}
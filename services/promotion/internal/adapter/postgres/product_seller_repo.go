@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProductSellerRepo implements domain.ProductSellerRepository using GORM/Postgres.
+type ProductSellerRepo struct {
+	db *gorm.DB
+}
+
+// NewProductSellerRepo creates a new ProductSellerRepo.
+func NewProductSellerRepo(db *gorm.DB) *ProductSellerRepo {
+	return &ProductSellerRepo{db: db}
+}
+
+// Upsert records or replaces the seller of a product.
+func (r *ProductSellerRepo) Upsert(ctx context.Context, productSeller *domain.ProductSeller) error {
+	model := &ProductSellerModel{
+		ProductID: productSeller.ProductID,
+		SellerID:  productSeller.SellerID,
+		UpdatedAt: productSeller.UpdatedAt,
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"seller_id", "updated_at"}),
+		}).
+		Create(model).Error
+}
+
+// Delete forgets a product.
+func (r *ProductSellerRepo) Delete(ctx context.Context, productID string) error {
+	return r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Delete(&ProductSellerModel{}).Error
+}
+
+// GetSellers returns the seller of each known product, keyed by product ID.
+func (r *ProductSellerRepo) GetSellers(ctx context.Context, productIDs []string) (map[string]string, error) {
+	var models []ProductSellerModel
+	err := r.db.WithContext(ctx).
+		Where("product_id IN ?", productIDs).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sellers := make(map[string]string, len(models))
+	for _, m := range models {
+		sellers[m.ProductID] = m.SellerID
+	}
+	return sellers, nil
+}
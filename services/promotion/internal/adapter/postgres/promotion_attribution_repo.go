@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PromotionAttributionRepo implements domain.PromotionAttributionRepository using GORM/Postgres.
+type PromotionAttributionRepo struct {
+	db *gorm.DB
+}
+
+// NewPromotionAttributionRepo creates a new PromotionAttributionRepo.
+func NewPromotionAttributionRepo(db *gorm.DB) *PromotionAttributionRepo {
+	return &PromotionAttributionRepo{db: db}
+}
+
+// Create persists an attribution, ignoring duplicates of the same promotion
+// on the same order so that redelivered events are harmless.
+func (r *PromotionAttributionRepo) Create(ctx context.Context, attribution *domain.PromotionAttribution) error {
+	model := ToPromotionAttributionModel(attribution)
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "order_id"}, {Name: "kind"}, {Name: "promotion_id"}},
+			DoNothing: true,
+		}).
+		Create(model).Error
+}
+
+// promotionTotalsRow is a row of the promotion analytics aggregates.
+type promotionTotalsRow struct {
+	Kind                   string
+	PromotionID            string
+	PromotionName          string
+	SellerID               string
+	Promotions             int
+	Redemptions            int
+	CancelledRedemptions   int
+	DiscountCostCents      int64
+	AttributedRevenueCents int64
+	RefundedCents          int64
+}
+
+// sellerShareSQL is the part of order f sold by the seller in column
+// seller, or the whole order for platform promotions.
+func sellerShareSQL(seller string) string {
+	return "CASE WHEN " + seller + " IN ('', @platform) THEN f.total_cents" +
+		" ELSE COALESCE((f.seller_totals ->> " + seller + ")::bigint, 0) END"
+}
+
+// matchingAttributionsSQL selects the attributions a of the orders f
+// matching an analytics filter.
+const matchingAttributionsSQL = `
+	FROM promotion_attributions a
+	JOIN promotion_order_facts f ON f.order_id = a.order_id
+	WHERE f.ordered_at >= @from AND f.ordered_at < @to
+		AND (@seller = '' OR a.seller_id = @seller)
+		AND (@kind = '' OR a.kind = @kind)`
+
+// promotionTotalsSQL sums up rows of discount_cents, cancelled,
+// total_cents, refunded_cents and share, spreading refunds evenly across
+// each order.
+const promotionTotalsSQL = `
+	COUNT(*) FILTER (WHERE NOT cancelled) AS redemptions,
+	COUNT(*) FILTER (WHERE cancelled) AS cancelled_redemptions,
+	COALESCE(SUM(discount_cents) FILTER (WHERE NOT cancelled), 0)::bigint AS discount_cost_cents,
+	COALESCE(SUM(share) FILTER (WHERE NOT cancelled), 0)::bigint AS attributed_revenue_cents,
+	COALESCE(SUM(CASE
+		WHEN total_cents <= 0 OR refunded_cents <= 0 THEN 0
+		WHEN share >= total_cents THEN refunded_cents
+		ELSE refunded_cents * share / total_cents
+	END) FILTER (WHERE NOT cancelled), 0)::bigint AS refunded_cents`
+
+// SumByPromotion sums up the orders matching filter per promotion, highest
+// attributed revenue first.
+func (r *PromotionAttributionRepo) SumByPromotion(ctx context.Context, filter domain.AnalyticsFilter) ([]*domain.PromotionTotals, error) {
+	query := `
+WITH attributed AS (
+	SELECT a.kind, a.promotion_id, a.promotion_name, a.seller_id, a.discount_cents,
+		f.cancelled, f.total_cents, f.refunded_cents, ` + sellerShareSQL("a.seller_id") + ` AS share` +
+		matchingAttributionsSQL + `
+)
+SELECT kind, promotion_id, MAX(promotion_name) AS promotion_name, seller_id,` + promotionTotalsSQL + `
+FROM attributed
+GROUP BY kind, promotion_id, seller_id
+ORDER BY attributed_revenue_cents DESC, kind, promotion_id`
+	return r.sum(ctx, query, filter)
+}
+
+// SumBySeller sums up the orders matching filter per seller, counting an
+// order discounted by several of a seller's promotions once, highest
+// attributed revenue first.
+func (r *PromotionAttributionRepo) SumBySeller(ctx context.Context, filter domain.AnalyticsFilter) ([]*domain.PromotionTotals, error) {
+	query := `
+WITH matching AS (
+	SELECT a.*` + matchingAttributionsSQL + `
+), promotions AS (
+	SELECT seller_id, COUNT(DISTINCT kind || ':' || promotion_id::text) AS promotions
+	FROM matching
+	GROUP BY seller_id
+), attributed AS (
+	SELECT a.seller_id, SUM(a.discount_cents) AS discount_cents,
+		f.cancelled, f.total_cents, f.refunded_cents, ` + sellerShareSQL("a.seller_id") + ` AS share
+	FROM matching a
+	JOIN promotion_order_facts f ON f.order_id = a.order_id
+	GROUP BY a.seller_id, f.order_id
+)
+SELECT a.seller_id, p.promotions,` + promotionTotalsSQL + `
+FROM attributed a
+JOIN promotions p ON p.seller_id = a.seller_id
+GROUP BY a.seller_id, p.promotions
+ORDER BY attributed_revenue_cents DESC, seller_id`
+	return r.sum(ctx, query, filter)
+}
+
+// sum runs an analytics aggregate query for filter.
+func (r *PromotionAttributionRepo) sum(ctx context.Context, query string, filter domain.AnalyticsFilter) ([]*domain.PromotionTotals, error) {
+	var rows []promotionTotalsRow
+	err := r.db.WithContext(ctx).Raw(query, map[string]interface{}{
+		"from":     filter.From,
+		"to":       filter.To,
+		"seller":   filter.SellerID,
+		"kind":     string(filter.Kind),
+		"platform": domain.PlatformCreator,
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make([]*domain.PromotionTotals, 0, len(rows))
+	for _, row := range rows {
+		totals = append(totals, &domain.PromotionTotals{
+			Kind:                   domain.PromotionKind(row.Kind),
+			PromotionID:            row.PromotionID,
+			PromotionName:          row.PromotionName,
+			SellerID:               row.SellerID,
+			Promotions:             row.Promotions,
+			Redemptions:            row.Redemptions,
+			CancelledRedemptions:   row.CancelledRedemptions,
+			DiscountCostCents:      row.DiscountCostCents,
+			AttributedRevenueCents: row.AttributedRevenueCents,
+			RefundedCents:          row.RefundedCents,
+		})
+	}
+	return totals, nil
+}
@@ -0,0 +1,210 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+)
+
+const (
+	flashSaleKeyPrefix = "flash_sale:item:"
+	// flashSaleCounterGrace keeps counters around after a sale ends so late
+	// releases still find them.
+	flashSaleCounterGrace = 7 * 24 * time.Hour
+)
+
+// Claim script results below zero signal why the claim was refused.
+const (
+	claimSoldOut    = -1
+	claimBuyerLimit = -2
+	claimNotSeeded  = -3
+	claimHeld       = -4
+)
+
+// seedScript initialises the sold counter, per-buyer hash and per-order
+// hash of an item unless the sold counter already exists.
+// KEYS: sold, buyers, orders. ARGV: expire-at (unix ms), then
+// buyer/order/quantity triples.
+var seedScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+local sold = 0
+for i = 2, #ARGV, 3 do
+	redis.call('HINCRBY', KEYS[2], ARGV[i], ARGV[i + 2])
+	redis.call('HINCRBY', KEYS[3], ARGV[i + 1], ARGV[i + 2])
+	sold = sold + tonumber(ARGV[i + 2])
+end
+redis.call('SET', KEYS[1], sold)
+redis.call('PEXPIREAT', KEYS[1], ARGV[1])
+if #ARGV > 1 then
+	redis.call('PEXPIREAT', KEYS[2], ARGV[1])
+	redis.call('PEXPIREAT', KEYS[3], ARGV[1])
+end
+return 1
+`)
+
+// claimScript adds quantity to the sold counter and the buyer's and order's
+// entries when both the item and per-buyer limits allow it. An order holds
+// at most one claim per item, so a repeated claim is refused as held.
+// KEYS: sold, buyers, orders. ARGV: buyer, order, quantity, quantity
+// limit, per-buyer limit.
+var claimScript = redis.NewScript(`
+local sold = redis.call('GET', KEYS[1])
+if not sold then
+	return -3
+end
+if redis.call('HEXISTS', KEYS[3], ARGV[2]) == 1 then
+	return -4
+end
+local qty = tonumber(ARGV[3])
+local limit = tonumber(ARGV[4])
+if limit > 0 and tonumber(sold) + qty > limit then
+	return -1
+end
+local perBuyer = tonumber(ARGV[5])
+if perBuyer > 0 then
+	local bought = tonumber(redis.call('HGET', KEYS[2], ARGV[1]) or '0')
+	if bought + qty > perBuyer then
+		return -2
+	end
+end
+redis.call('HINCRBY', KEYS[2], ARGV[1], qty)
+redis.call('HSET', KEYS[3], ARGV[2], qty)
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > 0 then
+	redis.call('PEXPIRE', KEYS[2], ttl)
+	redis.call('PEXPIRE', KEYS[3], ttl)
+end
+return redis.call('INCRBY', KEYS[1], qty)
+`)
+
+// releaseScript returns quantity to the item and the buyer, never going
+// below zero, and forgets the order's claim.
+// KEYS: sold, buyers, orders. ARGV: buyer, order, quantity.
+var releaseScript = redis.NewScript(`
+local qty = tonumber(ARGV[3])
+local sold = tonumber(redis.call('GET', KEYS[1]) or '0')
+if sold > 0 then
+	redis.call('DECRBY', KEYS[1], math.min(sold, qty))
+end
+local bought = tonumber(redis.call('HGET', KEYS[2], ARGV[1]) or '0')
+if bought > qty then
+	redis.call('HINCRBY', KEYS[2], ARGV[1], -qty)
+else
+	redis.call('HDEL', KEYS[2], ARGV[1])
+end
+redis.call('HDEL', KEYS[3], ARGV[2])
+return 1
+`)
+
+// FlashSaleStock implements domain.FlashSaleStock using Redis counters
+// updated by Lua scripts.
+type FlashSaleStock struct {
+	client *redis.Client
+}
+
+// NewFlashSaleStock creates a new Redis-backed FlashSaleStock.
+func NewFlashSaleStock(client *redis.Client) *FlashSaleStock {
+	return &FlashSaleStock{client: client}
+}
+
+func soldKey(itemID string) string {
+	return fmt.Sprintf("%s{%s}:sold", flashSaleKeyPrefix, itemID)
+}
+
+func buyersKey(itemID string) string {
+	return fmt.Sprintf("%s{%s}:buyers", flashSaleKeyPrefix, itemID)
+}
+
+func ordersKey(itemID string) string {
+	return fmt.Sprintf("%s{%s}:orders", flashSaleKeyPrefix, itemID)
+}
+
+func itemKeys(itemID string) []string {
+	return []string{soldKey(itemID), buyersKey(itemID), ordersKey(itemID)}
+}
+
+// Seed initialises an item's counters from its active claims unless they
+// already exist.
+func (s *FlashSaleStock) Seed(ctx context.Context, itemID string, claims []*domain.FlashSaleClaim, expiresAt time.Time) error {
+	args := []interface{}{expiresAt.Add(flashSaleCounterGrace).UnixMilli()}
+	for _, claim := range claims {
+		args = append(args, claim.BuyerID, claim.OrderID, claim.Quantity)
+	}
+	return seedScript.Run(ctx, s.client, itemKeys(itemID), args...).Err()
+}
+
+// Claim atomically claims quantity units of the item for the buyer's order.
+func (s *FlashSaleStock) Claim(ctx context.Context, item *domain.FlashSaleItem, buyerID, orderID string, quantity int) (int, error) {
+	result, err := claimScript.Run(ctx, s.client, itemKeys(item.ID),
+		buyerID, orderID, quantity, item.QuantityLimit, item.PerBuyerLimit,
+	).Int()
+	if err != nil {
+		return 0, err
+	}
+
+	switch result {
+	case claimSoldOut:
+		return 0, domain.ErrFlashSaleSoldOut
+	case claimBuyerLimit:
+		return 0, domain.ErrFlashSaleBuyerLimitReached
+	case claimNotSeeded:
+		return 0, domain.ErrFlashSaleStockNotSeeded
+	case claimHeld:
+		return 0, domain.ErrFlashSaleClaimHeld
+	}
+	return result, nil
+}
+
+// Release returns quantity units claimed by the buyer's order to the item.
+func (s *FlashSaleStock) Release(ctx context.Context, itemID, buyerID, orderID string, quantity int) error {
+	return releaseScript.Run(ctx, s.client, itemKeys(itemID), buyerID, orderID, quantity).Err()
+}
+
+// SoldCounts returns the live sold count of each item whose counters exist.
+func (s *FlashSaleStock) SoldCounts(ctx context.Context, itemIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(itemIDs))
+	if len(itemIDs) == 0 {
+		return counts, nil
+	}
+
+	keys := make([]string, len(itemIDs))
+	for i, id := range itemIDs {
+		keys[i] = soldKey(id)
+	}
+
+	// Keys of different items may live on different cluster slots, so read
+	// them through a pipeline rather than a single MGET.
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	for i, cmd := range cmds {
+		sold, err := cmd.Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(sold)
+		if err != nil {
+			return nil, err
+		}
+		counts[itemIDs[i]] = n
+	}
+	return counts, nil
+}
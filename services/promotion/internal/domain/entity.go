@@ -25,6 +25,20 @@ const (
 	CouponScopeSeller   CouponScope = "seller"
 )
 
+// CouponStacking controls which other coupons a coupon may be combined with
+// on the same order.
+type CouponStacking string
+
+const (
+	CouponStackingExclusive    CouponStacking = "exclusive"
+	CouponStackingWithPlatform CouponStacking = "stackable_with_platform"
+	CouponStackingWithSeller   CouponStacking = "stackable_with_seller"
+)
+
+// PlatformCreator is the CreatedBy value of coupons issued by the marketplace
+// rather than by an individual seller.
+const PlatformCreator = "platform"
+
 // Coupon represents a discount coupon.
 type Coupon struct {
 	ID              string
@@ -38,6 +52,9 @@ type Coupon struct {
 	PerUserLimit    int
 	Scope           CouponScope
 	ScopeIDs        []string
+	CampaignID      string // set on single-use codes generated in bulk
+	Stacking        CouponStacking
+	Priority        int // higher priority coupons are applied first
 	CreatedBy       string // seller_id or "platform"
 	StartsAt        time.Time
 	ExpiresAt       time.Time
@@ -45,6 +62,115 @@ type Coupon struct {
 	CreatedAt       time.Time
 }
 
+// IsPlatform reports whether the coupon was issued by the marketplace.
+func (c *Coupon) IsPlatform() bool {
+	return c.CreatedBy == PlatformCreator
+}
+
+// StacksWith reports whether c may be applied on the same order as other.
+// Both coupons must allow stacking with the other's issuer.
+func (c *Coupon) StacksWith(other *Coupon) bool {
+	return c.allowsIssuerOf(other) && other.allowsIssuerOf(c)
+}
+
+func (c *Coupon) allowsIssuerOf(other *Coupon) bool {
+	switch c.Stacking {
+	case CouponStackingWithPlatform:
+		return other.IsPlatform()
+	case CouponStackingWithSeller:
+		return !other.IsPlatform()
+	default:
+		return false
+	}
+}
+
+// AppliesTo reports whether the coupon discounts the given line. Seller
+// coupons only apply to that seller's lines, and Scope/ScopeIDs further
+// restrict the lines to the listed categories, products or sellers.
+func (c *Coupon) AppliesTo(line OrderLine) bool {
+	if !c.IsPlatform() && line.SellerID != c.CreatedBy {
+		return false
+	}
+
+	return scopeMatches(c.Scope, c.ScopeIDs, line)
+}
+
+// scopeMatches reports whether a line falls within scope, i.e. its category,
+// product or seller is one of ids. CouponScopeAll matches every line.
+func scopeMatches(scope CouponScope, ids []string, line OrderLine) bool {
+	switch scope {
+	case CouponScopeCategory:
+		return containsID(ids, line.CategoryID)
+	case CouponScopeProduct:
+		return containsID(ids, line.ProductID)
+	case CouponScopeSeller:
+		return containsID(ids, line.SellerID)
+	default:
+		return true
+	}
+}
+
+func containsID(ids []string, id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// OrderLine is an order or cart line that coupons are evaluated against.
+type OrderLine struct {
+	LineID         string `json:"line_id"`
+	ProductID      string `json:"product_id"`
+	VariantID      string `json:"variant_id"`
+	CategoryID     string `json:"category_id"`
+	SellerID       string `json:"seller_id"`
+	Quantity       int    `json:"quantity"`
+	UnitPriceCents int64  `json:"unit_price_cents"`
+}
+
+// TotalCents returns the line total before discounts.
+func (l OrderLine) TotalCents() int64 {
+	return l.UnitPriceCents * int64(l.Quantity)
+}
+
+// LineDiscount is the share of a coupon discount allocated to one order line,
+// used to compute partial refunds.
+type LineDiscount struct {
+	LineID        string `json:"line_id"`
+	ProductID     string `json:"product_id"`
+	VariantID     string `json:"variant_id"`
+	SellerID      string `json:"seller_id"`
+	DiscountCents int64  `json:"discount_cents"`
+}
+
+// ShippingQuote is a shipping rate returned by the shipping service for the
+// order (or for one seller's part of it when SellerID is set).
+type ShippingQuote struct {
+	SellerID    string `json:"seller_id"`
+	CarrierCode string `json:"carrier_code"`
+	ServiceName string `json:"service_name"`
+	RateCents   int64  `json:"rate_cents"`
+}
+
+// CouponUsageStatus tracks a redemption through the order lifecycle.
+type CouponUsageStatus string
+
+const (
+	// CouponUsageReserved holds a use of the coupon for an order that has
+	// not been confirmed yet.
+	CouponUsageReserved CouponUsageStatus = "reserved"
+	// CouponUsageConfirmed is a use of the coupon by a confirmed order.
+	CouponUsageConfirmed CouponUsageStatus = "confirmed"
+	// CouponUsageReleased is a use returned to the coupon, e.g. because the
+	// order was cancelled. Released usages do not count towards limits.
+	CouponUsageReleased CouponUsageStatus = "released"
+)
+
 // CouponUsage records when a coupon is redeemed.
 type CouponUsage struct {
 	ID            string
@@ -52,9 +178,21 @@ type CouponUsage struct {
 	UserID        string
 	OrderID       string
 	DiscountCents int64
+	Allocations   []LineDiscount
+	Status        CouponUsageStatus
 	CreatedAt     time.Time
+	UpdatedAt     time.Time
 }
 
+// FlashSaleStatus tracks where a flash sale is in its schedule.
+type FlashSaleStatus string
+
+const (
+	FlashSaleScheduled FlashSaleStatus = "scheduled"
+	FlashSaleLive      FlashSaleStatus = "live"
+	FlashSaleEnded     FlashSaleStatus = "ended"
+)
+
 // FlashSale represents a time-limited sale event.
 type FlashSale struct {
 	ID        string
@@ -62,6 +200,7 @@ type FlashSale struct {
 	StartsAt  time.Time
 	EndsAt    time.Time
 	IsActive  bool
+	Status    FlashSaleStatus
 	Items     []FlashSaleItem
 	CreatedAt time.Time
 }
@@ -74,19 +213,267 @@ type FlashSaleItem struct {
 	VariantID      string
 	SalePriceCents int64
 	QuantityLimit  int
-	SoldCount      int
+	// PerBuyerLimit caps the units a single buyer may claim; zero means no cap.
+	PerBuyerLimit int
+	SoldCount     int
+}
+
+// Remaining returns the units still available, or -1 when the item has no
+// quantity limit.
+func (i *FlashSaleItem) Remaining() int {
+	if i.QuantityLimit <= 0 {
+		return -1
+	}
+	if i.SoldCount >= i.QuantityLimit {
+		return 0
+	}
+	return i.QuantityLimit - i.SoldCount
+}
+
+// FlashSaleClaimStatus tracks a claim of flash sale units.
+type FlashSaleClaimStatus string
+
+const (
+	FlashSaleClaimActive   FlashSaleClaimStatus = "claimed"
+	FlashSaleClaimReleased FlashSaleClaimStatus = "released"
+)
+
+// FlashSaleClaim records units of a flash sale item claimed by a buyer for
+// an order. Active claims are the source of truth for sold counts.
+type FlashSaleClaim struct {
+	ID              string
+	FlashSaleID     string
+	FlashSaleItemID string
+	BuyerID         string
+	OrderID         string
+	Quantity        int
+	Status          FlashSaleClaimStatus
+	CreatedAt       time.Time
 }
 
 // Bundle represents a product bundle offering.
 type Bundle struct {
-	ID              string
-	Name            string
-	SellerID        string
-	ProductIDs      []string
+	ID               string
+	Name             string
+	SellerID         string
+	ProductIDs       []string
+	Items            []BundleItem
 	BundlePriceCents int64
-	SavingsCents    int64
-	IsActive        bool
-	CreatedAt       time.Time
+	SavingsCents     int64
+	IsActive         bool
+	CreatedAt        time.Time
+}
+
+// BundleItem is one component of a bundle. An empty VariantID matches any
+// variant of the product.
+type BundleItem struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// Matches reports whether an order line can fill this bundle component.
+func (i BundleItem) Matches(line OrderLine) bool {
+	return line.ProductID == i.ProductID && (i.VariantID == "" || line.VariantID == i.VariantID)
+}
+
+// BundleAllocation is the share of a bundle price allocated to one order
+// line, used for refunds and seller wallet splits.
+type BundleAllocation struct {
+	LineID         string `json:"line_id"`
+	ProductID      string `json:"product_id"`
+	VariantID      string `json:"variant_id"`
+	SellerID       string `json:"seller_id"`
+	Quantity       int    `json:"quantity"`
+	ListPriceCents int64  `json:"list_price_cents"`
+	PriceCents     int64  `json:"price_cents"`
+	DiscountCents  int64  `json:"discount_cents"`
+}
+
+// ProductSeller records which seller owns a product, as announced by the
+// product service.
+type ProductSeller struct {
+	ProductID string
+	SellerID  string
+	UpdatedAt time.Time
+}
+
+// PromotionTarget selects the order lines a promotion condition or action
+// looks at, using the same scopes as coupons.
+type PromotionTarget struct {
+	Scope    CouponScope `json:"scope"`
+	ScopeIDs []string    `json:"scope_ids,omitempty"`
+}
+
+// Matches reports whether the line is selected by the target.
+func (t PromotionTarget) Matches(line OrderLine) bool {
+	return scopeMatches(t.Scope, t.ScopeIDs, line)
+}
+
+// PromotionConditionType identifies what a promotion condition checks.
+type PromotionConditionType string
+
+const (
+	// PromotionConditionMinSubtotal requires the targeted lines to total at
+	// least MinSubtotalCents, e.g. "spend $100".
+	PromotionConditionMinSubtotal PromotionConditionType = "min_subtotal"
+	// PromotionConditionMinQuantity requires at least MinQuantity units of
+	// the targeted lines, e.g. "contains a product from category X".
+	PromotionConditionMinQuantity PromotionConditionType = "min_quantity"
+)
+
+// PromotionCondition is one requirement an order must meet before a
+// promotion's actions apply.
+type PromotionCondition struct {
+	Type             PromotionConditionType `json:"type"`
+	Target           PromotionTarget        `json:"target"`
+	MinSubtotalCents int64                  `json:"min_subtotal_cents,omitempty"`
+	MinQuantity      int                    `json:"min_quantity,omitempty"`
+}
+
+// PromotionActionType identifies the reward a promotion action grants.
+type PromotionActionType string
+
+const (
+	// PromotionActionPercentOff takes PercentOff off the targeted lines.
+	PromotionActionPercentOff PromotionActionType = "percent_off"
+	// PromotionActionAmountOff takes AmountOffCents off the targeted lines.
+	PromotionActionAmountOff PromotionActionType = "amount_off"
+	// PromotionActionBuyXGetY discounts GetQuantity of every BuyQuantity +
+	// GetQuantity targeted units by PercentOff, cheapest units first.
+	PromotionActionBuyXGetY PromotionActionType = "buy_x_get_y"
+	// PromotionActionTieredQuantity takes the PercentOff of the highest tier
+	// reached by the targeted quantity off the targeted lines.
+	PromotionActionTieredQuantity PromotionActionType = "tiered_quantity"
+	// PromotionActionFreeGift adds a free gift item to the order.
+	PromotionActionFreeGift PromotionActionType = "free_gift"
+)
+
+// QuantityTier is one step of a tiered quantity discount.
+type QuantityTier struct {
+	MinQuantity int   `json:"min_quantity"`
+	PercentOff  int64 `json:"percent_off"`
+}
+
+// PromotionAction is a reward granted when a promotion's conditions hold.
+// Percentages are stored as percentage * 100 (e.g. 1000 = 10%), like coupons.
+type PromotionAction struct {
+	Type             PromotionActionType `json:"type"`
+	Target           PromotionTarget     `json:"target"`
+	PercentOff       int64               `json:"percent_off,omitempty"`
+	AmountOffCents   int64               `json:"amount_off_cents,omitempty"`
+	BuyQuantity      int                 `json:"buy_quantity,omitempty"`
+	GetQuantity      int                 `json:"get_quantity,omitempty"`
+	Tiers            []QuantityTier      `json:"tiers,omitempty"`
+	GiftProductID    string              `json:"gift_product_id,omitempty"`
+	GiftVariantID    string              `json:"gift_variant_id,omitempty"`
+	GiftQuantity     int                 `json:"gift_quantity,omitempty"`
+	MaxDiscountCents int64               `json:"max_discount_cents,omitempty"`
+}
+
+// AutomaticPromotion is a promotion applied to carts without a code whenever
+// all of its conditions hold.
+type AutomaticPromotion struct {
+	ID          string
+	Name        string
+	Description string
+	CreatedBy   string // seller_id or "platform"
+	Conditions  []PromotionCondition
+	Actions     []PromotionAction
+	Priority    int  // higher priority promotions are evaluated first
+	Exclusive   bool // applies on its own, never stacked with other promotions
+	StartsAt    time.Time
+	EndsAt      time.Time
+	IsActive    bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsPlatform reports whether the promotion was created by the marketplace.
+func (p *AutomaticPromotion) IsPlatform() bool {
+	return p.CreatedBy == PlatformCreator
+}
+
+// Covers reports whether the promotion may look at the line at all. Seller
+// promotions only cover that seller's lines.
+func (p *AutomaticPromotion) Covers(line OrderLine) bool {
+	return p.IsPlatform() || line.SellerID == p.CreatedBy
+}
+
+// GiftItem is a free item added to an order by a promotion.
+type GiftItem struct {
+	PromotionID string `json:"promotion_id"`
+	ProductID   string `json:"product_id"`
+	VariantID   string `json:"variant_id"`
+	SellerID    string `json:"seller_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+// PromotionKind identifies which kind of promotion discounted an order.
+type PromotionKind string
+
+const (
+	PromotionKindCoupon    PromotionKind = "coupon"
+	PromotionKindFlashSale PromotionKind = "flash_sale"
+	PromotionKindBundle    PromotionKind = "bundle"
+)
+
+// OrderFact is the analytics view of an order, built from order events.
+// Its totals and refunds are in the settlement currency, so orders paid in
+// different currencies add up; the total the buyer paid is kept to convert
+// refunds.
+type OrderFact struct {
+	OrderID               string
+	BuyerID               string
+	Currency              string
+	TotalCents            int64
+	PresentmentCurrency   string
+	PresentmentTotalCents int64
+	SellerTotals          map[string]int64 // settlement share per seller
+	Cancelled             bool
+	RefundedCents         int64
+	OrderedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// AnalyticsFilter selects the orders placed in [From, To), optionally
+// narrowed to the promotions of one seller or of one kind.
+type AnalyticsFilter struct {
+	From     time.Time
+	To       time.Time
+	SellerID string
+	Kind     PromotionKind
+}
+
+// PromotionTotals sums up the orders discounted by a promotion, or by any
+// of a seller's promotions, in which case Kind, PromotionID and
+// PromotionName are empty. Revenue is the share of each order sold by the
+// promotion's seller, or the whole order for platform promotions, and
+// refunds are spread evenly across each order. Cancelled orders only count
+// towards CancelledRedemptions.
+type PromotionTotals struct {
+	Kind                   PromotionKind
+	PromotionID            string
+	PromotionName          string
+	SellerID               string
+	Promotions             int
+	Redemptions            int
+	CancelledRedemptions   int
+	DiscountCostCents      int64
+	AttributedRevenueCents int64
+	RefundedCents          int64
+}
+
+// PromotionAttribution records that a promotion discounted an order.
+type PromotionAttribution struct {
+	ID            string
+	OrderID       string
+	Kind          PromotionKind
+	PromotionID   string
+	PromotionName string
+	SellerID      string // seller_id or PlatformCreator
+	DiscountCents int64
+	AttributedAt  time.Time
 }
 
 // NewCoupon creates a new Coupon with a generated ID.
@@ -98,21 +485,25 @@ func NewCoupon(code string, couponType CouponType, discountValue int64, createdB
 		Type:          couponType,
 		DiscountValue: discountValue,
 		Scope:         CouponScopeAll,
+		Stacking:      CouponStackingExclusive,
 		CreatedBy:     createdBy,
 		IsActive:      true,
 		CreatedAt:     now,
 	}
 }
 
-// NewCouponUsage creates a new CouponUsage record.
+// NewCouponUsage creates a new CouponUsage record in the reserved state.
 func NewCouponUsage(couponID, userID, orderID string, discountCents int64) *CouponUsage {
+	now := time.Now()
 	return &CouponUsage{
 		ID:            uuid.New().String(),
 		CouponID:      couponID,
 		UserID:        userID,
 		OrderID:       orderID,
 		DiscountCents: discountCents,
-		CreatedAt:     time.Now(),
+		Status:        CouponUsageReserved,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 }
 
@@ -124,12 +515,13 @@ func NewFlashSale(name string, startsAt, endsAt time.Time) *FlashSale {
 		StartsAt:  startsAt,
 		EndsAt:    endsAt,
 		IsActive:  true,
+		Status:    FlashSaleScheduled,
 		CreatedAt: time.Now(),
 	}
 }
 
 // NewFlashSaleItem creates a new FlashSaleItem with a generated ID.
-func NewFlashSaleItem(flashSaleID, productID, variantID string, salePriceCents int64, quantityLimit int) *FlashSaleItem {
+func NewFlashSaleItem(flashSaleID, productID, variantID string, salePriceCents int64, quantityLimit, perBuyerLimit int) *FlashSaleItem {
 	return &FlashSaleItem{
 		ID:             uuid.New().String(),
 		FlashSaleID:    flashSaleID,
@@ -137,20 +529,80 @@ func NewFlashSaleItem(flashSaleID, productID, variantID string, salePriceCents i
 		VariantID:      variantID,
 		SalePriceCents: salePriceCents,
 		QuantityLimit:  quantityLimit,
+		PerBuyerLimit:  perBuyerLimit,
 		SoldCount:      0,
 	}
 }
 
-// NewBundle creates a new Bundle with a generated ID.
-func NewBundle(name, sellerID string, productIDs []string, bundlePriceCents, savingsCents int64) *Bundle {
-	return &Bundle{
+// NewFlashSaleClaim creates a new active FlashSaleClaim with a generated ID.
+func NewFlashSaleClaim(item *FlashSaleItem, buyerID, orderID string, quantity int) *FlashSaleClaim {
+	return &FlashSaleClaim{
+		ID:              uuid.New().String(),
+		FlashSaleID:     item.FlashSaleID,
+		FlashSaleItemID: item.ID,
+		BuyerID:         buyerID,
+		OrderID:         orderID,
+		Quantity:        quantity,
+		Status:          FlashSaleClaimActive,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// NewBundle creates a new Bundle with a generated ID. ProductIDs lists the
+// distinct products of items.
+func NewBundle(name, sellerID string, items []BundleItem, bundlePriceCents, savingsCents int64) *Bundle {
+	bundle := &Bundle{
 		ID:               uuid.New().String(),
 		Name:             name,
 		SellerID:         sellerID,
-		ProductIDs:       productIDs,
 		BundlePriceCents: bundlePriceCents,
 		SavingsCents:     savingsCents,
 		IsActive:         true,
 		CreatedAt:        time.Now(),
 	}
+	bundle.SetItems(items)
+	return bundle
+}
+
+// SetItems sets the items of a bundle and the distinct products they are
+// of.
+func (b *Bundle) SetItems(items []BundleItem) {
+	b.Items = items
+	b.ProductIDs = nil
+	for _, item := range items {
+		if !containsID(b.ProductIDs, item.ProductID) {
+			b.ProductIDs = append(b.ProductIDs, item.ProductID)
+		}
+	}
+}
+
+// NewAutomaticPromotion creates a new active AutomaticPromotion with a generated ID.
+func NewAutomaticPromotion(name, createdBy string, conditions []PromotionCondition, actions []PromotionAction, startsAt, endsAt time.Time) *AutomaticPromotion {
+	now := time.Now()
+	return &AutomaticPromotion{
+		ID:         uuid.New().String(),
+		Name:       name,
+		CreatedBy:  createdBy,
+		Conditions: conditions,
+		Actions:    actions,
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+		IsActive:   true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// NewPromotionAttribution creates a new PromotionAttribution with a generated ID.
+func NewPromotionAttribution(orderID string, kind PromotionKind, promotionID, promotionName, sellerID string, discountCents int64) *PromotionAttribution {
+	return &PromotionAttribution{
+		ID:            uuid.New().String(),
+		OrderID:       orderID,
+		Kind:          kind,
+		PromotionID:   promotionID,
+		PromotionName: promotionName,
+		SellerID:      sellerID,
+		DiscountCents: discountCents,
+		AttributedAt:  time.Now(),
+	}
 }
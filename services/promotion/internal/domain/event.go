@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // EventPublisher defines the interface for publishing domain events.
 type EventPublisher interface {
@@ -10,6 +13,7 @@ type EventPublisher interface {
 // Event subjects for promotion domain events.
 const (
 	EventCouponRedeemed   = "coupon.redeemed"
+	EventCouponReleased   = "coupon.released"
 	EventFlashSaleStarted = "flash_sale.started"
 	EventFlashSaleEnded   = "flash_sale.ended"
 )
@@ -21,10 +25,130 @@ type CouponRedeemedEvent struct {
 	UserID        string `json:"user_id"`
 	OrderID       string `json:"order_id"`
 	DiscountCents int64  `json:"discount_cents"`
+	CreatedBy     string `json:"created_by"`
+}
+
+// CouponReleasedEvent is the payload published when a redemption is released
+// back to its coupon, e.g. because the order was cancelled.
+type CouponReleasedEvent struct {
+	CouponID      string `json:"coupon_id"`
+	UserID        string `json:"user_id"`
+	OrderID       string `json:"order_id"`
+	DiscountCents int64  `json:"discount_cents"`
+}
+
+// Order lifecycle subjects consumed by the promotion service.
+const (
+	EventOrderCreated         = "order.created"
+	EventOrderConfirmed       = "order.confirmed"
+	EventOrderCancelled       = "order.cancelled"
+	EventSellerOrderCancelled = "order.seller_order.cancelled"
+)
+
+// EventPaymentRefunded is published by the payment service for each refund
+// of an order's payment.
+const EventPaymentRefunded = "payment.refunded"
+
+// OrderCreatedEvent is the payload of order.created published by the order
+// service. TotalCents is in the buyer's currency; the settlement total, the
+// seller orders' settlement shares and the bundle savings are in the
+// settlement currency.
+type OrderCreatedEvent struct {
+	OrderID              string             `json:"order_id"`
+	OrderNumber          string             `json:"order_number"`
+	BuyerID              string             `json:"buyer_id"`
+	TotalCents           int64              `json:"total_cents"`
+	Currency             string             `json:"currency"`
+	SettlementCurrency   string             `json:"settlement_currency"`
+	SettlementTotalCents int64              `json:"settlement_total_cents"`
+	Items                []OrderItemEvent   `json:"items"`
+	SellerOrders         []OrderSellerEvent `json:"seller_orders"`
+	Bundles              []OrderBundleEvent `json:"bundles"`
+}
+
+// OrderItemEvent is an order item in an order event payload. A flash sale
+// item's FlashSaleDiscountCents is what the sale took off its list price,
+// in the settlement currency.
+type OrderItemEvent struct {
+	ItemID                 string `json:"item_id"`
+	ProductID              string `json:"product_id"`
+	VariantID              string `json:"variant_id"`
+	Quantity               int    `json:"quantity"`
+	UnitPriceCents         int64  `json:"unit_price_cents"`
+	FlashSaleDiscountCents int64  `json:"flash_sale_discount_cents"`
+	SellerID               string `json:"seller_id"`
+}
+
+// OrderSellerEvent is a seller order in an order.created payload.
+type OrderSellerEvent struct {
+	SellerID                string `json:"seller_id"`
+	SettlementSubtotalCents int64  `json:"settlement_subtotal_cents"`
+}
+
+// OrderBundleEvent is a bundle applied to an order, in an order.created
+// payload.
+type OrderBundleEvent struct {
+	BundleID      string `json:"bundle_id"`
+	Name          string `json:"name"`
+	SellerID      string `json:"seller_id"`
+	DiscountCents int64  `json:"discount_cents"`
+}
+
+// PaymentRefundedEvent is the payload of payment.refunded. AmountCents is
+// the amount of this refund, in the order's currency.
+type PaymentRefundedEvent struct {
+	PaymentID   string `json:"payment_id"`
+	RefundID    string `json:"refund_id"`
+	OrderID     string `json:"order_id"`
+	AmountCents int64  `json:"amount_cents"`
+	Currency    string `json:"currency"`
+}
+
+// OrderStatusEvent is the payload of order status events published by the
+// order service.
+type OrderStatusEvent struct {
+	OrderID     string `json:"order_id"`
+	OrderNumber string `json:"order_number"`
+	BuyerID     string `json:"buyer_id"`
+	Status      string `json:"status"`
+}
+
+// SellerOrderCancelledEvent is the payload of order.seller_order.cancelled,
+// published by the order service when one seller's part of an order is
+// cancelled while the rest of the order goes ahead.
+type SellerOrderCancelledEvent struct {
+	OrderID       string           `json:"order_id"`
+	SellerOrderID string           `json:"seller_order_id"`
+	SellerID      string           `json:"seller_id"`
+	Items         []OrderItemEvent `json:"items"`
+}
+
+// ItemIDs returns the IDs of the cancelled seller order's items.
+func (e SellerOrderCancelledEvent) ItemIDs() []string {
+	ids := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		ids[i] = item.ItemID
+	}
+	return ids
 }
 
 // FlashSaleEvent is the payload published when a flash sale starts or ends.
 type FlashSaleEvent struct {
-	FlashSaleID string `json:"flash_sale_id"`
-	Name        string `json:"name"`
+	FlashSaleID string    `json:"flash_sale_id"`
+	Name        string    `json:"name"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+}
+
+// Product subjects consumed by the promotion service.
+const (
+	EventProductCreated = "product.created"
+	EventProductDeleted = "product.deleted"
+)
+
+// ProductEvent is the subset of product.created and product.deleted payloads
+// the promotion service uses to track product ownership.
+type ProductEvent struct {
+	ID       string `json:"id"`
+	SellerID string `json:"seller_id"`
 }
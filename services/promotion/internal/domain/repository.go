@@ -1,6 +1,32 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Errors returned by CouponRedemptionRepository.
+var (
+	ErrCouponUsageLimitReached   = errors.New("coupon usage limit reached")
+	ErrCouponPerUserLimitReached = errors.New("per-user coupon usage limit reached")
+)
+
+// Errors returned by FlashSaleStock.
+var (
+	ErrFlashSaleSoldOut           = errors.New("flash sale item sold out")
+	ErrFlashSaleBuyerLimitReached = errors.New("flash sale per-buyer limit reached")
+	// ErrFlashSaleStockNotSeeded means the item's counters are missing from
+	// the store and must be seeded from the claims table before claiming.
+	ErrFlashSaleStockNotSeeded = errors.New("flash sale stock not seeded")
+	// ErrFlashSaleClaimHeld means the order already holds a claim on the
+	// item.
+	ErrFlashSaleClaimHeld = errors.New("flash sale item already claimed by order")
+)
+
+// ErrProductNotFound is returned by ProductCatalog for products missing
+// from the catalog.
+var ErrProductNotFound = errors.New("product not found")
 
 // CouponRepository defines the interface for coupon persistence.
 type CouponRepository interface {
@@ -11,15 +37,41 @@ type CouponRepository interface {
 	Create(ctx context.Context, coupon *Coupon) error
 	Update(ctx context.Context, coupon *Coupon) error
 	IncrementUsageCount(ctx context.Context, id string) error
+	// CreateBatch persists many coupons at once, e.g. generated campaign codes.
+	CreateBatch(ctx context.Context, coupons []*Coupon) error
+	// ExistingCodes returns which of the given codes are already taken.
+	ExistingCodes(ctx context.Context, codes []string) ([]string, error)
+	ListByCampaign(ctx context.Context, campaignID string, page, pageSize int) ([]*Coupon, int64, error)
 }
 
 // CouponUsageRepository defines the interface for coupon usage persistence.
 type CouponUsageRepository interface {
 	GetByUserAndCoupon(ctx context.Context, userID, couponID string) ([]*CouponUsage, error)
+	// CountByUser counts the user's reserved and confirmed usages of a coupon.
 	CountByUser(ctx context.Context, userID, couponID string) (int64, error)
+	ListByOrder(ctx context.Context, orderID string) ([]*CouponUsage, error)
 	Create(ctx context.Context, usage *CouponUsage) error
 }
 
+// CouponRedemptionRepository changes coupon usage counts and usage records
+// together so that concurrent redemptions cannot exceed coupon limits.
+type CouponRedemptionRepository interface {
+	// Reserve atomically checks the global and per-user limits of each usage's
+	// coupon, increments its usage count and inserts the usage record. Either
+	// all usages are reserved or none is; ErrCouponUsageLimitReached or
+	// ErrCouponPerUserLimitReached is returned when a limit would be exceeded.
+	// A coupon is used at most once per order: reserving it again for the
+	// same order fills in the existing usage and counts nothing.
+	Reserve(ctx context.Context, usages []*CouponUsage) error
+	// ConfirmByOrder marks the order's reserved usages as confirmed.
+	ConfirmByOrder(ctx context.Context, orderID string) ([]*CouponUsage, error)
+	// ReleaseByOrder marks the order's outstanding usages as released and
+	// returns them to their coupons' usage counts.
+	ReleaseByOrder(ctx context.Context, orderID string) ([]*CouponUsage, error)
+	// ReleaseByIDs is like ReleaseByOrder for the given usages only.
+	ReleaseByIDs(ctx context.Context, ids []string) ([]*CouponUsage, error)
+}
+
 // FlashSaleRepository defines the interface for flash sale persistence.
 type FlashSaleRepository interface {
 	GetByID(ctx context.Context, id string) (*FlashSale, error)
@@ -27,13 +79,61 @@ type FlashSaleRepository interface {
 	ListAll(ctx context.Context, page, pageSize int) ([]*FlashSale, int64, error)
 	Create(ctx context.Context, flashSale *FlashSale) error
 	Update(ctx context.Context, flashSale *FlashSale) error
+	// ListDueToStart returns scheduled, active sales whose window has opened.
+	ListDueToStart(ctx context.Context, now time.Time) ([]*FlashSale, error)
+	// ListDueToEnd returns live sales whose window has closed or that were
+	// deactivated.
+	ListDueToEnd(ctx context.Context, now time.Time) ([]*FlashSale, error)
+	// TransitionStatus moves a sale from one status to another and reports
+	// whether this caller made the transition.
+	TransitionStatus(ctx context.Context, id string, from, to FlashSaleStatus) (bool, error)
 }
 
 // FlashSaleItemRepository defines the interface for flash sale item persistence.
 type FlashSaleItemRepository interface {
+	GetByID(ctx context.Context, id string) (*FlashSaleItem, error)
 	GetByFlashSaleID(ctx context.Context, flashSaleID string) ([]*FlashSaleItem, error)
 	Create(ctx context.Context, item *FlashSaleItem) error
 	IncrementSoldCount(ctx context.Context, id string) error
+	SetSoldCount(ctx context.Context, id string, soldCount int) error
+}
+
+// FlashSaleClaimRepository defines the interface for flash sale claim persistence.
+type FlashSaleClaimRepository interface {
+	Create(ctx context.Context, claim *FlashSaleClaim) error
+	ListActiveByOrder(ctx context.Context, orderID string) ([]*FlashSaleClaim, error)
+	// ListActiveByItem returns the active claims on an item.
+	ListActiveByItem(ctx context.Context, itemID string) ([]*FlashSaleClaim, error)
+	// Release marks an active claim as released and reports whether this
+	// caller released it.
+	Release(ctx context.Context, id string) (bool, error)
+}
+
+// FlashSaleStock keeps live per-item and per-buyer claim counters in a shared
+// store so concurrent checkouts can claim units atomically.
+type FlashSaleStock interface {
+	// Seed initialises an item's counters from its active claims unless
+	// they already exist.
+	Seed(ctx context.Context, itemID string, claims []*FlashSaleClaim, expiresAt time.Time) error
+	// Claim atomically adds quantity to the item and buyer counters if the
+	// item's quantity and per-buyer limits allow it, returning the new sold
+	// count. It returns ErrFlashSaleClaimHeld if the order already holds a
+	// claim on the item.
+	Claim(ctx context.Context, item *FlashSaleItem, buyerID, orderID string, quantity int) (int, error)
+	Release(ctx context.Context, itemID, buyerID, orderID string, quantity int) error
+	// SoldCounts returns the live sold count of each seeded item.
+	SoldCounts(ctx context.Context, itemIDs []string) (map[string]int, error)
+}
+
+// AutomaticPromotionRepository defines the interface for automatic promotion persistence.
+type AutomaticPromotionRepository interface {
+	GetByID(ctx context.Context, id string) (*AutomaticPromotion, error)
+	ListAll(ctx context.Context, page, pageSize int) ([]*AutomaticPromotion, int64, error)
+	// ListActive returns the active promotions running at now, highest
+	// priority first.
+	ListActive(ctx context.Context, now time.Time) ([]*AutomaticPromotion, error)
+	Create(ctx context.Context, promotion *AutomaticPromotion) error
+	Update(ctx context.Context, promotion *AutomaticPromotion) error
 }
 
 // BundleRepository defines the interface for bundle persistence.
@@ -41,6 +141,59 @@ type BundleRepository interface {
 	GetByID(ctx context.Context, id string) (*Bundle, error)
 	ListBySeller(ctx context.Context, sellerID string, page, pageSize int) ([]*Bundle, int64, error)
 	ListActive(ctx context.Context, page, pageSize int) ([]*Bundle, int64, error)
+	// ListActiveForProducts returns the active bundles containing any of the
+	// given products.
+	ListActiveForProducts(ctx context.Context, productIDs []string) ([]*Bundle, error)
 	Create(ctx context.Context, bundle *Bundle) error
 	Update(ctx context.Context, bundle *Bundle) error
 }
+
+// ProductSellerRepository defines the interface for the product ownership
+// read model built from product events.
+type ProductSellerRepository interface {
+	Upsert(ctx context.Context, productSeller *ProductSeller) error
+	Delete(ctx context.Context, productID string) error
+	// GetSellers returns the seller of each known product, keyed by product ID.
+	GetSellers(ctx context.Context, productIDs []string) (map[string]string, error)
+}
+
+// ProductCatalog looks products up in the product service, for products
+// the ownership read model has not heard of.
+type ProductCatalog interface {
+	// GetProductSeller returns the seller of a product.
+	GetProductSeller(ctx context.Context, productID string) (string, error)
+}
+
+// OrderFactRepository defines the interface for the order read model used by
+// promotion analytics.
+type OrderFactRepository interface {
+	GetByID(ctx context.Context, orderID string) (*OrderFact, error)
+	// Upsert records an order, keeping any cancellation or refunds already
+	// seen for it.
+	Upsert(ctx context.Context, fact *OrderFact) error
+	MarkCancelled(ctx context.Context, orderID string) (bool, error)
+	// AddRefund adds a refund of amountCents, in the currency the buyer
+	// paid in, to the order's refunded amount, capped at the order total.
+	// Adding the same refund twice is a no-op that reports false.
+	AddRefund(ctx context.Context, refundID, orderID string, amountCents int64) (bool, error)
+	// AverageUnpromotedValue returns the average share sold by sellerID of
+	// the uncancelled orders placed in [from, to) that no promotion of
+	// sellerID discounted. Platform averages use whole orders without any
+	// promotion.
+	AverageUnpromotedValue(ctx context.Context, sellerID string, from, to time.Time) (int64, error)
+}
+
+// PromotionAttributionRepository defines the interface for promotion
+// attribution persistence.
+type PromotionAttributionRepository interface {
+	// Create records an attribution; recording the same promotion against the
+	// same order twice is a no-op.
+	Create(ctx context.Context, attribution *PromotionAttribution) error
+	// SumByPromotion sums up the orders matching filter per promotion,
+	// highest attributed revenue first.
+	SumByPromotion(ctx context.Context, filter AnalyticsFilter) ([]*PromotionTotals, error)
+	// SumBySeller sums up the orders matching filter per seller, counting
+	// an order discounted by several of a seller's promotions once, highest
+	// attributed revenue first.
+	SumBySeller(ctx context.Context, filter AnalyticsFilter) ([]*PromotionTotals, error)
+}
@@ -11,7 +11,11 @@ type Config struct {
 	GRPCPort string
 	Postgres PostgresConfig
 	NATS     NATSConfig
+	Redis    RedisConfig
 	LogLevel string
+	// ProductGRPCAddr is the address of the product service's gRPC API,
+	// which the sellers of products are looked up in.
+	ProductGRPCAddr string
 }
 
 // PostgresConfig holds Postgres connection configuration.
@@ -28,6 +32,11 @@ type NATSConfig struct {
 	URL string
 }
 
+// RedisConfig holds Redis connection configuration.
+type RedisConfig struct {
+	URL string
+}
+
 // DSN returns the Postgres connection string.
 func (c PostgresConfig) DSN() string {
 	return fmt.Sprintf(
@@ -52,6 +61,10 @@ func Load() *Config {
 		NATS: NATSConfig{
 			URL: getEnv("NATS_URL", "nats://localhost:4222"),
 		},
+		Redis: RedisConfig{
+			URL: getEnv("REDIS_URL", "localhost:6379"),
+		},
+		ProductGRPCAddr: getEnv("PRODUCT_GRPC_ADDR", "localhost:9081"),
 	}
 }
 
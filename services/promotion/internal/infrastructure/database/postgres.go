@@ -20,13 +20,24 @@ func NewPostgresDB(cfg config.PostgresConfig) (*gorm.DB, error) {
 
 	log.Info().Msg("connected to PostgreSQL")
 
+	// Coupons are used at most once per order
+	if err := postgres.DedupeCouponUsages(db); err != nil {
+		return nil, err
+	}
+
 	// Auto-migrate tables
 	err = db.AutoMigrate(
 		&postgres.CouponModel{},
 		&postgres.CouponUsageModel{},
 		&postgres.FlashSaleModel{},
 		&postgres.FlashSaleItemModel{},
+		&postgres.FlashSaleClaimModel{},
 		&postgres.BundleModel{},
+		&postgres.AutomaticPromotionModel{},
+		&postgres.ProductSellerModel{},
+		&postgres.OrderFactModel{},
+		&postgres.RefundFactModel{},
+		&postgres.PromotionAttributionModel{},
 	)
 	if err != nil {
 		return nil, err
@@ -40,6 +40,20 @@ func (p *Publisher) Publish(ctx context.Context, subject string, data interface{
 	return nil
 }
 
+// Subscribe subscribes to a NATS subject with a handler function.
+func (p *Publisher) Subscribe(subject string, handler func(data []byte)) (*nats.Subscription, error) {
+	sub, err := p.conn.Subscribe(subject, func(msg *nats.Msg) {
+		handler(msg.Data)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("subject", subject).Msg("failed to subscribe")
+		return nil, err
+	}
+
+	log.Info().Str("subject", subject).Msg("subscribed to subject")
+	return sub, nil
+}
+
 // Close closes the NATS connection.
 func (p *Publisher) Close() {
 	if p.conn != nil {
@@ -0,0 +1,229 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+	"github.com/southern-martin/ecommerce/services/promotion/internal/usecase"
+)
+
+// StartOrderSubscriber ties coupon reservations to the order lifecycle:
+// order.confirmed confirms the order's reserved coupons and order.cancelled
+// releases them back to their coupons. order.seller_order.cancelled only
+// releases the coupons of the cancelled seller order's lines.
+func StartOrderSubscriber(p *Publisher, couponUC *usecase.CouponUseCase) error {
+	if _, err := p.Subscribe(domain.EventOrderConfirmed, func(data []byte) {
+		handleOrderEvent(domain.EventOrderConfirmed, data, couponUC.ConfirmOrderCoupons)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(domain.EventOrderCancelled, func(data []byte) {
+		handleOrderEvent(domain.EventOrderCancelled, data, couponUC.ReleaseOrderCoupons)
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(domain.EventSellerOrderCancelled, func(data []byte) {
+		handleSellerOrderEvent(data, func(ctx context.Context, event domain.SellerOrderCancelledEvent) (int, error) {
+			usages, err := couponUC.ReleaseSellerOrderCoupons(ctx, event.OrderID, event.ItemIDs())
+			return len(usages), err
+		})
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func handleSellerOrderEvent(data []byte, apply func(ctx context.Context, event domain.SellerOrderCancelledEvent) (int, error)) {
+	var event domain.SellerOrderCancelledEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		log.Error().Err(err).Str("subject", domain.EventSellerOrderCancelled).Msg("failed to unmarshal seller order event")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	released, err := apply(ctx, event)
+	if err != nil {
+		log.Error().Err(err).Str("order_id", event.OrderID).Str("seller_order_id", event.SellerOrderID).Msg("failed to release seller order promotions")
+		return
+	}
+
+	if released > 0 {
+		log.Info().Str("order_id", event.OrderID).Str("seller_order_id", event.SellerOrderID).Int("released", released).Msg("seller order promotions released")
+	}
+}
+
+func handleOrderEvent(subject string, data []byte, apply func(ctx context.Context, orderID string) ([]*domain.CouponUsage, error)) {
+	var event domain.OrderStatusEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		log.Error().Err(err).Str("subject", subject).Msg("failed to unmarshal order event")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	usages, err := apply(ctx, event.OrderID)
+	if err != nil {
+		log.Error().Err(err).Str("subject", subject).Str("order_id", event.OrderID).Msg("failed to update coupon usages")
+		return
+	}
+
+	if len(usages) > 0 {
+		log.Info().Str("subject", subject).Str("order_id", event.OrderID).Int("usages", len(usages)).Msg("coupon usages updated")
+	}
+}
+
+// StartFlashSaleOrderSubscriber returns the flash sale units claimed by an
+// order when the order is cancelled, or those claimed for a seller order's
+// items when only that seller order is.
+func StartFlashSaleOrderSubscriber(p *Publisher, flashSaleUC *usecase.FlashSaleUseCase) error {
+	if _, err := p.Subscribe(domain.EventSellerOrderCancelled, func(data []byte) {
+		handleSellerOrderEvent(data, func(ctx context.Context, event domain.SellerOrderCancelledEvent) (int, error) {
+			claims, err := flashSaleUC.ReleaseSellerOrderClaims(ctx, event.OrderID, event.Items)
+			return len(claims), err
+		})
+	}); err != nil {
+		return err
+	}
+
+	_, err := p.Subscribe(domain.EventOrderCancelled, func(data []byte) {
+		var event domain.OrderStatusEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Str("subject", domain.EventOrderCancelled).Msg("failed to unmarshal order event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		claims, err := flashSaleUC.ReleaseOrderClaims(ctx, event.OrderID)
+		if err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("failed to release flash sale claims")
+			return
+		}
+
+		if len(claims) > 0 {
+			log.Info().Str("order_id", event.OrderID).Int("claims", len(claims)).Msg("flash sale claims released")
+		}
+	})
+	return err
+}
+
+// StartProductSubscriber keeps the product ownership records used to
+// validate bundles in sync with product.created and product.deleted.
+func StartProductSubscriber(p *Publisher, bundleUC *usecase.BundleUseCase) error {
+	if _, err := p.Subscribe(domain.EventProductCreated, func(data []byte) {
+		handleProductEvent(domain.EventProductCreated, data, func(ctx context.Context, event domain.ProductEvent) error {
+			return bundleUC.RecordProductSeller(ctx, event.ID, event.SellerID)
+		})
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(domain.EventProductDeleted, func(data []byte) {
+		handleProductEvent(domain.EventProductDeleted, data, func(ctx context.Context, event domain.ProductEvent) error {
+			return bundleUC.ForgetProduct(ctx, event.ID)
+		})
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func handleProductEvent(subject string, data []byte, apply func(ctx context.Context, event domain.ProductEvent) error) {
+	var event domain.ProductEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		log.Error().Err(err).Str("subject", subject).Msg("failed to unmarshal product event")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := apply(ctx, event); err != nil {
+		log.Error().Err(err).Str("subject", subject).Str("product_id", event.ID).Msg("failed to update product ownership")
+	}
+}
+
+// StartAnalyticsSubscriber feeds the promotion analytics read model from
+// order.created, coupon.redeemed, order.cancelled and payment.refunded.
+func StartAnalyticsSubscriber(p *Publisher, analyticsUC *usecase.AnalyticsUseCase) error {
+	if _, err := p.Subscribe(domain.EventOrderCreated, func(data []byte) {
+		var event domain.OrderCreatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Str("subject", domain.EventOrderCreated).Msg("failed to unmarshal order event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := analyticsUC.RecordOrder(ctx, event); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("failed to record order for analytics")
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(domain.EventCouponRedeemed, func(data []byte) {
+		var event domain.CouponRedeemedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Str("subject", domain.EventCouponRedeemed).Msg("failed to unmarshal coupon event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := analyticsUC.RecordCouponRedemption(ctx, event); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Str("coupon_id", event.CouponID).Msg("failed to record coupon redemption for analytics")
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(domain.EventOrderCancelled, func(data []byte) {
+		var event domain.OrderStatusEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Str("subject", domain.EventOrderCancelled).Msg("failed to unmarshal order event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := analyticsUC.RecordCancellation(ctx, event.OrderID); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("failed to record order cancellation for analytics")
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(domain.EventPaymentRefunded, func(data []byte) {
+		var event domain.PaymentRefundedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Str("subject", domain.EventPaymentRefunded).Msg("failed to unmarshal payment event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if _, err := analyticsUC.RecordRefund(ctx, event); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("failed to record order refund for analytics")
+		}
+	}); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package product
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/southern-martin/ecommerce/pkg/grpcjson"
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+)
+
+// Client implements domain.ProductCatalog on top of the product service's
+// gRPC API.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient creates a client of the product service's gRPC API at addr.
+// The connection is established lazily, on the first call.
+func NewClient(addr string) (*Client, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpcjson.CallOption()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to product service at %s: %w", addr, err)
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the gRPC connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// --- Messages of the product service's gRPC API ---
+
+type getProductRequest struct {
+	ProductID string
+}
+
+type getProductResponse struct {
+	ID       string
+	SellerID string
+}
+
+// GetProductSeller returns the seller of a product.
+func (c *Client) GetProductSeller(ctx context.Context, productID string) (string, error) {
+	var resp getProductResponse
+	if err := c.conn.Invoke(ctx, "/product.ProductService/GetProduct",
+		&getProductRequest{ProductID: productID}, &resp); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", domain.ErrProductNotFound
+		}
+		return "", fmt.Errorf("failed to fetch product: %w", err)
+	}
+	return resp.SellerID, nil
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/southern-martin/ecommerce/services/promotion/internal/usecase"
+)
+
+// StartFlashSaleScheduler runs the flash sale schedule every interval until
+// ctx is cancelled, activating and deactivating sales and reconciling their
+// sold counts.
+func StartFlashSaleScheduler(ctx context.Context, flashSaleUC *usecase.FlashSaleUseCase, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			runFlashSaleSchedule(ctx, flashSaleUC)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func runFlashSaleSchedule(ctx context.Context, flashSaleUC *usecase.FlashSaleUseCase) {
+	runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := flashSaleUC.RunSchedule(runCtx, time.Now()); err != nil {
+		log.Error().Err(err).Msg("flash sale schedule run failed")
+	}
+}
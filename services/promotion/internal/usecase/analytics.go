@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+)
+
+// maxAnalyticsRange bounds the date range of a single analytics query.
+const maxAnalyticsRange = 366 * 24 * time.Hour
+
+// ErrInvalidAnalyticsRange is returned when an analytics query has an empty,
+// inverted or overly long date range.
+var ErrInvalidAnalyticsRange = errors.New("analytics range must end after it starts and span at most 366 days")
+
+// AnalyticsQuery selects the orders placed in [From, To), optionally narrowed
+// to one seller's promotions or one kind of promotion.
+type AnalyticsQuery struct {
+	From     time.Time
+	To       time.Time
+	SellerID string
+	Kind     domain.PromotionKind
+}
+
+// PerformanceMetrics are the figures reported for a promotion or a seller.
+// Cancelled orders only count towards CancelledRedemptions.
+type PerformanceMetrics struct {
+	Redemptions            int
+	CancelledRedemptions   int
+	DiscountCostCents      int64
+	AttributedRevenueCents int64
+	RefundedCents          int64
+	NetRevenueCents        int64 // attributed revenue minus refunds
+	AverageOrderValueCents int64
+	// BaselineOrderValueCents is the average value of the orders in the same
+	// range that no promotion of the same owner touched.
+	BaselineOrderValueCents int64
+	AOVUpliftBasisPoints    int64 // (average - baseline) / baseline, 10000 = 100%
+}
+
+// PromotionPerformance reports how a single promotion performed.
+type PromotionPerformance struct {
+	Kind          domain.PromotionKind
+	PromotionID   string
+	PromotionName string
+	SellerID      string
+	PerformanceMetrics
+}
+
+// SellerPerformance reports how all promotions of a seller performed
+// together. An order discounted by several of the seller's promotions counts
+// once.
+type SellerPerformance struct {
+	SellerID   string
+	Promotions int
+	PerformanceMetrics
+}
+
+// AnalyticsUseCase maintains the promotion analytics read model from order
+// and coupon events and reports on it.
+type AnalyticsUseCase struct {
+	orderFactRepo     domain.OrderFactRepository
+	attributionRepo   domain.PromotionAttributionRepository
+	flashSaleRepo     domain.FlashSaleRepository
+	flashSaleItemRepo domain.FlashSaleItemRepository
+	claimRepo         domain.FlashSaleClaimRepository
+}
+
+// NewAnalyticsUseCase creates a new AnalyticsUseCase instance.
+func NewAnalyticsUseCase(
+	orderFactRepo domain.OrderFactRepository,
+	attributionRepo domain.PromotionAttributionRepository,
+	flashSaleRepo domain.FlashSaleRepository,
+	flashSaleItemRepo domain.FlashSaleItemRepository,
+	claimRepo domain.FlashSaleClaimRepository,
+) *AnalyticsUseCase {
+	return &AnalyticsUseCase{
+		orderFactRepo:     orderFactRepo,
+		attributionRepo:   attributionRepo,
+		flashSaleRepo:     flashSaleRepo,
+		flashSaleItemRepo: flashSaleItemRepo,
+		claimRepo:         claimRepo,
+	}
+}
+
+// RecordOrder stores a newly created order, in the settlement currency, and
+// attributes it to the flash sales whose units it claimed and to the bundles
+// applied to it. Orders placed before the order service published
+// settlement totals are stored in the currency the buyer paid in.
+func (uc *AnalyticsUseCase) RecordOrder(ctx context.Context, event domain.OrderCreatedEvent) error {
+	if event.OrderID == "" {
+		return errors.New("order id is required")
+	}
+
+	now := time.Now()
+	fact := &domain.OrderFact{
+		OrderID:               event.OrderID,
+		BuyerID:               event.BuyerID,
+		Currency:              event.SettlementCurrency,
+		TotalCents:            event.SettlementTotalCents,
+		PresentmentCurrency:   event.Currency,
+		PresentmentTotalCents: event.TotalCents,
+		SellerTotals:          make(map[string]int64),
+		OrderedAt:             now,
+		UpdatedAt:             now,
+	}
+	if event.SettlementCurrency == "" {
+		fact.Currency = event.Currency
+		fact.TotalCents = event.TotalCents
+		for _, item := range event.Items {
+			fact.SellerTotals[item.SellerID] += item.UnitPriceCents * int64(item.Quantity)
+		}
+	}
+	for _, sellerOrder := range event.SellerOrders {
+		fact.SellerTotals[sellerOrder.SellerID] += sellerOrder.SettlementSubtotalCents
+	}
+	if err := uc.orderFactRepo.Upsert(ctx, fact); err != nil {
+		return err
+	}
+
+	if err := uc.attributeFlashSales(ctx, event); err != nil {
+		return err
+	}
+	return uc.attributeBundles(ctx, event)
+}
+
+// RecordCouponRedemption attributes an order to the coupon it redeemed.
+func (uc *AnalyticsUseCase) RecordCouponRedemption(ctx context.Context, event domain.CouponRedeemedEvent) error {
+	if event.OrderID == "" || event.CouponID == "" {
+		return errors.New("order id and coupon id are required")
+	}
+
+	sellerID := event.CreatedBy
+	if sellerID == "" {
+		sellerID = domain.PlatformCreator
+	}
+	attribution := domain.NewPromotionAttribution(event.OrderID, domain.PromotionKindCoupon, event.CouponID, event.CouponCode, sellerID, event.DiscountCents)
+	return uc.attributionRepo.Create(ctx, attribution)
+}
+
+// RecordCancellation marks an order as cancelled. It reports false when the
+// order is unknown or was already cancelled.
+func (uc *AnalyticsUseCase) RecordCancellation(ctx context.Context, orderID string) (bool, error) {
+	return uc.orderFactRepo.MarkCancelled(ctx, orderID)
+}
+
+// RecordRefund adds a refund of an order's payment to the order, once. A
+// payment is refunded at most once, so refunds published without an ID are
+// keyed by their payment. It reports false when the order is unknown or the
+// refund was already recorded.
+func (uc *AnalyticsUseCase) RecordRefund(ctx context.Context, event domain.PaymentRefundedEvent) (bool, error) {
+	if event.OrderID == "" || event.AmountCents <= 0 {
+		return false, errors.New("order id and a positive refund amount are required")
+	}
+	refundID := event.RefundID
+	if refundID == "" {
+		refundID = event.PaymentID
+	}
+	if refundID == "" {
+		return false, errors.New("refund id or payment id is required")
+	}
+	return uc.orderFactRepo.AddRefund(ctx, refundID, event.OrderID, event.AmountCents)
+}
+
+// PromotionReport returns the performance of every promotion that discounted
+// an order in the query range, highest attributed revenue first.
+func (uc *AnalyticsUseCase) PromotionReport(ctx context.Context, query AnalyticsQuery) ([]PromotionPerformance, error) {
+	filter, err := query.filter()
+	if err != nil {
+		return nil, err
+	}
+	totals, err := uc.attributionRepo.SumByPromotion(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	baselines := uc.baselines(query)
+	report := make([]PromotionPerformance, 0, len(totals))
+	for _, t := range totals {
+		baseline, err := baselines(ctx, t.SellerID)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, PromotionPerformance{
+			Kind:               t.Kind,
+			PromotionID:        t.PromotionID,
+			PromotionName:      t.PromotionName,
+			SellerID:           t.SellerID,
+			PerformanceMetrics: newPerformanceMetrics(t, baseline),
+		})
+	}
+	return report, nil
+}
+
+// SellerReport returns the combined performance of each seller's promotions
+// in the query range, highest attributed revenue first. Platform promotions
+// are reported under the PlatformCreator seller.
+func (uc *AnalyticsUseCase) SellerReport(ctx context.Context, query AnalyticsQuery) ([]SellerPerformance, error) {
+	filter, err := query.filter()
+	if err != nil {
+		return nil, err
+	}
+	totals, err := uc.attributionRepo.SumBySeller(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	baselines := uc.baselines(query)
+	report := make([]SellerPerformance, 0, len(totals))
+	for _, t := range totals {
+		baseline, err := baselines(ctx, t.SellerID)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, SellerPerformance{
+			SellerID:           t.SellerID,
+			Promotions:         t.Promotions,
+			PerformanceMetrics: newPerformanceMetrics(t, baseline),
+		})
+	}
+	return report, nil
+}
+
+// attributeFlashSales attributes an order to the flash sales it claimed units
+// from, at what the sales took off the list prices of the order's items.
+func (uc *AnalyticsUseCase) attributeFlashSales(ctx context.Context, event domain.OrderCreatedEvent) error {
+	claims, err := uc.claimRepo.ListActiveByOrder(ctx, event.OrderID)
+	if err != nil {
+		return err
+	}
+
+	discounts := make(map[string]int64)
+	var saleIDs []string
+	for _, claim := range claims {
+		item, err := uc.flashSaleItemRepo.GetByID(ctx, claim.FlashSaleItemID)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := discounts[claim.FlashSaleID]; !ok {
+			saleIDs = append(saleIDs, claim.FlashSaleID)
+		}
+		for _, line := range event.Items {
+			if line.ProductID != item.ProductID || (item.VariantID != "" && line.VariantID != item.VariantID) {
+				continue
+			}
+			discounts[claim.FlashSaleID] += line.FlashSaleDiscountCents
+			break
+		}
+	}
+
+	for _, saleID := range saleIDs {
+		flashSale, err := uc.flashSaleRepo.GetByID(ctx, saleID)
+		if err != nil {
+			return err
+		}
+		attribution := domain.NewPromotionAttribution(event.OrderID, domain.PromotionKindFlashSale, flashSale.ID, flashSale.Name, domain.PlatformCreator, discounts[saleID])
+		if err := uc.attributionRepo.Create(ctx, attribution); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attributeBundles attributes an order to the bundles applied to it.
+func (uc *AnalyticsUseCase) attributeBundles(ctx context.Context, event domain.OrderCreatedEvent) error {
+	for _, bundle := range event.Bundles {
+		attribution := domain.NewPromotionAttribution(event.OrderID, domain.PromotionKindBundle, bundle.BundleID, bundle.Name, bundle.SellerID, bundle.DiscountCents)
+		if err := uc.attributionRepo.Create(ctx, attribution); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filter checks the query range and returns the read model filter of the
+// query.
+func (q AnalyticsQuery) filter() (domain.AnalyticsFilter, error) {
+	if !q.To.After(q.From) || q.To.Sub(q.From) > maxAnalyticsRange {
+		return domain.AnalyticsFilter{}, ErrInvalidAnalyticsRange
+	}
+	return domain.AnalyticsFilter{
+		From:     q.From,
+		To:       q.To,
+		SellerID: q.SellerID,
+		Kind:     q.Kind,
+	}, nil
+}
+
+// baselines returns a function looking up the average value of the orders
+// in the query range that no promotion of a seller touched, once per
+// seller.
+func (uc *AnalyticsUseCase) baselines(query AnalyticsQuery) func(ctx context.Context, sellerID string) (int64, error) {
+	values := make(map[string]int64)
+	return func(ctx context.Context, sellerID string) (int64, error) {
+		if value, ok := values[sellerID]; ok {
+			return value, nil
+		}
+		value, err := uc.orderFactRepo.AverageUnpromotedValue(ctx, sellerID, query.From, query.To)
+		if err != nil {
+			return 0, err
+		}
+		values[sellerID] = value
+		return value, nil
+	}
+}
+
+// newPerformanceMetrics derives the reported figures from the totals of a
+// promotion or seller and the baseline order value.
+func newPerformanceMetrics(t *domain.PromotionTotals, baselineCents int64) PerformanceMetrics {
+	m := PerformanceMetrics{
+		Redemptions:             t.Redemptions,
+		CancelledRedemptions:    t.CancelledRedemptions,
+		DiscountCostCents:       t.DiscountCostCents,
+		AttributedRevenueCents:  t.AttributedRevenueCents,
+		RefundedCents:           t.RefundedCents,
+		NetRevenueCents:         t.AttributedRevenueCents - t.RefundedCents,
+		BaselineOrderValueCents: baselineCents,
+	}
+	if m.Redemptions > 0 {
+		m.AverageOrderValueCents = m.AttributedRevenueCents / int64(m.Redemptions)
+	}
+	if baselineCents > 0 && m.Redemptions > 0 {
+		m.AOVUpliftBasisPoints = (m.AverageOrderValueCents - baselineCents) * 10000 / baselineCents
+	}
+	return m
+}
@@ -0,0 +1,486 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
+)
+
+// CreateAutomaticPromotionInput represents the input for creating an automatic promotion.
+type CreateAutomaticPromotionInput struct {
+	Name        string
+	Description string
+	CreatedBy   string
+	Conditions  []domain.PromotionCondition
+	Actions     []domain.PromotionAction
+	Priority    int
+	Exclusive   bool
+	StartsAt    time.Time
+	EndsAt      time.Time
+}
+
+// EvaluatePromotionsInput represents a cart or order to evaluate automatic
+// promotions against.
+type EvaluatePromotionsInput struct {
+	UserID string
+	Lines  []domain.OrderLine
+}
+
+// AppliedPromotion is an automatic promotion whose conditions held, with the
+// discount it grants and the per-line allocation of that discount.
+type AppliedPromotion struct {
+	Promotion     *domain.AutomaticPromotion
+	DiscountCents int64
+	Allocations   []domain.LineDiscount
+	Gifts         []domain.GiftItem
+}
+
+// LineExplanation describes the promotions that discounted one order line,
+// e.g. "applied: Summer BOGO".
+type LineExplanation struct {
+	LineID        string
+	DiscountCents int64
+	Explanations  []string
+}
+
+// PromotionEvaluation is the outcome of evaluating automatic promotions.
+// Lines has one entry per input line, in input order.
+type PromotionEvaluation struct {
+	Applied            []AppliedPromotion
+	Lines              []LineExplanation
+	Gifts              []domain.GiftItem
+	TotalDiscountCents int64
+}
+
+// AutomaticPromotionUseCase handles rule-based automatic promotions.
+type AutomaticPromotionUseCase struct {
+	promotionRepo domain.AutomaticPromotionRepository
+}
+
+// NewAutomaticPromotionUseCase creates a new AutomaticPromotionUseCase instance.
+func NewAutomaticPromotionUseCase(promotionRepo domain.AutomaticPromotionRepository) *AutomaticPromotionUseCase {
+	return &AutomaticPromotionUseCase{promotionRepo: promotionRepo}
+}
+
+// CreatePromotion creates a new automatic promotion.
+func (uc *AutomaticPromotionUseCase) CreatePromotion(ctx context.Context, input CreateAutomaticPromotionInput) (*domain.AutomaticPromotion, error) {
+	if input.Name == "" {
+		return nil, errors.New("promotion name is required")
+	}
+	if input.CreatedBy == "" {
+		return nil, errors.New("created_by is required")
+	}
+	if input.EndsAt.IsZero() {
+		return nil, errors.New("ends_at is required")
+	}
+
+	startsAt := input.StartsAt
+	if startsAt.IsZero() {
+		startsAt = time.Now()
+	}
+	if !input.EndsAt.After(startsAt) {
+		return nil, errors.New("ends_at must be after starts_at")
+	}
+
+	promotion := domain.NewAutomaticPromotion(input.Name, input.CreatedBy, input.Conditions, input.Actions, startsAt, input.EndsAt)
+	promotion.Description = input.Description
+	promotion.Priority = input.Priority
+	promotion.Exclusive = input.Exclusive
+
+	if err := validatePromotionRules(promotion); err != nil {
+		return nil, err
+	}
+
+	if err := uc.promotionRepo.Create(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// GetPromotion retrieves an automatic promotion by ID.
+func (uc *AutomaticPromotionUseCase) GetPromotion(ctx context.Context, id string) (*domain.AutomaticPromotion, error) {
+	if id == "" {
+		return nil, errors.New("promotion id is required")
+	}
+	return uc.promotionRepo.GetByID(ctx, id)
+}
+
+// ListPromotions retrieves a paginated list of all automatic promotions.
+func (uc *AutomaticPromotionUseCase) ListPromotions(ctx context.Context, page, pageSize int) ([]*domain.AutomaticPromotion, int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return uc.promotionRepo.ListAll(ctx, page, pageSize)
+}
+
+// UpdatePromotion validates and persists changes to an automatic promotion.
+func (uc *AutomaticPromotionUseCase) UpdatePromotion(ctx context.Context, promotion *domain.AutomaticPromotion) error {
+	if !promotion.EndsAt.After(promotion.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if err := validatePromotionRules(promotion); err != nil {
+		return err
+	}
+	promotion.UpdatedAt = time.Now()
+	return uc.promotionRepo.Update(ctx, promotion)
+}
+
+// EvaluatePromotions applies the running automatic promotions to the lines
+// of a cart or order. The non-exclusive promotions stack, highest priority
+// first, each discounting what is left after the promotions before it. An
+// exclusive promotion applies on its own, so the best of the stacked
+// promotions and each exclusive promotion alone is chosen: whichever grants
+// the largest discount, the stack on a tie unless no stacked promotion
+// applies.
+func (uc *AutomaticPromotionUseCase) EvaluatePromotions(ctx context.Context, input EvaluatePromotionsInput) (*PromotionEvaluation, error) {
+	if len(input.Lines) == 0 {
+		return nil, errors.New("at least one order line is required")
+	}
+
+	promotions, err := uc.promotionRepo.ListActive(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var stacked, exclusive []*domain.AutomaticPromotion
+	for _, promotion := range promotions {
+		if promotion.Exclusive {
+			exclusive = append(exclusive, promotion)
+		} else {
+			stacked = append(stacked, promotion)
+		}
+	}
+
+	best := evaluatePromotions(stacked, input.Lines)
+	for _, promotion := range exclusive {
+		alone := evaluatePromotions([]*domain.AutomaticPromotion{promotion}, input.Lines)
+		if alone.TotalDiscountCents > best.TotalDiscountCents ||
+			(len(best.Applied) == 0 && len(alone.Applied) > 0) {
+			best = alone
+		}
+	}
+	return best, nil
+}
+
+// evaluatePromotions applies promotions to lines in order, each discounting
+// what is left after the promotions before it.
+func evaluatePromotions(promotions []*domain.AutomaticPromotion, lines []domain.OrderLine) *PromotionEvaluation {
+	result := &PromotionEvaluation{Lines: make([]LineExplanation, len(lines))}
+	for i, line := range lines {
+		result.Lines[i].LineID = line.LineID
+	}
+
+	st := newOrderState(lines, nil)
+	for _, promotion := range promotions {
+		if !conditionsHold(promotion, lines) {
+			continue
+		}
+
+		shares, gifts := applyPromotion(promotion, st)
+		applied := AppliedPromotion{Promotion: promotion, Gifts: gifts}
+		explanation := fmt.Sprintf("applied: %s", promotion.Name)
+		for i, share := range shares {
+			if share == 0 {
+				continue
+			}
+			line := lines[i]
+			applied.DiscountCents += share
+			applied.Allocations = append(applied.Allocations, domain.LineDiscount{
+				LineID:        line.LineID,
+				ProductID:     line.ProductID,
+				VariantID:     line.VariantID,
+				SellerID:      line.SellerID,
+				DiscountCents: share,
+			})
+			result.Lines[i].DiscountCents += share
+			result.Lines[i].Explanations = append(result.Lines[i].Explanations, explanation)
+		}
+		if applied.DiscountCents == 0 && len(applied.Gifts) == 0 {
+			continue
+		}
+
+		result.Applied = append(result.Applied, applied)
+		result.Gifts = append(result.Gifts, applied.Gifts...)
+		result.TotalDiscountCents += applied.DiscountCents
+	}
+	return result
+}
+
+// validatePromotionRules checks a promotion's conditions and actions and
+// fills in defaults (scope "all", a 100% buy-x-get-y discount, one gift).
+func validatePromotionRules(promotion *domain.AutomaticPromotion) error {
+	if len(promotion.Actions) == 0 {
+		return errors.New("at least one action is required")
+	}
+
+	for i := range promotion.Conditions {
+		cond := &promotion.Conditions[i]
+		if err := validateTarget(&cond.Target); err != nil {
+			return err
+		}
+		switch cond.Type {
+		case domain.PromotionConditionMinSubtotal:
+			if cond.MinSubtotalCents <= 0 {
+				return errors.New("min_subtotal condition requires min_subtotal_cents")
+			}
+		case domain.PromotionConditionMinQuantity:
+			if cond.MinQuantity <= 0 {
+				return errors.New("min_quantity condition requires min_quantity")
+			}
+		default:
+			return fmt.Errorf("invalid condition type %q", cond.Type)
+		}
+	}
+
+	for i := range promotion.Actions {
+		action := &promotion.Actions[i]
+		if err := validateTarget(&action.Target); err != nil {
+			return err
+		}
+		if action.MaxDiscountCents < 0 {
+			return errors.New("max_discount_cents cannot be negative")
+		}
+		switch action.Type {
+		case domain.PromotionActionPercentOff:
+			if action.PercentOff <= 0 || action.PercentOff > 10000 {
+				return errors.New("percent_off must be between 1 and 10000")
+			}
+		case domain.PromotionActionAmountOff:
+			if action.AmountOffCents <= 0 {
+				return errors.New("amount_off action requires amount_off_cents")
+			}
+		case domain.PromotionActionBuyXGetY:
+			if action.BuyQuantity <= 0 || action.GetQuantity <= 0 {
+				return errors.New("buy_x_get_y action requires buy_quantity and get_quantity")
+			}
+			if action.PercentOff == 0 {
+				action.PercentOff = 10000
+			}
+			if action.PercentOff < 0 || action.PercentOff > 10000 {
+				return errors.New("percent_off must be between 1 and 10000")
+			}
+		case domain.PromotionActionTieredQuantity:
+			if len(action.Tiers) == 0 {
+				return errors.New("tiered_quantity action requires tiers")
+			}
+			for _, tier := range action.Tiers {
+				if tier.MinQuantity <= 0 || tier.PercentOff <= 0 || tier.PercentOff > 10000 {
+					return errors.New("each tier requires min_quantity and a percent_off between 1 and 10000")
+				}
+			}
+			sort.Slice(action.Tiers, func(a, b int) bool {
+				return action.Tiers[a].MinQuantity < action.Tiers[b].MinQuantity
+			})
+		case domain.PromotionActionFreeGift:
+			if action.GiftProductID == "" {
+				return errors.New("free_gift action requires gift_product_id")
+			}
+			if action.GiftQuantity == 0 {
+				action.GiftQuantity = 1
+			}
+			if action.GiftQuantity < 0 {
+				return errors.New("gift_quantity cannot be negative")
+			}
+		default:
+			return fmt.Errorf("invalid action type %q", action.Type)
+		}
+	}
+
+	return nil
+}
+
+func validateTarget(target *domain.PromotionTarget) error {
+	switch target.Scope {
+	case "":
+		target.Scope = domain.CouponScopeAll
+	case domain.CouponScopeAll:
+	case domain.CouponScopeCategory, domain.CouponScopeProduct, domain.CouponScopeSeller:
+		if len(target.ScopeIDs) == 0 {
+			return errors.New("scope_ids are required for a scoped target")
+		}
+	default:
+		return errors.New("invalid target scope")
+	}
+	return nil
+}
+
+// targetedLines returns the indexes of the lines a promotion covers that are
+// selected by target.
+func targetedLines(promotion *domain.AutomaticPromotion, target domain.PromotionTarget, lines []domain.OrderLine) []int {
+	var idx []int
+	for i, line := range lines {
+		if promotion.Covers(line) && target.Matches(line) {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// conditionsHold reports whether every condition of the promotion is met by
+// the lines before any discounts.
+func conditionsHold(promotion *domain.AutomaticPromotion, lines []domain.OrderLine) bool {
+	for _, cond := range promotion.Conditions {
+		var subtotal int64
+		var quantity int
+		for _, i := range targetedLines(promotion, cond.Target, lines) {
+			subtotal += lines[i].TotalCents()
+			quantity += lines[i].Quantity
+		}
+
+		switch cond.Type {
+		case domain.PromotionConditionMinSubtotal:
+			if subtotal < cond.MinSubtotalCents {
+				return false
+			}
+		case domain.PromotionConditionMinQuantity:
+			if quantity < cond.MinQuantity {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// applyPromotion runs the promotion's actions against the order's remaining
+// amounts, deducting the discounts from the state. It returns the discount
+// on each line and the gifts granted.
+func applyPromotion(promotion *domain.AutomaticPromotion, st *orderState) ([]int64, []domain.GiftItem) {
+	shares := make([]int64, len(st.lines))
+	var gifts []domain.GiftItem
+
+	for _, action := range promotion.Actions {
+		eligible := targetedLines(promotion, action.Target, st.lines)
+		if len(eligible) == 0 {
+			continue
+		}
+
+		var actionShares []int64
+		switch action.Type {
+		case domain.PromotionActionPercentOff:
+			actionShares = percentOffShares(st, eligible, action.PercentOff)
+		case domain.PromotionActionAmountOff:
+			actionShares = amountOffShares(st, eligible, action.AmountOffCents)
+		case domain.PromotionActionTieredQuantity:
+			var quantity int
+			for _, i := range eligible {
+				quantity += st.lines[i].Quantity
+			}
+			var percent int64
+			for _, tier := range action.Tiers {
+				if quantity >= tier.MinQuantity {
+					percent = tier.PercentOff
+				}
+			}
+			actionShares = percentOffShares(st, eligible, percent)
+		case domain.PromotionActionBuyXGetY:
+			actionShares = buyXGetYShares(st, eligible, action)
+		case domain.PromotionActionFreeGift:
+			gift := domain.GiftItem{
+				PromotionID: promotion.ID,
+				ProductID:   action.GiftProductID,
+				VariantID:   action.GiftVariantID,
+				Quantity:    action.GiftQuantity,
+			}
+			if !promotion.IsPlatform() {
+				gift.SellerID = promotion.CreatedBy
+			}
+			gifts = append(gifts, gift)
+			continue
+		}
+
+		var total int64
+		for _, share := range actionShares {
+			total += share
+		}
+		if action.MaxDiscountCents > 0 && total > action.MaxDiscountCents {
+			actionShares = allocateProRata(action.MaxDiscountCents, actionShares)
+		}
+
+		for i, share := range actionShares {
+			st.remaining[i] -= share
+			shares[i] += share
+		}
+	}
+
+	return shares, gifts
+}
+
+// percentOffShares takes percent (percentage * 100) off the remaining amount
+// of the eligible lines, split pro rata.
+func percentOffShares(st *orderState, eligible []int, percent int64) []int64 {
+	var base int64
+	for _, i := range eligible {
+		base += st.remaining[i]
+	}
+	return amountOffShares(st, eligible, base*percent/10000)
+}
+
+// amountOffShares splits amount across the eligible lines pro rata to their
+// remaining amounts, never exceeding what is left on them.
+func amountOffShares(st *orderState, eligible []int, amount int64) []int64 {
+	shares := make([]int64, len(st.lines))
+	weights := make([]int64, len(eligible))
+	var base int64
+	for k, i := range eligible {
+		weights[k] = st.remaining[i]
+		base += st.remaining[i]
+	}
+	if amount > base {
+		amount = base
+	}
+	for k, share := range allocateProRata(amount, weights) {
+		shares[eligible[k]] = share
+	}
+	return shares
+}
+
+// buyXGetYShares discounts GetQuantity of every BuyQuantity + GetQuantity
+// eligible units by the action's percentage, starting with the cheapest units.
+func buyXGetYShares(st *orderState, eligible []int, action domain.PromotionAction) []int64 {
+	shares := make([]int64, len(st.lines))
+
+	var units int
+	for _, i := range eligible {
+		units += st.lines[i].Quantity
+	}
+	rewarded := units / (action.BuyQuantity + action.GetQuantity) * action.GetQuantity
+	if rewarded == 0 {
+		return shares
+	}
+
+	byPrice := append([]int(nil), eligible...)
+	sort.SliceStable(byPrice, func(a, b int) bool {
+		return st.lines[byPrice[a]].UnitPriceCents < st.lines[byPrice[b]].UnitPriceCents
+	})
+
+	for _, i := range byPrice {
+		if rewarded == 0 {
+			break
+		}
+		n := st.lines[i].Quantity
+		if n > rewarded {
+			n = rewarded
+		}
+		rewarded -= n
+
+		share := st.lines[i].UnitPriceCents * int64(n) * action.PercentOff / 10000
+		if share > st.remaining[i] {
+			share = st.remaining[i]
+		}
+		shares[i] = share
+	}
+	return shares
+}
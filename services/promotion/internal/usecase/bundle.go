@@ -3,52 +3,82 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
 )
 
-// CreateBundleInput represents the input for creating a bundle.
+// CreateBundleInput represents the input for creating a bundle. Items takes
+// precedence over ProductIDs, which adds one unit of each product.
 type CreateBundleInput struct {
 	Name             string
 	SellerID         string
 	ProductIDs       []string
+	Items            []domain.BundleItem
 	BundlePriceCents int64
 	SavingsCents     int64
 }
 
+// AppliedBundle is a bundle found complete in a cart or order, applied Sets
+// times, with its price split across the lines that fill it.
+type AppliedBundle struct {
+	Bundle         *domain.Bundle
+	Sets           int
+	ListPriceCents int64
+	PriceCents     int64
+	DiscountCents  int64
+	Allocations    []domain.BundleAllocation
+}
+
+// BundleApplication is the outcome of detecting bundles in a cart or order.
+type BundleApplication struct {
+	Applied            []AppliedBundle
+	TotalDiscountCents int64
+}
+
 // BundleUseCase handles bundle business logic.
 type BundleUseCase struct {
-	bundleRepo domain.BundleRepository
+	bundleRepo        domain.BundleRepository
+	productSellerRepo domain.ProductSellerRepository
+	catalog           domain.ProductCatalog
 }
 
-// NewBundleUseCase creates a new BundleUseCase instance.
-func NewBundleUseCase(bundleRepo domain.BundleRepository) *BundleUseCase {
-	return &BundleUseCase{bundleRepo: bundleRepo}
+// NewBundleUseCase creates a new BundleUseCase instance. Products missing
+// from the ownership read model are looked up in catalog.
+func NewBundleUseCase(bundleRepo domain.BundleRepository, productSellerRepo domain.ProductSellerRepository, catalog domain.ProductCatalog) *BundleUseCase {
+	return &BundleUseCase{
+		bundleRepo:        bundleRepo,
+		productSellerRepo: productSellerRepo,
+		catalog:           catalog,
+	}
 }
 
 // CreateBundle creates a new bundle.
 func (uc *BundleUseCase) CreateBundle(ctx context.Context, input CreateBundleInput) (*domain.Bundle, error) {
-	if input.Name == "" {
-		return nil, errors.New("bundle name is required")
-	}
 	if input.SellerID == "" {
 		return nil, errors.New("seller_id is required")
 	}
-	if len(input.ProductIDs) < 2 {
-		return nil, errors.New("at least two products are required for a bundle")
-	}
-	if input.BundlePriceCents <= 0 {
-		return nil, errors.New("bundle price must be greater than 0")
-	}
 
+	items := input.Items
+	if len(items) == 0 {
+		for _, productID := range input.ProductIDs {
+			items = append(items, domain.BundleItem{ProductID: productID, Quantity: 1})
+		}
+	}
 	bundle := domain.NewBundle(
 		input.Name,
 		input.SellerID,
-		input.ProductIDs,
+		items,
 		input.BundlePriceCents,
 		input.SavingsCents,
 	)
 
+	if err := uc.validateBundle(ctx, bundle); err != nil {
+		return nil, err
+	}
+
 	if err := uc.bundleRepo.Create(ctx, bundle); err != nil {
 		return nil, err
 	}
@@ -95,7 +125,270 @@ func (uc *BundleUseCase) ListActiveBundles(ctx context.Context, page, pageSize i
 	return uc.bundleRepo.ListActive(ctx, page, pageSize)
 }
 
-// UpdateBundle updates an existing bundle.
+// UpdateBundle updates an existing bundle after checking it as
+// CreateBundle does.
 func (uc *BundleUseCase) UpdateBundle(ctx context.Context, bundle *domain.Bundle) error {
+	if err := uc.validateBundle(ctx, bundle); err != nil {
+		return err
+	}
 	return uc.bundleRepo.Update(ctx, bundle)
 }
+
+// validateBundle checks a bundle's name, price and items and that the
+// products of an active bundle belong to its seller. Inactive bundles are
+// never applied, so bundles of deleted products can still be deactivated.
+func (uc *BundleUseCase) validateBundle(ctx context.Context, bundle *domain.Bundle) error {
+	if bundle.Name == "" {
+		return errors.New("bundle name is required")
+	}
+	if bundle.BundlePriceCents <= 0 {
+		return errors.New("bundle price must be greater than 0")
+	}
+
+	items, err := normalizeBundleItems(bundle.Items)
+	if err != nil {
+		return err
+	}
+	bundle.SetItems(items)
+
+	if !bundle.IsActive {
+		return nil
+	}
+	return uc.checkSellerOwnsProducts(ctx, bundle.SellerID, bundle.ProductIDs)
+}
+
+// ApplyBundles detects the active bundles completed by the lines of a cart
+// or order and prices them. Lines only fill bundles of their own seller, and
+// each unit is used by at most one bundle; the bundles saving the most are
+// applied first. The bundle price is split across the filling lines pro rata
+// to their list prices.
+func (uc *BundleUseCase) ApplyBundles(ctx context.Context, lines []domain.OrderLine) (*BundleApplication, error) {
+	if len(lines) == 0 {
+		return nil, errors.New("at least one order line is required")
+	}
+
+	var productIDs []string
+	for _, line := range lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+	bundles, err := uc.bundleRepo.ListActiveForProducts(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill bundles from the most expensive units first so the customer gets
+	// the larger saving.
+	order := make([]int, len(lines))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return lines[order[a]].UnitPriceCents > lines[order[b]].UnitPriceCents
+	})
+
+	remaining := make([]int, len(lines))
+	for i, line := range lines {
+		remaining[i] = line.Quantity
+	}
+
+	result := &BundleApplication{}
+	for {
+		var best *domain.Bundle
+		var bestSavings int64
+		for _, bundle := range bundles {
+			used, list := takeBundleSet(bundle, lines, order, remaining)
+			if used == nil {
+				continue
+			}
+			if savings := list - bundle.BundlePriceCents; savings > bestSavings {
+				best, bestSavings = bundle, savings
+			}
+		}
+		if best == nil {
+			break
+		}
+
+		units := make([]int, len(lines))
+		sets := 0
+		for {
+			used, list := takeBundleSet(best, lines, order, remaining)
+			if used == nil || list <= best.BundlePriceCents {
+				break
+			}
+			for i, n := range used {
+				remaining[i] -= n
+				units[i] += n
+			}
+			sets++
+		}
+
+		applied := priceBundle(best, sets, lines, units)
+		result.Applied = append(result.Applied, applied)
+		result.TotalDiscountCents += applied.DiscountCents
+	}
+
+	return result, nil
+}
+
+// RecordProductSeller records the seller of a product announced by the
+// product service.
+func (uc *BundleUseCase) RecordProductSeller(ctx context.Context, productID, sellerID string) error {
+	if productID == "" || sellerID == "" {
+		return errors.New("product id and seller id are required")
+	}
+	return uc.productSellerRepo.Upsert(ctx, &domain.ProductSeller{
+		ProductID: productID,
+		SellerID:  sellerID,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// ForgetProduct removes a deleted product from the ownership records.
+func (uc *BundleUseCase) ForgetProduct(ctx context.Context, productID string) error {
+	if productID == "" {
+		return errors.New("product id is required")
+	}
+	return uc.productSellerRepo.Delete(ctx, productID)
+}
+
+// checkSellerOwnsProducts verifies that every product belongs to the seller.
+// Products created before the read model was built are looked up in the
+// catalog and recorded.
+func (uc *BundleUseCase) checkSellerOwnsProducts(ctx context.Context, sellerID string, productIDs []string) error {
+	sellers, err := uc.productSellerRepo.GetSellers(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+	for _, productID := range productIDs {
+		owner, ok := sellers[productID]
+		if !ok {
+			if owner, err = uc.lookUpProductSeller(ctx, productID); err != nil {
+				return err
+			}
+		}
+		if owner != sellerID {
+			return fmt.Errorf("product %s does not belong to seller %s", productID, sellerID)
+		}
+	}
+	return nil
+}
+
+// lookUpProductSeller looks the seller of a product up in the catalog and
+// records it in the read model.
+func (uc *BundleUseCase) lookUpProductSeller(ctx context.Context, productID string) (string, error) {
+	sellerID, err := uc.catalog.GetProductSeller(ctx, productID)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return "", fmt.Errorf("product %s is not known", productID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up product %s: %w", productID, err)
+	}
+	if err := uc.RecordProductSeller(ctx, productID, sellerID); err != nil {
+		return "", err
+	}
+	return sellerID, nil
+}
+
+// normalizeBundleItems defaults quantities to one and checks that the items
+// are distinct and add up to at least two units.
+func normalizeBundleItems(items []domain.BundleItem) ([]domain.BundleItem, error) {
+	seen := make(map[domain.BundleItem]bool)
+	units := 0
+	out := make([]domain.BundleItem, 0, len(items))
+	for _, item := range items {
+		if item.ProductID == "" {
+			return nil, errors.New("bundle item product_id is required")
+		}
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+		if item.Quantity < 0 {
+			return nil, errors.New("bundle item quantity cannot be negative")
+		}
+
+		key := domain.BundleItem{ProductID: item.ProductID, VariantID: item.VariantID}
+		if seen[key] {
+			return nil, errors.New("duplicate bundle item")
+		}
+		seen[key] = true
+
+		units += item.Quantity
+		out = append(out, item)
+	}
+	if units < 2 {
+		return nil, errors.New("at least two products are required for a bundle")
+	}
+	return out, nil
+}
+
+// takeBundleSet finds the units needed for one set of the bundle among the
+// remaining quantities, visiting lines in order. It returns the units taken
+// from each line and their list price, or nil if the set cannot be filled.
+func takeBundleSet(bundle *domain.Bundle, lines []domain.OrderLine, order, remaining []int) ([]int, int64) {
+	used := make([]int, len(lines))
+	var list int64
+	for _, item := range bundle.Items {
+		need := item.Quantity
+		for _, i := range order {
+			if need == 0 {
+				break
+			}
+			line := lines[i]
+			if line.SellerID != bundle.SellerID || !item.Matches(line) {
+				continue
+			}
+			n := remaining[i] - used[i]
+			if n > need {
+				n = need
+			}
+			if n <= 0 {
+				continue
+			}
+			used[i] += n
+			need -= n
+			list += int64(n) * line.UnitPriceCents
+		}
+		if need > 0 {
+			return nil, 0
+		}
+	}
+	return used, list
+}
+
+// priceBundle splits the price of sets bundles across the units taken from
+// each line, pro rata to their list prices.
+func priceBundle(bundle *domain.Bundle, sets int, lines []domain.OrderLine, units []int) AppliedBundle {
+	applied := AppliedBundle{
+		Bundle:     bundle,
+		Sets:       sets,
+		PriceCents: int64(sets) * bundle.BundlePriceCents,
+	}
+
+	var idx []int
+	var weights []int64
+	for i, n := range units {
+		if n == 0 {
+			continue
+		}
+		list := int64(n) * lines[i].UnitPriceCents
+		idx = append(idx, i)
+		weights = append(weights, list)
+		applied.ListPriceCents += list
+	}
+	applied.DiscountCents = applied.ListPriceCents - applied.PriceCents
+
+	for k, price := range allocateProRata(applied.PriceCents, weights) {
+		line := lines[idx[k]]
+		applied.Allocations = append(applied.Allocations, domain.BundleAllocation{
+			LineID:         line.LineID,
+			ProductID:      line.ProductID,
+			VariantID:      line.VariantID,
+			SellerID:       line.SellerID,
+			Quantity:       units[idx[k]],
+			ListPriceCents: weights[k],
+			PriceCents:     price,
+			DiscountCents:  weights[k] - price,
+		})
+	}
+	return applied
+}
@@ -2,10 +2,15 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"math/big"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
 )
 
@@ -20,22 +25,98 @@ type CreateCouponInput struct {
 	PerUserLimit     int
 	Scope            string
 	ScopeIDs         []string
+	Stacking         string
+	Priority         int
 	CreatedBy        string
 	StartsAt         time.Time
 	ExpiresAt        time.Time
 }
 
-// ValidateCouponInput represents the input for validating a coupon.
+// ValidateCouponInput represents the input for validating a coupon. When
+// Lines is empty the coupon is evaluated against OrderCents as a single
+// line its scope is not checked against, as it was before coupons were
+// scoped.
 type ValidateCouponInput struct {
-	Code        string
-	UserID      string
-	OrderCents  int64
+	Code           string
+	UserID         string
+	OrderCents     int64
+	Lines          []domain.OrderLine
+	ShippingQuotes []domain.ShippingQuote
 }
 
+// ApplyCouponsInput represents the input for evaluating several coupons
+// against the lines of one order.
+type ApplyCouponsInput struct {
+	Codes          []string
+	UserID         string
+	Lines          []domain.OrderLine
+	ShippingQuotes []domain.ShippingQuote
+}
+
+// CouponDiscount is the discount one coupon grants on an order.
+type CouponDiscount struct {
+	// DiscountCents is the discount on item prices, split across the eligible
+	// lines in Allocations.
+	DiscountCents int64
+	Allocations   []domain.LineDiscount
+	// ShippingDiscountCents is the shipping waived by a free-shipping coupon.
+	ShippingDiscountCents int64
+	// ShippingQuotes are the order's shipping quotes after the coupon, with
+	// eligible quotes zeroed by free-shipping coupons.
+	ShippingQuotes []domain.ShippingQuote
+}
+
+// TotalCents returns the combined item and shipping discount.
+func (d *CouponDiscount) TotalCents() int64 {
+	return d.DiscountCents + d.ShippingDiscountCents
+}
+
+// AppliedCoupon is a coupon accepted by ApplyCoupons together with its
+// discount and the per-line allocation of that discount.
+type AppliedCoupon struct {
+	Coupon *domain.Coupon
+	CouponDiscount
+}
+
+// RejectedCoupon is a coupon code that could not be applied and why.
+type RejectedCoupon struct {
+	Code   string
+	Reason string
+}
+
+// ApplyCouponsResult is the outcome of evaluating a set of coupons.
+type ApplyCouponsResult struct {
+	Applied                    []AppliedCoupon
+	Rejected                   []RejectedCoupon
+	TotalDiscountCents         int64
+	TotalShippingDiscountCents int64
+	ShippingQuotes             []domain.ShippingQuote
+}
+
+// GenerateCodesInput represents the input for generating single-use campaign
+// codes. Every generated coupon copies the template fields except Code.
+type GenerateCodesInput struct {
+	Template   CreateCouponInput
+	Prefix     string
+	Count      int
+	CodeLength int
+}
+
+// Limits for bulk code generation.
+const (
+	maxGeneratedCodes     = 10000
+	defaultCodeLength     = 8
+	maxCodeGenerationRuns = 5
+)
+
+// codeAlphabet omits characters that are easily confused (0/O, 1/I/L).
+const codeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
 // CouponUseCase handles coupon business logic.
 type CouponUseCase struct {
 	couponRepo      domain.CouponRepository
 	couponUsageRepo domain.CouponUsageRepository
+	redemptionRepo  domain.CouponRedemptionRepository
 	publisher       domain.EventPublisher
 }
 
@@ -43,17 +124,33 @@ type CouponUseCase struct {
 func NewCouponUseCase(
 	couponRepo domain.CouponRepository,
 	couponUsageRepo domain.CouponUsageRepository,
+	redemptionRepo domain.CouponRedemptionRepository,
 	publisher domain.EventPublisher,
 ) *CouponUseCase {
 	return &CouponUseCase{
 		couponRepo:      couponRepo,
 		couponUsageRepo: couponUsageRepo,
+		redemptionRepo:  redemptionRepo,
 		publisher:       publisher,
 	}
 }
 
 // CreateCoupon creates a new coupon.
 func (uc *CouponUseCase) CreateCoupon(ctx context.Context, input CreateCouponInput) (*domain.Coupon, error) {
+	coupon, err := uc.buildCoupon(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.couponRepo.Create(ctx, coupon); err != nil {
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+// buildCoupon validates the input and builds an unsaved coupon from it.
+func (uc *CouponUseCase) buildCoupon(input CreateCouponInput) (*domain.Coupon, error) {
 	if input.Code == "" {
 		return nil, errors.New("coupon code is required")
 	}
@@ -87,16 +184,30 @@ func (uc *CouponUseCase) CreateCoupon(ctx context.Context, input CreateCouponInp
 	coupon.ExpiresAt = input.ExpiresAt
 
 	if input.Scope != "" {
+		switch domain.CouponScope(input.Scope) {
+		case domain.CouponScopeAll:
+		case domain.CouponScopeCategory, domain.CouponScopeProduct, domain.CouponScopeSeller:
+			if len(input.ScopeIDs) == 0 {
+				return nil, errors.New("scope_ids are required for a scoped coupon")
+			}
+		default:
+			return nil, errors.New("invalid coupon scope")
+		}
 		coupon.Scope = domain.CouponScope(input.Scope)
 	}
+	if input.Stacking != "" {
+		switch domain.CouponStacking(input.Stacking) {
+		case domain.CouponStackingExclusive, domain.CouponStackingWithPlatform, domain.CouponStackingWithSeller:
+			coupon.Stacking = domain.CouponStacking(input.Stacking)
+		default:
+			return nil, errors.New("invalid coupon stacking rule")
+		}
+	}
+	coupon.Priority = input.Priority
 	if len(input.ScopeIDs) > 0 {
 		coupon.ScopeIDs = input.ScopeIDs
 	}
 
-	if err := uc.couponRepo.Create(ctx, coupon); err != nil {
-		return nil, err
-	}
-
 	return coupon, nil
 }
 
@@ -139,102 +250,640 @@ func (uc *CouponUseCase) ListCouponsBySeller(ctx context.Context, sellerID strin
 	return uc.couponRepo.ListBySeller(ctx, sellerID, page, pageSize)
 }
 
-// ValidateCoupon checks if a coupon is valid for use.
-func (uc *CouponUseCase) ValidateCoupon(ctx context.Context, input ValidateCouponInput) (*domain.Coupon, int64, error) {
+// ValidateCoupon checks if a coupon is valid for use and computes its discount
+// over the order lines it applies to.
+func (uc *CouponUseCase) ValidateCoupon(ctx context.Context, input ValidateCouponInput) (*domain.Coupon, *CouponDiscount, error) {
 	if input.Code == "" {
-		return nil, 0, errors.New("coupon code is required")
+		return nil, nil, errors.New("coupon code is required")
 	}
 	if input.UserID == "" {
-		return nil, 0, errors.New("user_id is required")
+		return nil, nil, errors.New("user_id is required")
 	}
 
 	coupon, err := uc.couponRepo.GetByCode(ctx, strings.ToUpper(input.Code))
 	if err != nil {
-		return nil, 0, err
+		return nil, nil, err
+	}
+
+	if err := uc.checkUsable(ctx, coupon, input.UserID); err != nil {
+		return nil, nil, err
+	}
+
+	state := newOrderState(input.Lines, input.ShippingQuotes)
+	if len(input.Lines) == 0 {
+		state = newOrderState([]domain.OrderLine{{LineID: "order", Quantity: 1, UnitPriceCents: input.OrderCents}}, input.ShippingQuotes)
+		state.unscoped = true
+	}
+
+	discount, err := uc.applyCoupon(coupon, state)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return coupon, discount, nil
+}
+
+// ApplyCoupons evaluates several coupons against the lines of one order.
+// Coupons are applied in descending Priority order; a coupon is rejected if it
+// cannot be stacked with one already accepted. Each coupon only discounts the
+// lines it applies to (see Coupon.AppliesTo), and each discount is allocated
+// pro rata across those lines so that refunds can return the right share.
+func (uc *CouponUseCase) ApplyCoupons(ctx context.Context, input ApplyCouponsInput) (*ApplyCouponsResult, error) {
+	if input.UserID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	if len(input.Codes) == 0 {
+		return nil, errors.New("at least one coupon code is required")
+	}
+	if len(input.Lines) == 0 {
+		return nil, errors.New("at least one order line is required")
+	}
+
+	result := &ApplyCouponsResult{}
+	var candidates []*domain.Coupon
+	seen := make(map[string]bool)
+	for _, code := range input.Codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		coupon, err := uc.couponRepo.GetByCode(ctx, code)
+		if err != nil {
+			result.Rejected = append(result.Rejected, RejectedCoupon{Code: code, Reason: err.Error()})
+			continue
+		}
+		if err := uc.checkUsable(ctx, coupon, input.UserID); err != nil {
+			result.Rejected = append(result.Rejected, RejectedCoupon{Code: code, Reason: err.Error()})
+			continue
+		}
+		candidates = append(candidates, coupon)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Priority > candidates[j].Priority
+	})
+
+	state := newOrderState(input.Lines, input.ShippingQuotes)
+	var accepted []*domain.Coupon
+	for _, coupon := range candidates {
+		if conflict := firstConflict(coupon, accepted); conflict != nil {
+			result.Rejected = append(result.Rejected, RejectedCoupon{
+				Code:   coupon.Code,
+				Reason: fmt.Sprintf("cannot be combined with coupon %s", conflict.Code),
+			})
+			continue
+		}
+
+		discount, err := uc.applyCoupon(coupon, state)
+		if err != nil {
+			result.Rejected = append(result.Rejected, RejectedCoupon{Code: coupon.Code, Reason: err.Error()})
+			continue
+		}
+
+		accepted = append(accepted, coupon)
+		result.Applied = append(result.Applied, AppliedCoupon{Coupon: coupon, CouponDiscount: *discount})
+		result.TotalDiscountCents += discount.DiscountCents
+		result.TotalShippingDiscountCents += discount.ShippingDiscountCents
+	}
+	result.ShippingQuotes = state.quotes()
+
+	return result, nil
+}
+
+// RedeemCoupons applies and redeems several coupons for one order. Redemption
+// fails without recording any usage if one of the requested coupons is rejected
+// or a usage limit is reached concurrently.
+func (uc *CouponUseCase) RedeemCoupons(ctx context.Context, input ApplyCouponsInput, orderID string) ([]*domain.CouponUsage, error) {
+	return uc.redeemCoupons(ctx, input, orderID, domain.CouponUsageConfirmed)
+}
+
+// ReserveCoupons is like RedeemCoupons but only reserves the usages for the
+// order. They count towards coupon limits until the order is confirmed
+// (ConfirmOrderCoupons) or cancelled (ReleaseOrderCoupons).
+func (uc *CouponUseCase) ReserveCoupons(ctx context.Context, input ApplyCouponsInput, orderID string) ([]*domain.CouponUsage, error) {
+	return uc.redeemCoupons(ctx, input, orderID, domain.CouponUsageReserved)
+}
+
+func (uc *CouponUseCase) redeemCoupons(ctx context.Context, input ApplyCouponsInput, orderID string, status domain.CouponUsageStatus) ([]*domain.CouponUsage, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+
+	// A retried redemption returns the usages the order already holds
+	// instead of counting them against the coupons' limits again
+	existing, err := uc.activeOrderUsages(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return existing, nil
+	}
+
+	result, err := uc.ApplyCoupons(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Rejected) > 0 {
+		r := result.Rejected[0]
+		return nil, fmt.Errorf("coupon %s rejected: %s", r.Code, r.Reason)
+	}
+
+	usages := make([]*domain.CouponUsage, len(result.Applied))
+	for i, applied := range result.Applied {
+		usages[i] = domain.NewCouponUsage(applied.Coupon.ID, input.UserID, orderID, applied.TotalCents())
+		usages[i].Allocations = applied.Allocations
+		usages[i].Status = status
+	}
+
+	if err := uc.redemptionRepo.Reserve(ctx, usages); err != nil {
+		return nil, err
+	}
+
+	if status == domain.CouponUsageConfirmed {
+		for i, applied := range result.Applied {
+			uc.publishRedeemed(ctx, applied.Coupon, usages[i])
+		}
+	}
+
+	return usages, nil
+}
+
+// RedeemCoupon redeems a coupon for a user and order.
+func (uc *CouponUseCase) RedeemCoupon(ctx context.Context, input ValidateCouponInput, orderID string) (*domain.CouponUsage, error) {
+	return uc.redeemCoupon(ctx, input, orderID, domain.CouponUsageConfirmed)
+}
+
+// ReserveCoupon reserves a coupon for an order that has not been confirmed yet.
+func (uc *CouponUseCase) ReserveCoupon(ctx context.Context, input ValidateCouponInput, orderID string) (*domain.CouponUsage, error) {
+	return uc.redeemCoupon(ctx, input, orderID, domain.CouponUsageReserved)
+}
+
+func (uc *CouponUseCase) redeemCoupon(ctx context.Context, input ValidateCouponInput, orderID string, status domain.CouponUsageStatus) (*domain.CouponUsage, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+
+	// A retried redemption returns the usage the order already holds
+	existing, err := uc.activeOrderUsages(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		if coupon, err := uc.couponRepo.GetByCode(ctx, strings.ToUpper(input.Code)); err == nil {
+			for _, usage := range existing {
+				if usage.CouponID == coupon.ID {
+					return usage, nil
+				}
+			}
+		}
+	}
+
+	// Validate first
+	coupon, discount, err := uc.ValidateCoupon(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reserve the usage atomically against the coupon's limits
+	usage := domain.NewCouponUsage(coupon.ID, input.UserID, orderID, discount.TotalCents())
+	usage.Allocations = discount.Allocations
+	usage.Status = status
+	if err := uc.redemptionRepo.Reserve(ctx, []*domain.CouponUsage{usage}); err != nil {
+		return nil, err
+	}
+
+	if status == domain.CouponUsageConfirmed {
+		uc.publishRedeemed(ctx, coupon, usage)
+	}
+
+	return usage, nil
+}
+
+// activeOrderUsages returns the order's reserved and confirmed coupon
+// usages.
+func (uc *CouponUseCase) activeOrderUsages(ctx context.Context, orderID string) ([]*domain.CouponUsage, error) {
+	usages, err := uc.couponUsageRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	var active []*domain.CouponUsage
+	for _, usage := range usages {
+		if usage.Status != domain.CouponUsageReleased {
+			active = append(active, usage)
+		}
+	}
+	return active, nil
+}
+
+// ConfirmOrderCoupons confirms the coupon usages reserved for an order and
+// publishes a coupon.redeemed event for each of them.
+func (uc *CouponUseCase) ConfirmOrderCoupons(ctx context.Context, orderID string) ([]*domain.CouponUsage, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+
+	usages, err := uc.redemptionRepo.ConfirmByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, usage := range usages {
+		coupon, err := uc.couponRepo.GetByID(ctx, usage.CouponID)
+		if err != nil {
+			return nil, err
+		}
+		uc.publishRedeemed(ctx, coupon, usage)
+	}
+
+	return usages, nil
+}
+
+// ReleaseOrderCoupons returns the coupon usages of an order to their coupons,
+// e.g. when the order is cancelled.
+func (uc *CouponUseCase) ReleaseOrderCoupons(ctx context.Context, orderID string) ([]*domain.CouponUsage, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+
+	usages, err := uc.redemptionRepo.ReleaseByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.publishReleased(ctx, usages)
+	return usages, nil
+}
+
+// ReleaseSellerOrderCoupons returns the coupon usages of an order that only
+// discounted the lines of a cancelled seller order, such as the seller's own
+// coupons. Coupons that also discounted the rest of the order, and free
+// shipping, stay used until the whole order is cancelled.
+func (uc *CouponUseCase) ReleaseSellerOrderCoupons(ctx context.Context, orderID string, lineIDs []string) ([]*domain.CouponUsage, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+
+	cancelled := make(map[string]bool, len(lineIDs))
+	for _, lineID := range lineIDs {
+		cancelled[lineID] = true
+	}
+
+	usages, err := uc.activeOrderUsages(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, usage := range usages {
+		if len(usage.Allocations) == 0 {
+			continue
+		}
+		covered := true
+		for _, allocation := range usage.Allocations {
+			if allocation.DiscountCents > 0 && !cancelled[allocation.LineID] {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			ids = append(ids, usage.ID)
+		}
+	}
+
+	released, err := uc.redemptionRepo.ReleaseByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.publishReleased(ctx, released)
+	return released, nil
+}
+
+func (uc *CouponUseCase) publishReleased(ctx context.Context, usages []*domain.CouponUsage) {
+	for _, usage := range usages {
+		event := domain.CouponReleasedEvent{
+			CouponID:      usage.CouponID,
+			UserID:        usage.UserID,
+			OrderID:       usage.OrderID,
+			DiscountCents: usage.DiscountCents,
+		}
+		_ = uc.publisher.Publish(ctx, domain.EventCouponReleased, event)
+	}
+}
+
+// GenerateCouponCodes creates Count single-use coupons with unique random
+// codes sharing the template's discount rules. All coupons are tagged with a
+// new campaign ID which is returned alongside them.
+func (uc *CouponUseCase) GenerateCouponCodes(ctx context.Context, input GenerateCodesInput) (string, []*domain.Coupon, error) {
+	if input.Count <= 0 {
+		return "", nil, errors.New("count must be greater than 0")
+	}
+	if input.Count > maxGeneratedCodes {
+		return "", nil, fmt.Errorf("count must not exceed %d", maxGeneratedCodes)
+	}
+	if input.CodeLength <= 0 {
+		input.CodeLength = defaultCodeLength
+	}
+	if input.CodeLength < 6 {
+		return "", nil, errors.New("code length must be at least 6")
+	}
+
+	// Validate the template once by building a prototype coupon.
+	tmpl := input.Template
+	tmpl.Code = strings.ToUpper(input.Prefix) + strings.Repeat("X", input.CodeLength)
+	tmpl.UsageLimit = 1
+	tmpl.PerUserLimit = 1
+	proto, err := uc.buildCoupon(tmpl)
+	if err != nil {
+		return "", nil, err
+	}
+
+	campaignID := uuid.New().String()
+	var coupons []*domain.Coupon
+	taken := make(map[string]bool)
+
+	for run := 0; run < maxCodeGenerationRuns && len(coupons) < input.Count; run++ {
+		var codes []string
+		for len(codes) < input.Count-len(coupons) {
+			code, err := randomCode(input.CodeLength)
+			if err != nil {
+				return "", nil, err
+			}
+			code = strings.ToUpper(input.Prefix) + code
+			if taken[code] {
+				continue
+			}
+			taken[code] = true
+			codes = append(codes, code)
+		}
+
+		existing, err := uc.couponRepo.ExistingCodes(ctx, codes)
+		if err != nil {
+			return "", nil, err
+		}
+		exists := make(map[string]bool, len(existing))
+		for _, code := range existing {
+			exists[code] = true
+		}
+
+		var batch []*domain.Coupon
+		for _, code := range codes {
+			if exists[code] {
+				continue
+			}
+			c := *proto
+			c.ID = uuid.New().String()
+			c.Code = code
+			c.CampaignID = campaignID
+			batch = append(batch, &c)
+		}
+
+		if err := uc.couponRepo.CreateBatch(ctx, batch); err != nil {
+			return "", nil, err
+		}
+		coupons = append(coupons, batch...)
+	}
+
+	if len(coupons) < input.Count {
+		return campaignID, coupons, fmt.Errorf("only %d of %d unique codes could be generated", len(coupons), input.Count)
 	}
 
+	return campaignID, coupons, nil
+}
+
+// ListCampaignCoupons retrieves a paginated list of the codes generated for a campaign.
+func (uc *CouponUseCase) ListCampaignCoupons(ctx context.Context, campaignID string, page, pageSize int) ([]*domain.Coupon, int64, error) {
+	if campaignID == "" {
+		return nil, 0, errors.New("campaign id is required")
+	}
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 1000 {
+		pageSize = 1000
+	}
+	return uc.couponRepo.ListByCampaign(ctx, campaignID, page, pageSize)
+}
+
+// randomCode returns a random code of length n drawn from codeAlphabet.
+func randomCode(n int) (string, error) {
+	max := big.NewInt(int64(len(codeAlphabet)))
+	b := make([]byte, n)
+	for i := range b {
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = codeAlphabet[idx.Int64()]
+	}
+	return string(b), nil
+}
+
+// UpdateCoupon updates an existing coupon.
+func (uc *CouponUseCase) UpdateCoupon(ctx context.Context, coupon *domain.Coupon) error {
+	return uc.couponRepo.Update(ctx, coupon)
+}
+
+// checkUsable verifies that a coupon is active, within its validity window and
+// below its global and per-user usage limits.
+func (uc *CouponUseCase) checkUsable(ctx context.Context, coupon *domain.Coupon, userID string) error {
 	// Check if coupon is active
 	if !coupon.IsActive {
-		return nil, 0, errors.New("coupon is not active")
+		return errors.New("coupon is not active")
 	}
 
 	// Check if coupon has started
 	now := time.Now()
 	if now.Before(coupon.StartsAt) {
-		return nil, 0, errors.New("coupon is not yet active")
+		return errors.New("coupon is not yet active")
 	}
 
 	// Check if coupon has expired
 	if now.After(coupon.ExpiresAt) {
-		return nil, 0, errors.New("coupon has expired")
+		return errors.New("coupon has expired")
 	}
 
 	// Check usage limit
 	if coupon.UsageLimit > 0 && coupon.UsageCount >= coupon.UsageLimit {
-		return nil, 0, errors.New("coupon usage limit reached")
+		return domain.ErrCouponUsageLimitReached
 	}
 
 	// Check per-user limit
 	if coupon.PerUserLimit > 0 {
-		count, err := uc.couponUsageRepo.CountByUser(ctx, input.UserID, coupon.ID)
+		count, err := uc.couponUsageRepo.CountByUser(ctx, userID, coupon.ID)
 		if err != nil {
-			return nil, 0, err
+			return err
 		}
 		if count >= int64(coupon.PerUserLimit) {
-			return nil, 0, errors.New("per-user coupon usage limit reached")
+			return domain.ErrCouponPerUserLimitReached
 		}
 	}
 
-	// Check minimum order amount
-	if coupon.MinOrderCents > 0 && input.OrderCents < coupon.MinOrderCents {
-		return nil, 0, errors.New("order total does not meet minimum requirement")
+	return nil
+}
+
+// publishRedeemed publishes a coupon.redeemed event for a usage record.
+func (uc *CouponUseCase) publishRedeemed(ctx context.Context, coupon *domain.Coupon, usage *domain.CouponUsage) {
+	event := domain.CouponRedeemedEvent{
+		CouponID:      coupon.ID,
+		CouponCode:    coupon.Code,
+		UserID:        usage.UserID,
+		OrderID:       usage.OrderID,
+		DiscountCents: usage.DiscountCents,
+		CreatedBy:     coupon.CreatedBy,
 	}
+	_ = uc.publisher.Publish(ctx, domain.EventCouponRedeemed, event)
+}
 
-	// Calculate discount
-	discountCents := uc.calculateDiscount(coupon, input.OrderCents)
+// firstConflict returns the first accepted coupon that cannot be stacked with
+// coupon, or nil if it is compatible with all of them.
+func firstConflict(coupon *domain.Coupon, accepted []*domain.Coupon) *domain.Coupon {
+	for _, other := range accepted {
+		if !coupon.StacksWith(other) {
+			return other
+		}
+	}
+	return nil
+}
 
-	return coupon, discountCents, nil
+// orderState tracks what is left to discount on an order while coupons are
+// applied one after another.
+type orderState struct {
+	lines     []domain.OrderLine
+	remaining []int64 // each line's total after the coupons applied so far
+	shipping  []domain.ShippingQuote
+	// unscoped makes every line eligible for every coupon, for callers that
+	// only know the order amount.
+	unscoped bool
 }
 
-// RedeemCoupon redeems a coupon for a user and order.
-func (uc *CouponUseCase) RedeemCoupon(ctx context.Context, code, userID, orderID string, orderCents int64) (*domain.CouponUsage, error) {
-	// Validate first
-	coupon, discountCents, err := uc.ValidateCoupon(ctx, ValidateCouponInput{
-		Code:       code,
-		UserID:     userID,
-		OrderCents: orderCents,
-	})
-	if err != nil {
-		return nil, err
+func newOrderState(lines []domain.OrderLine, quotes []domain.ShippingQuote) *orderState {
+	st := &orderState{
+		lines:     lines,
+		remaining: make([]int64, len(lines)),
+		shipping:  append([]domain.ShippingQuote(nil), quotes...),
 	}
-
-	// Increment usage count
-	if err := uc.couponRepo.IncrementUsageCount(ctx, coupon.ID); err != nil {
-		return nil, err
+	for i, line := range lines {
+		st.remaining[i] = line.TotalCents()
 	}
+	return st
+}
 
-	// Create usage record
-	usage := domain.NewCouponUsage(coupon.ID, userID, orderID, discountCents)
-	if err := uc.couponUsageRepo.Create(ctx, usage); err != nil {
-		return nil, err
+func (st *orderState) quotes() []domain.ShippingQuote {
+	return append([]domain.ShippingQuote(nil), st.shipping...)
+}
+
+// applyCoupon computes the discount a coupon grants on the order's remaining
+// amounts and deducts it from the state. It returns an error describing why
+// the coupon does not apply, leaving the state untouched.
+func (uc *CouponUseCase) applyCoupon(coupon *domain.Coupon, st *orderState) (*CouponDiscount, error) {
+	var eligible []int
+	var eligibleSubtotal, base int64
+	sellers := make(map[string]bool)
+	for i, line := range st.lines {
+		if !st.unscoped && !coupon.AppliesTo(line) {
+			continue
+		}
+		eligible = append(eligible, i)
+		eligibleSubtotal += line.TotalCents()
+		base += st.remaining[i]
+		sellers[line.SellerID] = true
+	}
+	if len(eligible) == 0 {
+		return nil, errors.New("no eligible items in order")
 	}
 
-	// Publish coupon.redeemed event
-	event := domain.CouponRedeemedEvent{
-		CouponID:      coupon.ID,
-		CouponCode:    coupon.Code,
-		UserID:        userID,
-		OrderID:       orderID,
-		DiscountCents: discountCents,
+	// Check minimum order amount
+	if coupon.MinOrderCents > 0 && eligibleSubtotal < coupon.MinOrderCents {
+		return nil, errors.New("order total does not meet minimum requirement")
 	}
-	_ = uc.publisher.Publish(ctx, domain.EventCouponRedeemed, event)
 
-	return usage, nil
+	discount := &CouponDiscount{}
+
+	if coupon.Type == domain.CouponTypeFreeShipping {
+		// Zero the quotes covering eligible lines; order-level quotes (no
+		// SellerID) are eligible whenever any line is.
+		var waived []int
+		for i, q := range st.shipping {
+			if q.SellerID == "" || sellers[q.SellerID] {
+				waived = append(waived, i)
+				discount.ShippingDiscountCents += q.RateCents
+			}
+		}
+		if coupon.MaxDiscountCents > 0 && discount.ShippingDiscountCents > coupon.MaxDiscountCents {
+			discount.ShippingDiscountCents = coupon.MaxDiscountCents
+		}
+		weights := make([]int64, len(waived))
+		for i, idx := range waived {
+			weights[i] = st.shipping[idx].RateCents
+		}
+		for i, share := range allocateProRata(discount.ShippingDiscountCents, weights) {
+			st.shipping[waived[i]].RateCents -= share
+		}
+		discount.ShippingQuotes = st.quotes()
+		return discount, nil
+	}
+
+	discount.DiscountCents = uc.calculateDiscount(coupon, base)
+	weights := make([]int64, len(eligible))
+	for i, idx := range eligible {
+		weights[i] = st.remaining[idx]
+	}
+	for i, share := range allocateProRata(discount.DiscountCents, weights) {
+		idx := eligible[i]
+		st.remaining[idx] -= share
+		line := st.lines[idx]
+		discount.Allocations = append(discount.Allocations, domain.LineDiscount{
+			LineID:        line.LineID,
+			ProductID:     line.ProductID,
+			VariantID:     line.VariantID,
+			SellerID:      line.SellerID,
+			DiscountCents: share,
+		})
+	}
+	discount.ShippingQuotes = st.quotes()
+
+	return discount, nil
 }
 
-// UpdateCoupon updates an existing coupon.
-func (uc *CouponUseCase) UpdateCoupon(ctx context.Context, coupon *domain.Coupon) error {
-	return uc.couponRepo.Update(ctx, coupon)
+// allocateProRata splits total across weights proportionally using the
+// largest remainder method, so the shares always sum to total.
+func allocateProRata(total int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+	var sum int64
+	for _, w := range weights {
+		sum += w
+	}
+	if sum <= 0 || total <= 0 {
+		return shares
+	}
+
+	type remainder struct {
+		idx int
+		rem int64
+	}
+	// Shares are computed in big integers so that total*w cannot overflow
+	rems := make([]remainder, len(weights))
+	bigTotal, bigSum := big.NewInt(total), big.NewInt(sum)
+	var allocated int64
+	for i, w := range weights {
+		product := new(big.Int).Mul(bigTotal, big.NewInt(w))
+		share, rem := product.QuoRem(product, bigSum, new(big.Int))
+		shares[i] = share.Int64()
+		allocated += shares[i]
+		rems[i] = remainder{idx: i, rem: rem.Int64()}
+	}
+
+	sort.SliceStable(rems, func(i, j int) bool { return rems[i].rem > rems[j].rem })
+	for i := 0; allocated < total; i++ {
+		shares[rems[i%len(rems)].idx]++
+		allocated++
+	}
+	return shares
 }
 
 // calculateDiscount computes the discount amount based on coupon type.
@@ -248,7 +897,7 @@ func (uc *CouponUseCase) calculateDiscount(coupon *domain.Coupon, orderCents int
 	case domain.CouponTypeFixedAmount:
 		discount = coupon.DiscountValue
 	case domain.CouponTypeFreeShipping:
-		// Free shipping is handled externally; discount is 0 here
+		// Free shipping discounts shipping quotes, not items (see applyCoupon)
 		discount = 0
 	}
 
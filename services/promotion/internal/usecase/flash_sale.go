@@ -8,6 +8,10 @@ import (
 	"github.com/southern-martin/ecommerce/services/promotion/internal/domain"
 )
 
+// ErrFlashSaleNotLive is returned when claiming units of a sale that has not
+// started, has ended or was deactivated.
+var ErrFlashSaleNotLive = errors.New("flash sale is not live")
+
 // CreateFlashSaleInput represents the input for creating a flash sale.
 type CreateFlashSaleInput struct {
 	Name     string
@@ -22,12 +26,23 @@ type CreateFlashSaleItemInput struct {
 	VariantID      string
 	SalePriceCents int64
 	QuantityLimit  int
+	PerBuyerLimit  int
+}
+
+// ClaimFlashSaleItemInput represents a buyer claiming flash sale units for an order.
+type ClaimFlashSaleItemInput struct {
+	FlashSaleItemID string
+	BuyerID         string
+	OrderID         string
+	Quantity        int
 }
 
 // FlashSaleUseCase handles flash sale business logic.
 type FlashSaleUseCase struct {
 	flashSaleRepo     domain.FlashSaleRepository
 	flashSaleItemRepo domain.FlashSaleItemRepository
+	claimRepo         domain.FlashSaleClaimRepository
+	stock             domain.FlashSaleStock
 	publisher         domain.EventPublisher
 }
 
@@ -35,16 +50,21 @@ type FlashSaleUseCase struct {
 func NewFlashSaleUseCase(
 	flashSaleRepo domain.FlashSaleRepository,
 	flashSaleItemRepo domain.FlashSaleItemRepository,
+	claimRepo domain.FlashSaleClaimRepository,
+	stock domain.FlashSaleStock,
 	publisher domain.EventPublisher,
 ) *FlashSaleUseCase {
 	return &FlashSaleUseCase{
 		flashSaleRepo:     flashSaleRepo,
 		flashSaleItemRepo: flashSaleItemRepo,
+		claimRepo:         claimRepo,
+		stock:             stock,
 		publisher:         publisher,
 	}
 }
 
-// CreateFlashSale creates a new flash sale with items.
+// CreateFlashSale creates a new flash sale with items. The sale goes live
+// immediately if its window is already open; otherwise the scheduler starts it.
 func (uc *FlashSaleUseCase) CreateFlashSale(ctx context.Context, input CreateFlashSaleInput) (*domain.FlashSale, error) {
 	if input.Name == "" {
 		return nil, errors.New("flash sale name is required")
@@ -63,12 +83,19 @@ func (uc *FlashSaleUseCase) CreateFlashSale(ctx context.Context, input CreateFla
 
 	// Add items
 	for _, item := range input.Items {
+		if item.QuantityLimit < 0 || item.PerBuyerLimit < 0 {
+			return nil, errors.New("quantity limits cannot be negative")
+		}
+		if item.QuantityLimit > 0 && item.PerBuyerLimit > item.QuantityLimit {
+			return nil, errors.New("per_buyer_limit cannot exceed quantity_limit")
+		}
 		fsItem := domain.NewFlashSaleItem(
 			flashSale.ID,
 			item.ProductID,
 			item.VariantID,
 			item.SalePriceCents,
 			item.QuantityLimit,
+			item.PerBuyerLimit,
 		)
 		flashSale.Items = append(flashSale.Items, *fsItem)
 	}
@@ -77,25 +104,41 @@ func (uc *FlashSaleUseCase) CreateFlashSale(ctx context.Context, input CreateFla
 		return nil, err
 	}
 
-	// Publish flash_sale.started event if sale is currently active
-	now := time.Now()
-	if flashSale.IsActive && now.After(flashSale.StartsAt) && now.Before(flashSale.EndsAt) {
-		event := domain.FlashSaleEvent{
-			FlashSaleID: flashSale.ID,
-			Name:        flashSale.Name,
+	if flashSale.IsActive && isOpen(flashSale, time.Now()) {
+		if err := uc.startSale(ctx, flashSale); err != nil {
+			return nil, err
 		}
-		_ = uc.publisher.Publish(ctx, domain.EventFlashSaleStarted, event)
 	}
 
 	return flashSale, nil
 }
 
-// GetFlashSale retrieves a flash sale by ID.
+// GetFlashSale retrieves a flash sale by ID with live sold counts.
 func (uc *FlashSaleUseCase) GetFlashSale(ctx context.Context, id string) (*domain.FlashSale, error) {
 	if id == "" {
 		return nil, errors.New("flash sale id is required")
 	}
-	return uc.flashSaleRepo.GetByID(ctx, id)
+	flashSale, err := uc.flashSaleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.applyLiveCounts(ctx, flashSale); err != nil {
+		return nil, err
+	}
+	return flashSale, nil
+}
+
+// GetLiveFlashSale retrieves a flash sale for the storefront. Only live sales
+// are returned, with live sold counts.
+func (uc *FlashSaleUseCase) GetLiveFlashSale(ctx context.Context, id string) (*domain.FlashSale, error) {
+	flashSale, err := uc.GetFlashSale(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !isLive(flashSale, time.Now()) {
+		return nil, ErrFlashSaleNotLive
+	}
+	return flashSale, nil
 }
 
 // ListFlashSales retrieves a paginated list of all flash sales.
@@ -112,30 +155,316 @@ func (uc *FlashSaleUseCase) ListFlashSales(ctx context.Context, page, pageSize i
 	return uc.flashSaleRepo.ListAll(ctx, page, pageSize)
 }
 
-// ListActiveFlashSales retrieves all currently active flash sales.
+// ListActiveFlashSales retrieves all live flash sales with live sold counts.
 func (uc *FlashSaleUseCase) ListActiveFlashSales(ctx context.Context) ([]*domain.FlashSale, error) {
-	return uc.flashSaleRepo.ListActive(ctx)
+	flashSales, err := uc.flashSaleRepo.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, fs := range flashSales {
+		if err := uc.applyLiveCounts(ctx, fs); err != nil {
+			return nil, err
+		}
+	}
+	return flashSales, nil
 }
 
-// UpdateFlashSale updates an existing flash sale and publishes relevant events.
+// UpdateFlashSale updates an existing flash sale. Deactivating a live sale
+// ends it immediately; activating a sale whose window is open starts it.
 func (uc *FlashSaleUseCase) UpdateFlashSale(ctx context.Context, flashSale *domain.FlashSale) error {
 	if err := uc.flashSaleRepo.Update(ctx, flashSale); err != nil {
 		return err
 	}
 
-	event := domain.FlashSaleEvent{
-		FlashSaleID: flashSale.ID,
-		Name:        flashSale.Name,
+	switch {
+	case !flashSale.IsActive && flashSale.Status == domain.FlashSaleLive:
+		return uc.endSale(ctx, flashSale)
+	case flashSale.IsActive && flashSale.Status == domain.FlashSaleScheduled && isOpen(flashSale, time.Now()):
+		return uc.startSale(ctx, flashSale)
+	}
+	return nil
+}
+
+// ClaimFlashSaleItem atomically claims units of a live flash sale item for a
+// buyer's order, enforcing the item's quantity and per-buyer limits. An
+// order holds at most one claim per item: claiming the item again for the
+// same order, e.g. on a retried checkout, returns the claim it holds.
+func (uc *FlashSaleUseCase) ClaimFlashSaleItem(ctx context.Context, input ClaimFlashSaleItemInput) (*domain.FlashSaleClaim, *domain.FlashSaleItem, error) {
+	if input.FlashSaleItemID == "" {
+		return nil, nil, errors.New("flash_sale_item_id is required")
+	}
+	if input.BuyerID == "" {
+		return nil, nil, errors.New("buyer_id is required")
+	}
+	if input.OrderID == "" {
+		return nil, nil, errors.New("order_id is required")
+	}
+	if input.Quantity <= 0 {
+		return nil, nil, errors.New("quantity must be greater than 0")
+	}
+
+	item, err := uc.flashSaleItemRepo.GetByID(ctx, input.FlashSaleItemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	flashSale, err := uc.flashSaleRepo.GetByID(ctx, item.FlashSaleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !isLive(flashSale, time.Now()) {
+		return nil, nil, ErrFlashSaleNotLive
+	}
+
+	sold, err := uc.stock.Claim(ctx, item, input.BuyerID, input.OrderID, input.Quantity)
+	if errors.Is(err, domain.ErrFlashSaleStockNotSeeded) {
+		// The counters were evicted or never seeded; rebuild them from the
+		// claims table and try once more.
+		if err := uc.seedItem(ctx, flashSale, item); err != nil {
+			return nil, nil, err
+		}
+		sold, err = uc.stock.Claim(ctx, item, input.BuyerID, input.OrderID, input.Quantity)
+	}
+	if errors.Is(err, domain.ErrFlashSaleClaimHeld) {
+		return uc.heldClaim(ctx, item, input.OrderID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claim := domain.NewFlashSaleClaim(item, input.BuyerID, input.OrderID, input.Quantity)
+	if err := uc.claimRepo.Create(ctx, claim); err != nil {
+		_ = uc.stock.Release(ctx, item.ID, input.BuyerID, input.OrderID, input.Quantity)
+		return nil, nil, err
+	}
+
+	item.SoldCount = sold
+	return claim, item, nil
+}
+
+// heldClaim returns the active claim an order holds on an item.
+func (uc *FlashSaleUseCase) heldClaim(ctx context.Context, item *domain.FlashSaleItem, orderID string) (*domain.FlashSaleClaim, *domain.FlashSaleItem, error) {
+	claims, err := uc.claimRepo.ListActiveByOrder(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, claim := range claims {
+		if claim.FlashSaleItemID == item.ID {
+			return claim, item, nil
+		}
+	}
+	// The claim is being recorded by a concurrent request
+	return nil, nil, domain.ErrFlashSaleClaimHeld
+}
+
+// ReleaseOrderClaims returns the units claimed by an order, e.g. because the
+// order was cancelled. Claims that were already released are skipped.
+func (uc *FlashSaleUseCase) ReleaseOrderClaims(ctx context.Context, orderID string) ([]*domain.FlashSaleClaim, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+
+	claims, err := uc.claimRepo.ListActiveByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.releaseClaims(ctx, claims)
+}
+
+// ReleaseSellerOrderClaims returns the units an order claimed for the items
+// of one of its seller orders, because that seller order was cancelled.
+// Claims are matched to the items the same way checkout looks flash sales
+// up, by product and variant.
+func (uc *FlashSaleUseCase) ReleaseSellerOrderClaims(ctx context.Context, orderID string, items []domain.OrderItemEvent) ([]*domain.FlashSaleClaim, error) {
+	if orderID == "" {
+		return nil, errors.New("order_id is required")
+	}
+
+	claims, err := uc.claimRepo.ListActiveByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var cancelled []*domain.FlashSaleClaim
+	for _, claim := range claims {
+		saleItem, err := uc.flashSaleItemRepo.GetByID(ctx, claim.FlashSaleItemID)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.ProductID == saleItem.ProductID && (item.VariantID == "" || item.VariantID == saleItem.VariantID) {
+				cancelled = append(cancelled, claim)
+				break
+			}
+		}
+	}
+	return uc.releaseClaims(ctx, cancelled)
+}
+
+// releaseClaims returns the units of claims. Claims that were already
+// released are skipped.
+func (uc *FlashSaleUseCase) releaseClaims(ctx context.Context, claims []*domain.FlashSaleClaim) ([]*domain.FlashSaleClaim, error) {
+	var released []*domain.FlashSaleClaim
+	for _, claim := range claims {
+		ok, err := uc.claimRepo.Release(ctx, claim.ID)
+		if err != nil {
+			return released, err
+		}
+		if !ok {
+			continue
+		}
+		if err := uc.stock.Release(ctx, claim.FlashSaleItemID, claim.BuyerID, claim.OrderID, claim.Quantity); err != nil {
+			return released, err
+		}
+		claim.Status = domain.FlashSaleClaimReleased
+		released = append(released, claim)
+	}
+	return released, nil
+}
+
+// RunSchedule starts scheduled sales whose window has opened, ends live sales
+// that expired or were deactivated, and writes the live sold counts of the
+// remaining live sales back to the database.
+func (uc *FlashSaleUseCase) RunSchedule(ctx context.Context, now time.Time) error {
+	var errs []error
+
+	due, err := uc.flashSaleRepo.ListDueToStart(ctx, now)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, fs := range due {
+		if err := uc.startSale(ctx, fs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	expired, err := uc.flashSaleRepo.ListDueToEnd(ctx, now)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, fs := range expired {
+		if err := uc.endSale(ctx, fs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	live, err := uc.flashSaleRepo.ListActive(ctx)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, fs := range live {
+		if err := uc.reconcile(ctx, fs); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// startSale moves a scheduled sale to live, seeds its counters and publishes
+// flash_sale.started. Only the caller that wins the transition publishes.
+func (uc *FlashSaleUseCase) startSale(ctx context.Context, flashSale *domain.FlashSale) error {
+	ok, err := uc.flashSaleRepo.TransitionStatus(ctx, flashSale.ID, domain.FlashSaleScheduled, domain.FlashSaleLive)
+	if err != nil || !ok {
+		return err
+	}
+	flashSale.Status = domain.FlashSaleLive
+
+	for i := range flashSale.Items {
+		if err := uc.seedItem(ctx, flashSale, &flashSale.Items[i]); err != nil {
+			return err
+		}
+	}
+
+	_ = uc.publisher.Publish(ctx, domain.EventFlashSaleStarted, newFlashSaleEvent(flashSale))
+	return nil
+}
+
+// endSale moves a live sale to ended, records its final sold counts and
+// publishes flash_sale.ended. Only the caller that wins the transition publishes.
+func (uc *FlashSaleUseCase) endSale(ctx context.Context, flashSale *domain.FlashSale) error {
+	ok, err := uc.flashSaleRepo.TransitionStatus(ctx, flashSale.ID, domain.FlashSaleLive, domain.FlashSaleEnded)
+	if err != nil || !ok {
+		return err
+	}
+	flashSale.Status = domain.FlashSaleEnded
+
+	if err := uc.reconcile(ctx, flashSale); err != nil {
+		return err
 	}
 
-	if !flashSale.IsActive {
-		_ = uc.publisher.Publish(ctx, domain.EventFlashSaleEnded, event)
-	} else {
-		now := time.Now()
-		if now.After(flashSale.StartsAt) && now.Before(flashSale.EndsAt) {
-			_ = uc.publisher.Publish(ctx, domain.EventFlashSaleStarted, event)
+	_ = uc.publisher.Publish(ctx, domain.EventFlashSaleEnded, newFlashSaleEvent(flashSale))
+	return nil
+}
+
+// seedItem initialises an item's live counters from its active claims.
+func (uc *FlashSaleUseCase) seedItem(ctx context.Context, flashSale *domain.FlashSale, item *domain.FlashSaleItem) error {
+	claims, err := uc.claimRepo.ListActiveByItem(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+	return uc.stock.Seed(ctx, item.ID, claims, flashSale.EndsAt)
+}
+
+// reconcile copies the live sold counts of a sale's items to the database.
+func (uc *FlashSaleUseCase) reconcile(ctx context.Context, flashSale *domain.FlashSale) error {
+	persisted := make(map[string]int, len(flashSale.Items))
+	for _, item := range flashSale.Items {
+		persisted[item.ID] = item.SoldCount
+	}
+
+	if err := uc.applyLiveCounts(ctx, flashSale); err != nil {
+		return err
+	}
+
+	for _, item := range flashSale.Items {
+		if item.SoldCount == persisted[item.ID] {
+			continue
+		}
+		if err := uc.flashSaleItemRepo.SetSoldCount(ctx, item.ID, item.SoldCount); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
+// applyLiveCounts overwrites the sold counts of a sale's items with the live
+// counters where they exist.
+func (uc *FlashSaleUseCase) applyLiveCounts(ctx context.Context, flashSale *domain.FlashSale) error {
+	if len(flashSale.Items) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(flashSale.Items))
+	for i, item := range flashSale.Items {
+		ids[i] = item.ID
+	}
+
+	counts, err := uc.stock.SoldCounts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range flashSale.Items {
+		if sold, ok := counts[flashSale.Items[i].ID]; ok {
+			flashSale.Items[i].SoldCount = sold
+		}
+	}
 	return nil
 }
+
+func isOpen(flashSale *domain.FlashSale, now time.Time) bool {
+	return !now.Before(flashSale.StartsAt) && now.Before(flashSale.EndsAt)
+}
+
+func isLive(flashSale *domain.FlashSale, now time.Time) bool {
+	return flashSale.IsActive && flashSale.Status == domain.FlashSaleLive && isOpen(flashSale, now)
+}
+
+func newFlashSaleEvent(flashSale *domain.FlashSale) domain.FlashSaleEvent {
+	return domain.FlashSaleEvent{
+		FlashSaleID: flashSale.ID,
+		Name:        flashSale.Name,
+		StartsAt:    flashSale.StartsAt,
+		EndsAt:      flashSale.EndsAt,
+	}
+}
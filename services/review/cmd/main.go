@@ -20,6 +20,9 @@ import (
 	"github.com/southern-martin/ecommerce/services/review/internal/infrastructure/config"
 	"github.com/southern-martin/ecommerce/services/review/internal/infrastructure/database"
 	natsInfra "github.com/southern-martin/ecommerce/services/review/internal/infrastructure/nats"
+	"github.com/southern-martin/ecommerce/services/review/internal/infrastructure/order"
+	"github.com/southern-martin/ecommerce/services/review/internal/infrastructure/payment"
+	"github.com/southern-martin/ecommerce/services/review/internal/infrastructure/product"
 	"github.com/southern-martin/ecommerce/services/review/internal/usecase"
 )
 
@@ -38,6 +41,11 @@ func main() {
 	// AutoMigrate
 	if err := db.AutoMigrate(
 		&postgres.ReviewModel{},
+		&postgres.QuestionModel{},
+		&postgres.AnswerModel{},
+		&postgres.QAVoteModel{},
+		&postgres.PurchaseModel{},
+		&postgres.OrderPaymentModel{},
 	); err != nil {
 		log.Fatal().Err(err).Msg("failed to auto-migrate")
 	}
@@ -51,12 +59,43 @@ func main() {
 
 	// Initialize repositories
 	reviewRepo := postgres.NewReviewRepo(db)
+	questionRepo := postgres.NewQuestionRepo(db)
+	answerRepo := postgres.NewAnswerRepo(db)
+	voteRepo := postgres.NewVoteRepo(db)
+	purchaseRepo := postgres.NewPurchaseRepo(db)
 
 	// Initialize use cases
 	reviewUC := usecase.NewReviewUseCase(reviewRepo, publisher)
+	questionUC := usecase.NewQuestionUseCase(questionRepo, answerRepo, voteRepo, purchaseRepo, product.NewClient(cfg.ProductServiceURL), publisher)
+
+	// Order history is read from the order and payment services only when
+	// backfilling purchases
+	orderClient, err := order.NewClient(cfg.OrderGRPCAddr)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create order client")
+	}
+	defer orderClient.Close()
+	paymentClient, err := payment.NewClient(cfg.PaymentGRPCAddr)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create payment client")
+	}
+	defer paymentClient.Close()
+	purchaseUC := usecase.NewPurchaseUseCase(purchaseRepo, orderClient, paymentClient)
+
+	// "review backfill-purchases" records the purchases of orders paid for
+	// before the purchase history was kept
+	if len(os.Args) > 1 && os.Args[1] == "backfill-purchases" {
+		runBackfillPurchases(purchaseUC)
+		return
+	}
+
+	// Record order history for verified buyer badges
+	if err := natsInfra.StartPurchaseSubscriber(publisher, purchaseUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to subscribe to order events")
+	}
 
 	// Initialize HTTP handler and router
-	handler := httpAdapter.NewHandler(reviewUC)
+	handler := httpAdapter.NewHandler(reviewUC, questionUC)
 	router := httpAdapter.NewRouter(handler)
 
 	// Start HTTP server
@@ -107,6 +146,24 @@ func main() {
 	log.Info().Msg("review service stopped")
 }
 
+// runBackfillPurchases backfills the purchase history behind verified buyer
+// badges from the order and payment services.
+func runBackfillPurchases(purchaseUC *usecase.PurchaseUseCase) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	orders, err := purchaseUC.Backfill(ctx)
+	if err != nil {
+		log.Error().Err(err).Int("orders", orders).Msg("purchase backfill failed")
+		os.Exit(1)
+	}
+	log.Info().
+		Int("orders", orders).
+		Dur("took", time.Since(start)).
+		Msg("purchase backfill complete")
+}
+
 func setupLogger(level string) {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 
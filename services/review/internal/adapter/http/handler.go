@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -11,12 +12,13 @@ import (
 
 // Handler holds all HTTP handlers for the review service.
 type Handler struct {
-	reviewUC *usecase.ReviewUseCase
+	reviewUC   *usecase.ReviewUseCase
+	questionUC *usecase.QuestionUseCase
 }
 
 // NewHandler creates a new Handler.
-func NewHandler(reviewUC *usecase.ReviewUseCase) *Handler {
-	return &Handler{reviewUC: reviewUC}
+func NewHandler(reviewUC *usecase.ReviewUseCase, questionUC *usecase.QuestionUseCase) *Handler {
+	return &Handler{reviewUC: reviewUC, questionUC: questionUC}
 }
 
 // Health returns a health check response.
@@ -178,3 +180,203 @@ func (h *Handler) ApproveReview(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"review": review})
 }
+
+// --- Q&A Handlers ---
+
+type askQuestionRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	UserName  string `json:"user_name"`
+	Content   string `json:"content" binding:"required"`
+}
+
+func (h *Handler) AskQuestion(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user ID"})
+		return
+	}
+
+	var req askQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	question, err := h.questionUC.AskQuestion(c.Request.Context(), usecase.AskQuestionRequest{
+		ProductID: req.ProductID,
+		UserID:    userID,
+		UserName:  req.UserName,
+		Content:   req.Content,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"question": question})
+}
+
+func (h *Handler) ListProductQuestions(c *gin.Context) {
+	productID := c.Param("product_id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	filter := domain.QuestionFilter{
+		ProductID:    productID,
+		Status:       domain.QAStatus(c.DefaultQuery("status", string(domain.QAStatusApproved))),
+		AnsweredOnly: c.Query("answered") == "true",
+		SortBy:       c.DefaultQuery("sort_by", "created_at"),
+		SortOrder:    c.DefaultQuery("sort_order", "desc"),
+		Page:         page,
+		PageSize:     pageSize,
+	}
+
+	questions, total, err := h.questionUC.ListProductQuestions(c.Request.Context(), productID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"questions": questions,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+func (h *Handler) GetQuestion(c *gin.Context) {
+	question, err := h.questionUC.GetQuestion(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "question not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"question": question})
+}
+
+func (h *Handler) DeleteQuestion(c *gin.Context) {
+	if err := h.questionUC.DeleteQuestion(c.Request.Context(), c.Param("id"), c.GetHeader("X-User-ID")); err != nil {
+		c.JSON(qaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "question deleted"})
+}
+
+type answerQuestionRequest struct {
+	UserName string `json:"user_name"`
+	Content  string `json:"content" binding:"required"`
+}
+
+func (h *Handler) AnswerQuestion(c *gin.Context) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user ID"})
+		return
+	}
+
+	var req answerQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	answer, err := h.questionUC.AnswerQuestion(c.Request.Context(), usecase.AnswerQuestionRequest{
+		QuestionID: c.Param("id"),
+		UserID:     userID,
+		UserName:   req.UserName,
+		Content:    req.Content,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"answer": answer})
+}
+
+func (h *Handler) DeleteAnswer(c *gin.Context) {
+	if err := h.questionUC.DeleteAnswer(c.Request.Context(), c.Param("id"), c.GetHeader("X-User-ID")); err != nil {
+		c.JSON(qaErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "answer deleted"})
+}
+
+func (h *Handler) UpvoteQuestion(c *gin.Context) {
+	h.vote(c, domain.VoteTargetQuestion, true)
+}
+
+func (h *Handler) RemoveQuestionUpvote(c *gin.Context) {
+	h.vote(c, domain.VoteTargetQuestion, false)
+}
+
+func (h *Handler) UpvoteAnswer(c *gin.Context) {
+	h.vote(c, domain.VoteTargetAnswer, true)
+}
+
+func (h *Handler) RemoveAnswerUpvote(c *gin.Context) {
+	h.vote(c, domain.VoteTargetAnswer, false)
+}
+
+// vote adds or withdraws the user's upvote of a question or answer.
+func (h *Handler) vote(c *gin.Context, targetType string, upvote bool) {
+	userID := c.GetHeader("X-User-ID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user ID"})
+		return
+	}
+
+	var err error
+	if upvote {
+		err = h.questionUC.Upvote(c.Request.Context(), targetType, c.Param("id"), userID)
+	} else {
+		err = h.questionUC.RemoveUpvote(c.Request.Context(), targetType, c.Param("id"), userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"upvoted": upvote})
+}
+
+func (h *Handler) ApproveQuestion(c *gin.Context) {
+	h.moderateQuestion(c, domain.QAStatusApproved)
+}
+
+func (h *Handler) RejectQuestion(c *gin.Context) {
+	h.moderateQuestion(c, domain.QAStatusRejected)
+}
+
+func (h *Handler) moderateQuestion(c *gin.Context, status domain.QAStatus) {
+	question, err := h.questionUC.ModerateQuestion(c.Request.Context(), c.Param("id"), status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"question": question})
+}
+
+func (h *Handler) ApproveAnswer(c *gin.Context) {
+	h.moderateAnswer(c, domain.QAStatusApproved)
+}
+
+func (h *Handler) RejectAnswer(c *gin.Context) {
+	h.moderateAnswer(c, domain.QAStatusRejected)
+}
+
+func (h *Handler) moderateAnswer(c *gin.Context, status domain.QAStatus) {
+	answer, err := h.questionUC.ModerateAnswer(c.Request.Context(), c.Param("id"), status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"answer": answer})
+}
+
+// qaErrorStatus maps a Q&A error to its HTTP status.
+func qaErrorStatus(err error) int {
+	if errors.Is(err, usecase.ErrNotAuthor) {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
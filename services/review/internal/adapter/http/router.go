@@ -30,10 +30,32 @@ func NewRouter(handler *Handler) *gin.Engine {
 		// Product review summary
 		v1.GET("/products/:product_id/reviews/summary", handler.GetProductSummary)
 
+		// Product Q&A
+		v1.GET("/products/:product_id/questions", handler.ListProductQuestions)
+		questions := v1.Group("/questions")
+		{
+			questions.POST("", handler.AskQuestion)
+			questions.GET("/:id", handler.GetQuestion)
+			questions.DELETE("/:id", handler.DeleteQuestion)
+			questions.POST("/:id/answers", handler.AnswerQuestion)
+			questions.POST("/:id/upvote", handler.UpvoteQuestion)
+			questions.DELETE("/:id/upvote", handler.RemoveQuestionUpvote)
+		}
+		answers := v1.Group("/answers")
+		{
+			answers.DELETE("/:id", handler.DeleteAnswer)
+			answers.POST("/:id/upvote", handler.UpvoteAnswer)
+			answers.DELETE("/:id/upvote", handler.RemoveAnswerUpvote)
+		}
+
 		// Admin routes
 		admin := v1.Group("/admin")
 		{
 			admin.PATCH("/reviews/:id/approve", handler.ApproveReview)
+			admin.PATCH("/questions/:id/approve", handler.ApproveQuestion)
+			admin.PATCH("/questions/:id/reject", handler.RejectQuestion)
+			admin.PATCH("/answers/:id/approve", handler.ApproveAnswer)
+			admin.PATCH("/answers/:id/reject", handler.RejectAnswer)
 		}
 	}
 
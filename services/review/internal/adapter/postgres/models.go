@@ -73,3 +73,146 @@ func ToReviewModel(r *domain.Review) *ReviewModel {
 		UpdatedAt:          r.UpdatedAt,
 	}
 }
+
+// QuestionModel is the GORM model for the product_questions table.
+type QuestionModel struct {
+	ID          string    `gorm:"type:uuid;primaryKey"`
+	ProductID   string    `gorm:"type:uuid;index;not null"`
+	SellerID    string    `gorm:"type:uuid;index"`
+	UserID      string    `gorm:"type:uuid;index;not null"`
+	UserName    string    `gorm:"type:varchar(255);not null"`
+	Content     string    `gorm:"type:text;not null"`
+	Status      string    `gorm:"type:varchar(20);default:'pending';index"`
+	AnswerCount int       `gorm:"default:0"`
+	UpvoteCount int       `gorm:"default:0"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for QuestionModel.
+func (QuestionModel) TableName() string { return "product_questions" }
+
+// ToDomain converts a QuestionModel to a domain Question.
+func (m *QuestionModel) ToDomain() *domain.Question {
+	return &domain.Question{
+		ID:          m.ID,
+		ProductID:   m.ProductID,
+		SellerID:    m.SellerID,
+		UserID:      m.UserID,
+		UserName:    m.UserName,
+		Content:     m.Content,
+		Status:      domain.QAStatus(m.Status),
+		AnswerCount: m.AnswerCount,
+		UpvoteCount: m.UpvoteCount,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+}
+
+// ToQuestionModel converts a domain Question to a QuestionModel.
+func ToQuestionModel(q *domain.Question) *QuestionModel {
+	return &QuestionModel{
+		ID:          q.ID,
+		ProductID:   q.ProductID,
+		SellerID:    q.SellerID,
+		UserID:      q.UserID,
+		UserName:    q.UserName,
+		Content:     q.Content,
+		Status:      string(q.Status),
+		AnswerCount: q.AnswerCount,
+		UpvoteCount: q.UpvoteCount,
+		CreatedAt:   q.CreatedAt,
+		UpdatedAt:   q.UpdatedAt,
+	}
+}
+
+// AnswerModel is the GORM model for the product_answers table.
+type AnswerModel struct {
+	ID              string    `gorm:"type:uuid;primaryKey"`
+	QuestionID      string    `gorm:"type:uuid;index;not null"`
+	ProductID       string    `gorm:"type:uuid;index;not null"`
+	UserID          string    `gorm:"type:uuid;index;not null"`
+	UserName        string    `gorm:"type:varchar(255);not null"`
+	Content         string    `gorm:"type:text;not null"`
+	IsSeller        bool      `gorm:"default:false"`
+	IsVerifiedBuyer bool      `gorm:"default:false"`
+	UpvoteCount     int       `gorm:"default:0"`
+	Status          string    `gorm:"type:varchar(20);default:'pending';index"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime"`
+}
+
+// TableName returns the table name for AnswerModel.
+func (AnswerModel) TableName() string { return "product_answers" }
+
+// ToDomain converts an AnswerModel to a domain Answer.
+func (m *AnswerModel) ToDomain() *domain.Answer {
+	return &domain.Answer{
+		ID:              m.ID,
+		QuestionID:      m.QuestionID,
+		ProductID:       m.ProductID,
+		UserID:          m.UserID,
+		UserName:        m.UserName,
+		Content:         m.Content,
+		IsSeller:        m.IsSeller,
+		IsVerifiedBuyer: m.IsVerifiedBuyer,
+		UpvoteCount:     m.UpvoteCount,
+		Status:          domain.QAStatus(m.Status),
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
+
+// ToAnswerModel converts a domain Answer to an AnswerModel.
+func ToAnswerModel(a *domain.Answer) *AnswerModel {
+	return &AnswerModel{
+		ID:              a.ID,
+		QuestionID:      a.QuestionID,
+		ProductID:       a.ProductID,
+		UserID:          a.UserID,
+		UserName:        a.UserName,
+		Content:         a.Content,
+		IsSeller:        a.IsSeller,
+		IsVerifiedBuyer: a.IsVerifiedBuyer,
+		UpvoteCount:     a.UpvoteCount,
+		Status:          string(a.Status),
+		CreatedAt:       a.CreatedAt,
+		UpdatedAt:       a.UpdatedAt,
+	}
+}
+
+// QAVoteModel is the GORM model for the qa_votes table. Its primary key
+// allows one upvote per user and target.
+type QAVoteModel struct {
+	TargetType string    `gorm:"type:varchar(20);primaryKey"`
+	TargetID   string    `gorm:"type:uuid;primaryKey"`
+	UserID     string    `gorm:"type:uuid;primaryKey"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for QAVoteModel.
+func (QAVoteModel) TableName() string { return "qa_votes" }
+
+// PurchaseModel is the GORM model for the purchases table, the order history
+// behind verified buyer badges.
+type PurchaseModel struct {
+	OrderID   string    `gorm:"type:uuid;primaryKey"`
+	ProductID string    `gorm:"type:uuid;primaryKey;index:idx_purchases_user_product,priority:2"`
+	UserID    string    `gorm:"type:uuid;index:idx_purchases_user_product,priority:1;not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName returns the table name for PurchaseModel.
+func (PurchaseModel) TableName() string { return "purchases" }
+
+// OrderPaymentModel is the GORM model for the order_payments table, the
+// payments of the orders in the purchases table.
+type OrderPaymentModel struct {
+	OrderID       string    `gorm:"type:uuid;primaryKey"`
+	AmountCents   int64     `gorm:"not null"`
+	RefundedCents int64     `gorm:"not null;default:0"`
+	PaidAt        time.Time `gorm:"not null"`
+}
+
+// TableName returns the table name for OrderPaymentModel.
+func (OrderPaymentModel) TableName() string { return "order_payments" }
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/southern-martin/ecommerce/services/review/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PurchaseRepo implements domain.PurchaseRepository.
+type PurchaseRepo struct {
+	db *gorm.DB
+}
+
+// NewPurchaseRepo creates a new PurchaseRepo.
+func NewPurchaseRepo(db *gorm.DB) *PurchaseRepo {
+	return &PurchaseRepo{db: db}
+}
+
+func (r *PurchaseRepo) Record(ctx context.Context, purchases []domain.Purchase) error {
+	if len(purchases) == 0 {
+		return nil
+	}
+	models := make([]PurchaseModel, len(purchases))
+	for i, p := range purchases {
+		models[i] = PurchaseModel{
+			OrderID:   p.OrderID,
+			ProductID: p.ProductID,
+			UserID:    p.UserID,
+			CreatedAt: p.CreatedAt,
+		}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&models).Error
+}
+
+func (r *PurchaseRepo) RecordPayment(ctx context.Context, payment domain.OrderPayment) error {
+	model := OrderPaymentModel{
+		OrderID:       payment.OrderID,
+		AmountCents:   payment.AmountCents,
+		RefundedCents: payment.RefundedCents,
+		PaidAt:        payment.PaidAt,
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model).Error
+}
+
+func (r *PurchaseRepo) AddRefund(ctx context.Context, orderID string, amountCents int64) error {
+	return r.db.WithContext(ctx).Model(&OrderPaymentModel{}).
+		Where("order_id = ?", orderID).
+		Update("refunded_cents", gorm.Expr("refunded_cents + ?", amountCents)).Error
+}
+
+func (r *PurchaseRepo) DeleteByOrder(ctx context.Context, orderID string) error {
+	return r.db.WithContext(ctx).Where("order_id = ?", orderID).Delete(&PurchaseModel{}).Error
+}
+
+func (r *PurchaseRepo) HasPurchased(ctx context.Context, userID, productID string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&PurchaseModel{}).
+		Joins("JOIN order_payments ON order_payments.order_id = purchases.order_id").
+		Where("purchases.user_id = ? AND purchases.product_id = ?", userID, productID).
+		Where("order_payments.refunded_cents = 0 OR order_payments.refunded_cents < order_payments.amount_cents").
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/southern-martin/ecommerce/services/review/internal/domain"
+	"gorm.io/gorm"
+)
+
+// QuestionRepo implements domain.QuestionRepository.
+type QuestionRepo struct {
+	db *gorm.DB
+}
+
+// NewQuestionRepo creates a new QuestionRepo.
+func NewQuestionRepo(db *gorm.DB) *QuestionRepo {
+	return &QuestionRepo{db: db}
+}
+
+func (r *QuestionRepo) GetByID(ctx context.Context, id string) (*domain.Question, error) {
+	var model QuestionModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *QuestionRepo) ListByProduct(ctx context.Context, productID string, filter domain.QuestionFilter) ([]domain.Question, int64, error) {
+	query := r.db.WithContext(ctx).Model(&QuestionModel{}).Where("product_id = ?", productID)
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", string(filter.Status))
+	}
+	if filter.AnsweredOnly {
+		query = query.Where("answer_count > 0")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Sorting
+	sortBy := "created_at"
+	if filter.SortBy != "" {
+		allowedSorts := map[string]bool{
+			"created_at":   true,
+			"upvote_count": true,
+			"answer_count": true,
+		}
+		if allowedSorts[filter.SortBy] {
+			sortBy = filter.SortBy
+		}
+	}
+	sortOrder := "DESC"
+	if filter.SortOrder == "asc" || filter.SortOrder == "ASC" {
+		sortOrder = "ASC"
+	}
+	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+
+	// Pagination
+	page := filter.Page
+	if page < 1 {
+		page = 1
+	}
+	pageSize := filter.PageSize
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+
+	var models []QuestionModel
+	if err := query.Offset(offset).Limit(pageSize).Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	questions := make([]domain.Question, len(models))
+	for i, m := range models {
+		questions[i] = *m.ToDomain()
+	}
+	return questions, total, nil
+}
+
+func (r *QuestionRepo) Create(ctx context.Context, question *domain.Question) error {
+	model := ToQuestionModel(question)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	question.CreatedAt = model.CreatedAt
+	question.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *QuestionRepo) Update(ctx context.Context, question *domain.Question) error {
+	return r.db.WithContext(ctx).Model(&QuestionModel{}).Where("id = ?", question.ID).Updates(map[string]interface{}{
+		"content":      question.Content,
+		"status":       string(question.Status),
+		"answer_count": question.AnswerCount,
+	}).Error
+}
+
+func (r *QuestionRepo) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		answerIDs := tx.Model(&AnswerModel{}).Select("id").Where("question_id = ?", id)
+		if err := tx.Where("target_type = ? AND target_id IN (?)", domain.VoteTargetAnswer, answerIDs).
+			Delete(&QAVoteModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("target_type = ? AND target_id = ?", domain.VoteTargetQuestion, id).
+			Delete(&QAVoteModel{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("question_id = ?", id).Delete(&AnswerModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&QuestionModel{}).Error
+	})
+}
+
+// AnswerRepo implements domain.AnswerRepository.
+type AnswerRepo struct {
+	db *gorm.DB
+}
+
+// NewAnswerRepo creates a new AnswerRepo.
+func NewAnswerRepo(db *gorm.DB) *AnswerRepo {
+	return &AnswerRepo{db: db}
+}
+
+func (r *AnswerRepo) GetByID(ctx context.Context, id string) (*domain.Answer, error) {
+	var model AnswerModel
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		return nil, err
+	}
+	return model.ToDomain(), nil
+}
+
+func (r *AnswerRepo) ListByQuestions(ctx context.Context, questionIDs []string, status domain.QAStatus) ([]domain.Answer, error) {
+	if len(questionIDs) == 0 {
+		return nil, nil
+	}
+
+	query := r.db.WithContext(ctx).Where("question_id IN ?", questionIDs)
+	if status != "" {
+		query = query.Where("status = ?", string(status))
+	}
+
+	var models []AnswerModel
+	if err := query.Order("is_seller DESC, upvote_count DESC, created_at ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	answers := make([]domain.Answer, len(models))
+	for i, m := range models {
+		answers[i] = *m.ToDomain()
+	}
+	return answers, nil
+}
+
+func (r *AnswerRepo) Create(ctx context.Context, answer *domain.Answer) error {
+	model := ToAnswerModel(answer)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	answer.CreatedAt = model.CreatedAt
+	answer.UpdatedAt = model.UpdatedAt
+	return nil
+}
+
+func (r *AnswerRepo) Update(ctx context.Context, answer *domain.Answer) error {
+	return r.db.WithContext(ctx).Model(&AnswerModel{}).Where("id = ?", answer.ID).Updates(map[string]interface{}{
+		"content":           answer.Content,
+		"is_verified_buyer": answer.IsVerifiedBuyer,
+		"status":            string(answer.Status),
+	}).Error
+}
+
+func (r *AnswerRepo) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("target_type = ? AND target_id = ?", domain.VoteTargetAnswer, id).
+			Delete(&QAVoteModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&AnswerModel{}).Error
+	})
+}
@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/southern-martin/ecommerce/services/review/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VoteRepo implements domain.VoteRepository.
+type VoteRepo struct {
+	db *gorm.DB
+}
+
+// NewVoteRepo creates a new VoteRepo.
+func NewVoteRepo(db *gorm.DB) *VoteRepo {
+	return &VoteRepo{db: db}
+}
+
+// Upvote records a user's upvote and increments the target's upvote count in
+// the same transaction. A repeated upvote changes nothing.
+func (r *VoteRepo) Upvote(ctx context.Context, targetType, targetID, userID string) (bool, error) {
+	model, err := voteTargetModel(targetType)
+	if err != nil {
+		return false, err
+	}
+
+	added := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&QAVoteModel{
+			TargetType: targetType,
+			TargetID:   targetID,
+			UserID:     userID,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		added = true
+		return tx.Model(model).Where("id = ?", targetID).
+			UpdateColumn("upvote_count", gorm.Expr("upvote_count + 1")).Error
+	})
+	return added, err
+}
+
+// RemoveUpvote deletes a user's upvote and decrements the target's upvote
+// count in the same transaction.
+func (r *VoteRepo) RemoveUpvote(ctx context.Context, targetType, targetID, userID string) (bool, error) {
+	model, err := voteTargetModel(targetType)
+	if err != nil {
+		return false, err
+	}
+
+	removed := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("target_type = ? AND target_id = ? AND user_id = ?", targetType, targetID, userID).
+			Delete(&QAVoteModel{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		removed = true
+		return tx.Model(model).Where("id = ? AND upvote_count > 0", targetID).
+			UpdateColumn("upvote_count", gorm.Expr("upvote_count - 1")).Error
+	})
+	return removed, err
+}
+
+// voteTargetModel returns the model whose upvote count a vote target type
+// keeps.
+func voteTargetModel(targetType string) (interface{}, error) {
+	switch targetType {
+	case domain.VoteTargetQuestion:
+		return &QuestionModel{}, nil
+	case domain.VoteTargetAnswer:
+		return &AnswerModel{}, nil
+	default:
+		return nil, fmt.Errorf("unknown vote target: %s", targetType)
+	}
+}
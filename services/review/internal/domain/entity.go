@@ -50,3 +50,109 @@ type ReviewFilter struct {
 	Page      int
 	PageSize  int
 }
+
+// QAStatus represents the moderation status of a product question or answer.
+type QAStatus string
+
+const (
+	QAStatusPending  QAStatus = "pending"
+	QAStatusApproved QAStatus = "approved"
+	QAStatusRejected QAStatus = "rejected"
+)
+
+// Vote targets of Q&A upvotes.
+const (
+	VoteTargetQuestion = "question"
+	VoteTargetAnswer   = "answer"
+)
+
+// Question is a buyer's pre-purchase question about a product. SellerID is
+// the seller of the product, who is notified of the question and whose
+// answers are marked as the seller's. AnswerCount counts approved answers.
+type Question struct {
+	ID          string
+	ProductID   string
+	SellerID    string
+	UserID      string
+	UserName    string
+	Content     string
+	Status      QAStatus
+	AnswerCount int
+	UpvoteCount int
+	Answers     []Answer
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Answer is an answer to a product question, by the product's seller or by
+// the community. IsVerifiedBuyer marks answers by users who have ordered the
+// product.
+type Answer struct {
+	ID              string
+	QuestionID      string
+	ProductID       string
+	UserID          string
+	UserName        string
+	Content         string
+	IsSeller        bool
+	IsVerifiedBuyer bool
+	UpvoteCount     int
+	Status          QAStatus
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// QuestionFilter holds filtering and pagination options for listing
+// questions.
+type QuestionFilter struct {
+	ProductID    string
+	Status       QAStatus
+	AnsweredOnly bool
+	SortBy       string
+	SortOrder    string
+	Page         int
+	PageSize     int
+}
+
+// Purchase records that a user ordered a product, for verified buyer badges.
+// It counts once the order is paid for.
+type Purchase struct {
+	OrderID   string
+	UserID    string
+	ProductID string
+	CreatedAt time.Time
+}
+
+// OrderPayment records that an order was paid for and how much of it was
+// refunded. A user is a verified buyer of the products of orders that are
+// paid for and not refunded in full.
+type OrderPayment struct {
+	OrderID       string
+	AmountCents   int64
+	RefundedCents int64
+	PaidAt        time.Time
+}
+
+// OrderInfo is the part of an order the review service needs from the order
+// service.
+type OrderInfo struct {
+	ID         string
+	BuyerID    string
+	ProductIDs []string
+}
+
+// PaymentInfo is the part of a payment the review service needs from the
+// payment service.
+type PaymentInfo struct {
+	OrderID     string
+	AmountCents int64
+	Refunded    bool
+}
+
+// ProductInfo is the part of a product the review service needs from the
+// product service.
+type ProductInfo struct {
+	ID       string
+	SellerID string
+	Name     string
+}
@@ -6,3 +6,39 @@ import "context"
 type EventPublisher interface {
 	Publish(ctx context.Context, subject string, data interface{}) error
 }
+
+// Event subjects for product Q&A.
+const (
+	EventQuestionCreated  = "question.created"
+	EventQuestionAnswered = "question.answered"
+	EventQuestionRemoved  = "question.removed"
+)
+
+// QuestionCreatedEvent is published when a buyer asks a question, so that
+// the seller can be notified.
+type QuestionCreatedEvent struct {
+	QuestionID  string `json:"question_id"`
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	SellerID    string `json:"seller_id"`
+	UserID      string `json:"user_id"`
+	UserName    string `json:"user_name"`
+	Content     string `json:"content"`
+}
+
+// QuestionAnsweredEvent is published whenever the approved answers of an
+// approved question change, carrying the question and all of them for
+// search indexing.
+type QuestionAnsweredEvent struct {
+	QuestionID string   `json:"question_id"`
+	ProductID  string   `json:"product_id"`
+	Question   string   `json:"question"`
+	Answers    []string `json:"answers"`
+}
+
+// QuestionRemovedEvent is published when a question is no longer an
+// answered, approved question and drops out of search.
+type QuestionRemovedEvent struct {
+	QuestionID string `json:"question_id"`
+	ProductID  string `json:"product_id"`
+}
@@ -12,3 +12,68 @@ type ReviewRepository interface {
 	Delete(ctx context.Context, id string) error
 	GetSummary(ctx context.Context, productID string) (*ReviewSummary, error)
 }
+
+// QuestionRepository defines the interface for product question persistence.
+type QuestionRepository interface {
+	GetByID(ctx context.Context, id string) (*Question, error)
+	ListByProduct(ctx context.Context, productID string, filter QuestionFilter) ([]Question, int64, error)
+	Create(ctx context.Context, question *Question) error
+	Update(ctx context.Context, question *Question) error
+	// Delete removes a question together with its answers and votes.
+	Delete(ctx context.Context, id string) error
+}
+
+// AnswerRepository defines the interface for answer persistence.
+type AnswerRepository interface {
+	GetByID(ctx context.Context, id string) (*Answer, error)
+	// ListByQuestions lists the answers to questions, optionally only those
+	// in status, the seller's first and then by upvotes.
+	ListByQuestions(ctx context.Context, questionIDs []string, status QAStatus) ([]Answer, error)
+	Create(ctx context.Context, answer *Answer) error
+	Update(ctx context.Context, answer *Answer) error
+	// Delete removes an answer together with its votes.
+	Delete(ctx context.Context, id string) error
+}
+
+// VoteRepository defines the interface for Q&A upvotes. A user upvotes a
+// question or answer at most once; the target's upvote count is kept in step.
+type VoteRepository interface {
+	// Upvote records a user's upvote and reports whether it was new.
+	Upvote(ctx context.Context, targetType, targetID, userID string) (bool, error)
+	// RemoveUpvote withdraws a user's upvote and reports whether there was one.
+	RemoveUpvote(ctx context.Context, targetType, targetID, userID string) (bool, error)
+}
+
+// PurchaseRepository defines the interface for the order history used for
+// verified buyer badges.
+type PurchaseRepository interface {
+	// Record stores purchases, ignoring ones already recorded.
+	Record(ctx context.Context, purchases []Purchase) error
+	// RecordPayment stores an order's payment, ignoring it if already
+	// recorded.
+	RecordPayment(ctx context.Context, payment OrderPayment) error
+	// AddRefund adds amountCents to the amount refunded of an order's
+	// payment.
+	AddRefund(ctx context.Context, orderID string, amountCents int64) error
+	DeleteByOrder(ctx context.Context, orderID string) error
+	// HasPurchased reports whether a user ordered a product in an order
+	// that is paid for and not refunded in full.
+	HasPurchased(ctx context.Context, userID, productID string) (bool, error)
+}
+
+// OrderProvider fetches orders from the order service.
+type OrderProvider interface {
+	GetOrder(ctx context.Context, orderID string) (*OrderInfo, error)
+}
+
+// PaymentProvider pages through the payment history of the payment service.
+type PaymentProvider interface {
+	// ListPaidOrders lists the payments that were completed, refunded or
+	// not, oldest first.
+	ListPaidOrders(ctx context.Context, page, pageSize int) ([]PaymentInfo, error)
+}
+
+// ProductProvider fetches products from the product service.
+type ProductProvider interface {
+	GetProduct(ctx context.Context, productID string) (*ProductInfo, error)
+}
@@ -12,6 +12,13 @@ type Config struct {
 	Postgres PostgresConfig
 	NATS     NATSConfig
 	LogLevel string
+	// ProductServiceURL is where the seller and name of a product are
+	// fetched when a question is asked about it.
+	ProductServiceURL string
+	// OrderGRPCAddr and PaymentGRPCAddr are where order history is read
+	// from when backfilling verified buyer purchases.
+	OrderGRPCAddr   string
+	PaymentGRPCAddr string
 }
 
 // PostgresConfig holds Postgres connection configuration.
@@ -52,6 +59,9 @@ func Load() *Config {
 		NATS: NATSConfig{
 			URL: getEnv("NATS_URL", "nats://localhost:4222"),
 		},
+		ProductServiceURL: getEnv("PRODUCT_SERVICE_URL", "http://localhost:8081"),
+		OrderGRPCAddr:     getEnv("ORDER_GRPC_ADDR", "localhost:9083"),
+		PaymentGRPCAddr:   getEnv("PAYMENT_GRPC_ADDR", "localhost:9084"),
 	}
 }
 
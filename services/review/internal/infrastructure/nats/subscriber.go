@@ -0,0 +1,113 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/review/internal/usecase"
+)
+
+// Order and payment subjects the purchase history listens to.
+const (
+	subjectOrderCreated     = "order.created"
+	subjectOrderCancelled   = "order.cancelled"
+	subjectPaymentCompleted = "payment.completed"
+	subjectPaymentRefunded  = "payment.refunded"
+)
+
+// orderCreatedEvent holds the fields of an order.created payload used for
+// the purchase history.
+type orderCreatedEvent struct {
+	OrderID string `json:"order_id"`
+	BuyerID string `json:"buyer_id"`
+	Items   []struct {
+		ProductID string `json:"product_id"`
+	} `json:"items"`
+}
+
+// orderIDEvent holds the order ID shared by order and payment payloads.
+type orderIDEvent struct {
+	OrderID string `json:"order_id"`
+}
+
+// paymentEvent holds the fields of payment.completed and payment.refunded
+// payloads used for the purchase history.
+type paymentEvent struct {
+	OrderID     string `json:"order_id"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+// StartPurchaseSubscriber records the products buyers order, for verified
+// buyer badges on answers. They count once the order is paid for, until it
+// is cancelled or refunded in full.
+func StartPurchaseSubscriber(p *Publisher, purchaseUC *usecase.PurchaseUseCase) error {
+	if _, err := p.Subscribe(subjectOrderCreated, func(data []byte) {
+		var event orderCreatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode order.created event")
+			return
+		}
+		productIDs := make([]string, 0, len(event.Items))
+		for _, item := range event.Items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := purchaseUC.RecordOrder(ctx, event.OrderID, event.BuyerID, productIDs); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("failed to record purchases")
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(subjectPaymentCompleted, func(data []byte) {
+		var event paymentEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode payment.completed event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := purchaseUC.RecordPayment(ctx, event.OrderID, event.AmountCents); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("failed to record order payment")
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := p.Subscribe(subjectPaymentRefunded, func(data []byte) {
+		var event paymentEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode payment.refunded event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := purchaseUC.RecordRefund(ctx, event.OrderID, event.AmountCents); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("failed to record order refund")
+		}
+	}); err != nil {
+		return err
+	}
+
+	_, err := p.Subscribe(subjectOrderCancelled, func(data []byte) {
+		var event orderIDEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode order.cancelled event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := purchaseUC.RevokeOrder(ctx, event.OrderID); err != nil {
+			log.Error().Err(err).Str("order_id", event.OrderID).Msg("failed to revoke purchases")
+		}
+	})
+	return err
+}
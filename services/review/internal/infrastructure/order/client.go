@@ -0,0 +1,65 @@
+package order
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/southern-martin/ecommerce/pkg/grpcjson"
+	"github.com/southern-martin/ecommerce/services/review/internal/domain"
+)
+
+// Client implements domain.OrderProvider on top of the order service's gRPC
+// API.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient creates a client of the order service's gRPC API at addr. The
+// connection is established lazily, on the first call.
+func NewClient(addr string) (*Client, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpcjson.CallOption()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to order service at %s: %w", addr, err)
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the gRPC connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// --- Messages of the order service's gRPC API ---
+
+type getOrderRequest struct {
+	OrderID string
+}
+
+type getOrderResponse struct {
+	ID      string
+	BuyerID string
+	Items   []struct {
+		ProductID string
+	}
+}
+
+// GetOrder fetches the buyer and products of an order.
+func (c *Client) GetOrder(ctx context.Context, orderID string) (*domain.OrderInfo, error) {
+	var resp getOrderResponse
+	if err := c.conn.Invoke(ctx, "/order.OrderService/GetOrder",
+		&getOrderRequest{OrderID: orderID}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
+	}
+
+	order := &domain.OrderInfo{ID: resp.ID, BuyerID: resp.BuyerID}
+	for _, item := range resp.Items {
+		order.ProductIDs = append(order.ProductIDs, item.ProductID)
+	}
+	return order, nil
+}
@@ -0,0 +1,81 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/southern-martin/ecommerce/pkg/grpcjson"
+	"github.com/southern-martin/ecommerce/services/review/internal/domain"
+)
+
+// Payment statuses of the payment service that mean a payment was made.
+const (
+	statusCompleted = "completed"
+	statusRefunded  = "refunded"
+)
+
+// Client implements domain.PaymentProvider on top of the payment service's
+// gRPC API.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient creates a client of the payment service's gRPC API at addr.
+// The connection is established lazily, on the first call.
+func NewClient(addr string) (*Client, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpcjson.CallOption()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to payment service at %s: %w", addr, err)
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the gRPC connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// --- Messages of the payment service's gRPC API ---
+
+type listPaymentsRequest struct {
+	Statuses []string
+	Page     int32
+	PageSize int32
+}
+
+type listPaymentsResponse struct {
+	Payments []struct {
+		OrderID     string
+		AmountCents int64
+		Status      string
+	}
+	Total int64
+}
+
+// ListPaidOrders lists the completed and refunded payments, oldest first.
+func (c *Client) ListPaidOrders(ctx context.Context, page, pageSize int) ([]domain.PaymentInfo, error) {
+	var resp listPaymentsResponse
+	if err := c.conn.Invoke(ctx, "/payment.PaymentService/ListPayments", &listPaymentsRequest{
+		Statuses: []string{statusCompleted, statusRefunded},
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+
+	payments := make([]domain.PaymentInfo, 0, len(resp.Payments))
+	for _, p := range resp.Payments {
+		payments = append(payments, domain.PaymentInfo{
+			OrderID:     p.OrderID,
+			AmountCents: p.AmountCents,
+			Refunded:    p.Status == statusRefunded,
+		})
+	}
+	return payments, nil
+}
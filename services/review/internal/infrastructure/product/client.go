@@ -0,0 +1,65 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/review/internal/domain"
+)
+
+// Client implements domain.ProductProvider on top of the product service's
+// HTTP API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new product service client.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// productResponse is the part of the body of GET /api/v1/products/:id used
+// here.
+type productResponse struct {
+	ID       string `json:"id"`
+	SellerID string `json:"seller_id"`
+	Name     string `json:"name"`
+}
+
+// GetProduct fetches a product's seller and name.
+func (c *Client) GetProduct(ctx context.Context, productID string) (*domain.ProductInfo, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/products/%s", c.baseURL, url.PathEscape(productID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product service returned %s", resp.Status)
+	}
+
+	var body productResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode product: %w", err)
+	}
+	return &domain.ProductInfo{
+		ID:       body.ID,
+		SellerID: body.SellerID,
+		Name:     body.Name,
+	}, nil
+}
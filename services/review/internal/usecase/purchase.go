@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/services/review/internal/domain"
+)
+
+// backfillPageSize is how many payments are fetched at a time when
+// backfilling the purchase history.
+const backfillPageSize = 100
+
+// PurchaseUseCase keeps the order history behind verified buyer badges, fed
+// by order and payment events.
+type PurchaseUseCase struct {
+	purchaseRepo domain.PurchaseRepository
+	orders       domain.OrderProvider
+	payments     domain.PaymentProvider
+}
+
+// NewPurchaseUseCase creates a new PurchaseUseCase. The order and payment
+// providers are only used to backfill the purchase history.
+func NewPurchaseUseCase(purchaseRepo domain.PurchaseRepository, orders domain.OrderProvider, payments domain.PaymentProvider) *PurchaseUseCase {
+	return &PurchaseUseCase{
+		purchaseRepo: purchaseRepo,
+		orders:       orders,
+		payments:     payments,
+	}
+}
+
+// RecordOrder records that a buyer ordered products. They count as
+// purchased once the order is paid for.
+func (uc *PurchaseUseCase) RecordOrder(ctx context.Context, orderID, buyerID string, productIDs []string) error {
+	now := time.Now()
+	purchases := make([]domain.Purchase, 0, len(productIDs))
+	for _, productID := range productIDs {
+		purchases = append(purchases, domain.Purchase{
+			OrderID:   orderID,
+			UserID:    buyerID,
+			ProductID: productID,
+			CreatedAt: now,
+		})
+	}
+	return uc.purchaseRepo.Record(ctx, purchases)
+}
+
+// RecordPayment records that an order was paid for.
+func (uc *PurchaseUseCase) RecordPayment(ctx context.Context, orderID string, amountCents int64) error {
+	return uc.purchaseRepo.RecordPayment(ctx, domain.OrderPayment{
+		OrderID:     orderID,
+		AmountCents: amountCents,
+		PaidAt:      time.Now(),
+	})
+}
+
+// RecordRefund records a refund of an order's payment. The order's products
+// no longer count as purchased once it is refunded in full.
+func (uc *PurchaseUseCase) RecordRefund(ctx context.Context, orderID string, amountCents int64) error {
+	if amountCents <= 0 {
+		return fmt.Errorf("refund amount must be positive")
+	}
+	return uc.purchaseRepo.AddRefund(ctx, orderID, amountCents)
+}
+
+// RevokeOrder forgets the purchases of a cancelled order.
+func (uc *PurchaseUseCase) RevokeOrder(ctx context.Context, orderID string) error {
+	return uc.purchaseRepo.DeleteByOrder(ctx, orderID)
+}
+
+// Backfill records the purchases of the orders paid for before the purchase
+// history was kept, from the payment and order services, and returns how
+// many orders it recorded. Orders already recorded are left as they are, so
+// it can be run again. The payment service does not keep how much of a
+// payment was refunded, so refunded payments count as refunded in full.
+func (uc *PurchaseUseCase) Backfill(ctx context.Context) (int, error) {
+	recorded := 0
+	for page := 1; ; page++ {
+		payments, err := uc.payments.ListPaidOrders(ctx, page, backfillPageSize)
+		if err != nil {
+			return recorded, fmt.Errorf("failed to list payments: %w", err)
+		}
+
+		for _, payment := range payments {
+			if err := uc.backfillOrder(ctx, payment); err != nil {
+				log.Error().Err(err).Str("order_id", payment.OrderID).Msg("failed to backfill purchases")
+				continue
+			}
+			recorded++
+		}
+
+		if len(payments) < backfillPageSize {
+			return recorded, nil
+		}
+	}
+}
+
+func (uc *PurchaseUseCase) backfillOrder(ctx context.Context, payment domain.PaymentInfo) error {
+	order, err := uc.orders.GetOrder(ctx, payment.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch order: %w", err)
+	}
+
+	if err := uc.RecordOrder(ctx, order.ID, order.BuyerID, order.ProductIDs); err != nil {
+		return err
+	}
+
+	recorded := domain.OrderPayment{
+		OrderID:     payment.OrderID,
+		AmountCents: payment.AmountCents,
+		PaidAt:      time.Now(),
+	}
+	if payment.Refunded {
+		recorded.RefundedCents = payment.AmountCents
+	}
+	return uc.purchaseRepo.RecordPayment(ctx, recorded)
+}
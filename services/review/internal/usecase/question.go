@@ -0,0 +1,389 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/southern-martin/ecommerce/services/review/internal/domain"
+)
+
+// maxQAContentLength is the longest question or answer accepted, in bytes.
+const maxQAContentLength = 2000
+
+// ErrNotAuthor is returned when a user changes a question or answer they did
+// not write.
+var ErrNotAuthor = errors.New("only the author can do this")
+
+// QuestionUseCase handles product questions and answers.
+type QuestionUseCase struct {
+	questionRepo domain.QuestionRepository
+	answerRepo   domain.AnswerRepository
+	voteRepo     domain.VoteRepository
+	purchaseRepo domain.PurchaseRepository
+	products     domain.ProductProvider
+	publisher    domain.EventPublisher
+}
+
+// NewQuestionUseCase creates a new QuestionUseCase.
+func NewQuestionUseCase(
+	questionRepo domain.QuestionRepository,
+	answerRepo domain.AnswerRepository,
+	voteRepo domain.VoteRepository,
+	purchaseRepo domain.PurchaseRepository,
+	products domain.ProductProvider,
+	publisher domain.EventPublisher,
+) *QuestionUseCase {
+	return &QuestionUseCase{
+		questionRepo: questionRepo,
+		answerRepo:   answerRepo,
+		voteRepo:     voteRepo,
+		purchaseRepo: purchaseRepo,
+		products:     products,
+		publisher:    publisher,
+	}
+}
+
+// AskQuestionRequest is the input for asking a question about a product.
+type AskQuestionRequest struct {
+	ProductID string
+	UserID    string
+	UserName  string
+	Content   string
+}
+
+// AskQuestion records a question about a product for moderation and
+// notifies the product's seller.
+func (uc *QuestionUseCase) AskQuestion(ctx context.Context, req AskQuestionRequest) (*domain.Question, error) {
+	if req.ProductID == "" {
+		return nil, errors.New("product_id is required")
+	}
+	if req.UserID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	content, err := qaContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := uc.products.GetProduct(ctx, req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("product not found: %w", err)
+	}
+	if product.SellerID == req.UserID {
+		return nil, errors.New("sellers cannot ask questions about their own products")
+	}
+
+	question := &domain.Question{
+		ID:        uuid.New().String(),
+		ProductID: req.ProductID,
+		SellerID:  product.SellerID,
+		UserID:    req.UserID,
+		UserName:  req.UserName,
+		Content:   content,
+		Status:    domain.QAStatusPending,
+	}
+	if err := uc.questionRepo.Create(ctx, question); err != nil {
+		return nil, fmt.Errorf("failed to create question: %w", err)
+	}
+
+	// Publish event
+	_ = uc.publisher.Publish(ctx, domain.EventQuestionCreated, domain.QuestionCreatedEvent{
+		QuestionID:  question.ID,
+		ProductID:   question.ProductID,
+		ProductName: product.Name,
+		SellerID:    question.SellerID,
+		UserID:      question.UserID,
+		UserName:    question.UserName,
+		Content:     question.Content,
+	})
+
+	return question, nil
+}
+
+// GetQuestion retrieves a question by ID with its approved answers.
+func (uc *QuestionUseCase) GetQuestion(ctx context.Context, id string) (*domain.Question, error) {
+	question, err := uc.questionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := uc.answerRepo.ListByQuestions(ctx, []string{question.ID}, domain.QAStatusApproved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list answers: %w", err)
+	}
+	question.Answers = answers
+	return question, nil
+}
+
+// ListProductQuestions lists questions about a product with their approved
+// answers.
+func (uc *QuestionUseCase) ListProductQuestions(ctx context.Context, productID string, filter domain.QuestionFilter) ([]domain.Question, int64, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 || filter.PageSize > 100 {
+		filter.PageSize = 20
+	}
+	questions, total, err := uc.questionRepo.ListByProduct(ctx, productID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]string, len(questions))
+	for i, q := range questions {
+		ids[i] = q.ID
+	}
+	answers, err := uc.answerRepo.ListByQuestions(ctx, ids, domain.QAStatusApproved)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list answers: %w", err)
+	}
+	byQuestion := make(map[string][]domain.Answer)
+	for _, a := range answers {
+		byQuestion[a.QuestionID] = append(byQuestion[a.QuestionID], a)
+	}
+	for i := range questions {
+		questions[i].Answers = byQuestion[questions[i].ID]
+	}
+	return questions, total, nil
+}
+
+// DeleteQuestion deletes a question and its answers. Only its author can
+// delete it.
+func (uc *QuestionUseCase) DeleteQuestion(ctx context.Context, id, userID string) error {
+	question, err := uc.questionRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("question not found: %w", err)
+	}
+	if question.UserID != userID {
+		return ErrNotAuthor
+	}
+
+	if err := uc.questionRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// Publish event
+	_ = uc.publisher.Publish(ctx, domain.EventQuestionRemoved, domain.QuestionRemovedEvent{
+		QuestionID: question.ID,
+		ProductID:  question.ProductID,
+	})
+	return nil
+}
+
+// ModerateQuestion approves or rejects a question.
+func (uc *QuestionUseCase) ModerateQuestion(ctx context.Context, id string, status domain.QAStatus) (*domain.Question, error) {
+	if status != domain.QAStatusApproved && status != domain.QAStatusRejected {
+		return nil, fmt.Errorf("invalid moderation status: %s", status)
+	}
+	question, err := uc.questionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+
+	question.Status = status
+	if err := uc.syncQuestion(ctx, question); err != nil {
+		return nil, fmt.Errorf("failed to moderate question: %w", err)
+	}
+	return question, nil
+}
+
+// AnswerQuestionRequest is the input for answering a question.
+type AnswerQuestionRequest struct {
+	QuestionID string
+	UserID     string
+	UserName   string
+	Content    string
+}
+
+// AnswerQuestion records an answer to a question. The seller's answers are
+// published straight away; community answers wait for moderation. Answers by
+// users who have ordered the product are marked as by a verified buyer.
+func (uc *QuestionUseCase) AnswerQuestion(ctx context.Context, req AnswerQuestionRequest) (*domain.Answer, error) {
+	if req.UserID == "" {
+		return nil, errors.New("user_id is required")
+	}
+	content, err := qaContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := uc.questionRepo.GetByID(ctx, req.QuestionID)
+	if err != nil {
+		return nil, fmt.Errorf("question not found: %w", err)
+	}
+	if question.Status == domain.QAStatusRejected {
+		return nil, errors.New("question is not open for answers")
+	}
+
+	verified, err := uc.purchaseRepo.HasPurchased(ctx, req.UserID, question.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check order history: %w", err)
+	}
+
+	answer := &domain.Answer{
+		ID:              uuid.New().String(),
+		QuestionID:      question.ID,
+		ProductID:       question.ProductID,
+		UserID:          req.UserID,
+		UserName:        req.UserName,
+		Content:         content,
+		IsSeller:        req.UserID == question.SellerID,
+		IsVerifiedBuyer: verified,
+		Status:          domain.QAStatusPending,
+	}
+	if answer.IsSeller {
+		answer.Status = domain.QAStatusApproved
+	}
+	if err := uc.answerRepo.Create(ctx, answer); err != nil {
+		return nil, fmt.Errorf("failed to create answer: %w", err)
+	}
+
+	if answer.Status == domain.QAStatusApproved {
+		if err := uc.syncQuestion(ctx, question); err != nil {
+			return nil, err
+		}
+	}
+	return answer, nil
+}
+
+// DeleteAnswer deletes an answer. Only its author can delete it.
+func (uc *QuestionUseCase) DeleteAnswer(ctx context.Context, id, userID string) error {
+	answer, err := uc.answerRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("answer not found: %w", err)
+	}
+	if answer.UserID != userID {
+		return ErrNotAuthor
+	}
+
+	if err := uc.answerRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return uc.syncAnswerQuestion(ctx, answer)
+}
+
+// ModerateAnswer approves or rejects an answer.
+func (uc *QuestionUseCase) ModerateAnswer(ctx context.Context, id string, status domain.QAStatus) (*domain.Answer, error) {
+	if status != domain.QAStatusApproved && status != domain.QAStatusRejected {
+		return nil, fmt.Errorf("invalid moderation status: %s", status)
+	}
+	answer, err := uc.answerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("answer not found: %w", err)
+	}
+
+	answer.Status = status
+	if err := uc.answerRepo.Update(ctx, answer); err != nil {
+		return nil, fmt.Errorf("failed to moderate answer: %w", err)
+	}
+	if err := uc.syncAnswerQuestion(ctx, answer); err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+// Upvote records a user's upvote of a question or answer. Upvoting twice
+// counts once, and users cannot upvote their own posts.
+func (uc *QuestionUseCase) Upvote(ctx context.Context, targetType, targetID, userID string) error {
+	if userID == "" {
+		return errors.New("user_id is required")
+	}
+	authorID, err := uc.voteTargetAuthor(ctx, targetType, targetID)
+	if err != nil {
+		return err
+	}
+	if authorID == userID {
+		return fmt.Errorf("cannot upvote your own %s", targetType)
+	}
+
+	_, err = uc.voteRepo.Upvote(ctx, targetType, targetID, userID)
+	return err
+}
+
+// RemoveUpvote withdraws a user's upvote of a question or answer.
+func (uc *QuestionUseCase) RemoveUpvote(ctx context.Context, targetType, targetID, userID string) error {
+	if _, err := uc.voteTargetAuthor(ctx, targetType, targetID); err != nil {
+		return err
+	}
+	_, err := uc.voteRepo.RemoveUpvote(ctx, targetType, targetID, userID)
+	return err
+}
+
+// voteTargetAuthor returns the author of the question or answer being voted
+// on.
+func (uc *QuestionUseCase) voteTargetAuthor(ctx context.Context, targetType, targetID string) (string, error) {
+	switch targetType {
+	case domain.VoteTargetQuestion:
+		question, err := uc.questionRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return "", fmt.Errorf("question not found: %w", err)
+		}
+		return question.UserID, nil
+	case domain.VoteTargetAnswer:
+		answer, err := uc.answerRepo.GetByID(ctx, targetID)
+		if err != nil {
+			return "", fmt.Errorf("answer not found: %w", err)
+		}
+		return answer.UserID, nil
+	default:
+		return "", fmt.Errorf("unknown vote target: %s", targetType)
+	}
+}
+
+// syncAnswerQuestion syncs the question an answer belongs to.
+func (uc *QuestionUseCase) syncAnswerQuestion(ctx context.Context, answer *domain.Answer) error {
+	question, err := uc.questionRepo.GetByID(ctx, answer.QuestionID)
+	if err != nil {
+		return fmt.Errorf("question not found: %w", err)
+	}
+	return uc.syncQuestion(ctx, question)
+}
+
+// syncQuestion saves a question with the count of its approved answers and
+// publishes it for search: an approved question with approved answers is
+// published as answered, anything else as removed.
+func (uc *QuestionUseCase) syncQuestion(ctx context.Context, question *domain.Question) error {
+	answers, err := uc.answerRepo.ListByQuestions(ctx, []string{question.ID}, domain.QAStatusApproved)
+	if err != nil {
+		return fmt.Errorf("failed to list answers: %w", err)
+	}
+	question.Answers = answers
+	question.AnswerCount = len(answers)
+	if err := uc.questionRepo.Update(ctx, question); err != nil {
+		return fmt.Errorf("failed to update question: %w", err)
+	}
+
+	// Publish event
+	if question.Status != domain.QAStatusApproved || len(answers) == 0 {
+		_ = uc.publisher.Publish(ctx, domain.EventQuestionRemoved, domain.QuestionRemovedEvent{
+			QuestionID: question.ID,
+			ProductID:  question.ProductID,
+		})
+		return nil
+	}
+	contents := make([]string, len(answers))
+	for i, a := range answers {
+		contents[i] = a.Content
+	}
+	_ = uc.publisher.Publish(ctx, domain.EventQuestionAnswered, domain.QuestionAnsweredEvent{
+		QuestionID: question.ID,
+		ProductID:  question.ProductID,
+		Question:   question.Content,
+		Answers:    contents,
+	})
+	return nil
+}
+
+// qaContent validates and trims the text of a question or answer.
+func qaContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", errors.New("content is required")
+	}
+	if len(content) > maxQAContentLength {
+		return "", fmt.Errorf("content must be at most %d characters", maxQAContentLength)
+	}
+	return content, nil
+}
@@ -123,6 +123,7 @@ func (uc *ReviewUseCase) UpdateReview(ctx context.Context, id string, req Update
 		return nil, fmt.Errorf("review not found: %w", err)
 	}
 
+	previousRating := review.Rating
 	if req.Rating != nil {
 		if *req.Rating < 1 || *req.Rating > 5 {
 			return nil, errors.New("rating must be between 1 and 5")
@@ -149,12 +150,41 @@ func (uc *ReviewUseCase) UpdateReview(ctx context.Context, id string, req Update
 		return nil, fmt.Errorf("failed to update review: %w", err)
 	}
 
+	// Publish event
+	_ = uc.publisher.Publish(ctx, "review.updated", map[string]interface{}{
+		"review_id":       review.ID,
+		"product_id":      review.ProductID,
+		"user_id":         review.UserID,
+		"rating":          review.Rating,
+		"previous_rating": previousRating,
+		"status":          review.Status,
+		"previous_status": review.Status,
+	})
+
 	return review, nil
 }
 
 // DeleteReview deletes a review by ID.
 func (uc *ReviewUseCase) DeleteReview(ctx context.Context, id string) error {
-	return uc.reviewRepo.Delete(ctx, id)
+	review, err := uc.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("review not found: %w", err)
+	}
+
+	if err := uc.reviewRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// Publish event
+	_ = uc.publisher.Publish(ctx, "review.deleted", map[string]interface{}{
+		"review_id":       review.ID,
+		"product_id":      review.ProductID,
+		"user_id":         review.UserID,
+		"previous_rating": review.Rating,
+		"previous_status": review.Status,
+	})
+
+	return nil
 }
 
 // ApproveReview approves a review.
@@ -164,6 +194,7 @@ func (uc *ReviewUseCase) ApproveReview(ctx context.Context, id string) (*domain.
 		return nil, fmt.Errorf("review not found: %w", err)
 	}
 
+	previousStatus := review.Status
 	review.Status = domain.ReviewStatusApproved
 
 	if err := uc.reviewRepo.Update(ctx, review); err != nil {
@@ -172,10 +203,13 @@ func (uc *ReviewUseCase) ApproveReview(ctx context.Context, id string) (*domain.
 
 	// Publish event
 	_ = uc.publisher.Publish(ctx, "review.approved", map[string]interface{}{
-		"review_id":  review.ID,
-		"product_id": review.ProductID,
-		"user_id":    review.UserID,
-		"rating":     review.Rating,
+		"review_id":       review.ID,
+		"product_id":      review.ProductID,
+		"user_id":         review.UserID,
+		"rating":          review.Rating,
+		"previous_rating": review.Rating,
+		"status":          review.Status,
+		"previous_status": previousStatus,
 	})
 
 	return review, nil
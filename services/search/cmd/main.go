@@ -14,12 +14,15 @@ import (
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
 
+	"github.com/southern-martin/ecommerce/pkg/events"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 	grpcAdapter "github.com/southern-martin/ecommerce/services/search/internal/adapter/grpc"
 	httpAdapter "github.com/southern-martin/ecommerce/services/search/internal/adapter/http"
 	"github.com/southern-martin/ecommerce/services/search/internal/adapter/postgres"
 	"github.com/southern-martin/ecommerce/services/search/internal/infrastructure/config"
 	"github.com/southern-martin/ecommerce/services/search/internal/infrastructure/database"
 	natsInfra "github.com/southern-martin/ecommerce/services/search/internal/infrastructure/nats"
+	"github.com/southern-martin/ecommerce/services/search/internal/infrastructure/product"
 	"github.com/southern-martin/ecommerce/services/search/internal/usecase"
 )
 
@@ -38,10 +41,31 @@ func main() {
 	// AutoMigrate
 	if err := db.AutoMigrate(
 		&postgres.SearchIndexModel{},
+		&postgres.SearchQuestionModel{},
+		&postgres.SearchQueryModel{},
+		&postgres.SearchQuerySearcherModel{},
 	); err != nil {
 		log.Fatal().Err(err).Msg("failed to auto-migrate")
 	}
 
+	// Documents are unique per product and locale; drop the old per-product index
+	if db.Migrator().HasIndex(&postgres.SearchIndexModel{}, "idx_search_indices_product_id") {
+		if err := db.Migrator().DropIndex(&postgres.SearchIndexModel{}, "idx_search_indices_product_id"); err != nil {
+			log.Fatal().Err(err).Msg("failed to drop legacy search index")
+		}
+	}
+
+	// Full-text search needs unaccent and the search vectors of documents
+	// indexed before them
+	if err := postgres.MigrateTextSearch(context.Background(), db); err != nil {
+		log.Fatal().Err(err).Msg("failed to migrate full-text search")
+	}
+
+	// Typo-tolerant suggestions need pg_trgm and trigram indexes
+	if err := postgres.MigrateSuggestions(context.Background(), db); err != nil {
+		log.Fatal().Err(err).Msg("failed to migrate suggestions")
+	}
+
 	// Initialize NATS publisher
 	publisher, err := natsInfra.NewPublisher(cfg.NATS.URL)
 	if err != nil {
@@ -52,13 +76,53 @@ func main() {
 	// Initialize repositories
 	searchRepo := postgres.NewSearchRepo(db)
 
+	// Languages products are indexed and searched in
+	bundle := i18n.NewBundle()
+	bundle.SetupDefaults()
+
+	// Indexed products are read from the product catalog
+	catalog, err := product.NewCatalogClient(cfg.ProductGRPCAddr)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create product catalog client")
+	}
+	defer catalog.Close()
+
+	// Filterable attributes and categories are read from the product service
+	productClient := product.NewClient(cfg.ProductServiceURL)
+
 	// Initialize use cases
-	searchUC := usecase.NewSearchUseCase(searchRepo)
-	indexUC := usecase.NewIndexUseCase(searchRepo, publisher)
+	searchUC := usecase.NewSearchUseCase(searchRepo, bundle, productClient, productClient)
+	indexUC := usecase.NewIndexUseCase(searchRepo, publisher, bundle, catalog)
+
+	// "search reindex" rebuilds the product index from the catalog and
+	// swaps it in, while running instances keep serving the old one
+	if len(os.Args) > 1 && os.Args[1] == "reindex" {
+		runReindex(indexUC)
+		return
+	}
+
+	// Keep indexed products in line with the catalog
+	js, err := publisher.JetStream()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create JetStream context")
+	}
+	if err := natsInfra.StartCatalogSubscriber(events.NewSubscriber(js), indexUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to start catalog subscriber")
+	}
+
+	// Keep indexed ratings in line with the product service
+	if err := natsInfra.StartRatingSubscriber(publisher, indexUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to start rating subscriber")
+	}
+
+	// Make answered product questions searchable
+	if err := natsInfra.StartQuestionSubscriber(publisher, indexUC); err != nil {
+		log.Fatal().Err(err).Msg("failed to start question subscriber")
+	}
 
 	// Initialize HTTP handler and router
 	handler := httpAdapter.NewHandler(searchUC, indexUC)
-	router := httpAdapter.NewRouter(handler)
+	router := httpAdapter.NewRouter(handler, bundle)
 
 	// Start HTTP server
 	httpServer := &http.Server{
@@ -108,6 +172,25 @@ func main() {
 	log.Info().Msg("search service stopped")
 }
 
+// runReindex rebuilds the product index, stopping without touching the live
+// index when interrupted.
+func runReindex(indexUC *usecase.IndexUseCase) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	result, err := indexUC.Reindex(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("reindex failed")
+		os.Exit(1)
+	}
+	log.Info().
+		Int("products", result.Products).
+		Int("documents", result.Documents).
+		Dur("took", time.Since(start)).
+		Msg("reindex complete")
+}
+
 func setupLogger(level string) {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 
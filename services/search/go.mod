@@ -10,6 +10,7 @@ require (
 	github.com/lib/pq v1.10.9
 	github.com/nats-io/nats.go v1.49.0
 	github.com/rs/zerolog v1.34.0
+	github.com/southern-martin/ecommerce/pkg v0.0.0
 	google.golang.org/grpc v1.79.1
 	gorm.io/driver/postgres v1.5.9
 	gorm.io/gorm v1.31.1
@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/southern-martin/ecommerce/services/search/internal/domain"
 	"github.com/southern-martin/ecommerce/services/search/internal/usecase"
@@ -30,11 +31,33 @@ type SearchRequest struct {
 	SortOrder  string
 	Page       int32
 	PageSize   int32
+	Locale     string
+	// Facet filters; Attributes maps attribute slugs to their values.
+	SellerIDs  []string
+	Tags       []string
+	MinRating  float64
+	Attributes map[string][]string
 }
 
 type SearchResponse struct {
 	Results []*SearchResultProto
+	Facets  []*FacetProto
 	Total   int64
+	// DidYouMean is a correction of a query that found nothing, if any.
+	DidYouMean string
+}
+
+type FacetProto struct {
+	Name   string
+	Label  string
+	Values []*FacetValueProto
+}
+
+type FacetValueProto struct {
+	Value    string
+	Label    string
+	Count    int64
+	Selected bool
 }
 
 type SearchResultProto struct {
@@ -52,11 +75,16 @@ type SearchResultProto struct {
 	ReviewCount int32
 	InStock     bool
 	Score       float64
+	// NameHighlight and Snippet mark the matched words with <mark> tags.
+	NameHighlight string
+	Snippet       string
+	Locale        string
 }
 
 type SuggestRequest struct {
-	Query string
-	Limit int32
+	Query  string
+	Limit  int32
+	Locale string
 }
 
 type SuggestResponse struct {
@@ -64,9 +92,11 @@ type SuggestResponse struct {
 }
 
 type SuggestionProto struct {
-	Text      string
-	Type      string
-	ProductID string
+	Text       string
+	Type       string
+	ProductID  string
+	CategoryID string
+	Count      int64
 }
 
 // Server implements the SearchService gRPC interface.
@@ -95,6 +125,18 @@ func (s *Server) Search(ctx context.Context, req *SearchRequest) (*SearchRespons
 		SortOrder:  req.SortOrder,
 		Page:       int(req.Page),
 		PageSize:   int(req.PageSize),
+		Locale:     req.Locale,
+		SellerIDs:  req.SellerIDs,
+		Tags:       req.Tags,
+		MinRating:  req.MinRating,
+	}
+	slugs := make([]string, 0, len(req.Attributes))
+	for slug := range req.Attributes {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	for _, slug := range slugs {
+		filter.Attributes = append(filter.Attributes, domain.AttributeFilter{Slug: slug, Values: req.Attributes[slug]})
 	}
 
 	results, total, err := s.searchUC.Search(ctx, filter)
@@ -102,26 +144,50 @@ func (s *Server) Search(ctx context.Context, req *SearchRequest) (*SearchRespons
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	facets, err := s.searchUC.Facets(ctx, filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	resp := &SearchResponse{Total: total}
+	if total == 0 && req.Query != "" {
+		resp.DidYouMean = s.searchUC.DidYouMean(ctx, filter)
+	}
 	for _, r := range results {
 		resp.Results = append(resp.Results, &SearchResultProto{
-			ID:          r.ID,
-			ProductID:   r.ProductID,
-			Name:        r.Name,
-			Slug:        r.Slug,
-			Description: r.Description,
-			PriceCents:  r.PriceCents,
-			Currency:    r.Currency,
-			ImageURL:    r.ImageURL,
-			SellerID:    r.SellerID,
-			CategoryID:  r.CategoryID,
-			Rating:      r.Rating,
-			ReviewCount: int32(r.ReviewCount),
-			InStock:     r.InStock,
-			Score:       r.Score,
+			ID:            r.ID,
+			ProductID:     r.ProductID,
+			Name:          r.Name,
+			Slug:          r.Slug,
+			Description:   r.Description,
+			PriceCents:    r.PriceCents,
+			Currency:      r.Currency,
+			ImageURL:      r.ImageURL,
+			SellerID:      r.SellerID,
+			CategoryID:    r.CategoryID,
+			Rating:        r.Rating,
+			ReviewCount:   int32(r.ReviewCount),
+			InStock:       r.InStock,
+			Score:         r.Score,
+			NameHighlight: r.NameHighlight,
+			Snippet:       r.Snippet,
+			Locale:        r.Locale,
 		})
 	}
 
+	for _, f := range facets {
+		facet := &FacetProto{Name: f.Name, Label: f.Label}
+		for _, v := range f.Values {
+			facet.Values = append(facet.Values, &FacetValueProto{
+				Value:    v.Value,
+				Label:    v.Label,
+				Count:    v.Count,
+				Selected: v.Selected,
+			})
+		}
+		resp.Facets = append(resp.Facets, facet)
+	}
+
 	return resp, nil
 }
 
@@ -130,7 +196,7 @@ func (s *Server) Suggest(ctx context.Context, req *SuggestRequest) (*SuggestResp
 		return nil, status.Error(codes.InvalidArgument, "query is required")
 	}
 
-	suggestions, err := s.searchUC.Suggest(ctx, req.Query, int(req.Limit))
+	suggestions, err := s.searchUC.Suggest(ctx, req.Query, req.Locale, int(req.Limit))
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -138,9 +204,11 @@ func (s *Server) Suggest(ctx context.Context, req *SuggestRequest) (*SuggestResp
 	resp := &SuggestResponse{}
 	for _, s := range suggestions {
 		resp.Suggestions = append(resp.Suggestions, &SuggestionProto{
-			Text:      s.Text,
-			Type:      s.Type,
-			ProductID: s.ProductID,
+			Text:       s.Text,
+			Type:       s.Type,
+			ProductID:  s.ProductID,
+			CategoryID: s.CategoryID,
+			Count:      s.Count,
 		})
 	}
 
@@ -2,9 +2,12 @@ package http
 
 import (
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 	"github.com/southern-martin/ecommerce/services/search/internal/domain"
 	"github.com/southern-martin/ecommerce/services/search/internal/usecase"
 )
@@ -33,9 +36,15 @@ func (h *Handler) Search(c *gin.Context) {
 	filter := domain.SearchFilter{
 		Query:      c.Query("q"),
 		CategoryID: c.Query("category_id"),
-		SellerID:   c.Query("seller_id"),
+		SellerIDs:  queryValues(c, domain.FacetSeller),
+		Tags:       queryValues(c, domain.FacetTags),
 		SortBy:     c.Query("sort_by"),
 		SortOrder:  c.Query("sort_order"),
+		Locale:     i18n.GetLanguage(c),
+		Searcher:   c.GetHeader("X-User-ID"),
+	}
+	if filter.Searcher == "" {
+		filter.Searcher = c.ClientIP()
 	}
 
 	if v := c.Query("min_price"); v != "" {
@@ -50,6 +59,38 @@ func (h *Handler) Search(c *gin.Context) {
 		}
 	}
 
+	// A price range facet value, "2500-4999" or "50000-"
+	if v := c.Query(domain.FacetPrice); v != "" {
+		low, high, _ := strings.Cut(v, "-")
+		if parsed, err := strconv.ParseInt(low, 10, 64); err == nil && filter.MinPrice == 0 {
+			filter.MinPrice = parsed
+		}
+		if parsed, err := strconv.ParseInt(high, 10, 64); err == nil && filter.MaxPrice == 0 {
+			filter.MaxPrice = parsed
+		}
+	}
+
+	if v := c.Query(domain.FacetRating); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			filter.MinRating = parsed
+		}
+	}
+
+	// Attribute filters, e.g. attr.color=red,blue
+	var attrParams []string
+	for param := range c.Request.URL.Query() {
+		if strings.HasPrefix(param, domain.FacetAttributePrefix) {
+			attrParams = append(attrParams, param)
+		}
+	}
+	sort.Strings(attrParams)
+	for _, param := range attrParams {
+		filter.Attributes = append(filter.Attributes, domain.AttributeFilter{
+			Slug:   strings.TrimPrefix(param, domain.FacetAttributePrefix),
+			Values: queryValues(c, param),
+		})
+	}
+
 	if v := c.Query("in_stock"); v != "" {
 		if parsed, err := strconv.ParseBool(v); err == nil {
 			filter.InStock = &parsed
@@ -74,12 +115,41 @@ func (h *Handler) Search(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	facets, err := h.searchUC.Facets(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
+		return
+	}
+
+	response := gin.H{
 		"results":   results,
+		"facets":    facets,
 		"total":     total,
 		"page":      filter.Page,
 		"page_size": filter.PageSize,
-	})
+	}
+	if total == 0 && filter.Query != "" {
+		if correction := h.searchUC.DidYouMean(c.Request.Context(), filter); correction != "" {
+			response["did_you_mean"] = correction
+		}
+	}
+
+	c.Header("Content-Language", filter.Locale)
+	c.JSON(http.StatusOK, response)
+}
+
+// queryValues returns the values of a query parameter given repeatedly or
+// comma-separated.
+func queryValues(c *gin.Context, param string) []string {
+	var values []string
+	for _, v := range c.QueryArray(param) {
+		for _, value := range strings.Split(v, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
 }
 
 // Suggest handles GET /api/v1/search/suggest
@@ -97,12 +167,14 @@ func (h *Handler) Suggest(c *gin.Context) {
 		}
 	}
 
-	suggestions, err := h.searchUC.Suggest(c.Request.Context(), query, limit)
+	locale := i18n.GetLanguage(c)
+	suggestions, err := h.searchUC.Suggest(c.Request.Context(), query, locale, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "suggest failed"})
 		return
 	}
 
+	c.Header("Content-Language", locale)
 	c.JSON(http.StatusOK, gin.H{
 		"suggestions": suggestions,
 	})
@@ -111,6 +183,7 @@ func (h *Handler) Suggest(c *gin.Context) {
 // IndexProductRequest is the request body for indexing a product.
 type IndexProductRequest struct {
 	ProductID   string            `json:"product_id" binding:"required"`
+	Locale      string            `json:"locale"`
 	Name        string            `json:"name" binding:"required"`
 	Slug        string            `json:"slug"`
 	Description string            `json:"description"`
@@ -136,6 +209,7 @@ func (h *Handler) IndexProduct(c *gin.Context) {
 
 	idx := &domain.SearchIndex{
 		ProductID:   req.ProductID,
+		Locale:      req.Locale,
 		Name:        req.Name,
 		Slug:        req.Slug,
 		Description: req.Description,
@@ -159,10 +233,12 @@ func (h *Handler) IndexProduct(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message":    "product indexed successfully",
 		"product_id": req.ProductID,
+		"locale":     idx.Locale,
 	})
 }
 
-// DeleteProduct handles DELETE /api/v1/admin/search/index/:product_id
+// DeleteProduct handles DELETE /api/v1/admin/search/index/:product_id?locale=
+// Without a locale every localized document of the product is removed.
 func (h *Handler) DeleteProduct(c *gin.Context) {
 	productID := c.Param("product_id")
 	if productID == "" {
@@ -170,7 +246,7 @@ func (h *Handler) DeleteProduct(c *gin.Context) {
 		return
 	}
 
-	if err := h.indexUC.RemoveProduct(c.Request.Context(), productID); err != nil {
+	if err := h.indexUC.RemoveProduct(c.Request.Context(), productID, c.Query("locale")); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove product from index"})
 		return
 	}
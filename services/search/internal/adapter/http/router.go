@@ -2,14 +2,16 @@ package http
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 )
 
 // NewRouter creates and configures the Gin router with all search service routes.
-func NewRouter(handler *Handler) *gin.Engine {
+func NewRouter(handler *Handler, bundle *i18n.Bundle) *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.New()
 	router.Use(gin.Recovery())
 	router.Use(gin.Logger())
+	router.Use(i18n.GinMiddleware(bundle))
 
 	// Health check
 	router.GET("/health", handler.Health)
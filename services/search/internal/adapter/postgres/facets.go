@@ -0,0 +1,290 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/southern-martin/ecommerce/pkg/money"
+	"github.com/southern-martin/ecommerce/services/search/internal/domain"
+	"gorm.io/gorm"
+)
+
+// facetValueLimit is the number of most frequent values returned for the
+// tag, seller and attribute facets.
+const facetValueLimit = 20
+
+// priceBuckets are the lower bounds, in cents, of the price range facet's
+// ranges. Each range ends a cent below the next bound; the last one is
+// open-ended.
+var priceBuckets = []int64{0, 2500, 5000, 10000, 20000, 50000}
+
+// ratingBuckets are the minimum ratings of the rating facet's values.
+var ratingBuckets = []int{4, 3, 2, 1}
+
+// facetRow is a row of facet counts.
+type facetRow struct {
+	Key   string
+	Value string
+	Count int64
+}
+
+// Facets counts the results of a search by price range, rating, each of
+// the filterable attributes, tag and seller. Each dimension is counted
+// without its own filter, so that shoppers can widen it again.
+func (r *SearchRepo) Facets(ctx context.Context, filter domain.SearchFilter, attributes []domain.FilterableAttribute) ([]domain.Facet, error) {
+	locales := searchLocales(filter)
+	textQuery := parseTextQuery(filter.Query)
+	except := func(dimension string) *gorm.DB {
+		return r.filterQuery(ctx, filter, locales, textQuery, dimension)
+	}
+
+	var facets []domain.Facet
+	add := func(facet domain.Facet, err error) error {
+		if err != nil {
+			log.Error().Err(err).Str("facet", facet.Name).Msg("failed to count search facet")
+			return err
+		}
+		if len(facet.Values) > 0 {
+			facets = append(facets, facet)
+		}
+		return nil
+	}
+
+	if err := add(r.priceFacet(except(domain.FacetPrice), filter)); err != nil {
+		return nil, err
+	}
+	if err := add(r.ratingFacet(except(domain.FacetRating), filter)); err != nil {
+		return nil, err
+	}
+	attributeFacets, err := r.attributeFacets(except, filter, attributes)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to count attribute facets")
+		return nil, err
+	}
+	facets = append(facets, attributeFacets...)
+	if err := add(r.valueFacet(except(domain.FacetTags), domain.FacetTags, "Tags",
+		"CROSS JOIN LATERAL unnest(tags) AS facet(value)", "facet.value", filter.Tags)); err != nil {
+		return nil, err
+	}
+	if err := add(r.valueFacet(except(domain.FacetSeller), domain.FacetSeller, "Seller",
+		"", "nullif(seller_id, '')", filterSellers(filter))); err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// priceFacet counts results by price range. The ranges are labelled in the
+// currency most of the results are priced in.
+func (r *SearchRepo) priceFacet(query *gorm.DB, filter domain.SearchFilter) (domain.Facet, error) {
+	facet := domain.Facet{Name: domain.FacetPrice, Label: "Price"}
+
+	var bucket strings.Builder
+	bucket.WriteString("CASE")
+	for i := len(priceBuckets) - 1; i > 0; i-- {
+		fmt.Fprintf(&bucket, " WHEN price_cents >= %d THEN %d", priceBuckets[i], i)
+	}
+	bucket.WriteString(" ELSE 0 END")
+
+	var rows []facetRow
+	if err := query.Select(bucket.String() + " AS key, currency AS value, count(*) AS count").Group("key, currency").Scan(&rows).Error; err != nil {
+		return facet, err
+	}
+	counts := make(map[string]int64, len(rows))
+	currencies := make(map[string]int64)
+	for _, row := range rows {
+		counts[row.Key] += row.Count
+		currencies[row.Value] += row.Count
+	}
+	var currency string
+	for code, count := range currencies {
+		if count > currencies[currency] || (count == currencies[currency] && code < currency) {
+			currency = code
+		}
+	}
+
+	for i, low := range priceBuckets {
+		count := counts[strconv.Itoa(i)]
+		if count == 0 {
+			continue
+		}
+		value := domain.FacetValue{Count: count}
+		var high int64
+		if i+1 < len(priceBuckets) {
+			high = priceBuckets[i+1] - 1
+			value.Value = fmt.Sprintf("%d-%d", low, high)
+			value.Label = fmt.Sprintf("%s to %s", money.NewMoney(low, currency).Format(), money.NewMoney(high, currency).Format())
+		} else {
+			value.Value = fmt.Sprintf("%d-", low)
+			value.Label = fmt.Sprintf("%s and above", money.NewMoney(low, currency).Format())
+		}
+		value.Selected = filter.MinPrice == low && filter.MaxPrice == high && (low > 0 || high > 0)
+		facet.Values = append(facet.Values, value)
+	}
+	return facet, nil
+}
+
+// ratingFacet counts results rated at least each of the rating buckets.
+func (r *SearchRepo) ratingFacet(query *gorm.DB, filter domain.SearchFilter) (domain.Facet, error) {
+	facet := domain.Facet{Name: domain.FacetRating, Label: "Rating"}
+
+	var rows []facetRow
+	if err := query.Select("floor(rating)::int AS key, count(*) AS count").Group("key").Scan(&rows).Error; err != nil {
+		return facet, err
+	}
+
+	for _, stars := range ratingBuckets {
+		var count int64
+		for _, row := range rows {
+			if rowStars, _ := strconv.Atoi(row.Key); rowStars >= stars {
+				count += row.Count
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		facet.Values = append(facet.Values, domain.FacetValue{
+			Value:    strconv.Itoa(stars),
+			Label:    fmt.Sprintf("%d stars & up", stars),
+			Count:    count,
+			Selected: filter.MinRating == float64(stars),
+		})
+	}
+	return facet, nil
+}
+
+// attributeFacets counts results by the values of each filterable
+// attribute. Attributes not filtered by share one count; each filtered
+// attribute is counted without its own filter.
+func (r *SearchRepo) attributeFacets(except func(string) *gorm.DB, filter domain.SearchFilter, attributes []domain.FilterableAttribute) ([]domain.Facet, error) {
+	selected := make(map[string][]string, len(filter.Attributes))
+	for _, attr := range filter.Attributes {
+		if len(attr.Values) > 0 {
+			selected[attr.Slug] = attr.Values
+		}
+	}
+
+	// Documents key attribute values by slug or name
+	byKey := make(map[string]string)
+	var keys []string
+	for _, attr := range attributes {
+		if _, ok := selected[attr.Slug]; ok {
+			continue
+		}
+		for _, key := range []string{attr.Slug, attr.Name} {
+			key = strings.ToLower(key)
+			if _, ok := byKey[key]; !ok {
+				byKey[key] = attr.Slug
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	counts := make(map[string][]facetRow)
+	if len(keys) > 0 {
+		var rows []facetRow
+		if err := except("").
+			Joins("CROSS JOIN LATERAL jsonb_each_text(attributes) AS facet(key, value)").
+			Where("lower(facet.key) IN ? AND facet.value <> ''", keys).
+			Select("lower(facet.key) AS key, facet.value AS value, count(*) AS count").
+			Group("lower(facet.key), facet.value").
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			slug := byKey[row.Key]
+			counts[slug] = append(counts[slug], row)
+		}
+	}
+
+	var facets []domain.Facet
+	for _, attr := range attributes {
+		var rows []facetRow
+		if _, ok := selected[attr.Slug]; ok {
+			if err := except(domain.FacetAttributePrefix+attr.Slug).
+				Select("coalesce(attributes->>?, attributes->>?) AS value, count(*) AS count", attr.Slug, attr.Name).
+				Where("coalesce(attributes->>?, attributes->>?) <> ''", attr.Slug, attr.Name).
+				Group("value").
+				Scan(&rows).Error; err != nil {
+				return nil, err
+			}
+		} else {
+			rows = counts[attr.Slug]
+		}
+
+		facet := domain.Facet{
+			Name:   domain.FacetAttributePrefix + attr.Slug,
+			Label:  attr.Name,
+			Values: topFacetValues(rows, selected[attr.Slug]),
+		}
+		for i := range facet.Values {
+			if attr.Unit != "" {
+				facet.Values[i].Label = facet.Values[i].Value + " " + attr.Unit
+			}
+		}
+		if len(facet.Values) > 0 {
+			facets = append(facets, facet)
+		}
+	}
+	return facets, nil
+}
+
+// valueFacet counts results by the values of expr, joining join first if
+// set.
+func (r *SearchRepo) valueFacet(query *gorm.DB, name, label, join, expr string, selected []string) (domain.Facet, error) {
+	facet := domain.Facet{Name: name, Label: label}
+	if join != "" {
+		query = query.Joins(join)
+	}
+
+	var rows []facetRow
+	if err := query.
+		Select(expr + " AS value, count(*) AS count").
+		Where(expr + " IS NOT NULL").
+		Group("value").
+		Scan(&rows).Error; err != nil {
+		return facet, err
+	}
+	facet.Values = topFacetValues(rows, selected)
+	return facet, nil
+}
+
+// topFacetValues returns the most frequent values of rows, most frequent
+// first, and marks the selected ones. Selected values are always included.
+func topFacetValues(rows []facetRow, selected []string) []domain.FacetValue {
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Value] += row.Count
+	}
+	isSelected := make(map[string]bool, len(selected))
+	for _, value := range selected {
+		isSelected[value] = true
+	}
+
+	values := make([]domain.FacetValue, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, domain.FacetValue{
+			Value:    value,
+			Label:    value,
+			Count:    count,
+			Selected: isSelected[value],
+		})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+
+	top := values[:0]
+	for i, value := range values {
+		if i < facetValueLimit || value.Selected {
+			top = append(top, value)
+		}
+	}
+	return top
+}
@@ -37,7 +37,8 @@ func (a *AttributesJSON) Scan(value interface{}) error {
 // SearchIndexModel is the GORM model for the search_indices table.
 type SearchIndexModel struct {
 	ID          string         `gorm:"type:uuid;primaryKey"`
-	ProductID   string         `gorm:"type:varchar(255);uniqueIndex;not null"`
+	ProductID   string         `gorm:"type:varchar(255);uniqueIndex:idx_search_indices_product_locale;not null"`
+	Locale      string         `gorm:"type:varchar(10);uniqueIndex:idx_search_indices_product_locale;not null;default:'en'"`
 	Name        string         `gorm:"type:varchar(500);not null"`
 	Slug        string         `gorm:"type:varchar(500)"`
 	Description string         `gorm:"type:text"`
@@ -51,8 +52,11 @@ type SearchIndexModel struct {
 	InStock     bool           `gorm:"default:true;index"`
 	Tags        pq.StringArray `gorm:"type:text[]"`
 	Attributes  AttributesJSON `gorm:"type:jsonb"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// SearchVector is computed by the database from the text fields when
+	// the document is indexed.
+	SearchVector string `gorm:"type:tsvector;index:idx_search_indices_search_vector,type:gin;->:false;<-:false"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 // TableName returns the table name for SearchIndexModel.
@@ -73,6 +77,7 @@ func (m *SearchIndexModel) ToDomain() *domain.SearchIndex {
 	return &domain.SearchIndex{
 		ID:          m.ID,
 		ProductID:   m.ProductID,
+		Locale:      m.Locale,
 		Name:        m.Name,
 		Slug:        m.Slug,
 		Description: m.Description,
@@ -104,6 +109,7 @@ func ToModel(idx *domain.SearchIndex) *SearchIndexModel {
 	return &SearchIndexModel{
 		ID:          idx.ID,
 		ProductID:   idx.ProductID,
+		Locale:      idx.Locale,
 		Name:        idx.Name,
 		Slug:        idx.Slug,
 		Description: idx.Description,
@@ -121,3 +127,53 @@ func ToModel(idx *domain.SearchIndex) *SearchIndexModel {
 		UpdatedAt:   idx.UpdatedAt,
 	}
 }
+
+// SearchQuestionModel is the GORM model for the search_questions table.
+// Answers are stored newline-separated.
+type SearchQuestionModel struct {
+	QuestionID string `gorm:"type:varchar(255);primaryKey"`
+	ProductID  string `gorm:"type:varchar(255);index;not null"`
+	Question   string `gorm:"type:text;not null"`
+	Answers    string `gorm:"type:text"`
+	// SearchVector is computed by the database when the question is
+	// indexed.
+	SearchVector string `gorm:"type:tsvector;index:idx_search_questions_search_vector,type:gin;->:false;<-:false"`
+	UpdatedAt    time.Time
+}
+
+// TableName returns the table name for SearchQuestionModel.
+func (SearchQuestionModel) TableName() string {
+	return "search_questions"
+}
+
+// SearchQueryModel is the GORM model for the search_queries table, the log
+// of searches autocomplete and corrections learn popular queries from.
+// Queries are stored normalized, one row per query and locale.
+type SearchQueryModel struct {
+	Query          string    `gorm:"type:varchar(200);primaryKey"`
+	Locale         string    `gorm:"type:varchar(10);primaryKey"`
+	SearchCount    int64     `gorm:"not null;default:0"`
+	SearcherCount  int64     `gorm:"not null;default:0"`
+	ResultCount    int64     `gorm:"not null;default:0"`
+	LastSearchedAt time.Time `gorm:"index"`
+}
+
+// TableName returns the table name for SearchQueryModel.
+func (SearchQueryModel) TableName() string {
+	return "search_queries"
+}
+
+// SearchQuerySearcherModel is the GORM model for the
+// search_query_searchers table, which records who searched each query, by
+// hash only, to count the different shoppers searching it.
+type SearchQuerySearcherModel struct {
+	Query        string    `gorm:"type:varchar(200);primaryKey"`
+	Locale       string    `gorm:"type:varchar(10);primaryKey"`
+	SearcherHash string    `gorm:"type:varchar(64);primaryKey"`
+	SearchedAt   time.Time `gorm:"index"`
+}
+
+// TableName returns the table name for SearchQuerySearcherModel.
+func (SearchQuerySearcherModel) TableName() string {
+	return "search_query_searchers"
+}
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"github.com/southern-martin/ecommerce/services/search/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rebuildTable is where the index is rebuilt before it replaces the live
+// search_indices table.
+const rebuildTable = "search_indices_rebuild"
+
+// tombstoneTable records the documents deleted from the live index while it
+// is being rebuilt, so that the rebuild does not add them back from the
+// older catalog snapshot it reads.
+const tombstoneTable = "search_indices_rebuild_tombstones"
+
+// mirrorFunctionSQL and mirrorTriggerSQL create the trigger that copies
+// writes to the live index into the rebuild, so that products indexed or
+// removed while the index is being rebuilt are not lost when it is swapped
+// in. Removals also leave a tombstone, which indexing the document again
+// clears.
+const mirrorFunctionSQL = `
+CREATE FUNCTION search_indices_mirror() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		DELETE FROM search_indices_rebuild WHERE product_id = OLD.product_id AND locale = OLD.locale;
+	END IF;
+	IF TG_OP = 'DELETE' THEN
+		INSERT INTO search_indices_rebuild_tombstones (product_id, locale) VALUES (OLD.product_id, OLD.locale)
+			ON CONFLICT DO NOTHING;
+	END IF;
+	IF TG_OP IN ('INSERT', 'UPDATE') THEN
+		DELETE FROM search_indices_rebuild_tombstones WHERE product_id = NEW.product_id AND locale = NEW.locale;
+		DELETE FROM search_indices_rebuild WHERE product_id = NEW.product_id AND locale = NEW.locale;
+		INSERT INTO search_indices_rebuild SELECT (NEW).*;
+	END IF;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`
+
+const mirrorTriggerSQL = `
+CREATE TRIGGER search_indices_mirror
+	AFTER INSERT OR UPDATE OR DELETE ON search_indices
+	FOR EACH ROW EXECUTE FUNCTION search_indices_mirror()`
+
+// BeginRebuild creates an empty copy of the search_indices table, with its
+// indexes, and a tombstone table, and starts mirroring writes to the live
+// table into them.
+func (r *SearchRepo) BeginRebuild(ctx context.Context) error {
+	if err := r.AbortRebuild(ctx); err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("CREATE TABLE " + rebuildTable + " (LIKE search_indices INCLUDING ALL)").Error; err != nil {
+			return fmt.Errorf("failed to create rebuild table: %w", err)
+		}
+		if err := tx.Exec("CREATE TABLE " + tombstoneTable + " (product_id varchar(255) NOT NULL, locale varchar(10) NOT NULL, PRIMARY KEY (product_id, locale))").Error; err != nil {
+			return fmt.Errorf("failed to create tombstone table: %w", err)
+		}
+		for _, stmt := range []string{mirrorFunctionSQL, mirrorTriggerSQL} {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to mirror the live index: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// IndexRebuild adds a product's document in its locale to the rebuild,
+// unless the document was mirrored from the live index or deleted from it
+// in the meantime.
+func (r *SearchRepo) IndexRebuild(ctx context.Context, idx *domain.SearchIndex) error {
+	model := ToModel(idx)
+	if model.ID == "" {
+		model.ID = uuid.New().String()
+	}
+
+	db := r.db.WithContext(ctx)
+	var deleted bool
+	if err := db.Raw("SELECT EXISTS (SELECT 1 FROM "+tombstoneTable+" WHERE product_id = ? AND locale = ?)", model.ProductID, model.Locale).
+		Scan(&deleted).Error; err != nil {
+		return fmt.Errorf("failed to check for deleted document: %w", err)
+	}
+	if deleted {
+		return nil
+	}
+
+	result := db.Table(rebuildTable).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "product_id"}, {Name: "locale"}}, DoNothing: true}).
+		Create(model)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("product_id", idx.ProductID).Msg("failed to add document to rebuild")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	return db.Exec("UPDATE "+rebuildTable+" SET search_vector = "+productVectorSQL+" WHERE id = @id",
+		map[string]interface{}{"config": textSearchConfig(model.Locale), "id": model.ID}).Error
+}
+
+// SwapRebuild replaces search_indices with the rebuild in one transaction
+// and gives the rebuild's indexes the names of the live table's, so that
+// migrations keep recognizing them. Searches wait for the swap rather than
+// fail.
+func (r *SearchRepo) SwapRebuild(ctx context.Context) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE search_indices IN ACCESS EXCLUSIVE MODE").Error; err != nil {
+			return fmt.Errorf("failed to lock search index: %w", err)
+		}
+
+		liveNames, err := indexNames(tx, "search_indices")
+		if err != nil {
+			return err
+		}
+		rebuildNames, err := indexNames(tx, rebuildTable)
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range []string{
+			"DROP TABLE search_indices",
+			"DROP FUNCTION search_indices_mirror()",
+			"DROP TABLE " + tombstoneTable,
+			"ALTER TABLE " + rebuildTable + " RENAME TO search_indices",
+		} {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to swap search index: %w", err)
+			}
+		}
+
+		for definition, name := range rebuildNames {
+			liveName, ok := liveNames[definition]
+			if !ok || liveName == name {
+				continue
+			}
+			if err := tx.Exec("ALTER INDEX " + pq.QuoteIdentifier(name) + " RENAME TO " + pq.QuoteIdentifier(liveName)).Error; err != nil {
+				return fmt.Errorf("failed to rename index %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// AbortRebuild stops mirroring the live index and drops the rebuild and its
+// tombstones.
+func (r *SearchRepo) AbortRebuild(ctx context.Context) error {
+	db := r.db.WithContext(ctx)
+	for _, stmt := range []string{
+		"DROP TRIGGER IF EXISTS search_indices_mirror ON search_indices",
+		"DROP FUNCTION IF EXISTS search_indices_mirror()",
+		"DROP TABLE IF EXISTS " + rebuildTable,
+		"DROP TABLE IF EXISTS " + tombstoneTable,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to drop rebuild: %w", err)
+		}
+	}
+	return nil
+}
+
+// indexNames maps the definitions of a table's indexes, without their
+// names and table, to their names.
+func indexNames(tx *gorm.DB, table string) (map[string]string, error) {
+	var rows []struct {
+		IndexName string
+		IndexDef  string
+	}
+	if err := tx.Raw("SELECT indexname AS index_name, indexdef AS index_def FROM pg_indexes WHERE schemaname = current_schema() AND tablename = ?", table).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list indexes of %s: %w", table, err)
+	}
+
+	names := make(map[string]string, len(rows))
+	for _, row := range rows {
+		// e.g. CREATE UNIQUE INDEX name ON public.table USING btree (product_id, locale)
+		_, method, ok := strings.Cut(row.IndexDef, " USING ")
+		if !ok {
+			continue
+		}
+		definition := method
+		if strings.HasPrefix(row.IndexDef, "CREATE UNIQUE ") {
+			definition = "UNIQUE " + method
+		}
+		names[definition] = row.IndexName
+	}
+	return names, nil
+}
@@ -3,9 +3,13 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 	"github.com/southern-martin/ecommerce/services/search/internal/domain"
 	"gorm.io/gorm"
 )
@@ -20,7 +24,7 @@ func NewSearchRepo(db *gorm.DB) *SearchRepo {
 	return &SearchRepo{db: db}
 }
 
-// Index creates or updates a product in the search index (upsert).
+// Index creates or updates a product's document in its locale (upsert).
 func (r *SearchRepo) Index(ctx context.Context, idx *domain.SearchIndex) error {
 	model := ToModel(idx)
 	if model.ID == "" {
@@ -29,7 +33,7 @@ func (r *SearchRepo) Index(ctx context.Context, idx *domain.SearchIndex) error {
 
 	// Upsert: try to find existing by product_id, then save
 	var existing SearchIndexModel
-	result := r.db.WithContext(ctx).Where("product_id = ?", model.ProductID).First(&existing)
+	result := r.db.WithContext(ctx).Where("product_id = ? AND locale = ?", model.ProductID, model.Locale).First(&existing)
 	if result.Error == nil {
 		// Update existing record
 		model.ID = existing.ID
@@ -46,13 +50,25 @@ func (r *SearchRepo) Index(ctx context.Context, idx *domain.SearchIndex) error {
 		}
 	}
 
-	log.Info().Str("product_id", idx.ProductID).Msg("product indexed")
+	// Compute the search vector with the stemming of the document's language
+	if err := r.db.WithContext(ctx).Exec("UPDATE search_indices SET search_vector = "+productVectorSQL+" WHERE id = @id",
+		map[string]interface{}{"config": textSearchConfig(model.Locale), "id": model.ID}).Error; err != nil {
+		log.Error().Err(err).Str("product_id", idx.ProductID).Msg("failed to update search vector")
+		return err
+	}
+
+	log.Info().Str("product_id", idx.ProductID).Str("locale", idx.Locale).Msg("product indexed")
 	return nil
 }
 
-// Delete removes a product from the search index by product ID.
-func (r *SearchRepo) Delete(ctx context.Context, productID string) error {
-	result := r.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&SearchIndexModel{})
+// Delete removes a product from the search index by product ID, in one
+// locale or, when locale is empty, in all of them.
+func (r *SearchRepo) Delete(ctx context.Context, productID, locale string) error {
+	query := r.db.WithContext(ctx).Where("product_id = ?", productID)
+	if locale != "" {
+		query = query.Where("locale = ?", locale)
+	}
+	result := query.Delete(&SearchIndexModel{})
 	if result.Error != nil {
 		log.Error().Err(result.Error).Str("product_id", productID).Msg("failed to delete from search index")
 		return result.Error
@@ -61,38 +77,78 @@ func (r *SearchRepo) Delete(ctx context.Context, productID string) error {
 	return nil
 }
 
-// Search performs a full-text search using ILIKE on name and description.
-func (r *SearchRepo) Search(ctx context.Context, filter domain.SearchFilter) ([]domain.SearchResult, int64, error) {
-	query := r.db.WithContext(ctx).Model(&SearchIndexModel{})
-
-	// Text search using ILIKE
-	if filter.Query != "" {
-		pattern := fmt.Sprintf("%%%s%%", filter.Query)
-		query = query.Where("name ILIKE ? OR description ILIKE ?", pattern, pattern)
+// ProductLocales lists the locales a product is indexed in.
+func (r *SearchRepo) ProductLocales(ctx context.Context, productID string) ([]string, error) {
+	var locales []string
+	if err := r.db.WithContext(ctx).Model(&SearchIndexModel{}).Where("product_id = ?", productID).Pluck("locale", &locales).Error; err != nil {
+		log.Error().Err(err).Str("product_id", productID).Msg("failed to list indexed locales")
+		return nil, err
 	}
+	return locales, nil
+}
 
-	// Category filter
-	if filter.CategoryID != "" {
-		query = query.Where("category_id = ?", filter.CategoryID)
+// UpdateRating sets the rating of an indexed product.
+func (r *SearchRepo) UpdateRating(ctx context.Context, productID string, rating float64, reviewCount int) error {
+	result := r.db.WithContext(ctx).Model(&SearchIndexModel{}).
+		Where("product_id = ?", productID).
+		Updates(map[string]interface{}{
+			"rating":       rating,
+			"review_count": reviewCount,
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("product_id", productID).Msg("failed to update search index rating")
+		return result.Error
 	}
+	return nil
+}
 
-	// Price range filters
-	if filter.MinPrice > 0 {
-		query = query.Where("price_cents >= ?", filter.MinPrice)
+// IndexQuestion creates or replaces an answered question's document.
+func (r *SearchRepo) IndexQuestion(ctx context.Context, doc *domain.QuestionDocument) error {
+	model := &SearchQuestionModel{
+		QuestionID: doc.QuestionID,
+		ProductID:  doc.ProductID,
+		Question:   doc.Question,
+		Answers:    strings.Join(doc.Answers, "\n"),
+		UpdatedAt:  doc.UpdatedAt,
 	}
-	if filter.MaxPrice > 0 {
-		query = query.Where("price_cents <= ?", filter.MaxPrice)
+	if err := r.db.WithContext(ctx).Save(model).Error; err != nil {
+		log.Error().Err(err).Str("question_id", doc.QuestionID).Msg("failed to index question")
+		return err
 	}
-
-	// In-stock filter
-	if filter.InStock != nil {
-		query = query.Where("in_stock = ?", *filter.InStock)
+	if err := r.db.WithContext(ctx).Exec("UPDATE search_questions SET search_vector = "+questionVectorSQL+" WHERE question_id = @id",
+		map[string]interface{}{"config": questionSearchConfig, "id": doc.QuestionID}).Error; err != nil {
+		log.Error().Err(err).Str("question_id", doc.QuestionID).Msg("failed to update question search vector")
+		return err
 	}
+	return nil
+}
 
-	// Seller filter
-	if filter.SellerID != "" {
-		query = query.Where("seller_id = ?", filter.SellerID)
+// DeleteQuestion removes a question's document.
+func (r *SearchRepo) DeleteQuestion(ctx context.Context, questionID string) error {
+	if err := r.db.WithContext(ctx).Where("question_id = ?", questionID).Delete(&SearchQuestionModel{}).Error; err != nil {
+		log.Error().Err(err).Str("question_id", questionID).Msg("failed to delete indexed question")
+		return err
 	}
+	return nil
+}
+
+// searchRow is a search result row: a document with its relevance to the
+// query and the highlighted parts of its text.
+type searchRow struct {
+	SearchIndexModel
+	Score         float64
+	NameHighlight string
+	Snippet       string
+}
+
+// Search performs a full-text search over the documents' weighted search
+// vectors and the product's answered questions, ranking results by
+// relevance. See parseTextQuery for the query syntax.
+func (r *SearchRepo) Search(ctx context.Context, filter domain.SearchFilter) ([]domain.SearchResult, int64, error) {
+	locales := searchLocales(filter)
+	textQuery := parseTextQuery(filter.Query)
+	query := r.filterQuery(ctx, filter, locales, textQuery, "")
 
 	// Count total results
 	var total int64
@@ -101,8 +157,21 @@ func (r *SearchRepo) Search(ctx context.Context, filter domain.SearchFilter) ([]
 		return nil, 0, err
 	}
 
-	// Sorting
+	// Relevance and highlights of the matched text
+	if len(textQuery) > 0 {
+		rank, rankArgs := textQuery.perLocaleSQL(locales, "ts_rank(search_vector, %[1]s, 32)")
+		name, nameArgs := textQuery.perLocaleSQL(locales, "ts_headline(%[2]s, name, %[1]s, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>')")
+		snippet, snippetArgs := textQuery.perLocaleSQL(locales, "ts_headline(%[2]s, coalesce(description, ''), %[1]s, '"+headlineOptions+"')")
+
+		args := append(append(rankArgs, nameArgs...), snippetArgs...)
+		query = query.Select("search_indices.*, coalesce("+rank+", 0) AS score, "+name+" AS name_highlight, "+snippet+" AS snippet", args...)
+	}
+
+	// Sorting: by relevance when searching for text, newest first otherwise
 	orderClause := "created_at DESC"
+	if len(textQuery) > 0 {
+		orderClause = "score DESC, rating DESC"
+	}
 	if filter.SortBy != "" {
 		direction := "ASC"
 		if filter.SortOrder == "desc" {
@@ -117,6 +186,10 @@ func (r *SearchRepo) Search(ctx context.Context, filter domain.SearchFilter) ([]
 			orderClause = fmt.Sprintf("name %s", direction)
 		case "created_at":
 			orderClause = fmt.Sprintf("created_at %s", direction)
+		case "relevance":
+			if len(textQuery) > 0 {
+				orderClause = fmt.Sprintf("score %s", direction)
+			}
 		}
 	}
 	query = query.Order(orderClause)
@@ -137,65 +210,134 @@ func (r *SearchRepo) Search(ctx context.Context, filter domain.SearchFilter) ([]
 	query = query.Offset(offset).Limit(pageSize)
 
 	// Execute query
-	var models []SearchIndexModel
-	if err := query.Find(&models).Error; err != nil {
+	var rows []searchRow
+	if err := query.Find(&rows).Error; err != nil {
 		log.Error().Err(err).Msg("failed to execute search query")
 		return nil, 0, err
 	}
 
 	// Convert to domain results
-	results := make([]domain.SearchResult, len(models))
-	for i, m := range models {
+	results := make([]domain.SearchResult, len(rows))
+	for i, row := range rows {
+		m := row.SearchIndexModel
+		score := row.Score
+		if len(textQuery) == 0 {
+			score = 1.0
+		}
 		results[i] = domain.SearchResult{
-			ID:          m.ID,
-			ProductID:   m.ProductID,
-			Name:        m.Name,
-			Slug:        m.Slug,
-			Description: m.Description,
-			PriceCents:  m.PriceCents,
-			Currency:    m.Currency,
-			ImageURL:    m.ImageURL,
-			SellerID:    m.SellerID,
-			CategoryID:  m.CategoryID,
-			Rating:      m.Rating,
-			ReviewCount: m.ReviewCount,
-			InStock:     m.InStock,
-			Score:       1.0,
-			CreatedAt:   m.CreatedAt,
+			ID:            m.ID,
+			ProductID:     m.ProductID,
+			Name:          m.Name,
+			Slug:          m.Slug,
+			Description:   m.Description,
+			PriceCents:    m.PriceCents,
+			Currency:      m.Currency,
+			ImageURL:      m.ImageURL,
+			SellerID:      m.SellerID,
+			CategoryID:    m.CategoryID,
+			Rating:        m.Rating,
+			ReviewCount:   m.ReviewCount,
+			InStock:       m.InStock,
+			Score:         score,
+			NameHighlight: row.NameHighlight,
+			Snippet:       row.Snippet,
+			Locale:        m.Locale,
+			CreatedAt:     m.CreatedAt,
 		}
 	}
 
 	return results, total, nil
 }
 
-// Suggest returns search suggestions matching the query prefix.
-func (r *SearchRepo) Suggest(ctx context.Context, query string, limit int) ([]domain.SearchSuggestion, error) {
-	if limit <= 0 {
-		limit = 10
+// searchLocales returns the locale fallback chain of a search, defaulting
+// to the default language.
+func searchLocales(filter domain.SearchFilter) []string {
+	if len(filter.Locales) == 0 {
+		return []string{i18n.DefaultLanguage}
 	}
-	if limit > 50 {
-		limit = 50
+	return filter.Locales
+}
+
+// filterQuery selects the documents matching a search's text and filters,
+// except the filter of the facet dimension except, if any.
+func (r *SearchRepo) filterQuery(ctx context.Context, filter domain.SearchFilter, locales []string, textQuery textQuery, except string) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&SearchIndexModel{}).Where(r.localeScope(locales))
+
+	// Full-text search with each document's language
+	if len(textQuery) > 0 {
+		match, matchArgs := textQuery.matchSQL(locales)
+		questionQuery, questionArgs := textQuery.sql(questionSearchConfig)
+		questions := r.db.Model(&SearchQuestionModel{}).Select("product_id").
+			Where("search_vector @@ "+questionQuery, questionArgs...)
+		query = query.Where("("+match+" OR product_id IN (?))", append(matchArgs, questions)...)
 	}
 
-	pattern := fmt.Sprintf("%%%s%%", query)
-	var models []SearchIndexModel
-	if err := r.db.WithContext(ctx).
-		Where("name ILIKE ?", pattern).
-		Order("rating DESC").
-		Limit(limit).
-		Find(&models).Error; err != nil {
-		log.Error().Err(err).Str("query", query).Msg("failed to get suggestions")
-		return nil, err
+	// Category filter
+	if filter.CategoryID != "" {
+		query = query.Where("category_id = ?", filter.CategoryID)
 	}
 
-	suggestions := make([]domain.SearchSuggestion, len(models))
-	for i, m := range models {
-		suggestions[i] = domain.SearchSuggestion{
-			Text:      m.Name,
-			Type:      "product",
-			ProductID: m.ProductID,
+	// In-stock filter
+	if filter.InStock != nil {
+		query = query.Where("in_stock = ?", *filter.InStock)
+	}
+
+	// Price range filters
+	if except != domain.FacetPrice {
+		if filter.MinPrice > 0 {
+			query = query.Where("price_cents >= ?", filter.MinPrice)
+		}
+		if filter.MaxPrice > 0 {
+			query = query.Where("price_cents <= ?", filter.MaxPrice)
 		}
 	}
 
-	return suggestions, nil
+	// Seller filter
+	if sellers := filterSellers(filter); len(sellers) > 0 && except != domain.FacetSeller {
+		query = query.Where("seller_id IN ?", sellers)
+	}
+
+	// Tag filter, matching any of the tags
+	if len(filter.Tags) > 0 && except != domain.FacetTags {
+		query = query.Where("tags && ?", pq.StringArray(filter.Tags))
+	}
+
+	// Rating filter
+	if filter.MinRating > 0 && except != domain.FacetRating {
+		query = query.Where("rating >= ?", filter.MinRating)
+	}
+
+	// Attribute filters, values keyed by the attribute's slug or name
+	for _, attr := range filter.Attributes {
+		if len(attr.Values) == 0 || except == domain.FacetAttributePrefix+attr.Slug {
+			continue
+		}
+		query = query.Where("coalesce(attributes->>?, attributes->>?) IN ?", attr.Slug, attr.Name, attr.Values)
+	}
+
+	return query
+}
+
+// filterSellers returns the sellers a search is restricted to.
+func filterSellers(filter domain.SearchFilter) []string {
+	sellers := filter.SellerIDs
+	if filter.SellerID != "" {
+		sellers = append([]string{filter.SellerID}, sellers...)
+	}
+	return sellers
+}
+
+// localeScope selects one document per product along a locale fallback
+// chain: a document in a later locale only matches when the product has
+// none in an earlier one. Without locales only the default locale matches.
+func (r *SearchRepo) localeScope(locales []string) *gorm.DB {
+	if len(locales) == 0 {
+		locales = []string{i18n.DefaultLanguage}
+	}
+	scope := r.db.Where("locale = ?", locales[0])
+	for i := 1; i < len(locales); i++ {
+		covered := r.db.Model(&SearchIndexModel{}).Select("product_id").Where("locale IN ?", locales[:i])
+		scope = scope.Or("locale = ? AND product_id NOT IN (?)", locales[i], covered)
+	}
+	return scope
 }
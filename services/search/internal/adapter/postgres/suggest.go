@@ -0,0 +1,287 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"github.com/southern-martin/ecommerce/services/search/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Popular queries are learned from searches made by at least
+// popularQueryMinSearchers different shoppers, last within
+// popularQueryWindow, so that one-off (and possibly personal) searches are
+// never suggested to other shoppers, however often one shopper repeats them.
+const (
+	popularQueryMinSearchers = 3
+	popularQueryWindow       = 90 * 24 * time.Hour
+)
+
+// correctionMinSimilarity is the trigram similarity a word must have to a
+// typed word to be suggested as its correction.
+const correctionMinSimilarity = 0.4
+
+// correctionCandidateNames is the number of product names whose words are
+// considered as corrections of a typed word.
+const correctionCandidateNames = 200
+
+// brandSQL is a document's brand, keyed by the brand attribute's slug or,
+// in documents indexed before attributes were keyed by slug, its name.
+const brandSQL = "coalesce(attributes->>'brand', attributes->>'Brand')"
+
+// MigrateSuggestions installs pg_trgm and the trigram indexes typo-tolerant
+// suggestions use.
+func MigrateSuggestions(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(ctx)
+	for _, stmt := range []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE INDEX IF NOT EXISTS idx_search_indices_name_trgm ON search_indices USING gin (lower(name) gin_trgm_ops)",
+		"CREATE INDEX IF NOT EXISTS idx_search_queries_query_trgm ON search_queries USING gin (query gin_trgm_ops)",
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to migrate suggestions: %w", err)
+		}
+	}
+	return nil
+}
+
+// suggestionRow is a suggestion with its similarity to the typed query.
+type suggestionRow struct {
+	Text       string
+	ProductID  string
+	CategoryID string
+	Score      float64
+	Count      int64
+}
+
+// Suggest returns the products whose names contain query or a word
+// resembling it, most similar first.
+func (r *SearchRepo) Suggest(ctx context.Context, query string, locales []string, limit int) ([]domain.SearchSuggestion, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+	query = strings.ToLower(query)
+
+	var rows []suggestionRow
+	if err := r.db.WithContext(ctx).Model(&SearchIndexModel{}).
+		Where(r.localeScope(locales)).
+		Where("(lower(name) LIKE ? OR ? <% lower(name))", "%"+escapeLike(query)+"%", query).
+		Select("name AS text, product_id, word_similarity(?, lower(name)) AS score", query).
+		Order("score DESC, rating DESC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Str("query", query).Msg("failed to get suggestions")
+		return nil, err
+	}
+
+	suggestions := make([]domain.SearchSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = domain.SearchSuggestion{
+			Text:      row.Text,
+			Type:      domain.SuggestionProduct,
+			ProductID: row.ProductID,
+		}
+	}
+	return suggestions, nil
+}
+
+// SuggestQueries returns popular queries in locale that had results and
+// start with query or resemble it, those starting with it first.
+func (r *SearchRepo) SuggestQueries(ctx context.Context, query, locale string, limit int) ([]domain.SearchSuggestion, error) {
+	prefix := escapeLike(query) + "%"
+
+	var rows []suggestionRow
+	if err := r.popularQueries(ctx, locale).
+		Where("query <> ? AND (query LIKE ? OR ? <% query)", query, prefix, query).
+		Select("query AS text, search_count AS count, query LIKE ? AS prefix_match", prefix).
+		Order("prefix_match DESC, count DESC, text").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Str("query", query).Msg("failed to get query suggestions")
+		return nil, err
+	}
+
+	suggestions := make([]domain.SearchSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = domain.SearchSuggestion{Text: row.Text, Type: domain.SuggestionQuery}
+	}
+	return suggestions, nil
+}
+
+// SuggestCategories returns the categories whose names contain a word
+// starting with or resembling query, with the number of products indexed in
+// them. Categories without products are left out.
+func (r *SearchRepo) SuggestCategories(ctx context.Context, query string, categories []domain.Category, locales []string, limit int) ([]domain.SearchSuggestion, error) {
+	if len(categories) == 0 {
+		return nil, nil
+	}
+	query = strings.ToLower(query)
+	ids := make(pq.StringArray, len(categories))
+	names := make(pq.StringArray, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID
+		names[i] = c.Name
+	}
+
+	var rows []suggestionRow
+	if err := r.db.WithContext(ctx).
+		Table("unnest(CAST(? AS text[]), CAST(? AS text[])) AS category(id, name)", ids, names).
+		Joins("JOIN search_indices ON search_indices.category_id = category.id").
+		Where(r.localeScope(locales)).
+		Where("(lower(category.name) LIKE ? OR lower(category.name) LIKE ? OR ? <% lower(category.name))",
+			escapeLike(query)+"%", "% "+escapeLike(query)+"%", query).
+		Select("category.id AS category_id, category.name AS text, word_similarity(?, lower(category.name)) AS score, count(*) AS count", query).
+		Group("category.id, category.name").
+		Order("score DESC, count DESC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Str("query", query).Msg("failed to get category suggestions")
+		return nil, err
+	}
+
+	suggestions := make([]domain.SearchSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = domain.SearchSuggestion{
+			Text:       row.Text,
+			Type:       domain.SuggestionCategory,
+			CategoryID: row.CategoryID,
+			Count:      row.Count,
+		}
+	}
+	return suggestions, nil
+}
+
+// SuggestBrands returns the brands of indexed products that start with or
+// resemble query, with their number of products.
+func (r *SearchRepo) SuggestBrands(ctx context.Context, query string, locales []string, limit int) ([]domain.SearchSuggestion, error) {
+	query = strings.ToLower(query)
+
+	var rows []suggestionRow
+	if err := r.db.WithContext(ctx).Model(&SearchIndexModel{}).
+		Where(r.localeScope(locales)).
+		Where(brandSQL+" <> ''").
+		Where("(lower("+brandSQL+") LIKE ? OR ? <% lower("+brandSQL+"))", escapeLike(query)+"%", query).
+		Select(brandSQL+" AS text, max(word_similarity(?, lower("+brandSQL+"))) AS score, count(*) AS count", query).
+		Group("text").
+		Order("score DESC, count DESC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Str("query", query).Msg("failed to get brand suggestions")
+		return nil, err
+	}
+
+	suggestions := make([]domain.SearchSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = domain.SearchSuggestion{Text: row.Text, Type: domain.SuggestionBrand, Count: row.Count}
+	}
+	return suggestions, nil
+}
+
+// RecordQuery counts a search for query in locale, and the searcher if they
+// had not searched it before, and keeps its latest result count. Searches
+// without a searcher hash only count as searches.
+func (r *SearchRepo) RecordQuery(ctx context.Context, query, locale, searcherHash string, results int64) error {
+	now := time.Now()
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var newSearchers int64
+		if searcherHash != "" {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&SearchQuerySearcherModel{
+				Query:        query,
+				Locale:       locale,
+				SearcherHash: searcherHash,
+				SearchedAt:   now,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			newSearchers = result.RowsAffected
+		}
+
+		model := &SearchQueryModel{
+			Query:          query,
+			Locale:         locale,
+			SearchCount:    1,
+			SearcherCount:  newSearchers,
+			ResultCount:    results,
+			LastSearchedAt: now,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "query"}, {Name: "locale"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"search_count":     gorm.Expr("search_queries.search_count + 1"),
+				"searcher_count":   gorm.Expr("search_queries.searcher_count + ?", newSearchers),
+				"result_count":     results,
+				"last_searched_at": now,
+			}),
+		}).Create(model).Error
+	})
+	if err != nil {
+		log.Error().Err(err).Str("query", query).Msg("failed to record search query")
+		return err
+	}
+	return nil
+}
+
+// SimilarQuery returns the popular query in locale with results that is
+// most similar to query, by trigram similarity.
+func (r *SearchRepo) SimilarQuery(ctx context.Context, query, locale string) (string, error) {
+	var rows []suggestionRow
+	if err := r.popularQueries(ctx, locale).
+		Where("query <> ? AND query % ?", query, query).
+		Select("query AS text, similarity(query, ?) AS score, search_count AS count", query).
+		Order("score DESC, count DESC").
+		Limit(1).
+		Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Str("query", query).Msg("failed to find similar query")
+		return "", err
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	return rows[0].Text, nil
+}
+
+// ClosestWord returns the word most similar to word among the words of the
+// product names in locales that contain something resembling it.
+func (r *SearchRepo) ClosestWord(ctx context.Context, word string, locales []string) (string, error) {
+	word = strings.ToLower(word)
+
+	var closest []string
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT word FROM (
+			SELECT name FROM search_indices WHERE locale IN ? AND ? <% lower(name) LIMIT ?
+		) AS candidate, regexp_split_to_table(lower(candidate.name), '[^[:alnum:]]+') AS word
+		WHERE length(word) > 2 AND similarity(word, ?) >= ?
+		ORDER BY similarity(word, ?) DESC, word
+		LIMIT 1`,
+		locales, word, correctionCandidateNames, word, correctionMinSimilarity, word).
+		Scan(&closest).Error; err != nil {
+		log.Error().Err(err).Str("word", word).Msg("failed to find closest word")
+		return "", err
+	}
+	if len(closest) == 0 {
+		return "", nil
+	}
+	return closest[0], nil
+}
+
+// popularQueries selects the queries in locale searched by enough shoppers,
+// recently enough, to be suggested, and that had results.
+func (r *SearchRepo) popularQueries(ctx context.Context, locale string) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&SearchQueryModel{}).
+		Where("locale = ? AND result_count > 0 AND searcher_count >= ? AND last_searched_at > ?",
+			locale, popularQueryMinSearchers, time.Now().Add(-popularQueryWindow))
+}
+
+// escapeLike escapes the LIKE wildcards in s.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
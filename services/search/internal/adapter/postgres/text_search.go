@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// textSearchConfigs maps languages to the Postgres text search
+// configuration that stems them. Other languages are indexed with the
+// "simple" configuration, which only lowercases words.
+var textSearchConfigs = map[string]string{
+	"ar": "arabic",
+	"de": "german",
+	"en": "english",
+	"es": "spanish",
+	"fr": "french",
+	"it": "italian",
+	"nl": "dutch",
+	"pt": "portuguese",
+	"ru": "russian",
+}
+
+// questionSearchConfig is the text search configuration of indexed
+// questions, which are not tagged with a language.
+const questionSearchConfig = "simple"
+
+// textSearchConfig returns the text search configuration of a locale such
+// as "fr" or "pt-BR".
+func textSearchConfig(locale string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	if config, ok := textSearchConfigs[lang]; ok {
+		return config
+	}
+	return "simple"
+}
+
+// productVectorSQL computes a product document's search vector, weighting
+// matches in the name (A) over tags (B), attribute values (C) and the
+// description (D). Its only parameter is the text search configuration.
+const productVectorSQL = `
+	setweight(to_tsvector(CAST(@config AS regconfig), unaccent(coalesce(name, ''))), 'A') ||
+	setweight(to_tsvector(CAST(@config AS regconfig), unaccent(coalesce(array_to_string(tags, ' '), ''))), 'B') ||
+	setweight(to_tsvector(CAST(@config AS regconfig), unaccent(coalesce(
+		(SELECT string_agg(value, ' ') FROM jsonb_each_text(attributes)), ''))), 'C') ||
+	setweight(to_tsvector(CAST(@config AS regconfig), unaccent(coalesce(description, ''))), 'D')`
+
+// questionVectorSQL computes a question document's search vector, weighting
+// the question over its answers.
+const questionVectorSQL = `
+	setweight(to_tsvector(CAST(@config AS regconfig), unaccent(coalesce(question, ''))), 'C') ||
+	setweight(to_tsvector(CAST(@config AS regconfig), unaccent(coalesce(answers, ''))), 'D')`
+
+// headlineOptions are the ts_headline options of result snippets.
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MinWords=15, MaxWords=35, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// MigrateTextSearch installs the unaccent extension and computes the search
+// vectors of documents indexed before they were kept.
+func MigrateTextSearch(ctx context.Context, db *gorm.DB) error {
+	db = db.WithContext(ctx)
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS unaccent").Error; err != nil {
+		return fmt.Errorf("failed to install unaccent: %w", err)
+	}
+
+	var locales []string
+	if err := db.Model(&SearchIndexModel{}).Where("search_vector IS NULL").Distinct().Pluck("locale", &locales).Error; err != nil {
+		return err
+	}
+	for _, locale := range locales {
+		if err := db.Exec("UPDATE search_indices SET search_vector = "+productVectorSQL+" WHERE locale = @locale AND search_vector IS NULL",
+			map[string]interface{}{"config": textSearchConfig(locale), "locale": locale}).Error; err != nil {
+			return fmt.Errorf("failed to backfill search vectors: %w", err)
+		}
+	}
+
+	return db.Exec("UPDATE search_questions SET search_vector = "+questionVectorSQL+" WHERE search_vector IS NULL",
+		map[string]interface{}{"config": questionSearchConfig}).Error
+}
+
+// searchTerm is a word or quoted phrase of a search query.
+type searchTerm struct {
+	Text    string
+	Phrase  bool
+	Negated bool
+}
+
+// textQuery is a parsed search query: alternatives separated by OR, each
+// matching documents that have all of its terms.
+type textQuery [][]searchTerm
+
+// parseTextQuery parses a search query in the syntax buyers know from web
+// search: words match by prefix ("sho" finds "shoes"), "quoted phrases"
+// match their words in order, -word and -"phrase" exclude documents, and OR
+// (or |) separates alternatives. Punctuation inside words splits them into
+// phrases, so "t-shirt" and "women's" behave as typed.
+func parseTextQuery(query string) textQuery {
+	var (
+		parsed textQuery
+		group  []searchTerm
+	)
+	addTerm := func(text string, phrase, negated bool) {
+		words := strings.FieldsFunc(text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(words) == 0 {
+			return
+		}
+		group = append(group, searchTerm{
+			Text:    strings.Join(words, " "),
+			Phrase:  phrase || len(words) > 1,
+			Negated: negated,
+		})
+	}
+	endGroup := func() {
+		if len(group) > 0 {
+			parsed = append(parsed, group)
+			group = nil
+		}
+	}
+
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		negated := false
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			negated = true
+			i++
+		}
+
+		if runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			addTerm(string(runes[i+1:end]), true, negated)
+			i = end + 1
+			continue
+		}
+
+		end := i
+		for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '"' {
+			end++
+		}
+		word := string(runes[i:end])
+		i = end
+		if !negated && (word == "OR" || word == "|") {
+			endGroup()
+			continue
+		}
+		addTerm(word, false, negated)
+	}
+	endGroup()
+	return parsed
+}
+
+// sql returns a tsquery expression matching the query in a text search
+// configuration, and its arguments.
+func (q textQuery) sql(config string) (string, []interface{}) {
+	var (
+		groups []string
+		args   []interface{}
+	)
+	for _, group := range q {
+		terms := make([]string, 0, len(group))
+		for _, term := range group {
+			var expr string
+			switch {
+			case term.Phrase:
+				expr = "phraseto_tsquery(?::regconfig, unaccent(?))"
+			case term.Negated:
+				expr = "plainto_tsquery(?::regconfig, unaccent(?))"
+			default:
+				// Words are letters and digits only, safe to suffix with the
+				// prefix operator
+				expr = "to_tsquery(?::regconfig, unaccent(?) || ':*')"
+			}
+			if term.Negated {
+				// !! binds looser than &&, so it is parenthesized
+				expr = "(!!" + expr + ")"
+			}
+			terms = append(terms, expr)
+			args = append(args, config, term.Text)
+		}
+		groups = append(groups, "("+strings.Join(terms, " && ")+")")
+	}
+	return "(" + strings.Join(groups, " || ") + ")", args
+}
+
+// matchSQL returns a condition matching the documents in locales whose
+// search vector matches the query, using each locale's configuration.
+func (q textQuery) matchSQL(locales []string) (string, []interface{}) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	for config, configLocales := range localesByConfig(locales) {
+		tsquery, queryArgs := q.sql(config)
+		conds = append(conds, "(locale IN ? AND search_vector @@ "+tsquery+")")
+		args = append(args, configLocales)
+		args = append(args, queryArgs...)
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+// perLocaleSQL returns an expression evaluating expr for each document
+// with the configuration of its locale. The query appears in expr once, as
+// %[1]s, and the configuration as %[2]s.
+func (q textQuery) perLocaleSQL(locales []string, expr string) (string, []interface{}) {
+	var (
+		sql  strings.Builder
+		args []interface{}
+	)
+	sql.WriteString("CASE")
+	for config, configLocales := range localesByConfig(locales) {
+		tsquery, queryArgs := q.sql(config)
+		sql.WriteString(" WHEN locale IN ? THEN ")
+		sql.WriteString(fmt.Sprintf(expr, tsquery, "'"+config+"'::regconfig"))
+		args = append(args, configLocales)
+		args = append(args, queryArgs...)
+	}
+	sql.WriteString(" END")
+	return sql.String(), args
+}
+
+// localesByConfig groups locales by their text search configuration.
+func localesByConfig(locales []string) map[string][]string {
+	groups := make(map[string][]string)
+	for _, locale := range locales {
+		config := textSearchConfig(locale)
+		groups[config] = append(groups[config], locale)
+	}
+	return groups
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTextQuery(t *testing.T) {
+	word := func(text string) searchTerm { return searchTerm{Text: text} }
+	phrase := func(text string) searchTerm { return searchTerm{Text: text, Phrase: true} }
+	not := func(term searchTerm) searchTerm {
+		term.Negated = true
+		return term
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  textQuery
+	}{
+		{"empty", "", nil},
+		{"blank", "   ", nil},
+		{"words", "running shoes", textQuery{{word("running"), word("shoes")}}},
+		{"quoted phrase", `"red dress"`, textQuery{{phrase("red dress")}}},
+		{"quoted single word", `"red"`, textQuery{{phrase("red")}}},
+		{"unclosed quote", `"red dress`, textQuery{{phrase("red dress")}}},
+		{"empty quotes", `"" shoes`, textQuery{{word("shoes")}}},
+		{"quote ends word", `shoes"red"`, textQuery{{word("shoes"), phrase("red")}}},
+		{"negated word", "boots -leather", textQuery{{word("boots"), not(word("leather"))}}},
+		{"negated phrase", `coat -"faux fur"`, textQuery{{word("coat"), not(phrase("faux fur"))}}},
+		{"lone dash", "- shoes", textQuery{{word("shoes")}}},
+		{"punctuation makes phrases", "t-shirt women's", textQuery{{phrase("t shirt"), phrase("women s")}}},
+		{"or groups", "boots OR sandals", textQuery{{word("boots")}, {word("sandals")}}},
+		{"pipe groups", "boots | sandals", textQuery{{word("boots")}, {word("sandals")}}},
+		{"or groups with several terms", `"red dress" OR skirt -mini`, textQuery{
+			{phrase("red dress")},
+			{word("skirt"), not(word("mini"))},
+		}},
+		{"lowercase or is a word", "boots or sandals", textQuery{{word("boots"), word("or"), word("sandals")}}},
+		{"negated or is a word", "boots -OR", textQuery{{word("boots"), not(word("OR"))}}},
+		{"leading and repeated or", "OR boots OR OR sandals OR", textQuery{{word("boots")}, {word("sandals")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTextQuery(tt.query); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTextQuery(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -17,8 +17,14 @@ type SearchResult struct {
 	Rating      float64
 	ReviewCount int
 	InStock     bool
-	Score       float64
-	CreatedAt   time.Time
+	// Score is the relevance of the result to the query text, between 0
+	// and 1. NameHighlight and Snippet are the name and extracts of the
+	// description with the matched words in <mark> tags.
+	Score         float64
+	NameHighlight string
+	Snippet       string
+	Locale        string
+	CreatedAt     time.Time
 }
 
 // SearchFilter holds the parameters for a search query.
@@ -33,12 +39,77 @@ type SearchFilter struct {
 	SortOrder  string
 	Page       int
 	PageSize   int
+	// Locale is the requested language. Locales is its fallback chain:
+	// each product is returned once, in the first of these it is indexed in.
+	Locale  string
+	Locales []string
+	// Facet filters. Results match any of the values given for a
+	// dimension and every dimension given. SellerIDs adds to SellerID.
+	SellerIDs  []string
+	Tags       []string
+	MinRating  float64
+	Attributes []AttributeFilter
+	// Searcher identifies who searched, by user ID or, for guests, client
+	// address, to tell popular queries from one shopper's repeated ones. It
+	// is only ever stored hashed.
+	Searcher string
+}
+
+// AttributeFilter restricts results to products with one of Values for a
+// filterable attribute. Name is resolved from the attribute's definition.
+type AttributeFilter struct {
+	Slug   string
+	Name   string
+	Values []string
+}
+
+// Facet dimensions. Each is also the name of the query parameter that
+// filters by its values.
+const (
+	FacetPrice  = "price_range"
+	FacetRating = "min_rating"
+	FacetTags   = "tags"
+	FacetSeller = "seller_id"
+	// FacetAttributePrefix prefixes the slug of an attribute facet, e.g.
+	// "attr.color".
+	FacetAttributePrefix = "attr."
+)
+
+// Facet counts the search results by the values of one dimension, to
+// narrow the results down by. The counts of a dimension ignore the
+// dimension's own filter, so that other values can still be chosen.
+type Facet struct {
+	Name   string
+	Label  string
+	Values []FacetValue
+}
+
+// FacetValue is a value of a facet and the number of results with it.
+// Price values are ranges of cents such as "2500-4999" (open-ended ranges
+// have no maximum, "50000-"); rating values are the minimum rating, e.g.
+// "4" for 4 stars and up.
+type FacetValue struct {
+	Value    string
+	Label    string
+	Count    int64
+	Selected bool
+}
+
+// FilterableAttribute is a product attribute shoppers can filter search
+// results by, as defined in the product catalog. Indexed documents key its
+// values by slug or name.
+type FilterableAttribute struct {
+	Slug      string
+	Name      string
+	Unit      string
+	SortOrder int
 }
 
 // SearchIndex represents a product document in the search index.
 type SearchIndex struct {
 	ID          string
 	ProductID   string
+	Locale      string
 	Name        string
 	Slug        string
 	Description string
@@ -56,9 +127,91 @@ type SearchIndex struct {
 	UpdatedAt   time.Time
 }
 
-// SearchSuggestion represents a search autocomplete suggestion.
+// QuestionDocument is an answered product question indexed so that searches
+// also match the wording buyers and sellers use in Q&A.
+type QuestionDocument struct {
+	QuestionID string
+	ProductID  string
+	Question   string
+	Answers    []string
+	UpdatedAt  time.Time
+}
+
+// SearchSuggestion represents a search autocomplete suggestion. Category
+// suggestions carry the CategoryID; Count is the number of products in a
+// suggested category or of a suggested brand.
 type SearchSuggestion struct {
-	Text      string
-	Type      string
-	ProductID string
+	Text       string
+	Type       string
+	ProductID  string
+	CategoryID string
+	Count      int64
+}
+
+// Suggestion types.
+const (
+	SuggestionQuery    = "query"
+	SuggestionCategory = "category"
+	SuggestionBrand    = "brand"
+	SuggestionProduct  = "product"
+)
+
+// Category is a product category as the product catalog names it in a
+// locale.
+type Category struct {
+	ID   string
+	Name string
+	Slug string
+}
+
+// CatalogProduct is a product as the product catalog holds it, with what
+// its search documents are built from.
+type CatalogProduct struct {
+	ID             string
+	SellerID       string
+	CategoryID     string
+	Name           string
+	Slug           string
+	Description    string
+	BasePriceCents int64
+	Currency       string
+	Status         string
+	Tags           []string
+	ImageURLs      []string
+	Rating         float64
+	ReviewCount    int
+	// Attributes maps attribute slugs (or names, for attributes without a
+	// definition) to values; multi-select values are comma-separated.
+	Attributes   map[string]string
+	Variants     []CatalogVariant
+	Translations []CatalogTranslation
+	CreatedAt    time.Time
+}
+
+// CatalogProductActive is the status of products that are listed, and so
+// searchable.
+const CatalogProductActive = "active"
+
+// CatalogVariant is a purchasable variant of a catalog product.
+type CatalogVariant struct {
+	ID         string
+	PriceCents int64
+	Stock      int
+	IsActive   bool
+}
+
+// CatalogTranslation is a catalog product's content in one locale. Empty
+// fields fall back to the product's base content.
+type CatalogTranslation struct {
+	Locale      string
+	Name        string
+	Slug        string
+	Description string
+}
+
+// ReindexResult summarizes a full reindex.
+type ReindexResult struct {
+	Products  int
+	Documents int
+	Failed    int
 }
@@ -1,11 +1,80 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
 
 // SearchRepository defines the interface for search index operations.
 type SearchRepository interface {
 	Index(ctx context.Context, index *SearchIndex) error
-	Delete(ctx context.Context, productID string) error
+	// Delete removes a product's document in locale, or all of its
+	// documents when locale is empty.
+	Delete(ctx context.Context, productID, locale string) error
+	UpdateRating(ctx context.Context, productID string, rating float64, reviewCount int) error
 	Search(ctx context.Context, filter SearchFilter) ([]SearchResult, int64, error)
-	Suggest(ctx context.Context, query string, limit int) ([]SearchSuggestion, error)
+	// Facets counts the results of a search by price range, rating, each
+	// of attributes, tag and seller.
+	Facets(ctx context.Context, filter SearchFilter, attributes []FilterableAttribute) ([]Facet, error)
+	// Suggest returns the products whose names contain or resemble query.
+	Suggest(ctx context.Context, query string, locales []string, limit int) ([]SearchSuggestion, error)
+	// SuggestQueries returns popular past queries in locale that had
+	// results and start like or resemble query.
+	SuggestQueries(ctx context.Context, query, locale string, limit int) ([]SearchSuggestion, error)
+	// SuggestCategories returns the categories with indexed products whose
+	// names resemble query.
+	SuggestCategories(ctx context.Context, query string, categories []Category, locales []string, limit int) ([]SearchSuggestion, error)
+	// SuggestBrands returns the brands of indexed products resembling query.
+	SuggestBrands(ctx context.Context, query string, locales []string, limit int) ([]SearchSuggestion, error)
+	// RecordQuery logs a search for query in locale by the searcher with
+	// the given hash, and its result count.
+	RecordQuery(ctx context.Context, query, locale, searcherHash string, results int64) error
+	// SimilarQuery returns the popular past query in locale with results
+	// that is most similar to query, or "" if none is.
+	SimilarQuery(ctx context.Context, query, locale string) (string, error)
+	// ClosestWord returns the word of the indexed product names most
+	// similar to word, or "" if none is.
+	ClosestWord(ctx context.Context, word string, locales []string) (string, error)
+	IndexQuestion(ctx context.Context, doc *QuestionDocument) error
+	DeleteQuestion(ctx context.Context, questionID string) error
+	// ProductLocales lists the locales a product is indexed in.
+	ProductLocales(ctx context.Context, productID string) ([]string, error)
+
+	// BeginRebuild starts building a new index next to the live one,
+	// discarding a rebuild an interrupted run left behind. Until the
+	// rebuild is swapped in or aborted, writes to the live index are
+	// mirrored to it.
+	BeginRebuild(ctx context.Context) error
+	// IndexRebuild adds a document to the index being rebuilt. Documents
+	// mirrored from the live index are newer and are kept, and documents
+	// deleted from it since the rebuild began are not added back.
+	IndexRebuild(ctx context.Context, index *SearchIndex) error
+	// SwapRebuild atomically replaces the live index with the rebuilt one.
+	SwapRebuild(ctx context.Context) error
+	// AbortRebuild discards the index being rebuilt.
+	AbortRebuild(ctx context.Context) error
+}
+
+// ErrProductNotFound is returned by a ProductCatalog for products that do
+// not exist (anymore).
+var ErrProductNotFound = errors.New("product not found")
+
+// ProductCatalog reads products from the product catalog to index them.
+type ProductCatalog interface {
+	GetProduct(ctx context.Context, productID string) (*CatalogProduct, error)
+	// GetVariantProductID returns the ID of a variant's product.
+	GetVariantProductID(ctx context.Context, variantID string) (string, error)
+	// ListProductIDs pages through the IDs of the products with status.
+	ListProductIDs(ctx context.Context, status string, page, pageSize int) ([]string, int64, error)
+}
+
+// CategoryProvider lists the product categories, named in a locale.
+type CategoryProvider interface {
+	ListCategories(ctx context.Context, locale string) ([]Category, error)
+}
+
+// AttributeProvider lists the product attributes search results can be
+// filtered by.
+type AttributeProvider interface {
+	ListFilterableAttributes(ctx context.Context) ([]FilterableAttribute, error)
 }
@@ -13,6 +13,12 @@ type Config struct {
 	NATS             NATSConfig
 	ElasticsearchURL string
 	LogLevel         string
+	// ProductServiceURL is the base URL of the product service, which the
+	// filterable attributes are read from.
+	ProductServiceURL string
+	// ProductGRPCAddr is the address of the product service's gRPC API,
+	// which indexed products are read from.
+	ProductGRPCAddr string
 }
 
 // PostgresConfig holds Postgres connection configuration.
@@ -40,10 +46,12 @@ func (c PostgresConfig) DSN() string {
 // Load reads configuration from environment variables with sensible defaults.
 func Load() *Config {
 	return &Config{
-		HTTPPort:         getEnv("HTTP_PORT", "8087"),
-		GRPCPort:         getEnv("GRPC_PORT", "9087"),
-		LogLevel:         getEnv("LOG_LEVEL", "info"),
-		ElasticsearchURL: getEnv("ELASTICSEARCH_URL", "http://localhost:9200"),
+		HTTPPort:          getEnv("HTTP_PORT", "8087"),
+		GRPCPort:          getEnv("GRPC_PORT", "9087"),
+		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		ElasticsearchURL:  getEnv("ELASTICSEARCH_URL", "http://localhost:9200"),
+		ProductServiceURL: getEnv("PRODUCT_SERVICE_URL", "http://localhost:8081"),
+		ProductGRPCAddr:   getEnv("PRODUCT_GRPC_ADDR", "localhost:9081"),
 		Postgres: PostgresConfig{
 			User:     getEnv("POSTGRES_USER", "postgres"),
 			Password: getEnv("POSTGRES_PASSWORD", "postgres"),
@@ -0,0 +1,202 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/southern-martin/ecommerce/pkg/events"
+	"github.com/southern-martin/ecommerce/services/search/internal/domain"
+	"github.com/southern-martin/ecommerce/services/search/internal/usecase"
+)
+
+// subjectProductRatingUpdated is published by the product service whenever a
+// product's rating changes.
+const subjectProductRatingUpdated = "product.rating.updated"
+
+// ratingUpdatedEvent is the product.rating.updated payload.
+type ratingUpdatedEvent struct {
+	ProductID   string  `json:"product_id"`
+	RatingAvg   float64 `json:"rating_avg"`
+	RatingCount int     `json:"rating_count"`
+}
+
+// StartRatingSubscriber keeps the rating of indexed products current.
+func StartRatingSubscriber(p *Publisher, indexUC *usecase.IndexUseCase) error {
+	_, err := p.Subscribe(subjectProductRatingUpdated, func(data []byte) {
+		var event ratingUpdatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode product.rating.updated event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := indexUC.UpdateRating(ctx, event.ProductID, event.RatingAvg, event.RatingCount); err != nil {
+			log.Error().Err(err).Str("product_id", event.ProductID).Msg("failed to update indexed rating")
+		}
+	})
+	return err
+}
+
+// Subjects published by the review service as product Q&A changes.
+const (
+	subjectQuestionAnswered = "question.answered"
+	subjectQuestionRemoved  = "question.removed"
+)
+
+// questionAnsweredEvent is the question.answered payload.
+type questionAnsweredEvent struct {
+	QuestionID string   `json:"question_id"`
+	ProductID  string   `json:"product_id"`
+	Question   string   `json:"question"`
+	Answers    []string `json:"answers"`
+}
+
+// questionRemovedEvent is the question.removed payload.
+type questionRemovedEvent struct {
+	QuestionID string `json:"question_id"`
+}
+
+// StartQuestionSubscriber indexes answered product questions so that
+// searches also match their questions and answers.
+func StartQuestionSubscriber(p *Publisher, indexUC *usecase.IndexUseCase) error {
+	if _, err := p.Subscribe(subjectQuestionAnswered, func(data []byte) {
+		var event questionAnsweredEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode question.answered event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		doc := &domain.QuestionDocument{
+			QuestionID: event.QuestionID,
+			ProductID:  event.ProductID,
+			Question:   event.Question,
+			Answers:    event.Answers,
+		}
+		if err := indexUC.IndexQuestion(ctx, doc); err != nil {
+			log.Error().Err(err).Str("question_id", event.QuestionID).Msg("failed to index question")
+		}
+	}); err != nil {
+		return err
+	}
+
+	_, err := p.Subscribe(subjectQuestionRemoved, func(data []byte) {
+		var event questionRemovedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode question.removed event")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := indexUC.RemoveQuestion(ctx, event.QuestionID); err != nil {
+			log.Error().Err(err).Str("question_id", event.QuestionID).Msg("failed to remove indexed question")
+		}
+	})
+	return err
+}
+
+// productEvent is the part of the product.created, product.updated and
+// product.deleted payloads used here.
+type productEvent struct {
+	ID string `json:"id"`
+}
+
+// stockUpdatedEvent is the part of the product.stock.updated payload used
+// here.
+type stockUpdatedEvent struct {
+	VariantID string `json:"variant_id"`
+}
+
+// priceUpdatedEvent is the part of the product.price.updated payload used
+// here.
+type priceUpdatedEvent struct {
+	ProductID string `json:"product_id"`
+}
+
+// StartCatalogSubscriber keeps the product index in sync with the catalog.
+// The consumers are durable, so events published while the service is down
+// are indexed when it comes back, and events that fail to index are
+// redelivered.
+func StartCatalogSubscriber(sub *events.Subscriber, indexUC *usecase.IndexUseCase) error {
+	syncProduct := func(subject string) func(data []byte) error {
+		return func(data []byte) error {
+			var event productEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				log.Error().Err(err).Msgf("failed to decode %s event", subject)
+				return nil
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := indexUC.SyncProduct(ctx, event.ID); err != nil {
+				log.Error().Err(err).Str("product_id", event.ID).Msg("failed to sync indexed product")
+				return err
+			}
+			return nil
+		}
+	}
+	if err := sub.SubscribeWithRetry(events.SubjectProductCreated, "search-service-product-created", syncProduct(events.SubjectProductCreated)); err != nil {
+		return err
+	}
+	if err := sub.SubscribeWithRetry(events.SubjectProductUpdated, "search-service-product-updated", syncProduct(events.SubjectProductUpdated)); err != nil {
+		return err
+	}
+
+	if err := sub.SubscribeWithRetry(events.SubjectProductDeleted, "search-service-product-deleted", func(data []byte) error {
+		var event productEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode product.deleted event")
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := indexUC.RemoveProduct(ctx, event.ID, ""); err != nil {
+			log.Error().Err(err).Str("product_id", event.ID).Msg("failed to remove deleted product from index")
+			return err
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := sub.SubscribeWithRetry(events.SubjectProductStockUpdate, "search-service-product-stock", func(data []byte) error {
+		var event stockUpdatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode product.stock.updated event")
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := indexUC.SyncVariant(ctx, event.VariantID); err != nil {
+			log.Error().Err(err).Str("variant_id", event.VariantID).Msg("failed to sync indexed stock")
+			return err
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return sub.SubscribeWithRetry(events.SubjectProductPriceUpdate, "search-service-product-price", func(data []byte) error {
+		var event priceUpdatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			log.Error().Err(err).Msg("failed to decode product.price.updated event")
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := indexUC.SyncProduct(ctx, event.ProductID); err != nil {
+			log.Error().Err(err).Str("product_id", event.ProductID).Msg("failed to sync indexed price")
+			return err
+		}
+		return nil
+	})
+}
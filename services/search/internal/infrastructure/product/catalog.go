@@ -0,0 +1,209 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/southern-martin/ecommerce/pkg/grpcjson"
+	"github.com/southern-martin/ecommerce/services/search/internal/domain"
+)
+
+// CatalogClient implements domain.ProductCatalog on top of the product
+// service's gRPC API.
+type CatalogClient struct {
+	conn *grpc.ClientConn
+}
+
+// NewCatalogClient creates a client of the product service's gRPC API at
+// addr. The connection is established lazily, on the first call.
+func NewCatalogClient(addr string) (*CatalogClient, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpcjson.CallOption()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to product service at %s: %w", addr, err)
+	}
+	return &CatalogClient{conn: conn}, nil
+}
+
+// Close closes the gRPC connection.
+func (c *CatalogClient) Close() error {
+	return c.conn.Close()
+}
+
+// --- Messages of the product service's gRPC API ---
+
+type getProductRequest struct {
+	ProductID string
+}
+
+type getProductResponse struct {
+	ID             string
+	SellerID       string
+	CategoryID     string
+	Name           string
+	Slug           string
+	Description    string
+	BasePriceCents int64
+	Currency       string
+	Status         string
+	HasVariants    bool
+	Tags           []string
+	ImageURLs      []string
+	RatingAvg      float64
+	RatingCount    int
+	Attributes     []attributeValueInfo
+	Translations   []translationInfo
+	CreatedAt      time.Time
+}
+
+type attributeValueInfo struct {
+	Slug   string
+	Name   string
+	Value  string
+	Values []string
+}
+
+type translationInfo struct {
+	Locale      string
+	Name        string
+	Slug        string
+	Description string
+}
+
+type getVariantRequest struct {
+	VariantID string
+}
+
+type getVariantResponse struct {
+	ID        string
+	ProductID string
+}
+
+type listVariantsByProductRequest struct {
+	ProductID string
+}
+
+type variantInfo struct {
+	ID         string
+	PriceCents int64
+	Stock      int
+	IsActive   bool
+}
+
+type listVariantsByProductResponse struct {
+	Variants []variantInfo
+}
+
+type listProductsRequest struct {
+	Status   string
+	Page     int32
+	PageSize int32
+}
+
+type listProductsResponse struct {
+	ProductIDs []string
+	Total      int64
+}
+
+// GetProduct fetches a product with its attribute values, translations and
+// variants.
+func (c *CatalogClient) GetProduct(ctx context.Context, productID string) (*domain.CatalogProduct, error) {
+	var resp getProductResponse
+	if err := c.invoke(ctx, "GetProduct", &getProductRequest{ProductID: productID}, &resp); err != nil {
+		return nil, err
+	}
+
+	var variants listVariantsByProductResponse
+	if err := c.invoke(ctx, "ListVariantsByProduct", &listVariantsByProductRequest{ProductID: productID}, &variants); err != nil {
+		return nil, err
+	}
+
+	product := &domain.CatalogProduct{
+		ID:             resp.ID,
+		SellerID:       resp.SellerID,
+		CategoryID:     resp.CategoryID,
+		Name:           resp.Name,
+		Slug:           resp.Slug,
+		Description:    resp.Description,
+		BasePriceCents: resp.BasePriceCents,
+		Currency:       resp.Currency,
+		Status:         resp.Status,
+		Tags:           resp.Tags,
+		ImageURLs:      resp.ImageURLs,
+		Rating:         resp.RatingAvg,
+		ReviewCount:    resp.RatingCount,
+		Attributes:     make(map[string]string, len(resp.Attributes)),
+		CreatedAt:      resp.CreatedAt,
+	}
+	for _, attr := range resp.Attributes {
+		key := attr.Slug
+		if key == "" {
+			key = attr.Name
+		}
+		value := attr.Value
+		if len(attr.Values) > 0 {
+			value = strings.Join(attr.Values, ", ")
+		}
+		if key != "" && value != "" {
+			product.Attributes[key] = value
+		}
+	}
+	for _, v := range variants.Variants {
+		product.Variants = append(product.Variants, domain.CatalogVariant{
+			ID:         v.ID,
+			PriceCents: v.PriceCents,
+			Stock:      v.Stock,
+			IsActive:   v.IsActive,
+		})
+	}
+	for _, t := range resp.Translations {
+		product.Translations = append(product.Translations, domain.CatalogTranslation{
+			Locale:      t.Locale,
+			Name:        t.Name,
+			Slug:        t.Slug,
+			Description: t.Description,
+		})
+	}
+	return product, nil
+}
+
+// GetVariantProductID returns the ID of a variant's product.
+func (c *CatalogClient) GetVariantProductID(ctx context.Context, variantID string) (string, error) {
+	var resp getVariantResponse
+	if err := c.invoke(ctx, "GetVariant", &getVariantRequest{VariantID: variantID}, &resp); err != nil {
+		return "", err
+	}
+	return resp.ProductID, nil
+}
+
+// ListProductIDs pages through the IDs of the products with productStatus.
+func (c *CatalogClient) ListProductIDs(ctx context.Context, productStatus string, page, pageSize int) ([]string, int64, error) {
+	var resp listProductsResponse
+	req := &listProductsRequest{Status: productStatus, Page: int32(page), PageSize: int32(pageSize)}
+	if err := c.invoke(ctx, "ListProducts", req, &resp); err != nil {
+		return nil, 0, err
+	}
+	return resp.ProductIDs, resp.Total, nil
+}
+
+// invoke calls a method of the product service, reporting products and
+// variants it cannot find as domain.ErrProductNotFound.
+func (c *CatalogClient) invoke(ctx context.Context, method string, req, resp interface{}) error {
+	err := c.conn.Invoke(ctx, "/product.ProductService/"+method, req, resp)
+	if err == nil {
+		return nil
+	}
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("%w: %v", domain.ErrProductNotFound, err)
+	}
+	return fmt.Errorf("product service %s failed: %w", method, err)
+}
@@ -0,0 +1,129 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/southern-martin/ecommerce/services/search/internal/domain"
+)
+
+// Client implements domain.AttributeProvider and domain.CategoryProvider on
+// top of the product service's HTTP API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new product service client.
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// attributesResponse is the part of the body of GET
+// /api/v1/admin/attributes used here.
+type attributesResponse struct {
+	Attributes []struct {
+		Name       string `json:"name"`
+		Slug       string `json:"slug"`
+		Filterable bool   `json:"filterable"`
+		Unit       string `json:"unit"`
+		SortOrder  int    `json:"sort_order"`
+	} `json:"attributes"`
+}
+
+// ListFilterableAttributes fetches the attribute definitions marked
+// filterable, in their sort order.
+func (c *Client) ListFilterableAttributes(ctx context.Context) ([]domain.FilterableAttribute, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/admin/attributes", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product service returned %s", resp.Status)
+	}
+
+	var body attributesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode attributes: %w", err)
+	}
+
+	var attributes []domain.FilterableAttribute
+	for _, attr := range body.Attributes {
+		if !attr.Filterable || attr.Slug == "" {
+			continue
+		}
+		attributes = append(attributes, domain.FilterableAttribute{
+			Slug:      attr.Slug,
+			Name:      attr.Name,
+			Unit:      attr.Unit,
+			SortOrder: attr.SortOrder,
+		})
+	}
+	sort.SliceStable(attributes, func(i, j int) bool {
+		return attributes[i].SortOrder < attributes[j].SortOrder
+	})
+	return attributes, nil
+}
+
+// categoriesResponse is the part of the body of GET /api/v1/categories used
+// here.
+type categoriesResponse struct {
+	Categories []struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Slug     string `json:"slug"`
+		IsActive bool   `json:"is_active"`
+	} `json:"categories"`
+}
+
+// ListCategories fetches the active categories, named in locale.
+func (c *Client) ListCategories(ctx context.Context, locale string) ([]domain.Category, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/categories", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept-Language", locale)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product service returned %s", resp.Status)
+	}
+
+	var body categoriesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode categories: %w", err)
+	}
+
+	var categories []domain.Category
+	for _, category := range body.Categories {
+		if !category.IsActive || category.Name == "" {
+			continue
+		}
+		categories = append(categories, domain.Category{
+			ID:   category.ID,
+			Name: category.Name,
+			Slug: category.Slug,
+		})
+	}
+	return categories, nil
+}
@@ -2,29 +2,48 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 	"github.com/southern-martin/ecommerce/services/search/internal/domain"
 )
 
+// reindexPageSize is the number of products a full reindex reads from the
+// catalog at a time.
+const reindexPageSize = 100
+
 // IndexUseCase handles indexing operations for the search service.
 type IndexUseCase struct {
 	repo      domain.SearchRepository
 	publisher domain.EventPublisher
+	bundle    *i18n.Bundle
+	catalog   domain.ProductCatalog
 }
 
 // NewIndexUseCase creates a new IndexUseCase.
-func NewIndexUseCase(repo domain.SearchRepository, publisher domain.EventPublisher) *IndexUseCase {
+func NewIndexUseCase(repo domain.SearchRepository, publisher domain.EventPublisher, bundle *i18n.Bundle, catalog domain.ProductCatalog) *IndexUseCase {
 	return &IndexUseCase{
 		repo:      repo,
 		publisher: publisher,
+		bundle:    bundle,
+		catalog:   catalog,
 	}
 }
 
-// IndexProduct adds or updates a product in the search index.
+// IndexProduct adds or updates a product in the search index. Each locale of
+// a product is a separate document; documents without a locale are indexed
+// in the default language.
 func (uc *IndexUseCase) IndexProduct(ctx context.Context, idx *domain.SearchIndex) error {
+	locale, err := uc.normalizeLocale(idx.Locale)
+	if err != nil {
+		return err
+	}
+	idx.Locale = locale
+
 	if idx.ID == "" {
 		idx.ID = uuid.New().String()
 	}
@@ -42,6 +61,7 @@ func (uc *IndexUseCase) IndexProduct(ctx context.Context, idx *domain.SearchInde
 	event := map[string]interface{}{
 		"event":      "product.indexed",
 		"product_id": idx.ProductID,
+		"locale":     idx.Locale,
 		"name":       idx.Name,
 		"timestamp":  now,
 	}
@@ -52,9 +72,17 @@ func (uc *IndexUseCase) IndexProduct(ctx context.Context, idx *domain.SearchInde
 	return nil
 }
 
-// RemoveProduct removes a product from the search index.
-func (uc *IndexUseCase) RemoveProduct(ctx context.Context, productID string) error {
-	if err := uc.repo.Delete(ctx, productID); err != nil {
+// RemoveProduct removes a product from the search index, in one locale or,
+// when locale is empty, in all of them.
+func (uc *IndexUseCase) RemoveProduct(ctx context.Context, productID, locale string) error {
+	if locale != "" {
+		normalized, err := uc.normalizeLocale(locale)
+		if err != nil {
+			return err
+		}
+		locale = normalized
+	}
+	if err := uc.repo.Delete(ctx, productID, locale); err != nil {
 		return err
 	}
 
@@ -62,6 +90,7 @@ func (uc *IndexUseCase) RemoveProduct(ctx context.Context, productID string) err
 	event := map[string]interface{}{
 		"event":      "product.deindexed",
 		"product_id": productID,
+		"locale":     locale,
 		"timestamp":  time.Now(),
 	}
 	if err := uc.publisher.Publish(ctx, "search.product.deindexed", event); err != nil {
@@ -70,3 +99,246 @@ func (uc *IndexUseCase) RemoveProduct(ctx context.Context, productID string) err
 
 	return nil
 }
+
+// SyncProduct brings a product's documents in line with the catalog: an
+// active product is indexed in its base language and each of its
+// translations, and documents in other locales are removed. Products that
+// are gone or not active are removed from the index.
+func (uc *IndexUseCase) SyncProduct(ctx context.Context, productID string) error {
+	product, err := uc.catalog.GetProduct(ctx, productID)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return uc.RemoveProduct(ctx, productID, "")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch product %s: %w", productID, err)
+	}
+	if product.Status != domain.CatalogProductActive {
+		return uc.RemoveProduct(ctx, productID, "")
+	}
+
+	indexed := make(map[string]bool)
+	for _, doc := range uc.documents(product) {
+		if err := uc.IndexProduct(ctx, doc); err != nil {
+			return err
+		}
+		indexed[doc.Locale] = true
+	}
+
+	locales, err := uc.repo.ProductLocales(ctx, productID)
+	if err != nil {
+		return err
+	}
+	for _, locale := range locales {
+		if !indexed[locale] {
+			if err := uc.RemoveProduct(ctx, productID, locale); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SyncVariant syncs the product of a variant whose stock or price changed.
+func (uc *IndexUseCase) SyncVariant(ctx context.Context, variantID string) error {
+	productID, err := uc.catalog.GetVariantProductID(ctx, variantID)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		// The variant's product was deleted along with it
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch variant %s: %w", variantID, err)
+	}
+	return uc.SyncProduct(ctx, productID)
+}
+
+// Reindex rebuilds the product index from the catalog. The new index is
+// built next to the live one, which keeps serving searches and receiving
+// updates, and replaces it only once every active product is indexed. If
+// any product fails to index the rebuild is discarded and the live index
+// kept.
+func (uc *IndexUseCase) Reindex(ctx context.Context) (*domain.ReindexResult, error) {
+	if err := uc.repo.BeginRebuild(ctx); err != nil {
+		return nil, fmt.Errorf("failed to begin rebuild: %w", err)
+	}
+	abort := func(cause error) error {
+		if err := uc.repo.AbortRebuild(context.WithoutCancel(ctx)); err != nil {
+			log.Error().Err(err).Msg("failed to discard search index rebuild")
+		}
+		return cause
+	}
+
+	result := &domain.ReindexResult{}
+	for page := 1; ; page++ {
+		ids, total, err := uc.catalog.ListProductIDs(ctx, domain.CatalogProductActive, page, reindexPageSize)
+		if err != nil {
+			return nil, abort(fmt.Errorf("failed to list products: %w", err))
+		}
+
+		for _, id := range ids {
+			n, err := uc.rebuildProduct(ctx, id)
+			if err != nil {
+				log.Error().Err(err).Str("product_id", id).Msg("failed to reindex product")
+				result.Failed++
+				continue
+			}
+			if n > 0 {
+				result.Products++
+				result.Documents += n
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, abort(ctx.Err())
+		}
+
+		log.Info().Int("page", page).Int("products", result.Products).Int64("total", total).Msg("reindexing products")
+		if len(ids) < reindexPageSize || int64(page*reindexPageSize) >= total {
+			break
+		}
+	}
+
+	if result.Failed > 0 {
+		return result, abort(fmt.Errorf("%d products failed to index, keeping the live index", result.Failed))
+	}
+	if err := uc.repo.SwapRebuild(ctx); err != nil {
+		return result, abort(fmt.Errorf("failed to swap in rebuilt index: %w", err))
+	}
+	return result, nil
+}
+
+// rebuildProduct adds a product's documents to the index being rebuilt and
+// returns how many it added. Products that went away or are no longer
+// active since they were listed are skipped.
+func (uc *IndexUseCase) rebuildProduct(ctx context.Context, productID string) (int, error) {
+	product, err := uc.catalog.GetProduct(ctx, productID)
+	if errors.Is(err, domain.ErrProductNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if product.Status != domain.CatalogProductActive {
+		return 0, nil
+	}
+
+	docs := uc.documents(product)
+	for _, doc := range docs {
+		if err := uc.repo.IndexRebuild(ctx, doc); err != nil {
+			return 0, err
+		}
+	}
+	return len(docs), nil
+}
+
+// documents builds a catalog product's search documents: one in the default
+// language with its base content and one per supported translation, whose
+// empty fields fall back to the base content. Products with variants are
+// priced at their cheapest active variant and in stock when any active
+// variant is; products without tracked stock count as in stock.
+func (uc *IndexUseCase) documents(product *domain.CatalogProduct) []*domain.SearchIndex {
+	now := time.Now()
+	base := domain.SearchIndex{
+		ProductID:   product.ID,
+		Locale:      i18n.DefaultLanguage,
+		Name:        product.Name,
+		Slug:        product.Slug,
+		Description: product.Description,
+		PriceCents:  product.BasePriceCents,
+		Currency:    product.Currency,
+		CategoryID:  product.CategoryID,
+		SellerID:    product.SellerID,
+		Rating:      product.Rating,
+		ReviewCount: product.ReviewCount,
+		InStock:     true,
+		Tags:        product.Tags,
+		Attributes:  product.Attributes,
+		CreatedAt:   product.CreatedAt,
+		UpdatedAt:   now,
+	}
+	if len(product.ImageURLs) > 0 {
+		base.ImageURL = product.ImageURLs[0]
+	}
+	if base.CreatedAt.IsZero() {
+		base.CreatedAt = now
+	}
+
+	priced := false
+	for _, v := range product.Variants {
+		if !v.IsActive {
+			continue
+		}
+		price := v.PriceCents
+		if price == 0 {
+			price = product.BasePriceCents
+		}
+		if !priced || price < base.PriceCents {
+			base.PriceCents = price
+		}
+		if !priced {
+			base.InStock = false
+			priced = true
+		}
+		if v.Stock > 0 {
+			base.InStock = true
+		}
+	}
+
+	untranslated := base
+	docs := []*domain.SearchIndex{&base}
+	byLocale := map[string]*domain.SearchIndex{base.Locale: &base}
+	for _, t := range product.Translations {
+		if !i18n.IsSupported(t.Locale) {
+			continue
+		}
+		locale := uc.bundle.MatchLanguage(t.Locale)
+		doc, ok := byLocale[locale]
+		if !ok {
+			translated := untranslated
+			translated.Locale = locale
+			doc = &translated
+			byLocale[locale] = doc
+			docs = append(docs, doc)
+		}
+		if t.Name != "" {
+			doc.Name = t.Name
+		}
+		if t.Slug != "" {
+			doc.Slug = t.Slug
+		}
+		if t.Description != "" {
+			doc.Description = t.Description
+		}
+	}
+	return docs
+}
+
+// UpdateRating refreshes the rating of an indexed product.
+func (uc *IndexUseCase) UpdateRating(ctx context.Context, productID string, rating float64, reviewCount int) error {
+	return uc.repo.UpdateRating(ctx, productID, rating, reviewCount)
+}
+
+// IndexQuestion indexes an answered question so that searches match its
+// question and answers.
+func (uc *IndexUseCase) IndexQuestion(ctx context.Context, doc *domain.QuestionDocument) error {
+	if doc.QuestionID == "" || doc.ProductID == "" {
+		return fmt.Errorf("question_id and product_id are required")
+	}
+	doc.UpdatedAt = time.Now()
+	return uc.repo.IndexQuestion(ctx, doc)
+}
+
+// RemoveQuestion removes a question from the search index.
+func (uc *IndexUseCase) RemoveQuestion(ctx context.Context, questionID string) error {
+	return uc.repo.DeleteQuestion(ctx, questionID)
+}
+
+// normalizeLocale reduces a locale to the language code documents are
+// indexed under (e.g. "fr-CA" to "fr"), defaulting to the default language.
+func (uc *IndexUseCase) normalizeLocale(locale string) (string, error) {
+	if locale == "" {
+		return i18n.DefaultLanguage, nil
+	}
+	if !i18n.IsSupported(locale) {
+		return "", fmt.Errorf("unsupported locale %q", locale)
+	}
+	return uc.bundle.MatchLanguage(locale), nil
+}
@@ -2,22 +2,203 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/rs/zerolog/log"
+	"github.com/southern-martin/ecommerce/pkg/i18n"
 	"github.com/southern-martin/ecommerce/services/search/internal/domain"
 )
 
+// attributeCacheTTL is how long the filterable attributes and categories
+// fetched from the product catalog are used before they are fetched again.
+const attributeCacheTTL = 5 * time.Minute
+
+// Autocomplete suggests at most maxQuerySuggestions popular queries,
+// maxCategorySuggestions categories and maxBrandSuggestions brands, together
+// up to half of the suggestions, followed by products.
+const (
+	maxQuerySuggestions    = 4
+	maxCategorySuggestions = 2
+	maxBrandSuggestions    = 2
+)
+
+// maxLoggedQueryLength is the length, in characters, of the longest query
+// logged for suggestions.
+const maxLoggedQueryLength = 100
+
+// maxCorrectedWords is the length, in words, of the longest query "did you
+// mean" corrects word by word.
+const maxCorrectedWords = 6
+
 // SearchUseCase handles search query operations.
 type SearchUseCase struct {
-	repo domain.SearchRepository
+	repo       domain.SearchRepository
+	bundle     *i18n.Bundle
+	attributes domain.AttributeProvider
+	categories domain.CategoryProvider
+
+	mu                 sync.Mutex
+	filterable         []domain.FilterableAttribute
+	filterableLoadedAt time.Time
+	categoriesByLocale map[string]cachedCategories
 }
 
-// NewSearchUseCase creates a new SearchUseCase.
-func NewSearchUseCase(repo domain.SearchRepository) *SearchUseCase {
-	return &SearchUseCase{repo: repo}
+// cachedCategories are the categories named in a locale and when they
+// were fetched.
+type cachedCategories struct {
+	categories []domain.Category
+	loadedAt   time.Time
 }
 
-// Search performs a search with the given filter, normalizing pagination params.
+// NewSearchUseCase creates a new SearchUseCase. The bundle resolves the
+// locale fallback chain results are localized along; attributes lists the
+// attributes results can be filtered and faceted by, and categories the
+// categories autocomplete suggests.
+func NewSearchUseCase(repo domain.SearchRepository, bundle *i18n.Bundle, attributes domain.AttributeProvider, categories domain.CategoryProvider) *SearchUseCase {
+	return &SearchUseCase{
+		repo:               repo,
+		bundle:             bundle,
+		attributes:         attributes,
+		categories:         categories,
+		categoriesByLocale: make(map[string]cachedCategories),
+	}
+}
+
+// Search performs a search with the given filter, normalizing pagination
+// params. The first page of each text search is logged to learn popular
+// queries from.
 func (uc *SearchUseCase) Search(ctx context.Context, filter domain.SearchFilter) ([]domain.SearchResult, int64, error) {
+	filter = uc.normalize(ctx, filter)
+	results, total, err := uc.repo.Search(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if query := strings.ToLower(normalizeQuery(filter.Query)); query != "" && filter.Page == 1 &&
+		utf8.RuneCountInString(query) <= maxLoggedQueryLength {
+		// Logging is best effort; the repository logs failures
+		_ = uc.repo.RecordQuery(ctx, query, filter.Locales[0], hashSearcher(filter.Searcher), total)
+	}
+	return results, total, nil
+}
+
+// DidYouMean suggests a correction of a search query that found nothing:
+// the most similar popular query, or the query with each misspelled word
+// replaced by the closest word of the indexed product names. A correction
+// is only suggested if it finds results. It returns "" when there is none.
+func (uc *SearchUseCase) DidYouMean(ctx context.Context, filter domain.SearchFilter) string {
+	filter = uc.normalize(ctx, filter)
+	query := normalizeQuery(filter.Query)
+	if query == "" {
+		return ""
+	}
+
+	var candidates []string
+	if similar, err := uc.repo.SimilarQuery(ctx, strings.ToLower(query), filter.Locales[0]); err == nil && similar != "" {
+		candidates = append(candidates, similar)
+	}
+	if corrected := uc.correctWords(ctx, query, filter.Locales); !strings.EqualFold(corrected, query) {
+		candidates = append(candidates, corrected)
+	}
+
+	for _, candidate := range candidates {
+		check := filter
+		check.Query = candidate
+		check.Page = 1
+		check.PageSize = 1
+		_, total, err := uc.repo.Search(ctx, check)
+		if err != nil {
+			log.Warn().Err(err).Str("query", candidate).Msg("failed to check query correction")
+			return ""
+		}
+		if total > 0 {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// correctWords replaces each plain word of query with the closest word of
+// the indexed product names in locales. Words of at most two letters,
+// negations, phrases and operators are kept as typed.
+func (uc *SearchUseCase) correctWords(ctx context.Context, query string, locales []string) string {
+	words := strings.Fields(query)
+	if len(words) > maxCorrectedWords {
+		return query
+	}
+	for i, word := range words {
+		if utf8.RuneCountInString(word) < 3 || word == "OR" || strings.IndexFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) >= 0 {
+			continue
+		}
+		closest, err := uc.repo.ClosestWord(ctx, word, locales)
+		if err != nil {
+			return query
+		}
+		if closest != "" && !strings.EqualFold(closest, word) {
+			words[i] = closest
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Facets counts the results of a search by each facet dimension: price
+// range, rating, filterable attributes, tags and seller.
+func (uc *SearchUseCase) Facets(ctx context.Context, filter domain.SearchFilter) ([]domain.Facet, error) {
+	filter = uc.normalize(ctx, filter)
+	return uc.repo.Facets(ctx, filter, uc.filterableAttributes(ctx))
+}
+
+// Suggest returns search autocomplete suggestions in locale: popular
+// queries, categories and brands, then products. Matches tolerate typos.
+// Only the product suggestions are required; the others are left out when
+// they cannot be found.
+func (uc *SearchUseCase) Suggest(ctx context.Context, query, locale string, limit int) ([]domain.SearchSuggestion, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+	query = strings.ToLower(normalizeQuery(query))
+	suggestions := make([]domain.SearchSuggestion, 0, limit)
+	if query == "" {
+		return suggestions, nil
+	}
+	locales := uc.bundle.FallbackChain(locale)
+
+	if queries, err := uc.repo.SuggestQueries(ctx, query, locales[0], maxQuerySuggestions); err == nil {
+		suggestions = append(suggestions, queries...)
+	}
+	if categories := uc.categoriesIn(ctx, locales[0]); len(categories) > 0 {
+		if found, err := uc.repo.SuggestCategories(ctx, query, categories, locales, maxCategorySuggestions); err == nil {
+			suggestions = append(suggestions, found...)
+		}
+	}
+	if brands, err := uc.repo.SuggestBrands(ctx, query, locales, maxBrandSuggestions); err == nil {
+		suggestions = append(suggestions, brands...)
+	}
+	if len(suggestions) > limit/2 {
+		suggestions = suggestions[:limit/2]
+	}
+
+	products, err := uc.repo.Suggest(ctx, query, locales, limit-len(suggestions))
+	if err != nil {
+		return nil, err
+	}
+	return append(suggestions, products...), nil
+}
+
+// normalize normalizes pagination params, resolves the locale fallback
+// chain and keeps the filters of filterable attributes only.
+func (uc *SearchUseCase) normalize(ctx context.Context, filter domain.SearchFilter) domain.SearchFilter {
 	// Normalize pagination
 	if filter.Page < 1 {
 		filter.Page = 1
@@ -28,18 +209,81 @@ func (uc *SearchUseCase) Search(ctx context.Context, filter domain.SearchFilter)
 	if filter.PageSize > 100 {
 		filter.PageSize = 100
 	}
+	filter.Locales = uc.bundle.FallbackChain(filter.Locale)
 
-	return uc.repo.Search(ctx, filter)
+	if len(filter.Attributes) > 0 {
+		bySlug := make(map[string]domain.FilterableAttribute)
+		for _, attr := range uc.filterableAttributes(ctx) {
+			bySlug[attr.Slug] = attr
+		}
+		attrs := make([]domain.AttributeFilter, 0, len(filter.Attributes))
+		for _, attr := range filter.Attributes {
+			def, ok := bySlug[attr.Slug]
+			if !ok || len(attr.Values) == 0 {
+				continue
+			}
+			attr.Name = def.Name
+			attrs = append(attrs, attr)
+		}
+		filter.Attributes = attrs
+	}
+	return filter
 }
 
-// Suggest returns search autocomplete suggestions.
-func (uc *SearchUseCase) Suggest(ctx context.Context, query string, limit int) ([]domain.SearchSuggestion, error) {
-	if limit <= 0 {
-		limit = 10
+// filterableAttributes returns the filterable attributes, fetching them
+// from the product catalog when the cached ones are stale. If they cannot
+// be fetched, the stale ones are used until the next refresh.
+func (uc *SearchUseCase) filterableAttributes(ctx context.Context) []domain.FilterableAttribute {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if time.Since(uc.filterableLoadedAt) < attributeCacheTTL {
+		return uc.filterable
 	}
-	if limit > 50 {
-		limit = 50
+	attrs, err := uc.attributes.ListFilterableAttributes(ctx)
+	uc.filterableLoadedAt = time.Now()
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to load filterable attributes")
+		return uc.filterable
+	}
+	uc.filterable = attrs
+	return attrs
+}
+
+// categoriesIn returns the categories named in locale, fetching them from
+// the product catalog when the cached ones are stale. If they cannot be
+// fetched, the stale ones are used until the next refresh.
+func (uc *SearchUseCase) categoriesIn(ctx context.Context, locale string) []domain.Category {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	cached := uc.categoriesByLocale[locale]
+	if time.Since(cached.loadedAt) < attributeCacheTTL {
+		return cached.categories
 	}
+	categories, err := uc.categories.ListCategories(ctx, locale)
+	cached.loadedAt = time.Now()
+	if err != nil {
+		log.Warn().Err(err).Str("locale", locale).Msg("failed to load categories")
+	} else {
+		cached.categories = categories
+	}
+	uc.categoriesByLocale[locale] = cached
+	return cached.categories
+}
 
-	return uc.repo.Suggest(ctx, query, limit)
+// normalizeQuery collapses the whitespace of a search query.
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// hashSearcher hashes who searched, so that queries can be told apart by
+// how many shoppers searched them without storing who they were. It returns
+// "" for searches without a searcher.
+func hashSearcher(searcher string) string {
+	if searcher == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(searcher))
+	return hex.EncodeToString(sum[:])
 }
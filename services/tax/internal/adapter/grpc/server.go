@@ -21,6 +21,7 @@ type TaxService interface {
 type CalculateTaxRequest struct {
 	Items           []*TaxItemMessage
 	ShippingAddress *TaxAddressMessage
+	Currency        string
 }
 
 // TaxItemMessage represents a tax item in gRPC messages.
@@ -42,9 +43,11 @@ type TaxAddressMessage struct {
 
 // CalculateTaxResponse is the gRPC response for tax calculation.
 type CalculateTaxResponse struct {
+	Currency       string
 	SubtotalCents  int64
 	TaxAmountCents int64
 	Breakdown      []*TaxBreakdownMessage
+	ItemTaxCents   []int64
 }
 
 // TaxBreakdownMessage represents a tax breakdown item in gRPC messages.
@@ -111,6 +114,7 @@ func (s *Server) CalculateTax(ctx context.Context, req *CalculateTaxRequest) (*C
 			City:        req.ShippingAddress.City,
 			PostalCode:  req.ShippingAddress.PostalCode,
 		},
+		Currency: req.Currency,
 	}
 
 	for i, item := range req.Items {
@@ -139,9 +143,11 @@ func (s *Server) CalculateTax(ctx context.Context, req *CalculateTaxRequest) (*C
 	}
 
 	return &CalculateTaxResponse{
+		Currency:       result.Currency,
 		SubtotalCents:  result.SubtotalCents,
 		TaxAmountCents: result.TaxAmountCents,
 		Breakdown:      breakdown,
+		ItemTaxCents:   result.ItemTaxCents,
 	}, nil
 }
 
@@ -159,6 +159,7 @@ func (h *Handler) CalculateTax(c *gin.Context) {
 			City:        req.ShippingAddress.City,
 			PostalCode:  req.ShippingAddress.PostalCode,
 		},
+		Currency: req.Currency,
 	}
 
 	for i, item := range req.Items {
@@ -188,9 +189,11 @@ func (h *Handler) CalculateTax(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
+		"currency":         result.Currency,
 		"subtotal_cents":   result.SubtotalCents,
 		"tax_amount_cents": result.TaxAmountCents,
 		"breakdown":        breakdown,
+		"item_tax_cents":   result.ItemTaxCents,
 	})
 }
 
@@ -218,6 +221,7 @@ type updateRuleRequest struct {
 type calculateTaxRequest struct {
 	Items           []taxItemRequest    `json:"items" binding:"required"`
 	ShippingAddress taxAddressRequest   `json:"shipping_address" binding:"required"`
+	Currency        string              `json:"currency"`
 }
 
 type taxItemRequest struct {
@@ -23,10 +23,12 @@ type TaxRule struct {
 	IsActive  bool
 }
 
-// TaxCalculationRequest holds the input for a tax calculation.
+// TaxCalculationRequest holds the input for a tax calculation. Prices are
+// in the minor units of Currency, USD when it is empty.
 type TaxCalculationRequest struct {
 	Items           []TaxItem
 	ShippingAddress TaxAddress
+	Currency        string
 }
 
 // TaxItem represents a single item in a tax calculation request.
@@ -46,11 +48,15 @@ type TaxAddress struct {
 	PostalCode  string
 }
 
-// TaxCalculation holds the result of a tax calculation.
+// TaxCalculation holds the result of a tax calculation. ItemTaxCents is the
+// tax of each requested item, in the same order, and adds up to
+// TaxAmountCents.
 type TaxCalculation struct {
+	Currency       string
 	SubtotalCents  int64
 	TaxAmountCents int64
 	Breakdown      []TaxBreakdown
+	ItemTaxCents   []int64
 }
 
 // TaxBreakdown shows a single tax component in the calculation result.
@@ -4,6 +4,8 @@ import (
 	"context"
 	"math"
 
+	"github.com/southern-martin/ecommerce/pkg/currency"
+	"github.com/southern-martin/ecommerce/pkg/money"
 	"github.com/southern-martin/ecommerce/services/tax/internal/domain"
 )
 
@@ -36,11 +38,15 @@ func (uc *CalculateTaxUseCase) Execute(ctx context.Context, req *domain.TaxCalcu
 		subtotalCents += item.PriceCents * int64(item.Quantity)
 	}
 
-	// 3. For each item, find matching rules and calculate tax
+	// 3. For each item, find matching rules and add the item to the taxable
+	// base of each tax component. Components are taxed once, on their whole
+	// base, so that rounding does not add up across lines.
+	var components []taxComponent
+	bases := make(map[taxComponent]*taxBase)
 	breakdownMap := make(map[string]*domain.TaxBreakdown)
-	var totalTaxCents int64
+	var names []string
 
-	for _, item := range req.Items {
+	for i, item := range req.Items {
 		// Find matching rules for this item's category
 		rules, err := uc.ruleRepo.GetByZoneAndCategory(ctx, zone.ID, item.Category)
 		if err != nil {
@@ -48,43 +54,68 @@ func (uc *CalculateTaxUseCase) Execute(ctx context.Context, req *domain.TaxCalcu
 		}
 
 		for _, rule := range rules {
-			itemTotal := item.PriceCents * int64(item.Quantity)
-			var taxCents int64
-
-			if rule.Inclusive {
-				// Tax is included in price: tax = price - (price / (1 + rate))
-				priceExclTax := float64(itemTotal) / (1.0 + rule.Rate)
-				taxCents = int64(math.Round(float64(itemTotal) - priceExclTax))
-			} else {
-				// Tax is added on top: tax = price * rate
-				taxCents = int64(math.Round(float64(itemTotal) * rule.Rate))
+			component := taxComponent{name: rule.TaxName, rate: rule.Rate, inclusive: rule.Inclusive}
+			base, ok := bases[component]
+			if !ok {
+				base = &taxBase{}
+				bases[component] = base
+				components = append(components, component)
 			}
+			itemTotal := item.PriceCents * int64(item.Quantity)
+			base.items = append(base.items, i)
+			base.totals = append(base.totals, itemTotal)
+			base.totalCents += itemTotal
 
-			key := rule.TaxName
-			if existing, ok := breakdownMap[key]; ok {
-				existing.AmountCents += taxCents
-			} else {
-				breakdownMap[key] = &domain.TaxBreakdown{
+			if _, ok := breakdownMap[rule.TaxName]; !ok {
+				breakdownMap[rule.TaxName] = &domain.TaxBreakdown{
 					TaxName:      rule.TaxName,
 					Rate:         rule.Rate,
-					AmountCents:  taxCents,
 					Jurisdiction: zone.Name,
 				}
+				names = append(names, rule.TaxName)
 			}
-			totalTaxCents += taxCents
 		}
 	}
 
-	// 4. Build breakdown slice
-	breakdown := make([]domain.TaxBreakdown, 0, len(breakdownMap))
-	for _, b := range breakdownMap {
+	// 4. Tax each component with banker's rounding, so that ties do not
+	// bias the total upwards, and allocate its tax to the items in
+	// proportion to their totals, so that the items' taxes add up to it
+	code := taxCurrency(req)
+	itemTax := make([]int64, len(req.Items))
+	for _, component := range components {
+		base := bases[component]
+		if base.totalCents <= 0 {
+			continue
+		}
+		tax, err := component.tax(money.NewMoney(base.totalCents, code))
+		if err != nil {
+			return nil, err
+		}
+		parts, err := tax.Allocate(base.totals...)
+		if err != nil {
+			return nil, err
+		}
+		for j, part := range parts {
+			itemTax[base.items[j]] += part.AmountCents
+		}
+		breakdownMap[component.name].AmountCents += tax.AmountCents
+	}
+
+	// 5. Build breakdown slice, in the order the components first applied
+	var totalTaxCents int64
+	breakdown := make([]domain.TaxBreakdown, 0, len(names))
+	for _, name := range names {
+		b := breakdownMap[name]
+		totalTaxCents += b.AmountCents
 		breakdown = append(breakdown, *b)
 	}
 
 	return &domain.TaxCalculation{
+		Currency:       code,
 		SubtotalCents:  subtotalCents,
 		TaxAmountCents: totalTaxCents,
 		Breakdown:      breakdown,
+		ItemTaxCents:   itemTax,
 	}, nil
 }
 
@@ -94,8 +125,47 @@ func (uc *CalculateTaxUseCase) zeroTaxResult(req *domain.TaxCalculationRequest)
 		subtotalCents += item.PriceCents * int64(item.Quantity)
 	}
 	return &domain.TaxCalculation{
+		Currency:       taxCurrency(req),
 		SubtotalCents:  subtotalCents,
 		TaxAmountCents: 0,
 		Breakdown:      []domain.TaxBreakdown{},
+		ItemTaxCents:   make([]int64, len(req.Items)),
+	}
+}
+
+// rateScale is the precision tax rates are applied at: parts per million.
+const rateScale = 1_000_000
+
+// taxComponent is a tax applied at one rate, either included in prices or
+// added on top of them.
+type taxComponent struct {
+	name      string
+	rate      float64
+	inclusive bool
+}
+
+// taxBase is the taxable base of a tax component: the items it applies to,
+// their totals and the sum of them.
+type taxBase struct {
+	items      []int
+	totals     []int64
+	totalCents int64
+}
+
+// tax returns the tax of the component on base. Included tax is the part of
+// base above base / (1 + rate); added tax is base * rate.
+func (c taxComponent) tax(base money.Money) (money.Money, error) {
+	rate := int64(math.Round(c.rate * rateScale))
+	if c.inclusive {
+		return base.MultiplyRatio(rate, rateScale+rate, money.RoundHalfEven)
+	}
+	return base.MultiplyRatio(rate, rateScale, money.RoundHalfEven)
+}
+
+// taxCurrency returns the currency of a tax calculation request.
+func taxCurrency(req *domain.TaxCalculationRequest) string {
+	if req.Currency == "" {
+		return "USD"
 	}
+	return currency.Normalize(req.Currency)
 }
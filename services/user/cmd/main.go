@@ -69,6 +69,10 @@ func main() {
 	}
 	l.Info().Msg("NATS subscriber started for user.registered events")
 
+	if err := usernats.StartSellerRatingSubscriber(sub, sellerUC, l); err != nil {
+		log.Fatalf("failed to start seller rating subscriber: %v", err)
+	}
+
 	// Setup HTTP router
 	handler := userhttp.NewHandler(profileUC, addressUC, sellerUC, followUC)
 	router := userhttp.NewRouter(handler)
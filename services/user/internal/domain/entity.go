@@ -52,6 +52,7 @@ type SellerProfile struct {
 	Description string    `json:"description"`
 	LogoURL     string    `json:"logo_url"`
 	Rating      float64   `gorm:"default:0" json:"rating"`
+	RatingCount int       `gorm:"default:0" json:"rating_count"`
 	TotalSales  int       `gorm:"default:0" json:"total_sales"`
 	Status      string    `gorm:"default:pending" json:"status"`
 	CreatedAt   time.Time `json:"created_at"`
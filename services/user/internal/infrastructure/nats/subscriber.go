@@ -1,6 +1,7 @@
 package nats
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/rs/zerolog"
@@ -36,3 +37,27 @@ func StartSubscriber(sub *events.Subscriber, profileUC *usecase.ProfileUseCase,
 		}
 	})
 }
+
+// ProductRatingUpdatedEvent matches the product service's rating event payload.
+type ProductRatingUpdatedEvent struct {
+	ProductID         string  `json:"product_id"`
+	SellerID          string  `json:"seller_id"`
+	SellerRatingAvg   float64 `json:"seller_rating_avg"`
+	SellerRatingCount int     `json:"seller_rating_count"`
+}
+
+// StartSellerRatingSubscriber subscribes to product.rating.updated and keeps
+// seller ratings in line with their products.
+func StartSellerRatingSubscriber(sub *events.Subscriber, sellerUC *usecase.SellerUseCase, logger zerolog.Logger) error {
+	return sub.Subscribe(events.SubjectProductRatingUpdate, "user-service-seller-rating", func(data []byte) {
+		var evt ProductRatingUpdatedEvent
+		if err := json.Unmarshal(data, &evt); err != nil {
+			logger.Error().Err(err).Msg("failed to unmarshal product.rating.updated event")
+			return
+		}
+
+		if err := sellerUC.UpdateRating(context.Background(), evt.SellerID, evt.SellerRatingAvg, evt.SellerRatingCount); err != nil {
+			logger.Error().Err(err).Str("seller_id", evt.SellerID).Msg("failed to update seller rating")
+		}
+	})
+}
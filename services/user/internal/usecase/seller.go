@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 
 	"github.com/rs/zerolog"
 
@@ -127,3 +128,22 @@ func (uc *SellerUseCase) ApproveSeller(ctx context.Context, sellerID string) (*d
 
 	return seller, nil
 }
+
+// UpdateRating sets a seller's rating from the rolled up rating of their
+// products. Sellers without a profile are skipped.
+func (uc *SellerUseCase) UpdateRating(ctx context.Context, userID string, rating float64, count int) error {
+	seller, err := uc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		var notFound *apperrors.NotFoundError
+		if errors.As(err, &notFound) {
+			uc.logger.Warn().Str("user_id", userID).Msg("no seller profile for rating update")
+			return nil
+		}
+		return err
+	}
+
+	seller.Rating = rating
+	seller.RatingCount = count
+
+	return uc.repo.Update(ctx, seller)
+}
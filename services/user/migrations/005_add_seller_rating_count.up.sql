@@ -0,0 +1 @@
+ALTER TABLE seller_profiles ADD COLUMN rating_count INTEGER NOT NULL DEFAULT 0;